@@ -6,11 +6,13 @@ package countconnector // import "github.com/open-telemetry/opentelemetry-collec
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/confmap"
 	"go.uber.org/zap"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/countconnector/internal/hyperloglog"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/filter/filterottl"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
 )
@@ -34,6 +36,13 @@ const (
 	defaultMetricDescProfiles = "The number of profiles observed."
 )
 
+// Defaults of the distinct counts.
+const (
+	defaultDistinctWindow      = time.Minute
+	defaultDistinctPrecision   = 12
+	defaultDistinctMaxSketches = 1000
+)
+
 // Config for the connector
 type Config struct {
 	Spans      map[string]MetricInfo `mapstructure:"spans"`
@@ -51,6 +60,30 @@ type MetricInfo struct {
 	Description string            `mapstructure:"description"`
 	Conditions  []string          `mapstructure:"conditions"`
 	Attributes  []AttributeConfig `mapstructure:"attributes"`
+	// Distinct makes the metric an approximate count of the distinct values of an attribute over a time window,
+	// rather than a count of the matching data.
+	Distinct *DistinctConfig `mapstructure:"distinct"`
+	// prevent unkeyed literal initialization
+	_ struct{}
+}
+
+// DistinctConfig for a distinct count
+type DistinctConfig struct {
+	// Attribute is the key of the attribute whose distinct values are counted. The data without the attribute
+	// is not counted.
+	Attribute string `mapstructure:"attribute"`
+	// Window is the time period over which the distinct values are counted. The counts are emitted as gauges at
+	// the end of each window. Defaults to 1m.
+	Window time.Duration `mapstructure:"window"`
+	// Precision is the precision of the HyperLogLog sketches the distinct values are counted with, between 4
+	// and 16. A sketch uses 2^precision bytes, and its relative standard error is about 1.04/sqrt(2^precision).
+	// Defaults to 12, that is 4KiB per sketch and a 1.6% error.
+	Precision uint8 `mapstructure:"precision"`
+	// MaxSketches is the maximum number of sketches of the metric in a window, one per resource and set of
+	// attributes. The values of the data beyond it are counted in a single overflow sketch, emitted with the
+	// otel.metric.overflow attribute. The sketches of a metric use up to MaxSketches*2^precision bytes, plus
+	// their attributes. Defaults to 1000, that is 4MiB with the default precision.
+	MaxSketches int `mapstructure:"max_sketches"`
 	// prevent unkeyed literal initialization
 	_ struct{}
 }
@@ -73,6 +106,9 @@ func (c *Config) Validate() error {
 		if err := info.validateAttributes(); err != nil {
 			return fmt.Errorf("spans attributes: metric %q: %w", name, err)
 		}
+		if err := info.validateDistinct(); err != nil {
+			return fmt.Errorf("spans distinct: metric %q: %w", name, err)
+		}
 	}
 	for name, info := range c.SpanEvents {
 		if name == "" {
@@ -84,6 +120,9 @@ func (c *Config) Validate() error {
 		if err := info.validateAttributes(); err != nil {
 			return fmt.Errorf("spanevents attributes: metric %q: %w", name, err)
 		}
+		if err := info.validateDistinct(); err != nil {
+			return fmt.Errorf("spanevents distinct: metric %q: %w", name, err)
+		}
 	}
 	for name, info := range c.Metrics {
 		if name == "" {
@@ -95,6 +134,9 @@ func (c *Config) Validate() error {
 		if len(info.Attributes) > 0 {
 			return fmt.Errorf("metrics attributes not supported: metric %q", name)
 		}
+		if info.Distinct != nil {
+			return fmt.Errorf("metrics distinct not supported: metric %q", name)
+		}
 	}
 
 	for name, info := range c.DataPoints {
@@ -107,6 +149,9 @@ func (c *Config) Validate() error {
 		if err := info.validateAttributes(); err != nil {
 			return fmt.Errorf("spans attributes: metric %q: %w", name, err)
 		}
+		if err := info.validateDistinct(); err != nil {
+			return fmt.Errorf("datapoints distinct: metric %q: %w", name, err)
+		}
 	}
 	for name, info := range c.Logs {
 		if name == "" {
@@ -118,6 +163,9 @@ func (c *Config) Validate() error {
 		if err := info.validateAttributes(); err != nil {
 			return fmt.Errorf("logs attributes: metric %q: %w", name, err)
 		}
+		if err := info.validateDistinct(); err != nil {
+			return fmt.Errorf("logs distinct: metric %q: %w", name, err)
+		}
 	}
 	for name, info := range c.Profiles {
 		if name == "" {
@@ -129,6 +177,9 @@ func (c *Config) Validate() error {
 		if err := info.validateAttributes(); err != nil {
 			return fmt.Errorf("profiles attributes: metric %q: %w", name, err)
 		}
+		if err := info.validateDistinct(); err != nil {
+			return fmt.Errorf("profiles distinct: metric %q: %w", name, err)
+		}
 	}
 	return nil
 }
@@ -142,6 +193,25 @@ func (i *MetricInfo) validateAttributes() error {
 	return nil
 }
 
+func (i *MetricInfo) validateDistinct() error {
+	if i.Distinct == nil {
+		return nil
+	}
+	if i.Distinct.Attribute == "" {
+		return errors.New("attribute key missing")
+	}
+	if i.Distinct.Window < 0 {
+		return fmt.Errorf("invalid window: %v, the window should be positive", i.Distinct.Window)
+	}
+	if i.Distinct.Precision != 0 && (i.Distinct.Precision < hyperloglog.MinPrecision || i.Distinct.Precision > hyperloglog.MaxPrecision) {
+		return fmt.Errorf("invalid precision: %d, the precision should be between %d and %d", i.Distinct.Precision, hyperloglog.MinPrecision, hyperloglog.MaxPrecision)
+	}
+	if i.Distinct.MaxSketches < 0 {
+		return fmt.Errorf("invalid max_sketches: %d, the number of sketches should be positive", i.Distinct.MaxSketches)
+	}
+	return nil
+}
+
 var _ confmap.Unmarshaler = (*Config)(nil)
 
 // Unmarshal with custom logic to set default values.
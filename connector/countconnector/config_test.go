@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -432,6 +433,39 @@ func TestLoadConfig(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "distinct",
+			expect: &Config{
+				Spans: map[string]MetricInfo{
+					"route.client.distinct": {
+						Description: "The number of distinct clients per route.",
+						Attributes: []AttributeConfig{
+							{
+								Key: "http.route",
+							},
+						},
+						Distinct: &DistinctConfig{
+							Attribute:   "client.address",
+							Window:      5 * time.Minute,
+							Precision:   14,
+							MaxSketches: 500,
+						},
+					},
+				},
+				SpanEvents: defaultSpanEventsConfig(),
+				Metrics:    defaultMetricsConfig(),
+				DataPoints: defaultDataPointsConfig(),
+				Logs: map[string]MetricInfo{
+					"user.distinct": {
+						Description: "The number of distinct users.",
+						Distinct: &DistinctConfig{
+							Attribute: "user.id",
+						},
+					},
+				},
+				Profiles: defaultProfilesConfig(),
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -595,6 +629,66 @@ func TestConfigErrors(t *testing.T) {
 			},
 			expect: fmt.Sprintf("profiles condition: metric %q: unable to parse OTTL condition", defaultMetricNameProfiles),
 		},
+		{
+			name: "missing_distinct_attribute_span",
+			input: &Config{
+				Spans: map[string]MetricInfo{
+					defaultMetricNameSpans: {
+						Description: defaultMetricDescSpans,
+						Distinct:    &DistinctConfig{},
+					},
+				},
+			},
+			expect: fmt.Sprintf("spans distinct: metric %q: attribute key missing", defaultMetricNameSpans),
+		},
+		{
+			name: "distinct_metric",
+			input: &Config{
+				Metrics: map[string]MetricInfo{
+					defaultMetricNameMetrics: {
+						Description: defaultMetricDescMetrics,
+						Distinct:    &DistinctConfig{Attribute: "user.id"},
+					},
+				},
+			},
+			expect: fmt.Sprintf("metrics distinct not supported: metric %q", defaultMetricNameMetrics),
+		},
+		{
+			name: "invalid_distinct_window_log",
+			input: &Config{
+				Logs: map[string]MetricInfo{
+					defaultMetricNameLogs: {
+						Description: defaultMetricDescLogs,
+						Distinct:    &DistinctConfig{Attribute: "user.id", Window: -time.Second},
+					},
+				},
+			},
+			expect: fmt.Sprintf("logs distinct: metric %q: invalid window: -1s, the window should be positive", defaultMetricNameLogs),
+		},
+		{
+			name: "invalid_distinct_precision_datapoint",
+			input: &Config{
+				DataPoints: map[string]MetricInfo{
+					defaultMetricNameDataPoints: {
+						Description: defaultMetricDescDataPoints,
+						Distinct:    &DistinctConfig{Attribute: "user.id", Precision: 17},
+					},
+				},
+			},
+			expect: fmt.Sprintf("datapoints distinct: metric %q: invalid precision: 17, the precision should be between 4 and 16", defaultMetricNameDataPoints),
+		},
+		{
+			name: "invalid_distinct_max_sketches_span",
+			input: &Config{
+				Spans: map[string]MetricInfo{
+					defaultMetricNameSpans: {
+						Description: defaultMetricDescSpans,
+						Distinct:    &DistinctConfig{Attribute: "user.id", MaxSketches: -1},
+					},
+				},
+			},
+			expect: fmt.Sprintf("spans distinct: metric %q: invalid max_sketches: -1, the number of sketches should be positive", defaultMetricNameSpans),
+		},
 	}
 
 	for _, tc := range testCases {
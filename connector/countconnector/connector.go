@@ -29,8 +29,6 @@ import (
 // profiles and emit the counts onto a metrics pipeline.
 type count struct {
 	metricsConsumer consumer.Metrics
-	component.StartFunc
-	component.ShutdownFunc
 
 	spansMetricDefs      map[string]metricDef[ottlspan.TransformContext]
 	spanEventsMetricDefs map[string]metricDef[ottlspanevent.TransformContext]
@@ -38,6 +36,23 @@ type count struct {
 	dataPointsMetricDefs map[string]metricDef[ottldatapoint.TransformContext]
 	logsMetricDefs       map[string]metricDef[ottllog.TransformContext]
 	profilesMetricDefs   map[string]metricDef[ottlprofile.TransformContext]
+
+	// distinctMetrics are the distinct counts, emitted at the end of their windows.
+	distinctMetrics []*distinctMetric
+}
+
+func (c *count) Start(context.Context, component.Host) error {
+	for _, dm := range c.distinctMetrics {
+		dm.startWindows()
+	}
+	return nil
+}
+
+func (c *count) Shutdown(ctx context.Context) error {
+	for _, dm := range c.distinctMetrics {
+		dm.stopWindows(ctx)
+	}
+	return nil
 }
 
 func (c *count) Capabilities() consumer.Capabilities {
@@ -69,6 +84,9 @@ func (c *count) ConsumeTraces(ctx context.Context, td ptrace.Traces) error {
 			}
 		}
 
+		spansCounter.recordDistinct(resourceSpan.Resource())
+		spanEventsCounter.recordDistinct(resourceSpan.Resource())
+
 		if len(spansCounter.counts)+len(spanEventsCounter.counts) == 0 {
 			continue // don't add an empty resource
 		}
@@ -144,6 +162,8 @@ func (c *count) ConsumeMetrics(ctx context.Context, md pmetric.Metrics) error {
 			}
 		}
 
+		dataPointsCounter.recordDistinct(resourceMetric.Resource())
+
 		if len(metricsCounter.counts)+len(dataPointsCounter.counts) == 0 {
 			continue // don't add an empty resource
 		}
@@ -183,6 +203,8 @@ func (c *count) ConsumeLogs(ctx context.Context, ld plog.Logs) error {
 			}
 		}
 
+		counter.recordDistinct(resourceLog.Resource())
+
 		if len(counter.counts) == 0 {
 			continue // don't add an empty resource
 		}
@@ -222,6 +244,8 @@ func (c *count) ConsumeProfiles(ctx context.Context, ld pprofile.Profiles) error
 			}
 		}
 
+		counter.recordDistinct(resourceProfile.Resource())
+
 		if len(counter.counts) == 0 {
 			continue // don't add an empty resource
 		}
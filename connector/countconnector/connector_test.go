@@ -7,6 +7,7 @@ import (
 	"context"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -14,6 +15,8 @@ import (
 	"go.opentelemetry.io/collector/connector/connectortest"
 	"go.opentelemetry.io/collector/connector/xconnector"
 	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/countconnector/internal/metadata"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/golden"
@@ -695,6 +698,72 @@ func TestLogsToMetrics(t *testing.T) {
 	}
 }
 
+func TestLogsToMetricsDistinct(t *testing.T) {
+	cfg := &Config{
+		Logs: map[string]MetricInfo{
+			"user.distinct": {
+				Description: "The number of distinct users per route.",
+				Conditions:  []string{`severity_number >= SEVERITY_NUMBER_INFO`},
+				Attributes:  []AttributeConfig{{Key: "http.route"}},
+				Distinct:    &DistinctConfig{Attribute: "user.id", Window: time.Hour},
+			},
+		},
+	}
+	require.NoError(t, cfg.Validate())
+	factory := NewFactory()
+	sink := &consumertest.MetricsSink{}
+	conn, err := factory.CreateLogsToMetrics(context.Background(),
+		connectortest.NewNopSettings(metadata.Type), cfg, sink)
+	require.NoError(t, err)
+	require.NoError(t, conn.Start(context.Background(), componenttest.NewNopHost()))
+
+	ld := plog.NewLogs()
+	for _, service := range []string{"a", "b"} {
+		rl := ld.ResourceLogs().AppendEmpty()
+		rl.Resource().Attributes().PutStr("service.name", service)
+		logs := rl.ScopeLogs().AppendEmpty().LogRecords()
+		for i := 0; i < 100; i++ {
+			lr := logs.AppendEmpty()
+			lr.SetSeverityNumber(plog.SeverityNumberInfo)
+			lr.Attributes().PutStr("http.route", "/users")
+			lr.Attributes().PutInt("user.id", int64(i%10))
+		}
+		// Not counted, without the distinct attribute or not matching the condition.
+		logs.AppendEmpty().Attributes().PutStr("http.route", "/users")
+		debug := logs.AppendEmpty()
+		debug.SetSeverityNumber(plog.SeverityNumberDebug)
+		debug.Attributes().PutStr("http.route", "/users")
+		debug.Attributes().PutInt("user.id", 42)
+	}
+	ld.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Attributes().PutStr("http.route", "/orders")
+	require.NoError(t, conn.ConsumeLogs(context.Background(), ld))
+
+	// The distinct counts are emitted at the end of the window, or on shutdown.
+	for _, md := range sink.AllMetrics() {
+		assert.Equal(t, 0, md.DataPointCount())
+	}
+	require.NoError(t, conn.Shutdown(context.Background()))
+
+	allMetrics := sink.AllMetrics()
+	require.NotEmpty(t, allMetrics)
+	md := allMetrics[len(allMetrics)-1]
+	require.Equal(t, 2, md.ResourceMetrics().Len())
+	counts := make(map[string]int64)
+	for i := 0; i < md.ResourceMetrics().Len(); i++ {
+		rm := md.ResourceMetrics().At(i)
+		service, _ := rm.Resource().Attributes().Get("service.name")
+		m := rm.ScopeMetrics().At(0).Metrics().At(0)
+		assert.Equal(t, "user.distinct", m.Name())
+		require.Equal(t, pmetric.MetricTypeGauge, m.Type())
+		for j := 0; j < m.Gauge().DataPoints().Len(); j++ {
+			dp := m.Gauge().DataPoints().At(j)
+			route, _ := dp.Attributes().Get("http.route")
+			counts[service.Str()+route.Str()] = dp.IntValue()
+		}
+	}
+	assert.Equal(t, map[string]int64{"a/orders": 1, "a/users": 10, "b/users": 10}, counts)
+}
+
 // The test input file has a repetitive structure:
 // - There are four resources, each with four profiles, each with one sample.
 // - The four resources have the following sets of attributes:
@@ -11,6 +11,7 @@ import (
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/countconnector/internal/hyperloglog"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/pdatautil"
 )
 
@@ -20,6 +21,7 @@ func newCounter[K any](metricDefs map[string]metricDef[K]) *counter[K] {
 	return &counter[K]{
 		metricDefs: metricDefs,
 		counts:     make(map[string]map[[16]byte]*attrCounter, len(metricDefs)),
+		distinct:   make(map[*distinctMetric]map[[16]byte]*distinctValues),
 		timestamp:  time.Now(),
 	}
 }
@@ -27,7 +29,10 @@ func newCounter[K any](metricDefs map[string]metricDef[K]) *counter[K] {
 type counter[K any] struct {
 	metricDefs map[string]metricDef[K]
 	counts     map[string]map[[16]byte]*attrCounter
-	timestamp  time.Time
+	// distinct holds the values of the attributes counted by the distinct metrics, which are counted over windows
+	// rather than per batch.
+	distinct  map[*distinctMetric]map[[16]byte]*distinctValues
+	timestamp time.Time
 }
 
 type attrCounter struct {
@@ -72,21 +77,62 @@ func (c *counter[K]) update(ctx context.Context, attrs pcommon.Map, tCtx K) erro
 			continue
 		}
 
+		var distinctHash uint64
+		if md.distinct != nil {
+			distinctVal, ok := attrs.Get(md.distinct.attribute)
+			if !ok {
+				continue
+			}
+			distinctHash = hyperloglog.Hash(distinctVal.AsString())
+		}
+
 		// No conditions, so match all.
 		if md.condition == nil {
-			multiError = errors.Join(multiError, c.increment(name, countAttrs))
+			multiError = errors.Join(multiError, c.count(name, md, countAttrs, distinctHash))
 			continue
 		}
 
 		if match, err := md.condition.Eval(ctx, tCtx); err != nil {
 			multiError = errors.Join(multiError, err)
 		} else if match {
-			multiError = errors.Join(multiError, c.increment(name, countAttrs))
+			multiError = errors.Join(multiError, c.count(name, md, countAttrs, distinctHash))
 		}
 	}
 	return multiError
 }
 
+func (c *counter[K]) count(metricName string, md metricDef[K], attrs pcommon.Map, distinctHash uint64) error {
+	if md.distinct != nil {
+		c.addDistinct(md.distinct, attrs, distinctHash)
+		return nil
+	}
+	return c.increment(metricName, attrs)
+}
+
+func (c *counter[K]) addDistinct(dm *distinctMetric, attrs pcommon.Map, hash uint64) {
+	if _, ok := c.distinct[dm]; !ok {
+		c.distinct[dm] = make(map[[16]byte]*distinctValues)
+	}
+
+	key := noAttributes
+	if attrs.Len() > 0 {
+		key = pdatautil.MapHash(attrs)
+	}
+
+	if _, ok := c.distinct[dm][key]; !ok {
+		c.distinct[dm][key] = &distinctValues{attrs: attrs}
+	}
+
+	c.distinct[dm][key].hashes = append(c.distinct[dm][key].hashes, hash)
+}
+
+// recordDistinct adds the values of the distinct attributes to the windows of the distinct metrics.
+func (c *counter[K]) recordDistinct(resource pcommon.Resource) {
+	for dm, values := range c.distinct {
+		dm.record(resource, values)
+	}
+}
+
 func (c *counter[K]) increment(metricName string, attrs pcommon.Map) error {
 	if _, ok := c.counts[metricName]; !ok {
 		c.counts[metricName] = make(map[[16]byte]*attrCounter)
@@ -0,0 +1,267 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package countconnector // import "github.com/open-telemetry/opentelemetry-collector-contrib/connector/countconnector"
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/countconnector/internal/hyperloglog"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/countconnector/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/pdatautil"
+)
+
+// overflowKey is the attribute of the data point counting the values beyond the maximum number of sketches.
+const overflowKey = "otel.metric.overflow"
+
+// distinctMetric counts the distinct values of an attribute per resource and set of attributes, and emits the
+// counts as a gauge at the end of each window.
+type distinctMetric struct {
+	name            string
+	desc            string
+	attribute       string
+	precision       uint8
+	window          time.Duration
+	maxSketches     int
+	metricsConsumer consumer.Metrics
+	logger          *zap.Logger
+
+	lock      sync.Mutex
+	start     time.Time
+	resources map[[16]byte]*distinctResource
+	// sketches is the number of sketches of the window, bounded by maxSketches.
+	sketches int
+	// overflow counts the values of the data beyond maxSketches, nil until the limit is reached in the window.
+	overflow *hyperloglog.Sketch
+	// overflowLogged is whether the limit was logged, which is logged once.
+	overflowLogged bool
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+type distinctResource struct {
+	attrs    pcommon.Map
+	sketches map[[16]byte]*attrSketch
+}
+
+type attrSketch struct {
+	attrs  pcommon.Map
+	sketch *hyperloglog.Sketch
+}
+
+// distinctValues are the hashes of the values of the distinct attribute, found in a set of attributes of a batch.
+type distinctValues struct {
+	attrs  pcommon.Map
+	hashes []uint64
+}
+
+func newDistinctMetric(name string, info MetricInfo, metricsConsumer consumer.Metrics, logger *zap.Logger) *distinctMetric {
+	if info.Distinct == nil {
+		return nil
+	}
+	dm := &distinctMetric{
+		name:            name,
+		desc:            info.Description,
+		attribute:       info.Distinct.Attribute,
+		precision:       info.Distinct.Precision,
+		window:          info.Distinct.Window,
+		maxSketches:     info.Distinct.MaxSketches,
+		metricsConsumer: metricsConsumer,
+		logger:          logger,
+		start:           time.Now(),
+		resources:       make(map[[16]byte]*distinctResource),
+	}
+	if dm.precision == 0 {
+		dm.precision = defaultDistinctPrecision
+	}
+	if dm.window == 0 {
+		dm.window = defaultDistinctWindow
+	}
+	if dm.maxSketches == 0 {
+		dm.maxSketches = defaultDistinctMaxSketches
+	}
+	return dm
+}
+
+// distinctMetricsOf returns the distinct metrics of the metric definitions.
+func distinctMetricsOf[K any](metricDefs map[string]metricDef[K]) []*distinctMetric {
+	var dms []*distinctMetric
+	for _, md := range metricDefs {
+		if md.distinct != nil {
+			dms = append(dms, md.distinct)
+		}
+	}
+	return dms
+}
+
+// startWindows starts emitting the counts at the end of each window.
+func (dm *distinctMetric) startWindows() {
+	dm.lock.Lock()
+	dm.start = time.Now()
+	dm.lock.Unlock()
+
+	dm.done = make(chan struct{})
+	dm.wg.Add(1)
+	go func() {
+		defer dm.wg.Done()
+		ticker := time.NewTicker(dm.window)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				dm.emit(context.Background())
+			case <-dm.done:
+				return
+			}
+		}
+	}()
+}
+
+// stopWindows stops emitting the counts, and emits the counts of the current window.
+func (dm *distinctMetric) stopWindows(ctx context.Context) {
+	if dm.done == nil {
+		return
+	}
+	close(dm.done)
+	dm.wg.Wait()
+	dm.done = nil
+	dm.emit(ctx)
+}
+
+// record adds the values found in a batch of data of the resource to the counts of the window. Once the window has
+// maxSketches sketches, the values of new resources and sets of attributes are added to the overflow sketch.
+func (dm *distinctMetric) record(resource pcommon.Resource, values map[[16]byte]*distinctValues) {
+	if len(values) == 0 {
+		return
+	}
+	resourceKey := pdatautil.MapHash(resource.Attributes())
+
+	dm.lock.Lock()
+	defer dm.lock.Unlock()
+	dr := dm.resources[resourceKey]
+	for key, v := range values {
+		var sketch *hyperloglog.Sketch
+		if as, ok := dr.sketch(key); ok {
+			sketch = as.sketch
+		} else if dm.sketches < dm.maxSketches {
+			if dr == nil {
+				dr = &distinctResource{
+					attrs:    pcommon.NewMap(),
+					sketches: make(map[[16]byte]*attrSketch),
+				}
+				resource.Attributes().CopyTo(dr.attrs)
+				dm.resources[resourceKey] = dr
+			}
+			sketch = hyperloglog.New(dm.precision)
+			dr.sketches[key] = &attrSketch{attrs: v.attrs, sketch: sketch}
+			dm.sketches++
+		} else {
+			sketch = dm.overflowSketch()
+		}
+		for _, hash := range v.hashes {
+			sketch.InsertHash(hash)
+		}
+	}
+}
+
+// sketch returns the sketch of the set of attributes of the resource, which may be nil.
+func (dr *distinctResource) sketch(key [16]byte) (*attrSketch, bool) {
+	if dr == nil {
+		return nil, false
+	}
+	as, ok := dr.sketches[key]
+	return as, ok
+}
+
+// overflowSketch returns the overflow sketch of the window, it must be called with the lock held.
+func (dm *distinctMetric) overflowSketch() *hyperloglog.Sketch {
+	if dm.overflow == nil {
+		dm.overflow = hyperloglog.New(dm.precision)
+		if !dm.overflowLogged {
+			dm.overflowLogged = true
+			dm.logger.Warn("Maximum number of distinct count sketches reached, the values of the new resources and attributes are counted together",
+				zap.String("metric", dm.name), zap.Int("max_sketches", dm.maxSketches))
+		}
+	}
+	return dm.overflow
+}
+
+// emit emits the counts of the window, and starts a new window.
+func (dm *distinctMetric) emit(ctx context.Context) {
+	dm.lock.Lock()
+	resources := dm.resources
+	overflow := dm.overflow
+	start := dm.start
+	dm.resources = make(map[[16]byte]*distinctResource)
+	dm.sketches = 0
+	dm.overflow = nil
+	dm.start = time.Now()
+	dm.lock.Unlock()
+
+	if len(resources) == 0 && overflow == nil {
+		return
+	}
+	md := dm.buildMetrics(resources, overflow, start, time.Now())
+	if err := dm.metricsConsumer.ConsumeMetrics(ctx, md); err != nil {
+		dm.logger.Error("Failed to emit the distinct counts", zap.String("metric", dm.name), zap.Error(err))
+	}
+}
+
+func (dm *distinctMetric) buildMetrics(resources map[[16]byte]*distinctResource, overflow *hyperloglog.Sketch, start, end time.Time) pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	md.ResourceMetrics().EnsureCapacity(len(resources) + 1)
+
+	// The resources are sorted so that the emitted metrics are deterministic.
+	keys := make([][16]byte, 0, len(resources))
+	for key := range resources {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return string(keys[i][:]) < string(keys[j][:])
+	})
+
+	for _, key := range keys {
+		dr := resources[key]
+		rm := md.ResourceMetrics().AppendEmpty()
+		dr.attrs.CopyTo(rm.Resource().Attributes())
+		dps := dm.appendGauge(rm)
+		dps.EnsureCapacity(len(dr.sketches))
+		for _, as := range dr.sketches {
+			dp := dps.AppendEmpty()
+			as.attrs.CopyTo(dp.Attributes())
+			dp.SetStartTimestamp(pcommon.NewTimestampFromTime(start))
+			dp.SetTimestamp(pcommon.NewTimestampFromTime(end))
+			dp.SetIntValue(int64(as.sketch.Estimate()))
+		}
+	}
+
+	// The values beyond the maximum number of sketches are emitted without resource attributes.
+	if overflow != nil {
+		dp := dm.appendGauge(md.ResourceMetrics().AppendEmpty()).AppendEmpty()
+		dp.Attributes().PutBool(overflowKey, true)
+		dp.SetStartTimestamp(pcommon.NewTimestampFromTime(start))
+		dp.SetTimestamp(pcommon.NewTimestampFromTime(end))
+		dp.SetIntValue(int64(overflow.Estimate()))
+	}
+	return md
+}
+
+// appendGauge appends the gauge of the metric to the resource metrics, and returns its data points.
+func (dm *distinctMetric) appendGauge(rm pmetric.ResourceMetrics) pmetric.NumberDataPointSlice {
+	sm := rm.ScopeMetrics().AppendEmpty()
+	sm.Scope().SetName(metadata.ScopeName)
+
+	m := sm.Metrics().AppendEmpty()
+	m.SetName(dm.name)
+	m.SetDescription(dm.desc)
+	return m.SetEmptyGauge().DataPoints()
+}
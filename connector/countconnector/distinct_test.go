@@ -0,0 +1,199 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package countconnector
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/countconnector/internal/hyperloglog"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/pdatautil"
+)
+
+func newTestDistinctMetric(window time.Duration, sink *consumertest.MetricsSink) *distinctMetric {
+	info := MetricInfo{
+		Description: "Distinct users.",
+		Distinct:    &DistinctConfig{Attribute: "user.id", Window: window},
+	}
+	return newDistinctMetric("user.distinct", info, sink, zap.NewNop())
+}
+
+func distinctValuesOf(attrs map[string]any, values ...string) map[[16]byte]*distinctValues {
+	m := pcommon.NewMap()
+	_ = m.FromRaw(attrs)
+	key := noAttributes
+	if m.Len() > 0 {
+		key = pdatautil.MapHash(m)
+	}
+	dv := &distinctValues{attrs: m}
+	for _, v := range values {
+		dv.hashes = append(dv.hashes, hyperloglog.Hash(v))
+	}
+	return map[[16]byte]*distinctValues{key: dv}
+}
+
+func TestNewDistinctMetric(t *testing.T) {
+	assert.Nil(t, newDistinctMetric("count", MetricInfo{}, consumertest.NewNop(), zap.NewNop()))
+
+	dm := newTestDistinctMetric(0, new(consumertest.MetricsSink))
+	assert.Equal(t, "user.distinct", dm.name)
+	assert.Equal(t, "Distinct users.", dm.desc)
+	assert.Equal(t, "user.id", dm.attribute)
+	assert.Equal(t, defaultDistinctWindow, dm.window)
+	assert.Equal(t, uint8(defaultDistinctPrecision), dm.precision)
+	assert.Equal(t, defaultDistinctMaxSketches, dm.maxSketches)
+}
+
+func TestDistinctMetricEmit(t *testing.T) {
+	sink := new(consumertest.MetricsSink)
+	dm := newTestDistinctMetric(time.Hour, sink)
+
+	// Nothing is emitted for an empty window.
+	dm.emit(context.Background())
+	assert.Empty(t, sink.AllMetrics())
+
+	resourceA := pcommon.NewResource()
+	resourceA.Attributes().PutStr("service.name", "a")
+	resourceB := pcommon.NewResource()
+	resourceB.Attributes().PutStr("service.name", "b")
+
+	var users []string
+	for i := 0; i < 50; i++ {
+		users = append(users, "user-"+strconv.Itoa(i))
+	}
+	dm.record(resourceA, distinctValuesOf(map[string]any{"http.route": "/users"}, users...))
+	// The values of the later batches are merged into the window.
+	dm.record(resourceA, distinctValuesOf(map[string]any{"http.route": "/users"}, users[:10]...))
+	dm.record(resourceA, distinctValuesOf(map[string]any{"http.route": "/orders"}, "user-1", "user-1"))
+	dm.record(resourceB, distinctValuesOf(nil, users[:20]...))
+
+	dm.emit(context.Background())
+	require.Len(t, sink.AllMetrics(), 1)
+	md := sink.AllMetrics()[0]
+	require.Equal(t, 2, md.ResourceMetrics().Len())
+
+	counts := make(map[string]int64)
+	for i := 0; i < md.ResourceMetrics().Len(); i++ {
+		rm := md.ResourceMetrics().At(i)
+		service, _ := rm.Resource().Attributes().Get("service.name")
+		sm := rm.ScopeMetrics().At(0)
+		require.Equal(t, 1, sm.Metrics().Len())
+		m := sm.Metrics().At(0)
+		assert.Equal(t, "user.distinct", m.Name())
+		assert.Equal(t, "Distinct users.", m.Description())
+		require.Equal(t, pmetric.MetricTypeGauge, m.Type())
+		for j := 0; j < m.Gauge().DataPoints().Len(); j++ {
+			dp := m.Gauge().DataPoints().At(j)
+			assert.LessOrEqual(t, dp.StartTimestamp(), dp.Timestamp())
+			route, _ := dp.Attributes().Get("http.route")
+			counts[service.Str()+route.Str()] = dp.IntValue()
+		}
+	}
+	assert.Equal(t, map[string]int64{"a/users": 50, "a/orders": 1, "b": 20}, counts)
+
+	// The next window starts empty.
+	dm.emit(context.Background())
+	assert.Len(t, sink.AllMetrics(), 1)
+}
+
+func TestDistinctMetricMaxSketches(t *testing.T) {
+	sink := new(consumertest.MetricsSink)
+	core, logs := observer.New(zap.WarnLevel)
+	info := MetricInfo{Distinct: &DistinctConfig{Attribute: "user.id", MaxSketches: 2}}
+	dm := newDistinctMetric("user.distinct", info, sink, zap.New(core))
+
+	resourceA := pcommon.NewResource()
+	resourceA.Attributes().PutStr("service.name", "a")
+	resourceB := pcommon.NewResource()
+	resourceB.Attributes().PutStr("service.name", "b")
+
+	dm.record(resourceA, distinctValuesOf(map[string]any{"http.route": "/users"}, "user-1", "user-2"))
+	dm.record(resourceA, distinctValuesOf(map[string]any{"http.route": "/orders"}, "user-1"))
+	// The limit is reached: the new sets of attributes and resources are counted in the overflow sketch.
+	dm.record(resourceA, distinctValuesOf(map[string]any{"http.route": "/carts"}, "user-3", "user-4"))
+	dm.record(resourceB, distinctValuesOf(nil, "user-4", "user-5"))
+	// The existing sketches are still updated.
+	dm.record(resourceA, distinctValuesOf(map[string]any{"http.route": "/users"}, "user-6"))
+	require.Equal(t, 1, logs.Len())
+	assert.Equal(t, "Maximum number of distinct count sketches reached, the values of the new resources and attributes are counted together", logs.All()[0].Message)
+
+	dm.emit(context.Background())
+	require.Len(t, sink.AllMetrics(), 1)
+	md := sink.AllMetrics()[0]
+	require.Equal(t, 2, md.ResourceMetrics().Len())
+
+	counts := make(map[string]int64)
+	for i := 0; i < md.ResourceMetrics().Len(); i++ {
+		rm := md.ResourceMetrics().At(i)
+		service, _ := rm.Resource().Attributes().Get("service.name")
+		dps := rm.ScopeMetrics().At(0).Metrics().At(0).Gauge().DataPoints()
+		for j := 0; j < dps.Len(); j++ {
+			dp := dps.At(j)
+			route, _ := dp.Attributes().Get("http.route")
+			if overflow, ok := dp.Attributes().Get(overflowKey); ok {
+				assert.True(t, overflow.Bool())
+				assert.Equal(t, 0, rm.Resource().Attributes().Len())
+				counts[overflowKey] = dp.IntValue()
+				continue
+			}
+			counts[service.Str()+route.Str()] = dp.IntValue()
+		}
+	}
+	assert.Equal(t, map[string]int64{"a/users": 3, "a/orders": 1, overflowKey: 3}, counts)
+
+	// The next window starts with no sketches, and the limit is logged once.
+	dm.record(resourceB, distinctValuesOf(nil, "user-7"))
+	dm.emit(context.Background())
+	require.Len(t, sink.AllMetrics(), 2)
+	rms := sink.AllMetrics()[1].ResourceMetrics()
+	require.Equal(t, 1, rms.Len())
+	service, _ := rms.At(0).Resource().Attributes().Get("service.name")
+	assert.Equal(t, "b", service.Str())
+	assert.Equal(t, 1, logs.Len())
+}
+
+func TestDistinctMetricWindows(t *testing.T) {
+	sink := new(consumertest.MetricsSink)
+	dm := newTestDistinctMetric(10*time.Millisecond, sink)
+	dm.startWindows()
+
+	dm.record(pcommon.NewResource(), distinctValuesOf(nil, "a", "b"))
+	require.Eventually(t, func() bool {
+		return len(sink.AllMetrics()) == 1
+	}, 5*time.Second, 5*time.Millisecond)
+	assert.Equal(t, int64(2), sink.AllMetrics()[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Gauge().DataPoints().At(0).IntValue())
+
+	// The current window is emitted on shutdown.
+	dm.record(pcommon.NewResource(), distinctValuesOf(nil, "c"))
+	dm.stopWindows(context.Background())
+	metrics := sink.AllMetrics()
+	require.Len(t, metrics, 2)
+	assert.Equal(t, int64(1), metrics[1].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Gauge().DataPoints().At(0).IntValue())
+
+	// Stopping again is a no-op.
+	dm.stopWindows(context.Background())
+	assert.Len(t, sink.AllMetrics(), 2)
+}
+
+func TestDistinctMetricEmitError(t *testing.T) {
+	core, logs := observer.New(zap.ErrorLevel)
+	info := MetricInfo{Distinct: &DistinctConfig{Attribute: "user.id"}}
+	dm := newDistinctMetric("user.distinct", info, consumertest.NewErr(errors.New("boom")), zap.New(core))
+
+	dm.record(pcommon.NewResource(), distinctValuesOf(nil, "a"))
+	dm.emit(context.Background())
+	require.Equal(t, 1, logs.Len())
+	assert.Equal(t, "Failed to emit the distinct counts", logs.All()[0].Message)
+}
@@ -53,8 +53,9 @@ func createTracesToMetrics(
 	spanMetricDefs := make(map[string]metricDef[ottlspan.TransformContext], len(c.Spans))
 	for name, info := range c.Spans {
 		md := metricDef[ottlspan.TransformContext]{
-			desc:  info.Description,
-			attrs: info.Attributes,
+			desc:     info.Description,
+			attrs:    info.Attributes,
+			distinct: newDistinctMetric(name, info, nextConsumer, set.Logger),
 		}
 		if len(info.Conditions) > 0 {
 			// Error checked in Config.Validate()
@@ -67,8 +68,9 @@ func createTracesToMetrics(
 	spanEventMetricDefs := make(map[string]metricDef[ottlspanevent.TransformContext], len(c.SpanEvents))
 	for name, info := range c.SpanEvents {
 		md := metricDef[ottlspanevent.TransformContext]{
-			desc:  info.Description,
-			attrs: info.Attributes,
+			desc:     info.Description,
+			attrs:    info.Attributes,
+			distinct: newDistinctMetric(name, info, nextConsumer, set.Logger),
 		}
 		if len(info.Conditions) > 0 {
 			// Error checked in Config.Validate()
@@ -82,6 +84,7 @@ func createTracesToMetrics(
 		metricsConsumer:      nextConsumer,
 		spansMetricDefs:      spanMetricDefs,
 		spanEventsMetricDefs: spanEventMetricDefs,
+		distinctMetrics:      append(distinctMetricsOf(spanMetricDefs), distinctMetricsOf(spanEventMetricDefs)...),
 	}, nil
 }
 
@@ -110,8 +113,9 @@ func createMetricsToMetrics(
 	dataPointMetricDefs := make(map[string]metricDef[ottldatapoint.TransformContext], len(c.DataPoints))
 	for name, info := range c.DataPoints {
 		md := metricDef[ottldatapoint.TransformContext]{
-			desc:  info.Description,
-			attrs: info.Attributes,
+			desc:     info.Description,
+			attrs:    info.Attributes,
+			distinct: newDistinctMetric(name, info, nextConsumer, set.Logger),
 		}
 		if len(info.Conditions) > 0 {
 			// Error checked in Config.Validate()
@@ -125,6 +129,7 @@ func createMetricsToMetrics(
 		metricsConsumer:      nextConsumer,
 		metricsMetricDefs:    metricMetricDefs,
 		dataPointsMetricDefs: dataPointMetricDefs,
+		distinctMetrics:      distinctMetricsOf(dataPointMetricDefs),
 	}, nil
 }
 
@@ -140,8 +145,9 @@ func createLogsToMetrics(
 	metricDefs := make(map[string]metricDef[ottllog.TransformContext], len(c.Logs))
 	for name, info := range c.Logs {
 		md := metricDef[ottllog.TransformContext]{
-			desc:  info.Description,
-			attrs: info.Attributes,
+			desc:     info.Description,
+			attrs:    info.Attributes,
+			distinct: newDistinctMetric(name, info, nextConsumer, set.Logger),
 		}
 		if len(info.Conditions) > 0 {
 			// Error checked in Config.Validate()
@@ -154,6 +160,7 @@ func createLogsToMetrics(
 	return &count{
 		metricsConsumer: nextConsumer,
 		logsMetricDefs:  metricDefs,
+		distinctMetrics: distinctMetricsOf(metricDefs),
 	}, nil
 }
 
@@ -169,8 +176,9 @@ func createProfilesToMetrics(
 	metricDefs := make(map[string]metricDef[ottlprofile.TransformContext], len(c.Profiles))
 	for name, info := range c.Profiles {
 		md := metricDef[ottlprofile.TransformContext]{
-			desc:  info.Description,
-			attrs: info.Attributes,
+			desc:     info.Description,
+			attrs:    info.Attributes,
+			distinct: newDistinctMetric(name, info, nextConsumer, set.Logger),
 		}
 		if len(info.Conditions) > 0 {
 			// Error checked in Config.Validate()
@@ -183,6 +191,7 @@ func createProfilesToMetrics(
 	return &count{
 		metricsConsumer:    nextConsumer,
 		profilesMetricDefs: metricDefs,
+		distinctMetrics:    distinctMetricsOf(metricDefs),
 	}, nil
 }
 
@@ -190,4 +199,6 @@ type metricDef[K any] struct {
 	condition *ottl.ConditionSequence[K]
 	desc      string
 	attrs     []AttributeConfig
+	// distinct is set for the distinct counts.
+	distinct *distinctMetric
 }
@@ -0,0 +1,88 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package hyperloglog estimates the number of distinct values of a set with the HyperLogLog algorithm.
+package hyperloglog // import "github.com/open-telemetry/opentelemetry-collector-contrib/connector/countconnector/internal/hyperloglog"
+
+import (
+	"math"
+	"math/bits"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+const (
+	// MinPrecision and MaxPrecision bound the precision of the sketches.
+	MinPrecision = 4
+	MaxPrecision = 16
+)
+
+// Sketch estimates the number of distinct values inserted into it. It uses 2^precision registers of one byte,
+// and the relative standard error of the estimates is about 1.04/sqrt(2^precision).
+type Sketch struct {
+	precision uint8
+	registers []uint8
+}
+
+// New returns an empty sketch, the precision must be between MinPrecision and MaxPrecision.
+func New(precision uint8) *Sketch {
+	return &Sketch{
+		precision: precision,
+		registers: make([]uint8, 1<<precision),
+	}
+}
+
+// Hash returns the 64-bit hash of a value, as inserted into the sketches.
+func Hash(value string) uint64 {
+	return xxhash.Sum64String(value)
+}
+
+// Insert adds a value to the sketch.
+func (s *Sketch) Insert(value string) {
+	s.InsertHash(Hash(value))
+}
+
+// InsertHash adds the hash of a value to the sketch.
+func (s *Sketch) InsertHash(hash uint64) {
+	// The first bits of the hash select the register, which keeps the maximum position of the first set bit
+	// of the remaining bits. A sentinel bit bounds the position when the remaining bits are all zero.
+	index := hash >> (64 - s.precision)
+	rank := uint8(bits.LeadingZeros64(hash<<s.precision|1<<(s.precision-1))) + 1
+	if rank > s.registers[index] {
+		s.registers[index] = rank
+	}
+}
+
+// Estimate returns the estimated number of distinct values inserted into the sketch.
+func (s *Sketch) Estimate() uint64 {
+	m := float64(len(s.registers))
+	var sum float64
+	zeros := 0
+	for _, r := range s.registers {
+		sum += math.Ldexp(1, -int(r))
+		if r == 0 {
+			zeros++
+		}
+	}
+
+	estimate := alpha(len(s.registers)) * m * m / sum
+	// The raw estimate is biased for the small cardinalities, which are better estimated by linear counting.
+	// No correction is needed for the large cardinalities with 64-bit hashes.
+	if estimate <= 2.5*m && zeros > 0 {
+		estimate = m * math.Log(m/float64(zeros))
+	}
+	return uint64(estimate + 0.5)
+}
+
+func alpha(m int) float64 {
+	switch m {
+	case 16:
+		return 0.673
+	case 32:
+		return 0.697
+	case 64:
+		return 0.709
+	default:
+		return 0.7213 / (1 + 1.079/float64(m))
+	}
+}
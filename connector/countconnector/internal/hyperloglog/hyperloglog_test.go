@@ -0,0 +1,54 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package hyperloglog
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmpty(t *testing.T) {
+	assert.Equal(t, uint64(0), New(14).Estimate())
+}
+
+func TestDuplicates(t *testing.T) {
+	s := New(14)
+	for i := 0; i < 1000; i++ {
+		s.Insert("user-" + strconv.Itoa(i%10))
+	}
+	assert.Equal(t, uint64(10), s.Estimate())
+}
+
+func TestEstimate(t *testing.T) {
+	for _, tc := range []struct {
+		precision uint8
+		count     int
+		// maxError is three times the relative standard error of the precision.
+		maxError float64
+	}{
+		{precision: 4, count: 100, maxError: 0.78},
+		{precision: 10, count: 1000, maxError: 0.1},
+		{precision: 12, count: 100000, maxError: 0.05},
+		{precision: 14, count: 100, maxError: 0.03},
+		{precision: 14, count: 1000000, maxError: 0.03},
+		{precision: 16, count: 50000, maxError: 0.02},
+	} {
+		t.Run(strconv.Itoa(int(tc.precision))+"/"+strconv.Itoa(tc.count), func(t *testing.T) {
+			s := New(tc.precision)
+			for i := 0; i < tc.count; i++ {
+				s.Insert("value-" + strconv.Itoa(i))
+			}
+			assert.InEpsilon(t, tc.count, s.Estimate(), tc.maxError)
+		})
+	}
+}
+
+func TestInsertHashZero(t *testing.T) {
+	s := New(MinPrecision)
+	s.InsertHash(0)
+	assert.Equal(t, uint64(1), s.Estimate())
+	assert.Equal(t, uint8(64-MinPrecision+1), s.registers[0])
+}
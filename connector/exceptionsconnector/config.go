@@ -5,10 +5,16 @@ package exceptionsconnector // import "github.com/open-telemetry/opentelemetry-c
 
 import (
 	"fmt"
+	"time"
 
 	"go.opentelemetry.io/collector/confmap/xconfmap"
 )
 
+const (
+	defaultFingerprintLogInterval = time.Minute
+	defaultMaxFingerprints        = 10000
+)
+
 // Dimension defines the dimension name and optional default value if the Dimension is missing from a span attribute.
 type Dimension struct {
 	Name    string  `mapstructure:"name"`
@@ -19,6 +25,20 @@ type Exemplars struct {
 	Enabled bool `mapstructure:"enabled"`
 }
 
+// Fingerprint defines the configuration for grouping the exceptions by the fingerprint of their stack trace.
+type Fingerprint struct {
+	// Enabled adds the exception.fingerprint dimension, a hash of the stack trace stripped from its line numbers,
+	// addresses, generated lambdas and messages, and masks the variable parts of the exception.message dimension.
+	// The logs are deduplicated per fingerprint.
+	Enabled bool `mapstructure:"enabled"`
+	// LogInterval is the minimum time period between two logs of the same fingerprint. The exceptions of a
+	// fingerprint occurring in this period are only counted by the metrics. Zero logs every exception.
+	LogInterval time.Duration `mapstructure:"log_interval"`
+	// MaxFingerprints is the maximum number of fingerprints whose last log is remembered. Once reached, the least
+	// recently logged fingerprint is forgotten, and is logged again on its next exception.
+	MaxFingerprints int `mapstructure:"max_fingerprints"`
+}
+
 // Config defines the configuration options for exceptionsconnector
 type Config struct {
 	// Dimensions defines the list of additional dimensions on top of the provided:
@@ -31,6 +51,8 @@ type Config struct {
 	Dimensions []Dimension `mapstructure:"dimensions"`
 	// Exemplars defines the configuration for exemplars.
 	Exemplars Exemplars `mapstructure:"exemplars"`
+	// Fingerprint defines the configuration for grouping the exceptions by the fingerprint of their stack trace.
+	Fingerprint Fingerprint `mapstructure:"fingerprint"`
 	// prevent unkeyed literal initialization
 	_ struct{}
 }
@@ -43,6 +65,19 @@ func (c Config) Validate() error {
 	if err != nil {
 		return err
 	}
+	if c.Fingerprint.Enabled {
+		for _, d := range c.Dimensions {
+			if d.Name == exceptionFingerprintKey {
+				return fmt.Errorf("duplicate dimension name %q", d.Name)
+			}
+		}
+		if c.Fingerprint.LogInterval < 0 {
+			return fmt.Errorf("invalid log_interval: %v, the duration should not be negative", c.Fingerprint.LogInterval)
+		}
+		if c.Fingerprint.MaxFingerprints <= 0 {
+			return fmt.Errorf("invalid max_fingerprints: %d, the number of fingerprints should be positive", c.Fingerprint.MaxFingerprints)
+		}
+	}
 	return nil
 }
 
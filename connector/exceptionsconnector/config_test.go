@@ -6,6 +6,7 @@ package exceptionsconnector
 import (
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -40,6 +41,11 @@ func TestLoadConfig(t *testing.T) {
 				Exemplars: Exemplars{
 					Enabled: false,
 				},
+				Fingerprint: Fingerprint{
+					Enabled:         true,
+					LogInterval:     5 * time.Minute,
+					MaxFingerprints: 100,
+				},
 			},
 		},
 	}
@@ -59,6 +65,43 @@ func TestLoadConfig(t *testing.T) {
 	}
 }
 
+func TestLoadInvalidConfig(t *testing.T) {
+	t.Parallel()
+
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		id          component.ID
+		expectedErr string
+	}{
+		{
+			id:          component.NewIDWithName(metadata.Type, "duplicate_fingerprint"),
+			expectedErr: "duplicate dimension name \"exception.fingerprint\"",
+		},
+		{
+			id:          component.NewIDWithName(metadata.Type, "negative_log_interval"),
+			expectedErr: "invalid log_interval: -1s, the duration should not be negative",
+		},
+		{
+			id:          component.NewIDWithName(metadata.Type, "invalid_max_fingerprints"),
+			expectedErr: "invalid max_fingerprints: 0, the number of fingerprints should be positive",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id.String(), func(t *testing.T) {
+			factory := NewFactory()
+			cfg := factory.CreateDefaultConfig()
+
+			sub, err := cm.Sub(tt.id.String())
+			require.NoError(t, err)
+			require.NoError(t, sub.Unmarshal(cfg))
+			assert.ErrorContains(t, xconfmap.Validate(cfg), tt.expectedErr)
+		})
+	}
+}
+
 func TestValidateDimensions(t *testing.T) {
 	for _, tc := range []struct {
 		name        string
@@ -5,7 +5,10 @@ package exceptionsconnector // import "github.com/open-telemetry/opentelemetry-c
 
 import (
 	"context"
+	"sync"
+	"time"
 
+	"github.com/hashicorp/golang-lru/v2/simplelru"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/pdata/pcommon"
@@ -28,17 +31,26 @@ type logsConnector struct {
 	component.StartFunc
 	component.ShutdownFunc
 
+	// lastLogged is the time of the last log of each fingerprint, nil unless the exceptions are fingerprinted.
+	lastLogged *simplelru.LRU[string, time.Time]
+	lock       sync.Mutex
+
 	logger *zap.Logger
 }
 
 func newLogsConnector(logger *zap.Logger, config component.Config) *logsConnector {
 	cfg := config.(*Config)
 
-	return &logsConnector{
+	c := &logsConnector{
 		logger:     logger,
 		config:     *cfg,
 		dimensions: newDimensions(cfg.Dimensions),
 	}
+	if cfg.Fingerprint.Enabled {
+		// The size is validated to be positive, the creation can't fail.
+		c.lastLogged, _ = simplelru.NewLRU[string, time.Time](cfg.Fingerprint.MaxFingerprints, nil)
+	}
+	return c
 }
 
 // Capabilities implements the consumer interface.
@@ -68,8 +80,17 @@ func (c *logsConnector) ConsumeTraces(ctx context.Context, traces ptrace.Traces)
 				span := spans.At(k)
 				for l := 0; l < span.Events().Len(); l++ {
 					event := span.Events().At(l)
-					if event.Name() == eventNameExc {
+					if event.Name() != eventNameExc {
+						continue
+					}
+					if c.lastLogged == nil {
 						c.attrToLogRecord(sl, serviceName, span, event, resourceAttr)
+						continue
+					}
+					fp := eventFingerprint(event.Attributes())
+					if c.shouldLog(fp, time.Now()) {
+						lr := c.attrToLogRecord(sl, serviceName, span, event, resourceAttr)
+						lr.Attributes().PutStr(exceptionFingerprintKey, fp)
 					}
 				}
 			}
@@ -78,6 +99,18 @@ func (c *logsConnector) ConsumeTraces(ctx context.Context, traces ptrace.Traces)
 	return c.exportLogs(ctx, ld)
 }
 
+// shouldLog reports whether an exception with the given fingerprint is logged, that is whether no exception with
+// the same fingerprint was logged in the log interval before now.
+func (c *logsConnector) shouldLog(fp string, now time.Time) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if last, ok := c.lastLogged.Get(fp); ok && now.Sub(last) < c.config.Fingerprint.LogInterval {
+		return false
+	}
+	c.lastLogged.Add(fp, now)
+	return true
+}
+
 func (c *logsConnector) exportLogs(ctx context.Context, ld plog.Logs) error {
 	if err := c.logsConsumer.ConsumeLogs(ctx, ld); err != nil {
 		c.logger.Error("failed to convert exceptions to logs", zap.Error(err))
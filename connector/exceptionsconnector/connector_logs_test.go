@@ -6,6 +6,7 @@ package exceptionsconnector
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -46,6 +47,64 @@ func TestConnectorLogConsumeTraces(t *testing.T) {
 	}
 }
 
+func TestConnectorLogConsumeTracesFingerprint(t *testing.T) {
+	lsink := new(consumertest.LogsSink)
+	cfg := createDefaultConfig().(*Config)
+	cfg.Fingerprint.Enabled = true
+	cfg.Fingerprint.LogInterval = time.Hour
+	p := newLogsConnector(zaptest.NewLogger(t), cfg)
+	p.logsConsumer = lsink
+
+	ctx := metadata.NewIncomingContext(context.Background(), nil)
+	require.NoError(t, p.Start(ctx, componenttest.NewNopHost()))
+	defer func() { require.NoError(t, p.Shutdown(ctx)) }()
+
+	// The second exception has the same fingerprint as the first one, and is not logged.
+	require.NoError(t, p.ConsumeTraces(ctx, buildFingerprintTrace()))
+	logs := lsink.AllLogs()
+	require.Len(t, logs, 1)
+	records := logs[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+	require.Equal(t, 2, records.Len())
+	fingerprints := make(map[string]string)
+	for i := 0; i < records.Len(); i++ {
+		attrs := records.At(i).Attributes()
+		message, ok := attrs.Get(exceptionMessageKey)
+		require.True(t, ok)
+		fp, ok := attrs.Get(exceptionFingerprintKey)
+		require.True(t, ok)
+		fingerprints[message.Str()] = fp.Str()
+	}
+	assert.Len(t, fingerprints, 2)
+	assert.Contains(t, fingerprints, "order 1234 not found")
+	assert.Contains(t, fingerprints, "payment 1234 not found")
+	assert.NotEqual(t, fingerprints["order 1234 not found"], fingerprints["payment 1234 not found"])
+
+	// The fingerprints were logged in the log interval.
+	require.NoError(t, p.ConsumeTraces(ctx, buildFingerprintTrace()))
+	logs = lsink.AllLogs()
+	require.Len(t, logs, 2)
+	assert.Equal(t, 0, logs[1].LogRecordCount())
+}
+
+func TestShouldLog(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Fingerprint.Enabled = true
+	cfg.Fingerprint.MaxFingerprints = 1
+	p := newLogsConnector(zap.NewNop(), cfg)
+
+	now := time.Now()
+	assert.True(t, p.shouldLog("a", now))
+	assert.False(t, p.shouldLog("a", now.Add(time.Second)))
+	assert.True(t, p.shouldLog("a", now.Add(cfg.Fingerprint.LogInterval)))
+
+	// Remembering b makes a forgotten, it is logged again.
+	assert.True(t, p.shouldLog("b", now.Add(cfg.Fingerprint.LogInterval)))
+	assert.True(t, p.shouldLog("a", now.Add(cfg.Fingerprint.LogInterval+time.Second)))
+
+	p.config.Fingerprint.LogInterval = 0
+	assert.True(t, p.shouldLog("a", now.Add(cfg.Fingerprint.LogInterval+time.Second)))
+}
+
 func newTestLogsConnector(lcon consumer.Logs, logger *zap.Logger) *logsConnector {
 	cfg := &Config{
 		Dimensions: []Dimension{
@@ -91,12 +91,16 @@ func (c *metricsConnector) ConsumeTraces(ctx context.Context, traces ptrace.Trac
 					event := span.Events().At(l)
 					if event.Name() == eventNameExc {
 						eventAttrs := event.Attributes()
+						var fp string
+						if c.config.Fingerprint.Enabled {
+							fp = eventFingerprint(eventAttrs)
+						}
 
 						c.keyBuf.Reset()
-						buildKey(c.keyBuf, serviceName, span, c.dimensions, eventAttrs, resourceAttr)
+						buildKey(c.keyBuf, serviceName, span, c.dimensions, eventAttrs, resourceAttr, fp)
 						key := c.keyBuf.String()
 
-						attrs := buildDimensionKVs(c.dimensions, serviceName, span, eventAttrs, resourceAttr)
+						attrs := buildDimensionKVs(c.dimensions, serviceName, span, eventAttrs, resourceAttr, fp)
 						exc := c.addException(key, attrs)
 						c.addExemplar(exc, span.TraceID(), span.SpanID())
 					}
@@ -176,18 +180,27 @@ func (c *metricsConnector) addExemplar(exc *exception, traceID pcommon.TraceID,
 	e.SetDoubleValue(float64(exc.count))
 }
 
-func buildDimensionKVs(dimensions []pdatautil.Dimension, serviceName string, span ptrace.Span, eventAttrs pcommon.Map, resourceAttrs pcommon.Map) pcommon.Map {
+// buildDimensionKVs builds the attributes of the metric. When the exception is fingerprinted, the fingerprint is
+// added, and the variable parts of the exception message are masked.
+func buildDimensionKVs(dimensions []pdatautil.Dimension, serviceName string, span ptrace.Span, eventAttrs pcommon.Map, resourceAttrs pcommon.Map, fingerprint string) pcommon.Map {
 	dims := pcommon.NewMap()
-	dims.EnsureCapacity(3 + len(dimensions))
+	dims.EnsureCapacity(5 + len(dimensions))
 	dims.PutStr(serviceNameKey, serviceName)
 	dims.PutStr(spanNameKey, span.Name())
 	dims.PutStr(spanKindKey, traceutil.SpanKindStr(span.Kind()))
 	dims.PutStr(statusCodeKey, traceutil.StatusCodeStr(span.Status().Code()))
 	for _, d := range dimensions {
 		if v, ok := pdatautil.GetDimensionValue(d, span.Attributes(), eventAttrs, resourceAttrs); ok {
+			if fingerprint != "" && d.Name == exceptionMessageKey {
+				dims.PutStr(d.Name, normalizeMessage(v.AsString()))
+				continue
+			}
 			v.CopyTo(dims.PutEmpty(d.Name))
 		}
 	}
+	if fingerprint != "" {
+		dims.PutStr(exceptionFingerprintKey, fingerprint)
+	}
 	return dims
 }
 
@@ -195,8 +208,9 @@ func buildDimensionKVs(dimensions []pdatautil.Dimension, serviceName string, spa
 // will attempt to add any additional dimensions the user has configured that match the span's attributes
 // or resource attributes. If the dimension exists in both, the span's attributes, being the most specific, takes precedence.
 //
-// The metric key is a simple concatenation of dimension values, delimited by a null character.
-func buildKey(dest *bytes.Buffer, serviceName string, span ptrace.Span, optionalDims []pdatautil.Dimension, eventAttrs pcommon.Map, resourceAttrs pcommon.Map) {
+// The metric key is a simple concatenation of dimension values, delimited by a null character. When the exception
+// is fingerprinted, the fingerprint is appended, and the variable parts of the exception message are masked.
+func buildKey(dest *bytes.Buffer, serviceName string, span ptrace.Span, optionalDims []pdatautil.Dimension, eventAttrs pcommon.Map, resourceAttrs pcommon.Map, fingerprint string) {
 	concatDimensionValue(dest, serviceName, false)
 	concatDimensionValue(dest, span.Name(), true)
 	concatDimensionValue(dest, traceutil.SpanKindStr(span.Kind()), true)
@@ -204,9 +218,16 @@ func buildKey(dest *bytes.Buffer, serviceName string, span ptrace.Span, optional
 
 	for _, d := range optionalDims {
 		if v, ok := getDimensionValue(d, span.Attributes(), eventAttrs, resourceAttrs); ok {
-			concatDimensionValue(dest, v.AsString(), true)
+			value := v.AsString()
+			if fingerprint != "" && d.Name == exceptionMessageKey {
+				value = normalizeMessage(value)
+			}
+			concatDimensionValue(dest, value, true)
 		}
 	}
+	if fingerprint != "" {
+		concatDimensionValue(dest, fingerprint, true)
+	}
 }
 
 func concatDimensionValue(dest *bytes.Buffer, value string, prefixSep bool) {
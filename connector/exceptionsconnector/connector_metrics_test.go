@@ -107,6 +107,37 @@ func TestConnectorConsumeTraces(t *testing.T) {
 	})
 }
 
+func TestConnectorConsumeTracesFingerprint(t *testing.T) {
+	msink := &consumertest.MetricsSink{}
+	cfg := createDefaultConfig().(*Config)
+	cfg.Fingerprint.Enabled = true
+	p := newMetricsConnector(zaptest.NewLogger(t), cfg)
+	p.metricsConsumer = msink
+
+	ctx := metadata.NewIncomingContext(context.Background(), nil)
+	require.NoError(t, p.Start(ctx, componenttest.NewNopHost()))
+	defer func() { require.NoError(t, p.Shutdown(ctx)) }()
+
+	require.NoError(t, p.ConsumeTraces(ctx, buildFingerprintTrace()))
+
+	metrics := msink.AllMetrics()
+	require.Len(t, metrics, 1)
+	dps := metrics[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Sum().DataPoints()
+	require.Equal(t, 2, dps.Len())
+
+	counts := make(map[string]int64)
+	for i := 0; i < dps.Len(); i++ {
+		dp := dps.At(i)
+		message, ok := dp.Attributes().Get(exceptionMessageKey)
+		require.True(t, ok)
+		fp, ok := dp.Attributes().Get(exceptionFingerprintKey)
+		require.True(t, ok)
+		assert.Len(t, fp.Str(), 16)
+		counts[message.Str()] = dp.IntValue()
+	}
+	assert.Equal(t, map[string]int64{"order <*> not found": 2, "payment <*> not found": 1}, counts)
+}
+
 func BenchmarkConnectorConsumeTraces(b *testing.B) {
 	msink := &consumertest.MetricsSink{}
 
@@ -257,18 +288,44 @@ func TestBuildKeySameServiceOperationCharSequence(t *testing.T) {
 	span0 := ptrace.NewSpan()
 	span0.SetName("c")
 	buf := &bytes.Buffer{}
-	buildKey(buf, "ab", span0, nil, pcommon.NewMap(), pcommon.NewMap())
+	buildKey(buf, "ab", span0, nil, pcommon.NewMap(), pcommon.NewMap(), "")
 	k0 := buf.String()
 	buf.Reset()
 	span1 := ptrace.NewSpan()
 	span1.SetName("bc")
-	buildKey(buf, "a", span1, nil, pcommon.NewMap(), pcommon.NewMap())
+	buildKey(buf, "a", span1, nil, pcommon.NewMap(), pcommon.NewMap(), "")
 	k1 := buf.String()
 	assert.NotEqual(t, k0, k1)
 	assert.Equal(t, "ab\u0000c\u0000SPAN_KIND_UNSPECIFIED\u0000STATUS_CODE_UNSET", k0)
 	assert.Equal(t, "a\u0000bc\u0000SPAN_KIND_UNSPECIFIED\u0000STATUS_CODE_UNSET", k1)
 }
 
+func TestBuildKeyWithFingerprint(t *testing.T) {
+	span0 := ptrace.NewSpan()
+	span0.SetName("c")
+	eventAttrs := pcommon.NewMap()
+	eventAttrs.PutStr(exceptionMessageKey, "user 42 not found")
+	dims := []pdatautil.Dimension{{Name: exceptionMessageKey}}
+
+	buf := &bytes.Buffer{}
+	buildKey(buf, "ab", span0, dims, eventAttrs, pcommon.NewMap(), "")
+	assert.Equal(t, "ab\u0000c\u0000SPAN_KIND_UNSPECIFIED\u0000STATUS_CODE_UNSET\u0000user 42 not found", buf.String())
+
+	buf.Reset()
+	buildKey(buf, "ab", span0, dims, eventAttrs, pcommon.NewMap(), "0123456789abcdef")
+	assert.Equal(t, "ab\u0000c\u0000SPAN_KIND_UNSPECIFIED\u0000STATUS_CODE_UNSET\u0000user <*> not found\u00000123456789abcdef", buf.String())
+
+	attrs := buildDimensionKVs(dims, "ab", span0, eventAttrs, pcommon.NewMap(), "0123456789abcdef")
+	assert.Equal(t, map[string]any{
+		serviceNameKey:          "ab",
+		spanNameKey:             "c",
+		spanKindKey:             "SPAN_KIND_UNSPECIFIED",
+		statusCodeKey:           "STATUS_CODE_UNSET",
+		exceptionMessageKey:     "user <*> not found",
+		exceptionFingerprintKey: "0123456789abcdef",
+	}, attrs.AsRaw())
+}
+
 func TestBuildKeyWithDimensions(t *testing.T) {
 	defaultFoo := pcommon.NewValueStr("bar")
 	for _, tc := range []struct {
@@ -337,7 +394,7 @@ func TestBuildKeyWithDimensions(t *testing.T) {
 			assert.NoError(t, span0.Attributes().FromRaw(tc.spanAttrMap))
 			span0.SetName("c")
 			buf := &bytes.Buffer{}
-			buildKey(buf, "ab", span0, tc.optionalDims, pcommon.NewMap(), resAttr)
+			buildKey(buf, "ab", span0, tc.optionalDims, pcommon.NewMap(), resAttr, "")
 			assert.Equal(t, tc.wantKey, buf.String())
 		})
 	}
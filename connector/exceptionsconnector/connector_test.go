@@ -111,3 +111,39 @@ func initSpan(span span, s ptrace.Span) {
 	e.Attributes().PutStr(exceptionMessageKey, "Exception message")
 	e.Attributes().PutStr(exceptionStacktraceKey, "Exception stacktrace")
 }
+
+// buildFingerprintTrace builds a trace with a span recording three exceptions, the first two only differing by
+// their message and line numbers.
+func buildFingerprintTrace() ptrace.Traces {
+	traces := ptrace.NewTraces()
+	rs := traces.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr(string(conventions.ServiceNameKey), "service-a")
+	s := rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	initSpan(span{name: "svc-a-ep1", kind: ptrace.SpanKindServer, statusCode: ptrace.StatusCodeError}, s)
+	s.Events().RemoveIf(func(ptrace.SpanEvent) bool { return true })
+
+	for _, exc := range []struct {
+		message    string
+		stacktrace string
+	}{
+		{
+			message:    "order 1234 not found",
+			stacktrace: "java.lang.IllegalStateException: order 1234 not found\n\tat com.example.OrderService.find(OrderService.java:42)",
+		},
+		{
+			message:    "order 5678 not found",
+			stacktrace: "java.lang.IllegalStateException: order 5678 not found\n\tat com.example.OrderService.find(OrderService.java:45)",
+		},
+		{
+			message:    "payment 1234 not found",
+			stacktrace: "java.lang.IllegalStateException: payment 1234 not found\n\tat com.example.PaymentService.pay(PaymentService.java:42)",
+		},
+	} {
+		e := s.Events().AppendEmpty()
+		e.SetName("exception")
+		e.Attributes().PutStr(exceptionTypeKey, "java.lang.IllegalStateException")
+		e.Attributes().PutStr(exceptionMessageKey, exc.message)
+		e.Attributes().PutStr(exceptionStacktraceKey, exc.stacktrace)
+	}
+	return traces
+}
@@ -31,6 +31,10 @@ func createDefaultConfig() component.Config {
 			{Name: exceptionTypeKey},
 			{Name: exceptionMessageKey},
 		},
+		Fingerprint: Fingerprint{
+			LogInterval:     defaultFingerprintLogInterval,
+			MaxFingerprints: defaultMaxFingerprints,
+		},
 	}
 }
 
@@ -0,0 +1,88 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exceptionsconnector // import "github.com/open-telemetry/opentelemetry-collector-contrib/connector/exceptionsconnector"
+
+import (
+	"encoding/hex"
+	"hash/fnv"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/pdatautil"
+)
+
+const (
+	exceptionFingerprintKey = "exception.fingerprint" // OpenTelemetry non-standard constant.
+
+	// maskedValue replaces the variable parts of the stack traces and messages.
+	maskedValue = "<*>"
+)
+
+var (
+	// exceptionHeaderRegexp matches the lines made of an exception type followed by its message, as in
+	// "java.lang.IllegalStateException: user 42 not found" or "Caused by: ValueError: invalid literal".
+	exceptionHeaderRegexp = regexp.MustCompile(`^((?:Caused by|Suppressed): )?([\w.$]+): .*$`)
+	// generatedClassRegexp matches the names of the classes generated at runtime, such as
+	// "GeneratedMethodAccessor12" or "$Proxy34".
+	generatedClassRegexp = regexp.MustCompile(`(Accessor|\$Proxy)\d+\b`)
+	uuidRegexp           = regexp.MustCompile(`\b[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}\b`)
+	addressRegexp        = regexp.MustCompile(`\b0x[0-9a-fA-F]+\b`)
+	// numberRegexp matches the decimal and hexadecimal numbers, such as line numbers, lambda indexes or IDs.
+	numberRegexp = regexp.MustCompile(`\b[0-9a-fA-F]*[0-9][0-9a-fA-F]*\b`)
+	quotedRegexp = regexp.MustCompile(`"[^"]*"|'[^']*'`)
+)
+
+// eventFingerprint returns the fingerprint of the exception recorded by the span event.
+func eventFingerprint(eventAttrs pcommon.Map) string {
+	excType, _ := pdatautil.GetAttributeValue(exceptionTypeKey, eventAttrs)
+	message, _ := pdatautil.GetAttributeValue(exceptionMessageKey, eventAttrs)
+	stacktrace, _ := pdatautil.GetAttributeValue(exceptionStacktraceKey, eventAttrs)
+	return fingerprint(excType, message, stacktrace)
+}
+
+// fingerprint returns a stable hash of the exception, which doesn't change with the line numbers, the memory
+// addresses, the generated lambdas and the messages of the stack trace. Without stack trace, the exception type
+// and the normalized message are hashed instead.
+func fingerprint(excType, message, stacktrace string) string {
+	h := fnv.New64a()
+	if normalized := normalizeStacktrace(stacktrace); normalized != "" {
+		h.Write([]byte(normalized))
+	} else {
+		h.Write([]byte(excType))
+		h.Write([]byte{0})
+		h.Write([]byte(normalizeMessage(message)))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// normalizeStacktrace removes the variable parts of the stack trace: the messages of the exceptions are
+// dropped, the addresses and the numbers are masked, and the blank lines and indentation are removed.
+func normalizeStacktrace(stacktrace string) string {
+	lines := strings.Split(stacktrace, "\n")
+	normalized := make([]string, 0, len(lines))
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		line = exceptionHeaderRegexp.ReplaceAllString(line, "$1$2")
+		line = generatedClassRegexp.ReplaceAllString(line, "$1"+maskedValue)
+		normalized = append(normalized, maskNumbers(line))
+	}
+	return strings.Join(normalized, "\n")
+}
+
+// normalizeMessage masks the variable parts of the exception message: the quoted values, the UUIDs, the
+// addresses and the numbers.
+func normalizeMessage(message string) string {
+	return maskNumbers(quotedRegexp.ReplaceAllString(message, maskedValue))
+}
+
+func maskNumbers(s string) string {
+	s = uuidRegexp.ReplaceAllString(s, maskedValue)
+	s = addressRegexp.ReplaceAllString(s, maskedValue)
+	return numberRegexp.ReplaceAllString(s, maskedValue)
+}
@@ -0,0 +1,97 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exceptionsconnector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+func TestNormalizeStacktrace(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		stacktrace string
+		want       string
+	}{
+		{
+			name: "java",
+			stacktrace: `java.lang.IllegalStateException: order 1234 not found
+	at com.example.OrderService.lambda$find$0(OrderService.java:42)
+	at com.example.OrderService$$Lambda$123/0x0000000800c0b440.apply(Unknown Source)
+	at jdk.internal.reflect.GeneratedMethodAccessor12.invoke(Unknown Source)
+	at com.example.Handler.handle(Handler.java:17)
+Caused by: java.sql.SQLException: connection 'db-7' refused
+	... 12 more
+`,
+			want: `java.lang.IllegalStateException
+at com.example.OrderService.lambda$find$<*>(OrderService.java:<*>)
+at com.example.OrderService$$Lambda$<*>/<*>.apply(Unknown Source)
+at jdk.internal.reflect.GeneratedMethodAccessor<*>.invoke(Unknown Source)
+at com.example.Handler.handle(Handler.java:<*>)
+Caused by: java.sql.SQLException
+... <*> more`,
+		},
+		{
+			name: "python",
+			stacktrace: `Traceback (most recent call last):
+  File "/app/handler.py", line 12, in handle
+    user = load(user_id)
+ValueError: invalid literal for int() with base 10: 'abc'`,
+			want: `Traceback (most recent call last):
+File "/app/handler.py", line <*>, in handle
+user = load(user_id)
+ValueError`,
+		},
+		{
+			name: "go",
+			stacktrace: `panic: runtime error: index out of range [5] with length 3
+
+goroutine 7 [running]:
+main.handle(0xc000012345, 0x3)
+	/app/main.go:42 +0x1d`,
+			want: `panic
+goroutine <*> [running]:
+main.handle(<*>, <*>)
+/app/main.go:<*> +<*>`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, normalizeStacktrace(tc.stacktrace))
+		})
+	}
+}
+
+func TestNormalizeMessage(t *testing.T) {
+	assert.Equal(t, "order <*> of user <*> not found at <*>: <*>",
+		normalizeMessage(`order 1234 of user "alice" not found at 0x7ffd5e8c: 123e4567-e89b-12d3-a456-426614174000`))
+	assert.Equal(t, "connection refused", normalizeMessage("connection refused"))
+}
+
+func TestFingerprint(t *testing.T) {
+	first := fingerprint("java.lang.IllegalStateException", "order 1234 not found", `java.lang.IllegalStateException: order 1234 not found
+	at com.example.OrderService.find(OrderService.java:42)`)
+	// The same exception thrown from a newer version of the code, with another message.
+	second := fingerprint("java.lang.IllegalStateException", "order 5678 not found", `java.lang.IllegalStateException: order 5678 not found
+	at com.example.OrderService.find(OrderService.java:45)`)
+	assert.Equal(t, first, second)
+	assert.Len(t, first, 16)
+
+	other := fingerprint("java.lang.IllegalStateException", "order 1234 not found", `java.lang.IllegalStateException: order 1234 not found
+	at com.example.PaymentService.pay(PaymentService.java:42)`)
+	assert.NotEqual(t, first, other)
+
+	// Without stack trace, the type and the message are fingerprinted.
+	assert.Equal(t, fingerprint("IOException", "read 12 bytes", ""), fingerprint("IOException", "read 34 bytes", "  \n"))
+	assert.NotEqual(t, fingerprint("IOException", "read 12 bytes", ""), fingerprint("EOFException", "read 12 bytes", ""))
+}
+
+func TestEventFingerprint(t *testing.T) {
+	attrs := pcommon.NewMap()
+	attrs.PutStr(exceptionTypeKey, "Exception")
+	attrs.PutStr(exceptionMessageKey, "Exception message")
+	attrs.PutStr(exceptionStacktraceKey, "Exception stacktrace")
+	assert.Equal(t, fingerprint("Exception", "Exception message", "Exception stacktrace"), eventFingerprint(attrs))
+}
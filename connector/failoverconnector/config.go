@@ -7,12 +7,16 @@ import (
 	"errors"
 	"time"
 
+	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/pipeline"
 )
 
 var (
-	errNoPipelinePriority    = errors.New("No pipelines are defined in the priority list")
-	errInvalidRetryIntervals = errors.New("Retry interval must be positive")
+	errNoPipelinePriority     = errors.New("No pipelines are defined in the priority list")
+	errInvalidRetryIntervals  = errors.New("Retry interval must be positive")
+	errInvalidCheckInterval   = errors.New("Health check interval must not be negative")
+	errInvalidFailbackDelay   = errors.New("Health check failback delay must not be negative")
+	errInvalidQueueSaturation = errors.New("Health check queue saturation must be between 0 and 1")
 )
 
 type Config struct {
@@ -33,10 +37,33 @@ type Config struct {
 	// MaxRetry is the maximum retries per level, once this limit is hit for a level, even if the next pipeline level fails,
 	// it will not try to recover the level that exceeded the maximum retries
 	MaxRetries int `mapstructure:"max_retries"` // **Deprecated**
+
+	// HealthCheck enables failing over on the status of the pipelines, reported by an extension, on top of their errors
+	HealthCheck *HealthCheckConfig `mapstructure:"health_check"`
 	// prevent unkeyed literal initialization
 	_ struct{}
 }
 
+// HealthCheckConfig defines the extension reporting the status of the pipelines, and how the failover
+// connector reacts to it
+type HealthCheckConfig struct {
+	// Extension is the ID of the extension providing the status of the pipelines, aggregated from the status events
+	// of their components, e.g. healthcheckv2. A level is failed over as soon as one of its pipelines reports an error
+	Extension component.ID `mapstructure:"extension"`
+
+	// CheckInterval is the frequency at which the status of the pipelines is checked, 5s if unset
+	CheckInterval time.Duration `mapstructure:"check_interval"`
+
+	// QueueSaturation is the ratio of the size of the sending queue of an exporter over its capacity from which its
+	// level is failed over, so that the connector fails over before the queue is full and rejects data. The extension
+	// must then provide the queue usage of the pipelines. Disabled if unset
+	QueueSaturation float64 `mapstructure:"queue_saturation"`
+
+	// FailbackDelay is how long a level of higher priority must stay healthy before failing back to it, so that a
+	// flapping pipeline doesn't make the connector switch levels back and forth
+	FailbackDelay time.Duration `mapstructure:"failback_delay"`
+}
+
 // Validate needs to ensure RetryInterval > # elements in PriorityList * RetryGap
 func (c *Config) Validate() error {
 	if len(c.PipelinePriority) == 0 {
@@ -45,5 +72,16 @@ func (c *Config) Validate() error {
 	if c.RetryInterval <= 0 {
 		return errInvalidRetryIntervals
 	}
+	if c.HealthCheck != nil {
+		if c.HealthCheck.CheckInterval < 0 {
+			return errInvalidCheckInterval
+		}
+		if c.HealthCheck.FailbackDelay < 0 {
+			return errInvalidFailbackDelay
+		}
+		if c.HealthCheck.QueueSaturation < 0 || c.HealthCheck.QueueSaturation > 1 {
+			return errInvalidQueueSaturation
+		}
+	}
 	return nil
 }
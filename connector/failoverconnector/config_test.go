@@ -55,6 +55,26 @@ func TestLoadConfig(t *testing.T) {
 				RetryInterval: 5 * time.Minute,
 			},
 		},
+		{
+			id: component.NewIDWithName(metadata.Type, "health_check"),
+			expected: &Config{
+				PipelinePriority: [][]pipeline.ID{
+					{
+						pipeline.NewIDWithName(pipeline.SignalTraces, "first"),
+					},
+					{
+						pipeline.NewIDWithName(pipeline.SignalTraces, "second"),
+					},
+				},
+				RetryInterval: 10 * time.Minute,
+				HealthCheck: &HealthCheckConfig{
+					Extension:       component.MustNewID("healthcheckv2"),
+					CheckInterval:   10 * time.Second,
+					QueueSaturation: 0.8,
+					FailbackDelay:   2 * time.Minute,
+				},
+			},
+		},
 	}
 
 	for _, tc := range testcases {
@@ -91,6 +111,21 @@ func TestValidateConfig(t *testing.T) {
 			id:   component.NewIDWithName(metadata.Type, "invalid"),
 			err:  errInvalidRetryIntervals,
 		},
+		{
+			name: "invalid check_interval",
+			id:   component.NewIDWithName(metadata.Type, "invalid_check_interval"),
+			err:  errInvalidCheckInterval,
+		},
+		{
+			name: "invalid failback_delay",
+			id:   component.NewIDWithName(metadata.Type, "invalid_failback_delay"),
+			err:  errInvalidFailbackDelay,
+		},
+		{
+			name: "invalid queue_saturation",
+			id:   component.NewIDWithName(metadata.Type, "invalid_queue_saturation"),
+			err:  errInvalidQueueSaturation,
+		},
 	}
 
 	for _, tc := range testcases {
@@ -5,7 +5,11 @@ package failoverconnector // import "github.com/open-telemetry/opentelemetry-col
 
 import (
 	"errors"
+	"fmt"
+	"time"
 
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componentstatus"
 	"go.opentelemetry.io/collector/pipeline"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/failoverconnector/internal/state"
@@ -16,14 +20,31 @@ var (
 	errConsumer        = errors.New("Error registering consumer")
 )
 
+const defaultCheckInterval = 5 * time.Second
+
 type consumerProvider[C any] func(...pipeline.ID) (C, error)
 
+// PipelineStatusProvider is implemented by the extensions providing the status of the pipelines, aggregated from
+// the status events of their components, e.g. healthcheckv2. The boolean return value indicates whether or not
+// the status of the pipeline is known.
+type PipelineStatusProvider interface {
+	PipelineStatus(pipeline.ID) (componentstatus.Status, bool)
+}
+
+// PipelineQueueProvider is implemented by the extensions providing the usage of the sending queues of the exporters
+// of the pipelines. It returns the size and the capacity of the most used queue of the pipeline, and whether or not
+// they are known.
+type PipelineQueueProvider interface {
+	PipelineQueueUsage(pipeline.ID) (size, capacity int64, ok bool)
+}
+
 // baseFailoverRouter provides the common infrastructure for failover routing
 type baseFailoverRouter[C any] struct {
 	cfg       *Config
 	pS        *state.PipelineSelector
 	consumers []C
 
+	health      *state.HealthMonitor
 	errTryLock  *state.TryLock
 	notifyRetry chan struct{}
 	done        chan struct{}
@@ -44,11 +65,98 @@ func (f *baseFailoverRouter[C]) getConsumerAtIndex(idx int) C {
 	return f.consumers[idx]
 }
 
-// reportConsumerError ensures only one consumer is reporting an error at a time to avoid multiple failovers
+// reportConsumerError ensures only one consumer is reporting an error at a time to avoid multiple failovers, and
+// makes the level unhealthy for the health check so that it isn't failed back to before the failback delay
 func (f *baseFailoverRouter[C]) reportConsumerError(idx int) {
+	if f.health != nil {
+		f.health.MarkUnhealthy(idx)
+	}
 	f.errTryLock.TryExecute(f.pS.HandleError, idx)
 }
 
+// retryable returns whether the level can be retried, which isn't the case of the levels not healthy for the
+// failback delay when the health check is enabled
+func (f *baseFailoverRouter[C]) retryable(idx int) bool {
+	return f.health == nil || f.health.Stable(idx, time.Now())
+}
+
+// Start looks up the extension providing the status of the pipelines, and the usage of their queues if the queue
+// saturation is set, and starts checking their health, if enabled
+func (f *baseFailoverRouter[C]) Start(host component.Host) error {
+	if f.cfg.HealthCheck == nil {
+		return nil
+	}
+
+	id := f.cfg.HealthCheck.Extension
+	ext, ok := host.GetExtensions()[id]
+	if !ok {
+		return fmt.Errorf("health check extension '%s' not found", id)
+	}
+	var status state.StatusFunc
+	if provider, ok := ext.(PipelineStatusProvider); ok {
+		status = provider.PipelineStatus
+	}
+	var queue state.QueueFunc
+	if f.cfg.HealthCheck.QueueSaturation > 0 {
+		provider, ok := ext.(PipelineQueueProvider)
+		if !ok {
+			return fmt.Errorf("extension '%s' doesn't provide the queue usage of the pipelines", id)
+		}
+		queue = provider.PipelineQueueUsage
+	}
+	if status == nil && queue == nil {
+		return fmt.Errorf("extension '%s' doesn't provide the status of the pipelines", id)
+	}
+
+	f.health = state.NewHealthMonitor(f.cfg.PipelinePriority, status, queue, f.cfg.HealthCheck.QueueSaturation, f.cfg.HealthCheck.FailbackDelay)
+	f.health.Update(time.Now())
+
+	checkInterval := f.cfg.HealthCheck.CheckInterval
+	if checkInterval == 0 {
+		checkInterval = defaultCheckInterval
+	}
+	go f.healthCheckLoop(checkInterval)
+	return nil
+}
+
+func (f *baseFailoverRouter[C]) healthCheckLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			f.checkHealth(now)
+		case <-f.done:
+			return
+		}
+	}
+}
+
+// checkHealth fails over from the current level as soon as it is unhealthy, to the next healthy level, and fails
+// back to the level of highest priority which has been healthy for the failback delay
+func (f *baseFailoverRouter[C]) checkHealth(now time.Time) {
+	f.health.Update(now)
+	current := f.pS.CurrentPipeline()
+	for idx := range f.consumers {
+		switch {
+		case idx < current:
+			if f.health.Stable(idx, now) {
+				f.pS.ResetHealthyPipeline(idx)
+				return
+			}
+		case idx == current:
+			if f.health.Healthy(idx) {
+				return
+			}
+		default:
+			if f.health.Healthy(idx) {
+				f.pS.FailoverTo(idx)
+				return
+			}
+		}
+	}
+}
+
 func (f *baseFailoverRouter[C]) Shutdown() {
 	close(f.done)
 }
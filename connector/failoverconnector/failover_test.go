@@ -4,11 +4,15 @@
 package failoverconnector // import "github.com/open-telemetry/opentelemetry-collector-contrib/connector/failoverconnector"
 import (
 	"context"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componentstatus"
+	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/connector"
 	"go.opentelemetry.io/collector/connector/connectortest"
 	"go.opentelemetry.io/collector/consumer"
@@ -137,3 +141,281 @@ func resetConsumers(conn *tracesFailover, consumers ...consumer.Traces) {
 	}
 	conn.failover.TestSetStableConsumerIndex(0)
 }
+
+type statusExtension struct {
+	component.StartFunc
+	component.ShutdownFunc
+
+	lock     sync.Mutex
+	statuses map[pipeline.ID]componentstatus.Status
+}
+
+func (e *statusExtension) PipelineStatus(id pipeline.ID) (componentstatus.Status, bool) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	st, ok := e.statuses[id]
+	return st, ok
+}
+
+func (e *statusExtension) setStatus(id pipeline.ID, st componentstatus.Status) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.statuses[id] = st
+}
+
+type queueUsage struct {
+	size, capacity int64
+}
+
+type queueExtension struct {
+	component.StartFunc
+	component.ShutdownFunc
+
+	lock   sync.Mutex
+	usages map[pipeline.ID]queueUsage
+}
+
+func (e *queueExtension) PipelineQueueUsage(id pipeline.ID) (int64, int64, bool) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	u, ok := e.usages[id]
+	return u.size, u.capacity, ok
+}
+
+func (e *queueExtension) setUsage(id pipeline.ID, size, capacity int64) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.usages[id] = queueUsage{size: size, capacity: capacity}
+}
+
+type nopExtension struct {
+	component.StartFunc
+	component.ShutdownFunc
+}
+
+type extensionsHost struct {
+	extensions map[component.ID]component.Component
+}
+
+func (h extensionsHost) GetExtensions() map[component.ID]component.Component {
+	return h.extensions
+}
+
+func TestFailoverHealthCheck(t *testing.T) {
+	var sinkFirst, sinkSecond, sinkThird consumertest.TracesSink
+	tracesFirst := pipeline.NewIDWithName(pipeline.SignalTraces, "traces/first")
+	tracesSecond := pipeline.NewIDWithName(pipeline.SignalTraces, "traces/second")
+	tracesThird := pipeline.NewIDWithName(pipeline.SignalTraces, "traces/third")
+	extensionID := component.MustNewID("healthcheckv2")
+
+	cfg := &Config{
+		PipelinePriority: [][]pipeline.ID{{tracesFirst}, {tracesSecond}, {tracesThird}},
+		RetryInterval:    time.Minute,
+		HealthCheck: &HealthCheckConfig{
+			Extension:     extensionID,
+			CheckInterval: 10 * time.Millisecond,
+			FailbackDelay: 200 * time.Millisecond,
+		},
+	}
+
+	router := connector.NewTracesRouter(map[pipeline.ID]consumer.Traces{
+		tracesFirst:  &sinkFirst,
+		tracesSecond: &sinkSecond,
+		tracesThird:  &sinkThird,
+	})
+
+	conn, err := NewFactory().CreateTracesToTraces(context.Background(),
+		connectortest.NewNopSettings(metadata.Type), cfg, router.(consumer.Traces))
+	require.NoError(t, err)
+
+	ext := &statusExtension{statuses: map[pipeline.ID]componentstatus.Status{}}
+	host := extensionsHost{extensions: map[component.ID]component.Component{extensionID: ext}}
+	require.NoError(t, conn.Start(context.Background(), host))
+	defer func() {
+		assert.NoError(t, conn.Shutdown(context.Background()))
+	}()
+
+	failoverConnector := conn.(*tracesFailover)
+
+	// The connector fails over as soon as the status of the pipeline is an error, without waiting for errors.
+	ext.setStatus(tracesFirst, componentstatus.StatusRecoverableError)
+	ext.setStatus(tracesSecond, componentstatus.StatusPermanentError)
+	require.Eventually(t, func() bool {
+		return failoverConnector.failover.TestGetCurrentConsumerIndex() == 2
+	}, 3*time.Second, 5*time.Millisecond)
+
+	require.NoError(t, conn.ConsumeTraces(context.Background(), sampleTrace()))
+	assert.Equal(t, 0, sinkFirst.SpanCount())
+	assert.Equal(t, 1, sinkThird.SpanCount())
+
+	// The connector fails back once the pipeline has been healthy for the failback delay.
+	ext.setStatus(tracesFirst, componentstatus.StatusOK)
+	recovered := time.Now()
+	require.Eventually(t, func() bool {
+		return failoverConnector.failover.TestGetCurrentConsumerIndex() == 0
+	}, 3*time.Second, 5*time.Millisecond)
+	assert.GreaterOrEqual(t, time.Since(recovered), cfg.HealthCheck.FailbackDelay)
+
+	require.NoError(t, conn.ConsumeTraces(context.Background(), sampleTrace()))
+	assert.Equal(t, 1, sinkFirst.SpanCount())
+}
+
+func TestFailoverHealthCheckQueueSaturation(t *testing.T) {
+	var sinkFirst, sinkSecond consumertest.TracesSink
+	tracesFirst := pipeline.NewIDWithName(pipeline.SignalTraces, "traces/first")
+	tracesSecond := pipeline.NewIDWithName(pipeline.SignalTraces, "traces/second")
+	extensionID := component.MustNewID("healthcheckv2")
+
+	cfg := &Config{
+		PipelinePriority: [][]pipeline.ID{{tracesFirst}, {tracesSecond}},
+		RetryInterval:    time.Minute,
+		HealthCheck: &HealthCheckConfig{
+			Extension:       extensionID,
+			CheckInterval:   10 * time.Millisecond,
+			QueueSaturation: 0.8,
+			FailbackDelay:   200 * time.Millisecond,
+		},
+	}
+
+	router := connector.NewTracesRouter(map[pipeline.ID]consumer.Traces{
+		tracesFirst:  &sinkFirst,
+		tracesSecond: &sinkSecond,
+	})
+
+	conn, err := NewFactory().CreateTracesToTraces(context.Background(),
+		connectortest.NewNopSettings(metadata.Type), cfg, router.(consumer.Traces))
+	require.NoError(t, err)
+
+	ext := &queueExtension{usages: map[pipeline.ID]queueUsage{}}
+	ext.setUsage(tracesFirst, 50, 100)
+	host := extensionsHost{extensions: map[component.ID]component.Component{extensionID: ext}}
+	require.NoError(t, conn.Start(context.Background(), host))
+	defer func() {
+		assert.NoError(t, conn.Shutdown(context.Background()))
+	}()
+
+	failoverConnector := conn.(*tracesFailover)
+
+	require.NoError(t, conn.ConsumeTraces(context.Background(), sampleTrace()))
+	assert.Equal(t, 1, sinkFirst.SpanCount())
+	assert.Equal(t, 0, failoverConnector.failover.TestGetCurrentConsumerIndex())
+
+	// The connector fails over as soon as the queue reaches the saturation, before it is full and rejects data.
+	ext.setUsage(tracesFirst, 80, 100)
+	require.Eventually(t, func() bool {
+		return failoverConnector.failover.TestGetCurrentConsumerIndex() == 1
+	}, 3*time.Second, 5*time.Millisecond)
+
+	require.NoError(t, conn.ConsumeTraces(context.Background(), sampleTrace()))
+	assert.Equal(t, 1, sinkFirst.SpanCount())
+	assert.Equal(t, 1, sinkSecond.SpanCount())
+
+	// The connector fails back once the queue has been drained for the failback delay.
+	ext.setUsage(tracesFirst, 10, 100)
+	drained := time.Now()
+	require.Eventually(t, func() bool {
+		return failoverConnector.failover.TestGetCurrentConsumerIndex() == 0
+	}, 3*time.Second, 5*time.Millisecond)
+	assert.GreaterOrEqual(t, time.Since(drained), cfg.HealthCheck.FailbackDelay)
+}
+
+func TestFailoverHealthCheckConsumerErrors(t *testing.T) {
+	var sinkSecond consumertest.TracesSink
+	tracesFirst := pipeline.NewIDWithName(pipeline.SignalTraces, "traces/first")
+	tracesSecond := pipeline.NewIDWithName(pipeline.SignalTraces, "traces/second")
+	extensionID := component.MustNewID("healthcheckv2")
+
+	cfg := &Config{
+		PipelinePriority: [][]pipeline.ID{{tracesFirst}, {tracesSecond}},
+		RetryInterval:    time.Minute,
+		HealthCheck: &HealthCheckConfig{
+			Extension:     extensionID,
+			CheckInterval: time.Hour,
+			FailbackDelay: time.Minute,
+		},
+	}
+
+	router := connector.NewTracesRouter(map[pipeline.ID]consumer.Traces{
+		tracesFirst:  consumertest.NewErr(errTracesConsumer),
+		tracesSecond: &sinkSecond,
+	})
+
+	conn, err := NewFactory().CreateTracesToTraces(context.Background(),
+		connectortest.NewNopSettings(metadata.Type), cfg, router.(consumer.Traces))
+	require.NoError(t, err)
+
+	ext := &statusExtension{statuses: map[pipeline.ID]componentstatus.Status{
+		tracesFirst:  componentstatus.StatusOK,
+		tracesSecond: componentstatus.StatusOK,
+	}}
+	host := extensionsHost{extensions: map[component.ID]component.Component{extensionID: ext}}
+	require.NoError(t, conn.Start(context.Background(), host))
+	defer func() {
+		assert.NoError(t, conn.Shutdown(context.Background()))
+	}()
+
+	failoverConnector := conn.(*tracesFailover)
+
+	// The connector still fails over on the error of the request, even though the status of the pipeline is OK.
+	require.NoError(t, conn.ConsumeTraces(context.Background(), sampleTrace()))
+	assert.Equal(t, 1, sinkSecond.SpanCount())
+	assert.Equal(t, 1, failoverConnector.failover.TestGetCurrentConsumerIndex())
+
+	// The level which returned the error is only failed back to once healthy for the failback delay.
+	now := time.Now()
+	failoverConnector.failover.checkHealth(now)
+	assert.Equal(t, 1, failoverConnector.failover.TestGetCurrentConsumerIndex())
+	failoverConnector.failover.checkHealth(now.Add(cfg.HealthCheck.FailbackDelay))
+	assert.Equal(t, 0, failoverConnector.failover.TestGetCurrentConsumerIndex())
+}
+
+func TestFailoverHealthCheckExtensionErrors(t *testing.T) {
+	tracesFirst := pipeline.NewIDWithName(pipeline.SignalTraces, "traces/first")
+	router := connector.NewTracesRouter(map[pipeline.ID]consumer.Traces{
+		tracesFirst: consumertest.NewNop(),
+	})
+
+	for _, tc := range []struct {
+		name            string
+		host            component.Host
+		queueSaturation float64
+		expected        string
+	}{
+		{
+			name:     "missing",
+			host:     componenttest.NewNopHost(),
+			expected: "health check extension 'healthcheckv2' not found",
+		},
+		{
+			name: "not a status provider",
+			host: extensionsHost{extensions: map[component.ID]component.Component{
+				component.MustNewID("healthcheckv2"): &nopExtension{},
+			}},
+			expected: "extension 'healthcheckv2' doesn't provide the status of the pipelines",
+		},
+		{
+			name: "not a queue usage provider",
+			host: extensionsHost{extensions: map[component.ID]component.Component{
+				component.MustNewID("healthcheckv2"): &statusExtension{statuses: map[pipeline.ID]componentstatus.Status{}},
+			}},
+			queueSaturation: 0.8,
+			expected:        "extension 'healthcheckv2' doesn't provide the queue usage of the pipelines",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &Config{
+				PipelinePriority: [][]pipeline.ID{{tracesFirst}},
+				RetryInterval:    time.Minute,
+				HealthCheck: &HealthCheckConfig{
+					Extension:       component.MustNewID("healthcheckv2"),
+					QueueSaturation: tc.queueSaturation,
+				},
+			}
+			conn, err := NewFactory().CreateTracesToTraces(context.Background(),
+				connectortest.NewNopSettings(metadata.Type), cfg, router.(consumer.Traces))
+			require.NoError(t, err)
+			assert.EqualError(t, conn.Start(context.Background(), tc.host), tc.expected)
+			assert.NoError(t, conn.Shutdown(context.Background()))
+		})
+	}
+}
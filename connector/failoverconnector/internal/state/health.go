@@ -0,0 +1,123 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package state // import "github.com/open-telemetry/opentelemetry-collector-contrib/connector/failoverconnector/internal/state"
+
+import (
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component/componentstatus"
+	"go.opentelemetry.io/collector/pipeline"
+)
+
+// StatusFunc returns the status of a pipeline, and whether the status of the pipeline is known.
+type StatusFunc func(pipeline.ID) (componentstatus.Status, bool)
+
+// QueueFunc returns the size and the capacity of the most used sending queue of the exporters of a pipeline, and
+// whether the queue usage of the pipeline is known.
+type QueueFunc func(pipeline.ID) (size, capacity int64, ok bool)
+
+// HealthMonitor tracks the health of the priority levels from the status of their pipelines, and from the usage of
+// their sending queues if enabled. A level is unhealthy as soon as one of its pipelines reports an error status, or
+// as soon as the size of one of its queues reaches the saturation ratio of its capacity.
+type HealthMonitor struct {
+	levels          [][]pipeline.ID
+	status          StatusFunc
+	queue           QueueFunc
+	queueSaturation float64
+	failbackDelay   time.Duration
+
+	lock sync.RWMutex
+	// healthySince is the time since which each level is healthy, the zero time for the unhealthy levels
+	healthySince []time.Time
+}
+
+// NewHealthMonitor creates a HealthMonitor, status and queue being nil when the health of the levels doesn't depend
+// on the status of their pipelines or on the usage of their sending queues
+func NewHealthMonitor(levels [][]pipeline.ID, status StatusFunc, queue QueueFunc, queueSaturation float64, failbackDelay time.Duration) *HealthMonitor {
+	return &HealthMonitor{
+		levels:          levels,
+		status:          status,
+		queue:           queue,
+		queueSaturation: queueSaturation,
+		failbackDelay:   failbackDelay,
+		healthySince:    make([]time.Time, len(levels)),
+	}
+}
+
+// Update refreshes the health of the levels from the status of their pipelines and the usage of their queues
+func (h *HealthMonitor) Update(now time.Time) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	for idx, pipelines := range h.levels {
+		if !h.levelHealthy(pipelines) {
+			h.healthySince[idx] = time.Time{}
+			continue
+		}
+		if h.healthySince[idx].IsZero() {
+			h.healthySince[idx] = now
+		}
+	}
+}
+
+func (h *HealthMonitor) levelHealthy(pipelines []pipeline.ID) bool {
+	for _, id := range pipelines {
+		if !h.statusHealthy(id) || h.queueSaturated(id) {
+			return false
+		}
+	}
+	return true
+}
+
+func (h *HealthMonitor) statusHealthy(id pipeline.ID) bool {
+	if h.status == nil {
+		return true
+	}
+	st, ok := h.status(id)
+	if !ok {
+		return true
+	}
+	switch st {
+	case componentstatus.StatusRecoverableError, componentstatus.StatusPermanentError, componentstatus.StatusFatalError:
+		return false
+	}
+	return true
+}
+
+// queueSaturated returns whether the size of the most used sending queue of the pipeline reached the saturation
+// ratio of its capacity, so that the level is failed over before the queue is full and rejects data
+func (h *HealthMonitor) queueSaturated(id pipeline.ID) bool {
+	if h.queue == nil {
+		return false
+	}
+	size, capacity, ok := h.queue(id)
+	if !ok || capacity <= 0 {
+		return false
+	}
+	return float64(size) >= h.queueSaturation*float64(capacity)
+}
+
+// MarkUnhealthy makes the level unhealthy until the next update, e.g. when one of its pipelines returned an error,
+// so that the connector doesn't fail back to it before it has been healthy for the failback delay
+func (h *HealthMonitor) MarkUnhealthy(idx int) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if idx < len(h.healthySince) {
+		h.healthySince[idx] = time.Time{}
+	}
+}
+
+// Healthy returns whether the level is healthy
+func (h *HealthMonitor) Healthy(idx int) bool {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	return idx < len(h.healthySince) && !h.healthySince[idx].IsZero()
+}
+
+// Stable returns whether the level has been healthy for at least the failback delay, so that it can be failed back to
+func (h *HealthMonitor) Stable(idx int, now time.Time) bool {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	return idx < len(h.healthySince) && !h.healthySince[idx].IsZero() && now.Sub(h.healthySince[idx]) >= h.failbackDelay
+}
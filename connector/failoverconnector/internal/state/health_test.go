@@ -0,0 +1,110 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package state
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/component/componentstatus"
+	"go.opentelemetry.io/collector/pipeline"
+)
+
+func TestHealthMonitor(t *testing.T) {
+	first := pipeline.NewIDWithName(pipeline.SignalTraces, "first")
+	alsoFirst := pipeline.NewIDWithName(pipeline.SignalTraces, "also_first")
+	second := pipeline.NewIDWithName(pipeline.SignalTraces, "second")
+	statuses := map[pipeline.ID]componentstatus.Status{
+		first:     componentstatus.StatusOK,
+		alsoFirst: componentstatus.StatusOK,
+	}
+	status := func(id pipeline.ID) (componentstatus.Status, bool) {
+		st, ok := statuses[id]
+		return st, ok
+	}
+	h := NewHealthMonitor([][]pipeline.ID{{first, alsoFirst}, {second}}, status, nil, 0, time.Minute)
+	now := time.Now()
+
+	assert.False(t, h.Healthy(0), "the health is unknown until updated")
+	h.Update(now)
+	assert.True(t, h.Healthy(0))
+	assert.True(t, h.Healthy(1), "the pipelines without status are healthy")
+	assert.False(t, h.Healthy(2))
+	assert.False(t, h.Stable(0, now))
+
+	// A level is unhealthy as soon as one of its pipelines reports an error.
+	statuses[alsoFirst] = componentstatus.StatusRecoverableError
+	h.Update(now.Add(time.Minute))
+	assert.False(t, h.Healthy(0))
+	assert.False(t, h.Stable(0, now.Add(time.Minute)))
+	assert.True(t, h.Stable(1, now.Add(time.Minute)))
+
+	// A recovered level is stable once healthy for the failback delay.
+	statuses[alsoFirst] = componentstatus.StatusOK
+	h.Update(now.Add(2 * time.Minute))
+	assert.True(t, h.Healthy(0))
+	assert.False(t, h.Stable(0, now.Add(2*time.Minute+30*time.Second)))
+	h.Update(now.Add(3 * time.Minute))
+	assert.True(t, h.Stable(0, now.Add(3*time.Minute)))
+
+	for _, st := range []componentstatus.Status{componentstatus.StatusPermanentError, componentstatus.StatusFatalError} {
+		statuses[second] = st
+		h.Update(now.Add(4 * time.Minute))
+		assert.False(t, h.Healthy(1))
+	}
+}
+
+func TestHealthMonitorQueueSaturation(t *testing.T) {
+	first := pipeline.NewIDWithName(pipeline.SignalTraces, "first")
+	alsoFirst := pipeline.NewIDWithName(pipeline.SignalTraces, "also_first")
+	second := pipeline.NewIDWithName(pipeline.SignalTraces, "second")
+	type usage struct{ size, capacity int64 }
+	usages := map[pipeline.ID]usage{
+		first:     {size: 10, capacity: 100},
+		alsoFirst: {size: 0, capacity: 0},
+	}
+	queue := func(id pipeline.ID) (int64, int64, bool) {
+		u, ok := usages[id]
+		return u.size, u.capacity, ok
+	}
+	h := NewHealthMonitor([][]pipeline.ID{{first, alsoFirst}, {second}}, nil, queue, 0.8, time.Minute)
+	now := time.Now()
+
+	h.Update(now)
+	assert.True(t, h.Healthy(0), "the queues without capacity are not saturated")
+	assert.True(t, h.Healthy(1), "the pipelines without queue usage are healthy")
+
+	// A level is unhealthy as soon as one of its queues reaches the saturation ratio, before it is full.
+	usages[first] = usage{size: 80, capacity: 100}
+	h.Update(now.Add(time.Minute))
+	assert.False(t, h.Healthy(0))
+	assert.True(t, h.Healthy(1))
+
+	// A level is healthy again once its queues are drained, and stable after the failback delay.
+	usages[first] = usage{size: 79, capacity: 100}
+	h.Update(now.Add(2 * time.Minute))
+	assert.True(t, h.Healthy(0))
+	assert.False(t, h.Stable(0, now.Add(2*time.Minute)))
+	assert.True(t, h.Stable(0, now.Add(3*time.Minute)))
+}
+
+func TestHealthMonitorMarkUnhealthy(t *testing.T) {
+	first := pipeline.NewIDWithName(pipeline.SignalTraces, "first")
+	status := func(pipeline.ID) (componentstatus.Status, bool) {
+		return componentstatus.StatusOK, true
+	}
+	h := NewHealthMonitor([][]pipeline.ID{{first}}, status, nil, 0, time.Minute)
+	now := time.Now()
+	h.Update(now)
+	assert.True(t, h.Stable(0, now.Add(time.Minute)))
+
+	// A level which returned an error is unhealthy until the next update, and stable after the failback delay.
+	h.MarkUnhealthy(0)
+	assert.False(t, h.Healthy(0))
+	h.Update(now.Add(2 * time.Minute))
+	assert.True(t, h.Healthy(0))
+	assert.False(t, h.Stable(0, now.Add(2*time.Minute)))
+	assert.True(t, h.Stable(0, now.Add(3*time.Minute)))
+}
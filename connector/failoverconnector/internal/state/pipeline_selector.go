@@ -36,6 +36,18 @@ func (p *PipelineSelector) NextStableLevel() {
 	p.currentPipeline++
 }
 
+// FailoverTo switches from the current level to the given level of lower priority, and enables the retry of the levels above it
+func (p *PipelineSelector) FailoverTo(idx int) {
+	p.lock.Lock()
+	if idx <= p.currentPipeline {
+		p.lock.Unlock()
+		return
+	}
+	p.currentPipeline = idx
+	p.lock.Unlock()
+	p.TryEnableRetry()
+}
+
 // TryEnableRetry checks if a retry is already in effect and if not starts the retry goroutine
 func (p *PipelineSelector) TryEnableRetry() {
 	select {
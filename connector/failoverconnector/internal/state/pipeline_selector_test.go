@@ -73,3 +73,33 @@ func TestCurrentPipelineWithReset(t *testing.T) {
 		return idx == 0
 	}, 3*time.Second, 5*time.Millisecond)
 }
+
+func TestFailoverTo(t *testing.T) {
+	done := make(chan struct{})
+	retryChan := make(chan struct{}, 1)
+	constants := PSConstants{
+		RetryInterval: 50 * time.Millisecond,
+	}
+	pS := NewPipelineSelector(retryChan, done, constants)
+
+	defer func() {
+		close(done)
+	}()
+
+	pS.FailoverTo(2)
+	require.Equal(t, 2, pS.CurrentPipeline())
+
+	// Only the levels of lower priority can be failed over to.
+	pS.FailoverTo(1)
+	require.Equal(t, 2, pS.CurrentPipeline())
+
+	// The retry of the levels above is enabled.
+	require.Eventually(t, func() bool {
+		select {
+		case <-retryChan:
+			return true
+		default:
+			return false
+		}
+	}, 3*time.Second, 5*time.Millisecond)
+}
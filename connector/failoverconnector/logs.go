@@ -59,6 +59,9 @@ func (f *logsRouter) consumeByHealthyPipeline(ctx context.Context, ld plog.Logs)
 func (f *logsRouter) sampleRetryConsumers(ctx context.Context, ld plog.Logs) bool {
 	stableIndex := f.pS.CurrentPipeline()
 	for i := 0; i < stableIndex; i++ {
+		if !f.retryable(i) {
+			continue
+		}
 		consumer := f.getConsumerAtIndex(i)
 		err := consumer.ConsumeLogs(ctx, ld)
 		if err == nil {
@@ -70,7 +73,6 @@ func (f *logsRouter) sampleRetryConsumers(ctx context.Context, ld plog.Logs) boo
 }
 
 type logsFailover struct {
-	component.StartFunc
 	component.ShutdownFunc
 
 	config   *Config
@@ -87,6 +89,11 @@ func (f *logsFailover) ConsumeLogs(ctx context.Context, ld plog.Logs) error {
 	return f.failover.Consume(ctx, ld)
 }
 
+// Start starts checking the health of the pipelines, if enabled
+func (f *logsFailover) Start(_ context.Context, host component.Host) error {
+	return f.failover.Start(host)
+}
+
 func (f *logsFailover) Shutdown(_ context.Context) error {
 	if f.failover != nil {
 		f.failover.Shutdown()
@@ -59,6 +59,9 @@ func (f *metricsRouter) consumeByHealthyPipeline(ctx context.Context, md pmetric
 func (f *metricsRouter) sampleRetryConsumers(ctx context.Context, md pmetric.Metrics) bool {
 	stableIndex := f.pS.CurrentPipeline()
 	for i := 0; i < stableIndex; i++ {
+		if !f.retryable(i) {
+			continue
+		}
 		consumer := f.getConsumerAtIndex(i)
 		err := consumer.ConsumeMetrics(ctx, md)
 		if err == nil {
@@ -70,7 +73,6 @@ func (f *metricsRouter) sampleRetryConsumers(ctx context.Context, md pmetric.Met
 }
 
 type metricsFailover struct {
-	component.StartFunc
 	component.ShutdownFunc
 
 	config   *Config
@@ -87,6 +89,11 @@ func (f *metricsFailover) ConsumeMetrics(ctx context.Context, md pmetric.Metrics
 	return f.failover.Consume(ctx, md)
 }
 
+// Start starts checking the health of the pipelines, if enabled
+func (f *metricsFailover) Start(_ context.Context, host component.Host) error {
+	return f.failover.Start(host)
+}
+
 func (f *metricsFailover) Shutdown(_ context.Context) error {
 	if f.failover != nil {
 		f.failover.Shutdown()
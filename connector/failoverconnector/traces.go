@@ -60,6 +60,9 @@ func (f *tracesRouter) consumeByHealthyPipeline(ctx context.Context, td ptrace.T
 func (f *tracesRouter) sampleRetryConsumers(ctx context.Context, td ptrace.Traces) bool {
 	stableIndex := f.pS.CurrentPipeline()
 	for i := 0; i < stableIndex; i++ {
+		if !f.retryable(i) {
+			continue
+		}
 		consumer := f.getConsumerAtIndex(i)
 		err := consumer.ConsumeTraces(ctx, td)
 		if err == nil {
@@ -71,7 +74,6 @@ func (f *tracesRouter) sampleRetryConsumers(ctx context.Context, td ptrace.Trace
 }
 
 type tracesFailover struct {
-	component.StartFunc
 	component.ShutdownFunc
 
 	config   *Config
@@ -88,6 +90,11 @@ func (f *tracesFailover) ConsumeTraces(ctx context.Context, td ptrace.Traces) er
 	return f.failover.Consume(ctx, td)
 }
 
+// Start starts checking the health of the pipelines, if enabled
+func (f *tracesFailover) Start(_ context.Context, host component.Host) error {
+	return f.failover.Start(host)
+}
+
 func (f *tracesFailover) Shutdown(_ context.Context) error {
 	if f.failover != nil {
 		f.failover.Shutdown()
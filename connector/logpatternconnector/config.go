@@ -0,0 +1,69 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package logpatternconnector // import "github.com/open-telemetry/opentelemetry-collector-contrib/connector/logpatternconnector"
+
+import (
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+const (
+	defaultDepth                 = 4
+	defaultSimilarityThreshold   = 0.4
+	defaultMaxChildren           = 100
+	defaultMaxPatterns           = 1000
+	defaultStateSnapshotInterval = time.Minute
+)
+
+// Config defines the configuration options for logpatternconnector.
+type Config struct {
+	// Depth is the depth of the prefix tree the patterns are searched in. The pattern of a log is searched among
+	// the patterns of the logs with the same number of tokens, and the same first depth-3 tokens.
+	Depth int `mapstructure:"depth"`
+
+	// SimilarityThreshold is the minimum ratio of tokens of a log body equal to the tokens of a pattern for the
+	// log to match the pattern. Otherwise, a new pattern is created.
+	SimilarityThreshold float64 `mapstructure:"similarity_threshold"`
+
+	// MaxChildren is the maximum number of children of a node of the prefix tree. Once reached, the logs with
+	// new tokens are searched among the patterns with a wildcard token.
+	MaxChildren int `mapstructure:"max_children"`
+
+	// MaxPatterns is the maximum number of patterns. Once reached, the least recently matched pattern is
+	// forgotten to make room for a new one.
+	MaxPatterns int `mapstructure:"max_patterns"`
+
+	// Storage is the ID of the storage extension the learned patterns are snapshotted to, and restored from on
+	// start, so that the patterns and their IDs survive restarts.
+	// Optional, the patterns are kept in memory only by default.
+	Storage *component.ID `mapstructure:"storage"`
+
+	// StateSnapshotInterval is the minimum time period between two snapshots of the patterns. The patterns are
+	// snapshotted when logs are received, once this period elapsed since the last snapshot, and on shutdown.
+	StateSnapshotInterval time.Duration `mapstructure:"state_snapshot_interval"`
+
+	// prevent unkeyed literal initialization
+	_ struct{}
+}
+
+func (c *Config) Validate() error {
+	if c.Depth < 3 {
+		return fmt.Errorf("invalid depth: %d, the depth should be at least 3", c.Depth)
+	}
+	if c.SimilarityThreshold <= 0 || c.SimilarityThreshold > 1 {
+		return fmt.Errorf("invalid similarity_threshold: %v, the threshold should be greater than 0 and at most 1", c.SimilarityThreshold)
+	}
+	if c.MaxChildren < 2 {
+		return fmt.Errorf("invalid max_children: %d, the number of children should be at least 2", c.MaxChildren)
+	}
+	if c.MaxPatterns <= 0 {
+		return fmt.Errorf("invalid max_patterns: %d, the number of patterns should be positive", c.MaxPatterns)
+	}
+	if c.StateSnapshotInterval < 0 {
+		return fmt.Errorf("invalid state_snapshot_interval: %v, the duration should be positive", c.StateSnapshotInterval)
+	}
+	return nil
+}
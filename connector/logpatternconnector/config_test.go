@@ -0,0 +1,88 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package logpatternconnector
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap/confmaptest"
+	"go.opentelemetry.io/collector/confmap/xconfmap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/logpatternconnector/internal/metadata"
+)
+
+func TestLoadConfig(t *testing.T) {
+	t.Parallel()
+
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+	require.NoError(t, err)
+
+	fileStorageID := component.MustNewID("file_storage")
+	tests := []struct {
+		id           component.ID
+		expected     component.Config
+		errorMessage string
+	}{
+		{
+			id:       component.NewID(metadata.Type),
+			expected: createDefaultConfig(),
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "full"),
+			expected: &Config{
+				Depth:                 5,
+				SimilarityThreshold:   0.6,
+				MaxChildren:           50,
+				MaxPatterns:           200,
+				Storage:               &fileStorageID,
+				StateSnapshotInterval: 5 * time.Minute,
+			},
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "invalid_depth"),
+			errorMessage: "invalid depth: 2, the depth should be at least 3",
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "invalid_similarity_threshold"),
+			errorMessage: "invalid similarity_threshold: 1.5, the threshold should be greater than 0 and at most 1",
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "invalid_max_children"),
+			errorMessage: "invalid max_children: 1, the number of children should be at least 2",
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "invalid_max_patterns"),
+			errorMessage: "invalid max_patterns: 0, the number of patterns should be positive",
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "invalid_state_snapshot_interval"),
+			errorMessage: "invalid state_snapshot_interval: -1m0s, the duration should be positive",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id.String(), func(t *testing.T) {
+			factory := NewFactory()
+			cfg := factory.CreateDefaultConfig()
+
+			sub, err := cm.Sub(tt.id.String())
+			require.NoError(t, err)
+			err = sub.Unmarshal(cfg)
+
+			if tt.expected == nil {
+				err = errors.Join(err, xconfmap.Validate(cfg))
+				assert.ErrorContains(t, err, tt.errorMessage)
+				return
+			}
+			assert.NoError(t, xconfmap.Validate(cfg))
+			assert.Equal(t, tt.expected, cfg)
+		})
+	}
+}
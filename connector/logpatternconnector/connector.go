@@ -0,0 +1,130 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package logpatternconnector // import "github.com/open-telemetry/opentelemetry-collector-contrib/connector/logpatternconnector"
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/logpatternconnector/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/sharedcomponent"
+)
+
+const (
+	serviceNameKey     = "service.name"
+	patternIDKey       = "log.pattern.id"
+	patternTemplateKey = "log.pattern.template"
+
+	metricNameRecordCount = "log.pattern.record.count"
+)
+
+// logsConnector sets the ID of their pattern on the logs, and passes them to a logs pipeline.
+type logsConnector struct {
+	*sharedcomponent.SharedComponent
+	miner        *miner
+	logsConsumer consumer.Logs
+}
+
+func (c *logsConnector) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{MutatesData: true}
+}
+
+func (c *logsConnector) ConsumeLogs(ctx context.Context, ld plog.Logs) error {
+	c.miner.matchLogs(ctx, ld, true, func(_ plog.ResourceLogs, lr plog.LogRecord, p pattern) {
+		lr.Attributes().PutStr(patternIDKey, p.id)
+	})
+	return c.logsConsumer.ConsumeLogs(ctx, ld)
+}
+
+// metricsConnector counts the logs per service and pattern, and emits the counts onto a metrics pipeline.
+type metricsConnector struct {
+	*sharedcomponent.SharedComponent
+	miner           *miner
+	metricsConsumer consumer.Metrics
+}
+
+type patternCount struct {
+	pattern
+	count int64
+}
+
+func (c *metricsConnector) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{MutatesData: false}
+}
+
+func (c *metricsConnector) ConsumeLogs(ctx context.Context, ld plog.Logs) error {
+	counts := make(map[string]map[string]*patternCount)
+	c.miner.matchLogs(ctx, ld, false, func(rl plog.ResourceLogs, _ plog.LogRecord, p pattern) {
+		service := ""
+		if v, ok := rl.Resource().Attributes().Get(serviceNameKey); ok {
+			service = v.AsString()
+		}
+		patterns, ok := counts[service]
+		if !ok {
+			patterns = make(map[string]*patternCount)
+			counts[service] = patterns
+		}
+		pc, ok := patterns[p.id]
+		if !ok {
+			pc = &patternCount{}
+			patterns[p.id] = pc
+		}
+		// The template of the pattern may be generalized by the later logs, the latest one is kept.
+		pc.pattern = p
+		pc.count++
+	})
+	if len(counts) == 0 {
+		return nil
+	}
+	return c.metricsConsumer.ConsumeMetrics(ctx, buildMetrics(counts, time.Now()))
+}
+
+func buildMetrics(counts map[string]map[string]*patternCount, now time.Time) pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	timestamp := pcommon.NewTimestampFromTime(now)
+
+	services := make([]string, 0, len(counts))
+	for service := range counts {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+
+	for _, service := range services {
+		rm := md.ResourceMetrics().AppendEmpty()
+		if service != "" {
+			rm.Resource().Attributes().PutStr(serviceNameKey, service)
+		}
+		sm := rm.ScopeMetrics().AppendEmpty()
+		sm.Scope().SetName(metadata.ScopeName)
+
+		m := sm.Metrics().AppendEmpty()
+		m.SetName(metricNameRecordCount)
+		m.SetUnit("{record}")
+		m.SetDescription("The number of log records matching each log pattern.")
+		sum := m.SetEmptySum()
+		sum.SetIsMonotonic(true)
+		sum.SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+
+		ids := make([]string, 0, len(counts[service]))
+		for id := range counts[service] {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			pc := counts[service][id]
+			dp := sum.DataPoints().AppendEmpty()
+			dp.Attributes().PutStr(patternIDKey, pc.id)
+			dp.Attributes().PutStr(patternTemplateKey, pc.template)
+			dp.SetTimestamp(timestamp)
+			dp.SetIntValue(pc.count)
+		}
+	}
+	return md
+}
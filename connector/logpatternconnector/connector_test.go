@@ -0,0 +1,204 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package logpatternconnector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/connector"
+	"go.opentelemetry.io/collector/connector/connectortest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/logpatternconnector/internal/metadata"
+)
+
+func buildLogs(bodies map[string][]string) plog.Logs {
+	ld := plog.NewLogs()
+	for _, service := range []string{"frontend", "checkout", ""} {
+		if _, ok := bodies[service]; !ok {
+			continue
+		}
+		rl := ld.ResourceLogs().AppendEmpty()
+		if service != "" {
+			rl.Resource().Attributes().PutStr(serviceNameKey, service)
+		}
+		logs := rl.ScopeLogs().AppendEmpty().LogRecords()
+		for _, body := range bodies[service] {
+			logs.AppendEmpty().Body().SetStr(body)
+		}
+	}
+	return ld
+}
+
+func createConnectors(t *testing.T, cfg *Config) (connector.Logs, *consumertest.LogsSink, connector.Logs, *consumertest.MetricsSink) {
+	factory := NewFactory()
+	set := connectortest.NewNopSettings(metadata.Type)
+
+	logsSink := new(consumertest.LogsSink)
+	logsConnector, err := factory.CreateLogsToLogs(context.Background(), set, cfg, logsSink)
+	require.NoError(t, err)
+	metricsSink := new(consumertest.MetricsSink)
+	metricsConnector, err := factory.CreateLogsToMetrics(context.Background(), set, cfg, metricsSink)
+	require.NoError(t, err)
+
+	host := componenttest.NewNopHost()
+	require.NoError(t, logsConnector.Start(context.Background(), host))
+	require.NoError(t, metricsConnector.Start(context.Background(), host))
+	t.Cleanup(func() {
+		require.NoError(t, logsConnector.Shutdown(context.Background()))
+		require.NoError(t, metricsConnector.Shutdown(context.Background()))
+	})
+	return logsConnector, logsSink, metricsConnector, metricsSink
+}
+
+func TestLogsConnector(t *testing.T) {
+	logsConnector, logsSink, _, _ := createConnectors(t, createDefaultConfig().(*Config))
+	assert.True(t, logsConnector.Capabilities().MutatesData)
+
+	require.NoError(t, logsConnector.ConsumeLogs(context.Background(), buildLogs(map[string][]string{
+		"frontend": {
+			"user alice logged in from 10.0.0.1",
+			"user bob logged in from 10.0.0.2",
+			"connection reset by peer",
+		},
+	})))
+
+	require.Len(t, logsSink.AllLogs(), 1)
+	logs := logsSink.AllLogs()[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+	require.Equal(t, 3, logs.Len())
+	ids := make([]string, logs.Len())
+	for i := 0; i < logs.Len(); i++ {
+		id, ok := logs.At(i).Attributes().Get(patternIDKey)
+		require.True(t, ok)
+		ids[i] = id.Str()
+	}
+	assert.Equal(t, ids[0], ids[1])
+	assert.NotEqual(t, ids[0], ids[2])
+}
+
+func TestMetricsConnector(t *testing.T) {
+	_, _, metricsConnector, metricsSink := createConnectors(t, createDefaultConfig().(*Config))
+	assert.False(t, metricsConnector.Capabilities().MutatesData)
+
+	require.NoError(t, metricsConnector.ConsumeLogs(context.Background(), buildLogs(map[string][]string{
+		"frontend": {
+			"user alice logged in from 10.0.0.1",
+			"user bob logged in from 10.0.0.2",
+			"connection reset by peer",
+		},
+		"checkout": {
+			"connection reset by peer",
+		},
+		"": {
+			"connection reset by peer",
+		},
+	})))
+
+	require.Len(t, metricsSink.AllMetrics(), 1)
+	md := metricsSink.AllMetrics()[0]
+	counts := map[string]map[string]int64{}
+	for i := 0; i < md.ResourceMetrics().Len(); i++ {
+		rm := md.ResourceMetrics().At(i)
+		service := ""
+		if v, ok := rm.Resource().Attributes().Get(serviceNameKey); ok {
+			service = v.Str()
+		}
+		m := rm.ScopeMetrics().At(0).Metrics().At(0)
+		assert.Equal(t, metricNameRecordCount, m.Name())
+		assert.Equal(t, pmetric.AggregationTemporalityDelta, m.Sum().AggregationTemporality())
+		assert.True(t, m.Sum().IsMonotonic())
+		counts[service] = map[string]int64{}
+		for j := 0; j < m.Sum().DataPoints().Len(); j++ {
+			dp := m.Sum().DataPoints().At(j)
+			_, ok := dp.Attributes().Get(patternIDKey)
+			assert.True(t, ok)
+			template, _ := dp.Attributes().Get(patternTemplateKey)
+			counts[service][template.Str()] = dp.IntValue()
+		}
+	}
+	assert.Equal(t, map[string]map[string]int64{
+		"":         {"connection reset by peer": 1},
+		"checkout": {"connection reset by peer": 1},
+		"frontend": {
+			"user <*> logged in from <*>": 2,
+			"connection reset by peer":    1,
+		},
+	}, counts)
+}
+
+func TestMetricsConnectorNoLogs(t *testing.T) {
+	_, _, metricsConnector, metricsSink := createConnectors(t, createDefaultConfig().(*Config))
+	require.NoError(t, metricsConnector.ConsumeLogs(context.Background(), plog.NewLogs()))
+	assert.Empty(t, metricsSink.AllMetrics())
+}
+
+func TestConnectorsSharePatterns(t *testing.T) {
+	logsConnector, logsSink, metricsConnector, metricsSink := createConnectors(t, createDefaultConfig().(*Config))
+	ctx := context.Background()
+
+	require.NoError(t, logsConnector.ConsumeLogs(ctx, buildLogs(map[string][]string{
+		"frontend": {"job 1 finished in 3s", "job 2 finished in 5s"},
+	})))
+	require.NoError(t, metricsConnector.ConsumeLogs(ctx, buildLogs(map[string][]string{
+		"frontend": {"job 3 finished in 8s"},
+	})))
+
+	id, _ := logsSink.AllLogs()[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Attributes().Get(patternIDKey)
+	dp := metricsSink.AllMetrics()[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Sum().DataPoints().At(0)
+	assert.Equal(t, map[string]any{
+		patternIDKey:       id.Str(),
+		patternTemplateKey: "job <*> finished in <*>",
+	}, dp.Attributes().AsRaw())
+}
+
+func TestConnectorsTrainOnce(t *testing.T) {
+	lc, logsSink, mc, metricsSink := createConnectors(t, createDefaultConfig().(*Config))
+	m := lc.(*logsConnector).miner
+	ctx := context.Background()
+
+	// Both connectors receive the same batch, the logs connector first: the metrics connector matches the logs
+	// against the patterns trained by the logs connector, without training them again.
+	ld := buildLogs(map[string][]string{"frontend": {"user alice logged in", "cache warmed up"}})
+	require.NoError(t, lc.ConsumeLogs(ctx, ld))
+	require.NoError(t, mc.ConsumeLogs(ctx, buildLogs(map[string][]string{"frontend": {"user bob logged in", "cache warmed up"}})))
+	assert.Equal(t, 2, m.drain.Len())
+
+	logs := logsSink.AllLogs()[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+	id, _ := logs.At(0).Attributes().Get(patternIDKey)
+	dps := metricsSink.AllMetrics()[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Sum().DataPoints()
+	templates := make(map[any]any)
+	for i := 0; i < dps.Len(); i++ {
+		attrs := dps.At(i).Attributes().AsRaw()
+		templates[attrs[patternIDKey]] = attrs[patternTemplateKey]
+		assert.Equal(t, int64(1), dps.At(i).IntValue())
+	}
+	assert.Len(t, templates, 2)
+	assert.Equal(t, "user alice logged in", templates[id.Str()], "the template isn't generalized by the metrics connector")
+
+	// The logs matching no pattern are trained by the metrics connector.
+	require.NoError(t, mc.ConsumeLogs(ctx, buildLogs(map[string][]string{"frontend": {"connection reset by peer"}})))
+	assert.Equal(t, 3, m.drain.Len())
+}
+
+func TestMaxPatterns(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.MaxPatterns = 1
+	lc, logsSink, _, _ := createConnectors(t, cfg)
+
+	require.NoError(t, lc.ConsumeLogs(context.Background(), buildLogs(map[string][]string{
+		"frontend": {"cache warmed up", "connection reset by peer", "cache warmed up"},
+	})))
+	assert.Equal(t, 1, lc.(*logsConnector).miner.drain.Len())
+	logs := logsSink.AllLogs()[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+	first, _ := logs.At(0).Attributes().Get(patternIDKey)
+	last, _ := logs.At(2).Attributes().Get(patternIDKey)
+	// The pattern is learned again with the same ID once evicted.
+	assert.Equal(t, first.Str(), last.Str())
+}
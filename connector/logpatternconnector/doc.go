@@ -0,0 +1,7 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate mdatagen metadata.yaml
+
+// Package logpatternconnector clusters the bodies of the logs into patterns, and counts the logs per pattern.
+package logpatternconnector // import "github.com/open-telemetry/opentelemetry-collector-contrib/connector/logpatternconnector"
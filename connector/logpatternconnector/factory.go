@@ -0,0 +1,77 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package logpatternconnector // import "github.com/open-telemetry/opentelemetry-collector-contrib/connector/logpatternconnector"
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/connector"
+	"go.opentelemetry.io/collector/consumer"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/logpatternconnector/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/sharedcomponent"
+)
+
+// miners are shared by the connectors created from the same configuration, so that the logs and metrics
+// pipelines see the same patterns.
+var miners = sharedcomponent.NewSharedComponents()
+
+// NewFactory returns a ConnectorFactory.
+func NewFactory() connector.Factory {
+	return connector.NewFactory(
+		metadata.Type,
+		createDefaultConfig,
+		connector.WithLogsToLogs(createLogsToLogs, metadata.LogsToLogsStability),
+		connector.WithLogsToMetrics(createLogsToMetrics, metadata.LogsToMetricsStability),
+	)
+}
+
+// createDefaultConfig creates the default configuration.
+func createDefaultConfig() component.Config {
+	return &Config{
+		Depth:                 defaultDepth,
+		SimilarityThreshold:   defaultSimilarityThreshold,
+		MaxChildren:           defaultMaxChildren,
+		MaxPatterns:           defaultMaxPatterns,
+		StateSnapshotInterval: defaultStateSnapshotInterval,
+	}
+}
+
+func getOrCreateMiner(set connector.Settings, cfg component.Config) *sharedcomponent.SharedComponent {
+	return miners.GetOrAdd(cfg, func() component.Component {
+		return newMiner(set, cfg.(*Config))
+	})
+}
+
+// createLogsToLogs creates a logs to logs connector based on provided config.
+func createLogsToLogs(
+	_ context.Context,
+	set connector.Settings,
+	cfg component.Config,
+	nextConsumer consumer.Logs,
+) (connector.Logs, error) {
+	m := getOrCreateMiner(set, cfg)
+	m.Unwrap().(*miner).addLogsConnector()
+	return &logsConnector{
+		SharedComponent: m,
+		miner:           m.Unwrap().(*miner),
+		logsConsumer:    nextConsumer,
+	}, nil
+}
+
+// createLogsToMetrics creates a logs to metrics connector based on provided config.
+func createLogsToMetrics(
+	_ context.Context,
+	set connector.Settings,
+	cfg component.Config,
+	nextConsumer consumer.Metrics,
+) (connector.Logs, error) {
+	m := getOrCreateMiner(set, cfg)
+	return &metricsConnector{
+		SharedComponent: m,
+		miner:           m.Unwrap().(*miner),
+		metricsConsumer: nextConsumer,
+	}, nil
+}
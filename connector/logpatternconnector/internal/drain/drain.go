@@ -0,0 +1,302 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package drain clusters log messages into templates online, following the Drain algorithm described in
+// "Drain: An Online Log Parsing Approach with Fixed Depth Tree" (He et al., ICWS 2017).
+package drain // import "github.com/open-telemetry/opentelemetry-collector-contrib/connector/logpatternconnector/internal/drain"
+
+import (
+	"container/list"
+	"encoding/hex"
+	"hash/fnv"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Wildcard is the token of the templates matching any token.
+const Wildcard = "<*>"
+
+// Config configures the clustering of the messages.
+type Config struct {
+	// Depth is the depth of the prefix tree, including the root node, the level of the token counts and the
+	// level of the clusters. The cluster of a message is searched among the messages sharing its first
+	// Depth-3 tokens.
+	Depth int
+	// SimilarityThreshold is the minimum ratio of tokens of a message matching a template for the message to
+	// belong to the cluster of the template.
+	SimilarityThreshold float64
+	// MaxChildren is the maximum number of children of a node of the prefix tree.
+	MaxChildren int
+	// MaxClusters is the maximum number of clusters, the least recently matched cluster is evicted to make
+	// room for a new one.
+	MaxClusters int
+}
+
+// Cluster is a group of messages sharing the same template.
+type Cluster struct {
+	// ID identifies the cluster, it is derived from the first message of the cluster, whose tokens containing
+	// digits are replaced with wildcards, so that it is stable as the template is generalized.
+	ID string
+	// Tokens are the tokens of the template.
+	Tokens []string
+	// Path is the keys of the nodes of the prefix tree leading to the cluster, fixed at its creation.
+	Path []string
+
+	leaf    *node
+	element *list.Element
+}
+
+// Template returns the template of the cluster.
+func (c *Cluster) Template() string {
+	return strings.Join(c.Tokens, " ")
+}
+
+type node struct {
+	children map[string]*node
+	clusters []*Cluster
+}
+
+func newNode() *node {
+	return &node{children: make(map[string]*node)}
+}
+
+func (n *node) child(key string) *node {
+	c, ok := n.children[key]
+	if !ok {
+		c = newNode()
+		n.children[key] = c
+	}
+	return c
+}
+
+// Drain clusters the messages. It isn't safe for concurrent use.
+type Drain struct {
+	config Config
+	root   *node
+	// clusters are ordered from the most to the least recently matched
+	clusters *list.List
+}
+
+// New creates an empty Drain.
+func New(config Config) *Drain {
+	return &Drain{
+		config:   config,
+		root:     newNode(),
+		clusters: list.New(),
+	}
+}
+
+// Train returns the cluster of the message, which is created if no cluster is similar enough to the message.
+// The template of the cluster is merged with the message, the tokens differing between them becoming
+// wildcards.
+func (d *Drain) Train(message string) *Cluster {
+	tokens := strings.Fields(message)
+	leaf, path := d.descend(tokens)
+
+	if c := d.bestMatch(leaf.clusters, tokens); c != nil {
+		for i, t := range c.Tokens {
+			if t != tokens[i] {
+				c.Tokens[i] = Wildcard
+			}
+		}
+		d.clusters.MoveToFront(c.element)
+		return c
+	}
+
+	c := &Cluster{
+		ID:     clusterID(tokens),
+		Tokens: tokens,
+		Path:   path,
+	}
+	d.add(leaf, c)
+	return c
+}
+
+// Match returns the cluster of the message without learning the message, or nil if no cluster is similar
+// enough to the message. The template of the cluster is left unchanged.
+func (d *Drain) Match(message string) *Cluster {
+	tokens := strings.Fields(message)
+	leaf := d.lookup(tokens)
+	if leaf == nil {
+		return nil
+	}
+	c := d.bestMatch(leaf.clusters, tokens)
+	if c != nil {
+		d.clusters.MoveToFront(c.element)
+	}
+	return c
+}
+
+// Len returns the number of clusters.
+func (d *Drain) Len() int {
+	return d.clusters.Len()
+}
+
+// Clusters returns the clusters, from the most to the least recently matched.
+func (d *Drain) Clusters() []*Cluster {
+	clusters := make([]*Cluster, 0, d.clusters.Len())
+	for e := d.clusters.Front(); e != nil; e = e.Next() {
+		clusters = append(clusters, e.Value.(*Cluster))
+	}
+	return clusters
+}
+
+// Restore adds the clusters, ordered from the most to the least recently matched, as returned by Clusters.
+func (d *Drain) Restore(clusters []*Cluster) {
+	for _, c := range slices.Backward(clusters) {
+		if len(c.Path) == 0 || c.Path[0] != strconv.Itoa(len(c.Tokens)) {
+			continue
+		}
+		leaf := d.root
+		for _, key := range c.Path {
+			leaf = leaf.child(key)
+		}
+		d.add(leaf, &Cluster{
+			ID:     c.ID,
+			Tokens: slices.Clone(c.Tokens),
+			Path:   slices.Clone(c.Path),
+		})
+	}
+}
+
+// descend returns the leaf node of the prefix tree the clusters of the message are searched in, and the path
+// leading to it. The nodes are created as needed.
+func (d *Drain) descend(tokens []string) (*node, []string) {
+	path := []string{strconv.Itoa(len(tokens))}
+	cur := d.root.child(path[0])
+	for i := 0; i < len(tokens) && i < d.config.Depth-3; i++ {
+		key := d.childKey(cur, tokens[i])
+		path = append(path, key)
+		cur = cur.child(key)
+	}
+	return cur, path
+}
+
+// lookup returns the leaf node of the prefix tree the clusters of the message are searched in, or nil if the
+// node doesn't exist. No node is created.
+func (d *Drain) lookup(tokens []string) *node {
+	cur := d.root.children[strconv.Itoa(len(tokens))]
+	for i := 0; cur != nil && i < len(tokens) && i < d.config.Depth-3; i++ {
+		cur = cur.children[d.childKey(cur, tokens[i])]
+	}
+	return cur
+}
+
+// childKey returns the key of the child of the node to descend to for the token. The tokens containing digits
+// are likely variables, they go to the wildcard child, as well as the new tokens once the node is full.
+func (d *Drain) childKey(n *node, token string) string {
+	if _, ok := n.children[token]; ok {
+		return token
+	}
+	if hasDigit(token) {
+		return Wildcard
+	}
+	if _, ok := n.children[Wildcard]; ok {
+		if len(n.children) < d.config.MaxChildren {
+			return token
+		}
+		return Wildcard
+	}
+	if len(n.children)+1 < d.config.MaxChildren {
+		return token
+	}
+	return Wildcard
+}
+
+// bestMatch returns the most similar cluster to the message, preferring the more general templates,
+// or nil if no cluster is similar enough.
+func (d *Drain) bestMatch(clusters []*Cluster, tokens []string) *Cluster {
+	var best *Cluster
+	bestSimilarity, bestWildcards := -1.0, -1
+	for _, c := range clusters {
+		if len(c.Tokens) != len(tokens) {
+			continue
+		}
+		similarity, wildcards := similarity(c.Tokens, tokens)
+		if similarity > bestSimilarity || similarity == bestSimilarity && wildcards > bestWildcards {
+			best, bestSimilarity, bestWildcards = c, similarity, wildcards
+		}
+	}
+	if best == nil || bestSimilarity < d.config.SimilarityThreshold {
+		return nil
+	}
+	return best
+}
+
+// add adds the cluster to the leaf before evicting, so that the leaf isn't pruned if it held the evicted cluster.
+func (d *Drain) add(leaf *node, c *Cluster) {
+	c.leaf = leaf
+	c.element = d.clusters.PushFront(c)
+	leaf.clusters = append(leaf.clusters, c)
+	for d.clusters.Len() > d.config.MaxClusters {
+		d.evict()
+	}
+}
+
+// evict removes the least recently matched cluster, and the nodes of its path left without clusters nor children.
+func (d *Drain) evict() {
+	c := d.clusters.Remove(d.clusters.Back()).(*Cluster)
+	c.leaf.clusters = slices.DeleteFunc(c.leaf.clusters, func(o *Cluster) bool {
+		return o == c
+	})
+	d.prune(c.Path)
+}
+
+// prune removes the nodes of the path without clusters nor children, from the leaf up, so that the tree doesn't
+// keep the nodes of the evicted clusters.
+func (d *Drain) prune(path []string) {
+	nodes := make([]*node, 0, len(path)+1)
+	cur := d.root
+	nodes = append(nodes, cur)
+	for _, key := range path {
+		cur = cur.children[key]
+		if cur == nil {
+			return
+		}
+		nodes = append(nodes, cur)
+	}
+	for i := len(path); i > 0; i-- {
+		if len(nodes[i].clusters) > 0 || len(nodes[i].children) > 0 {
+			return
+		}
+		delete(nodes[i-1].children, path[i-1])
+	}
+}
+
+// similarity returns the ratio of tokens of the message equal to the tokens of the template, and the number
+// of wildcards of the template.
+func similarity(template, tokens []string) (float64, int) {
+	if len(template) == 0 {
+		return 1, 0
+	}
+	var same, wildcards int
+	for i, t := range template {
+		switch t {
+		case Wildcard:
+			wildcards++
+		case tokens[i]:
+			same++
+		}
+	}
+	return float64(same) / float64(len(template)), wildcards
+}
+
+func clusterID(tokens []string) string {
+	h := fnv.New64a()
+	for i, t := range tokens {
+		if i > 0 {
+			_, _ = h.Write([]byte{0})
+		}
+		if hasDigit(t) {
+			t = Wildcard
+		}
+		_, _ = h.Write([]byte(t))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func hasDigit(token string) bool {
+	return strings.ContainsFunc(token, unicode.IsDigit)
+}
@@ -0,0 +1,205 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package drain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testConfig() Config {
+	return Config{
+		Depth:               4,
+		SimilarityThreshold: 0.4,
+		MaxChildren:         100,
+		MaxClusters:         1000,
+	}
+}
+
+func TestTrain(t *testing.T) {
+	d := New(testConfig())
+
+	first := d.Train("user alice logged in from 10.0.0.1")
+	assert.Equal(t, "user alice logged in from 10.0.0.1", first.Template())
+
+	second := d.Train("user bob logged in from 10.0.0.2")
+	assert.Same(t, first, second)
+	assert.Equal(t, "user <*> logged in from <*>", second.Template())
+
+	third := d.Train("user   carol logged in from 10.0.0.3")
+	assert.Same(t, first, third)
+
+	other := d.Train("connection closed by peer")
+	assert.NotEqual(t, first.ID, other.ID)
+	assert.Equal(t, "connection closed by peer", other.Template())
+
+	// A message with another number of tokens never belongs to the same cluster.
+	longer := d.Train("user dave logged in from 10.0.0.4 twice")
+	assert.NotEqual(t, first.ID, longer.ID)
+
+	assert.Equal(t, 3, d.Len())
+}
+
+func TestMatch(t *testing.T) {
+	d := New(testConfig())
+	assert.Nil(t, d.Match("user alice logged in from 10.0.0.1"))
+
+	first := d.Train("user alice logged in from 10.0.0.1")
+	other := d.Train("connection closed by peer")
+
+	// The message is matched without generalizing the template of its cluster.
+	assert.Same(t, first, d.Match("user bob logged in from 10.0.0.2"))
+	assert.Equal(t, "user alice logged in from 10.0.0.1", first.Template())
+	assert.Equal(t, []*Cluster{first, other}, d.Clusters(), "the matched cluster is the most recently matched")
+
+	// No cluster nor node is created for the messages matching no cluster.
+	assert.Nil(t, d.Match("connection opened"))
+	assert.Nil(t, d.Match("service stopped by operator"))
+	assert.Equal(t, 2, d.Len())
+	assert.Len(t, d.root.children, 2)
+}
+
+func TestTrainSimilarityThreshold(t *testing.T) {
+	cfg := testConfig()
+	cfg.SimilarityThreshold = 0.7
+	d := New(cfg)
+
+	first := d.Train("request GET /users completed with status 200 in 5ms")
+	assert.Same(t, first, d.Train("request GET /orders completed with status 200 in 7ms"))
+	assert.NotSame(t, first, d.Train("request GET /users failed with timeout after 30 seconds"))
+	assert.Equal(t, 2, d.Len())
+}
+
+func TestTrainEmptyMessage(t *testing.T) {
+	d := New(testConfig())
+	first := d.Train("")
+	assert.Same(t, first, d.Train("   "))
+	assert.Empty(t, first.Template())
+}
+
+func TestClusterIDIsStable(t *testing.T) {
+	d := New(testConfig())
+	c := d.Train("job 42 finished in 3s")
+	id := c.ID
+	d.Train("job 43 finished in 5s")
+	assert.Equal(t, "job <*> finished in <*>", c.Template())
+	assert.Equal(t, id, c.ID)
+
+	// The ID only depends on the first message, once its variable tokens are masked.
+	assert.Equal(t, id, New(testConfig()).Train("job 7 finished in 1s").ID)
+}
+
+func TestMaxChildren(t *testing.T) {
+	cfg := testConfig()
+	cfg.MaxChildren = 3
+	cfg.SimilarityThreshold = 1
+	d := New(cfg)
+
+	a := d.Train("alpha started")
+	b := d.Train("beta started")
+	// The node of the token count is full, the other first tokens go to the wildcard child.
+	c := d.Train("gamma started")
+	e := d.Train("delta started")
+	assert.Equal(t, []string{"2", "alpha"}, a.Path)
+	assert.Equal(t, []string{"2", "beta"}, b.Path)
+	assert.Equal(t, []string{"2", Wildcard}, c.Path)
+	assert.Equal(t, []string{"2", Wildcard}, e.Path)
+	assert.NotSame(t, c, e)
+
+	// The tokens containing digits always go to the wildcard child.
+	f := d.Train("v2 started")
+	assert.Equal(t, []string{"2", Wildcard}, f.Path)
+}
+
+func TestMaxClusters(t *testing.T) {
+	cfg := testConfig()
+	cfg.MaxClusters = 2
+	d := New(cfg)
+
+	a := d.Train("alpha started")
+	b := d.Train("beta stopped now")
+	// Matching a makes b the least recently matched cluster.
+	assert.Same(t, a, d.Train("alpha started"))
+	c := d.Train("gamma failed with error")
+
+	assert.Equal(t, 2, d.Len())
+	assert.Equal(t, []*Cluster{c, a}, d.Clusters())
+
+	// The evicted cluster is created again, with the same ID.
+	recreated := d.Train("beta stopped now")
+	assert.NotSame(t, b, recreated)
+	assert.Equal(t, b.ID, recreated.ID)
+	assert.Equal(t, []*Cluster{recreated, c}, d.Clusters())
+}
+
+func TestRestore(t *testing.T) {
+	d := New(testConfig())
+	d.Train("user alice logged in")
+	d.Train("user bob logged in")
+	d.Train("disk full on /dev/sda1")
+	clusters := d.Clusters()
+
+	restored := New(testConfig())
+	restored.Restore(clusters)
+	require.Equal(t, 2, restored.Len())
+	for i, c := range restored.Clusters() {
+		assert.Equal(t, clusters[i].ID, c.ID)
+		assert.Equal(t, clusters[i].Tokens, c.Tokens)
+		assert.Equal(t, clusters[i].Path, c.Path)
+	}
+
+	c := restored.Train("user carol logged in")
+	assert.Equal(t, clusters[1].ID, c.ID)
+	assert.Equal(t, "user <*> logged in", c.Template())
+	assert.Equal(t, 2, restored.Len())
+}
+
+func TestRestoreMaxClusters(t *testing.T) {
+	d := New(testConfig())
+	d.Train("alpha started")
+	d.Train("beta stopped now")
+	d.Train("gamma failed with error")
+	clusters := d.Clusters()
+
+	cfg := testConfig()
+	cfg.MaxClusters = 2
+	restored := New(cfg)
+	restored.Restore(clusters)
+	require.Equal(t, 2, restored.Len())
+	assert.Equal(t, clusters[0].ID, restored.Clusters()[0].ID)
+	assert.Equal(t, clusters[1].ID, restored.Clusters()[1].ID)
+}
+
+func TestRestoreInvalidCluster(t *testing.T) {
+	d := New(testConfig())
+	d.Restore([]*Cluster{
+		{ID: "a", Tokens: []string{"a", "b"}, Path: []string{"3", "a"}},
+		{ID: "b", Tokens: []string{"a", "b"}},
+	})
+	assert.Equal(t, 0, d.Len())
+}
+
+func TestMaxClustersPrunesTree(t *testing.T) {
+	cfg := testConfig()
+	cfg.MaxClusters = 1
+	d := New(cfg)
+
+	d.Train("alpha started now")
+	d.Train("beta stopped")
+	// The nodes of the evicted cluster are removed.
+	assert.Len(t, d.root.children, 1)
+	require.Contains(t, d.root.children, "2")
+	assert.Len(t, d.root.children["2"].children, 1)
+	assert.Contains(t, d.root.children["2"].children, "beta")
+
+	// The leaf of the evicted cluster is kept when the new cluster is added to it.
+	cfg.SimilarityThreshold = 1
+	d = New(cfg)
+	d.Train("alpha one")
+	two := d.Train("alpha two")
+	assert.Same(t, two, d.Train("alpha two"))
+	assert.Equal(t, 1, d.Len())
+}
@@ -0,0 +1,267 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package logpatternconnector // import "github.com/open-telemetry/opentelemetry-collector-contrib/connector/logpatternconnector"
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/connector"
+	"go.opentelemetry.io/collector/extension/xextension/storage"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/logpatternconnector/internal/drain"
+)
+
+const stateStorageKey = "logpattern_state"
+
+// pattern is the pattern matched by a log.
+type pattern struct {
+	id       string
+	template string
+}
+
+// minerState is the state of the patterns, as persisted to the storage.
+type minerState struct {
+	// Patterns are ordered from the most to the least recently matched.
+	Patterns []patternState
+}
+
+type patternState struct {
+	ID     string
+	Tokens []string
+	Path   []string
+}
+
+// miner learns the patterns of the log bodies. It is shared by the connectors created from the same
+// configuration.
+type miner struct {
+	id     component.ID
+	config *Config
+	logger *zap.Logger
+
+	lock  sync.Mutex
+	drain *drain.Drain
+	// hasLogsConnector is whether a logs connector shares the miner, in which case it trains the patterns of the
+	// logs, and the metrics connector only trains the logs matching no pattern.
+	hasLogsConnector bool
+	// storageClient persists the patterns, nil unless a storage is configured.
+	storageClient storage.Client
+	lastSnapshot  time.Time
+}
+
+func newMiner(set connector.Settings, config *Config) *miner {
+	return &miner{
+		id:     set.ID,
+		config: config,
+		logger: set.Logger,
+		drain:  newDrain(config),
+	}
+}
+
+// addLogsConnector records that a logs connector shares the miner.
+func (m *miner) addLogsConnector() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.hasLogsConnector = true
+}
+
+func newDrain(config *Config) *drain.Drain {
+	return drain.New(drain.Config{
+		Depth:               config.Depth,
+		SimilarityThreshold: config.SimilarityThreshold,
+		MaxChildren:         config.MaxChildren,
+		MaxClusters:         config.MaxPatterns,
+	})
+}
+
+// Start implements the component.Component interface.
+func (m *miner) Start(ctx context.Context, host component.Host) error {
+	if m.config.Storage == nil {
+		return nil
+	}
+	client, err := getStorageClient(ctx, host, m.config.Storage, m.id)
+	if err != nil {
+		return err
+	}
+	if err := m.loadState(ctx, client); err != nil {
+		return err
+	}
+	m.lock.Lock()
+	m.storageClient = client
+	m.lastSnapshot = time.Now()
+	m.lock.Unlock()
+	return nil
+}
+
+// Shutdown implements the component.Component interface.
+func (m *miner) Shutdown(ctx context.Context) error {
+	m.lock.Lock()
+	client := m.storageClient
+	m.storageClient = nil
+	var data []byte
+	var err error
+	if client != nil {
+		data, err = m.encodeState()
+	}
+	m.lock.Unlock()
+
+	if client == nil {
+		return nil
+	}
+	if err != nil {
+		m.logger.Error("Failed to encode the state", zap.Error(err))
+	} else {
+		storeState(ctx, client, data, m.logger)
+	}
+	return client.Close(ctx)
+}
+
+// matchLogs calls fn with the pattern of each log of the batch. The patterns are trained on the logs if train is
+// set, which is the case of the logs connector, and of the metrics connector when it doesn't share the miner with a
+// logs connector. Otherwise, the logs are matched against the learned patterns, and only the logs matching no
+// pattern are trained: the collector sends the logs to the connectors mutating them, such as the logs connector,
+// before the other ones, so the logs received by both connectors are trained once.
+func (m *miner) matchLogs(ctx context.Context, ld plog.Logs, train bool, fn func(plog.ResourceLogs, plog.LogRecord, pattern)) {
+	bodies := logBodies(ld)
+
+	m.lock.Lock()
+	patterns := m.batchPatterns(bodies, train || !m.hasLogsConnector)
+	client := m.storageClient
+	data := m.snapshotState()
+	m.lock.Unlock()
+
+	n := 0
+	for i := 0; i < ld.ResourceLogs().Len(); i++ {
+		rl := ld.ResourceLogs().At(i)
+		for j := 0; j < rl.ScopeLogs().Len(); j++ {
+			logs := rl.ScopeLogs().At(j).LogRecords()
+			for k := 0; k < logs.Len(); k++ {
+				fn(rl, logs.At(k), patterns[n])
+				n++
+			}
+		}
+	}
+
+	if data != nil {
+		storeState(ctx, client, data, m.logger)
+	}
+}
+
+// batchPatterns returns the patterns of the bodies of a batch, training the patterns on the bodies if train is set,
+// and on the bodies matching no pattern otherwise. It must be called with the lock held.
+func (m *miner) batchPatterns(bodies []string, train bool) []pattern {
+	patterns := make([]pattern, len(bodies))
+	for i, body := range bodies {
+		var c *drain.Cluster
+		if !train {
+			c = m.drain.Match(body)
+		}
+		if c == nil {
+			c = m.drain.Train(body)
+		}
+		patterns[i] = pattern{id: c.ID, template: c.Template()}
+	}
+	return patterns
+}
+
+// logBodies returns the bodies of the logs of the batch, in order.
+func logBodies(ld plog.Logs) []string {
+	bodies := make([]string, 0, ld.LogRecordCount())
+	for i := 0; i < ld.ResourceLogs().Len(); i++ {
+		rl := ld.ResourceLogs().At(i)
+		for j := 0; j < rl.ScopeLogs().Len(); j++ {
+			logs := rl.ScopeLogs().At(j).LogRecords()
+			for k := 0; k < logs.Len(); k++ {
+				bodies = append(bodies, logs.At(k).Body().AsString())
+			}
+		}
+	}
+	return bodies
+}
+
+func getStorageClient(ctx context.Context, host component.Host, storageID *component.ID, id component.ID) (storage.Client, error) {
+	extension, ok := host.GetExtensions()[*storageID]
+	if !ok {
+		return nil, fmt.Errorf("storage extension '%s' not found", storageID)
+	}
+
+	storageExtension, ok := extension.(storage.Extension)
+	if !ok {
+		return nil, fmt.Errorf("non-storage extension '%s' found", storageID)
+	}
+
+	return storageExtension.GetClient(ctx, component.KindConnector, id, "")
+}
+
+// loadState restores the patterns from the storage. A state which can't be decoded is discarded, the patterns
+// are then learned from scratch.
+func (m *miner) loadState(ctx context.Context, client storage.Client) error {
+	data, err := client.Get(ctx, stateStorageKey)
+	if err != nil {
+		return fmt.Errorf("failed to load the state from the storage: %w", err)
+	}
+	if len(data) == 0 {
+		return nil
+	}
+
+	var state minerState
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&state); err != nil {
+		m.logger.Warn("Discarding the state which couldn't be decoded", zap.Error(err))
+		return nil
+	}
+
+	clusters := make([]*drain.Cluster, len(state.Patterns))
+	for i, p := range state.Patterns {
+		clusters[i] = &drain.Cluster{ID: p.ID, Tokens: p.Tokens, Path: p.Path}
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.drain = newDrain(m.config)
+	m.drain.Restore(clusters)
+	m.logger.Info("Restored the log patterns", zap.Int("patterns", m.drain.Len()))
+	return nil
+}
+
+// encodeState encodes the patterns, it must be called with the lock held.
+func (m *miner) encodeState() ([]byte, error) {
+	var state minerState
+	for _, c := range m.drain.Clusters() {
+		state.Patterns = append(state.Patterns, patternState{ID: c.ID, Tokens: c.Tokens, Path: c.Path})
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(state); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// snapshotState encodes the patterns if the snapshot interval elapsed since the last snapshot, it must be
+// called with the lock held. It returns nil if no snapshot is due.
+func (m *miner) snapshotState() []byte {
+	if m.storageClient == nil || time.Since(m.lastSnapshot) < m.config.StateSnapshotInterval {
+		return nil
+	}
+	data, err := m.encodeState()
+	if err != nil {
+		m.logger.Error("Failed to encode the state", zap.Error(err))
+		return nil
+	}
+	m.lastSnapshot = time.Now()
+	return data
+}
+
+func storeState(ctx context.Context, client storage.Client, data []byte, logger *zap.Logger) {
+	if err := client.Set(ctx, stateStorageKey, data); err != nil {
+		logger.Error("Failed to store the state", zap.Error(err))
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package logpatternconnector
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/connector/connectortest"
+	"go.opentelemetry.io/collector/pdata/plog"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/logpatternconnector/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
+)
+
+func newStartedMiner(t *testing.T, cfg *Config, host component.Host) *miner {
+	m := newMiner(connectortest.NewNopSettings(metadata.Type), cfg)
+	require.NoError(t, m.Start(context.Background(), host))
+	return m
+}
+
+func matchBodies(m *miner, train bool, bodies ...string) []pattern {
+	ld := plog.NewLogs()
+	logs := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	for _, body := range bodies {
+		logs.AppendEmpty().Body().SetStr(body)
+	}
+	var patterns []pattern
+	m.matchLogs(context.Background(), ld, train, func(_ plog.ResourceLogs, _ plog.LogRecord, p pattern) {
+		patterns = append(patterns, p)
+	})
+	return patterns
+}
+
+func TestMinerBody(t *testing.T) {
+	m := newStartedMiner(t, createDefaultConfig().(*Config), componenttest.NewNopHost())
+	defer func() {
+		require.NoError(t, m.Shutdown(context.Background()))
+	}()
+
+	ld := plog.NewLogs()
+	logs := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	logs.AppendEmpty().Body().SetStr("disk usage at 91 percent")
+	logs.AppendEmpty().Body().SetInt(42)
+	var templates []string
+	m.matchLogs(context.Background(), ld, true, func(_ plog.ResourceLogs, _ plog.LogRecord, p pattern) {
+		templates = append(templates, p.template)
+	})
+	assert.Equal(t, []string{"disk usage at 91 percent", "42"}, templates)
+}
+
+func TestMinerTrain(t *testing.T) {
+	m := newStartedMiner(t, createDefaultConfig().(*Config), componenttest.NewNopHost())
+	defer func() {
+		require.NoError(t, m.Shutdown(context.Background()))
+	}()
+
+	// Without a logs connector, the metrics connector trains the patterns on all the logs.
+	first := matchBodies(m, false, "user alice logged in")
+	assert.Equal(t, "user <*> logged in", matchBodies(m, false, "user bob logged in")[0].template)
+
+	// With a logs connector, the metrics connector matches the logs against the learned patterns, and only
+	// trains the logs matching no pattern.
+	m.addLogsConnector()
+	matched := matchBodies(m, false, "job 1 finished in 3s", "user carol logged in")
+	assert.Equal(t, "job 1 finished in 3s", matched[0].template)
+	assert.Equal(t, first[0].id, matched[1].id)
+	matched = matchBodies(m, false, "job 2 finished in 5s")
+	assert.Equal(t, "job 1 finished in 3s", matched[0].template)
+
+	// The logs connector trains the patterns on all the logs, including identical consecutive batches.
+	trained := matchBodies(m, true, "job 2 finished in 5s")
+	assert.Equal(t, pattern{id: matched[0].id, template: "job <*> finished in <*>"}, trained[0])
+	assert.Equal(t, trained, matchBodies(m, true, "job 2 finished in 5s"))
+	assert.Equal(t, 2, m.drain.Len())
+}
+
+func TestMinerState(t *testing.T) {
+	ctx := context.Background()
+	host := storagetest.NewStorageHost().WithFileBackedStorageExtension("state", t.TempDir())
+	storageID := storagetest.NewStorageID("state")
+	cfg := createDefaultConfig().(*Config)
+	cfg.Storage = &storageID
+
+	m := newStartedMiner(t, cfg, host)
+	learned := matchBodies(m, true, "user alice logged in", "user bob logged in", "cache warmed up")
+	require.NoError(t, m.Shutdown(ctx))
+
+	// The patterns are restored with their IDs and templates.
+	m = newStartedMiner(t, cfg, host)
+	assert.Equal(t, 2, m.drain.Len())
+	restored := matchBodies(m, true, "user carol logged in", "cache warmed up")
+	assert.Equal(t, pattern{id: learned[1].id, template: "user <*> logged in"}, restored[0])
+	assert.Equal(t, learned[2], restored[1])
+	assert.Equal(t, 2, m.drain.Len())
+	require.NoError(t, m.Shutdown(ctx))
+}
+
+func TestMinerStateSnapshotInterval(t *testing.T) {
+	ctx := context.Background()
+	host := storagetest.NewStorageHost().WithInMemoryStorageExtension("state")
+	storageID := storagetest.NewStorageID("state")
+	cfg := createDefaultConfig().(*Config)
+	cfg.Storage = &storageID
+
+	m := newStartedMiner(t, cfg, host)
+	matchBodies(m, true, "cache warmed up")
+	data, err := m.storageClient.Get(ctx, stateStorageKey)
+	require.NoError(t, err)
+	assert.Empty(t, data, "the state is snapshotted once the interval elapsed")
+
+	m.lastSnapshot = time.Now().Add(-cfg.StateSnapshotInterval)
+	matchBodies(m, true, "cache warmed up")
+	data, err = m.storageClient.Get(ctx, stateStorageKey)
+	require.NoError(t, err)
+	assert.NotEmpty(t, data)
+	require.NoError(t, m.Shutdown(ctx))
+}
+
+func TestMinerStateInvalid(t *testing.T) {
+	ctx := context.Background()
+	host := storagetest.NewStorageHost().WithFileBackedStorageExtension("state", t.TempDir())
+	storageID := storagetest.NewStorageID("state")
+	client, err := host.GetExtensions()[storageID].(*storagetest.TestStorage).GetClient(ctx, component.KindConnector, component.NewID(metadata.Type), "")
+	require.NoError(t, err)
+	require.NoError(t, client.Set(ctx, stateStorageKey, []byte("invalid")))
+	require.NoError(t, client.Close(ctx))
+
+	// The patterns are learned from scratch.
+	cfg := createDefaultConfig().(*Config)
+	cfg.Storage = &storageID
+	m := newStartedMiner(t, cfg, host)
+	assert.Zero(t, m.drain.Len())
+	require.NoError(t, m.Shutdown(ctx))
+}
+
+func TestMinerStorageErrors(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		storageID component.ID
+		host      component.Host
+		expected  string
+	}{
+		{
+			name:      "missing",
+			storageID: storagetest.NewStorageID("missing"),
+			host:      componenttest.NewNopHost(),
+			expected:  "storage extension 'test_storage/missing' not found",
+		},
+		{
+			name:      "not a storage",
+			storageID: storagetest.NewNonStorageID("other"),
+			host:      storagetest.NewStorageHost().WithNonStorageExtension("other"),
+			expected:  "non-storage extension 'non_storage/other' found",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.Storage = &tt.storageID
+			m := newMiner(connectortest.NewNopSettings(metadata.Type), cfg)
+			assert.EqualError(t, m.Start(context.Background(), tt.host), tt.expected)
+			require.NoError(t, m.Shutdown(context.Background()))
+		})
+	}
+}
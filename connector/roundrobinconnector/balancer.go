@@ -0,0 +1,113 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package roundrobinconnector // import "github.com/open-telemetry/opentelemetry-collector-contrib/connector/roundrobinconnector"
+
+import (
+	"sync/atomic"
+)
+
+// balancer picks the pipeline each batch of data is sent to, by its index.
+type balancer struct {
+	strategy Strategy
+	next     atomic.Uint64
+	size     int
+
+	// schedule is the sequence of the indexes of the pipelines for the weighted strategy, in which each pipeline
+	// appears as many times as its weight, spread as evenly as possible.
+	schedule []int
+
+	// weights and inflight are the weights and the number of in-flight requests of the pipelines for the
+	// least_loaded strategy.
+	weights  []int64
+	inflight []atomic.Int64
+}
+
+func newBalancer(strategy Strategy, weights []int) *balancer {
+	b := &balancer{strategy: strategy, size: len(weights)}
+	switch strategy {
+	case StrategyWeighted:
+		b.schedule = weightedSchedule(weights)
+	case StrategyLeastLoaded:
+		b.weights = make([]int64, len(weights))
+		for i, w := range weights {
+			b.weights[i] = int64(w)
+		}
+		b.inflight = make([]atomic.Int64, len(weights))
+	}
+	return b
+}
+
+// acquire returns the index of the pipeline the next batch is sent to, release must be called with the index
+// once the pipeline consumed the batch.
+func (b *balancer) acquire() int {
+	switch b.strategy {
+	case StrategyWeighted:
+		return b.schedule[b.next.Add(1)%uint64(len(b.schedule))]
+	case StrategyLeastLoaded:
+		return b.leastLoaded()
+	default:
+		return int(b.next.Add(1) % uint64(b.size))
+	}
+}
+
+func (b *balancer) release(i int) {
+	if b.strategy == StrategyLeastLoaded {
+		b.inflight[i].Add(-1)
+	}
+}
+
+// leastLoaded picks the pipeline with the fewest in-flight requests per unit of weight, counting the new request.
+// The search starts from the next pipeline in turn, so that the ties are broken in a round-robin mode.
+// The pick is best effort, concurrent requests may pick the same pipeline.
+func (b *balancer) leastLoaded() int {
+	start := int(b.next.Add(1) % uint64(b.size))
+	best, bestLoad := start, b.inflight[start].Load()
+	for k := 1; k < b.size; k++ {
+		i := (start + k) % b.size
+		load := b.inflight[i].Load()
+		// (load+1)/weights[i] < (bestLoad+1)/weights[best], without the divisions.
+		if (load+1)*b.weights[best] < (bestLoad+1)*b.weights[i] {
+			best, bestLoad = i, load
+		}
+	}
+	b.inflight[best].Add(1)
+	return best
+}
+
+// weightedSchedule spreads the pipelines over a schedule with the smooth weighted round-robin algorithm, so that
+// a pipeline of large weight does not get bursts of consecutive batches.
+func weightedSchedule(weights []int) []int {
+	divisor := 0
+	for _, w := range weights {
+		divisor = gcd(divisor, w)
+	}
+	total := 0
+	scaled := make([]int, len(weights))
+	for i, w := range weights {
+		scaled[i] = w / divisor
+		total += scaled[i]
+	}
+
+	current := make([]int, len(weights))
+	schedule := make([]int, total)
+	for n := range schedule {
+		best := 0
+		for i, w := range scaled {
+			current[i] += w
+			if current[i] > current[best] {
+				best = i
+			}
+		}
+		current[best] -= total
+		schedule[n] = best
+	}
+	return schedule
+}
+
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
@@ -0,0 +1,74 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package roundrobinconnector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeightedSchedule(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		weights []int
+		want    []int
+	}{
+		{name: "equal", weights: []int{1, 1, 1}, want: []int{0, 1, 2}},
+		{name: "reduced", weights: []int{4, 2}, want: []int{0, 1, 0}},
+		// The heaviest pipeline is interleaved with the others rather than picked in a burst.
+		{name: "smooth", weights: []int{5, 1, 1}, want: []int{0, 0, 1, 0, 2, 0, 0}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, weightedSchedule(tc.weights))
+		})
+	}
+}
+
+func TestBalancerRoundRobin(t *testing.T) {
+	b := newBalancer("", []int{1, 1, 1})
+	var picks []int
+	for i := 0; i < 6; i++ {
+		picks = append(picks, b.acquire())
+		b.release(picks[i])
+	}
+	assert.Equal(t, []int{1, 2, 0, 1, 2, 0}, picks)
+}
+
+func TestBalancerWeighted(t *testing.T) {
+	b := newBalancer(StrategyWeighted, []int{2, 1})
+	counts := make([]int, 2)
+	for i := 0; i < 30; i++ {
+		counts[b.acquire()]++
+	}
+	assert.Equal(t, []int{20, 10}, counts)
+}
+
+func TestBalancerLeastLoaded(t *testing.T) {
+	b := newBalancer(StrategyLeastLoaded, []int{1, 1, 1})
+	// The idle pipelines are picked before any pipeline gets a second request.
+	first := []int{b.acquire(), b.acquire(), b.acquire()}
+	assert.ElementsMatch(t, []int{0, 1, 2}, first)
+
+	// The pipeline that completed its request is the least loaded one.
+	b.release(1)
+	assert.Equal(t, 1, b.acquire())
+
+	b.release(0)
+	b.release(1)
+	b.release(2)
+	for i := range b.inflight {
+		assert.Zero(t, b.inflight[i].Load())
+	}
+}
+
+func TestBalancerLeastLoadedWeights(t *testing.T) {
+	b := newBalancer(StrategyLeastLoaded, []int{3, 1})
+	// While no request completes, the in-flight requests follow the weights.
+	counts := make([]int, 2)
+	for i := 0; i < 8; i++ {
+		counts[b.acquire()]++
+	}
+	assert.Equal(t, []int{6, 2}, counts)
+}
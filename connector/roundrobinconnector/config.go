@@ -3,5 +3,53 @@
 
 package roundrobinconnector // import "github.com/open-telemetry/opentelemetry-collector-contrib/connector/roundrobinconnector"
 
+import (
+	"fmt"
+
+	"go.opentelemetry.io/collector/pipeline"
+)
+
+// Strategy is how the data is distributed among the pipelines.
+type Strategy string
+
+const (
+	// StrategyRoundRobin sends the data to each pipeline in turn.
+	StrategyRoundRobin Strategy = "round_robin"
+	// StrategyWeighted sends the data to the pipelines in proportion to their weights.
+	StrategyWeighted Strategy = "weighted"
+	// StrategyLeastLoaded sends the data to the pipeline with the fewest in-flight requests relative to its weight.
+	StrategyLeastLoaded Strategy = "least_loaded"
+)
+
+// maxWeight bounds the weights, the weighted strategy keeps a schedule as long as the sum of the weights.
+const maxWeight = 1000
+
 // Config for the connector
-type Config struct{}
+type Config struct {
+	// Strategy is how the data is distributed among the pipelines, round_robin if unset
+	Strategy Strategy `mapstructure:"strategy"`
+
+	// Weights are the relative capacities of the pipelines for the weighted and least_loaded strategies,
+	// the pipelines without a weight have a weight of 1
+	Weights map[pipeline.ID]int `mapstructure:"weights"`
+}
+
+// Validate checks that the strategy is known and the weights are supported by it
+func (c *Config) Validate() error {
+	switch c.Strategy {
+	case "", StrategyRoundRobin:
+		if len(c.Weights) > 0 {
+			return fmt.Errorf("invalid weights, the weights are not supported by the %s strategy", StrategyRoundRobin)
+		}
+	case StrategyWeighted, StrategyLeastLoaded:
+	default:
+		return fmt.Errorf("invalid strategy: %q, the strategy should be one of %s, %s or %s",
+			c.Strategy, StrategyRoundRobin, StrategyWeighted, StrategyLeastLoaded)
+	}
+	for pipeID, weight := range c.Weights {
+		if weight <= 0 || weight > maxWeight {
+			return fmt.Errorf("invalid weight of pipeline %q: %d, the weight should be between 1 and %d", pipeID, weight, maxWeight)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package roundrobinconnector
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap/confmaptest"
+	"go.opentelemetry.io/collector/pipeline"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/roundrobinconnector/internal/metadata"
+)
+
+func TestLoadConfig(t *testing.T) {
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+	require.NoError(t, err)
+
+	testcases := []struct {
+		id          component.ID
+		expected    *Config
+		expectedErr string
+	}{
+		{
+			id:       component.NewID(metadata.Type),
+			expected: &Config{},
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "weighted"),
+			expected: &Config{
+				Strategy: StrategyWeighted,
+				Weights: map[pipeline.ID]int{
+					pipeline.NewIDWithName(pipeline.SignalTraces, "us"): 3,
+					pipeline.NewIDWithName(pipeline.SignalTraces, "eu"): 1,
+				},
+			},
+		},
+		{
+			id:       component.NewIDWithName(metadata.Type, "least_loaded"),
+			expected: &Config{Strategy: StrategyLeastLoaded},
+		},
+		{
+			id:          component.NewIDWithName(metadata.Type, "invalid_strategy"),
+			expectedErr: `invalid strategy: "random", the strategy should be one of round_robin, weighted or least_loaded`,
+		},
+		{
+			id:          component.NewIDWithName(metadata.Type, "invalid_weight"),
+			expectedErr: `invalid weight of pipeline "traces/us": 0, the weight should be between 1 and 1000`,
+		},
+		{
+			id:          component.NewIDWithName(metadata.Type, "weights_round_robin"),
+			expectedErr: "invalid weights, the weights are not supported by the round_robin strategy",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.id.String(), func(t *testing.T) {
+			cfg := NewFactory().CreateDefaultConfig()
+			sub, err := cm.Sub(tc.id.String())
+			require.NoError(t, err)
+			require.NoError(t, sub.Unmarshal(cfg))
+
+			if tc.expectedErr != "" {
+				assert.EqualError(t, cfg.(*Config).Validate(), tc.expectedErr)
+				return
+			}
+			assert.NoError(t, cfg.(*Config).Validate())
+			assert.Equal(t, tc.expected, cfg)
+		})
+	}
+}
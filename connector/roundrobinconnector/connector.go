@@ -5,7 +5,7 @@ package roundrobinconnector // import "github.com/open-telemetry/opentelemetry-c
 
 import (
 	"context"
-	"sync/atomic"
+	"fmt"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/connector"
@@ -16,17 +16,32 @@ import (
 	"go.opentelemetry.io/collector/pipeline"
 )
 
-func allConsumers[T any](r router[T]) ([]T, error) {
+// allConsumers returns the consumers of the pipelines of the router, and the balancer distributing the data among them.
+// The weights of the pipelines of other signals are checked by the connectors of their signal.
+func allConsumers[T any](cfg *Config, signal pipeline.Signal, r router[T]) ([]T, *balancer, error) {
 	pipeIDs := r.PipelineIDs()
 	consumers := make([]T, len(pipeIDs))
+	weights := make([]int, len(pipeIDs))
 	for i, pipeID := range pipeIDs {
 		cons, err := r.Consumer(pipeID)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		consumers[i] = cons
+		weights[i] = 1
+		if w, ok := cfg.Weights[pipeID]; ok {
+			weights[i] = w
+		}
+	}
+	for pipeID := range cfg.Weights {
+		if pipeID.Signal() != signal {
+			continue
+		}
+		if _, err := r.Consumer(pipeID); err != nil {
+			return nil, nil, fmt.Errorf("weight configured for pipeline %q, which is not connected to the connector: %w", pipeID, err)
+		}
 	}
-	return consumers, nil
+	return consumers, newBalancer(cfg.Strategy, weights), nil
 }
 
 type router[T any] interface {
@@ -34,40 +49,41 @@ type router[T any] interface {
 	Consumer(pipelineIDs ...pipeline.ID) (T, error)
 }
 
-func newLogs(nextConsumer consumer.Logs) (connector.Logs, error) {
-	nextConsumers, err := allConsumers[consumer.Logs](nextConsumer.(connector.LogsRouterAndConsumer))
+func newLogs(cfg *Config, nextConsumer consumer.Logs) (connector.Logs, error) {
+	nextConsumers, b, err := allConsumers[consumer.Logs](cfg, pipeline.SignalLogs, nextConsumer.(connector.LogsRouterAndConsumer))
 	if err != nil {
 		return nil, err
 	}
-	return &roundRobin{nextLogs: nextConsumers}, nil
+	return &roundRobin{balancer: b, nextLogs: nextConsumers}, nil
 }
 
-func newMetrics(nextConsumer consumer.Metrics) (connector.Metrics, error) {
-	nextConsumers, err := allConsumers[consumer.Metrics](nextConsumer.(connector.MetricsRouterAndConsumer))
+func newMetrics(cfg *Config, nextConsumer consumer.Metrics) (connector.Metrics, error) {
+	nextConsumers, b, err := allConsumers[consumer.Metrics](cfg, pipeline.SignalMetrics, nextConsumer.(connector.MetricsRouterAndConsumer))
 	if err != nil {
 		return nil, err
 	}
-	return &roundRobin{nextMetrics: nextConsumers}, nil
+	return &roundRobin{balancer: b, nextMetrics: nextConsumers}, nil
 }
 
-func newTraces(nextConsumer consumer.Traces) (connector.Traces, error) {
-	nextConsumers, err := allConsumers[consumer.Traces](nextConsumer.(connector.TracesRouterAndConsumer))
+func newTraces(cfg *Config, nextConsumer consumer.Traces) (connector.Traces, error) {
+	nextConsumers, b, err := allConsumers[consumer.Traces](cfg, pipeline.SignalTraces, nextConsumer.(connector.TracesRouterAndConsumer))
 	if err != nil {
 		return nil, err
 	}
-	return &roundRobin{nextTraces: nextConsumers}, nil
+	return &roundRobin{balancer: b, nextTraces: nextConsumers}, nil
 }
 
-// roundRobin is used to pass signals directly from one pipeline to one of the configured once in a round-robin mode.
+// roundRobin is used to pass signals directly from one pipeline to one of the configured once in a round-robin mode,
+// or in proportion to the weights or the load of the pipelines.
 // This is useful when there is a need to scale (shard) data processing and downstream components do not
 // handle concurrent requests very well.
 type roundRobin struct {
 	component.StartFunc
 	component.ShutdownFunc
-	nextConsumer atomic.Uint64
-	nextMetrics  []consumer.Metrics
-	nextLogs     []consumer.Logs
-	nextTraces   []consumer.Traces
+	balancer    *balancer
+	nextMetrics []consumer.Metrics
+	nextLogs    []consumer.Logs
+	nextTraces  []consumer.Traces
 }
 
 func (rr *roundRobin) Capabilities() consumer.Capabilities {
@@ -75,13 +91,19 @@ func (rr *roundRobin) Capabilities() consumer.Capabilities {
 }
 
 func (rr *roundRobin) ConsumeLogs(ctx context.Context, ld plog.Logs) error {
-	return rr.nextLogs[rr.nextConsumer.Add(1)%uint64(len(rr.nextLogs))].ConsumeLogs(ctx, ld)
+	i := rr.balancer.acquire()
+	defer rr.balancer.release(i)
+	return rr.nextLogs[i].ConsumeLogs(ctx, ld)
 }
 
 func (rr *roundRobin) ConsumeMetrics(ctx context.Context, md pmetric.Metrics) error {
-	return rr.nextMetrics[rr.nextConsumer.Add(1)%uint64(len(rr.nextMetrics))].ConsumeMetrics(ctx, md)
+	i := rr.balancer.acquire()
+	defer rr.balancer.release(i)
+	return rr.nextMetrics[i].ConsumeMetrics(ctx, md)
 }
 
 func (rr *roundRobin) ConsumeTraces(ctx context.Context, td ptrace.Traces) error {
-	return rr.nextTraces[rr.nextConsumer.Add(1)%uint64(len(rr.nextTraces))].ConsumeTraces(ctx, td)
+	i := rr.balancer.acquire()
+	defer rr.balancer.release(i)
+	return rr.nextTraces[i].ConsumeTraces(ctx, td)
 }
@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/connector"
 	"go.opentelemetry.io/collector/connector/connectortest"
@@ -140,3 +141,158 @@ func TestTracesRoundRobin(t *testing.T) {
 
 	assert.NoError(t, traces.Shutdown(ctx))
 }
+
+func TestLogsWeighted(t *testing.T) {
+	f := NewFactory()
+	cfg := &Config{
+		Strategy: StrategyWeighted,
+		Weights: map[pipeline.ID]int{
+			pipeline.NewIDWithName(pipeline.SignalLogs, "0"): 3,
+			pipeline.NewIDWithName(pipeline.SignalLogs, "1"): 2,
+		},
+	}
+
+	ctx := context.Background()
+	set := connectortest.NewNopSettings(metadata.Type)
+	host := componenttest.NewNopHost()
+
+	sink1 := new(consumertest.LogsSink)
+	sink2 := new(consumertest.LogsSink)
+	sink3 := new(consumertest.LogsSink)
+	logs, err := f.CreateLogsToLogs(ctx, set, cfg, connector.NewLogsRouter(newPipelineMap[consumer.Logs](pipeline.SignalLogs, sink1, sink2, sink3)))
+	assert.NoError(t, err)
+	assert.NotNil(t, logs)
+
+	assert.NoError(t, logs.Start(ctx, host))
+
+	for i := 0; i < 12; i++ {
+		assert.NoError(t, logs.ConsumeLogs(ctx, plog.NewLogs()))
+	}
+
+	// The third pipeline has the default weight of 1.
+	assert.Len(t, sink1.AllLogs(), 6)
+	assert.Len(t, sink2.AllLogs(), 4)
+	assert.Len(t, sink3.AllLogs(), 2)
+
+	assert.NoError(t, logs.Shutdown(ctx))
+}
+
+func TestTracesLeastLoaded(t *testing.T) {
+	f := NewFactory()
+	cfg := &Config{Strategy: StrategyLeastLoaded}
+
+	ctx := context.Background()
+	set := connectortest.NewNopSettings(metadata.Type)
+	host := componenttest.NewNopHost()
+
+	entered := make(chan struct{})
+	unblock := make(chan struct{})
+	blocking, err := consumer.NewTraces(func(context.Context, ptrace.Traces) error {
+		entered <- struct{}{}
+		<-unblock
+		return nil
+	})
+	require.NoError(t, err)
+	sink2 := new(consumertest.TracesSink)
+	sink3 := new(consumertest.TracesSink)
+	traces, err := f.CreateTracesToTraces(ctx, set, cfg, connector.NewTracesRouter(newPipelineMap[consumer.Traces](pipeline.SignalTraces, blocking, sink2, sink3)))
+	assert.NoError(t, err)
+	assert.NotNil(t, traces)
+
+	assert.NoError(t, traces.Start(ctx, host))
+
+	// Consume until a request is in flight in the blocking pipeline.
+	done := make(chan error, 1)
+	consumed := 0
+	for blocked := false; !blocked; {
+		go func() {
+			done <- traces.ConsumeTraces(ctx, ptrace.NewTraces())
+		}()
+		select {
+		case <-entered:
+			blocked = true
+		case err = <-done:
+			assert.NoError(t, err)
+			consumed++
+		}
+	}
+
+	// The idle pipelines get all the data while the blocking pipeline is busy.
+	for i := 0; i < 6; i++ {
+		assert.NoError(t, traces.ConsumeTraces(ctx, ptrace.NewTraces()))
+	}
+	assert.Equal(t, consumed+6, len(sink2.AllTraces())+len(sink3.AllTraces()))
+	assert.NotEmpty(t, sink2.AllTraces())
+	assert.NotEmpty(t, sink3.AllTraces())
+
+	close(unblock)
+	assert.NoError(t, <-done)
+
+	assert.NoError(t, traces.Shutdown(ctx))
+}
+
+func TestMetricsWeightOfUnknownPipeline(t *testing.T) {
+	f := NewFactory()
+	cfg := &Config{
+		Strategy: StrategyWeighted,
+		Weights: map[pipeline.ID]int{
+			pipeline.NewIDWithName(pipeline.SignalMetrics, "missing"): 2,
+		},
+	}
+
+	ctx := context.Background()
+	set := connectortest.NewNopSettings(metadata.Type)
+
+	sink1 := new(consumertest.MetricsSink)
+	sink2 := new(consumertest.MetricsSink)
+	metrics, err := f.CreateMetricsToMetrics(ctx, set, cfg, connector.NewMetricsRouter(newPipelineMap[consumer.Metrics](pipeline.SignalMetrics, sink1, sink2)))
+	assert.ErrorContains(t, err, `weight configured for pipeline "metrics/missing"`)
+	assert.Nil(t, metrics)
+
+	// The weight of a pipeline of another signal is left to the connectors of that signal.
+	cfg.Weights = map[pipeline.ID]int{
+		pipeline.NewIDWithName(pipeline.SignalMetrics, "1"):      2,
+		pipeline.NewIDWithName(pipeline.SignalTraces, "missing"): 2,
+	}
+	metrics, err = f.CreateMetricsToMetrics(ctx, set, cfg, connector.NewMetricsRouter(newPipelineMap[consumer.Metrics](pipeline.SignalMetrics, sink1, sink2)))
+	assert.NoError(t, err)
+	assert.NotNil(t, metrics)
+}
+
+func TestWeightsOfSeveralSignals(t *testing.T) {
+	f := NewFactory()
+	cfg := &Config{
+		Strategy: StrategyWeighted,
+		Weights: map[pipeline.ID]int{
+			pipeline.NewIDWithName(pipeline.SignalTraces, "1"): 3,
+			pipeline.NewIDWithName(pipeline.SignalLogs, "1"):   2,
+		},
+	}
+
+	ctx := context.Background()
+	set := connectortest.NewNopSettings(metadata.Type)
+	host := componenttest.NewNopHost()
+
+	// The connectors of both signals share the configuration, each one using the weights of its pipelines.
+	tracesSink1 := new(consumertest.TracesSink)
+	tracesSink2 := new(consumertest.TracesSink)
+	traces, err := f.CreateTracesToTraces(ctx, set, cfg, connector.NewTracesRouter(newPipelineMap[consumer.Traces](pipeline.SignalTraces, tracesSink1, tracesSink2)))
+	require.NoError(t, err)
+	logsSink1 := new(consumertest.LogsSink)
+	logsSink2 := new(consumertest.LogsSink)
+	logs, err := f.CreateLogsToLogs(ctx, set, cfg, connector.NewLogsRouter(newPipelineMap[consumer.Logs](pipeline.SignalLogs, logsSink1, logsSink2)))
+	require.NoError(t, err)
+
+	require.NoError(t, traces.Start(ctx, host))
+	require.NoError(t, logs.Start(ctx, host))
+	for i := 0; i < 12; i++ {
+		assert.NoError(t, traces.ConsumeTraces(ctx, ptrace.NewTraces()))
+		assert.NoError(t, logs.ConsumeLogs(ctx, plog.NewLogs()))
+	}
+	assert.Len(t, tracesSink1.AllTraces(), 3)
+	assert.Len(t, tracesSink2.AllTraces(), 9)
+	assert.Len(t, logsSink1.AllLogs(), 4)
+	assert.Len(t, logsSink2.AllLogs(), 8)
+	assert.NoError(t, traces.Shutdown(ctx))
+	assert.NoError(t, logs.Shutdown(ctx))
+}
@@ -55,6 +55,14 @@ type Config struct {
 	// DatabaseNameAttributes is the attribute name list of attributes need to match used to identify the database name from span attributes, the higher the front, the higher the priority.
 	// The default value is {"db.name"}.
 	DatabaseNameAttributes []string `mapstructure:"database_name_attributes"`
+
+	// SpanLinks enables pairing the consumer spans with the producer spans they link to, in addition to their parent span.
+	// This builds the edges of batch consumers, whose span links to the producer spans of the messages of the batch.
+	SpanLinks bool `mapstructure:"span_links"`
+
+	// MessagingDimensions adds the messaging_system and messaging_destination dimensions to the edges across a messaging system.
+	// Their values are fetched from the messaging.system and messaging.destination.name attributes of the producer or consumer spans.
+	MessagingDimensions bool `mapstructure:"messaging_dimensions"`
 }
 
 type StoreConfig struct {
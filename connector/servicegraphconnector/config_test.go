@@ -38,6 +38,8 @@ func TestLoadConfig(t *testing.T) {
 			CacheLoop:             time.Minute,
 			StoreExpirationLoop:   2 * time.Second,
 			DatabaseNameAttribute: "db.name",
+			SpanLinks:             true,
+			MessagingDimensions:   true,
 		},
 		cfg.Connectors[component.NewID(metadata.Type)],
 	)
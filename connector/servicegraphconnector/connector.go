@@ -32,6 +32,8 @@ const (
 	clientKind         = "client"
 	serverKind         = "server"
 	virtualNodeLabel   = "virtual_node"
+	messagingSystem    = "messaging_system"
+	messagingDest      = "messaging_destination"
 	millisecondsUnit   = "ms"
 	secondsUnit        = "s"
 )
@@ -273,6 +275,10 @@ func (p *serviceGraphConnector) aggregateMetrics(ctx context.Context, td ptrace.
 							p.upsertPeerAttributes(p.config.VirtualNodePeerAttributes, e.Peer, span.Attributes())
 						}
 
+						if e.ConnectionType == store.MessagingSystem {
+							p.upsertMessagingAttributes(e, span.Attributes())
+						}
+
 						// A database request will only have one span, we don't wait for the server
 						// span but just copy details from the client span
 						if dbName, ok := getFirstMatchingValue(p.config.DatabaseNameAttributes, rAttributes, span.Attributes()); ok {
@@ -284,17 +290,20 @@ func (p *serviceGraphConnector) aggregateMetrics(ctx context.Context, td ptrace.
 				case ptrace.SpanKindConsumer:
 					// override connection type and continue processing as span kind server
 					connectionType = store.MessagingSystem
+					if p.config.SpanLinks && span.Links().Len() > 0 {
+						totalDroppedSpans += p.upsertLinkedEdges(ctx, serviceName, rAttributes, span)
+						// A consumer span without parent is only paired through its links
+						if span.ParentSpanID().IsEmpty() {
+							continue
+						}
+					}
 					fallthrough
 				case ptrace.SpanKindServer:
 					traceID := span.TraceID()
 					key := store.NewKey(traceID, span.ParentSpanID())
 					isNew, err = p.store.UpsertEdge(key, func(e *store.Edge) {
 						e.TraceID = traceID
-						e.ConnectionType = connectionType
-						e.ServerService = serviceName
-						e.ServerLatencySec = spanDuration(span)
-						e.Failed = e.Failed || span.Status().Code() == ptrace.StatusCodeError
-						p.upsertDimensions(serverKind, e.Dimensions, rAttributes, span.Attributes())
+						p.updateServerEdge(e, connectionType, serviceName, rAttributes, span)
 					})
 				default:
 					// this span is not part of an edge
@@ -321,6 +330,50 @@ func (p *serviceGraphConnector) aggregateMetrics(ctx context.Context, td ptrace.
 	return nil
 }
 
+// upsertLinkedEdges pairs the consumer span with the producer spans it links to, the link to its
+// parent span excepted as the consumer span is paired with its parent span already.
+// It returns the number of edges which were dropped because the store is full.
+func (p *serviceGraphConnector) upsertLinkedEdges(ctx context.Context, serviceName string, rAttributes pcommon.Map, span ptrace.Span) int {
+	var droppedSpans int
+	links := span.Links()
+	for l := 0; l < links.Len(); l++ {
+		link := links.At(l)
+		if link.SpanID().IsEmpty() || (link.TraceID() == span.TraceID() && link.SpanID() == span.ParentSpanID()) {
+			continue
+		}
+
+		traceID := link.TraceID()
+		key := store.NewKey(traceID, link.SpanID())
+		isNew, err := p.store.UpsertEdge(key, func(e *store.Edge) {
+			e.TraceID = traceID
+			p.updateServerEdge(e, store.MessagingSystem, serviceName, rAttributes, span)
+		})
+		if errors.Is(err, store.ErrTooManyItems) {
+			droppedSpans++
+			p.telemetryBuilder.ConnectorServicegraphDroppedSpans.Add(ctx, 1)
+			continue
+		}
+
+		if isNew {
+			p.telemetryBuilder.ConnectorServicegraphTotalEdges.Add(ctx, 1)
+		}
+	}
+	return droppedSpans
+}
+
+// updateServerEdge updates the Edge with the details of the server or consumer span.
+func (p *serviceGraphConnector) updateServerEdge(e *store.Edge, connectionType store.ConnectionType, serviceName string, rAttributes pcommon.Map, span ptrace.Span) {
+	e.ConnectionType = connectionType
+	e.ServerService = serviceName
+	e.ServerLatencySec = spanDuration(span)
+	e.Failed = e.Failed || span.Status().Code() == ptrace.StatusCodeError
+	p.upsertDimensions(serverKind, e.Dimensions, rAttributes, span.Attributes())
+
+	if e.ConnectionType == store.MessagingSystem {
+		p.upsertMessagingAttributes(e, span.Attributes())
+	}
+}
+
 func (p *serviceGraphConnector) upsertDimensions(kind string, m map[string]string, resourceAttr pcommon.Map, spanAttr pcommon.Map) {
 	for _, dim := range p.config.Dimensions {
 		if v, ok := pdatautil.GetAttributeValue(dim, resourceAttr, spanAttr); ok {
@@ -338,6 +391,20 @@ func (p *serviceGraphConnector) upsertPeerAttributes(m []string, peers map[strin
 	}
 }
 
+// upsertMessagingAttributes sets the messaging system and destination of the Edge, unless they
+// were set by its other span already.
+func (p *serviceGraphConnector) upsertMessagingAttributes(e *store.Edge, spanAttr pcommon.Map) {
+	if !p.config.MessagingDimensions {
+		return
+	}
+	if v, ok := spanAttr.Get(string(semconv.MessagingSystemKey)); ok && e.MessagingSystem == "" {
+		e.MessagingSystem = v.AsString()
+	}
+	if v, ok := spanAttr.Get(string(semconv.MessagingDestinationNameKey)); ok && e.MessagingDestination == "" {
+		e.MessagingDestination = v.AsString()
+	}
+}
+
 func (p *serviceGraphConnector) onComplete(e *store.Edge) {
 	p.logger.Debug(
 		"edge completed",
@@ -382,6 +449,9 @@ func (p *serviceGraphConnector) onExpire(e *store.Edge) {
 
 func (p *serviceGraphConnector) aggregateMetricsForEdge(e *store.Edge) {
 	metricKey := p.buildMetricKey(e.ClientService, e.ServerService, string(e.ConnectionType), strconv.FormatBool(e.Failed), e.Dimensions)
+	if e.MessagingSystem != "" || e.MessagingDestination != "" {
+		metricKey += metricKeySeparator + e.MessagingSystem + metricKeySeparator + e.MessagingDestination
+	}
 	dimensions := buildDimensions(e)
 
 	if p.config.VirtualNodeExtraLabel {
@@ -453,6 +523,12 @@ func buildDimensions(e *store.Edge) pcommon.Map {
 	dims.PutStr("server", e.ServerService)
 	dims.PutStr("connection_type", string(e.ConnectionType))
 	dims.PutBool("failed", e.Failed)
+	if e.MessagingSystem != "" {
+		dims.PutStr(messagingSystem, e.MessagingSystem)
+	}
+	if e.MessagingDestination != "" {
+		dims.PutStr(messagingDest, e.MessagingDestination)
+	}
 	for k, v := range e.Dimensions {
 		dims.PutStr(k, v)
 	}
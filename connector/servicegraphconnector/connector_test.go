@@ -733,6 +733,117 @@ func TestVirtualNodeClientLabels(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func buildBatchConsumerTrace() ptrace.Traces {
+	tStart := time.Date(2022, 1, 2, 3, 4, 5, 6, time.UTC)
+	tEnd := time.Date(2022, 1, 2, 3, 4, 6, 6, time.UTC)
+
+	traces := ptrace.NewTraces()
+
+	producerSpans := traces.ResourceSpans().AppendEmpty()
+	producerSpans.Resource().Attributes().PutStr(string(semconv.ServiceNameKey), "producer")
+	consumerSpans := traces.ResourceSpans().AppendEmpty()
+	consumerSpans.Resource().Attributes().PutStr(string(semconv.ServiceNameKey), "consumer")
+
+	// The consumer span of the batch is the root of its own trace and links to the producer spans of the messages.
+	consumerSpan := consumerSpans.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	consumerSpan.SetName("process orders")
+	consumerSpan.SetTraceID([16]byte{3})
+	consumerSpan.SetSpanID([8]byte{3})
+	consumerSpan.SetKind(ptrace.SpanKindConsumer)
+	consumerSpan.SetStartTimestamp(pcommon.NewTimestampFromTime(tStart))
+	consumerSpan.SetEndTimestamp(pcommon.NewTimestampFromTime(tEnd))
+	consumerSpan.Attributes().PutStr(string(semconv.MessagingSystemKey), "kafka")
+	consumerSpan.Attributes().PutStr(string(semconv.MessagingDestinationNameKey), "orders")
+
+	for i := byte(1); i <= 2; i++ {
+		producerSpan := producerSpans.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+		producerSpan.SetName("publish orders")
+		producerSpan.SetTraceID([16]byte{i})
+		producerSpan.SetSpanID([8]byte{i})
+		producerSpan.SetKind(ptrace.SpanKindProducer)
+		producerSpan.SetStartTimestamp(pcommon.NewTimestampFromTime(tStart))
+		producerSpan.SetEndTimestamp(pcommon.NewTimestampFromTime(tEnd))
+		producerSpan.Attributes().PutStr(string(semconv.MessagingSystemKey), "kafka")
+		producerSpan.Attributes().PutStr(string(semconv.MessagingDestinationNameKey), "orders")
+
+		link := consumerSpan.Links().AppendEmpty()
+		link.SetTraceID(producerSpan.TraceID())
+		link.SetSpanID(producerSpan.SpanID())
+	}
+
+	return traces
+}
+
+func TestSpanLinks(t *testing.T) {
+	for _, tc := range []struct {
+		name                string
+		spanLinks           bool
+		messagingDimensions bool
+		expectedAttributes  map[string]any
+		expectedStoreLen    int
+	}{
+		{
+			name:             "disabled",
+			expectedStoreLen: 3,
+		},
+		{
+			name:      "enabled",
+			spanLinks: true,
+			expectedAttributes: map[string]any{
+				"client":          "producer",
+				"server":          "consumer",
+				"connection_type": "messaging_system",
+				"failed":          false,
+			},
+		},
+		{
+			name:                "enabled with messaging dimensions",
+			spanLinks:           true,
+			messagingDimensions: true,
+			expectedAttributes: map[string]any{
+				"client":                "producer",
+				"server":                "consumer",
+				"connection_type":       "messaging_system",
+				"failed":                false,
+				"messaging_system":      "kafka",
+				"messaging_destination": "orders",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &Config{
+				Store:                StoreConfig{MaxItems: 10, TTL: time.Minute},
+				MetricsFlushInterval: ptr(0 * time.Millisecond),
+				SpanLinks:            tc.spanLinks,
+				MessagingDimensions:  tc.messagingDimensions,
+			}
+			set := componenttest.NewNopTelemetrySettings()
+			set.Logger = zaptest.NewLogger(t)
+			conn, err := newConnector(set, cfg, newMockMetricsExporter())
+			require.NoError(t, err)
+			require.NoError(t, conn.Start(context.Background(), componenttest.NewNopHost()))
+			defer func() { require.NoError(t, conn.Shutdown(context.Background())) }()
+
+			require.NoError(t, conn.ConsumeTraces(context.Background(), buildBatchConsumerTrace()))
+			assert.Equal(t, tc.expectedStoreLen, conn.store.Len())
+
+			metrics := conn.metricsConsumer.(*mockMetricsExporter).GetMetrics()
+			if tc.expectedAttributes == nil {
+				assert.Empty(t, metrics)
+				return
+			}
+			require.Len(t, metrics, 1)
+			m := metrics[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0)
+			require.Equal(t, "traces_service_graph_request_total", m.Name())
+			require.Equal(t, 1, m.Sum().DataPoints().Len())
+			dp := m.Sum().DataPoints().At(0)
+			// Each linked producer span makes a request to the consumer.
+			assert.Equal(t, int64(2), dp.IntValue())
+			assert.Equal(t, tc.expectedAttributes, dp.Attributes().AsRaw())
+		})
+	}
+}
+
 // ptr returns a pointer to the given value.
 func ptr[T any](value T) *T {
 	return &value
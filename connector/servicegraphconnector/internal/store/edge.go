@@ -50,6 +50,10 @@ type Edge struct {
 
 	// VirtualNodeLabel is an optional label to be added to the spans
 	VirtualNodeLabel VirtualNodeLabel
+
+	// MessagingSystem and MessagingDestination identify the messaging system
+	// and the destination of an Edge across a messaging system
+	MessagingSystem, MessagingDestination string
 }
 
 func newEdge(key Key, ttl time.Duration) *Edge {
@@ -6,6 +6,7 @@ package config // import "github.com/open-telemetry/opentelemetry-collector-cont
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/lightstep/go-expohisto/structure"
 	"go.opentelemetry.io/collector/component"
@@ -29,6 +30,10 @@ const (
 	// error of less than 5%.
 	// Ref: https://opentelemetry.io/docs/specs/otel/metrics/sdk/#base2-exponential-bucket-histogram-aggregation
 	defaultExponentialHistogramMaxSize = 160
+
+	// defaultGaugeFlushInterval is the default interval at which the gauges
+	// are produced.
+	defaultGaugeFlushInterval = 60 * time.Second
 )
 
 var defaultHistogramBuckets = []float64{
@@ -38,12 +43,18 @@ var defaultHistogramBuckets = []float64{
 var _ confmap.Unmarshaler = (*Config)(nil)
 
 // Config for the connector. Each configuration field describes the metrics
-// to produce from a specific signal.
+// to produce from a specific signal. The metrics are aggregated per consumed
+// batch and produced with the delta temporality, except the gauges which are
+// aggregated across batches until they are flushed, so the connector has no
+// state to persist across restarts.
 type Config struct {
 	Spans      []MetricInfo `mapstructure:"spans"`
 	Datapoints []MetricInfo `mapstructure:"datapoints"`
 	Logs       []MetricInfo `mapstructure:"logs"`
 	Profiles   []MetricInfo `mapstructure:"profiles"`
+	// GaugeFlushInterval is the interval at which the gauges, aggregated
+	// across the consumed batches, are produced. Defaults to 60s.
+	GaugeFlushInterval time.Duration `mapstructure:"gauge_flush_interval"`
 	// prevent unkeyed literal initialization
 	_ struct{}
 }
@@ -52,6 +63,9 @@ func (c *Config) Validate() error {
 	if len(c.Spans) == 0 && len(c.Datapoints) == 0 && len(c.Logs) == 0 && len(c.Profiles) == 0 {
 		return errors.New("no configuration provided, at least one should be specified")
 	}
+	if c.GaugeFlushInterval <= 0 {
+		return errors.New("gauge_flush_interval must be positive")
+	}
 	var multiError error // collect all errors at once
 	if len(c.Spans) > 0 {
 		parser, err := ottlspan.NewParser(
@@ -122,6 +136,9 @@ func (c *Config) Unmarshal(collectorCfg *confmap.Conf) error {
 	if err := collectorCfg.Unmarshal(c, confmap.WithIgnoreUnused()); err != nil {
 		return err
 	}
+	if c.GaugeFlushInterval == 0 {
+		c.GaugeFlushInterval = defaultGaugeFlushInterval
+	}
 	for i, info := range c.Spans {
 		info.ensureDefaults()
 		c.Spans[i] = info
@@ -163,6 +180,27 @@ type Sum struct {
 	Value string `mapstructure:"value"`
 }
 
+// GaugeAggregation defines how the values recorded for a gauge are
+// aggregated into a single datapoint.
+type GaugeAggregation string
+
+const (
+	// GaugeAggregationLast records the last value.
+	GaugeAggregationLast GaugeAggregation = "last"
+	// GaugeAggregationMin records the minimum value.
+	GaugeAggregationMin GaugeAggregation = "min"
+	// GaugeAggregationMax records the maximum value.
+	GaugeAggregationMax GaugeAggregation = "max"
+)
+
+// Gauge records the last, minimum or maximum of the values recorded across
+// the batches consumed by the connector since the gauges were last flushed.
+type Gauge struct {
+	Value string `mapstructure:"value"`
+	// Aggregation is one of `last`, `min` or `max`, defaults to `last`.
+	Aggregation GaugeAggregation `mapstructure:"aggregation"`
+}
+
 // MetricInfo defines the structure of the metric produced by the connector.
 type MetricInfo struct {
 	Name        string `mapstructure:"name"`
@@ -181,6 +219,7 @@ type MetricInfo struct {
 	Histogram            *Histogram            `mapstructure:"histogram"`
 	ExponentialHistogram *ExponentialHistogram `mapstructure:"exponential_histogram"`
 	Sum                  *Sum                  `mapstructure:"sum"`
+	Gauge                *Gauge                `mapstructure:"gauge"`
 	// prevent unkeyed literal initialization
 	_ struct{}
 }
@@ -197,6 +236,11 @@ func (mi *MetricInfo) ensureDefaults() {
 			mi.ExponentialHistogram.MaxSize = defaultExponentialHistogramMaxSize
 		}
 	}
+	if mi.Gauge != nil {
+		if mi.Gauge.Aggregation == "" {
+			mi.Gauge.Aggregation = GaugeAggregationLast
+		}
+	}
 }
 
 func (mi *MetricInfo) validateAttributes() error {
@@ -251,6 +295,20 @@ func (mi *MetricInfo) validateSum() error {
 	return nil
 }
 
+func (mi *MetricInfo) validateGauge() error {
+	if mi.Gauge != nil {
+		if mi.Gauge.Value == "" {
+			return errors.New("value must be defined for gauge metrics")
+		}
+		switch mi.Gauge.Aggregation {
+		case GaugeAggregationLast, GaugeAggregationMin, GaugeAggregationMax:
+		default:
+			return fmt.Errorf("invalid aggregation %q, must be one of last, min or max", mi.Gauge.Aggregation)
+		}
+	}
+	return nil
+}
+
 // validateMetricInfo is an utility method validate all supported metric
 // types defined for the metric info including any ottl expressions.
 func validateMetricInfo[K any](mi MetricInfo, parser ottl.Parser[K]) error {
@@ -266,6 +324,9 @@ func validateMetricInfo[K any](mi MetricInfo, parser ottl.Parser[K]) error {
 	if err := mi.validateSum(); err != nil {
 		return fmt.Errorf("sum validation failed: %w", err)
 	}
+	if err := mi.validateGauge(); err != nil {
+		return fmt.Errorf("gauge validation failed: %w", err)
+	}
 
 	// Exactly one metric should be defined. Also, validate OTTL expressions,
 	// note that, here we only evaluate if statements are valid. Check for
@@ -299,6 +360,12 @@ func validateMetricInfo[K any](mi MetricInfo, parser ottl.Parser[K]) error {
 			return fmt.Errorf("failed to parse value OTTL expression for summary: %w", err)
 		}
 	}
+	if mi.Gauge != nil {
+		metricsDefinedCount++
+		if _, err := parser.ParseValueExpression(mi.Gauge.Value); err != nil {
+			return fmt.Errorf("failed to parse value OTTL expression for gauge: %w", err)
+		}
+	}
 	if metricsDefinedCount != 1 {
 		return fmt.Errorf("exactly one of the metrics must be defined, %d found", metricsDefinedCount)
 	}
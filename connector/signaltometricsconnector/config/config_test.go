@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -90,6 +91,19 @@ func TestConfig(t *testing.T) {
 				fullErrorForSignal(t, "profiles", "sum validation failed"),
 			},
 		},
+		{
+			path: "invalid_gauge",
+			errorMsgs: []string{
+				fullErrorForSignal(t, "spans", "gauge validation failed: value must be defined for gauge metrics"),
+				fullErrorForSignal(t, "datapoints", `gauge validation failed: invalid aggregation "avg"`),
+				fullErrorForSignal(t, "logs", "gauge validation failed: value must be defined for gauge metrics"),
+				fullErrorForSignal(t, "profiles", `gauge validation failed: invalid aggregation "sum"`),
+			},
+		},
+		{
+			path:      "invalid_gauge_flush_interval",
+			errorMsgs: []string{"gauge_flush_interval must be positive"},
+		},
 		{
 			path: "multiple_metric",
 			errorMsgs: []string{
@@ -120,6 +134,7 @@ func TestConfig(t *testing.T) {
 		{
 			path: "valid_full",
 			expected: &Config{
+				GaugeFlushInterval: 30 * time.Second,
 				Spans: []MetricInfo{
 					{
 						Name:                      "span.exp_histogram",
@@ -196,6 +211,26 @@ func TestConfig(t *testing.T) {
 							Value: "1",
 						},
 					},
+					{
+						Name:        "log.gauge",
+						Description: "Gauge",
+						Unit:        "By",
+						Attributes:  []Attribute{{Key: "key.2"}},
+						Gauge: &Gauge{
+							Value:       `attributes["payload.size"]`,
+							Aggregation: GaugeAggregationLast,
+						},
+					},
+					{
+						Name:        "log.gauge.max",
+						Description: "Gauge",
+						Unit:        "By",
+						Attributes:  []Attribute{{Key: "key.2"}},
+						Gauge: &Gauge{
+							Value:       `attributes["payload.size"]`,
+							Aggregation: GaugeAggregationMax,
+						},
+					},
 				},
 				Profiles: []MetricInfo{
 					{
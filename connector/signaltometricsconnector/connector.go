@@ -6,6 +6,8 @@ package signaltometricsconnector // import "github.com/open-telemetry/openteleme
 import (
 	"context"
 	"fmt"
+	"sync"
+	"time"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
@@ -16,6 +18,7 @@ import (
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.uber.org/zap"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/signaltometricsconnector/config"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/signaltometricsconnector/internal/aggregator"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/signaltometricsconnector/internal/model"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottldatapoint"
@@ -34,8 +37,68 @@ type signalToMetrics struct {
 	logMetricDefs     []model.MetricDef[ottllog.TransformContext]
 	profileMetricDefs []model.MetricDef[ottlprofile.TransformContext]
 
-	component.StartFunc
-	component.ShutdownFunc
+	// gauges aggregates the gauges across batches until they are flushed,
+	// every gaugeFlushInterval. It is nil if no gauge is defined.
+	gauges             *aggregator.Gauges
+	gaugeFlushInterval time.Duration
+	shutdownCh         chan struct{}
+	wg                 sync.WaitGroup
+}
+
+// newGauges returns the gauges of the connector, or nil if none of the
+// metrics is a gauge.
+func newGauges(infos []config.MetricInfo) *aggregator.Gauges {
+	for _, info := range infos {
+		if info.Gauge != nil {
+			return aggregator.NewGauges()
+		}
+	}
+	return nil
+}
+
+func (sm *signalToMetrics) Start(context.Context, component.Host) error {
+	if sm.gauges == nil {
+		return nil
+	}
+	sm.shutdownCh = make(chan struct{})
+	sm.wg.Add(1)
+	go func() {
+		defer sm.wg.Done()
+		ticker := time.NewTicker(sm.gaugeFlushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := sm.flushGauges(context.Background()); err != nil {
+					sm.logger.Error("failed to flush gauges", zap.Error(err))
+				}
+			case <-sm.shutdownCh:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Shutdown flushes the gauges aggregated since the last flush.
+func (sm *signalToMetrics) Shutdown(ctx context.Context) error {
+	if sm.gauges == nil {
+		return nil
+	}
+	if sm.shutdownCh != nil {
+		close(sm.shutdownCh)
+		sm.wg.Wait()
+		sm.shutdownCh = nil
+	}
+	return sm.flushGauges(ctx)
+}
+
+func (sm *signalToMetrics) flushGauges(ctx context.Context) error {
+	gauges := sm.gauges.Flush(time.Now())
+	if gauges.ResourceMetrics().Len() == 0 {
+		return nil
+	}
+	return sm.next.ConsumeMetrics(ctx, gauges)
 }
 
 func (sm *signalToMetrics) Capabilities() consumer.Capabilities {
@@ -49,7 +112,7 @@ func (sm *signalToMetrics) ConsumeTraces(ctx context.Context, td ptrace.Traces)
 
 	processedMetrics := pmetric.NewMetrics()
 	processedMetrics.ResourceMetrics().EnsureCapacity(td.ResourceSpans().Len())
-	aggregator := aggregator.NewAggregator[ottlspan.TransformContext](processedMetrics)
+	aggregator := aggregator.NewAggregator[ottlspan.TransformContext](processedMetrics, sm.gauges)
 
 	for i := 0; i < td.ResourceSpans().Len(); i++ {
 		resourceSpan := td.ResourceSpans().At(i)
@@ -88,6 +151,10 @@ func (sm *signalToMetrics) ConsumeTraces(ctx context.Context, td ptrace.Traces)
 		}
 	}
 	aggregator.Finalize(sm.spanMetricDefs)
+	if processedMetrics.ResourceMetrics().Len() == 0 {
+		// Nothing to produce, e.g. only gauges are defined.
+		return nil
+	}
 	return sm.next.ConsumeMetrics(ctx, processedMetrics)
 }
 
@@ -98,7 +165,7 @@ func (sm *signalToMetrics) ConsumeMetrics(ctx context.Context, m pmetric.Metrics
 
 	processedMetrics := pmetric.NewMetrics()
 	processedMetrics.ResourceMetrics().EnsureCapacity(m.ResourceMetrics().Len())
-	aggregator := aggregator.NewAggregator[ottldatapoint.TransformContext](processedMetrics)
+	aggregator := aggregator.NewAggregator[ottldatapoint.TransformContext](processedMetrics, sm.gauges)
 	for i := 0; i < m.ResourceMetrics().Len(); i++ {
 		resourceMetric := m.ResourceMetrics().At(i)
 		resourceAttrs := resourceMetric.Resource().Attributes()
@@ -196,6 +263,10 @@ func (sm *signalToMetrics) ConsumeMetrics(ctx context.Context, m pmetric.Metrics
 		}
 	}
 	aggregator.Finalize(sm.dpMetricDefs)
+	if processedMetrics.ResourceMetrics().Len() == 0 {
+		// Nothing to produce, e.g. only gauges are defined.
+		return nil
+	}
 	return sm.next.ConsumeMetrics(ctx, processedMetrics)
 }
 
@@ -206,7 +277,7 @@ func (sm *signalToMetrics) ConsumeLogs(ctx context.Context, logs plog.Logs) erro
 
 	processedMetrics := pmetric.NewMetrics()
 	processedMetrics.ResourceMetrics().EnsureCapacity(logs.ResourceLogs().Len())
-	aggregator := aggregator.NewAggregator[ottllog.TransformContext](processedMetrics)
+	aggregator := aggregator.NewAggregator[ottllog.TransformContext](processedMetrics, sm.gauges)
 	for i := 0; i < logs.ResourceLogs().Len(); i++ {
 		resourceLog := logs.ResourceLogs().At(i)
 		resourceAttrs := resourceLog.Resource().Attributes()
@@ -243,6 +314,10 @@ func (sm *signalToMetrics) ConsumeLogs(ctx context.Context, logs plog.Logs) erro
 		}
 	}
 	aggregator.Finalize(sm.logMetricDefs)
+	if processedMetrics.ResourceMetrics().Len() == 0 {
+		// Nothing to produce, e.g. only gauges are defined.
+		return nil
+	}
 	return sm.next.ConsumeMetrics(ctx, processedMetrics)
 }
 
@@ -253,7 +328,7 @@ func (sm *signalToMetrics) ConsumeProfiles(ctx context.Context, profiles pprofil
 
 	processedMetrics := pmetric.NewMetrics()
 	processedMetrics.ResourceMetrics().EnsureCapacity(profiles.ResourceProfiles().Len())
-	aggregator := aggregator.NewAggregator[ottlprofile.TransformContext](processedMetrics)
+	aggregator := aggregator.NewAggregator[ottlprofile.TransformContext](processedMetrics, sm.gauges)
 
 	for i := 0; i < profiles.ResourceProfiles().Len(); i++ {
 		resourceProfile := profiles.ResourceProfiles().At(i)
@@ -294,5 +369,9 @@ func (sm *signalToMetrics) ConsumeProfiles(ctx context.Context, profiles pprofil
 		}
 	}
 	aggregator.Finalize(sm.profileMetricDefs)
+	if processedMetrics.ResourceMetrics().Len() == 0 {
+		// Nothing to produce, e.g. only gauges are defined.
+		return nil
+	}
 	return sm.next.ConsumeMetrics(ctx, processedMetrics)
 }
@@ -7,10 +7,12 @@ import (
 	"context"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/confmap"
 	"go.opentelemetry.io/collector/confmap/confmaptest"
 	"go.opentelemetry.io/collector/confmap/xconfmap"
@@ -20,6 +22,7 @@ import (
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 	"go.uber.org/zap/zapcore"
@@ -101,6 +104,7 @@ func TestConnectorWithMetrics(t *testing.T) {
 func TestConnectorWithLogs(t *testing.T) {
 	testCases := []string{
 		"sum",
+		"gauge",
 		"histograms",
 		"exponential_histograms",
 		"metric_identity",
@@ -125,6 +129,8 @@ func TestConnectorWithLogs(t *testing.T) {
 			require.NoError(t, err)
 
 			require.NoError(t, connector.ConsumeLogs(ctx, inputLogs))
+			// The gauges are produced when flushed, the remaining ones on shutdown.
+			require.NoError(t, connector.Shutdown(ctx))
 			require.Len(t, next.AllMetrics(), 1)
 			assertAggregatedMetrics(t, expectedMetrics, next.AllMetrics()[0])
 		})
@@ -165,6 +171,135 @@ func TestConnectorWithProfiles(t *testing.T) {
 	}
 }
 
+func TestConnectorGaugesAcrossBatches(t *testing.T) {
+	ctx := context.Background()
+	next := &consumertest.MetricsSink{}
+	cfg := &config.Config{
+		Logs: []config.MetricInfo{
+			{
+				Name:       "queue.depth.last",
+				Attributes: []config.Attribute{{Key: "queue.name"}},
+				Gauge:      &config.Gauge{Value: `attributes["queue.depth"]`},
+			},
+			{
+				Name:       "queue.depth.min",
+				Attributes: []config.Attribute{{Key: "queue.name"}},
+				Gauge:      &config.Gauge{Value: `attributes["queue.depth"]`, Aggregation: config.GaugeAggregationMin},
+			},
+			{
+				Name:       "queue.depth.max",
+				Attributes: []config.Attribute{{Key: "queue.name"}},
+				Gauge:      &config.Gauge{Value: `attributes["queue.depth"]`, Aggregation: config.GaugeAggregationMax},
+			},
+		},
+		GaugeFlushInterval: time.Hour,
+	}
+	require.NoError(t, cfg.Unmarshal(confmap.New())) // set required fields to default
+	require.NoError(t, cfg.Validate())
+	c, err := NewFactory().(xconnector.Factory).CreateLogsToMetrics(ctx, connectortest.NewNopSettings(metadata.Type), cfg, next)
+	require.NoError(t, err)
+	require.NoError(t, c.Start(ctx, componenttest.NewNopHost()))
+	sm := c.(*signalToMetrics)
+
+	batch := func(depths map[string][]int64) plog.Logs {
+		logs := plog.NewLogs()
+		records := logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+		for _, queue := range []string{"a", "b"} {
+			for _, depth := range depths[queue] {
+				record := records.AppendEmpty()
+				record.Attributes().PutStr("queue.name", queue)
+				record.Attributes().PutInt("queue.depth", depth)
+			}
+		}
+		return logs
+	}
+	// gauges returns the values of the datapoints of the flushed gauges per
+	// gauge and queue.
+	gauges := func(t *testing.T, md pmetric.Metrics) map[string]map[string]int64 {
+		t.Helper()
+		values := map[string]map[string]int64{}
+		require.Equal(t, 1, md.ResourceMetrics().Len())
+		metrics := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+		for i := 0; i < metrics.Len(); i++ {
+			metric := metrics.At(i)
+			require.Equal(t, pmetric.MetricTypeGauge, metric.Type())
+			values[metric.Name()] = map[string]int64{}
+			dps := metric.Gauge().DataPoints()
+			for j := 0; j < dps.Len(); j++ {
+				queue, ok := dps.At(j).Attributes().Get("queue.name")
+				require.True(t, ok)
+				values[metric.Name()][queue.Str()] = dps.At(j).IntValue()
+			}
+		}
+		return values
+	}
+
+	// The gauges are aggregated across the batches consumed until the flush.
+	require.NoError(t, c.ConsumeLogs(ctx, batch(map[string][]int64{"a": {5, 2}, "b": {7}})))
+	require.NoError(t, c.ConsumeLogs(ctx, batch(map[string][]int64{"a": {9, 4}})))
+	require.NoError(t, c.ConsumeLogs(ctx, batch(map[string][]int64{"a": {3}, "b": {1, 6}})))
+	assert.Empty(t, next.AllMetrics())
+	require.NoError(t, sm.flushGauges(ctx))
+	require.Len(t, next.AllMetrics(), 1)
+	assert.Equal(t, map[string]map[string]int64{
+		"queue.depth.last": {"a": 3, "b": 6},
+		"queue.depth.min":  {"a": 2, "b": 1},
+		"queue.depth.max":  {"a": 9, "b": 7},
+	}, gauges(t, next.AllMetrics()[0]))
+
+	// The next interval only aggregates the values recorded since the flush.
+	require.NoError(t, c.ConsumeLogs(ctx, batch(map[string][]int64{"a": {8}})))
+	require.NoError(t, c.ConsumeLogs(ctx, batch(map[string][]int64{"a": {1}})))
+	require.NoError(t, sm.flushGauges(ctx))
+	require.Len(t, next.AllMetrics(), 2)
+	assert.Equal(t, map[string]map[string]int64{
+		"queue.depth.last": {"a": 1},
+		"queue.depth.min":  {"a": 1},
+		"queue.depth.max":  {"a": 8},
+	}, gauges(t, next.AllMetrics()[1]))
+
+	// Nothing is produced if nothing was recorded since the flush.
+	require.NoError(t, sm.flushGauges(ctx))
+	require.Len(t, next.AllMetrics(), 2)
+
+	// The gauges recorded since the last flush are flushed on shutdown.
+	require.NoError(t, c.ConsumeLogs(ctx, batch(map[string][]int64{"b": {4}})))
+	require.NoError(t, c.Shutdown(ctx))
+	require.Len(t, next.AllMetrics(), 3)
+	assert.Equal(t, map[string]map[string]int64{
+		"queue.depth.last": {"b": 4},
+		"queue.depth.min":  {"b": 4},
+		"queue.depth.max":  {"b": 4},
+	}, gauges(t, next.AllMetrics()[2]))
+}
+
+func TestConnectorGaugeFlushInterval(t *testing.T) {
+	ctx := context.Background()
+	next := &consumertest.MetricsSink{}
+	cfg := &config.Config{
+		Logs: []config.MetricInfo{
+			{
+				Name:  "log.duration",
+				Gauge: &config.Gauge{Value: `attributes["log.duration"]`},
+			},
+		},
+		GaugeFlushInterval: 10 * time.Millisecond,
+	}
+	require.NoError(t, cfg.Unmarshal(confmap.New())) // set required fields to default
+	c, err := NewFactory().(xconnector.Factory).CreateLogsToMetrics(ctx, connectortest.NewNopSettings(metadata.Type), cfg, next)
+	require.NoError(t, err)
+	require.NoError(t, c.Start(ctx, componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, c.Shutdown(ctx)) })
+
+	inputLogs, err := golden.ReadLogs(filepath.Join(testDataDir, "logs", "logs.yaml"))
+	require.NoError(t, err)
+	require.NoError(t, c.ConsumeLogs(ctx, inputLogs))
+	assert.Eventually(t, func() bool {
+		return len(next.AllMetrics()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, 1, next.AllMetrics()[0].MetricCount())
+}
+
 func BenchmarkConnectorWithTraces(b *testing.B) {
 	factory := NewFactory()
 	settings := connectortest.NewNopSettings(metadata.Type)
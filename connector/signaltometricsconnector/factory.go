@@ -64,8 +64,10 @@ func createTracesToMetrics(
 		collectorInstanceInfo: model.NewCollectorInstanceInfo(
 			set.TelemetrySettings,
 		),
-		next:           nextConsumer,
-		spanMetricDefs: metricDefs,
+		next:               nextConsumer,
+		spanMetricDefs:     metricDefs,
+		gauges:             newGauges(c.Spans),
+		gaugeFlushInterval: c.GaugeFlushInterval,
 	}, nil
 }
 
@@ -95,8 +97,10 @@ func createMetricsToMetrics(
 		collectorInstanceInfo: model.NewCollectorInstanceInfo(
 			set.TelemetrySettings,
 		),
-		next:         nextConsumer,
-		dpMetricDefs: metricDefs,
+		next:               nextConsumer,
+		dpMetricDefs:       metricDefs,
+		gauges:             newGauges(c.Datapoints),
+		gaugeFlushInterval: c.GaugeFlushInterval,
 	}, nil
 }
 
@@ -126,8 +130,10 @@ func createLogsToMetrics(
 		collectorInstanceInfo: model.NewCollectorInstanceInfo(
 			set.TelemetrySettings,
 		),
-		next:          nextConsumer,
-		logMetricDefs: metricDefs,
+		next:               nextConsumer,
+		logMetricDefs:      metricDefs,
+		gauges:             newGauges(c.Logs),
+		gaugeFlushInterval: c.GaugeFlushInterval,
 	}, nil
 }
 
@@ -157,7 +163,9 @@ func createProfilesToMetrics(
 		collectorInstanceInfo: model.NewCollectorInstanceInfo(
 			set.TelemetrySettings,
 		),
-		next:              nextConsumer,
-		profileMetricDefs: metricDefs,
+		next:               nextConsumer,
+		profileMetricDefs:  metricDefs,
+		gauges:             newGauges(c.Profiles),
+		gaugeFlushInterval: c.GaugeFlushInterval,
 	}, nil
 }
@@ -27,17 +27,28 @@ type Aggregator[K any] struct {
 	smLookup    map[[16]byte]pmetric.ScopeMetrics
 	valueCounts map[model.MetricKey]map[[16]byte]map[[16]byte]*valueCountDP
 	sums        map[model.MetricKey]map[[16]byte]map[[16]byte]*sumDP
-	timestamp   time.Time
+	gauges      map[model.MetricKey]map[[16]byte]map[[16]byte]*gaugeDP
+	// gaugeResources maps resourceID against the resource attributes of the
+	// gauges, which are not produced with the result but merged into the
+	// gauges aggregated across batches.
+	gaugeResources map[[16]byte]pcommon.Map
+	gaugeStore     *Gauges
+	timestamp      time.Time
 }
 
-// NewAggregator creates a new instance of aggregator.
-func NewAggregator[K any](metrics pmetric.Metrics) *Aggregator[K] {
+// NewAggregator creates a new instance of aggregator. The values recorded
+// for the gauges are merged into the given gauges when finalizing, they
+// can be nil if no gauge is defined.
+func NewAggregator[K any](metrics pmetric.Metrics, gauges *Gauges) *Aggregator[K] {
 	return &Aggregator[K]{
-		result:      metrics,
-		smLookup:    make(map[[16]byte]pmetric.ScopeMetrics),
-		valueCounts: make(map[model.MetricKey]map[[16]byte]map[[16]byte]*valueCountDP),
-		sums:        make(map[model.MetricKey]map[[16]byte]map[[16]byte]*sumDP),
-		timestamp:   time.Now(),
+		result:         metrics,
+		smLookup:       make(map[[16]byte]pmetric.ScopeMetrics),
+		valueCounts:    make(map[model.MetricKey]map[[16]byte]map[[16]byte]*valueCountDP),
+		sums:           make(map[model.MetricKey]map[[16]byte]map[[16]byte]*sumDP),
+		gauges:         make(map[model.MetricKey]map[[16]byte]map[[16]byte]*gaugeDP),
+		gaugeResources: make(map[[16]byte]pcommon.Map),
+		gaugeStore:     gauges,
+		timestamp:      time.Now(),
 	}
 }
 
@@ -87,6 +98,22 @@ func (a *Aggregator[K]) Aggregate(
 				v, v,
 			)
 		}
+	case pmetric.MetricTypeGauge:
+		raw, err := md.Gauge.Value.Eval(ctx, tCtx)
+		if err != nil {
+			return fmt.Errorf("failed to execute OTTL value for gauge: %w", err)
+		}
+		switch v := raw.(type) {
+		case int64:
+			a.getGaugeDP(md, resAttrs, srcAttrs).AggregateInt(v)
+		case float64:
+			a.getGaugeDP(md, resAttrs, srcAttrs).AggregateDouble(v)
+		default:
+			return fmt.Errorf(
+				"failed to parse gauge OTTL value of type %T into int64 or float64: %v",
+				v, v,
+			)
+		}
 	}
 	return nil
 }
@@ -145,11 +172,19 @@ func (a *Aggregator[K]) Finalize(mds []model.MetricDef[K]) {
 				dp.Copy(a.timestamp, destCounter.DataPoints().AppendEmpty())
 			}
 		}
+		// The gauges are aggregated across batches until they are flushed.
+		for resID, dpMap := range a.gauges[md.Key] {
+			if md.Gauge == nil {
+				continue
+			}
+			a.gaugeStore.merge(md.Key, resID, a.gaugeResources[resID], dpMap)
+		}
 		// If there are two metric defined with the same key required by metricKey
 		// then they will be aggregated within the same metric and produced
 		// together. Deleting the key ensures this while preventing duplicates.
 		delete(a.valueCounts, md.Key)
 		delete(a.sums, md.Key)
+		delete(a.gauges, md.Key)
 	}
 }
 
@@ -193,6 +228,29 @@ func (a *Aggregator[K]) aggregateDouble(
 	return nil
 }
 
+func (a *Aggregator[K]) getGaugeDP(
+	md model.MetricDef[K],
+	resAttrs, srcAttrs pcommon.Map,
+) *gaugeDP {
+	// The resource of the gauges isn't added to the result as the gauges are
+	// produced when flushed.
+	resID := pdatautil.MapHash(resAttrs)
+	if _, ok := a.gaugeResources[resID]; !ok {
+		a.gaugeResources[resID] = resAttrs
+	}
+	attrID := pdatautil.MapHash(srcAttrs)
+	if _, ok := a.gauges[md.Key]; !ok {
+		a.gauges[md.Key] = make(map[[16]byte]map[[16]byte]*gaugeDP)
+	}
+	if _, ok := a.gauges[md.Key][resID]; !ok {
+		a.gauges[md.Key][resID] = make(map[[16]byte]*gaugeDP)
+	}
+	if _, ok := a.gauges[md.Key][resID][attrID]; !ok {
+		a.gauges[md.Key][resID][attrID] = newGaugeDP(srcAttrs, md.Gauge.Aggregation)
+	}
+	return a.gauges[md.Key][resID][attrID]
+}
+
 func (a *Aggregator[K]) aggregateValueCount(
 	md model.MetricDef[K],
 	resAttrs, srcAttrs pcommon.Map,
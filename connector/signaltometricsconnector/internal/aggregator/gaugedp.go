@@ -0,0 +1,94 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package aggregator // import "github.com/open-telemetry/opentelemetry-collector-contrib/connector/signaltometricsconnector/internal/aggregator"
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/signaltometricsconnector/config"
+)
+
+// gaugeDP records the last, minimum or maximum of the values (supports all
+// event types). The datapoint holds an integer value until a double value
+// is recorded, from then on the values are aggregated as doubles.
+type gaugeDP struct {
+	attrs       pcommon.Map
+	aggregation config.GaugeAggregation
+
+	hasVal bool
+	isDbl  bool
+	intVal int64
+	dblVal float64
+}
+
+func newGaugeDP(attrs pcommon.Map, aggregation config.GaugeAggregation) *gaugeDP {
+	return &gaugeDP{
+		attrs:       attrs,
+		aggregation: aggregation,
+	}
+}
+
+func (dp *gaugeDP) AggregateInt(v int64) {
+	if dp.isDbl {
+		dp.AggregateDouble(float64(v))
+		return
+	}
+	if !dp.hasVal || dp.replaces(float64(v), float64(dp.intVal)) {
+		dp.intVal = v
+	}
+	dp.hasVal = true
+}
+
+func (dp *gaugeDP) AggregateDouble(v float64) {
+	if !dp.isDbl {
+		dp.isDbl = true
+		dp.dblVal = float64(dp.intVal)
+	}
+	if !dp.hasVal || dp.replaces(v, dp.dblVal) {
+		dp.dblVal = v
+	}
+	dp.hasVal = true
+}
+
+// merge aggregates the value of another datapoint, e.g. recorded for a
+// later batch, as per the aggregation of the datapoint.
+func (dp *gaugeDP) merge(other *gaugeDP) {
+	if !other.hasVal {
+		return
+	}
+	if other.isDbl {
+		dp.AggregateDouble(other.dblVal)
+	} else {
+		dp.AggregateInt(other.intVal)
+	}
+}
+
+// replaces reports whether the value v replaces the current value as per the
+// aggregation of the datapoint.
+func (dp *gaugeDP) replaces(v, current float64) bool {
+	switch dp.aggregation {
+	case config.GaugeAggregationMin:
+		return v < current
+	case config.GaugeAggregationMax:
+		return v > current
+	default:
+		return true
+	}
+}
+
+func (dp *gaugeDP) Copy(
+	timestamp time.Time,
+	dest pmetric.NumberDataPoint,
+) {
+	dp.attrs.CopyTo(dest.Attributes())
+	if dp.isDbl {
+		dest.SetDoubleValue(dp.dblVal)
+	} else {
+		dest.SetIntValue(dp.intVal)
+	}
+	dest.SetTimestamp(pcommon.NewTimestampFromTime(timestamp))
+}
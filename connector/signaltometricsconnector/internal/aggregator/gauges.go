@@ -0,0 +1,105 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package aggregator // import "github.com/open-telemetry/opentelemetry-collector-contrib/connector/signaltometricsconnector/internal/aggregator"
+
+import (
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/signaltometricsconnector/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/signaltometricsconnector/internal/model"
+)
+
+// Gauges aggregates the values recorded for the gauges across the batches
+// consumed by the connector, until they are flushed. It is safe for
+// concurrent use.
+type Gauges struct {
+	mu sync.Mutex
+	// keys are the keys of the gauges in the order they were first
+	// recorded, so that the flushed metrics are in a stable order.
+	keys      []model.MetricKey
+	resources map[[16]byte]pcommon.Map
+	dps       map[model.MetricKey]map[[16]byte]map[[16]byte]*gaugeDP
+}
+
+// NewGauges creates a new instance of gauges.
+func NewGauges() *Gauges {
+	return &Gauges{
+		resources: make(map[[16]byte]pcommon.Map),
+		dps:       make(map[model.MetricKey]map[[16]byte]map[[16]byte]*gaugeDP),
+	}
+}
+
+// merge aggregates the datapoints of a gauge recorded for a batch into the
+// datapoints recorded since the last flush.
+func (g *Gauges) merge(
+	key model.MetricKey,
+	resID [16]byte,
+	resAttrs pcommon.Map,
+	dpMap map[[16]byte]*gaugeDP,
+) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if _, ok := g.resources[resID]; !ok {
+		attrs := pcommon.NewMap()
+		resAttrs.CopyTo(attrs)
+		g.resources[resID] = attrs
+	}
+	if _, ok := g.dps[key]; !ok {
+		g.dps[key] = make(map[[16]byte]map[[16]byte]*gaugeDP)
+		g.keys = append(g.keys, key)
+	}
+	if _, ok := g.dps[key][resID]; !ok {
+		g.dps[key][resID] = make(map[[16]byte]*gaugeDP)
+	}
+	for attrID, dp := range dpMap {
+		if _, ok := g.dps[key][resID][attrID]; !ok {
+			attrs := pcommon.NewMap()
+			dp.attrs.CopyTo(attrs)
+			g.dps[key][resID][attrID] = newGaugeDP(attrs, dp.aggregation)
+		}
+		g.dps[key][resID][attrID].merge(dp)
+	}
+}
+
+// Flush returns the gauges recorded since the last flush, with a datapoint
+// timestamped at the given time per set of attributes, and resets them.
+// The returned metrics are empty if no value was recorded.
+func (g *Gauges) Flush(timestamp time.Time) pmetric.Metrics {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	result := pmetric.NewMetrics()
+	smLookup := make(map[[16]byte]pmetric.ScopeMetrics)
+	for _, key := range g.keys {
+		for resID, dpMap := range g.dps[key] {
+			sm, ok := smLookup[resID]
+			if !ok {
+				destResourceMetric := result.ResourceMetrics().AppendEmpty()
+				g.resources[resID].CopyTo(destResourceMetric.Resource().Attributes())
+				sm = destResourceMetric.ScopeMetrics().AppendEmpty()
+				sm.Scope().SetName(metadata.ScopeName)
+				smLookup[resID] = sm
+			}
+			destMetric := sm.Metrics().AppendEmpty()
+			destMetric.SetName(key.Name)
+			destMetric.SetUnit(key.Unit)
+			destMetric.SetDescription(key.Description)
+			destGauge := destMetric.SetEmptyGauge()
+			destGauge.DataPoints().EnsureCapacity(len(dpMap))
+			for _, dp := range dpMap {
+				dp.Copy(timestamp, destGauge.DataPoints().AppendEmpty())
+			}
+		}
+	}
+
+	g.keys = nil
+	g.resources = make(map[[16]byte]pcommon.Map)
+	g.dps = make(map[model.MetricKey]map[[16]byte]map[[16]byte]*gaugeDP)
+	return result
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"time"
 
+	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/confmap/xconfmap"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 
@@ -92,6 +93,16 @@ type Config struct {
 	IncludeInstrumentationScope []string `mapstructure:"include_instrumentation_scope"`
 
 	AggregationCardinalityLimit int `mapstructure:"aggregation_cardinality_limit"`
+
+	// Storage is the ID of the storage extension the state of the cumulative metrics is snapshotted to, and
+	// restored from on start, so that the counters and their start timestamps survive restarts.
+	// Optional, the state is kept in memory only by default.
+	Storage *component.ID `mapstructure:"storage"`
+
+	// StateSnapshotInterval is the minimum time period between two snapshots of the state. The state is snapshotted
+	// when the metrics are flushed, once this period elapsed since the last snapshot, and on shutdown.
+	// Default value (0) means that the state is snapshotted every time the metrics are flushed.
+	StateSnapshotInterval time.Duration `mapstructure:"state_snapshot_interval"`
 }
 
 type HistogramConfig struct {
@@ -159,6 +170,14 @@ func (c Config) Validate() error {
 		return fmt.Errorf("invalid aggregation_cardinality_limit: %v, the limit should be positive", c.AggregationCardinalityLimit)
 	}
 
+	if c.StateSnapshotInterval < 0 {
+		return fmt.Errorf("invalid state_snapshot_interval: %v, the duration should be positive", c.StateSnapshotInterval)
+	}
+
+	if c.Storage != nil && c.GetAggregationTemporality() == pmetric.AggregationTemporalityDelta {
+		return errors.New("storage is only supported with the cumulative aggregation temporality")
+	}
+
 	return nil
 }
 
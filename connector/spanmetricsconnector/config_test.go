@@ -30,6 +30,7 @@ func TestLoadConfig(t *testing.T) {
 	defaultMethod := http.MethodGet
 	defaultMaxPerDatapoint := 5
 	customTimestampCacheSize := 123
+	fileStorageID := component.MustNewID("file_storage")
 	tests := []struct {
 		id              component.ID
 		expected        component.Config
@@ -174,6 +175,26 @@ func TestLoadConfig(t *testing.T) {
 				Namespace:                DefaultNamespace,
 			},
 		},
+		{
+			id: component.NewIDWithName(metadata.Type, "storage"),
+			expected: &Config{
+				AggregationTemporality:   "AGGREGATION_TEMPORALITY_CUMULATIVE",
+				ResourceMetricsCacheSize: defaultResourceMetricsCacheSize,
+				MetricsFlushInterval:     60 * time.Second,
+				Histogram:                HistogramConfig{Disable: false, Unit: defaultUnit},
+				Namespace:                DefaultNamespace,
+				Storage:                  &fileStorageID,
+				StateSnapshotInterval:    5 * time.Minute,
+			},
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "invalid_state_snapshot_interval"),
+			errorMessage: "invalid state_snapshot_interval: -5m0s, the duration should be positive",
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "storage_with_delta_temporality"),
+			errorMessage: "storage is only supported with the cumulative aggregation temporality",
+		},
 	}
 
 	for _, tt := range tests {
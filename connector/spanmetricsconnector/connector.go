@@ -14,6 +14,7 @@ import (
 	"github.com/lightstep/go-expohisto/structure"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/extension/xextension/storage"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
@@ -50,6 +51,7 @@ const (
 
 type connectorImp struct {
 	lock   sync.Mutex
+	id     component.ID
 	logger *zap.Logger
 	config Config
 
@@ -84,6 +86,10 @@ type connectorImp struct {
 
 	// Tracks the last TimestampUnixNano for delta metrics so that they represent an uninterrupted series. Unused for cumulative span metrics.
 	lastDeltaTimestamps *simplelru.LRU[metrics.Key, pcommon.Timestamp]
+
+	// storageClient persists the state of the metrics, nil unless a storage is configured.
+	storageClient storage.Client
+	lastSnapshot  time.Time
 }
 
 type resourceMetrics struct {
@@ -203,9 +209,20 @@ func durationsToUnits(vs []time.Duration, unitDivider int64) []float64 {
 }
 
 // Start implements the component.Component interface.
-func (p *connectorImp) Start(ctx context.Context, _ component.Host) error {
+func (p *connectorImp) Start(ctx context.Context, host component.Host) error {
 	p.logger.Info("Starting spanmetrics connector")
 
+	if p.config.Storage != nil {
+		client, err := getStorageClient(ctx, host, p.config.Storage, p.id)
+		if err != nil {
+			return err
+		}
+		p.storageClient = client
+		if err := p.loadState(ctx); err != nil {
+			return err
+		}
+	}
+
 	p.started = true
 	go func() {
 		for {
@@ -222,7 +239,8 @@ func (p *connectorImp) Start(ctx context.Context, _ component.Host) error {
 }
 
 // Shutdown implements the component.Component interface.
-func (p *connectorImp) Shutdown(context.Context) error {
+func (p *connectorImp) Shutdown(ctx context.Context) error {
+	var err error
 	p.shutdownOnce.Do(func() {
 		p.logger.Info("Shutting down spanmetrics connector")
 		if p.started {
@@ -231,8 +249,19 @@ func (p *connectorImp) Shutdown(context.Context) error {
 			p.done <- struct{}{}
 			p.started = false
 		}
+		if p.storageClient != nil {
+			p.lock.Lock()
+			data, encodeErr := p.encodeState()
+			p.lock.Unlock()
+			if encodeErr != nil {
+				p.logger.Error("Failed to encode the state", zap.Error(encodeErr))
+			} else {
+				p.storeState(ctx, data)
+			}
+			err = p.storageClient.Close(ctx)
+		}
 	})
-	return nil
+	return err
 }
 
 // Capabilities implements the consumer interface.
@@ -254,10 +283,15 @@ func (p *connectorImp) exportMetrics(ctx context.Context) {
 
 	m := p.buildMetrics()
 	p.resetState()
+	snapshot := p.snapshotState()
 
 	// This component no longer needs to read the metrics once built, so it is safe to unlock.
 	p.lock.Unlock()
 
+	if snapshot != nil {
+		p.storeState(ctx, snapshot)
+	}
+
 	if err := p.metricsConsumer.ConsumeMetrics(ctx, m); err != nil {
 		p.logger.Error("Failed ConsumeMetrics", zap.Error(err))
 		return
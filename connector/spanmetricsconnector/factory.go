@@ -60,6 +60,7 @@ func createTracesToMetricsConnector(ctx context.Context, params connector.Settin
 	if err != nil {
 		return nil, err
 	}
+	c.id = params.ID
 	c.metricsConsumer = nextConsumer
 	return c, nil
 }
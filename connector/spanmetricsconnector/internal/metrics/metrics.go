@@ -20,6 +20,8 @@ type HistogramMetrics interface {
 	GetOrCreate(key Key, attributesFun BuildAttributesFun, startTimestamp pcommon.Timestamp) (Histogram, bool)
 	BuildMetrics(pmetric.Metric, pcommon.Timestamp, func(Key, pcommon.Timestamp) pcommon.Timestamp, pmetric.AggregationTemporality)
 	ClearExemplars()
+	State() map[Key]HistogramState
+	Restore(map[Key]HistogramState)
 }
 
 type Histogram interface {
@@ -61,6 +63,9 @@ type exponentialHistogram struct {
 	exemplars  pmetric.ExemplarSlice
 
 	histogram *structure.Histogram[float64]
+	// sumOffset corrects the sum of a histogram restored from its state,
+	// see restoreExponentialHistogram.
+	sumOffset float64
 
 	maxExemplarCount *int
 
@@ -215,6 +220,7 @@ func (m *exponentialHistogramMetrics) BuildMetrics(
 		dp.SetStartTimestamp(startTimestamp)
 		dp.SetTimestamp(timestamp)
 		expoHistToExponentialDataPoint(e.histogram, dp)
+		dp.SetSum(dp.Sum() + e.sumOffset)
 		for i := 0; i < e.exemplars.Len(); i++ {
 			e.exemplars.At(i).SetTimestamp(timestamp)
 		}
@@ -0,0 +1,252 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics // import "github.com/open-telemetry/opentelemetry-collector-contrib/connector/spanmetricsconnector/internal/metrics"
+
+import (
+	"math"
+	"slices"
+
+	"github.com/lightstep/go-expohisto/mapping"
+	"github.com/lightstep/go-expohisto/mapping/exponent"
+	"github.com/lightstep/go-expohisto/mapping/logarithm"
+	"github.com/lightstep/go-expohisto/structure"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+// SumState is the state of a sum, as persisted across restarts. Exemplars are not part of the state.
+type SumState struct {
+	Attributes     map[string]any
+	Count          uint64
+	StartTimestamp pcommon.Timestamp
+	IsFirst        bool
+}
+
+// HistogramState is the state of an explicit or exponential histogram, as persisted across restarts.
+// Exemplars are not part of the state.
+type HistogramState struct {
+	Attributes     map[string]any
+	StartTimestamp pcommon.Timestamp
+	Count          uint64
+	Sum            float64
+
+	// Bounds and BucketCounts are the buckets of explicit histograms.
+	Bounds       []float64
+	BucketCounts []uint64
+
+	// Exponential is set for exponential histograms, whose buckets are recorded as in their data points.
+	Exponential bool
+	Min         float64
+	Max         float64
+	Scale       int32
+	ZeroCount   uint64
+	Positive    ExponentialBucketsState
+	Negative    ExponentialBucketsState
+}
+
+// ExponentialBucketsState are the positive or negative buckets of an exponential histogram.
+type ExponentialBucketsState struct {
+	Offset       int32
+	BucketCounts []uint64
+}
+
+// State returns the state of the sums.
+func (m *SumMetrics) State() map[Key]SumState {
+	states := make(map[Key]SumState, len(m.metrics))
+	for k, s := range m.metrics {
+		states[k] = SumState{
+			Attributes:     s.attributes.AsRaw(),
+			Count:          s.count,
+			StartTimestamp: s.startTimestamp,
+			IsFirst:        s.isFirst,
+		}
+	}
+	return states
+}
+
+// Restore restores the sums from their state.
+func (m *SumMetrics) Restore(states map[Key]SumState) {
+	for k, state := range states {
+		attributes := pcommon.NewMap()
+		if err := attributes.FromRaw(state.Attributes); err != nil {
+			continue
+		}
+		m.metrics[k] = &Sum{
+			attributes:       attributes,
+			count:            state.Count,
+			exemplars:        pmetric.NewExemplarSlice(),
+			maxExemplarCount: m.maxExemplarCount,
+			startTimestamp:   state.StartTimestamp,
+			isFirst:          state.IsFirst,
+		}
+	}
+}
+
+// State returns the state of the histograms.
+func (m *explicitHistogramMetrics) State() map[Key]HistogramState {
+	states := make(map[Key]HistogramState, len(m.metrics))
+	for k, h := range m.metrics {
+		states[k] = HistogramState{
+			Attributes:     h.attributes.AsRaw(),
+			StartTimestamp: h.startTimestamp,
+			Count:          h.count,
+			Sum:            h.sum,
+			Bounds:         h.bounds,
+			BucketCounts:   slices.Clone(h.bucketCounts),
+		}
+	}
+	return states
+}
+
+// Restore restores the histograms from their state. The states of the histograms
+// with other buckets than the configured ones are ignored.
+func (m *explicitHistogramMetrics) Restore(states map[Key]HistogramState) {
+	for k, state := range states {
+		if state.Exponential || !slices.Equal(state.Bounds, m.bounds) || len(state.BucketCounts) != len(m.bounds)+1 {
+			continue
+		}
+		attributes := pcommon.NewMap()
+		if err := attributes.FromRaw(state.Attributes); err != nil {
+			continue
+		}
+		m.metrics[k] = &explicitHistogram{
+			attributes:       attributes,
+			exemplars:        pmetric.NewExemplarSlice(),
+			bucketCounts:     slices.Clone(state.BucketCounts),
+			count:            state.Count,
+			sum:              state.Sum,
+			bounds:           m.bounds,
+			maxExemplarCount: m.maxExemplarCount,
+			startTimestamp:   state.StartTimestamp,
+		}
+	}
+}
+
+// State returns the state of the histograms.
+func (m *exponentialHistogramMetrics) State() map[Key]HistogramState {
+	states := make(map[Key]HistogramState, len(m.metrics))
+	for k, e := range m.metrics {
+		dp := pmetric.NewExponentialHistogramDataPoint()
+		expoHistToExponentialDataPoint(e.histogram, dp)
+		states[k] = HistogramState{
+			Attributes:     e.attributes.AsRaw(),
+			StartTimestamp: e.startTimestamp,
+			Count:          dp.Count(),
+			Sum:            dp.Sum() + e.sumOffset,
+			Exponential:    true,
+			Min:            dp.Min(),
+			Max:            dp.Max(),
+			Scale:          dp.Scale(),
+			ZeroCount:      dp.ZeroCount(),
+			Positive: ExponentialBucketsState{
+				Offset:       dp.Positive().Offset(),
+				BucketCounts: dp.Positive().BucketCounts().AsRaw(),
+			},
+			Negative: ExponentialBucketsState{
+				Offset:       dp.Negative().Offset(),
+				BucketCounts: dp.Negative().BucketCounts().AsRaw(),
+			},
+		}
+	}
+	return states
+}
+
+// Restore restores the histograms from their state. The states of explicit histograms are ignored.
+func (m *exponentialHistogramMetrics) Restore(states map[Key]HistogramState) {
+	for k, state := range states {
+		if !state.Exponential {
+			continue
+		}
+		attributes := pcommon.NewMap()
+		if err := attributes.FromRaw(state.Attributes); err != nil {
+			continue
+		}
+		histogram, sumOffset, err := restoreExponentialHistogram(state, m.maxSize)
+		if err != nil {
+			continue
+		}
+		m.metrics[k] = &exponentialHistogram{
+			attributes:       attributes,
+			exemplars:        pmetric.NewExemplarSlice(),
+			histogram:        histogram,
+			sumOffset:        sumOffset,
+			maxExemplarCount: m.maxExemplarCount,
+			startTimestamp:   state.StartTimestamp,
+		}
+	}
+}
+
+// restoreExponentialHistogram rebuilds a histogram from its state. The buckets of the histogram can only be
+// updated by observations, so the observations of each bucket are replayed at the midpoint of the bucket,
+// except for the minimum and maximum which are replayed exactly. The difference between the sum of the
+// replayed observations and the recorded sum is returned to be added to the sum of the histogram.
+func restoreExponentialHistogram(state HistogramState, maxSize int32) (*structure.Histogram[float64], float64, error) {
+	histogram := new(structure.Histogram[float64])
+	histogram.Init(structure.NewConfig(structure.WithMaxSize(maxSize)))
+	if state.Count == 0 {
+		return histogram, 0, nil
+	}
+
+	m, err := newMapping(state.Scale)
+	if err != nil {
+		return nil, 0, err
+	}
+	zeroCount := state.ZeroCount
+	positive := slices.Clone(state.Positive.BucketCounts)
+	negative := slices.Clone(state.Negative.BucketCounts)
+	// Remove the minimum and maximum from their buckets, they're replayed separately.
+	extremes := []float64{state.Min}
+	if state.Count > 1 {
+		extremes = append(extremes, state.Max)
+	}
+	for _, value := range extremes {
+		switch {
+		case value == 0:
+			if zeroCount > 0 {
+				zeroCount--
+			}
+		case value > 0:
+			decrementBucket(positive, m.MapToIndex(value)-state.Positive.Offset)
+		default:
+			decrementBucket(negative, m.MapToIndex(-value)-state.Negative.Offset)
+		}
+	}
+
+	if zeroCount > 0 {
+		histogram.UpdateByIncr(0, zeroCount)
+	}
+	for i, count := range positive {
+		if count > 0 {
+			histogram.UpdateByIncr(bucketMidpoint(state.Positive.Offset+int32(i), state.Scale), count)
+		}
+	}
+	for i, count := range negative {
+		if count > 0 {
+			histogram.UpdateByIncr(-bucketMidpoint(state.Negative.Offset+int32(i), state.Scale), count)
+		}
+	}
+	for _, value := range extremes {
+		histogram.Update(value)
+	}
+	return histogram, state.Sum - histogram.Sum(), nil
+}
+
+func newMapping(scale int32) (mapping.Mapping, error) {
+	if scale <= 0 {
+		return exponent.NewMapping(scale)
+	}
+	return logarithm.NewMapping(scale)
+}
+
+func decrementBucket(counts []uint64, i int32) {
+	if i >= 0 && int(i) < len(counts) && counts[i] > 0 {
+		counts[i]--
+	}
+}
+
+// bucketMidpoint returns the geometric midpoint of the bucket at the index for the scale,
+// the bucket covering the values in (base^index, base^(index+1)] where base is 2^(2^-scale).
+func bucketMidpoint(index, scale int32) float64 {
+	return math.Min(math.Exp2(math.Ldexp(float64(index)+0.5, -int(scale))), math.MaxFloat64)
+}
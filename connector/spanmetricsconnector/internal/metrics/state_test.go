@@ -0,0 +1,144 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+func testAttributes() pcommon.Map {
+	attrs := pcommon.NewMap()
+	attrs.PutStr("service.name", "checkout")
+	attrs.PutInt("http.response.status_code", 200)
+	attrs.PutEmptyBytes("bytes").FromRaw([]byte{1, 2})
+	return attrs
+}
+
+func buildMetric(build func(pmetric.Metric, pcommon.Timestamp, func(Key, pcommon.Timestamp) pcommon.Timestamp, pmetric.AggregationTemporality)) pmetric.Metric {
+	metric := pmetric.NewMetric()
+	build(metric, 20, func(_ Key, start pcommon.Timestamp) pcommon.Timestamp { return start }, pmetric.AggregationTemporalityCumulative)
+	return metric
+}
+
+func dataPointValues(dps pmetric.NumberDataPointSlice) []int64 {
+	values := make([]int64, dps.Len())
+	for i := 0; i < dps.Len(); i++ {
+		values[i] = dps.At(i).IntValue()
+	}
+	return values
+}
+
+func TestSumMetricsState(t *testing.T) {
+	m := NewSumMetrics(nil, 0)
+	s, _ := m.GetOrCreate("a", testAttributes, 10)
+	s.Add(3)
+	buildMetric(m.BuildMetrics)
+	_, _ = m.GetOrCreate("b", testAttributes, 15)
+
+	restored := NewSumMetrics(nil, 0)
+	restored.Restore(m.State())
+	assert.Equal(t, m.State(), restored.State())
+	// The restored sums which weren't built yet start from zero.
+	expected, actual := buildMetric(m.BuildMetrics), buildMetric(restored.BuildMetrics)
+	assert.Equal(t, m.State(), restored.State())
+	assert.ElementsMatch(t, dataPointValues(expected.Sum().DataPoints()), dataPointValues(actual.Sum().DataPoints()))
+}
+
+func TestExplicitHistogramMetricsState(t *testing.T) {
+	m := NewExplicitHistogramMetrics([]float64{1, 10, 100}, nil, 0)
+	h, _ := m.GetOrCreate("a", testAttributes, 10)
+	for _, v := range []float64{0.5, 5, 50, 500, 7} {
+		h.Observe(v)
+	}
+
+	restored := NewExplicitHistogramMetrics([]float64{1, 10, 100}, nil, 0)
+	restored.Restore(m.State())
+	assert.Equal(t, m.State(), restored.State())
+	expected := buildMetric(m.BuildMetrics).Histogram().DataPoints().At(0)
+	actual := buildMetric(restored.BuildMetrics).Histogram().DataPoints().At(0)
+	assert.Equal(t, expected.BucketCounts().AsRaw(), actual.BucketCounts().AsRaw())
+	assert.Equal(t, expected.Count(), actual.Count())
+	assert.Equal(t, expected.Sum(), actual.Sum())
+	assert.Equal(t, expected.StartTimestamp(), actual.StartTimestamp())
+	assert.Equal(t, expected.Attributes().AsRaw(), actual.Attributes().AsRaw())
+
+	// The histograms of other buckets, or exponential, are not restored.
+	other := NewExplicitHistogramMetrics([]float64{1, 10, 1000}, nil, 0)
+	other.Restore(m.State())
+	assert.Empty(t, other.State())
+	exponential := NewExponentialHistogramMetrics(160, nil, 0)
+	exponential.Restore(m.State())
+	assert.Empty(t, exponential.State())
+}
+
+func TestExponentialHistogramMetricsState(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		maxSize int32
+		values  []float64
+	}{
+		{
+			name:    "positive",
+			maxSize: 160,
+			values:  []float64{2, 4, 4.5, 100, 1000},
+		},
+		{
+			name:    "positive and negative",
+			maxSize: 4,
+			values:  []float64{-4, -2, 0, 2, 3, 4},
+		},
+		{
+			name:    "single",
+			maxSize: 160,
+			values:  []float64{42},
+		},
+		{
+			name:    "zeros",
+			maxSize: 160,
+			values:  []float64{0, 0},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewExponentialHistogramMetrics(tt.maxSize, nil, 0)
+			h, _ := m.GetOrCreate("a", testAttributes, 10)
+			for _, v := range tt.values {
+				h.Observe(v)
+			}
+
+			restored := NewExponentialHistogramMetrics(tt.maxSize, nil, 0)
+			restored.Restore(m.State())
+			expected := buildMetric(m.BuildMetrics).ExponentialHistogram().DataPoints().At(0)
+			actual := buildMetric(restored.BuildMetrics).ExponentialHistogram().DataPoints().At(0)
+			assert.Equal(t, expected.Count(), actual.Count())
+			assert.InDelta(t, expected.Sum(), actual.Sum(), 1e-9)
+			assert.Equal(t, expected.Min(), actual.Min())
+			assert.Equal(t, expected.Max(), actual.Max())
+			assert.Equal(t, expected.ZeroCount(), actual.ZeroCount())
+			assert.Equal(t, expected.Scale(), actual.Scale())
+			assert.Equal(t, expected.Positive().Offset(), actual.Positive().Offset())
+			assert.Equal(t, expected.Positive().BucketCounts().AsRaw(), actual.Positive().BucketCounts().AsRaw())
+			assert.Equal(t, expected.Negative().Offset(), actual.Negative().Offset())
+			assert.Equal(t, expected.Negative().BucketCounts().AsRaw(), actual.Negative().BucketCounts().AsRaw())
+			assert.Equal(t, expected.Attributes().AsRaw(), actual.Attributes().AsRaw())
+			assert.Equal(t, expected.StartTimestamp(), actual.StartTimestamp())
+
+			// The restored histogram keeps aggregating.
+			h, _ = restored.GetOrCreate("a", testAttributes, 10)
+			h.Observe(8)
+			actual = buildMetric(restored.BuildMetrics).ExponentialHistogram().DataPoints().At(0)
+			assert.Equal(t, expected.Count()+1, actual.Count())
+			assert.InDelta(t, expected.Sum()+8, actual.Sum(), 1e-9)
+
+			// The histograms of explicit metrics are not restored.
+			explicit := NewExplicitHistogramMetrics([]float64{1, 10}, nil, 0)
+			explicit.Restore(m.State())
+			require.Empty(t, explicit.State())
+		})
+	}
+}
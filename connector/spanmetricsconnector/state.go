@@ -0,0 +1,159 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package spanmetricsconnector // import "github.com/open-telemetry/opentelemetry-collector-contrib/connector/spanmetricsconnector"
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/extension/xextension/storage"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/spanmetricsconnector/internal/metrics"
+)
+
+const stateStorageKey = "spanmetrics_state"
+
+func init() {
+	// The attributes of the state are the raw values of the pcommon.Map.
+	gob.Register(map[string]any{})
+	gob.Register([]any{})
+}
+
+// connectorState is the state of the cumulative metrics, as persisted to the storage.
+type connectorState struct {
+	Resources []resourceState
+}
+
+type resourceState struct {
+	Key        resourceKey
+	Attributes map[string]any
+	LastSeen   time.Time
+	Sums       map[metrics.Key]metrics.SumState
+	Events     map[metrics.Key]metrics.SumState
+	Histograms map[metrics.Key]metrics.HistogramState
+}
+
+func getStorageClient(ctx context.Context, host component.Host, storageID *component.ID, id component.ID) (storage.Client, error) {
+	extension, ok := host.GetExtensions()[*storageID]
+	if !ok {
+		return nil, fmt.Errorf("storage extension '%s' not found", storageID)
+	}
+
+	storageExtension, ok := extension.(storage.Extension)
+	if !ok {
+		return nil, fmt.Errorf("non-storage extension '%s' found", storageID)
+	}
+
+	return storageExtension.GetClient(ctx, component.KindConnector, id, "")
+}
+
+// loadState restores the state of the metrics from the storage. A state which can't be decoded
+// is discarded, the connector then starts from scratch.
+func (p *connectorImp) loadState(ctx context.Context) error {
+	data, err := p.storageClient.Get(ctx, stateStorageKey)
+	if err != nil {
+		return fmt.Errorf("failed to load the state from the storage: %w", err)
+	}
+	if len(data) == 0 {
+		return nil
+	}
+
+	var state connectorState
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&state); err != nil {
+		p.logger.Warn("Discarding the state which couldn't be decoded", zap.Error(err))
+		return nil
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.restoreState(state)
+	p.lastSnapshot = p.clock.Now()
+	return nil
+}
+
+// restoreState restores the metrics of the resources not expired since they were last seen.
+func (p *connectorImp) restoreState(state connectorState) {
+	now := p.clock.Now()
+	var restored int
+	for _, rs := range state.Resources {
+		lastSeen := rs.LastSeen
+		if lastSeen.IsZero() {
+			lastSeen = now
+		}
+		if p.config.MetricsExpiration > 0 && now.Sub(lastSeen) >= p.config.MetricsExpiration {
+			continue
+		}
+		attributes := pcommon.NewMap()
+		if err := attributes.FromRaw(rs.Attributes); err != nil {
+			continue
+		}
+
+		rm := &resourceMetrics{
+			histograms: initHistogramMetrics(p.config),
+			sums:       metrics.NewSumMetrics(p.config.Exemplars.MaxPerDataPoint, p.config.AggregationCardinalityLimit),
+			events:     metrics.NewSumMetrics(p.config.Exemplars.MaxPerDataPoint, p.config.AggregationCardinalityLimit),
+			attributes: attributes,
+			lastSeen:   lastSeen,
+		}
+		rm.sums.Restore(rs.Sums)
+		rm.events.Restore(rs.Events)
+		if rm.histograms != nil {
+			rm.histograms.Restore(rs.Histograms)
+		}
+		p.resourceMetrics.Add(rs.Key, rm)
+		restored++
+	}
+	p.logger.Info("Restored the state of the metrics", zap.Int("resources", restored))
+}
+
+// encodeState encodes the state of the metrics, it must be called with the lock held.
+func (p *connectorImp) encodeState() ([]byte, error) {
+	var state connectorState
+	p.resourceMetrics.ForEach(func(k resourceKey, rm *resourceMetrics) {
+		rs := resourceState{
+			Key:        k,
+			Attributes: rm.attributes.AsRaw(),
+			LastSeen:   rm.lastSeen,
+			Sums:       rm.sums.State(),
+			Events:     rm.events.State(),
+		}
+		if rm.histograms != nil {
+			rs.Histograms = rm.histograms.State()
+		}
+		state.Resources = append(state.Resources, rs)
+	})
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(state); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// snapshotState encodes the state of the metrics if the snapshot interval elapsed since the
+// last snapshot, it must be called with the lock held. It returns nil if no snapshot is due.
+func (p *connectorImp) snapshotState() []byte {
+	if p.storageClient == nil || p.clock.Since(p.lastSnapshot) < p.config.StateSnapshotInterval {
+		return nil
+	}
+	data, err := p.encodeState()
+	if err != nil {
+		p.logger.Error("Failed to encode the state", zap.Error(err))
+		return nil
+	}
+	p.lastSnapshot = p.clock.Now()
+	return data
+}
+
+func (p *connectorImp) storeState(ctx context.Context, data []byte) {
+	if err := p.storageClient.Set(ctx, stateStorageKey, data); err != nil {
+		p.logger.Error("Failed to store the state", zap.Error(err))
+	}
+}
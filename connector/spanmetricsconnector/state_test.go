@@ -0,0 +1,176 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package spanmetricsconnector
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/spanmetricsconnector/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
+)
+
+func newConnectorWithStorage(t *testing.T, histogramConfig func() HistogramConfig, expiration time.Duration, clock clockwork.Clock, host component.Host) (*connectorImp, *consumertest.MetricsSink) {
+	p, err := newConnectorImp(stringp("defaultNullValue"), histogramConfig, disabledExemplarsConfig, disabledEventsConfig, cumulative, expiration, []string{}, 1000, clock)
+	require.NoError(t, err)
+	storageID := storagetest.NewStorageID("state")
+	p.config.Storage = &storageID
+	p.id = component.NewID(metadata.Type)
+	sink := &consumertest.MetricsSink{}
+	p.metricsConsumer = sink
+	require.NoError(t, p.Start(context.Background(), host))
+	return p, sink
+}
+
+func callsStartTimestamps(m pmetric.Metrics) []pcommon.Timestamp {
+	var timestamps []pcommon.Timestamp
+	for i := 0; i < m.ResourceMetrics().Len(); i++ {
+		metrics := m.ResourceMetrics().At(i).ScopeMetrics().At(0).Metrics()
+		for j := 0; j < metrics.Len(); j++ {
+			if metrics.At(j).Name() != metricNameCalls {
+				continue
+			}
+			dps := metrics.At(j).Sum().DataPoints()
+			for k := 0; k < dps.Len(); k++ {
+				timestamps = append(timestamps, dps.At(k).StartTimestamp())
+			}
+		}
+	}
+	return timestamps
+}
+
+func TestStateRestoredOnRestart(t *testing.T) {
+	for _, tt := range []struct {
+		name            string
+		histogramConfig func() HistogramConfig
+	}{
+		{
+			name:            "explicit histograms",
+			histogramConfig: explicitHistogramsConfig,
+		},
+		{
+			name:            "exponential histograms",
+			histogramConfig: exponentialHistogramsConfig,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			host := storagetest.NewStorageHost().WithFileBackedStorageExtension("state", t.TempDir())
+			clock := newAlwaysIncreasingClock()
+
+			p, sink := newConnectorWithStorage(t, tt.histogramConfig, 0, clock, host)
+			for i := 0; i < 2; i++ {
+				require.NoError(t, p.ConsumeTraces(ctx, buildSampleTrace()))
+				p.exportMetrics(ctx)
+			}
+			verifyConsumeMetricsInput(t, sink.AllMetrics()[1], pmetric.AggregationTemporalityCumulative, 2)
+			startTimestamps := callsStartTimestamps(sink.AllMetrics()[1])
+			require.NoError(t, p.Shutdown(ctx))
+
+			// The counters and their start timestamps carry on after the restart.
+			p, sink = newConnectorWithStorage(t, tt.histogramConfig, 0, clock, host)
+			require.NoError(t, p.ConsumeTraces(ctx, buildSampleTrace()))
+			p.exportMetrics(ctx)
+			verifyConsumeMetricsInput(t, sink.AllMetrics()[0], pmetric.AggregationTemporalityCumulative, 3)
+			assert.ElementsMatch(t, startTimestamps, callsStartTimestamps(sink.AllMetrics()[0]))
+			require.NoError(t, p.Shutdown(ctx))
+		})
+	}
+}
+
+func TestStateSnapshotInterval(t *testing.T) {
+	ctx := context.Background()
+	host := storagetest.NewStorageHost().WithInMemoryStorageExtension("state")
+	clock := clockwork.NewFakeClock()
+	p, _ := newConnectorWithStorage(t, explicitHistogramsConfig, 0, clock, host)
+	p.config.StateSnapshotInterval = time.Minute
+
+	require.NoError(t, p.ConsumeTraces(ctx, buildSampleTrace()))
+	p.exportMetrics(ctx)
+	data, err := p.storageClient.Get(ctx, stateStorageKey)
+	require.NoError(t, err)
+	assert.Empty(t, data, "the state is snapshotted once the interval elapsed")
+
+	clock.Advance(time.Minute)
+	p.exportMetrics(ctx)
+	data, err = p.storageClient.Get(ctx, stateStorageKey)
+	require.NoError(t, err)
+	assert.NotEmpty(t, data)
+	require.NoError(t, p.Shutdown(ctx))
+}
+
+func TestStateExpiredOnRestore(t *testing.T) {
+	ctx := context.Background()
+	host := storagetest.NewStorageHost().WithFileBackedStorageExtension("state", t.TempDir())
+	clock := clockwork.NewFakeClock()
+
+	p, _ := newConnectorWithStorage(t, explicitHistogramsConfig, time.Hour, clock, host)
+	require.NoError(t, p.ConsumeTraces(ctx, buildSampleTrace()))
+	require.NoError(t, p.Shutdown(ctx))
+
+	clock.Advance(30 * time.Minute)
+	p, _ = newConnectorWithStorage(t, explicitHistogramsConfig, time.Hour, clock, host)
+	assert.Equal(t, 2, p.resourceMetrics.Len())
+	require.NoError(t, p.Shutdown(ctx))
+
+	// The metrics not seen for longer than the expiration aren't restored.
+	clock.Advance(time.Hour)
+	p, _ = newConnectorWithStorage(t, explicitHistogramsConfig, time.Hour, clock, host)
+	assert.Zero(t, p.resourceMetrics.Len())
+	require.NoError(t, p.Shutdown(ctx))
+}
+
+func TestStateInvalid(t *testing.T) {
+	ctx := context.Background()
+	host := storagetest.NewStorageHost().WithFileBackedStorageExtension("state", t.TempDir())
+	client, err := host.GetExtensions()[storagetest.NewStorageID("state")].(*storagetest.TestStorage).GetClient(ctx, component.KindConnector, component.NewID(metadata.Type), "")
+	require.NoError(t, err)
+	require.NoError(t, client.Set(ctx, stateStorageKey, []byte("invalid")))
+	require.NoError(t, client.Close(ctx))
+
+	// The connector starts from scratch.
+	p, _ := newConnectorWithStorage(t, explicitHistogramsConfig, 0, clockwork.NewFakeClock(), host)
+	assert.Zero(t, p.resourceMetrics.Len())
+	require.NoError(t, p.Shutdown(ctx))
+}
+
+func TestStateStorageErrors(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		storageID component.ID
+		host      component.Host
+		expected  string
+	}{
+		{
+			name:      "missing",
+			storageID: storagetest.NewStorageID("missing"),
+			host:      componenttest.NewNopHost(),
+			expected:  "storage extension 'test_storage/missing' not found",
+		},
+		{
+			name:      "not a storage",
+			storageID: storagetest.NewNonStorageID("other"),
+			host:      storagetest.NewStorageHost().WithNonStorageExtension("other"),
+			expected:  "non-storage extension 'non_storage/other' found",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := newConnectorImp(stringp("defaultNullValue"), explicitHistogramsConfig, disabledExemplarsConfig, disabledEventsConfig, cumulative, 0, []string{}, 1000, clockwork.NewFakeClock())
+			require.NoError(t, err)
+			p.config.Storage = &tt.storageID
+			assert.EqualError(t, p.Start(context.Background(), tt.host), tt.expected)
+			require.NoError(t, p.Shutdown(context.Background()))
+		})
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package tracemetricsconnector // import "github.com/open-telemetry/opentelemetry-collector-contrib/connector/tracemetricsconnector"
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+const (
+	defaultWaitDuration = 10 * time.Second
+	defaultNumTraces    = 10_000
+)
+
+var defaultDurationHistogramBuckets = []time.Duration{
+	10 * time.Millisecond, 25 * time.Millisecond, 50 * time.Millisecond, 100 * time.Millisecond,
+	250 * time.Millisecond, 500 * time.Millisecond, time.Second, 2500 * time.Millisecond,
+	5 * time.Second, 10 * time.Second, 30 * time.Second, time.Minute,
+}
+
+// Dimension defines the dimension name and optional default value if the Dimension is missing from the root span
+// and its resource.
+type Dimension struct {
+	Name    string  `mapstructure:"name"`
+	Default *string `mapstructure:"default"`
+	// prevent unkeyed literal initialization
+	_ struct{}
+}
+
+// Config defines the configuration options for tracemetricsconnector.
+type Config struct {
+	// WaitDuration is how long to wait for new spans of a trace after its last received span, before the trace
+	// is considered complete and aggregated into the metrics.
+	WaitDuration time.Duration `mapstructure:"wait_duration"`
+
+	// NumTraces is the maximum number of traces waiting for their completion. When reached, the oldest trace
+	// is considered complete to make room for the new one.
+	NumTraces int `mapstructure:"num_traces"`
+
+	// DurationHistogramBuckets is the list of durations representing the buckets of the trace duration histogram.
+	DurationHistogramBuckets []time.Duration `mapstructure:"duration_histogram_buckets"`
+
+	// Dimensions defines the list of additional dimensions on top of the provided:
+	// - service.name, as resource attribute
+	// - span.name
+	// - error
+	// The dimensions are fetched from the attributes of the root span, or of its resource.
+	Dimensions []Dimension `mapstructure:"dimensions"`
+	// prevent unkeyed literal initialization
+	_ struct{}
+}
+
+func (c *Config) Validate() error {
+	if c.WaitDuration <= 0 {
+		return fmt.Errorf("invalid wait_duration: %v, the duration should be positive", c.WaitDuration)
+	}
+	if c.NumTraces <= 0 {
+		return fmt.Errorf("invalid num_traces: %d, the number of traces should be positive", c.NumTraces)
+	}
+	if !slices.IsSorted(c.DurationHistogramBuckets) || len(slices.Compact(slices.Clone(c.DurationHistogramBuckets))) != len(c.DurationHistogramBuckets) {
+		return errors.New("duration_histogram_buckets must be in increasing order")
+	}
+
+	seen := map[string]struct{}{
+		spanNameKey:                {},
+		errorKey:                   {},
+		criticalPathServiceNameKey: {},
+	}
+	for _, d := range c.Dimensions {
+		if _, ok := seen[d.Name]; ok {
+			return fmt.Errorf("duplicate dimension name %q", d.Name)
+		}
+		seen[d.Name] = struct{}{}
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package tracemetricsconnector
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap/confmaptest"
+	"go.opentelemetry.io/collector/confmap/xconfmap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/tracemetricsconnector/internal/metadata"
+)
+
+func TestLoadConfig(t *testing.T) {
+	t.Parallel()
+
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+	require.NoError(t, err)
+
+	defaultMethod := "GET"
+	tests := []struct {
+		id           component.ID
+		expected     component.Config
+		errorMessage string
+	}{
+		{
+			id:       component.NewID(metadata.Type),
+			expected: createDefaultConfig(),
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "full"),
+			expected: &Config{
+				WaitDuration:             30 * time.Second,
+				NumTraces:                100,
+				DurationHistogramBuckets: []time.Duration{100 * time.Millisecond, time.Second, 10 * time.Second},
+				Dimensions: []Dimension{
+					{Name: "http.request.method", Default: &defaultMethod},
+					{Name: "deployment.environment.name"},
+				},
+			},
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "invalid_wait_duration"),
+			errorMessage: "invalid wait_duration: 0s, the duration should be positive",
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "invalid_num_traces"),
+			errorMessage: "invalid num_traces: -1, the number of traces should be positive",
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "invalid_duration_histogram_buckets"),
+			errorMessage: "duration_histogram_buckets must be in increasing order",
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "duplicate_dimension"),
+			errorMessage: `duplicate dimension name "http.request.method"`,
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "reserved_dimension"),
+			errorMessage: `duplicate dimension name "span.name"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id.String(), func(t *testing.T) {
+			factory := NewFactory()
+			cfg := factory.CreateDefaultConfig()
+
+			sub, err := cm.Sub(tt.id.String())
+			require.NoError(t, err)
+			err = sub.Unmarshal(cfg)
+
+			if tt.expected == nil {
+				err = errors.Join(err, xconfmap.Validate(cfg))
+				assert.ErrorContains(t, err, tt.errorMessage)
+				return
+			}
+			assert.NoError(t, xconfmap.Validate(cfg))
+			assert.Equal(t, tt.expected, cfg)
+		})
+	}
+}
@@ -0,0 +1,232 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package tracemetricsconnector // import "github.com/open-telemetry/opentelemetry-collector-contrib/connector/tracemetricsconnector"
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+)
+
+// maxCompletionCheckInterval is the maximum interval between two checks of the traces waiting for their completion.
+const maxCompletionCheckInterval = time.Second
+
+type connectorImp struct {
+	config          *Config
+	logger          *zap.Logger
+	metricsConsumer consumer.Metrics
+	durationBounds  []float64
+
+	lock sync.Mutex
+	// traces are the traces waiting for their completion, in the order of their first received span
+	traces      *list.List
+	tracesByID  map[pcommon.TraceID]*list.Element
+	lastFlushed pcommon.Timestamp
+
+	shutdownCh chan struct{}
+	wg         sync.WaitGroup
+}
+
+func newConnector(logger *zap.Logger, config *Config, metricsConsumer consumer.Metrics) *connectorImp {
+	durationBounds := make([]float64, len(config.DurationHistogramBuckets))
+	for i, d := range config.DurationHistogramBuckets {
+		durationBounds[i] = d.Seconds()
+	}
+
+	return &connectorImp{
+		config:          config,
+		logger:          logger,
+		metricsConsumer: metricsConsumer,
+		durationBounds:  durationBounds,
+		traces:          list.New(),
+		tracesByID:      make(map[pcommon.TraceID]*list.Element),
+		lastFlushed:     pcommon.NewTimestampFromTime(time.Now()),
+		shutdownCh:      make(chan struct{}),
+	}
+}
+
+// Start implements the component.Component interface.
+func (p *connectorImp) Start(context.Context, component.Host) error {
+	p.wg.Add(1)
+	go p.completionLoop(min(p.config.WaitDuration, maxCompletionCheckInterval))
+	return nil
+}
+
+// Shutdown implements the component.Component interface. The traces waiting for their completion are
+// considered complete.
+func (p *connectorImp) Shutdown(ctx context.Context) error {
+	select {
+	case <-p.shutdownCh:
+		return nil
+	default:
+	}
+	close(p.shutdownCh)
+	p.wg.Wait()
+
+	p.lock.Lock()
+	var completed []*traceData
+	for e := p.traces.Front(); e != nil; e = e.Next() {
+		completed = append(completed, e.Value.(*traceData))
+	}
+	p.traces.Init()
+	clear(p.tracesByID)
+	p.lock.Unlock()
+
+	p.exportMetrics(ctx, completed)
+	return nil
+}
+
+// Capabilities implements the consumer interface.
+func (p *connectorImp) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{MutatesData: false}
+}
+
+// ConsumeTraces buffers the spans of the traces until the traces are complete.
+func (p *connectorImp) ConsumeTraces(ctx context.Context, td ptrace.Traces) error {
+	now := time.Now()
+	var evicted []*traceData
+
+	p.lock.Lock()
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		resourceAttributes := rs.Resource().Attributes()
+		service := ""
+		if v, ok := resourceAttributes.Get(serviceNameKey); ok {
+			service = v.AsString()
+		}
+
+		ilss := rs.ScopeSpans()
+		for j := 0; j < ilss.Len(); j++ {
+			spans := ilss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				t, ok := p.getOrCreateTrace(span.TraceID())
+				if !ok {
+					evicted = append(evicted, p.evictOldestTrace())
+					t, _ = p.getOrCreateTrace(span.TraceID())
+				}
+				t.spans = append(t.spans, p.newSpanData(span, service, resourceAttributes))
+				t.lastSeen = now
+			}
+		}
+	}
+	p.lock.Unlock()
+
+	if len(evicted) > 0 {
+		p.logger.Debug("Completing the oldest traces as the maximum number of traces is reached", zap.Int("traces", len(evicted)))
+		p.exportMetrics(ctx, evicted)
+	}
+	return nil
+}
+
+// getOrCreateTrace returns the trace, which is created unless the maximum number of traces is reached.
+// It must be called with the lock held.
+func (p *connectorImp) getOrCreateTrace(traceID pcommon.TraceID) (*traceData, bool) {
+	if e, ok := p.tracesByID[traceID]; ok {
+		return e.Value.(*traceData), true
+	}
+	if p.traces.Len() >= p.config.NumTraces {
+		return nil, false
+	}
+	t := &traceData{traceID: traceID}
+	p.tracesByID[traceID] = p.traces.PushBack(t)
+	return t, true
+}
+
+// evictOldestTrace removes the oldest trace, it must be called with the lock held.
+func (p *connectorImp) evictOldestTrace() *traceData {
+	t := p.traces.Remove(p.traces.Front()).(*traceData)
+	delete(p.tracesByID, t.traceID)
+	return t
+}
+
+func (p *connectorImp) newSpanData(span ptrace.Span, service string, resourceAttributes pcommon.Map) *spanData {
+	s := &spanData{
+		spanID:       span.SpanID(),
+		parentSpanID: span.ParentSpanID(),
+		name:         span.Name(),
+		service:      service,
+		start:        span.StartTimestamp(),
+		end:          span.EndTimestamp(),
+		isError:      span.Status().Code() == ptrace.StatusCodeError,
+	}
+	// The root span is only known once the trace is complete, so the dimensions of every span are kept.
+	s.dimensions = make([]pcommon.Value, len(p.config.Dimensions))
+	for i, d := range p.config.Dimensions {
+		s.dimensions[i] = pcommon.NewValueEmpty()
+		if v, ok := span.Attributes().Get(d.Name); ok {
+			v.CopyTo(s.dimensions[i])
+		} else if v, ok := resourceAttributes.Get(d.Name); ok {
+			v.CopyTo(s.dimensions[i])
+		}
+	}
+	return s
+}
+
+func (p *connectorImp) completionLoop(interval time.Duration) {
+	defer p.wg.Done()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			p.completeTraces(context.Background(), now)
+		case <-p.shutdownCh:
+			return
+		}
+	}
+}
+
+// completeTraces exports the metrics of the traces which didn't receive new spans for the wait duration.
+func (p *connectorImp) completeTraces(ctx context.Context, now time.Time) {
+	var completed []*traceData
+	p.lock.Lock()
+	for e := p.traces.Front(); e != nil; {
+		next := e.Next()
+		t := e.Value.(*traceData)
+		if now.Sub(t.lastSeen) >= p.config.WaitDuration {
+			p.traces.Remove(e)
+			delete(p.tracesByID, t.traceID)
+			completed = append(completed, t)
+		}
+		e = next
+	}
+	p.lock.Unlock()
+
+	p.exportMetrics(ctx, completed)
+}
+
+// exportMetrics exports the metrics of the complete traces.
+func (p *connectorImp) exportMetrics(ctx context.Context, completed []*traceData) {
+	if len(completed) == 0 {
+		return
+	}
+
+	agg := newAggregator(p.config, p.durationBounds)
+	for _, t := range completed {
+		agg.add(t.summarize())
+	}
+
+	end := pcommon.NewTimestampFromTime(time.Now())
+	p.lock.Lock()
+	start := p.lastFlushed
+	p.lastFlushed = end
+	p.lock.Unlock()
+
+	md := agg.buildMetrics(start, end)
+	if md.DataPointCount() == 0 {
+		return
+	}
+	if err := p.metricsConsumer.ConsumeMetrics(ctx, md); err != nil {
+		p.logger.Error("Failed ConsumeMetrics", zap.Error(err))
+	}
+}
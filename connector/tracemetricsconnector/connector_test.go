@@ -0,0 +1,223 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package tracemetricsconnector
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+)
+
+type testSpanDef struct {
+	traceID    byte
+	spanID     byte
+	parentID   byte
+	service    string
+	name       string
+	start, end time.Duration
+	isError    bool
+	attributes map[string]any
+}
+
+func buildTraces(base time.Time, defs ...testSpanDef) ptrace.Traces {
+	td := ptrace.NewTraces()
+	for _, def := range defs {
+		rs := td.ResourceSpans().AppendEmpty()
+		rs.Resource().Attributes().PutStr(serviceNameKey, def.service)
+		span := rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+		span.SetTraceID(pcommon.TraceID{def.traceID})
+		span.SetSpanID(pcommon.SpanID{def.spanID})
+		if def.parentID != 0 {
+			span.SetParentSpanID(pcommon.SpanID{def.parentID})
+		}
+		span.SetName(def.name)
+		span.SetStartTimestamp(pcommon.NewTimestampFromTime(base.Add(def.start)))
+		span.SetEndTimestamp(pcommon.NewTimestampFromTime(base.Add(def.end)))
+		if def.isError {
+			span.Status().SetCode(ptrace.StatusCodeError)
+		}
+		_ = span.Attributes().FromRaw(def.attributes)
+	}
+	return td
+}
+
+func newTestConnector(t *testing.T, cfg *Config) (*connectorImp, *consumertest.MetricsSink) {
+	require.NoError(t, cfg.Validate())
+	sink := new(consumertest.MetricsSink)
+	return newConnector(zap.NewNop(), cfg, sink), sink
+}
+
+func findMetric(t *testing.T, sm pmetric.ScopeMetrics, name string) pmetric.Metric {
+	for i := 0; i < sm.Metrics().Len(); i++ {
+		if sm.Metrics().At(i).Name() == name {
+			return sm.Metrics().At(i)
+		}
+	}
+	require.Failf(t, "metric not found", "metric %q not found", name)
+	return pmetric.Metric{}
+}
+
+func TestConnectorCompleteTraces(t *testing.T) {
+	p, sink := newTestConnector(t, createDefaultConfig().(*Config))
+	ctx := context.Background()
+	base := time.Now()
+
+	require.NoError(t, p.ConsumeTraces(ctx, buildTraces(base,
+		testSpanDef{traceID: 1, spanID: 1, service: "frontend", name: "GET /checkout", end: 200 * time.Millisecond},
+		testSpanDef{traceID: 1, spanID: 2, parentID: 1, service: "checkout", name: "Checkout", start: 20 * time.Millisecond, end: 150 * time.Millisecond},
+	)))
+	require.NoError(t, p.ConsumeTraces(ctx, buildTraces(base,
+		testSpanDef{traceID: 1, spanID: 3, parentID: 2, service: "payment", name: "Charge", start: 50 * time.Millisecond, end: 130 * time.Millisecond, isError: true},
+		testSpanDef{traceID: 2, spanID: 1, service: "frontend", name: "GET /checkout", end: 30 * time.Millisecond},
+	)))
+
+	// The traces aren't complete until the wait duration elapsed.
+	p.completeTraces(ctx, time.Now())
+	assert.Empty(t, sink.AllMetrics())
+
+	p.completeTraces(ctx, time.Now().Add(p.config.WaitDuration))
+	require.Len(t, sink.AllMetrics(), 1)
+	assert.Equal(t, 0, p.traces.Len())
+	assert.Empty(t, p.tracesByID)
+
+	md := sink.AllMetrics()[0]
+	require.Equal(t, 1, md.ResourceMetrics().Len())
+	rm := md.ResourceMetrics().At(0)
+	service, _ := rm.Resource().Attributes().Get(serviceNameKey)
+	assert.Equal(t, "frontend", service.Str())
+	sm := rm.ScopeMetrics().At(0)
+
+	duration := findMetric(t, sm, metricNameDuration).Histogram()
+	assert.Equal(t, pmetric.AggregationTemporalityDelta, duration.AggregationTemporality())
+	require.Equal(t, 2, duration.DataPoints().Len())
+	// The data points are sorted by span name then error.
+	ok := duration.DataPoints().At(0)
+	assert.Equal(t, map[string]any{spanNameKey: "GET /checkout", errorKey: false}, ok.Attributes().AsRaw())
+	assert.Equal(t, uint64(1), ok.Count())
+	assert.InDelta(t, 0.03, ok.Sum(), 1e-9)
+	failed := duration.DataPoints().At(1)
+	assert.Equal(t, map[string]any{spanNameKey: "GET /checkout", errorKey: true}, failed.Attributes().AsRaw())
+	assert.InDelta(t, 0.2, failed.Sum(), 1e-9)
+
+	spanCount := findMetric(t, sm, metricNameSpanCount).Histogram()
+	assert.InDelta(t, 1.0, spanCount.DataPoints().At(0).Sum(), 1e-9)
+	assert.InDelta(t, 3.0, spanCount.DataPoints().At(1).Sum(), 1e-9)
+
+	serviceCount := findMetric(t, sm, metricNameServiceCount).Histogram()
+	assert.InDelta(t, 1.0, serviceCount.DataPoints().At(0).Sum(), 1e-9)
+	assert.InDelta(t, 3.0, serviceCount.DataPoints().At(1).Sum(), 1e-9)
+
+	selfTime := findMetric(t, sm, metricNameCriticalPathSelfTime).Sum()
+	assert.Equal(t, pmetric.AggregationTemporalityDelta, selfTime.AggregationTemporality())
+	assert.True(t, selfTime.IsMonotonic())
+	selfTimes := map[bool]map[string]float64{}
+	for i := 0; i < selfTime.DataPoints().Len(); i++ {
+		dp := selfTime.DataPoints().At(i)
+		isError, _ := dp.Attributes().Get(errorKey)
+		criticalPathService, _ := dp.Attributes().Get(criticalPathServiceNameKey)
+		if selfTimes[isError.Bool()] == nil {
+			selfTimes[isError.Bool()] = map[string]float64{}
+		}
+		selfTimes[isError.Bool()][criticalPathService.Str()] = dp.DoubleValue()
+	}
+	assert.InDeltaMapValues(t, map[string]float64{"frontend": 0.03}, selfTimes[false], 1e-9)
+	assert.InDeltaMapValues(t, map[string]float64{"frontend": 0.07, "checkout": 0.05, "payment": 0.08}, selfTimes[true], 1e-9)
+}
+
+func TestConnectorNumTraces(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.NumTraces = 1
+	p, sink := newTestConnector(t, cfg)
+	ctx := context.Background()
+	base := time.Now()
+
+	require.NoError(t, p.ConsumeTraces(ctx, buildTraces(base,
+		testSpanDef{traceID: 1, spanID: 1, service: "frontend", name: "first", end: time.Millisecond},
+	)))
+	assert.Empty(t, sink.AllMetrics())
+
+	require.NoError(t, p.ConsumeTraces(ctx, buildTraces(base,
+		testSpanDef{traceID: 2, spanID: 1, service: "frontend", name: "second", end: time.Millisecond},
+	)))
+	require.Len(t, sink.AllMetrics(), 1)
+	dp := sink.AllMetrics()[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Histogram().DataPoints().At(0)
+	name, _ := dp.Attributes().Get(spanNameKey)
+	assert.Equal(t, "first", name.Str())
+	assert.Equal(t, 1, p.traces.Len())
+	assert.Contains(t, p.tracesByID, pcommon.TraceID{2})
+}
+
+func TestConnectorDimensions(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	defaultMethod := "GET"
+	cfg.Dimensions = []Dimension{
+		{Name: "http.request.method", Default: &defaultMethod},
+		{Name: "deployment.environment.name"},
+		{Name: "http.route"},
+	}
+	p, sink := newTestConnector(t, cfg)
+	ctx := context.Background()
+	base := time.Now()
+
+	td := buildTraces(base,
+		testSpanDef{traceID: 1, spanID: 1, service: "frontend", name: "root", end: time.Millisecond, attributes: map[string]any{"http.route": "/checkout"}},
+		testSpanDef{traceID: 1, spanID: 2, parentID: 1, service: "checkout", name: "child", end: time.Millisecond, attributes: map[string]any{"http.request.method": "POST"}},
+	)
+	td.ResourceSpans().At(0).Resource().Attributes().PutStr("deployment.environment.name", "production")
+	require.NoError(t, p.ConsumeTraces(ctx, td))
+	p.completeTraces(ctx, time.Now().Add(cfg.WaitDuration))
+
+	require.Len(t, sink.AllMetrics(), 1)
+	dp := sink.AllMetrics()[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Histogram().DataPoints().At(0)
+	assert.Equal(t, map[string]any{
+		spanNameKey:                   "root",
+		errorKey:                      false,
+		"http.request.method":         "GET",
+		"deployment.environment.name": "production",
+		"http.route":                  "/checkout",
+	}, dp.Attributes().AsRaw())
+}
+
+func TestConnectorShutdown(t *testing.T) {
+	p, sink := newTestConnector(t, createDefaultConfig().(*Config))
+	ctx := context.Background()
+	require.NoError(t, p.Start(ctx, componenttest.NewNopHost()))
+
+	require.NoError(t, p.ConsumeTraces(ctx, buildTraces(time.Now(),
+		testSpanDef{traceID: 1, spanID: 1, service: "frontend", name: "root", end: time.Millisecond},
+	)))
+	assert.Empty(t, sink.AllMetrics())
+
+	require.NoError(t, p.Shutdown(ctx))
+	assert.Len(t, sink.AllMetrics(), 1)
+	assert.Equal(t, 0, p.traces.Len())
+	require.NoError(t, p.Shutdown(ctx))
+}
+
+func TestConnectorCompletionLoop(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.WaitDuration = 10 * time.Millisecond
+	p, sink := newTestConnector(t, cfg)
+	ctx := context.Background()
+	require.NoError(t, p.Start(ctx, componenttest.NewNopHost()))
+	defer func() {
+		require.NoError(t, p.Shutdown(ctx))
+	}()
+
+	require.NoError(t, p.ConsumeTraces(ctx, buildTraces(time.Now(),
+		testSpanDef{traceID: 1, spanID: 1, service: "frontend", name: "root", end: time.Millisecond},
+	)))
+	assert.Eventually(t, func() bool {
+		return len(sink.AllMetrics()) == 1
+	}, time.Second, 5*time.Millisecond)
+}
@@ -0,0 +1,218 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package tracemetricsconnector // import "github.com/open-telemetry/opentelemetry-collector-contrib/connector/tracemetricsconnector"
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/tracemetricsconnector/internal/metadata"
+)
+
+const (
+	serviceNameKey             = "service.name"
+	spanNameKey                = "span.name"
+	errorKey                   = "error"
+	criticalPathServiceNameKey = "critical_path.service.name"
+
+	metricNameDuration             = "trace.duration"
+	metricNameSpanCount            = "trace.span.count"
+	metricNameServiceCount         = "trace.service.count"
+	metricNameCriticalPathSelfTime = "trace.critical_path.self_time"
+
+	keySeparator = string(byte(0))
+)
+
+var countHistogramBuckets = []float64{1, 2, 5, 10, 20, 50, 100, 200, 500, 1000}
+
+// histogram is an explicit bucket histogram.
+type histogram struct {
+	bounds       []float64
+	bucketCounts []uint64
+	count        uint64
+	sum          float64
+	min, max     float64
+}
+
+func newHistogram(bounds []float64) *histogram {
+	return &histogram{
+		bounds:       bounds,
+		bucketCounts: make([]uint64, len(bounds)+1),
+	}
+}
+
+func (h *histogram) observe(value float64) {
+	if h.count == 0 || value < h.min {
+		h.min = value
+	}
+	if h.count == 0 || value > h.max {
+		h.max = value
+	}
+	h.bucketCounts[sort.SearchFloat64s(h.bounds, value)]++
+	h.count++
+	h.sum += value
+}
+
+func (h *histogram) copyTo(dp pmetric.HistogramDataPoint) {
+	dp.ExplicitBounds().FromRaw(h.bounds)
+	dp.BucketCounts().FromRaw(h.bucketCounts)
+	dp.SetCount(h.count)
+	dp.SetSum(h.sum)
+	dp.SetMin(h.min)
+	dp.SetMax(h.max)
+}
+
+// series holds the metrics of the traces of a root operation.
+type series struct {
+	attributes   pcommon.Map
+	duration     *histogram
+	spanCount    *histogram
+	serviceCount *histogram
+	selfTimes    map[string]time.Duration
+}
+
+// aggregator aggregates the summaries of complete traces into delta metrics, per service of the root span.
+type aggregator struct {
+	config         *Config
+	durationBounds []float64
+	services       map[string]map[string]*series
+}
+
+func newAggregator(config *Config, durationBounds []float64) *aggregator {
+	return &aggregator{
+		config:         config,
+		durationBounds: durationBounds,
+		services:       make(map[string]map[string]*series),
+	}
+}
+
+func (a *aggregator) add(summary traceSummary) {
+	if summary.root == nil {
+		return
+	}
+
+	attributes := pcommon.NewMap()
+	attributes.PutStr(spanNameKey, summary.root.name)
+	attributes.PutBool(errorKey, summary.isError)
+	var key strings.Builder
+	key.WriteString(summary.root.name + keySeparator + strconv.FormatBool(summary.isError))
+	for i, d := range a.config.Dimensions {
+		v := summary.root.dimensions[i]
+		switch {
+		case v.Type() != pcommon.ValueTypeEmpty:
+			v.CopyTo(attributes.PutEmpty(d.Name))
+		case d.Default != nil:
+			attributes.PutStr(d.Name, *d.Default)
+		default:
+			continue
+		}
+		value, _ := attributes.Get(d.Name)
+		key.WriteString(keySeparator + d.Name + keySeparator + value.AsString())
+	}
+
+	services, ok := a.services[summary.root.service]
+	if !ok {
+		services = make(map[string]*series)
+		a.services[summary.root.service] = services
+	}
+	s, ok := services[key.String()]
+	if !ok {
+		s = &series{
+			attributes:   attributes,
+			duration:     newHistogram(a.durationBounds),
+			spanCount:    newHistogram(countHistogramBuckets),
+			serviceCount: newHistogram(countHistogramBuckets),
+			selfTimes:    make(map[string]time.Duration),
+		}
+		services[key.String()] = s
+	}
+
+	s.duration.observe(summary.duration.Seconds())
+	s.spanCount.observe(float64(summary.spanCount))
+	s.serviceCount.observe(float64(summary.serviceCount))
+	for service, selfTime := range summary.selfTimes {
+		s.selfTimes[service] += selfTime
+	}
+}
+
+// buildMetrics builds the delta metrics of the traces aggregated between the start and end timestamps.
+func (a *aggregator) buildMetrics(start, end pcommon.Timestamp) pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	services := make([]string, 0, len(a.services))
+	for service := range a.services {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+
+	for _, service := range services {
+		rm := md.ResourceMetrics().AppendEmpty()
+		rm.Resource().Attributes().PutStr(serviceNameKey, service)
+		sm := rm.ScopeMetrics().AppendEmpty()
+		sm.Scope().SetName(metadata.ScopeName)
+
+		duration := newHistogramMetric(sm, metricNameDuration, "s", "Duration of the traces, from the start of their first span to the end of their last span.")
+		spanCount := newHistogramMetric(sm, metricNameSpanCount, "{span}", "Number of spans of the traces.")
+		serviceCount := newHistogramMetric(sm, metricNameServiceCount, "{service}", "Number of services taking part in the traces.")
+		selfTime := sm.Metrics().AppendEmpty()
+		selfTime.SetName(metricNameCriticalPathSelfTime)
+		selfTime.SetUnit("s")
+		selfTime.SetDescription("Time spent by each service on the critical path of the traces.")
+		selfTimeSum := selfTime.SetEmptySum()
+		selfTimeSum.SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+		selfTimeSum.SetIsMonotonic(true)
+
+		keys := make([]string, 0, len(a.services[service]))
+		for key := range a.services[service] {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			s := a.services[service][key]
+			for _, h := range []struct {
+				metric    pmetric.HistogramDataPointSlice
+				histogram *histogram
+			}{
+				{duration, s.duration},
+				{spanCount, s.spanCount},
+				{serviceCount, s.serviceCount},
+			} {
+				dp := h.metric.AppendEmpty()
+				dp.SetStartTimestamp(start)
+				dp.SetTimestamp(end)
+				s.attributes.CopyTo(dp.Attributes())
+				h.histogram.copyTo(dp)
+			}
+
+			criticalPathServices := make([]string, 0, len(s.selfTimes))
+			for criticalPathService := range s.selfTimes {
+				criticalPathServices = append(criticalPathServices, criticalPathService)
+			}
+			sort.Strings(criticalPathServices)
+			for _, criticalPathService := range criticalPathServices {
+				dp := selfTimeSum.DataPoints().AppendEmpty()
+				dp.SetStartTimestamp(start)
+				dp.SetTimestamp(end)
+				s.attributes.CopyTo(dp.Attributes())
+				dp.Attributes().PutStr(criticalPathServiceNameKey, criticalPathService)
+				dp.SetDoubleValue(s.selfTimes[criticalPathService].Seconds())
+			}
+		}
+	}
+	return md
+}
+
+func newHistogramMetric(sm pmetric.ScopeMetrics, name, unit, description string) pmetric.HistogramDataPointSlice {
+	m := sm.Metrics().AppendEmpty()
+	m.SetName(name)
+	m.SetUnit(unit)
+	m.SetDescription(description)
+	h := m.SetEmptyHistogram()
+	h.SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+	return h.DataPoints()
+}
@@ -0,0 +1,132 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package tracemetricsconnector // import "github.com/open-telemetry/opentelemetry-collector-contrib/connector/tracemetricsconnector"
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+// spanData is the part of a span needed to describe its trace.
+type spanData struct {
+	spanID       pcommon.SpanID
+	parentSpanID pcommon.SpanID
+	name         string
+	service      string
+	start, end   pcommon.Timestamp
+	isError      bool
+	// dimensions are the values of the configured dimensions, in case the span is the root span
+	dimensions []pcommon.Value
+}
+
+// traceData holds the spans of a trace waiting for its completion.
+type traceData struct {
+	traceID  pcommon.TraceID
+	spans    []*spanData
+	lastSeen time.Time
+}
+
+// traceSummary describes a complete trace.
+type traceSummary struct {
+	root         *spanData
+	duration     time.Duration
+	spanCount    int
+	serviceCount int
+	isError      bool
+	// selfTimes is the time spent by each service on the critical path of the trace
+	selfTimes map[string]time.Duration
+}
+
+// summarize describes the trace from its spans. The root of the trace is the span without parent, or the
+// earliest span whose parent is missing in the trace if there is none.
+func (t *traceData) summarize() traceSummary {
+	summary := traceSummary{
+		spanCount: len(t.spans),
+		selfTimes: make(map[string]time.Duration),
+	}
+
+	byID := make(map[pcommon.SpanID]*spanData, len(t.spans))
+	for _, s := range t.spans {
+		byID[s.spanID] = s
+	}
+
+	services := make(map[string]struct{})
+	children := make(map[pcommon.SpanID][]*spanData)
+	var orphan *spanData
+	var start, end pcommon.Timestamp
+	for i, s := range t.spans {
+		services[s.service] = struct{}{}
+		summary.isError = summary.isError || s.isError
+		if i == 0 || s.start < start {
+			start = s.start
+		}
+		if i == 0 || s.end > end {
+			end = s.end
+		}
+
+		if s.parentSpanID.IsEmpty() {
+			if summary.root == nil || s.start < summary.root.start {
+				summary.root = s
+			}
+			continue
+		}
+		if _, ok := byID[s.parentSpanID]; !ok {
+			if orphan == nil || s.start < orphan.start {
+				orphan = s
+			}
+			continue
+		}
+		children[s.parentSpanID] = append(children[s.parentSpanID], s)
+	}
+	if summary.root == nil {
+		summary.root = orphan
+	}
+	summary.serviceCount = len(services)
+	if end > start {
+		summary.duration = time.Duration(end - start)
+	}
+
+	if summary.root != nil {
+		for _, c := range children {
+			slices.SortFunc(c, func(a, b *spanData) int {
+				return cmp.Compare(b.end, a.end)
+			})
+		}
+		walkCriticalPath(summary.root, summary.root.end, children, make(map[pcommon.SpanID]struct{}), summary.selfTimes)
+	}
+	return summary
+}
+
+// walkCriticalPath records the self time of the services on the critical path of the span, until the end
+// time. Walking backwards from the end, the critical path goes through the last finishing child span, then
+// through the last child span finishing before the start of the previous one, and so on. The time not
+// covered by the child spans on the critical path is the self time of the span.
+func walkCriticalPath(s *spanData, end pcommon.Timestamp, children map[pcommon.SpanID][]*spanData, visited map[pcommon.SpanID]struct{}, selfTimes map[string]time.Duration) {
+	if _, ok := visited[s.spanID]; ok {
+		return
+	}
+	visited[s.spanID] = struct{}{}
+
+	cursor := min(end, s.end)
+	for _, c := range children[s.spanID] {
+		if cursor <= s.start {
+			break
+		}
+		// The child spans finishing after their parent span are cut at its end, and the child spans
+		// overlapping the critical path already found aren't on it.
+		childEnd := min(c.end, s.end)
+		if childEnd > cursor || c.start >= cursor {
+			continue
+		}
+		selfTimes[s.service] += time.Duration(cursor - childEnd)
+		walkCriticalPath(c, childEnd, children, visited, selfTimes)
+		cursor = max(c.start, s.start)
+	}
+	if cursor > s.start {
+		selfTimes[s.service] += time.Duration(cursor - s.start)
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package tracemetricsconnector
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+func testSpan(id, parent byte, service string, start, end time.Duration) *spanData {
+	s := &spanData{
+		spanID:  pcommon.SpanID{id},
+		name:    "op-" + service,
+		service: service,
+		start:   pcommon.Timestamp(start),
+		end:     pcommon.Timestamp(end),
+	}
+	if parent != 0 {
+		s.parentSpanID = pcommon.SpanID{parent}
+	}
+	return s
+}
+
+func TestSummarize(t *testing.T) {
+	tests := []struct {
+		name              string
+		spans             []*spanData
+		expectedRoot      byte
+		expectedDuration  time.Duration
+		expectedServices  int
+		expectedSelfTimes map[string]time.Duration
+	}{
+		{
+			name:              "single span",
+			spans:             []*spanData{testSpan(1, 0, "a", 0, 100)},
+			expectedRoot:      1,
+			expectedDuration:  100,
+			expectedServices:  1,
+			expectedSelfTimes: map[string]time.Duration{"a": 100},
+		},
+		{
+			name: "sequential children",
+			spans: []*spanData{
+				testSpan(1, 0, "a", 0, 100),
+				testSpan(2, 1, "b", 10, 40),
+				testSpan(3, 1, "c", 50, 90),
+			},
+			expectedRoot:      1,
+			expectedDuration:  100,
+			expectedServices:  3,
+			expectedSelfTimes: map[string]time.Duration{"a": 30, "b": 30, "c": 40},
+		},
+		{
+			name: "overlapping children",
+			spans: []*spanData{
+				testSpan(1, 0, "a", 0, 100),
+				testSpan(2, 1, "b", 10, 60),
+				testSpan(3, 1, "c", 50, 90),
+			},
+			expectedRoot:      1,
+			expectedDuration:  100,
+			expectedServices:  3,
+			expectedSelfTimes: map[string]time.Duration{"a": 60, "c": 40},
+		},
+		{
+			name: "nested children",
+			spans: []*spanData{
+				testSpan(1, 0, "a", 0, 100),
+				testSpan(2, 1, "b", 10, 90),
+				testSpan(3, 2, "c", 20, 70),
+			},
+			expectedRoot:      1,
+			expectedDuration:  100,
+			expectedServices:  3,
+			expectedSelfTimes: map[string]time.Duration{"a": 20, "b": 30, "c": 50},
+		},
+		{
+			name: "child ending after its parent",
+			spans: []*spanData{
+				testSpan(1, 0, "a", 0, 100),
+				testSpan(2, 1, "b", 50, 120),
+			},
+			expectedRoot:      1,
+			expectedDuration:  120,
+			expectedServices:  2,
+			expectedSelfTimes: map[string]time.Duration{"a": 50, "b": 50},
+		},
+		{
+			name: "missing root span",
+			spans: []*spanData{
+				testSpan(2, 1, "b", 10, 90),
+				testSpan(3, 2, "c", 20, 70),
+				testSpan(4, 9, "d", 30, 40),
+			},
+			expectedRoot:      2,
+			expectedDuration:  80,
+			expectedServices:  3,
+			expectedSelfTimes: map[string]time.Duration{"b": 30, "c": 50},
+		},
+		{
+			name: "root span received last",
+			spans: []*spanData{
+				testSpan(2, 1, "b", 10, 90),
+				testSpan(1, 0, "a", 0, 100),
+			},
+			expectedRoot:      1,
+			expectedDuration:  100,
+			expectedServices:  2,
+			expectedSelfTimes: map[string]time.Duration{"a": 20, "b": 80},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			td := &traceData{spans: tt.spans}
+			summary := td.summarize()
+			assert.Equal(t, pcommon.SpanID{tt.expectedRoot}, summary.root.spanID)
+			assert.Equal(t, tt.expectedDuration, summary.duration)
+			assert.Equal(t, len(tt.spans), summary.spanCount)
+			assert.Equal(t, tt.expectedServices, summary.serviceCount)
+			assert.False(t, summary.isError)
+			assert.Equal(t, tt.expectedSelfTimes, summary.selfTimes)
+		})
+	}
+}
+
+func TestSummarizeError(t *testing.T) {
+	child := testSpan(2, 1, "b", 10, 90)
+	child.isError = true
+	td := &traceData{spans: []*spanData{testSpan(1, 0, "a", 0, 100), child}}
+	assert.True(t, td.summarize().isError)
+}
+
+func TestSummarizeCycle(t *testing.T) {
+	td := &traceData{spans: []*spanData{
+		testSpan(1, 0, "a", 0, 100),
+		testSpan(2, 3, "b", 10, 90),
+		testSpan(3, 2, "c", 20, 80),
+	}}
+	summary := td.summarize()
+	assert.Equal(t, pcommon.SpanID{1}, summary.root.spanID)
+	assert.Equal(t, map[string]time.Duration{"a": 100}, summary.selfTimes)
+}
@@ -5,6 +5,8 @@ package awss3exporter // import "github.com/open-telemetry/opentelemetry-collect
 
 import (
 	"errors"
+	"fmt"
+	"slices"
 	"time"
 
 	"go.opentelemetry.io/collector/component"
@@ -17,6 +19,9 @@ const (
 	DefaultRetryMode        = "standard"
 	DefaultRetryMaxAttempts = 3
 	DefaultRetryMaxBackoff  = 20 * time.Second
+
+	defaultRolloverMaxMegabytes = 64
+	defaultRolloverMaxAge       = 5 * time.Minute
 )
 
 // S3UploaderConfig contains aws s3 uploader related config to controls things
@@ -25,9 +30,11 @@ type S3UploaderConfig struct {
 	Region string `mapstructure:"region"`
 	// S3Bucket is the bucket name to be uploaded to.
 	S3Bucket string `mapstructure:"s3_bucket"`
-	// S3Prefix is the key (directory) prefix to written to inside the bucket
+	// S3Prefix is the key (directory) prefix to written to inside the bucket.
+	// It may reference resource attributes with %{<attribute>}.
 	S3Prefix string `mapstructure:"s3_prefix"`
 	// S3PartitionFormat is used to provide the rollup on how data is written. Uses [strftime](https://www.man7.org/linux/man-pages/man3/strftime.3.html) formatting.
+	// It may reference resource attributes with %{<attribute>}.
 	S3PartitionFormat string `mapstructure:"s3_partition_format"`
 	// FilePrefix is the filename prefix used for the file to avoid any potential collisions.
 	FilePrefix string `mapstructure:"file_prefix"`
@@ -67,6 +74,7 @@ const (
 	OtlpJSON     MarshalerType = "otlp_json"
 	SumoIC       MarshalerType = "sumo_ic"
 	Body         MarshalerType = "body"
+	Parquet      MarshalerType = "parquet"
 )
 
 // ResourceAttrsToS3 defines the mapping of S3 uploading configuration values to resource attribute values.
@@ -75,6 +83,25 @@ type ResourceAttrsToS3 struct {
 	S3Prefix string `mapstructure:"s3_prefix"`
 }
 
+// RolloverConfig defines the buffering of the batches sharing the same key prefix into larger objects.
+type RolloverConfig struct {
+	// Enabled buffers the batches, and uploads them as a single object once it is
+	// bigger than MaxMegabytes or older than MaxAge.
+	Enabled bool `mapstructure:"enabled"`
+	// MaxMegabytes is the size of the buffered data above which an object is uploaded.
+	// Defaults to 64 if zero.
+	MaxMegabytes int `mapstructure:"max_megabytes"`
+	// MaxAge is the time after which an object is uploaded, regardless of its size.
+	// Defaults to 5m if zero.
+	MaxAge time.Duration `mapstructure:"max_age"`
+	// MaxBufferedMegabytes is the size of the data of all the objects waiting to be uploaded
+	// above which the batches are refused. Defaults to four times MaxMegabytes if zero.
+	MaxBufferedMegabytes int `mapstructure:"max_buffered_megabytes"`
+	// StorageID is the storage extension persisting the buffered batches, so that they
+	// are uploaded after a restart. The batches are only buffered in memory if not set.
+	StorageID *component.ID `mapstructure:"storage"`
+}
+
 // Config contains the main configuration options for the s3 exporter
 type Config struct {
 	QueueSettings   exporterhelper.QueueBatchConfig `mapstructure:"sending_queue"`
@@ -86,6 +113,7 @@ type Config struct {
 	Encoding              *component.ID     `mapstructure:"encoding"`
 	EncodingFileExtension string            `mapstructure:"encoding_file_extension"`
 	ResourceAttrsToS3     ResourceAttrsToS3 `mapstructure:"resource_attrs_to_s3"`
+	Rollover              RolloverConfig    `mapstructure:"rollover"`
 }
 
 func (c *Config) Validate() error {
@@ -130,13 +158,59 @@ func (c *Config) Validate() error {
 			errs = multierr.Append(errs, errors.New("unknown compression type"))
 		}
 
-		if c.MarshalerName == SumoIC {
+		if c.MarshalerName == SumoIC || c.MarshalerName == Parquet {
 			errs = multierr.Append(errs, errors.New("marshaler does not support compression"))
 		}
 	}
 
+	if _, err := parseKeyTemplate(c.S3Uploader.S3Prefix); err != nil {
+		errs = multierr.Append(errs, fmt.Errorf("invalid s3_prefix: %w", err))
+	}
+	if _, err := parseKeyTemplate(c.S3Uploader.S3PartitionFormat); err != nil {
+		errs = multierr.Append(errs, fmt.Errorf("invalid s3_partition_format: %w", err))
+	}
+
+	if c.Rollover.MaxMegabytes < 0 {
+		errs = multierr.Append(errs, errors.New("rollover max_megabytes must not be negative"))
+	}
+	if c.Rollover.MaxAge < 0 {
+		errs = multierr.Append(errs, errors.New("rollover max_age must not be negative"))
+	}
+	if c.Rollover.MaxBufferedMegabytes < 0 {
+		errs = multierr.Append(errs, errors.New("rollover max_buffered_megabytes must not be negative"))
+	} else if c.Rollover.MaxBufferedMegabytes > 0 && c.Rollover.MaxBufferedMegabytes < c.Rollover.maxMegabytes() {
+		errs = multierr.Append(errs, errors.New("rollover max_buffered_megabytes must not be smaller than max_megabytes"))
+	}
+
 	if c.S3Uploader.RetryMode != "nop" && c.S3Uploader.RetryMode != "standard" && c.S3Uploader.RetryMode != "adaptive" {
 		errs = multierr.Append(errs, errors.New("invalid retry mode, must be either 'standard', 'adaptive' or 'nop'"))
 	}
 	return errs
 }
+
+// maxMegabytes returns the size of the buffered data above which an object is uploaded.
+func (c *RolloverConfig) maxMegabytes() int {
+	if c.MaxMegabytes > 0 {
+		return c.MaxMegabytes
+	}
+	return defaultRolloverMaxMegabytes
+}
+
+// partitionAttributes returns the resource attributes the keys of the objects depend on,
+// the batches being split so that each object only holds resources sharing their values.
+func (c *Config) partitionAttributes() []string {
+	var attributes []string
+	if c.ResourceAttrsToS3.S3Prefix != "" {
+		attributes = append(attributes, c.ResourceAttrsToS3.S3Prefix)
+	}
+	for _, s := range []string{c.S3Uploader.S3Prefix, c.S3Uploader.S3PartitionFormat} {
+		// The templates are validated.
+		t, _ := parseKeyTemplate(s)
+		for _, attribute := range t.attributes {
+			if !slices.Contains(attributes, attribute) {
+				attributes = append(attributes, attribute)
+			}
+		}
+	}
+	return attributes
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configcompression"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 	"go.opentelemetry.io/collector/otelcol/otelcoltest"
 	"go.uber.org/multierr"
@@ -323,6 +324,60 @@ func TestConfig_Validate(t *testing.T) {
 			}(),
 			errExpected: errors.New("region is required"),
 		},
+		{
+			name: "parquet with compression",
+			config: func() *Config {
+				c := createDefaultConfig().(*Config)
+				c.S3Uploader.Region = "foo"
+				c.S3Uploader.S3Bucket = "bar"
+				c.S3Uploader.Compression = configcompression.TypeGzip
+				c.MarshalerName = Parquet
+				return c
+			}(),
+			errExpected: errors.New("marshaler does not support compression"),
+		},
+		{
+			name: "invalid key templates",
+			config: func() *Config {
+				c := createDefaultConfig().(*Config)
+				c.S3Uploader.S3Bucket = "bar"
+				c.S3Uploader.S3Prefix = "%{}"
+				c.S3Uploader.S3PartitionFormat = "service=%{service.name"
+				return c
+			}(),
+			errExpected: multierr.Append(errors.New("invalid s3_prefix: empty attribute name in key template"),
+				errors.New("invalid s3_partition_format: unterminated %{ in key template")),
+		},
+		{
+			name: "negative rollover settings",
+			config: func() *Config {
+				c := createDefaultConfig().(*Config)
+				c.S3Uploader.S3Bucket = "bar"
+				c.Rollover = RolloverConfig{
+					Enabled:              true,
+					MaxMegabytes:         -1,
+					MaxAge:               -time.Second,
+					MaxBufferedMegabytes: -1,
+				}
+				return c
+			}(),
+			errExpected: multierr.Combine(errors.New("rollover max_megabytes must not be negative"),
+				errors.New("rollover max_age must not be negative"),
+				errors.New("rollover max_buffered_megabytes must not be negative")),
+		},
+		{
+			name: "rollover buffer smaller than objects",
+			config: func() *Config {
+				c := createDefaultConfig().(*Config)
+				c.S3Uploader.S3Bucket = "bar"
+				c.Rollover = RolloverConfig{
+					Enabled:              true,
+					MaxBufferedMegabytes: 32,
+				}
+				return c
+			}(),
+			errExpected: errors.New("rollover max_buffered_megabytes must not be smaller than max_megabytes"),
+		},
 	}
 
 	for _, tt := range tests {
@@ -481,6 +536,41 @@ func TestResourceAttrsToS3(t *testing.T) {
 	)
 }
 
+func TestRollover(t *testing.T) {
+	factories, err := otelcoltest.NopFactories()
+	assert.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Exporters[factory.Type()] = factory
+	cfg, err := otelcoltest.LoadConfigAndValidate(
+		filepath.Join("testdata", "config-s3_rollover.yaml"), factories)
+
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+
+	e := cfg.Exporters[component.MustNewID("awss3")].(*Config)
+	storageID := component.MustNewID("file_storage")
+
+	assert.Equal(t, S3UploaderConfig{
+		Region:            "us-east-1",
+		S3Bucket:          "foo",
+		S3Prefix:          "tenants/%{tenant}",
+		S3PartitionFormat: "service=%{service.name}/year=%Y/month=%m/day=%d/hour=%H",
+		StorageClass:      "STANDARD",
+		RetryMode:         DefaultRetryMode,
+		RetryMaxAttempts:  DefaultRetryMaxAttempts,
+		RetryMaxBackoff:   DefaultRetryMaxBackoff,
+	}, e.S3Uploader)
+	assert.Equal(t, RolloverConfig{
+		Enabled:              true,
+		MaxMegabytes:         128,
+		MaxAge:               10 * time.Minute,
+		MaxBufferedMegabytes: 512,
+		StorageID:            &storageID,
+	}, e.Rollover)
+	assert.Equal(t, []string{"tenant", "service.name"}, e.partitionAttributes())
+}
+
 func TestRetry(t *testing.T) {
 	factories, err := otelcoltest.NopFactories()
 	assert.NoError(t, err)
@@ -5,11 +5,13 @@ package awss3exporter // import "github.com/open-telemetry/opentelemetry-collect
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/extension/xextension/storage"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
@@ -21,10 +23,14 @@ import (
 
 type s3Exporter struct {
 	config     *Config
+	id         component.ID
 	signalType string
 	uploader   upload.Manager
 	logger     *zap.Logger
 	marshaler  marshaler
+
+	prefixTemplate    keyTemplate
+	partitionTemplate keyTemplate
 }
 
 func newS3Exporter(
@@ -32,24 +38,33 @@ func newS3Exporter(
 	signalType string,
 	params exporter.Settings,
 ) *s3Exporter {
+	// The templates are validated with the configuration.
+	prefixTemplate, _ := parseKeyTemplate(config.S3Uploader.S3Prefix)
+	partitionTemplate, _ := parseKeyTemplate(config.S3Uploader.S3PartitionFormat)
 	s3Exporter := &s3Exporter{
-		config:     config,
-		signalType: signalType,
-		logger:     params.Logger,
+		config:            config,
+		id:                params.ID,
+		signalType:        signalType,
+		logger:            params.Logger,
+		prefixTemplate:    prefixTemplate,
+		partitionTemplate: partitionTemplate,
 	}
 	return s3Exporter
 }
 
 func (e *s3Exporter) getUploadOpts(res pcommon.Resource) *upload.UploadOptions {
-	s3Prefix := ""
+	uploadOpts := &upload.UploadOptions{}
+	if len(e.prefixTemplate.attributes) > 0 {
+		uploadOpts.OverridePrefix = e.prefixTemplate.render(res, false)
+	}
+	if len(e.partitionTemplate.attributes) > 0 {
+		uploadOpts.OverridePartitionFormat = e.partitionTemplate.render(res, true)
+	}
 	if s3PrefixKey := e.config.ResourceAttrsToS3.S3Prefix; s3PrefixKey != "" {
 		if value, ok := res.Attributes().Get(s3PrefixKey); ok {
-			s3Prefix = value.AsString()
+			uploadOpts.OverridePrefix = value.AsString()
 		}
 	}
-	uploadOpts := &upload.UploadOptions{
-		OverridePrefix: s3Prefix,
-	}
 	return uploadOpts
 }
 
@@ -73,9 +88,112 @@ func (e *s3Exporter) start(ctx context.Context, host component.Host) error {
 		return err
 	}
 	e.uploader = up
+
+	if e.config.Rollover.Enabled {
+		return e.startRollover(ctx, host)
+	}
+	return nil
+}
+
+// startRollover buffers the batches before they are uploaded. They are buffered as OTLP protobuf,
+// whose concatenated messages are merged when unmarshaled, and marshaled with the configured
+// marshaler when the object is uploaded.
+func (e *s3Exporter) startRollover(ctx context.Context, host component.Host) error {
+	client, err := getStorageClient(ctx, host, e.config.Rollover.StorageID, e.id, e.signalType)
+	if err != nil {
+		return err
+	}
+
+	maxMegabytes := e.config.Rollover.maxMegabytes()
+	maxBufferedMegabytes := e.config.Rollover.MaxBufferedMegabytes
+	if maxBufferedMegabytes == 0 {
+		maxBufferedMegabytes = 4 * maxMegabytes
+	}
+	settings := upload.RolloverSettings{
+		MaxSize:         int64(maxMegabytes) * 1024 * 1024,
+		MaxAge:          defaultRolloverMaxAge,
+		MaxBufferedSize: int64(maxBufferedMegabytes) * 1024 * 1024,
+	}
+	if e.config.Rollover.MaxAge > 0 {
+		settings.MaxAge = e.config.Rollover.MaxAge
+	}
+
+	m := e.marshaler
+	var encode upload.EncodeFunc
+	switch e.signalType {
+	case "logs":
+		encode = func(data []byte) ([]byte, error) {
+			ld, err := (&plog.ProtoUnmarshaler{}).UnmarshalLogs(data)
+			if err != nil {
+				return nil, err
+			}
+			return m.MarshalLogs(ld)
+		}
+	case "metrics":
+		encode = func(data []byte) ([]byte, error) {
+			md, err := (&pmetric.ProtoUnmarshaler{}).UnmarshalMetrics(data)
+			if err != nil {
+				return nil, err
+			}
+			return m.MarshalMetrics(md)
+		}
+	case "traces":
+		encode = func(data []byte) ([]byte, error) {
+			td, err := (&ptrace.ProtoUnmarshaler{}).UnmarshalTraces(data)
+			if err != nil {
+				return nil, err
+			}
+			return m.MarshalTraces(td)
+		}
+	}
+
+	rollover := upload.NewRollover(
+		e.uploader,
+		newPartitionKeyBuilder(e.config, e.signalType, m.format()),
+		client,
+		encode,
+		settings,
+		e.logger,
+	)
+	if err := rollover.Start(ctx); err != nil {
+		return errors.Join(err, client.Close(ctx))
+	}
+	e.uploader = rollover
+	e.marshaler = &s3Marshaler{
+		logsMarshaler:    &plog.ProtoMarshaler{},
+		metricsMarshaler: &pmetric.ProtoMarshaler{},
+		tracesMarshaler:  &ptrace.ProtoMarshaler{},
+		logger:           e.logger,
+		fileFormat:       m.format(),
+	}
+	return nil
+}
+
+func (e *s3Exporter) shutdown(ctx context.Context) error {
+	if rollover, ok := e.uploader.(*upload.Rollover); ok {
+		return rollover.Shutdown(ctx)
+	}
 	return nil
 }
 
+func getStorageClient(ctx context.Context, host component.Host, storageID *component.ID, id component.ID, signalType string) (storage.Client, error) {
+	if storageID == nil {
+		return storage.NewNopClient(), nil
+	}
+
+	extension, ok := host.GetExtensions()[*storageID]
+	if !ok {
+		return nil, fmt.Errorf("storage extension '%s' not found", storageID)
+	}
+
+	storageExtension, ok := extension.(storage.Extension)
+	if !ok {
+		return nil, fmt.Errorf("non-storage extension '%s' found", storageID)
+	}
+
+	return storageExtension.GetClient(ctx, component.KindExporter, id, signalType)
+}
+
 func (e *s3Exporter) Capabilities() consumer.Capabilities {
 	return consumer.Capabilities{MutatesData: false}
 }
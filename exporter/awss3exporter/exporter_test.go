@@ -6,13 +6,25 @@ package awss3exporter
 import (
 	"context"
 	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.uber.org/zap"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/awss3exporter/internal/metadata"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/awss3exporter/internal/upload"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
 )
 
 var (
@@ -83,3 +95,144 @@ func TestLogWithResourceAttrs(t *testing.T) {
 	exporter := getLogExporterWithResourceAttrs(t)
 	assert.NoError(t, exporter.ConsumeLogs(context.Background(), logs))
 }
+
+func TestUploadOptsWithKeyTemplates(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.S3Uploader.S3Prefix = "tenants/%{tenant}"
+	config.S3Uploader.S3PartitionFormat = "service=%{service.name}/year=%Y"
+	exporter := newS3Exporter(config, "logs", exportertest.NewNopSettings(metadata.Type))
+
+	res := pcommon.NewResource()
+	res.Attributes().PutStr("tenant", "acme")
+	res.Attributes().PutStr("service.name", "checkout")
+	assert.Equal(t, &upload.UploadOptions{
+		OverridePrefix:          "tenants/acme",
+		OverridePartitionFormat: "service=checkout/year=%Y",
+	}, exporter.getUploadOpts(res))
+
+	// The resource attribute of resource_attrs_to_s3 takes precedence over the prefix.
+	config.ResourceAttrsToS3.S3Prefix = s3PrefixKey
+	res.Attributes().PutStr(s3PrefixKey, overridePrefix)
+	assert.Equal(t, &upload.UploadOptions{
+		OverridePrefix:          overridePrefix,
+		OverridePartitionFormat: "service=checkout/year=%Y",
+	}, exporter.getUploadOpts(res))
+}
+
+// s3StandIn is a minimal S3-compatible server storing the uploaded objects.
+type s3StandIn struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func newS3StandIn(t *testing.T) (*s3StandIn, string) {
+	s := &s3StandIn{objects: map[string][]byte{}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			w.WriteHeader(http.StatusNotImplemented)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.objects[r.URL.Path] = body
+	}))
+	t.Cleanup(server.Close)
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+	return s, server.URL
+}
+
+func (s *s3StandIn) keys() map[string][]byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	objects := map[string][]byte{}
+	for key, data := range s.objects {
+		// Drop the unique suffix of the keys.
+		objects[key[:strings.LastIndex(key, "/")]] = data
+	}
+	return objects
+}
+
+func TestLogsRollover(t *testing.T) {
+	standIn, endpoint := newS3StandIn(t)
+	storageID := storagetest.NewStorageID("buffer")
+
+	config := createDefaultConfig().(*Config)
+	config.S3Uploader.S3Bucket = "bucket"
+	config.S3Uploader.Endpoint = endpoint
+	config.S3Uploader.S3ForcePathStyle = true
+	config.S3Uploader.RetryMode = "nop"
+	config.S3Uploader.S3Prefix = "logs"
+	config.S3Uploader.S3PartitionFormat = "service=%{service.name}"
+	config.Rollover = RolloverConfig{
+		Enabled:   true,
+		MaxAge:    time.Hour,
+		StorageID: &storageID,
+	}
+	require.NoError(t, config.Validate())
+
+	host := storagetest.NewStorageHost().WithInMemoryStorageExtension("buffer")
+	exp, err := NewFactory().CreateLogs(context.Background(), exportertest.NewNopSettings(metadata.Type), config)
+	require.NoError(t, err)
+	require.NoError(t, exp.Start(context.Background(), host))
+
+	newLogs := func(services ...string) plog.Logs {
+		ld := plog.NewLogs()
+		for _, service := range services {
+			rl := ld.ResourceLogs().AppendEmpty()
+			rl.Resource().Attributes().PutStr("service.name", service)
+			rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr(service)
+		}
+		return ld
+	}
+	require.NoError(t, exp.ConsumeLogs(context.Background(), newLogs("checkout", "cart")))
+	require.NoError(t, exp.ConsumeLogs(context.Background(), newLogs("checkout")))
+	assert.Empty(t, standIn.keys(), "the batches are buffered")
+
+	require.NoError(t, exp.Shutdown(context.Background()))
+	objects := standIn.keys()
+	require.Len(t, objects, 2)
+	for key, records := range map[string]int{
+		"/bucket/logs/service=checkout": 2,
+		"/bucket/logs/service=cart":     1,
+	} {
+		require.Contains(t, objects, key)
+		ld, err := (&plog.JSONUnmarshaler{}).UnmarshalLogs(objects[key])
+		require.NoError(t, err)
+		assert.Equal(t, records, ld.LogRecordCount(), key)
+	}
+}
+
+func TestStartRolloverStorageErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		storageID component.ID
+		errVal    string
+	}{
+		{
+			name:      "missing storage",
+			storageID: storagetest.NewStorageID("missing"),
+			errVal:    "storage extension 'test_storage/missing' not found",
+		},
+		{
+			name:      "non-storage extension",
+			storageID: storagetest.NewNonStorageID("other"),
+			errVal:    "non-storage extension 'non_storage/other' found",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			config := createDefaultConfig().(*Config)
+			config.S3Uploader.S3Bucket = "bucket"
+			config.Rollover = RolloverConfig{Enabled: true, StorageID: &tc.storageID}
+			host := storagetest.NewStorageHost().WithNonStorageExtension("other")
+			exporter := newS3Exporter(config, "logs", exportertest.NewNopSettings(metadata.Type))
+			assert.EqualError(t, exporter.start(context.Background(), host), tc.errVal)
+		})
+	}
+}
@@ -80,6 +80,7 @@ func createLogsExporter(ctx context.Context,
 		config,
 		s3Exporter.ConsumeLogs,
 		exporterhelper.WithStart(s3Exporter.start),
+		exporterhelper.WithShutdown(s3Exporter.shutdown),
 		exporterhelper.WithQueue(cfg.QueueSettings),
 		exporterhelper.WithTimeout(cfg.TimeoutSettings),
 	)
@@ -87,13 +88,14 @@ func createLogsExporter(ctx context.Context,
 		return nil, err
 	}
 
-	if cfg.ResourceAttrsToS3.S3Prefix == "" {
+	attributes := cfg.partitionAttributes()
+	if len(attributes) == 0 {
 		return logsExporter, err
 	}
 
 	wrapped := &baseLogsExporter{
 		Component: logsExporter,
-		Logs:      batchperresourceattr.NewBatchPerResourceLogs(cfg.ResourceAttrsToS3.S3Prefix, logsExporter),
+		Logs:      batchperresourceattr.NewMultiBatchPerResourceLogs(attributes, logsExporter),
 	}
 	return wrapped, nil
 }
@@ -117,6 +119,7 @@ func createMetricsExporter(ctx context.Context,
 		config,
 		s3Exporter.ConsumeMetrics,
 		exporterhelper.WithStart(s3Exporter.start),
+		exporterhelper.WithShutdown(s3Exporter.shutdown),
 		exporterhelper.WithQueue(cfg.QueueSettings),
 		exporterhelper.WithTimeout(cfg.TimeoutSettings),
 	)
@@ -124,13 +127,14 @@ func createMetricsExporter(ctx context.Context,
 		return nil, err
 	}
 
-	if cfg.ResourceAttrsToS3.S3Prefix == "" {
+	attributes := cfg.partitionAttributes()
+	if len(attributes) == 0 {
 		return metricsExporter, err
 	}
 
 	wrapped := &baseMetricsExporter{
 		Component: metricsExporter,
-		Metrics:   batchperresourceattr.NewBatchPerResourceMetrics(cfg.ResourceAttrsToS3.S3Prefix, metricsExporter),
+		Metrics:   batchperresourceattr.NewMultiBatchPerResourceMetrics(attributes, metricsExporter),
 	}
 	return wrapped, nil
 }
@@ -155,6 +159,7 @@ func createTracesExporter(ctx context.Context,
 		config,
 		s3Exporter.ConsumeTraces,
 		exporterhelper.WithStart(s3Exporter.start),
+		exporterhelper.WithShutdown(s3Exporter.shutdown),
 		exporterhelper.WithQueue(cfg.QueueSettings),
 		exporterhelper.WithTimeout(cfg.TimeoutSettings),
 	)
@@ -162,13 +167,14 @@ func createTracesExporter(ctx context.Context,
 		return nil, err
 	}
 
-	if cfg.ResourceAttrsToS3.S3Prefix == "" {
+	attributes := cfg.partitionAttributes()
+	if len(attributes) == 0 {
 		return tracesExporter, err
 	}
 
 	wrapped := &baseTracesExporter{
 		Component: tracesExporter,
-		Traces:    batchperresourceattr.NewBatchPerResourceTraces(cfg.ResourceAttrsToS3.S3Prefix, tracesExporter),
+		Traces:    batchperresourceattr.NewMultiBatchPerResourceTraces(attributes, tracesExporter),
 	}
 	return wrapped, nil
 }
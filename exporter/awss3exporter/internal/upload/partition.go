@@ -49,16 +49,24 @@ func (pki *PartitionKeyBuilder) Build(ts time.Time, overridePrefix string) strin
 }
 
 func (pki *PartitionKeyBuilder) bucketKeyPrefix(ts time.Time, overridePrefix string) string {
-	// Don't want to overwrite the actual value
-	prefix := pki.PartitionPrefix
+	return pki.keyPrefix(ts, &UploadOptions{OverridePrefix: overridePrefix})
+}
+
+// keyPrefix returns the key prefix of the objects uploaded at ts with opts.
+func (pki *PartitionKeyBuilder) keyPrefix(ts time.Time, opts *UploadOptions) string {
+	// Don't want to overwrite the actual values
+	prefix, format := pki.PartitionPrefix, pki.PartitionFormat
 	// Only override when it's not empty string
-	if overridePrefix != "" {
-		prefix = overridePrefix
+	if opts != nil && opts.OverridePrefix != "" {
+		prefix = opts.OverridePrefix
+	}
+	if opts != nil && opts.OverridePartitionFormat != "" {
+		format = opts.OverridePartitionFormat
 	}
 	if prefix != "" {
 		prefix += "/"
 	}
-	return prefix + timefmt.Format(ts, pki.PartitionFormat)
+	return prefix + timefmt.Format(ts, format)
 }
 
 func (pki *PartitionKeyBuilder) fileName() string {
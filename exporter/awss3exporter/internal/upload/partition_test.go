@@ -152,6 +152,22 @@ func TestPartitionKeyInputsBucketPrefix(t *testing.T) {
 	}
 }
 
+func TestPartitionKeyInputsKeyPrefix(t *testing.T) {
+	t.Parallel()
+
+	inputs := &PartitionKeyBuilder{
+		PartitionPrefix: "telemetry",
+		PartitionFormat: "year=%Y/month=%m",
+	}
+	ts := time.Date(2024, 0o1, 24, 6, 40, 20, 0, time.Local)
+
+	assert.Equal(t, "telemetry/year=2024/month=01", inputs.keyPrefix(ts, nil))
+	assert.Equal(t, "tenant-a/service=checkout/day=24", inputs.keyPrefix(ts, &UploadOptions{
+		OverridePrefix:          "tenant-a",
+		OverridePartitionFormat: "service=checkout/day=%d",
+	}))
+}
+
 func TestPartitionKeyInputsFilename(t *testing.T) {
 	t.Parallel()
 
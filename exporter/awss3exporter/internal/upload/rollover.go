@@ -0,0 +1,306 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package upload // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/awss3exporter/internal/upload"
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/tilinna/clock"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/extension/xextension/storage"
+	"go.uber.org/zap"
+)
+
+// rolloverIndexKey is the storage key of the index of the buffered objects.
+const rolloverIndexKey = "rollover_index"
+
+// EncodeFunc encodes the data buffered for an object, made of the concatenated batches, into its content.
+type EncodeFunc func(data []byte) ([]byte, error)
+
+// RolloverSettings defines when the buffered objects are uploaded.
+type RolloverSettings struct {
+	// MaxSize is the size in bytes of the buffered data above which an object is uploaded.
+	MaxSize int64
+	// MaxAge is the time after which an object is uploaded, regardless of its size.
+	MaxAge time.Duration
+	// MaxBufferedSize is the size in bytes of the data of all the objects waiting to be uploaded
+	// above which the batches are refused. The buffered data isn't limited if zero.
+	MaxBufferedSize int64
+}
+
+// rolloverBuffer holds the batches of an object before it is uploaded.
+type rolloverBuffer struct {
+	ID      uint64        `json:"id"`
+	Options UploadOptions `json:"options"`
+	Created time.Time     `json:"created"`
+	Batches int           `json:"batches"`
+
+	data []byte
+	// uploading is set while the object is being uploaded, without the lock held.
+	uploading bool
+}
+
+// Rollover is a Manager buffering the batches sharing the same key prefix, and uploading
+// them as a single object once it is bigger than MaxSize or older than MaxAge.
+//
+// The batches are persisted with the storage client until the object is uploaded, so that
+// they are uploaded after a restart rather than lost. Once the objects waiting to be uploaded
+// hold more than MaxBufferedSize bytes, the batches are refused until they are uploaded.
+type Rollover struct {
+	manager  Manager
+	builder  *PartitionKeyBuilder
+	client   storage.Client
+	encode   EncodeFunc
+	settings RolloverSettings
+	logger   *zap.Logger
+
+	mu sync.Mutex
+	// buffers are the objects the batches are appended to, by key prefix. Once they are bigger than
+	// MaxSize or older than MaxAge, they are sealed: nothing is appended to them anymore, and they
+	// are uploaded without the lock held, remaining sealed until they are uploaded.
+	buffers  map[string]*rolloverBuffer
+	sealed   map[uint64]*rolloverBuffer
+	buffered int64
+	nextID   uint64
+	// uploads are the uploads of the objects sealed by Upload, waited for on shutdown.
+	uploads sync.WaitGroup
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+var _ Manager = (*Rollover)(nil)
+
+// NewRollover returns a Rollover uploading the objects with manager, whose keys are built with builder.
+func NewRollover(manager Manager, builder *PartitionKeyBuilder, client storage.Client, encode EncodeFunc, settings RolloverSettings, logger *zap.Logger) *Rollover {
+	return &Rollover{
+		manager:  manager,
+		builder:  builder,
+		client:   client,
+		encode:   encode,
+		settings: settings,
+		logger:   logger,
+		buffers:  map[string]*rolloverBuffer{},
+		sealed:   map[uint64]*rolloverBuffer{},
+		stop:     make(chan struct{}),
+	}
+}
+
+// Start restores the objects persisted by a previous run, and starts uploading the objects older than MaxAge.
+func (r *Rollover) Start(ctx context.Context) error {
+	if err := r.restore(ctx); err != nil {
+		return err
+	}
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		ticker := time.NewTicker(min(r.settings.MaxAge, time.Second))
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				r.flushExpired(context.Background())
+			case <-r.stop:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Upload appends data to the object with the same key prefix, and uploads it if it is bigger than MaxSize.
+func (r *Rollover) Upload(ctx context.Context, data []byte, opts *UploadOptions) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	var options UploadOptions
+	if opts != nil {
+		options = *opts
+	}
+	if options.Timestamp.IsZero() {
+		options.Timestamp = clock.Now(ctx)
+	}
+	key := r.builder.keyPrefix(options.Timestamp, &options)
+
+	r.mu.Lock()
+	if r.settings.MaxBufferedSize > 0 && r.buffered > 0 && r.buffered+int64(len(data)) > r.settings.MaxBufferedSize {
+		buffered := r.buffered
+		r.mu.Unlock()
+		return fmt.Errorf("refusing the batch: %d bytes are waiting to be uploaded", buffered)
+	}
+	b, ok := r.buffers[key]
+	if !ok {
+		b = &rolloverBuffer{ID: r.nextID, Options: options, Created: time.Now()}
+		r.nextID++
+		r.buffers[key] = b
+	}
+	b.data = append(b.data, data...)
+	b.Batches++
+	index, err := r.index()
+	if err == nil {
+		err = r.client.Batch(ctx,
+			storage.SetOperation(batchKey(b.ID, b.Batches-1), data),
+			storage.SetOperation(rolloverIndexKey, index),
+		)
+	}
+	if err != nil {
+		b.data = b.data[:len(b.data)-len(data)]
+		b.Batches--
+		if b.Batches == 0 {
+			delete(r.buffers, key)
+		}
+		r.mu.Unlock()
+		return fmt.Errorf("failed to persist the batch: %w", err)
+	}
+	r.buffered += int64(len(data))
+
+	if int64(len(b.data)) < r.settings.MaxSize {
+		r.mu.Unlock()
+		return nil
+	}
+	r.seal(key, b)
+	b.uploading = true
+	r.uploads.Add(1)
+	r.mu.Unlock()
+
+	defer r.uploads.Done()
+	if err := r.flush(ctx, b); err != nil {
+		// The batch is persisted, and the object is uploaded again on expiry: retrying the batch would duplicate it.
+		return consumererror.NewPermanent(fmt.Errorf("the batch is buffered, but uploading its object failed: %w", err))
+	}
+	return nil
+}
+
+// Shutdown uploads all the buffered objects. The objects that can't be uploaded remain
+// persisted, to be uploaded after a restart.
+func (r *Rollover) Shutdown(ctx context.Context) error {
+	close(r.stop)
+	r.wg.Wait()
+	r.uploads.Wait()
+
+	var errs error
+	for _, b := range r.sealAll(func(*rolloverBuffer) bool { return true }) {
+		errs = errors.Join(errs, r.flush(ctx, b))
+	}
+	return errors.Join(errs, r.client.Close(ctx))
+}
+
+func (r *Rollover) flushExpired(ctx context.Context) {
+	expired := r.sealAll(func(b *rolloverBuffer) bool {
+		return time.Since(b.Created) >= r.settings.MaxAge
+	})
+	for _, b := range expired {
+		if err := r.flush(ctx, b); err != nil {
+			r.logger.Warn("Failed to upload object", zap.Uint64("id", b.ID), zap.Error(err))
+		}
+	}
+}
+
+// sealAll seals the buffers matching filter, and returns the sealed objects matching it which aren't being uploaded,
+// marking them as being uploaded.
+func (r *Rollover) sealAll(filter func(*rolloverBuffer) bool) []*rolloverBuffer {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for key, b := range r.buffers {
+		if filter(b) {
+			r.seal(key, b)
+		}
+	}
+	var objects []*rolloverBuffer
+	for _, b := range r.sealed {
+		if !b.uploading && filter(b) {
+			b.uploading = true
+			objects = append(objects, b)
+		}
+	}
+	return objects
+}
+
+// seal stops appending the batches to the buffer of key. It must be called with the lock held.
+func (r *Rollover) seal(key string, b *rolloverBuffer) {
+	delete(r.buffers, key)
+	r.sealed[b.ID] = b
+}
+
+// flush uploads the sealed object, marked as being uploaded, and removes it. It must be called without the lock held.
+func (r *Rollover) flush(ctx context.Context, b *rolloverBuffer) error {
+	content, err := r.encode(b.data)
+	if err == nil {
+		if err = r.manager.Upload(ctx, content, &b.Options); err != nil {
+			r.mu.Lock()
+			b.uploading = false
+			r.mu.Unlock()
+			return err
+		}
+	} else {
+		// The data will never be encoded: drop it rather than retrying forever.
+		err = fmt.Errorf("dropping object with %d batches: %w", b.Batches, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.sealed, b.ID)
+	r.buffered -= int64(len(b.data))
+	index, indexErr := r.index()
+	if indexErr != nil {
+		return errors.Join(err, indexErr)
+	}
+	ops := []*storage.Operation{storage.SetOperation(rolloverIndexKey, index)}
+	for i := 0; i < b.Batches; i++ {
+		ops = append(ops, storage.DeleteOperation(batchKey(b.ID, i)))
+	}
+	return errors.Join(err, r.client.Batch(ctx, ops...))
+}
+
+// restore loads the objects persisted by a previous run.
+func (r *Rollover) restore(ctx context.Context) error {
+	index, err := r.client.Get(ctx, rolloverIndexKey)
+	if err != nil || len(index) == 0 {
+		return err
+	}
+	var buffers []*rolloverBuffer
+	if err := json.Unmarshal(index, &buffers); err != nil {
+		return fmt.Errorf("failed to read the index of the buffered objects: %w", err)
+	}
+	for _, b := range buffers {
+		for i := 0; i < b.Batches; i++ {
+			data, err := r.client.Get(ctx, batchKey(b.ID, i))
+			if err != nil {
+				return err
+			}
+			b.data = append(b.data, data...)
+		}
+		// The batches are appended to a single object per key prefix, the other objects having been sealed.
+		key := r.builder.keyPrefix(b.Options.Timestamp, &b.Options)
+		if _, ok := r.buffers[key]; ok {
+			r.sealed[b.ID] = b
+		} else {
+			r.buffers[key] = b
+		}
+		r.buffered += int64(len(b.data))
+		r.nextID = max(r.nextID, b.ID+1)
+	}
+	return nil
+}
+
+func (r *Rollover) index() ([]byte, error) {
+	buffers := make([]*rolloverBuffer, 0, len(r.buffers)+len(r.sealed))
+	for _, b := range r.buffers {
+		buffers = append(buffers, b)
+	}
+	for _, b := range r.sealed {
+		buffers = append(buffers, b)
+	}
+	return json.Marshal(buffers)
+}
+
+func batchKey(id uint64, batch int) string {
+	return fmt.Sprintf("rollover_%d_%d", id, batch)
+}
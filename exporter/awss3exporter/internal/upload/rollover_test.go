@@ -0,0 +1,236 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package upload
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tilinna/clock"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
+)
+
+type uploadedObject struct {
+	data []byte
+	opts UploadOptions
+}
+
+type recordingManager struct {
+	// block, if set, blocks the uploads until it is closed.
+	block   chan struct{}
+	mu      sync.Mutex
+	err     error
+	objects []uploadedObject
+}
+
+func (m *recordingManager) Upload(_ context.Context, data []byte, opts *UploadOptions) error {
+	if m.block != nil {
+		<-m.block
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return m.err
+	}
+	m.objects = append(m.objects, uploadedObject{data: data, opts: *opts})
+	return nil
+}
+
+func (m *recordingManager) uploaded() []uploadedObject {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]uploadedObject(nil), m.objects...)
+}
+
+func testBuilder() *PartitionKeyBuilder {
+	return &PartitionKeyBuilder{
+		PartitionPrefix: "telemetry",
+		PartitionFormat: "year=%Y/month=%m/day=%d/hour=%H",
+	}
+}
+
+func identity(data []byte) ([]byte, error) {
+	return data, nil
+}
+
+func TestRolloverMaxSize(t *testing.T) {
+	manager := &recordingManager{}
+	client := storagetest.NewInMemoryClient(component.KindExporter, component.MustNewID("awss3"), "logs")
+	r := NewRollover(manager, testBuilder(), client, identity, RolloverSettings{MaxSize: 10, MaxAge: time.Hour}, zap.NewNop())
+	require.NoError(t, r.Start(context.Background()))
+
+	ts := time.Date(2024, 0o1, 10, 10, 30, 40, 0, time.UTC)
+	ctx := clock.Context(context.Background(), clock.NewMock(ts))
+	require.NoError(t, r.Upload(ctx, []byte("hello"), nil))
+	require.NoError(t, r.Upload(ctx, []byte("other"), &UploadOptions{OverridePrefix: "tenant-a"}))
+	require.NoError(t, r.Upload(ctx, nil, nil))
+	assert.Empty(t, manager.uploaded())
+
+	require.NoError(t, r.Upload(ctx, []byte("world"), nil))
+	assert.Equal(t, []uploadedObject{
+		{data: []byte("helloworld"), opts: UploadOptions{Timestamp: ts}},
+	}, manager.uploaded())
+
+	// The remaining objects are uploaded on shutdown.
+	require.NoError(t, r.Shutdown(context.Background()))
+	assert.Equal(t, []uploadedObject{
+		{data: []byte("helloworld"), opts: UploadOptions{Timestamp: ts}},
+		{data: []byte("other"), opts: UploadOptions{OverridePrefix: "tenant-a", Timestamp: ts}},
+	}, manager.uploaded())
+}
+
+func TestRolloverPartitions(t *testing.T) {
+	manager := &recordingManager{}
+	client := storagetest.NewInMemoryClient(component.KindExporter, component.MustNewID("awss3"), "logs")
+	r := NewRollover(manager, testBuilder(), client, identity, RolloverSettings{MaxSize: 100, MaxAge: time.Hour}, zap.NewNop())
+	require.NoError(t, r.Start(context.Background()))
+
+	mc := clock.NewMock(time.Date(2024, 0o1, 10, 10, 30, 40, 0, time.UTC))
+	ctx := clock.Context(context.Background(), mc)
+	require.NoError(t, r.Upload(ctx, []byte("a"), nil))
+	mc.Add(10 * time.Minute)
+	require.NoError(t, r.Upload(ctx, []byte("b"), nil))
+	// The batches of another hour go to another object.
+	mc.Add(time.Hour)
+	require.NoError(t, r.Upload(ctx, []byte("c"), nil))
+	require.NoError(t, r.Shutdown(context.Background()))
+
+	objects := manager.uploaded()
+	require.Len(t, objects, 2)
+	var data []string
+	for _, o := range objects {
+		data = append(data, string(o.data))
+	}
+	assert.ElementsMatch(t, []string{"ab", "c"}, data)
+}
+
+func TestRolloverMaxAge(t *testing.T) {
+	manager := &recordingManager{}
+	client := storagetest.NewInMemoryClient(component.KindExporter, component.MustNewID("awss3"), "logs")
+	r := NewRollover(manager, testBuilder(), client, identity, RolloverSettings{MaxSize: 100, MaxAge: 10 * time.Millisecond}, zap.NewNop())
+	require.NoError(t, r.Start(context.Background()))
+	t.Cleanup(func() { require.NoError(t, r.Shutdown(context.Background())) })
+
+	require.NoError(t, r.Upload(context.Background(), []byte("hello"), nil))
+	assert.Eventually(t, func() bool {
+		return len(manager.uploaded()) == 1
+	}, 5*time.Second, 5*time.Millisecond)
+	assert.Equal(t, []byte("hello"), manager.uploaded()[0].data)
+}
+
+func TestRolloverRestart(t *testing.T) {
+	dir := t.TempDir()
+	id := component.MustNewID("awss3")
+	settings := RolloverSettings{MaxSize: 100, MaxAge: time.Hour}
+	ts := time.Date(2024, 0o1, 10, 10, 30, 40, 0, time.UTC)
+	ctx := clock.Context(context.Background(), clock.NewMock(ts))
+
+	// The objects that can't be uploaded on shutdown remain persisted.
+	manager := &recordingManager{err: errors.New("unavailable")}
+	client := storagetest.NewFileBackedClient(component.KindExporter, id, "logs", dir)
+	r := NewRollover(manager, testBuilder(), client, identity, settings, zap.NewNop())
+	require.NoError(t, r.Start(context.Background()))
+	require.NoError(t, r.Upload(ctx, []byte("hello"), nil))
+	require.NoError(t, r.Upload(ctx, []byte("world"), nil))
+	require.NoError(t, r.Upload(ctx, []byte("other"), &UploadOptions{OverridePrefix: "tenant-a"}))
+	require.EqualError(t, r.Shutdown(context.Background()), "unavailable\nunavailable")
+
+	manager = &recordingManager{}
+	client = storagetest.NewFileBackedClient(component.KindExporter, id, "logs", dir)
+	r = NewRollover(manager, testBuilder(), client, identity, settings, zap.NewNop())
+	require.NoError(t, r.Start(context.Background()))
+	require.NoError(t, r.Upload(ctx, []byte("!"), nil))
+	require.NoError(t, r.Shutdown(context.Background()))
+	assert.ElementsMatch(t, []uploadedObject{
+		{data: []byte("helloworld!"), opts: UploadOptions{Timestamp: ts}},
+		{data: []byte("other"), opts: UploadOptions{OverridePrefix: "tenant-a", Timestamp: ts}},
+	}, manager.uploaded())
+
+	// Nothing remains after the objects are uploaded.
+	client = storagetest.NewFileBackedClient(component.KindExporter, id, "logs", dir)
+	index, err := client.Get(context.Background(), rolloverIndexKey)
+	require.NoError(t, err)
+	assert.JSONEq(t, "[]", string(index))
+	data, err := client.Get(context.Background(), batchKey(0, 0))
+	require.NoError(t, err)
+	assert.Nil(t, data)
+}
+
+func TestRolloverEncodeError(t *testing.T) {
+	manager := &recordingManager{}
+	client := storagetest.NewInMemoryClient(component.KindExporter, component.MustNewID("awss3"), "logs")
+	encode := func([]byte) ([]byte, error) {
+		return nil, errors.New("invalid data")
+	}
+	r := NewRollover(manager, testBuilder(), client, encode, RolloverSettings{MaxSize: 100, MaxAge: time.Hour}, zap.NewNop())
+	require.NoError(t, r.Start(context.Background()))
+	require.NoError(t, r.Upload(context.Background(), []byte("hello"), nil))
+	assert.EqualError(t, r.Shutdown(context.Background()), "dropping object with 1 batches: invalid data")
+	assert.Empty(t, manager.uploaded())
+}
+
+func TestRolloverUploadWithoutLock(t *testing.T) {
+	manager := &recordingManager{block: make(chan struct{})}
+	client := storagetest.NewInMemoryClient(component.KindExporter, component.MustNewID("awss3"), "logs")
+	r := NewRollover(manager, testBuilder(), client, identity, RolloverSettings{MaxSize: 5, MaxAge: time.Hour}, zap.NewNop())
+	require.NoError(t, r.Start(context.Background()))
+
+	uploaded := make(chan error)
+	go func() {
+		uploaded <- r.Upload(context.Background(), []byte("hello"), nil)
+	}()
+	// The batches are buffered while an object is uploaded.
+	require.Eventually(t, func() bool {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		return len(r.sealed) == 1
+	}, 5*time.Second, 5*time.Millisecond)
+	require.NoError(t, r.Upload(context.Background(), []byte("a"), &UploadOptions{OverridePrefix: "tenant-a"}))
+
+	close(manager.block)
+	require.NoError(t, <-uploaded)
+	require.NoError(t, r.Shutdown(context.Background()))
+	var data []string
+	for _, o := range manager.uploaded() {
+		data = append(data, string(o.data))
+	}
+	assert.Equal(t, []string{"hello", "a"}, data)
+}
+
+func TestRolloverUploadError(t *testing.T) {
+	manager := &recordingManager{err: errors.New("unavailable")}
+	client := storagetest.NewInMemoryClient(component.KindExporter, component.MustNewID("awss3"), "logs")
+	settings := RolloverSettings{MaxSize: 10, MaxAge: time.Hour, MaxBufferedSize: 10}
+	r := NewRollover(manager, testBuilder(), client, identity, settings, zap.NewNop())
+	require.NoError(t, r.Start(context.Background()))
+
+	require.NoError(t, r.Upload(context.Background(), []byte("hello"), nil))
+	// The batch is buffered: the error is permanent, so that the batch isn't retried.
+	err := r.Upload(context.Background(), []byte("world"), nil)
+	require.EqualError(t, err, "the batch is buffered, but uploading its object failed: unavailable")
+	assert.True(t, consumererror.IsPermanent(err))
+
+	// The batches are refused while the buffered data is bigger than MaxBufferedSize.
+	err = r.Upload(context.Background(), []byte("!"), nil)
+	require.EqualError(t, err, "refusing the batch: 10 bytes are waiting to be uploaded")
+	assert.False(t, consumererror.IsPermanent(err))
+
+	// The object is uploaded again, once.
+	manager.mu.Lock()
+	manager.err = nil
+	manager.mu.Unlock()
+	require.NoError(t, r.Shutdown(context.Background()))
+	objects := manager.uploaded()
+	require.Len(t, objects, 1)
+	assert.Equal(t, []byte("helloworld"), objects[0].data)
+}
@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"compress/gzip"
 	"context"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
@@ -24,6 +25,10 @@ type ManagerOpt func(Manager)
 
 type UploadOptions struct {
 	OverridePrefix string
+	// OverridePartitionFormat replaces the partition format of the key when it isn't empty.
+	OverridePartitionFormat string
+	// Timestamp is the time used to build the key, the current time if it is zero.
+	Timestamp time.Time
 }
 
 type s3manager struct {
@@ -68,15 +73,13 @@ func (sw *s3manager) Upload(ctx context.Context, data []byte, opts *UploadOption
 	}
 
 	now := clock.Now(ctx)
-
-	overridePrefix := ""
-	if opts != nil {
-		overridePrefix = opts.OverridePrefix
+	if opts != nil && !opts.Timestamp.IsZero() {
+		now = opts.Timestamp
 	}
 
 	_, err = sw.uploader.Upload(ctx, &s3.PutObjectInput{
 		Bucket:          aws.String(sw.bucket),
-		Key:             aws.String(sw.builder.Build(now, overridePrefix)),
+		Key:             aws.String(sw.builder.keyPrefix(now, opts) + "/" + sw.builder.fileName()),
 		Body:            content,
 		ContentEncoding: aws.String(encoding),
 		StorageClass:    sw.storageClass,
@@ -177,6 +177,29 @@ func TestS3ManagerUpload(t *testing.T) {
 			errVal:      "",
 			uploadOpts:  &UploadOptions{OverridePrefix: ""},
 		},
+		{
+			name: "upload with partition format and timestamp",
+			handler: func(t *testing.T) http.Handler {
+				return http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+					_, _ = io.Copy(io.Discard, r.Body)
+					_ = r.Body.Close()
+
+					assert.Equal(
+						t,
+						"/my-bucket/telemetry/service=checkout/year=2023/signal-data-noop_random.metrics",
+						r.URL.Path,
+						"Must match the expected path",
+					)
+				})
+			},
+			compression: configcompression.Type(""),
+			data:        []byte("hello world"),
+			errVal:      "",
+			uploadOpts: &UploadOptions{
+				OverridePartitionFormat: "service=checkout/year=%Y",
+				Timestamp:               time.Date(2023, 0o5, 1, 0, 0, 0, 0, time.Local),
+			},
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
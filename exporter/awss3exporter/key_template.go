@@ -0,0 +1,76 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package awss3exporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/awss3exporter"
+
+import (
+	"errors"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+// missingAttributeValue replaces the resource attributes missing from the resource in the keys.
+const missingAttributeValue = "unknown"
+
+// keyTemplate is a part of the S3 keys referencing resource attributes with %{<attribute>},
+// e.g. "service=%{service.name}/year=%Y".
+type keyTemplate struct {
+	// literals holds the text around the attributes, it has one more element than attributes.
+	literals   []string
+	attributes []string
+}
+
+func parseKeyTemplate(s string) (keyTemplate, error) {
+	var t keyTemplate
+	var literal strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '%' || i+1 == len(s) {
+			literal.WriteByte(s[i])
+			continue
+		}
+		if s[i+1] != '{' {
+			// Keep the strftime directives, including %%, as they are.
+			literal.WriteString(s[i : i+2])
+			i++
+			continue
+		}
+		end := strings.IndexByte(s[i:], '}')
+		if end < 0 {
+			return keyTemplate{}, errors.New("unterminated %{ in key template")
+		}
+		attribute := s[i+2 : i+end]
+		if attribute == "" {
+			return keyTemplate{}, errors.New("empty attribute name in key template")
+		}
+		t.literals = append(t.literals, literal.String())
+		t.attributes = append(t.attributes, attribute)
+		literal.Reset()
+		i += end
+	}
+	t.literals = append(t.literals, literal.String())
+	return t, nil
+}
+
+// render replaces the attributes of the template with their values in res. The slashes of the values
+// are replaced so that they don't create extra levels in the keys, and, if escapePercent is set, their
+// percent signs are escaped so that they aren't interpreted as strftime directives.
+func (t keyTemplate) render(res pcommon.Resource, escapePercent bool) string {
+	if len(t.attributes) == 0 {
+		return t.literals[0]
+	}
+	var b strings.Builder
+	for i, attribute := range t.attributes {
+		b.WriteString(t.literals[i])
+		value := missingAttributeValue
+		if v, ok := res.Attributes().Get(attribute); ok && v.AsString() != "" {
+			value = strings.ReplaceAll(v.AsString(), "/", "_")
+		}
+		if escapePercent {
+			value = strings.ReplaceAll(value, "%", "%%")
+		}
+		b.WriteString(value)
+	}
+	b.WriteString(t.literals[len(t.literals)-1])
+	return b.String()
+}
@@ -0,0 +1,85 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package awss3exporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+func TestKeyTemplate(t *testing.T) {
+	res := pcommon.NewResource()
+	res.Attributes().PutStr("service.name", "checkout")
+	res.Attributes().PutStr("tenant", "acme/eu")
+	res.Attributes().PutStr("percent", "100%")
+	res.Attributes().PutInt("shard", 3)
+
+	for _, tc := range []struct {
+		name          string
+		template      string
+		escapePercent bool
+		attributes    []string
+		expect        string
+	}{
+		{
+			name:     "no attributes",
+			template: "year=%Y/month=%m/%%",
+			expect:   "year=%Y/month=%m/%%",
+		},
+		{
+			name:       "attributes",
+			template:   "service=%{service.name}/shard=%{shard}/year=%Y",
+			attributes: []string{"service.name", "shard"},
+			expect:     "service=checkout/shard=3/year=%Y",
+		},
+		{
+			name:       "attribute only",
+			template:   "%{service.name}",
+			attributes: []string{"service.name"},
+			expect:     "checkout",
+		},
+		{
+			name:       "slashes are replaced",
+			template:   "tenant=%{tenant}",
+			attributes: []string{"tenant"},
+			expect:     "tenant=acme_eu",
+		},
+		{
+			name:       "missing attribute",
+			template:   "region=%{cloud.region}/",
+			attributes: []string{"cloud.region"},
+			expect:     "region=unknown/",
+		},
+		{
+			name:          "percent signs are escaped",
+			template:      "%{percent}/%H",
+			escapePercent: true,
+			attributes:    []string{"percent"},
+			expect:        "100%%/%H",
+		},
+		{
+			name:       "percent signs are kept",
+			template:   "%{percent}",
+			attributes: []string{"percent"},
+			expect:     "100%",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			kt, err := parseKeyTemplate(tc.template)
+			require.NoError(t, err)
+			assert.Equal(t, tc.attributes, kt.attributes)
+			assert.Equal(t, tc.expect, kt.render(res, tc.escapePercent))
+		})
+	}
+}
+
+func TestKeyTemplateErrors(t *testing.T) {
+	_, err := parseKeyTemplate("service=%{service.name")
+	assert.EqualError(t, err, "unterminated %{ in key template")
+	_, err = parseKeyTemplate("service=%{}")
+	assert.EqualError(t, err, "empty attribute name in key template")
+}
@@ -12,6 +12,8 @@ import (
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/parquet"
 )
 
 type marshaler interface {
@@ -58,6 +60,11 @@ func newMarshaler(mType MarshalerType, logger *zap.Logger) (marshaler, error) {
 		exportbodyMarshaler := newbodyMarshaler()
 		marshaler.logsMarshaler = &exportbodyMarshaler
 		marshaler.fileFormat = exportbodyMarshaler.format()
+	case Parquet:
+		marshaler.logsMarshaler = parquet.Marshaler{}
+		marshaler.tracesMarshaler = parquet.Marshaler{}
+		marshaler.metricsMarshaler = parquet.Marshaler{}
+		marshaler.fileFormat = parquet.FileExtension
 	default:
 		return nil, ErrUnknownMarshaler
 	}
@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/plog"
 	"go.uber.org/zap"
 )
 
@@ -44,6 +45,16 @@ func TestMarshaler(t *testing.T) {
 		require.NotNil(t, m)
 		assert.Equal(t, "txt", m.format())
 	}
+	{
+		m, err := newMarshaler("parquet", zap.NewNop())
+		assert.NoError(t, err)
+		require.NotNil(t, m)
+		assert.Equal(t, "parquet", m.format())
+
+		buf, err := m.MarshalLogs(plog.NewLogs())
+		assert.NoError(t, err)
+		assert.Equal(t, "PAR1", string(buf[:4]))
+	}
 }
 
 type hostWithExtensions struct {
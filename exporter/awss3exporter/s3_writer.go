@@ -80,16 +80,20 @@ func newUploadManager(
 
 	return upload.NewS3Manager(
 		conf.S3Uploader.S3Bucket,
-		&upload.PartitionKeyBuilder{
-			PartitionPrefix: conf.S3Uploader.S3Prefix,
-			PartitionFormat: conf.S3Uploader.S3PartitionFormat,
-			FilePrefix:      conf.S3Uploader.FilePrefix,
-			Metadata:        metadata,
-			FileFormat:      format,
-			Compression:     conf.S3Uploader.Compression,
-		},
+		newPartitionKeyBuilder(conf, metadata, format),
 		s3.NewFromConfig(cfg, s3Opts...),
 		s3types.StorageClass(conf.S3Uploader.StorageClass),
 		managerOpts...,
 	), nil
 }
+
+func newPartitionKeyBuilder(conf *Config, metadata string, format string) *upload.PartitionKeyBuilder {
+	return &upload.PartitionKeyBuilder{
+		PartitionPrefix: conf.S3Uploader.S3Prefix,
+		PartitionFormat: conf.S3Uploader.S3PartitionFormat,
+		FilePrefix:      conf.S3Uploader.FilePrefix,
+		Metadata:        metadata,
+		FileFormat:      format,
+		Compression:     conf.S3Uploader.Compression,
+	}
+}
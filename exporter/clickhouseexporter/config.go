@@ -8,6 +8,9 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"regexp"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/ClickHouse/clickhouse-go/v2"
@@ -64,6 +67,12 @@ type Config struct {
 	AsyncInsert bool `mapstructure:"async_insert"`
 	// MetricsTables defines the table names for metric types.
 	MetricsTables MetricTablesConfig `mapstructure:"metrics_tables"`
+	// MigrationsTableName is the table name tracking the schema migrations applied to the other tables. default is `otel_schema_migrations`.
+	MigrationsTableName string `mapstructure:"migrations_table_name"`
+	// LogsColumns promotes attributes to dedicated columns of the logs table.
+	LogsColumns []ColumnMapping `mapstructure:"logs_columns"`
+	// TracesColumns promotes attributes to dedicated columns of the traces table.
+	TracesColumns []ColumnMapping `mapstructure:"traces_columns"`
 }
 
 type MetricTablesConfig struct {
@@ -79,6 +88,85 @@ type MetricTablesConfig struct {
 	ExponentialHistogram internal.MetricTypeConfig `mapstructure:"exponential_histogram"`
 }
 
+// ColumnMapping promotes an attribute to a dedicated typed column, materialized from the attribute map column.
+type ColumnMapping struct {
+	// Name is the name of the column.
+	Name string `mapstructure:"name"`
+	// Attribute is the key of the attribute stored in the column.
+	Attribute string `mapstructure:"attribute"`
+	// Source is where the attribute is read from: `resource`, `scope` or `record` (the log record or span attributes). default is `record`.
+	Source string `mapstructure:"source"`
+	// Type is the type of the column: `String` (default), `LowCardinality(String)`, `Int64`, `Float64` or `Bool`.
+	Type string `mapstructure:"type"`
+}
+
+func (c ColumnMapping) source() string {
+	if c.Source == "" {
+		return columnSourceRecord
+	}
+	return c.Source
+}
+
+func (c ColumnMapping) columnType() string {
+	if c.Type == "" {
+		return columnTypeString
+	}
+	return c.Type
+}
+
+// definition returns the type and the materialized expression of the column.
+func (c ColumnMapping) definition(mapColumns map[string]string) string {
+	return fmt.Sprintf("%s MATERIALIZED %s", c.columnType(), c.expression(mapColumns[c.source()]))
+}
+
+// expression returns the expression reading the attribute from the map column, converted to the column type.
+// Missing attributes and values that can't be converted result in the default value of the type.
+func (c ColumnMapping) expression(mapColumn string) string {
+	value := fmt.Sprintf("%s[%s]", mapColumn, quoteString(c.Attribute))
+	switch c.columnType() {
+	case columnTypeInt64:
+		return fmt.Sprintf("toInt64OrZero(%s)", value)
+	case columnTypeFloat64:
+		return fmt.Sprintf("toFloat64OrZero(%s)", value)
+	case columnTypeBool:
+		return fmt.Sprintf("%s = 'true'", value)
+	default:
+		return value
+	}
+}
+
+func validateColumnMappings(option string, columns []ColumnMapping, tableColumns []string) (err error) {
+	names := map[string]struct{}{}
+	for i, c := range columns {
+		switch {
+		case !columnNameRegexp.MatchString(c.Name):
+			err = errors.Join(err, fmt.Errorf("%s[%d]: invalid column name %q", option, i, c.Name))
+		case strings.HasPrefix(c.Name, "_"):
+			err = errors.Join(err, fmt.Errorf("%s[%d]: column name %q is reserved, names starting with an underscore are used by the virtual columns", option, i, c.Name))
+		case slices.Contains(tableColumns, c.Name):
+			err = errors.Join(err, fmt.Errorf("%s[%d]: column name %q is already a column of the table", option, i, c.Name))
+		}
+		if _, ok := names[c.Name]; ok {
+			err = errors.Join(err, fmt.Errorf("%s[%d]: duplicate column name %q", option, i, c.Name))
+		}
+		names[c.Name] = struct{}{}
+		if c.Attribute == "" {
+			err = errors.Join(err, fmt.Errorf("%s[%d]: attribute must be specified", option, i))
+		}
+		switch c.source() {
+		case columnSourceResource, columnSourceScope, columnSourceRecord:
+		default:
+			err = errors.Join(err, fmt.Errorf("%s[%d]: invalid source %q", option, i, c.Source))
+		}
+		switch c.columnType() {
+		case columnTypeString, columnTypeLowCardinalityString, columnTypeInt64, columnTypeFloat64, columnTypeBool:
+		default:
+			err = errors.Join(err, fmt.Errorf("%s[%d]: unsupported column type %q", option, i, c.Type))
+		}
+	}
+	return err
+}
+
 // TableEngine defines the ENGINE string value when creating the table.
 type TableEngine struct {
 	Name   string `mapstructure:"name"`
@@ -94,6 +182,31 @@ const (
 	defaultSummarySuffix      = "_summary"
 	defaultHistogramSuffix    = "_histogram"
 	defaultExpHistogramSuffix = "_exponential_histogram"
+	defaultMigrationsTable    = "otel_schema_migrations"
+
+	columnSourceResource = "resource"
+	columnSourceScope    = "scope"
+	columnSourceRecord   = "record"
+
+	columnTypeString               = "String"
+	columnTypeLowCardinalityString = "LowCardinality(String)"
+	columnTypeInt64                = "Int64"
+	columnTypeFloat64              = "Float64"
+	columnTypeBool                 = "Bool"
+)
+
+var columnNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// logsTableColumns and tracesTableColumns are the columns of the tables created by the exporter, which the column mappings can't replace.
+var (
+	logsTableColumns = []string{
+		"Timestamp", "TimestampTime", "TraceId", "SpanId", "TraceFlags", "SeverityText", "SeverityNumber", "ServiceName", "Body",
+		"ResourceSchemaUrl", "ResourceAttributes", "ScopeSchemaUrl", "ScopeName", "ScopeVersion", "ScopeAttributes", "LogAttributes",
+	}
+	tracesTableColumns = []string{
+		"Timestamp", "TraceId", "SpanId", "ParentSpanId", "TraceState", "SpanName", "SpanKind", "ServiceName", "ResourceAttributes",
+		"ScopeName", "ScopeVersion", "SpanAttributes", "Duration", "StatusCode", "StatusMessage", "Events", "Links",
+	}
 )
 
 var (
@@ -113,6 +226,9 @@ func (cfg *Config) Validate() (err error) {
 
 	cfg.buildMetricTableNames()
 
+	err = errors.Join(err, validateColumnMappings("logs_columns", cfg.LogsColumns, logsTableColumns))
+	err = errors.Join(err, validateColumnMappings("traces_columns", cfg.TracesColumns, tracesTableColumns))
+
 	// Validate DSN with clickhouse driver.
 	// Last chance to catch invalid config.
 	if _, e := clickhouse.ParseDSN(dsn); e != nil {
@@ -229,6 +345,14 @@ func (cfg *Config) areMetricTableNamesSet() bool {
 		len(cfg.MetricsTables.ExponentialHistogram.Name) != 0
 }
 
+// migrationsTableName returns the name of the migrations table qualified with the database, like the tables it refers to.
+func (cfg *Config) migrationsTableName() string {
+	if cfg.MigrationsTableName == "" {
+		return cfg.Database + "." + defaultMigrationsTable
+	}
+	return cfg.Database + "." + cfg.MigrationsTableName
+}
+
 // tableEngineString generates the ENGINE string.
 func (cfg *Config) tableEngineString() string {
 	engine := cfg.TableEngine.Name
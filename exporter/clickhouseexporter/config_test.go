@@ -48,16 +48,24 @@ func TestLoadConfig(t *testing.T) {
 		{
 			id: component.NewIDWithName(metadata.Type, "full"),
 			expected: &Config{
-				collectorVersion: "unknown",
-				driverName:       clickhouseDriverName,
-				Endpoint:         defaultEndpoint,
-				Database:         "otel",
-				Username:         "foo",
-				Password:         "bar",
-				TTL:              72 * time.Hour,
-				LogsTableName:    "otel_logs",
-				TracesTableName:  "otel_traces",
-				CreateSchema:     true,
+				collectorVersion:    "unknown",
+				driverName:          clickhouseDriverName,
+				Endpoint:            defaultEndpoint,
+				Database:            "otel",
+				Username:            "foo",
+				Password:            "bar",
+				TTL:                 72 * time.Hour,
+				LogsTableName:       "otel_logs",
+				TracesTableName:     "otel_traces",
+				CreateSchema:        true,
+				MigrationsTableName: "otel_migrations",
+				LogsColumns: []ColumnMapping{
+					{Name: "K8sNamespace", Attribute: "k8s.namespace.name", Source: "resource", Type: "LowCardinality(String)"},
+					{Name: "HttpStatusCode", Attribute: "http.response.status_code", Type: "Int64"},
+				},
+				TracesColumns: []ColumnMapping{
+					{Name: "HttpRoute", Attribute: "http.route"},
+				},
 				TimeoutSettings: exporterhelper.TimeoutConfig{
 					Timeout: 5 * time.Second,
 				},
@@ -643,3 +651,117 @@ func TestClusterString(t *testing.T) {
 		})
 	}
 }
+
+func TestColumnMappingsValidation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		columns []ColumnMapping
+		wantErr string
+	}{
+		{
+			name: "valid",
+			columns: []ColumnMapping{
+				{Name: "K8sNamespace", Attribute: "k8s.namespace.name", Source: "resource", Type: "LowCardinality(String)"},
+				{Name: "ScopeTeam", Attribute: "team", Source: "scope"},
+				{Name: "Retries", Attribute: "retries", Type: "Int64"},
+				{Name: "Ratio", Attribute: "ratio", Type: "Float64"},
+				{Name: "Sampled", Attribute: "sampled", Type: "Bool"},
+			},
+		},
+		{
+			name:    "invalid name",
+			columns: []ColumnMapping{{Name: "k8s.namespace", Attribute: "k8s.namespace.name"}},
+			wantErr: `logs_columns[0]: invalid column name "k8s.namespace"`,
+		},
+		{
+			name: "duplicate name",
+			columns: []ColumnMapping{
+				{Name: "Namespace", Attribute: "k8s.namespace.name"},
+				{Name: "Namespace", Attribute: "namespace"},
+			},
+			wantErr: `logs_columns[1]: duplicate column name "Namespace"`,
+		},
+		{
+			name:    "existing column",
+			columns: []ColumnMapping{{Name: "ServiceName", Attribute: "service.name", Source: "resource"}},
+			wantErr: `logs_columns[0]: column name "ServiceName" is already a column of the table`,
+		},
+		{
+			name:    "reserved name",
+			columns: []ColumnMapping{{Name: "_part", Attribute: "part"}},
+			wantErr: `logs_columns[0]: column name "_part" is reserved, names starting with an underscore are used by the virtual columns`,
+		},
+		{
+			name:    "missing attribute",
+			columns: []ColumnMapping{{Name: "Namespace"}},
+			wantErr: "logs_columns[0]: attribute must be specified",
+		},
+		{
+			name:    "invalid source",
+			columns: []ColumnMapping{{Name: "Namespace", Attribute: "k8s.namespace.name", Source: "body"}},
+			wantErr: `logs_columns[0]: invalid source "body"`,
+		},
+		{
+			name:    "unsupported type",
+			columns: []ColumnMapping{{Name: "Namespace", Attribute: "k8s.namespace.name", Type: "Array(String)"}},
+			wantErr: `logs_columns[0]: unsupported column type "Array(String)"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := withDefaultConfig(func(cfg *Config) {
+				cfg.Endpoint = defaultEndpoint
+				cfg.LogsColumns = tt.columns
+			})
+			err := xconfmap.Validate(cfg)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestColumnMappingExpression(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		column   ColumnMapping
+		expected string
+	}{
+		{
+			column:   ColumnMapping{Attribute: "k8s.namespace.name"},
+			expected: "LogAttributes['k8s.namespace.name']",
+		},
+		{
+			column:   ColumnMapping{Attribute: "k8s.namespace.name", Type: "LowCardinality(String)"},
+			expected: "LogAttributes['k8s.namespace.name']",
+		},
+		{
+			column:   ColumnMapping{Attribute: "retries", Type: "Int64"},
+			expected: "toInt64OrZero(LogAttributes['retries'])",
+		},
+		{
+			column:   ColumnMapping{Attribute: "ratio", Type: "Float64"},
+			expected: "toFloat64OrZero(LogAttributes['ratio'])",
+		},
+		{
+			column:   ColumnMapping{Attribute: "sampled", Type: "Bool"},
+			expected: "LogAttributes['sampled'] = 'true'",
+		},
+		{
+			column:   ColumnMapping{Attribute: `it's a \ test`},
+			expected: `LogAttributes['it\'s a \\ test']`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.column.expression("LogAttributes"))
+		})
+	}
+}
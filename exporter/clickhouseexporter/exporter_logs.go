@@ -49,7 +49,11 @@ func (e *logsExporter) start(ctx context.Context, _ component.Host) error {
 		return err
 	}
 
-	return createLogsTable(ctx, e.cfg, e.client)
+	if err := migrateSchema(ctx, e.cfg, e.client, e.cfg.LogsTableName, logsSchemaMigrations()); err != nil {
+		return err
+	}
+
+	return addColumns(ctx, e.cfg, e.client, e.cfg.LogsTableName, e.cfg.LogsColumns, logsMapColumns)
 }
 
 // shutdown will shut down the exporter.
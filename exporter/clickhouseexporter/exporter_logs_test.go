@@ -8,6 +8,7 @@ import (
 	"database/sql"
 	"database/sql/driver"
 	"errors"
+	"io"
 	"strings"
 	"testing"
 	"time"
@@ -75,7 +76,7 @@ func TestExporter_pushLogsData(t *testing.T) {
 		var items int
 		initClickhouseTestServer(t, func(query string, values []driver.Value) error {
 			t.Logf("%d, values:%+v", items, values)
-			if strings.HasPrefix(query, "INSERT") {
+			if strings.HasPrefix(query, "INSERT INTO otel_logs") {
 				items++
 			}
 			return nil
@@ -89,7 +90,7 @@ func TestExporter_pushLogsData(t *testing.T) {
 	})
 	t.Run("test check resource metadata", func(t *testing.T) {
 		initClickhouseTestServer(t, func(query string, values []driver.Value) error {
-			if strings.HasPrefix(query, "INSERT") {
+			if strings.HasPrefix(query, "INSERT INTO otel_logs") {
 				require.Equal(t, "https://opentelemetry.io/schemas/1.4.0", values[8])
 				require.Equal(t, orderedmap.FromMap(map[string]string{
 					"service.name": "test-service",
@@ -102,7 +103,7 @@ func TestExporter_pushLogsData(t *testing.T) {
 	})
 	t.Run("test check scope metadata", func(t *testing.T) {
 		initClickhouseTestServer(t, func(query string, values []driver.Value) error {
-			if strings.HasPrefix(query, "INSERT") {
+			if strings.HasPrefix(query, "INSERT INTO otel_logs") {
 				require.Equal(t, "https://opentelemetry.io/schemas/1.7.0", values[10])
 				require.Equal(t, "io.opentelemetry.contrib.clickhouse", values[11])
 				require.Equal(t, "1.0.0", values[12])
@@ -117,7 +118,7 @@ func TestExporter_pushLogsData(t *testing.T) {
 	})
 	t.Run("test with only observed timestamp", func(t *testing.T) {
 		initClickhouseTestServer(t, func(query string, values []driver.Value) error {
-			if strings.HasPrefix(query, "INSERT") {
+			if strings.HasPrefix(query, "INSERT INTO otel_logs") {
 				require.NotEqual(t, "0", values[0])
 			}
 			return nil
@@ -128,7 +129,7 @@ func TestExporter_pushLogsData(t *testing.T) {
 	})
 	t.Run("test with 2 log records with different service.name", func(t *testing.T) {
 		initClickhouseTestServer(t, func(query string, values []driver.Value) error {
-			if strings.HasPrefix(query, "INSERT") {
+			if strings.HasPrefix(query, "INSERT INTO otel_logs") {
 				body, _ := values[7].(string)
 				if body == "empty ServiceName" {
 					require.Empty(t, values[6])
@@ -261,31 +262,43 @@ func mustPushLogsData(t *testing.T, exporter *logsExporter, ld plog.Logs) {
 }
 
 func initClickhouseTestServer(t *testing.T, recorder recorder) {
+	initClickhouseTestServerWithQuerier(t, recorder, nil)
+}
+
+// initClickhouseTestServerWithQuerier registers a test driver whose queries return the rows of the querier.
+func initClickhouseTestServerWithQuerier(t *testing.T, recorder recorder, querier querier) {
 	sql.Register(t.Name(), &testClickhouseDriver{
 		recorder: recorder,
+		querier:  querier,
 	})
 }
 
 type recorder func(query string, values []driver.Value) error
 
+type querier func(query string, values []driver.Value) [][]driver.Value
+
 type testClickhouseDriver struct {
 	recorder recorder
+	querier  querier
 }
 
 func (t *testClickhouseDriver) Open(_ string) (driver.Conn, error) {
 	return &testClickhouseDriverConn{
 		recorder: t.recorder,
+		querier:  t.querier,
 	}, nil
 }
 
 type testClickhouseDriverConn struct {
 	recorder recorder
+	querier  querier
 }
 
 func (t *testClickhouseDriverConn) Prepare(query string) (driver.Stmt, error) {
 	return &testClickhouseDriverStmt{
 		query:    query,
 		recorder: t.recorder,
+		querier:  t.querier,
 	}, nil
 }
 
@@ -304,6 +317,7 @@ func (*testClickhouseDriverConn) CheckNamedValue(_ *driver.NamedValue) error {
 type testClickhouseDriverStmt struct {
 	query    string
 	recorder recorder
+	querier  querier
 }
 
 func (*testClickhouseDriverStmt) Close() error {
@@ -318,8 +332,36 @@ func (t *testClickhouseDriverStmt) Exec(args []driver.Value) (driver.Result, err
 	return nil, t.recorder(t.query, args)
 }
 
-func (t *testClickhouseDriverStmt) Query(_ []driver.Value) (driver.Rows, error) {
-	return nil, nil
+func (t *testClickhouseDriverStmt) Query(args []driver.Value) (driver.Rows, error) {
+	rows := &testClickhouseDriverRows{}
+	if t.querier != nil {
+		rows.rows = t.querier(t.query, args)
+	}
+	return rows, nil
+}
+
+type testClickhouseDriverRows struct {
+	rows [][]driver.Value
+}
+
+func (t *testClickhouseDriverRows) Columns() []string {
+	if len(t.rows) == 0 {
+		return nil
+	}
+	return make([]string, len(t.rows[0]))
+}
+
+func (*testClickhouseDriverRows) Close() error {
+	return nil
+}
+
+func (t *testClickhouseDriverRows) Next(dest []driver.Value) error {
+	if len(t.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, t.rows[0])
+	t.rows = t.rows[1:]
+	return nil
 }
 
 type testClickhouseDriverTx struct{}
@@ -50,8 +50,12 @@ func (e *metricsExporter) start(ctx context.Context, _ component.Host) error {
 		return err
 	}
 
-	ttlExpr := generateTTLExpr(e.cfg.TTL, "toDateTime(TimeUnix)")
-	return internal.NewMetricsTable(ctx, e.tablesConfig, e.cfg.clusterString(), e.cfg.tableEngineString(), ttlExpr, e.client)
+	for metricType, tableConfig := range e.tablesConfig {
+		if err := migrateSchema(ctx, e.cfg, e.client, tableConfig.Name, metricsSchemaMigrations(metricType, tableConfig.Name)); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func generateMetricTablesConfigMapper(cfg *Config) internal.MetricTablesConfigMapper {
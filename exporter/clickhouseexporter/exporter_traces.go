@@ -51,7 +51,11 @@ func (e *tracesExporter) start(ctx context.Context, _ component.Host) error {
 		return err
 	}
 
-	return createTracesTable(ctx, e.cfg, e.client)
+	if err := migrateSchema(ctx, e.cfg, e.client, e.cfg.TracesTableName, tracesSchemaMigrations()); err != nil {
+		return err
+	}
+
+	return addColumns(ctx, e.cfg, e.client, e.cfg.TracesTableName, e.cfg.TracesColumns, tracesMapColumns)
 }
 
 // shutdown will shut down the exporter.
@@ -37,16 +37,17 @@ func createDefaultConfig() component.Config {
 		collectorVersion: "unknown",
 		driverName:       clickhouseDriverName,
 
-		TimeoutSettings:  exporterhelper.NewDefaultTimeoutConfig(),
-		QueueSettings:    exporterhelper.NewDefaultQueueConfig(),
-		BackOffConfig:    configretry.NewDefaultBackOffConfig(),
-		ConnectionParams: map[string]string{},
-		Database:         defaultDatabase,
-		LogsTableName:    "otel_logs",
-		TracesTableName:  "otel_traces",
-		TTL:              0,
-		CreateSchema:     true,
-		AsyncInsert:      true,
+		TimeoutSettings:     exporterhelper.NewDefaultTimeoutConfig(),
+		QueueSettings:       exporterhelper.NewDefaultQueueConfig(),
+		BackOffConfig:       configretry.NewDefaultBackOffConfig(),
+		ConnectionParams:    map[string]string{},
+		Database:            defaultDatabase,
+		LogsTableName:       "otel_logs",
+		TracesTableName:     "otel_traces",
+		MigrationsTableName: defaultMigrationsTable,
+		TTL:                 0,
+		CreateSchema:        true,
+		AsyncInsert:         true,
 		MetricsTables: MetricTablesConfig{
 			Gauge:                internal.MetricTypeConfig{Name: defaultMetricTableName + defaultGaugeSuffix},
 			Sum:                  internal.MetricTypeConfig{Name: defaultMetricTableName + defaultSumSuffix},
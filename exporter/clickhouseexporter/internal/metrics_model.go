@@ -59,13 +59,15 @@ func SetLogger(l *zap.Logger) {
 	logger = l
 }
 
-// NewMetricsTable create metric tables with an expiry time to storage metric telemetry data
-func NewMetricsTable(ctx context.Context, tablesConfig MetricTablesConfigMapper, cluster, engine, ttlExpr string, db *sql.DB) error {
-	for key, queryTemplate := range supportedMetricTypes {
-		query := fmt.Sprintf(queryTemplate, tablesConfig[key].Name, cluster, engine, ttlExpr)
-		if _, err := db.ExecContext(ctx, query); err != nil {
-			return fmt.Errorf("exec create metrics table sql: %w", err)
-		}
+// NewMetricTable create the table of a metric type with an expiry time to storage metric telemetry data
+func NewMetricTable(ctx context.Context, metricType pmetric.MetricType, name, cluster, engine, ttlExpr string, db *sql.DB) error {
+	queryTemplate, ok := supportedMetricTypes[metricType]
+	if !ok {
+		return fmt.Errorf("unsupported metric type: %s", metricType)
+	}
+	query := fmt.Sprintf(queryTemplate, name, cluster, engine, ttlExpr)
+	if _, err := db.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("exec create metrics table sql: %w", err)
 	}
 	return nil
 }
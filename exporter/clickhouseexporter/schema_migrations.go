@@ -0,0 +1,275 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package clickhouseexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/clickhouseexporter"
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/clickhouseexporter/internal"
+)
+
+const (
+	// language=ClickHouse SQL
+	createMigrationsTableSQL = `
+CREATE TABLE IF NOT EXISTS %s %s (
+	TableName String CODEC(ZSTD(1)),
+	Version UInt32,
+	Description String CODEC(ZSTD(1)),
+	AppliedAt DateTime64(3) DEFAULT now64(3)
+) ENGINE = %s
+ORDER BY (TableName, Version)
+SETTINGS index_granularity = 8192;
+`
+	// language=ClickHouse SQL
+	selectSchemaVersionSQL = `SELECT max(Version) FROM %s WHERE TableName = ?`
+	// language=ClickHouse SQL
+	insertMigrationSQL = `INSERT INTO %s (TableName, Version, Description) VALUES (?, ?, ?)`
+	// language=ClickHouse SQL
+	selectColumnDefinitionSQL = `SELECT Description FROM %s WHERE TableName = ? ORDER BY Version DESC LIMIT 1`
+	// language=ClickHouse SQL
+	addColumnSQL = `
+ALTER TABLE %s %s
+ADD COLUMN IF NOT EXISTS %s %s CODEC(ZSTD(1));
+`
+	// language=ClickHouse SQL
+	materializeColumnSQL = `
+ALTER TABLE %s %s
+MATERIALIZE COLUMN %s;
+`
+	// language=ClickHouse SQL
+	createAttributeKeysTableSQL = `
+CREATE TABLE IF NOT EXISTS %s_attribute_keys %s (
+	Date Date CODEC(Delta, ZSTD(1)),
+	ServiceName LowCardinality(String) CODEC(ZSTD(1)),
+	Source LowCardinality(String) CODEC(ZSTD(1)),
+	Key LowCardinality(String) CODEC(ZSTD(1))
+) ENGINE = %s
+PARTITION BY Date
+ORDER BY (ServiceName, Source, Key, Date)
+%s
+SETTINGS index_granularity = 8192, ttl_only_drop_parts = 1;
+`
+	// language=ClickHouse SQL
+	createAttributeKeysMaterializedViewSQL = `
+CREATE MATERIALIZED VIEW IF NOT EXISTS %s_attribute_keys_mv %s
+TO %s.%s_attribute_keys
+AS SELECT
+	toDate(%s) AS Date,
+	ServiceName,
+	Attribute.1 AS Source,
+	Attribute.2 AS Key
+FROM %s.%s
+ARRAY JOIN arrayConcat(
+	arrayMap(k -> ('%s', k), mapKeys(%s)),
+	arrayMap(k -> ('%s', k), mapKeys(%s)),
+	arrayMap(k -> ('%s', k), mapKeys(%s))
+) AS Attribute
+GROUP BY Date, ServiceName, Source, Key;
+`
+)
+
+// schemaMigration is a versioned change of the schema of a table.
+// The statements of a migration must be idempotent, as exporters sharing
+// the same tables may apply a migration concurrently.
+type schemaMigration struct {
+	version     uint32
+	description string
+	apply       func(ctx context.Context, cfg *Config, db *sql.DB) error
+}
+
+// logsMapColumns are the map columns of the logs table the column mapping sources read from.
+var logsMapColumns = map[string]string{
+	columnSourceResource: "ResourceAttributes",
+	columnSourceScope:    "ScopeAttributes",
+	columnSourceRecord:   "LogAttributes",
+}
+
+// tracesMapColumns are the map columns of the traces table the column mapping sources read from.
+var tracesMapColumns = map[string]string{
+	columnSourceResource: "ResourceAttributes",
+	columnSourceScope:    "ScopeAttributes",
+	columnSourceRecord:   "SpanAttributes",
+}
+
+func logsSchemaMigrations() []schemaMigration {
+	return []schemaMigration{
+		{version: 1, description: "create logs table", apply: createLogsTable},
+		{
+			version:     2,
+			description: "create logs attribute keys view",
+			apply: func(ctx context.Context, cfg *Config, db *sql.DB) error {
+				return createAttributeKeysView(ctx, cfg, db, cfg.LogsTableName, "TimestampTime", logsMapColumns)
+			},
+		},
+	}
+}
+
+func tracesSchemaMigrations() []schemaMigration {
+	return []schemaMigration{
+		{version: 1, description: "create traces tables", apply: createTracesTable},
+		{
+			version:     2,
+			description: "create traces attribute keys view",
+			apply: func(ctx context.Context, cfg *Config, db *sql.DB) error {
+				return createAttributeKeysView(ctx, cfg, db, cfg.TracesTableName, "Timestamp", tracesMapColumns)
+			},
+		},
+	}
+}
+
+func metricsSchemaMigrations(metricType pmetric.MetricType, tableName string) []schemaMigration {
+	return []schemaMigration{
+		{
+			version:     1,
+			description: "create metrics table",
+			apply: func(ctx context.Context, cfg *Config, db *sql.DB) error {
+				ttlExpr := generateTTLExpr(cfg.TTL, "toDateTime(TimeUnix)")
+				return internal.NewMetricTable(ctx, metricType, tableName, cfg.clusterString(), cfg.tableEngineString(), ttlExpr, db)
+			},
+		},
+	}
+}
+
+// migrateSchema applies the migrations of the table that weren't applied yet in order of version,
+// and records each applied migration in the migrations table.
+func migrateSchema(ctx context.Context, cfg *Config, db *sql.DB, tableName string, migrations []schemaMigration) error {
+	if _, err := db.ExecContext(ctx, renderCreateMigrationsTableSQL(cfg)); err != nil {
+		return fmt.Errorf("exec create migrations table sql: %w", err)
+	}
+
+	version, err := schemaVersion(ctx, cfg, db, tableName)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if m.version <= version {
+			continue
+		}
+		if err := m.apply(ctx, cfg, db); err != nil {
+			return fmt.Errorf("apply migration %d (%s) of table %s: %w", m.version, m.description, tableName, err)
+		}
+		if _, err := db.ExecContext(ctx, renderInsertMigrationSQL(cfg), tableName, m.version, m.description); err != nil {
+			return fmt.Errorf("record migration %d of table %s: %w", m.version, tableName, err)
+		}
+	}
+	return nil
+}
+
+// schemaVersion returns the version of the last migration applied to the table, or 0 if none was applied.
+func schemaVersion(ctx context.Context, cfg *Config, db *sql.DB, tableName string) (uint32, error) {
+	var version uint32
+	err := db.QueryRowContext(ctx, renderSelectSchemaVersionSQL(cfg), tableName).Scan(&version)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("query schema version of table %s: %w", tableName, err)
+	}
+	return version, nil
+}
+
+// createAttributeKeysView creates a table with the attribute keys seen per service and day, and the materialized
+// view filling it, so that the attributes worth a column mapping can be found without reading the attribute map columns.
+func createAttributeKeysView(ctx context.Context, cfg *Config, db *sql.DB, tableName, timestampColumn string, mapColumns map[string]string) error {
+	if _, err := db.ExecContext(ctx, renderCreateAttributeKeysTableSQL(cfg, tableName)); err != nil {
+		return fmt.Errorf("exec create attribute keys table sql: %w", err)
+	}
+	if _, err := db.ExecContext(ctx, renderCreateAttributeKeysMaterializedViewSQL(cfg, tableName, timestampColumn, mapColumns)); err != nil {
+		return fmt.Errorf("exec create attribute keys view sql: %w", err)
+	}
+	return nil
+}
+
+// addColumns adds the columns of the column mapping to the table. The columns are materialized
+// from the attribute map columns, so that they don't need to be inserted by the exporter.
+//
+// Each added column is recorded in the migrations table as `<table>.<column>`, with its definition
+// as description. A column whose recorded definition differs from its mapping isn't modified,
+// and an error is returned instead, as the data of the column would no longer match its mapping.
+func addColumns(ctx context.Context, cfg *Config, db *sql.DB, tableName string, columns []ColumnMapping, mapColumns map[string]string) error {
+	for _, c := range columns {
+		migrationName := tableName + "." + c.Name
+		definition := c.definition(mapColumns)
+		recorded, err := recordedColumnDefinition(ctx, cfg, db, migrationName)
+		if err != nil {
+			return err
+		}
+		switch recorded {
+		case definition:
+			continue
+		case "":
+		default:
+			return fmt.Errorf("column %s of table %s was added as %q but is now mapped as %q: drop the column and its %q record in %s to change its mapping",
+				c.Name, tableName, recorded, definition, migrationName, cfg.migrationsTableName())
+		}
+
+		if _, err := db.ExecContext(ctx, renderAddColumnSQL(cfg, tableName, c.Name, definition)); err != nil {
+			return fmt.Errorf("exec add column %s to table %s sql: %w", c.Name, tableName, err)
+		}
+		// Compute the column for the data inserted before it was added. The mutation runs in the background.
+		if _, err := db.ExecContext(ctx, renderMaterializeColumnSQL(cfg, tableName, c.Name)); err != nil {
+			return fmt.Errorf("exec materialize column %s of table %s sql: %w", c.Name, tableName, err)
+		}
+		if _, err := db.ExecContext(ctx, renderInsertMigrationSQL(cfg), migrationName, uint32(1), definition); err != nil {
+			return fmt.Errorf("record column %s of table %s: %w", c.Name, tableName, err)
+		}
+	}
+	return nil
+}
+
+// recordedColumnDefinition returns the definition recorded for a mapped column, or an empty string if none was recorded.
+func recordedColumnDefinition(ctx context.Context, cfg *Config, db *sql.DB, migrationName string) (string, error) {
+	var definition string
+	err := db.QueryRowContext(ctx, renderSelectColumnDefinitionSQL(cfg), migrationName).Scan(&definition)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("query definition of column %s: %w", migrationName, err)
+	}
+	return definition, nil
+}
+
+func renderCreateMigrationsTableSQL(cfg *Config) string {
+	return fmt.Sprintf(createMigrationsTableSQL, cfg.migrationsTableName(), cfg.clusterString(), cfg.tableEngineString())
+}
+
+func renderSelectSchemaVersionSQL(cfg *Config) string {
+	return fmt.Sprintf(selectSchemaVersionSQL, cfg.migrationsTableName())
+}
+
+func renderInsertMigrationSQL(cfg *Config) string {
+	return fmt.Sprintf(insertMigrationSQL, cfg.migrationsTableName())
+}
+
+func renderSelectColumnDefinitionSQL(cfg *Config) string {
+	return fmt.Sprintf(selectColumnDefinitionSQL, cfg.migrationsTableName())
+}
+
+func renderAddColumnSQL(cfg *Config, tableName, columnName, definition string) string {
+	return fmt.Sprintf(addColumnSQL, tableName, cfg.clusterString(), columnName, definition)
+}
+
+func renderMaterializeColumnSQL(cfg *Config, tableName, columnName string) string {
+	return fmt.Sprintf(materializeColumnSQL, tableName, cfg.clusterString(), columnName)
+}
+
+func renderCreateAttributeKeysTableSQL(cfg *Config, tableName string) string {
+	ttlExpr := generateTTLExpr(cfg.TTL, "toDateTime(Date)")
+	return fmt.Sprintf(createAttributeKeysTableSQL, tableName, cfg.clusterString(), cfg.tableEngineString(), ttlExpr)
+}
+
+func renderCreateAttributeKeysMaterializedViewSQL(cfg *Config, tableName, timestampColumn string, mapColumns map[string]string) string {
+	return fmt.Sprintf(createAttributeKeysMaterializedViewSQL, tableName, cfg.clusterString(), cfg.Database, tableName,
+		timestampColumn, cfg.Database, tableName,
+		columnSourceResource, mapColumns[columnSourceResource],
+		columnSourceScope, mapColumns[columnSourceScope],
+		columnSourceRecord, mapColumns[columnSourceRecord])
+}
+
+// quoteString quotes s as a ClickHouse string literal.
+func quoteString(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
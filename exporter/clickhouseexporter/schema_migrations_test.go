@@ -0,0 +1,266 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package clickhouseexporter
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// queryLog records the queries executed by the test driver.
+type queryLog struct {
+	mu      sync.Mutex
+	queries []string
+	values  map[string][][]driver.Value
+}
+
+func newQueryLog() *queryLog {
+	return &queryLog{values: map[string][][]driver.Value{}}
+}
+
+func (l *queryLog) record(query string, values []driver.Value) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	query = strings.TrimSpace(query)
+	l.queries = append(l.queries, query)
+	l.values[query] = append(l.values[query], values)
+	return nil
+}
+
+// withPrefix returns the recorded queries starting with the prefix.
+func (l *queryLog) withPrefix(prefix string) []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var out []string
+	for _, q := range l.queries {
+		if strings.HasPrefix(q, prefix) {
+			out = append(out, q)
+		}
+	}
+	return out
+}
+
+func TestMigrateSchema(t *testing.T) {
+	t.Run("apply all migrations", func(t *testing.T) {
+		log := newQueryLog()
+		initClickhouseTestServer(t, log.record)
+		newTestLogsExporter(t, defaultEndpoint, withDriverName(t.Name()))
+
+		require.Len(t, log.withPrefix("CREATE TABLE IF NOT EXISTS default.otel_schema_migrations"), 1)
+		require.Len(t, log.withPrefix("CREATE TABLE IF NOT EXISTS otel_logs "), 1)
+		require.Len(t, log.withPrefix("CREATE TABLE IF NOT EXISTS otel_logs_attribute_keys"), 1)
+		require.Len(t, log.withPrefix("CREATE MATERIALIZED VIEW IF NOT EXISTS otel_logs_attribute_keys_mv"), 1)
+		inserts := log.withPrefix("INSERT INTO default.otel_schema_migrations")
+		require.Len(t, inserts, 2)
+		assert.Equal(t, [][]driver.Value{
+			{"otel_logs", uint32(1), "create logs table"},
+			{"otel_logs", uint32(2), "create logs attribute keys view"},
+		}, log.values[inserts[0]])
+	})
+
+	t.Run("apply new migrations", func(t *testing.T) {
+		log := newQueryLog()
+		initClickhouseTestServerWithQuerier(t, log.record, func(query string, _ []driver.Value) [][]driver.Value {
+			if strings.HasPrefix(query, "SELECT max(Version) FROM default.otel_schema_migrations") {
+				return [][]driver.Value{{int64(1)}}
+			}
+			return nil
+		})
+		newTestTracesExporter(t, defaultEndpoint, withDriverName(t.Name()))
+
+		assert.Empty(t, log.withPrefix("CREATE TABLE IF NOT EXISTS otel_traces "))
+		assert.Len(t, log.withPrefix("CREATE TABLE IF NOT EXISTS otel_traces_attribute_keys"), 1)
+		assert.Len(t, log.withPrefix("CREATE MATERIALIZED VIEW IF NOT EXISTS otel_traces_attribute_keys_mv"), 1)
+		inserts := log.withPrefix("INSERT INTO default.otel_schema_migrations")
+		require.Len(t, inserts, 1)
+		assert.Equal(t, [][]driver.Value{{"otel_traces", uint32(2), "create traces attribute keys view"}}, log.values[inserts[0]])
+	})
+
+	t.Run("skip applied migrations", func(t *testing.T) {
+		log := newQueryLog()
+		var versionQueries []driver.Value
+		initClickhouseTestServerWithQuerier(t, log.record, func(query string, values []driver.Value) [][]driver.Value {
+			if strings.HasPrefix(query, "SELECT max(Version) FROM default.otel_schema_migrations") {
+				versionQueries = append(versionQueries, values...)
+				return [][]driver.Value{{int64(2)}}
+			}
+			return nil
+		})
+		newTestTracesExporter(t, defaultEndpoint, withDriverName(t.Name()))
+
+		assert.Equal(t, []driver.Value{"otel_traces"}, versionQueries)
+		assert.Len(t, log.withPrefix("CREATE TABLE IF NOT EXISTS default.otel_schema_migrations"), 1)
+		assert.Empty(t, log.withPrefix("CREATE TABLE IF NOT EXISTS otel_traces"))
+		assert.Empty(t, log.withPrefix("INSERT INTO default.otel_schema_migrations"))
+	})
+
+	t.Run("failed migration isn't recorded", func(t *testing.T) {
+		log := newQueryLog()
+		initClickhouseTestServer(t, log.record)
+		cfg := withDefaultConfig(func(cfg *Config) {
+			cfg.Endpoint = defaultEndpoint
+			cfg.driverName = t.Name()
+		})
+		db, err := cfg.buildDB()
+		require.NoError(t, err)
+		defer func() {
+			_ = db.Close()
+		}()
+
+		errMigration := errors.New("migration failed")
+		migrations := []schemaMigration{
+			{version: 1, description: "first", apply: createLogsTable},
+			{version: 2, description: "second", apply: func(context.Context, *Config, *sql.DB) error {
+				return errMigration
+			}},
+		}
+		err = migrateSchema(context.Background(), cfg, db, cfg.LogsTableName, migrations)
+		require.ErrorIs(t, err, errMigration)
+		assert.ErrorContains(t, err, "apply migration 2 (second) of table otel_logs")
+
+		inserts := log.withPrefix("INSERT INTO default.otel_schema_migrations")
+		require.Len(t, inserts, 1)
+		assert.Equal(t, [][]driver.Value{{"otel_logs", uint32(1), "first"}}, log.values[inserts[0]])
+	})
+
+	t.Run("metrics tables", func(t *testing.T) {
+		log := newQueryLog()
+		initClickhouseTestServer(t, log.record)
+		newTestMetricsExporter(t, defaultEndpoint, withDriverName(t.Name()))
+
+		assert.Len(t, log.withPrefix("CREATE TABLE IF NOT EXISTS otel_metrics_"), 5)
+		assert.Len(t, log.withPrefix("INSERT INTO default.otel_schema_migrations"), 5)
+	})
+}
+
+func TestAddColumns(t *testing.T) {
+	t.Run("logs", func(t *testing.T) {
+		log := newQueryLog()
+		initClickhouseTestServer(t, log.record)
+		newTestLogsExporter(t, defaultEndpoint, withDriverName(t.Name()), func(cfg *Config) {
+			cfg.LogsColumns = []ColumnMapping{
+				{Name: "K8sNamespace", Attribute: "k8s.namespace.name", Source: "resource", Type: "LowCardinality(String)"},
+				{Name: "HttpStatusCode", Attribute: "http.response.status_code", Type: "Int64"},
+			}
+		})
+
+		assert.Equal(t, []string{
+			"ALTER TABLE otel_logs \nADD COLUMN IF NOT EXISTS K8sNamespace LowCardinality(String) MATERIALIZED ResourceAttributes['k8s.namespace.name'] CODEC(ZSTD(1));",
+			"ALTER TABLE otel_logs \nMATERIALIZE COLUMN K8sNamespace;",
+			"ALTER TABLE otel_logs \nADD COLUMN IF NOT EXISTS HttpStatusCode Int64 MATERIALIZED toInt64OrZero(LogAttributes['http.response.status_code']) CODEC(ZSTD(1));",
+			"ALTER TABLE otel_logs \nMATERIALIZE COLUMN HttpStatusCode;",
+		}, log.withPrefix("ALTER TABLE"))
+
+		inserts := log.withPrefix("INSERT INTO default.otel_schema_migrations")
+		require.NotEmpty(t, inserts)
+		assert.Equal(t, [][]driver.Value{
+			{"otel_logs", uint32(1), "create logs table"},
+			{"otel_logs", uint32(2), "create logs attribute keys view"},
+			{"otel_logs.K8sNamespace", uint32(1), "LowCardinality(String) MATERIALIZED ResourceAttributes['k8s.namespace.name']"},
+			{"otel_logs.HttpStatusCode", uint32(1), "Int64 MATERIALIZED toInt64OrZero(LogAttributes['http.response.status_code'])"},
+		}, log.values[inserts[0]])
+	})
+
+	t.Run("traces on cluster", func(t *testing.T) {
+		log := newQueryLog()
+		initClickhouseTestServer(t, log.record)
+		newTestTracesExporter(t, defaultEndpoint, withDriverName(t.Name()), func(cfg *Config) {
+			cfg.ClusterName = "cluster_a_b"
+			cfg.TracesColumns = []ColumnMapping{{Name: "HttpRoute", Attribute: "http.route"}}
+		})
+
+		queries := log.withPrefix("ALTER TABLE")
+		require.Len(t, queries, 2)
+		assert.Equal(t, "ALTER TABLE otel_traces ON CLUSTER cluster_a_b\nADD COLUMN IF NOT EXISTS HttpRoute String MATERIALIZED SpanAttributes['http.route'] CODEC(ZSTD(1));", queries[0])
+		assert.Equal(t, "ALTER TABLE otel_traces ON CLUSTER cluster_a_b\nMATERIALIZE COLUMN HttpRoute;", queries[1])
+		for _, query := range queries {
+			require.NoError(t, checkClusterQueryDefinition(query, "cluster_a_b"))
+		}
+	})
+
+	t.Run("recorded column", func(t *testing.T) {
+		log := newQueryLog()
+		var columnQueries []driver.Value
+		initClickhouseTestServerWithQuerier(t, log.record, func(query string, values []driver.Value) [][]driver.Value {
+			if strings.HasPrefix(query, "SELECT Description FROM default.otel_schema_migrations") {
+				columnQueries = append(columnQueries, values...)
+				return [][]driver.Value{{"String MATERIALIZED SpanAttributes['http.route']"}}
+			}
+			return nil
+		})
+		newTestTracesExporter(t, defaultEndpoint, withDriverName(t.Name()), func(cfg *Config) {
+			cfg.TracesColumns = []ColumnMapping{{Name: "HttpRoute", Attribute: "http.route"}}
+		})
+
+		assert.Equal(t, []driver.Value{"otel_traces.HttpRoute"}, columnQueries)
+		assert.Empty(t, log.withPrefix("ALTER TABLE"))
+	})
+
+	t.Run("changed mapping", func(t *testing.T) {
+		log := newQueryLog()
+		initClickhouseTestServerWithQuerier(t, log.record, func(query string, _ []driver.Value) [][]driver.Value {
+			if strings.HasPrefix(query, "SELECT Description FROM default.otel_schema_migrations") {
+				return [][]driver.Value{{"String MATERIALIZED SpanAttributes['http.route']"}}
+			}
+			return nil
+		})
+		cfg := withDefaultConfig(func(cfg *Config) {
+			cfg.Endpoint = defaultEndpoint
+			cfg.driverName = t.Name()
+		})
+		db, err := cfg.buildDB()
+		require.NoError(t, err)
+		defer func() {
+			_ = db.Close()
+		}()
+
+		columns := []ColumnMapping{{Name: "HttpRoute", Attribute: "http.route", Type: "LowCardinality(String)"}}
+		err = addColumns(context.Background(), cfg, db, cfg.TracesTableName, columns, tracesMapColumns)
+		assert.EqualError(t, err, `column HttpRoute of table otel_traces was added as "String MATERIALIZED SpanAttributes['http.route']" `+
+			`but is now mapped as "LowCardinality(String) MATERIALIZED SpanAttributes['http.route']": `+
+			`drop the column and its "otel_traces.HttpRoute" record in default.otel_schema_migrations to change its mapping`)
+		assert.Empty(t, log.withPrefix("ALTER TABLE"))
+		assert.Empty(t, log.withPrefix("INSERT INTO default.otel_schema_migrations"))
+	})
+}
+
+func TestRenderCreateAttributeKeysMaterializedViewSQL(t *testing.T) {
+	cfg := withDefaultConfig(func(cfg *Config) {
+		cfg.Database = "otel"
+	})
+	assert.Equal(t, `
+CREATE MATERIALIZED VIEW IF NOT EXISTS otel_logs_attribute_keys_mv 
+TO otel.otel_logs_attribute_keys
+AS SELECT
+	toDate(TimestampTime) AS Date,
+	ServiceName,
+	Attribute.1 AS Source,
+	Attribute.2 AS Key
+FROM otel.otel_logs
+ARRAY JOIN arrayConcat(
+	arrayMap(k -> ('resource', k), mapKeys(ResourceAttributes)),
+	arrayMap(k -> ('scope', k), mapKeys(ScopeAttributes)),
+	arrayMap(k -> ('record', k), mapKeys(LogAttributes))
+) AS Attribute
+GROUP BY Date, ServiceName, Source, Key;
+`, renderCreateAttributeKeysMaterializedViewSQL(cfg, cfg.LogsTableName, "TimestampTime", logsMapColumns))
+}
+
+func TestRenderMigrationsTableSQL(t *testing.T) {
+	cfg := withDefaultConfig(func(cfg *Config) {
+		cfg.Database = "otel"
+		cfg.MigrationsTableName = "otel_migrations"
+	})
+	assert.Equal(t, "INSERT INTO otel.otel_migrations (TableName, Version, Description) VALUES (?, ?, ?)", renderInsertMigrationSQL(cfg))
+	assert.Equal(t, "SELECT max(Version) FROM otel.otel_migrations WHERE TableName = ?", renderSelectSchemaVersionSQL(cfg))
+	assert.True(t, strings.HasPrefix(strings.TrimSpace(renderCreateMigrationsTableSQL(cfg)), "CREATE TABLE IF NOT EXISTS otel.otel_migrations "))
+}
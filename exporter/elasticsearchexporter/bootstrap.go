@@ -0,0 +1,167 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package elasticsearchexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/elasticsearchexporter"
+
+import (
+	"slices"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/indexbootstrap"
+)
+
+// bootstrapResources returns the templates and the lifecycle policy of the indices the exporter
+// writes the documents of the data stream type to, with the mappings of the mapping modes.
+//
+// With dynamic document routing, the documents are routed to different data streams by default
+// depending on the mapping mode, e.g. logs-*.otel-* in the OTel mapping mode, or logs-*-* in the other
+// modes, so an index template is installed for each of these patterns. The OTel mode template takes
+// precedence over the other one, whose pattern overlaps with its own. With a static index, a single
+// index template matches the index.
+//
+// The mappings of a template are the ones of the modes writing to its indices. If the modes map a field
+// differently, the mapping of the first mode is kept, so the default mode must be the first one.
+func bootstrapResources(cfg *Config, dsType, index string, modes []MappingMode) (indexbootstrap.Resources, error) {
+	res := indexbootstrap.Resources{PolicyName: "otel-" + dsType}
+
+	var templates []indexbootstrap.IndexTemplate
+	templateModes := map[string][]MappingMode{}
+	for _, mode := range modes {
+		name, pattern, priorityOffset := "otel-"+dsType, index, 0
+		if pattern == "" {
+			pattern = dsType + "-*-*"
+			if mode == MappingOTel {
+				name, pattern, priorityOffset = "otel-"+dsType+"-otel", dsType+"-*.otel-*", 1
+			}
+		}
+		if _, ok := templateModes[name]; !ok {
+			templates = append(templates, indexbootstrap.IndexTemplate{
+				Name:           name,
+				IndexPatterns:  []string{pattern},
+				ComposedOf:     []string{name + "@mappings"},
+				DataStream:     true,
+				PriorityOffset: priorityOffset,
+			})
+		}
+		templateModes[name] = append(templateModes[name], mode)
+	}
+
+	for _, it := range templates {
+		if cfg.LogstashFormat.Enabled {
+			// The indices are suffixed with their date, and rotated by the exporter.
+			it.IndexPatterns[0] += cfg.LogstashFormat.PrefixSeparator + "*"
+			it.DataStream = false
+		}
+		mappings, err := bootstrapMappings(dsType, templateModes[it.Name])
+		if err != nil {
+			return indexbootstrap.Resources{}, err
+		}
+		res.ComponentTemplates = append(res.ComponentTemplates, indexbootstrap.ComponentTemplate{
+			Name:     it.Name + "@mappings",
+			Mappings: mappings,
+		})
+		res.IndexTemplates = append(res.IndexTemplates, it)
+	}
+	return res, nil
+}
+
+// bootstrapModes returns the mapping modes the exporter encodes the documents with, the default one first.
+func bootstrapModes(defaultMode MappingMode, allowedModes map[string]MappingMode) []MappingMode {
+	var modes []MappingMode
+	for _, mode := range allowedModes {
+		if mode != defaultMode {
+			modes = append(modes, mode)
+		}
+	}
+	slices.Sort(modes)
+	return append([]MappingMode{defaultMode}, modes...)
+}
+
+// bootstrapMappings returns the mappings of the fields of the documents encoded in the mapping modes.
+// Only the fields whose dynamic mapping isn't suitable are mapped explicitly.
+func bootstrapMappings(dsType string, modes []MappingMode) (map[string]any, error) {
+	fields := map[string]any{}
+	for _, mode := range modes {
+		for path, mapping := range bootstrapFields(mode, dsType) {
+			if _, ok := fields[path]; !ok {
+				fields[path] = mapping
+			}
+		}
+	}
+	return indexbootstrap.Mappings(fields)
+}
+
+// bootstrapFields returns the mappings of the fields of the documents encoded in the mapping mode, keyed by their dotted paths.
+func bootstrapFields(mode MappingMode, dsType string) map[string]any {
+	var fields map[string]any
+	switch mode {
+	case MappingOTel:
+		fields = otelModeFields(dsType)
+	case MappingECS:
+		fields = map[string]any{
+			"@timestamp":     indexbootstrap.Field("date"),
+			"message":        indexbootstrap.Field("text"),
+			"trace.id":       indexbootstrap.Field("keyword"),
+			"span.id":        indexbootstrap.Field("keyword"),
+			"log.level":      indexbootstrap.Field("keyword"),
+			"service.name":   indexbootstrap.Field("keyword"),
+			"host.name":      indexbootstrap.Field("keyword"),
+			"event.severity": indexbootstrap.Field("long"),
+		}
+	case MappingNone, MappingRaw:
+		fields = map[string]any{
+			"@timestamp": indexbootstrap.Field("date"),
+			"TraceId":    indexbootstrap.Field("keyword"),
+			"SpanId":     indexbootstrap.Field("keyword"),
+		}
+	default:
+		// In the bodymap mode, the documents are the log record bodies, only their timestamp is known.
+		fields = map[string]any{
+			"@timestamp": indexbootstrap.Field("date"),
+		}
+	}
+	fields["data_stream.type"] = indexbootstrap.Field("keyword")
+	fields["data_stream.dataset"] = indexbootstrap.Field("keyword")
+	fields["data_stream.namespace"] = indexbootstrap.Field("keyword")
+	return fields
+}
+
+func otelModeFields(dsType string) map[string]any {
+	fields := map[string]any{
+		"@timestamp": indexbootstrap.Field("date_nanos"),
+		"trace_id":   indexbootstrap.Field("keyword"),
+		"span_id":    indexbootstrap.Field("keyword"),
+	}
+	// The attributes keep the dots of their keys, e.g. attributes.http.request.method.
+	// Passthrough objects keep the keys as they are, and make the attributes queryable without
+	// their prefix, the attributes of the record taking precedence over the scope and resource ones.
+	for field, priority := range map[string]int{
+		"resource.attributes": 10,
+		"scope.attributes":    20,
+		"attributes":          30,
+	} {
+		fields[field] = map[string]any{"type": "passthrough", "dynamic": true, "priority": priority}
+	}
+
+	switch dsType {
+	case defaultDataStreamTypeLogs:
+		fields["observed_timestamp"] = indexbootstrap.Field("date_nanos")
+		fields["severity_text"] = indexbootstrap.Field("keyword")
+		fields["severity_number"] = indexbootstrap.Field("byte")
+		fields["event_name"] = indexbootstrap.Field("keyword")
+		fields["body.text"] = indexbootstrap.Field("text")
+		fields["body.structured"] = indexbootstrap.Field("flattened")
+	case defaultDataStreamTypeMetrics:
+		fields["start_timestamp"] = indexbootstrap.Field("date_nanos")
+		fields["unit"] = indexbootstrap.Field("keyword")
+		fields["_metric_names_hash"] = indexbootstrap.Field("keyword")
+	case defaultDataStreamTypeTraces:
+		fields["parent_span_id"] = indexbootstrap.Field("keyword")
+		fields["trace_state"] = indexbootstrap.Field("keyword")
+		fields["name"] = indexbootstrap.Field("keyword")
+		fields["kind"] = indexbootstrap.Field("keyword")
+		fields["duration"] = indexbootstrap.Field("long")
+		fields["status.code"] = indexbootstrap.Field("keyword")
+		fields["status.message"] = indexbootstrap.Field("text")
+	}
+	return fields
+}
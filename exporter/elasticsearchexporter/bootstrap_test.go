@@ -0,0 +1,244 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package elasticsearchexporter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/indexbootstrap"
+)
+
+func TestBootstrapResources(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		dsType    string
+		index     string
+		modes     []MappingMode
+		configure func(*Config)
+		expected  []indexbootstrap.IndexTemplate
+	}{
+		{
+			name:   "otel mode",
+			dsType: defaultDataStreamTypeLogs,
+			modes:  []MappingMode{MappingOTel},
+			expected: []indexbootstrap.IndexTemplate{
+				{Name: "otel-logs-otel", IndexPatterns: []string{"logs-*.otel-*"}, DataStream: true, PriorityOffset: 1},
+			},
+		},
+		{
+			name:   "ecs mode",
+			dsType: defaultDataStreamTypeTraces,
+			modes:  []MappingMode{MappingECS},
+			expected: []indexbootstrap.IndexTemplate{
+				{Name: "otel-traces", IndexPatterns: []string{"traces-*-*"}, DataStream: true},
+			},
+		},
+		{
+			name:   "allowed modes",
+			dsType: defaultDataStreamTypeLogs,
+			modes:  []MappingMode{MappingECS, MappingNone, MappingOTel},
+			expected: []indexbootstrap.IndexTemplate{
+				{Name: "otel-logs", IndexPatterns: []string{"logs-*-*"}, DataStream: true},
+				{Name: "otel-logs-otel", IndexPatterns: []string{"logs-*.otel-*"}, DataStream: true, PriorityOffset: 1},
+			},
+		},
+		{
+			name:   "static index",
+			dsType: defaultDataStreamTypeMetrics,
+			index:  "my-metrics",
+			modes:  []MappingMode{MappingOTel, MappingECS},
+			expected: []indexbootstrap.IndexTemplate{
+				{Name: "otel-metrics", IndexPatterns: []string{"my-metrics"}, DataStream: true},
+			},
+		},
+		{
+			name:   "logstash format",
+			dsType: defaultDataStreamTypeLogs,
+			index:  "my-logs",
+			modes:  []MappingMode{MappingNone},
+			configure: func(cfg *Config) {
+				cfg.LogstashFormat.Enabled = true
+			},
+			expected: []indexbootstrap.IndexTemplate{
+				{Name: "otel-logs", IndexPatterns: []string{"my-logs-*"}},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := withDefaultConfig()
+			if tc.configure != nil {
+				tc.configure(cfg)
+			}
+			res, err := bootstrapResources(cfg, tc.dsType, tc.index, tc.modes)
+			require.NoError(t, err)
+
+			assert.Equal(t, "otel-"+tc.dsType, res.PolicyName)
+			require.Len(t, res.ComponentTemplates, len(tc.expected))
+			for i := range tc.expected {
+				tc.expected[i].ComposedOf = []string{tc.expected[i].Name + "@mappings"}
+				assert.Equal(t, tc.expected[i].Name+"@mappings", res.ComponentTemplates[i].Name)
+			}
+			assert.Equal(t, tc.expected, res.IndexTemplates)
+		})
+	}
+}
+
+func TestBootstrapModes(t *testing.T) {
+	allowed := map[string]MappingMode{
+		"otel": MappingOTel,
+		"ecs":  MappingECS,
+		"none": MappingNone,
+	}
+	assert.Equal(t, []MappingMode{MappingECS, MappingNone, MappingOTel}, bootstrapModes(MappingECS, allowed))
+	assert.Equal(t, []MappingMode{MappingOTel}, bootstrapModes(MappingOTel, map[string]MappingMode{"otel": MappingOTel}))
+}
+
+func TestBootstrapMappings(t *testing.T) {
+	fieldType := func(mappings map[string]any, path ...string) any {
+		props := mappings["properties"].(map[string]any)
+		for _, key := range path[:len(path)-1] {
+			props = props[key].(map[string]any)["properties"].(map[string]any)
+		}
+		return props[path[len(path)-1]].(map[string]any)["type"]
+	}
+
+	mappings := func(dsType string, modes ...MappingMode) map[string]any {
+		m, err := bootstrapMappings(dsType, modes)
+		require.NoError(t, err)
+		return m
+	}
+
+	logs := mappings(defaultDataStreamTypeLogs, MappingOTel)
+	assert.Equal(t, "date_nanos", fieldType(logs, "@timestamp"))
+	assert.Equal(t, "passthrough", fieldType(logs, "attributes"))
+	assert.Equal(t, "passthrough", fieldType(logs, "resource", "attributes"))
+	assert.Equal(t, "flattened", fieldType(logs, "body", "structured"))
+	assert.Equal(t, "keyword", fieldType(logs, "data_stream", "dataset"))
+
+	traces := mappings(defaultDataStreamTypeTraces, MappingOTel)
+	assert.Equal(t, "keyword", fieldType(traces, "status", "code"))
+	assert.NotContains(t, traces["properties"], "body")
+
+	ecs := mappings(defaultDataStreamTypeLogs, MappingECS)
+	assert.Equal(t, "date", fieldType(ecs, "@timestamp"))
+	assert.Equal(t, "keyword", fieldType(ecs, "trace", "id"))
+	assert.Equal(t, "keyword", fieldType(ecs, "service", "name"))
+
+	// The fields of all the modes are mapped, the first mode taking precedence.
+	merged := mappings(defaultDataStreamTypeLogs, MappingOTel, MappingECS)
+	assert.Equal(t, "date_nanos", fieldType(merged, "@timestamp"))
+	assert.Equal(t, "keyword", fieldType(merged, "trace", "id"))
+	assert.Equal(t, "keyword", fieldType(merged, "trace_id"))
+}
+
+// newBootstrapTestServer returns an Elasticsearch test server storing the templates and policies put to it.
+func newBootstrapTestServer(t *testing.T, status int) (*httptest.Server, func() map[string]json.RawMessage) {
+	var mu sync.Mutex
+	resources := map[string]json.RawMessage{}
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("X-Elastic-Product", "Elasticsearch")
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case http.MethodGet:
+			if _, ok := resources[r.URL.Path]; !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write(resources[r.URL.Path])
+		case http.MethodPut:
+			if status != http.StatusOK {
+				http.Error(w, `{"error":"invalid template"}`, status)
+				return
+			}
+			var body json.RawMessage
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			resources[r.URL.Path] = body
+			_, _ = w.Write([]byte(`{"acknowledged":true}`))
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/_ilm/policy/", handler)
+	mux.HandleFunc("/_component_template/", handler)
+	mux.HandleFunc("/_index_template/", handler)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, func() map[string]json.RawMessage {
+		mu.Lock()
+		defer mu.Unlock()
+		return resources
+	}
+}
+
+func TestExporterBootstrap(t *testing.T) {
+	t.Run("install", func(t *testing.T) {
+		server, resources := newBootstrapTestServer(t, http.StatusOK)
+		newTestLogsExporter(t, server.URL, func(cfg *Config) {
+			cfg.Bootstrap.Enabled = true
+		})
+
+		// The templates of all the allowed mapping modes are installed.
+		installed := resources()
+		assert.Len(t, installed, 5)
+		assert.Contains(t, installed, "/_ilm/policy/otel-logs")
+		assert.Contains(t, installed, "/_component_template/otel-logs-otel@mappings")
+		assert.Contains(t, installed, "/_component_template/otel-logs@mappings")
+
+		var indexTemplate map[string]any
+		require.NoError(t, json.Unmarshal(installed["/_index_template/otel-logs-otel"], &indexTemplate))
+		assert.Equal(t, []any{"logs-*.otel-*"}, indexTemplate["index_patterns"])
+		assert.Equal(t, []any{"otel-logs-otel@mappings"}, indexTemplate["composed_of"])
+		assert.Equal(t, 121.0, indexTemplate["priority"])
+		assert.Equal(t, map[string]any{"settings": map[string]any{"index.lifecycle.name": "otel-logs"}}, indexTemplate["template"])
+
+		require.NoError(t, json.Unmarshal(installed["/_index_template/otel-logs"], &indexTemplate))
+		assert.Equal(t, []any{"logs-*-*"}, indexTemplate["index_patterns"])
+		assert.Equal(t, 120.0, indexTemplate["priority"])
+	})
+
+	t.Run("allowed mode", func(t *testing.T) {
+		server, resources := newBootstrapTestServer(t, http.StatusOK)
+		newTestLogsExporter(t, server.URL, func(cfg *Config) {
+			cfg.Bootstrap.Enabled = true
+			cfg.Mapping.Mode = "ecs"
+			cfg.Mapping.AllowedModes = []string{"ecs"}
+		})
+
+		installed := resources()
+		assert.Len(t, installed, 3)
+		assert.Contains(t, installed, "/_index_template/otel-logs")
+		assert.NotContains(t, installed, "/_index_template/otel-logs-otel")
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		server, resources := newBootstrapTestServer(t, http.StatusOK)
+		newTestTracesExporter(t, server.URL)
+
+		assert.Empty(t, resources())
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		server, _ := newBootstrapTestServer(t, http.StatusBadRequest)
+		exp := newUnstartedTestLogsExporter(t, server.URL, func(cfg *Config) {
+			cfg.Bootstrap.Enabled = true
+		})
+
+		err := exp.Start(context.Background(), componenttest.NewNopHost())
+		assert.ErrorContains(t, err, `error bootstrapping index templates: failed to install lifecycle policy "otel-logs": 400 Bad Request`)
+		require.NoError(t, exp.Shutdown(context.Background()))
+	})
+}
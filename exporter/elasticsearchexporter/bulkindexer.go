@@ -16,7 +16,6 @@ import (
 
 	"github.com/elastic/go-docappender/v2"
 	"github.com/elastic/go-elasticsearch/v8/esapi"
-	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configcompression"
 	"go.opentelemetry.io/collector/exporter"
 	"go.uber.org/zap"
@@ -418,28 +417,13 @@ type bulkIndexers struct {
 }
 
 func (b *bulkIndexers) start(
-	ctx context.Context,
 	cfg *Config,
 	set exporter.Settings,
-	host component.Host,
+	esClient esapi.Transport,
 	allowedMappingModes map[string]MappingMode,
 ) error {
-	userAgent := fmt.Sprintf(
-		"%s/%s (%s/%s)",
-		set.BuildInfo.Description,
-		set.BuildInfo.Version,
-		runtime.GOOS,
-		runtime.GOARCH,
-	)
-
-	esClient, err := newElasticsearchClient(ctx, cfg, host, set.TelemetrySettings, userAgent)
-	if err != nil {
-		return err
-	}
-
 	for _, mode := range allowedMappingModes {
-		var bi bulkIndexer
-		bi, err = newBulkIndexer(set.Logger, esClient, cfg, mode == MappingOTel)
+		bi, err := newBulkIndexer(set.Logger, esClient, cfg, mode == MappingOTel)
 		if err != nil {
 			return err
 		}
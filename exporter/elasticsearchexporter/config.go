@@ -19,6 +19,8 @@ import (
 	"go.opentelemetry.io/collector/confmap"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/indexbootstrap"
 )
 
 // Config defines configuration for Elastic exporter.
@@ -100,6 +102,10 @@ type Config struct {
 	// If Batcher.Enabled is non-nil (i.e. batcher::enabled is specified),
 	// then the Flush will be ignored even if Batcher.Enabled is false.
 	Batcher BatcherConfig `mapstructure:"batcher"`
+
+	// Bootstrap configures the installation of the index templates and the
+	// ILM policy matching the default mapping mode on start.
+	Bootstrap indexbootstrap.Config `mapstructure:"bootstrap"`
 }
 
 // BatcherConfig holds configuration for exporterbatcher.
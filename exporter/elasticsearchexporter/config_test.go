@@ -22,6 +22,7 @@ import (
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/elasticsearchexporter/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/indexbootstrap"
 )
 
 func TestConfig(t *testing.T) {
@@ -130,6 +131,7 @@ func TestConfig(t *testing.T) {
 				TelemetrySettings: TelemetrySettings{
 					LogFailedDocsInputRateLimit: time.Second,
 				},
+				Bootstrap: indexbootstrap.NewDefaultConfig(),
 			},
 		},
 		{
@@ -210,6 +212,7 @@ func TestConfig(t *testing.T) {
 				TelemetrySettings: TelemetrySettings{
 					LogFailedDocsInputRateLimit: time.Second,
 				},
+				Bootstrap: indexbootstrap.NewDefaultConfig(),
 			},
 		},
 		{
@@ -290,6 +293,7 @@ func TestConfig(t *testing.T) {
 				TelemetrySettings: TelemetrySettings{
 					LogFailedDocsInputRateLimit: time.Second,
 				},
+				Bootstrap: indexbootstrap.NewDefaultConfig(),
 			},
 		},
 		{
@@ -363,6 +367,18 @@ func TestConfig(t *testing.T) {
 				cfg.IncludeSourceOnError = &includeSource
 			}),
 		},
+		{
+			id:         component.NewIDWithName(metadata.Type, "bootstrap"),
+			configFile: "config.yaml",
+			expected: withDefaultConfig(func(cfg *Config) {
+				cfg.Endpoint = "https://elastic.example.com:9200"
+				cfg.Bootstrap.Enabled = true
+				cfg.Bootstrap.Overwrite = true
+				cfg.Bootstrap.Priority = 200
+				cfg.Bootstrap.Lifecycle.RolloverMaxAge = 24 * time.Hour
+				cfg.Bootstrap.Lifecycle.DeleteAfter = 30 * 24 * time.Hour
+			}),
+		},
 	}
 
 	for _, tt := range tests {
@@ -413,6 +429,20 @@ func TestConfig_Validate(t *testing.T) {
 			}),
 			err: `invalid CloudID "invalid"`,
 		},
+		"negative bootstrap priority": {
+			config: withDefaultConfig(func(cfg *Config) {
+				cfg.Endpoints = []string{"http://localhost:9200"}
+				cfg.Bootstrap.Priority = -1
+			}),
+			err: `priority should be non-negative`,
+		},
+		"invalid bootstrap rollover size": {
+			config: withDefaultConfig(func(cfg *Config) {
+				cfg.Endpoints = []string{"http://localhost:9200"}
+				cfg.Bootstrap.Lifecycle.RolloverMaxPrimaryShardSize = "big"
+			}),
+			err: `lifecycle::rollover_max_primary_shard_size "big" is not a valid byte size, e.g. 50gb`,
+		},
 		"invalid decoded cloudid": {
 			config: withDefaultConfig(func(cfg *Config) {
 				cfg.CloudID = "foo:YWJj"
@@ -8,6 +8,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"runtime"
 
 	"github.com/elastic/go-docappender/v2"
 	"go.opentelemetry.io/collector/client"
@@ -26,12 +27,14 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/elasticsearchexporter/internal/metricgroup"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/elasticsearchexporter/internal/pool"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/elasticsearchexporter/internal/serializer/otelserializer"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/indexbootstrap"
 )
 
 type elasticsearchExporter struct {
 	set                 exporter.Settings
 	config              *Config
 	index               string
+	dataStreamType      string
 	logstashFormat      LogstashFormatSettings
 	defaultMappingMode  MappingMode
 	allowedMappingModes map[string]MappingMode
@@ -43,13 +46,14 @@ type elasticsearchExporter struct {
 	spanEventDocumentRouters [NumMappingModes]documentRouter
 }
 
-func newExporter(cfg *Config, set exporter.Settings, index string) (*elasticsearchExporter, error) {
+func newExporter(cfg *Config, set exporter.Settings, index, dataStreamType string) (*elasticsearchExporter, error) {
 	allowedMappingModes := cfg.allowedMappingModes()
 	defaultMappingMode := allowedMappingModes[canonicalMappingModeName(cfg.Mapping.Mode)]
 	exporter := &elasticsearchExporter{
 		set:                 set,
 		config:              cfg,
 		index:               index,
+		dataStreamType:      dataStreamType,
 		logstashFormat:      cfg.LogstashFormat,
 		allowedMappingModes: allowedMappingModes,
 		defaultMappingMode:  defaultMappingMode,
@@ -68,7 +72,30 @@ func newExporter(cfg *Config, set exporter.Settings, index string) (*elasticsear
 }
 
 func (e *elasticsearchExporter) Start(ctx context.Context, host component.Host) error {
-	if err := e.bulkIndexers.start(ctx, e.config, e.set, host, e.allowedMappingModes); err != nil {
+	userAgent := fmt.Sprintf(
+		"%s/%s (%s/%s)",
+		e.set.BuildInfo.Description,
+		e.set.BuildInfo.Version,
+		runtime.GOOS,
+		runtime.GOARCH,
+	)
+	esClient, err := newElasticsearchClient(ctx, e.config, host, e.set.TelemetrySettings, userAgent)
+	if err != nil {
+		return err
+	}
+
+	if e.config.Bootstrap.Enabled && e.dataStreamType != defaultDataStreamTypeProfiles {
+		resources, err := bootstrapResources(e.config, e.dataStreamType, e.index, bootstrapModes(e.defaultMappingMode, e.allowedMappingModes))
+		if err != nil {
+			return fmt.Errorf("error bootstrapping index templates: %w", err)
+		}
+		bootstrapper := indexbootstrap.New(esClient, indexbootstrap.FlavorElasticsearch, e.config.Bootstrap, e.set.Logger)
+		if err := bootstrapper.Install(ctx, resources); err != nil {
+			return fmt.Errorf("error bootstrapping index templates: %w", err)
+		}
+	}
+
+	if err := e.bulkIndexers.start(e.config, e.set, esClient, e.allowedMappingModes); err != nil {
 		return fmt.Errorf("error starting bulk indexers: %w", err)
 	}
 	return nil
@@ -23,6 +23,7 @@ import (
 	"go.opentelemetry.io/collector/exporter/xexporter"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/elasticsearchexporter/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/indexbootstrap"
 )
 
 var defaultBatcherMinSizeItems = int64(5000)
@@ -95,6 +96,7 @@ func createDefaultConfig() component.Config {
 			Bytes:    5e+6,
 			Interval: 30 * time.Second,
 		},
+		Bootstrap: indexbootstrap.NewDefaultConfig(),
 	}
 }
 
@@ -111,7 +113,7 @@ func createLogsExporter(
 	handleDeprecatedConfig(cf, set.Logger)
 	handleTelemetryConfig(cf, set.Logger)
 
-	exporter, err := newExporter(cf, set, cf.LogsIndex)
+	exporter, err := newExporter(cf, set, cf.LogsIndex, defaultDataStreamTypeLogs)
 	if err != nil {
 		return nil, err
 	}
@@ -134,7 +136,7 @@ func createMetricsExporter(
 	handleDeprecatedConfig(cf, set.Logger)
 	handleTelemetryConfig(cf, set.Logger)
 
-	exporter, err := newExporter(cf, set, cf.MetricsIndex)
+	exporter, err := newExporter(cf, set, cf.MetricsIndex, defaultDataStreamTypeMetrics)
 	if err != nil {
 		return nil, err
 	}
@@ -156,7 +158,7 @@ func createTracesExporter(ctx context.Context,
 	handleDeprecatedConfig(cf, set.Logger)
 	handleTelemetryConfig(cf, set.Logger)
 
-	exporter, err := newExporter(cf, set, cf.TracesIndex)
+	exporter, err := newExporter(cf, set, cf.TracesIndex, defaultDataStreamTypeTraces)
 	if err != nil {
 		return nil, err
 	}
@@ -183,7 +185,7 @@ func createProfilesExporter(
 	handleDeprecatedConfig(cf, set.Logger)
 	handleTelemetryConfig(cf, set.Logger)
 
-	exporter, err := newExporter(cf, set, "")
+	exporter, err := newExporter(cf, set, "", defaultDataStreamTypeProfiles)
 	if err != nil {
 		return nil, err
 	}
@@ -36,6 +36,7 @@ type Config struct {
 	// Options:
 	// - json[default]:  OTLP json bytes.
 	// - proto:  OTLP binary protobuf bytes.
+	// - parquet:  Parquet file, a row group per batch.
 	FormatType string `mapstructure:"format"`
 
 	// Encoding defines the encoding of the telemetry data.
@@ -75,6 +76,11 @@ type Rotation struct {
 	// backup files is the computer's local time.  The default is to use UTC
 	// time.
 	LocalTime bool `mapstructure:"localtime"`
+
+	// MaxAge is the maximum time between the first batch written to a Parquet file and
+	// the rotation of the file, which writes its footer so that it can be read. Only
+	// supported by the parquet format. The default is not to rotate files based on age.
+	MaxAge time.Duration `mapstructure:"max_age"`
 }
 
 type GroupBy struct {
@@ -105,9 +111,30 @@ func (cfg *Config) Validate() error {
 	if cfg.Append && cfg.Rotation != nil {
 		return errors.New("append and rotation enabled at the same time is not supported")
 	}
-	if cfg.FormatType != formatTypeJSON && cfg.FormatType != formatTypeProto {
+	if cfg.FormatType != formatTypeJSON && cfg.FormatType != formatTypeProto && cfg.FormatType != formatTypeParquet {
 		return errors.New("format type is not supported")
 	}
+	if cfg.FormatType == formatTypeParquet && cfg.Encoding == nil {
+		// A Parquet file can't be appended to, its pages are already compressed,
+		// and its footer must be written before it is closed.
+		if cfg.Append {
+			return errors.New("append is not supported by the parquet format")
+		}
+		if cfg.Compression != "" {
+			return errors.New("compression is not supported by the parquet format")
+		}
+		if cfg.GroupBy != nil && cfg.GroupBy.Enabled {
+			return errors.New("group_by is not supported by the parquet format")
+		}
+	}
+	if cfg.Rotation != nil && cfg.Rotation.MaxAge != 0 {
+		if cfg.FormatType != formatTypeParquet || cfg.Encoding != nil {
+			return errors.New("max_age is only supported by the parquet format")
+		}
+		if cfg.Rotation.MaxAge < 0 {
+			return errors.New("max_age must be larger than zero")
+		}
+	}
 	if cfg.Compression != "" && cfg.Compression != compressionZSTD {
 		return errors.New("compression is not supported")
 	}
@@ -104,6 +104,35 @@ func TestLoadConfig(t *testing.T) {
 			id:           component.NewIDWithName(metadata.Type, "format_error"),
 			errorMessage: "format type is not supported",
 		},
+		{
+			id: component.NewIDWithName(metadata.Type, "parquet"),
+			expected: &Config{
+				Path: "./filename.parquet",
+				Rotation: &Rotation{
+					MaxMegabytes: 10,
+					MaxBackups:   defaultMaxBackups,
+					MaxAge:       time.Hour,
+				},
+				FormatType:    formatTypeParquet,
+				FlushInterval: time.Second,
+				GroupBy: &GroupBy{
+					MaxOpenFiles:      defaultMaxOpenFiles,
+					ResourceAttribute: defaultResourceAttribute,
+				},
+			},
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "parquet_append_error"),
+			errorMessage: "append is not supported by the parquet format",
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "max_age_error"),
+			errorMessage: "max_age is only supported by the parquet format",
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "parquet_compression_error"),
+			errorMessage: "compression is not supported by the parquet format",
+		},
 		{
 			id: component.NewIDWithName(metadata.Type, "flush_interval_5"),
 			expected: &Config{
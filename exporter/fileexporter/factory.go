@@ -31,8 +31,9 @@ const (
 	defaultMaxBackups = 100
 
 	// the format of encoded telemetry data
-	formatTypeJSON  = "json"
-	formatTypeProto = "proto"
+	formatTypeJSON    = "json"
+	formatTypeProto   = "proto"
+	formatTypeParquet = "parquet"
 
 	// the type of compression codec
 	compressionZSTD = "zstd"
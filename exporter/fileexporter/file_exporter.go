@@ -11,6 +11,8 @@ import (
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/pprofile"
 	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/parquet"
 )
 
 // fileExporter is the implementation of file exporter that writes telemetry data to a file
@@ -18,9 +20,15 @@ type fileExporter struct {
 	conf       *Config
 	marshaller *marshaller
 	writer     *fileWriter
+	// parquet is set when the telemetry is written to Parquet files, which are written
+	// a row group per batch rather than marshaled.
+	parquet bool
 }
 
 func (e *fileExporter) consumeTraces(_ context.Context, td ptrace.Traces) error {
+	if e.parquet {
+		return e.writer.exportParquet(func(w *parquet.Writer) error { return w.WriteTraces(td) })
+	}
 	buf, err := e.marshaller.marshalTraces(td)
 	if err != nil {
 		return err
@@ -29,6 +37,9 @@ func (e *fileExporter) consumeTraces(_ context.Context, td ptrace.Traces) error
 }
 
 func (e *fileExporter) consumeMetrics(_ context.Context, md pmetric.Metrics) error {
+	if e.parquet {
+		return e.writer.exportParquet(func(w *parquet.Writer) error { return w.WriteMetrics(md) })
+	}
 	buf, err := e.marshaller.marshalMetrics(md)
 	if err != nil {
 		return err
@@ -37,6 +48,9 @@ func (e *fileExporter) consumeMetrics(_ context.Context, md pmetric.Metrics) err
 }
 
 func (e *fileExporter) consumeLogs(_ context.Context, ld plog.Logs) error {
+	if e.parquet {
+		return e.writer.exportParquet(func(w *parquet.Writer) error { return w.WriteLogs(ld) })
+	}
 	buf, err := e.marshaller.marshalLogs(ld)
 	if err != nil {
 		return err
@@ -59,9 +73,13 @@ func (e *fileExporter) Start(_ context.Context, host component.Host) error {
 	if err != nil {
 		return err
 	}
-	export := buildExportFunc(e.conf)
-
-	e.writer, err = newFileWriter(e.conf.Path, e.conf.Append, e.conf.Rotation, e.conf.FlushInterval, export)
+	e.parquet = e.conf.Encoding == nil && e.conf.FormatType == formatTypeParquet
+	if e.parquet {
+		e.writer, err = newParquetFileWriter(e.conf.Path, e.conf.Rotation, e.conf.FlushInterval)
+	} else {
+		export := buildExportFunc(e.conf)
+		e.writer, err = newFileWriter(e.conf.Path, e.conf.Append, e.conf.Rotation, e.conf.FlushInterval, export)
+	}
 	if err != nil {
 		return err
 	}
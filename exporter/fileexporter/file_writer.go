@@ -5,9 +5,12 @@ package fileexporter // import "github.com/open-telemetry/opentelemetry-collecto
 
 import (
 	"encoding/binary"
+	"errors"
 	"io"
 	"sync"
 	"time"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/parquet"
 )
 
 // exportFunc defines how to export encoded telemetry data.
@@ -20,6 +23,19 @@ type fileWriter struct {
 
 	exporter exportFunc
 
+	// parquet writes the telemetry to the file with the parquet format. rotate, if set, rotates
+	// the file once it is complete: once it is bigger than maxSize, or, if maxAge is set, once
+	// maxAge elapsed since the first batch was written to it, at expiry.
+	parquet  *parquet.Writer
+	rotate   func() error
+	maxSize  int64
+	maxAge   time.Duration
+	expiry   time.Time
+	complete bool
+	// stopAgeRotation stops the goroutine rotating the expired files, which closes ageRotationDone.
+	stopAgeRotation chan struct{}
+	ageRotationDone chan struct{}
+
 	flushInterval time.Duration
 	flushTicker   *time.Ticker
 	stopTicker    chan struct{}
@@ -90,6 +106,9 @@ func (w *fileWriter) start() {
 	if w.flushInterval > 0 {
 		w.startFlusher()
 	}
+	if w.maxAge > 0 {
+		w.startAgeRotation()
+	}
 }
 
 // Shutdown stops the exporter and is invoked during shutdown.
@@ -102,6 +121,15 @@ func (w *fileWriter) shutdown() error {
 		close(w.stopTicker)
 		w.mutex.Unlock()
 	}
+	if w.stopAgeRotation != nil {
+		close(w.stopAgeRotation)
+		<-w.ageRotationDone
+	}
+	if w.parquet != nil {
+		w.mutex.Lock()
+		defer w.mutex.Unlock()
+		return errors.Join(w.parquet.Close(), w.file.Close())
+	}
 	return w.file.Close()
 }
 
@@ -0,0 +1,130 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package fileexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/fileexporter"
+
+import (
+	"math"
+	"os"
+	"time"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/parquet"
+)
+
+// defaultMaxMegabytes is the default size of the files before they are rotated, the default of lumberjack.
+const defaultMaxMegabytes = 100
+
+// newParquetFileWriter returns a writer writing the telemetry to a Parquet file, a row group per batch.
+//
+// The footer of a Parquet file must be written before it is rotated, so the files are rotated by the
+// writer once they are bigger than the maximum size or older than the maximum age, rather than by lumberjack.
+func newParquetFileWriter(path string, rotation *Rotation, flushInterval time.Duration) (*fileWriter, error) {
+	w := &fileWriter{
+		path:          path,
+		flushInterval: flushInterval,
+	}
+	if rotation == nil {
+		f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o644)
+		if err != nil {
+			return nil, err
+		}
+		w.file = newBufferedWriteCloser(f)
+	} else {
+		logger := &lumberjack.Logger{
+			Filename:   path,
+			MaxSize:    math.MaxInt32,
+			MaxAge:     rotation.MaxDays,
+			MaxBackups: rotation.MaxBackups,
+			LocalTime:  rotation.LocalTime,
+		}
+		// lumberjack appends to the existing file, which would corrupt it: back it up instead.
+		if err := logger.Rotate(); err != nil {
+			return nil, err
+		}
+		maxMegabytes := rotation.MaxMegabytes
+		if maxMegabytes == 0 {
+			maxMegabytes = defaultMaxMegabytes
+		}
+		w.file = logger
+		w.rotate = logger.Rotate
+		w.maxSize = int64(maxMegabytes) * 1024 * 1024
+		w.maxAge = rotation.MaxAge
+	}
+	w.parquet = parquet.NewWriter(w.file)
+	return w, nil
+}
+
+// exportParquet writes a row group to the Parquet file with write. Once the file is bigger than the maximum
+// size, or older than the maximum age, its footer is written, and the file is rotated before the next batch is
+// written, so that the files are readable as soon as they are complete.
+func (w *fileWriter) exportParquet(write func(*parquet.Writer) error) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.complete {
+		if err := w.rotate(); err != nil {
+			return err
+		}
+		w.parquet = parquet.NewWriter(w.file)
+		w.complete = false
+	}
+	empty := w.parquet.Size() == 0
+	if err := write(w.parquet); err != nil {
+		return err
+	}
+	if w.rotate == nil {
+		return nil
+	}
+	now := time.Now()
+	if empty && w.parquet.Size() > 0 {
+		w.expiry = now.Add(w.maxAge)
+	}
+	if w.parquet.Size() < w.maxSize && !w.expired(now) {
+		return nil
+	}
+	return w.completeParquet()
+}
+
+// expired reports whether the footer of the file must be written because it is older than the maximum age.
+func (w *fileWriter) expired(now time.Time) bool {
+	return w.maxAge > 0 && !w.complete && w.parquet.Size() > 0 && !now.Before(w.expiry)
+}
+
+// completeParquet writes the footer of the Parquet file, which is rotated before the next batch is written.
+// The file is rotated even if the footer couldn't be written, as nothing can be written to it anymore.
+func (w *fileWriter) completeParquet() error {
+	w.complete = true
+	return w.parquet.Close()
+}
+
+// startAgeRotation starts the goroutine writing the footer of the Parquet files once they are older than the
+// maximum age, so that a file is readable even if nothing else is written to it.
+func (w *fileWriter) startAgeRotation() {
+	w.stopAgeRotation = make(chan struct{})
+	w.ageRotationDone = make(chan struct{})
+	go func() {
+		defer close(w.ageRotationDone)
+		timer := time.NewTimer(w.maxAge)
+		defer timer.Stop()
+		for {
+			select {
+			case <-timer.C:
+				w.mutex.Lock()
+				now := time.Now()
+				if w.expired(now) {
+					// Writing to the file failed: the error was returned by the batch written to it.
+					_ = w.completeParquet()
+				}
+				next := w.maxAge
+				if !w.complete && w.parquet.Size() > 0 && w.expiry.After(now) {
+					next = w.expiry.Sub(now)
+				}
+				w.mutex.Unlock()
+				timer.Reset(next)
+			case <-w.stopAgeRotation:
+				return
+			}
+		}
+	}()
+}
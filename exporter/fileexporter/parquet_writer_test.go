@@ -0,0 +1,191 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package fileexporter
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pprofile"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/testdata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/parquet"
+)
+
+func assertParquetFile(t *testing.T, path string) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Greater(t, len(data), 8)
+	assert.Equal(t, "PAR1", string(data[:4]))
+	assert.Equal(t, "PAR1", string(data[len(data)-4:]))
+}
+
+func TestParquetExporter(t *testing.T) {
+	tests := []struct {
+		name    string
+		consume func(fe *fileExporter) error
+	}{
+		{
+			name: "traces",
+			consume: func(fe *fileExporter) error {
+				return fe.consumeTraces(context.Background(), testdata.GenerateTracesTwoSpansSameResource())
+			},
+		},
+		{
+			name: "metrics",
+			consume: func(fe *fileExporter) error {
+				return fe.consumeMetrics(context.Background(), testdata.GenerateMetricsTwoMetrics())
+			},
+		},
+		{
+			name: "logs",
+			consume: func(fe *fileExporter) error {
+				return fe.consumeLogs(context.Background(), testdata.GenerateLogsTwoLogRecordsSameResource())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := &Config{
+				Path:       tempFileName(t),
+				FormatType: formatTypeParquet,
+			}
+			fe := newFileExporter(conf, zap.NewNop()).(*fileExporter)
+			require.NoError(t, fe.Start(context.Background(), componenttest.NewNopHost()))
+			require.NoError(t, tt.consume(fe))
+			require.NoError(t, tt.consume(fe))
+			require.NoError(t, fe.Shutdown(context.Background()))
+			assertParquetFile(t, conf.Path)
+		})
+	}
+}
+
+func TestParquetExporterErrors(t *testing.T) {
+	conf := &Config{
+		Path:       tempFileName(t),
+		FormatType: formatTypeParquet,
+	}
+	fe := newFileExporter(conf, zap.NewNop()).(*fileExporter)
+	require.NoError(t, fe.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, fe.consumeLogs(context.Background(), testdata.GenerateLogsTwoLogRecordsSameResource()))
+	assert.EqualError(t, fe.consumeTraces(context.Background(), testdata.GenerateTracesTwoSpansSameResource()),
+		"cannot write traces to a parquet file holding logs")
+	assert.Error(t, fe.consumeProfiles(context.Background(), pprofile.NewProfiles()))
+	require.NoError(t, fe.Shutdown(context.Background()))
+	assertParquetFile(t, conf.Path)
+}
+
+func TestParquetFileWriterRotation(t *testing.T) {
+	path := tempFileName(t)
+	w, err := newParquetFileWriter(path, &Rotation{MaxBackups: defaultMaxBackups}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(defaultMaxMegabytes*1024*1024), w.maxSize)
+	// Rotate the file after each batch.
+	w.maxSize = 1
+	writeLogs := func(w *parquet.Writer) error {
+		return w.WriteLogs(testdata.GenerateLogsTwoLogRecordsSameResource())
+	}
+	require.NoError(t, w.exportParquet(writeLogs))
+	require.NoError(t, w.exportParquet(writeLogs))
+	require.NoError(t, w.shutdown())
+
+	files, err := filepath.Glob(filepath.Join(filepath.Dir(path), "*"))
+	require.NoError(t, err)
+	require.Len(t, files, 2, "the current file and a backup")
+	for _, f := range files {
+		assertParquetFile(t, f)
+	}
+}
+
+func TestParquetFileWriterMaxAge(t *testing.T) {
+	path := tempFileName(t)
+	w, err := newParquetFileWriter(path, &Rotation{MaxBackups: defaultMaxBackups, MaxAge: 50 * time.Millisecond}, 0)
+	require.NoError(t, err)
+	w.start()
+	writeLogs := func(w *parquet.Writer) error {
+		return w.WriteLogs(testdata.GenerateLogsTwoLogRecordsSameResource())
+	}
+	backups := func() []string {
+		files, err := filepath.Glob(filepath.Join(filepath.Dir(path), "*"))
+		require.NoError(t, err)
+		var backups []string
+		for _, f := range files {
+			if f != path {
+				backups = append(backups, f)
+			}
+		}
+		return backups
+	}
+
+	// The footer of the file is written once it is older than the maximum age, even if nothing else is written to it.
+	require.NoError(t, w.exportParquet(writeLogs))
+	require.Eventually(t, func() bool {
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		return len(data) > 8 && string(data[len(data)-4:]) == "PAR1"
+	}, 5*time.Second, 10*time.Millisecond)
+	assertParquetFile(t, path)
+	assert.Empty(t, backups())
+
+	// The complete file is rotated when the next batch is written.
+	require.NoError(t, w.exportParquet(writeLogs))
+	require.Len(t, backups(), 1)
+	assertParquetFile(t, backups()[0])
+
+	// The footer of a file older than the maximum age is written when a batch is written to it.
+	w.mutex.Lock()
+	w.expiry = time.Now()
+	w.mutex.Unlock()
+	require.NoError(t, w.exportParquet(writeLogs))
+	assertParquetFile(t, path)
+	require.NoError(t, w.shutdown())
+	assert.Len(t, backups(), 1)
+}
+
+func TestParquetFileWriterExistingFile(t *testing.T) {
+	path := tempFileName(t)
+	require.NoError(t, os.WriteFile(path, []byte("existing"), 0o600))
+
+	// Without rotation, the existing file is truncated.
+	w, err := newParquetFileWriter(path, nil, 0)
+	require.NoError(t, err)
+	require.NoError(t, w.exportParquet(func(w *parquet.Writer) error {
+		return w.WriteLogs(plog.NewLogs())
+	}))
+	require.NoError(t, w.shutdown())
+	assertParquetFile(t, path)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "existing")
+
+	// With rotation, the existing file is backed up rather than appended to.
+	require.NoError(t, os.WriteFile(path, []byte("existing"), 0o600))
+	w, err = newParquetFileWriter(path, &Rotation{MaxBackups: defaultMaxBackups}, 0)
+	require.NoError(t, err)
+	require.NoError(t, w.exportParquet(func(w *parquet.Writer) error {
+		return w.WriteLogs(testdata.GenerateLogsTwoLogRecordsSameResource())
+	}))
+	require.NoError(t, w.shutdown())
+	assertParquetFile(t, path)
+
+	files, err := filepath.Glob(filepath.Join(filepath.Dir(path), "*"))
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	for _, f := range files {
+		if f != path {
+			data, err = os.ReadFile(f)
+			require.NoError(t, err)
+			assert.Equal(t, "existing", string(data))
+		}
+	}
+}
@@ -21,6 +21,7 @@ const (
 	resourceRouting
 	streamIDRouting
 	attrRouting
+	ottlRouting
 )
 
 const (
@@ -30,6 +31,7 @@ const (
 	resourceRoutingStr   = "resource"
 	streamIDRoutingStr   = "streamID"
 	attrRoutingStr       = "attributes"
+	ottlRoutingStr       = "ottl"
 )
 
 // Config defines configuration for the exporter.
@@ -49,6 +51,41 @@ type Config struct {
 	// Supports all attributes available (both resource and span), as well as the pseudo attributes "span.kind" and
 	// "span.name".
 	RoutingAttributes []string `mapstructure:"routing_attributes"`
+
+	// RoutingExpression is an OTTL value expression used to compute the routing key when the routing key is "ottl",
+	// e.g. `attributes["tenant"]`. The result is converted to a string, and values evaluating to nil share the empty
+	// routing key.
+	RoutingExpression string `mapstructure:"routing_expression"`
+
+	// RoutingContext is the OTTL context the routing expression is evaluated in: "resource", or the context of the
+	// individual items of the signal, "span", "log" or "datapoint". Defaults to the context of the individual items.
+	RoutingContext string `mapstructure:"routing_context"`
+
+	// BoundedLoad enables consistent hashing with bounded loads, limiting the share of in-flight requests each backend
+	// can receive. Keys hashed to a backend at capacity are routed to the next backend in the ring with spare capacity.
+	BoundedLoad *BoundedLoadSettings `mapstructure:"bounded_load"`
+
+	// Draining keeps sending routing keys already seen to their previous backend for a grace period after the list of
+	// backends changes, including backends removed from the list, while new keys are routed using the new list.
+	Draining *DrainingSettings `mapstructure:"draining"`
+}
+
+// DrainingSettings defines the configuration for draining backends on changes to the list of backends
+type DrainingSettings struct {
+	// GracePeriod is how long backends removed from the list keep receiving routing keys already seen.
+	GracePeriod time.Duration `mapstructure:"grace_period"`
+}
+
+// BoundedLoadSettings defines the configuration for consistent hashing with bounded loads
+type BoundedLoadSettings struct {
+	// CapacityFactor is how much more than its fair share of the in-flight requests a backend can receive, e.g. 1.25
+	// allows 25% more. Lower values balance the load more evenly, at the cost of moving more keys to other backends.
+	CapacityFactor float64 `mapstructure:"capacity_factor"`
+
+	// AffinityWindow is how long a key keeps the backend it was routed to after it was last seen. Only keys not seen
+	// within the window are routed based on the load of the backends, so that the keys of a trace are not spread
+	// across backends as their load changes.
+	AffinityWindow time.Duration `mapstructure:"affinity_window"`
 }
 
 // Protocol holds the individual protocol-specific settings. Only OTLP is supported at the moment.
@@ -67,6 +104,10 @@ type ResolverSettings struct {
 // StaticResolver defines the configuration for the resolver providing a fixed list of backends
 type StaticResolver struct {
 	Hostnames []string `mapstructure:"hostnames"`
+
+	// Weights sets the relative weight of hostnames, which defaults to 100. A hostname with a weight of 200 receives
+	// twice as many keys as a hostname with the default weight.
+	Weights map[string]int `mapstructure:"weights"`
 }
 
 // DNSResolver defines the configuration for the DNS resolver
@@ -6,6 +6,7 @@ package loadbalancingexporter
 import (
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
@@ -24,4 +25,37 @@ func TestLoadConfig(t *testing.T) {
 	require.NoError(t, err)
 	require.NoError(t, sub.Unmarshal(cfg))
 	require.NotNil(t, cfg)
+
+	lbCfg := cfg.(*Config)
+	require.Equal(t, map[string]int{"endpoint-2:55678": 200}, lbCfg.Resolver.Static.Weights)
+	require.Equal(t, &BoundedLoadSettings{CapacityFactor: 1.25}, lbCfg.BoundedLoad)
+}
+
+func TestLoadConfigDraining(t *testing.T) {
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+	require.NoError(t, err)
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+
+	sub, err := cm.Sub(component.NewIDWithName(metadata.Type, "3").String())
+	require.NoError(t, err)
+	require.NoError(t, sub.Unmarshal(cfg))
+
+	require.Equal(t, &DrainingSettings{GracePeriod: time.Minute}, cfg.(*Config).Draining)
+}
+
+func TestLoadConfigOTTLRouting(t *testing.T) {
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+	require.NoError(t, err)
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+
+	sub, err := cm.Sub(component.NewIDWithName(metadata.Type, "6").String())
+	require.NoError(t, err)
+	require.NoError(t, sub.Unmarshal(cfg))
+
+	lbCfg := cfg.(*Config)
+	require.Equal(t, ottlRoutingStr, lbCfg.RoutingKey)
+	require.Equal(t, `attributes["tenant"]`, lbCfg.RoutingExpression)
+	require.Equal(t, resourceContextStr, lbCfg.RoutingContext)
 }
@@ -5,6 +5,7 @@ package loadbalancingexporter // import "github.com/open-telemetry/opentelemetry
 
 import (
 	"hash/crc32"
+	"math"
 	"sort"
 )
 
@@ -31,7 +32,18 @@ type hashRing struct {
 
 // newHashRing builds a new immutable consistent hash ring based on the given endpoints.
 func newHashRing(endpoints []string) *hashRing {
-	items := positionsForEndpoints(endpoints, defaultWeight)
+	return newWeightedHashRing(endpoints, nil)
+}
+
+// newWeightedHashRing builds a new immutable consistent hash ring, where each endpoint takes as many positions in the
+// ring as its weight. Endpoints without a weight take the default number of positions.
+func newWeightedHashRing(endpoints []string, weights map[string]int) *hashRing {
+	items := positionsForWeightedEndpoints(endpoints, func(endpoint string) int {
+		if weight, ok := weights[endpoint]; ok {
+			return weight
+		}
+		return defaultWeight
+	})
 	return &hashRing{
 		items: items,
 	}
@@ -51,6 +63,58 @@ func (h *hashRing) endpointFor(identifier []byte) string {
 	return h.findEndpoint(position(pos))
 }
 
+// boundedEndpointFor calculates which backend is responsible for the given identifier, following the consistent
+// hashing with bounded loads algorithm by Mirrokni et al.: starting from the position of the identifier, the first
+// endpoint accepted by withinCapacity is returned. If no endpoint has capacity left, the endpoint responsible for the
+// identifier in the plain ring is returned.
+func (h *hashRing) boundedEndpointFor(identifier []byte, withinCapacity func(endpoint string) bool) string {
+	if h == nil || len(h.items) == 0 {
+		return ""
+	}
+	hasher := crc32.NewIEEE()
+	hasher.Write(identifier)
+	pos := position(hasher.Sum32() % maxPositions)
+
+	ringSize := len(h.items)
+	start := sort.Search(ringSize, func(i int) bool {
+		return h.items[i].pos >= pos
+	}) % ringSize
+
+	visited := map[string]bool{}
+	for i := 0; i < ringSize; i++ {
+		endpoint := h.items[(start+i)%ringSize].endpoint
+		if visited[endpoint] {
+			continue
+		}
+		visited[endpoint] = true
+		if withinCapacity(endpoint) {
+			return endpoint
+		}
+	}
+	return h.items[start].endpoint
+}
+
+// shares returns the fraction of the positions in the ring taken by each endpoint.
+func (h *hashRing) shares() map[string]float64 {
+	res := map[string]float64{}
+	if h == nil || len(h.items) == 0 {
+		return res
+	}
+	for _, item := range h.items {
+		res[item.endpoint]++
+	}
+	for endpoint := range res {
+		res[endpoint] /= float64(len(h.items))
+	}
+	return res
+}
+
+// boundedCapacity returns the maximum load of an endpoint taking the given share of the ring, when the total load
+// across all endpoints is totalLoad. The new item is counted as part of the total load.
+func boundedCapacity(capacityFactor, share float64, totalLoad int64) int64 {
+	return int64(math.Ceil(capacityFactor * share * float64(totalLoad+1)))
+}
+
 // findEndpoint returns the "next" endpoint starting from the given position, or an empty string in case no endpoints are available
 func (h *hashRing) findEndpoint(pos position) string {
 	ringSize := len(h.items)
@@ -106,7 +170,12 @@ func positionsFor(endpoint string, numPoints int) []position {
 	for i := 0; i < numPoints; i++ {
 		h := crc32.NewIEEE()
 		h.Write([]byte(endpoint))
-		h.Write([]byte{byte(i)})
+		if i < 256 {
+			h.Write([]byte{byte(i)})
+		} else {
+			// keep the positions for the first 256 points stable, while still having distinct positions for higher weights
+			h.Write([]byte{byte(i), byte(i >> 8)})
+		}
 		hash := h.Sum32()
 		pos := hash % maxPositions
 		res = append(res, position(pos))
@@ -117,11 +186,16 @@ func positionsFor(endpoint string, numPoints int) []position {
 
 // positionsForEndpoints calculates all the positions for all the given endpoints
 func positionsForEndpoints(endpoints []string, weight int) []ringItem {
+	return positionsForWeightedEndpoints(endpoints, func(string) int { return weight })
+}
+
+// positionsForWeightedEndpoints calculates all the positions for all the given endpoints, with the number of positions
+// for each endpoint given by weightFor
+func positionsForWeightedEndpoints(endpoints []string, weightFor func(endpoint string) int) []ringItem {
 	var items []ringItem
 	positions := map[position]bool{} // tracking the used positions
 	for _, endpoint := range endpoints {
-		// for this initial implementation, we don't allow endpoints to have custom weights
-		for _, pos := range positionsFor(endpoint, weight) {
+		for _, pos := range positionsFor(endpoint, weightFor(endpoint)) {
 			// if this position is occupied already, skip this item
 			if _, found := positions[pos]; found {
 				continue
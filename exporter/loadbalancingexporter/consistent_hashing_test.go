@@ -218,3 +218,91 @@ func TestEqual(t *testing.T) {
 		})
 	}
 }
+
+func TestNewWeightedHashRing(t *testing.T) {
+	// prepare
+	endpoints := []string{"endpoint-1", "endpoint-2"}
+
+	// test
+	ring := newWeightedHashRing(endpoints, map[string]int{"endpoint-2": 300})
+
+	// verify
+	shares := ring.shares()
+	assert.InDelta(t, 0.25, shares["endpoint-1"], 0.01)
+	assert.InDelta(t, 0.75, shares["endpoint-2"], 0.01)
+
+	counts := map[string]int{}
+	for i := 0; i < 10000; i++ {
+		counts[ring.endpointFor([]byte(fmt.Sprintf("key-%d", i)))]++
+	}
+	assert.InDelta(t, 2500, counts["endpoint-1"], 250)
+	assert.InDelta(t, 7500, counts["endpoint-2"], 250)
+}
+
+func TestNewWeightedHashRingDefaultWeight(t *testing.T) {
+	endpoints := []string{"endpoint-1", "endpoint-2"}
+	assert.True(t, newHashRing(endpoints).equal(newWeightedHashRing(endpoints, nil)))
+	assert.True(t, newHashRing(endpoints).equal(newWeightedHashRing(endpoints, map[string]int{"endpoint-1": defaultWeight})))
+}
+
+func TestPositionsForHighWeight(t *testing.T) {
+	// test
+	positions := positionsFor("endpoint-1", 512)
+
+	// verify
+	assert.Equal(t, positionsFor("endpoint-1", 256), positions[:256], "the positions for the first points should be stable")
+	unique := map[position]bool{}
+	for _, pos := range positions {
+		unique[pos] = true
+	}
+	assert.Greater(t, len(unique), 500, "points beyond 256 should get distinct positions")
+}
+
+func TestBoundedEndpointFor(t *testing.T) {
+	// prepare
+	ring := newHashRing([]string{"endpoint-1", "endpoint-2"})
+
+	for _, tt := range []struct {
+		name           string
+		id             []byte
+		withinCapacity func(string) bool
+		expected       string
+	}{
+		{"all within capacity", []byte{1, 2, 0, 0}, func(string) bool { return true }, "endpoint-1"},
+		{"primary at capacity", []byte{1, 2, 0, 0}, func(e string) bool { return e != "endpoint-1" }, "endpoint-2"},
+		{"none within capacity", []byte{1, 2, 0, 0}, func(string) bool { return false }, "endpoint-1"},
+		{"other primary at capacity", []byte{128, 128, 0, 0}, func(e string) bool { return e != "endpoint-2" }, "endpoint-1"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// test
+			endpoint := ring.boundedEndpointFor(tt.id, tt.withinCapacity)
+
+			// verify
+			assert.Equal(t, tt.expected, endpoint)
+		})
+	}
+}
+
+func TestBoundedEndpointForEmptyRing(t *testing.T) {
+	var ring *hashRing
+	assert.Empty(t, ring.boundedEndpointFor([]byte{1}, func(string) bool { return true }))
+	assert.Empty(t, newHashRing(nil).boundedEndpointFor([]byte{1}, func(string) bool { return true }))
+}
+
+func TestBoundedCapacity(t *testing.T) {
+	for _, tt := range []struct {
+		capacityFactor float64
+		share          float64
+		totalLoad      int64
+		expected       int64
+	}{
+		{1, 0.5, 0, 1},
+		{1.25, 0.5, 3, 3},
+		{1.25, 0.25, 15, 5},
+		{2, 0.5, 9, 10},
+	} {
+		t.Run(fmt.Sprintf("factor %v share %v load %d", tt.capacityFactor, tt.share, tt.totalLoad), func(t *testing.T) {
+			assert.Equal(t, tt.expected, boundedCapacity(tt.capacityFactor, tt.share, tt.totalLoad))
+		})
+	}
+}
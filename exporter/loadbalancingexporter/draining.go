@@ -0,0 +1,76 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package loadbalancingexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter"
+
+import (
+	"sync"
+	"time"
+)
+
+// drainingExporter is an exporter for a backend removed from the list of backends, which keeps receiving routing keys
+// already seen until its grace period is over.
+type drainingExporter struct {
+	exporter *wrappedExporter
+	timer    *time.Timer
+}
+
+// route is the backend a routing key was last sent to.
+type route struct {
+	endpoint string
+	lastSeen time.Time
+}
+
+// routeCache remembers the backend each routing key was last sent to. Routes not seen for longer than the ttl are
+// forgotten, so that the cache only holds the keys seen recently.
+type routeCache struct {
+	ttl time.Duration
+
+	mu        sync.Mutex
+	routes    map[string]route
+	lastSweep time.Time
+}
+
+func newRouteCache(ttl time.Duration) *routeCache {
+	return &routeCache{
+		ttl:       ttl,
+		routes:    map[string]route{},
+		lastSweep: time.Now(),
+	}
+}
+
+// get returns the backend the key was last sent to, if it was seen within the ttl.
+func (c *routeCache) get(key string, now time.Time) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	r, found := c.routes[key]
+	if !found || now.Sub(r.lastSeen) > c.ttl {
+		return "", false
+	}
+	return r.endpoint, true
+}
+
+// set records that the key was sent to the given backend. Expired routes are removed at most once per ttl.
+func (c *routeCache) set(key, endpoint string, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.routes[key] = route{endpoint: endpoint, lastSeen: now}
+
+	if now.Sub(c.lastSweep) <= c.ttl {
+		return
+	}
+	for k, r := range c.routes {
+		if now.Sub(r.lastSeen) > c.ttl {
+			delete(c.routes, k)
+		}
+	}
+	c.lastSweep = now
+}
+
+func (c *routeCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.routes)
+}
@@ -0,0 +1,48 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package loadbalancingexporter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouteCache(t *testing.T) {
+	// prepare
+	now := time.Now()
+	cache := newRouteCache(time.Minute)
+
+	// test
+	cache.set("key-1", "endpoint-1", now)
+
+	// verify
+	endpoint, found := cache.get("key-1", now.Add(30*time.Second))
+	assert.True(t, found)
+	assert.Equal(t, "endpoint-1", endpoint)
+
+	_, found = cache.get("key-2", now)
+	assert.False(t, found)
+
+	_, found = cache.get("key-1", now.Add(2*time.Minute))
+	assert.False(t, found, "routes not seen within the ttl should be forgotten")
+}
+
+func TestRouteCacheSweep(t *testing.T) {
+	// prepare
+	now := time.Now()
+	cache := newRouteCache(time.Minute)
+	cache.set("key-1", "endpoint-1", now)
+	cache.set("key-2", "endpoint-2", now.Add(30*time.Second))
+	assert.Equal(t, 2, cache.len())
+
+	// test
+	cache.set("key-3", "endpoint-1", now.Add(90*time.Second))
+
+	// verify
+	assert.Equal(t, 2, cache.len(), "expired routes should be removed")
+	_, found := cache.get("key-2", now.Add(90*time.Second))
+	assert.True(t, found)
+}
@@ -28,6 +28,8 @@ type TelemetryBuilder struct {
 	registrations                 []metric.Registration
 	LoadbalancerBackendLatency    metric.Int64Histogram
 	LoadbalancerBackendOutcome    metric.Int64Counter
+	LoadbalancerKeysDrained       metric.Int64Counter
+	LoadbalancerKeysMigrated      metric.Int64Counter
 	LoadbalancerNumBackendUpdates metric.Int64Counter
 	LoadbalancerNumBackends       metric.Int64Gauge
 	LoadbalancerNumResolutions    metric.Int64Counter
@@ -75,6 +77,18 @@ func NewTelemetryBuilder(settings component.TelemetrySettings, options ...Teleme
 		metric.WithUnit("{outcomes}"),
 	)
 	errs = errors.Join(errs, err)
+	builder.LoadbalancerKeysDrained, err = builder.meter.Int64Counter(
+		"otelcol_loadbalancer_keys_drained",
+		metric.WithDescription("Number of routing keys sent to a backend being drained after it was removed from the list of backends."),
+		metric.WithUnit("{keys}"),
+	)
+	errs = errors.Join(errs, err)
+	builder.LoadbalancerKeysMigrated, err = builder.meter.Int64Counter(
+		"otelcol_loadbalancer_keys_migrated",
+		metric.WithDescription("Number of routing keys sent to a different backend than before, after a change in the list of backends."),
+		metric.WithUnit("{keys}"),
+	)
+	errs = errors.Join(errs, err)
 	builder.LoadbalancerNumBackendUpdates, err = builder.meter.Int64Counter(
 		"otelcol_loadbalancer_num_backend_updates",
 		metric.WithDescription("Number of times the list of backends was updated."),
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	"go.opentelemetry.io/collector/component"
 	"go.uber.org/zap"
@@ -17,12 +18,21 @@ import (
 )
 
 const (
-	defaultPort = "4317"
+	defaultPort           = "4317"
+	defaultCapacityFactor = 1.25
+	defaultAffinityWindow = 30 * time.Second
+	defaultGracePeriod    = 30 * time.Second
+
+	// maxWeight bounds the weights of the backends, each backend takes as many positions in the ring as its weight
+	maxWeight = 1000
 )
 
 var (
 	errNoResolver                = errors.New("no resolvers specified for the exporter")
 	errMultipleResolversProvided = errors.New("only one resolver should be specified")
+	errInvalidCapacityFactor     = errors.New("the bounded load capacity factor must be at least 1")
+	errInvalidAffinityWindow     = errors.New("the bounded load affinity window must not be negative")
+	errInvalidGracePeriod        = errors.New("the draining grace period must not be negative")
 )
 
 type componentFactory func(ctx context.Context, endpoint string) (component.Component, error)
@@ -31,11 +41,24 @@ type loadBalancer struct {
 	logger *zap.Logger
 	host   component.Host
 
-	res  resolver
-	ring *hashRing
+	res     resolver
+	ring    *hashRing
+	weights map[string]int
+
+	// capacityFactor enables consistent hashing with bounded loads when greater than zero
+	capacityFactor float64
+	shares         map[string]float64
+	pins           *routeCache
+
+	// gracePeriod enables draining backends on changes to the list of backends when greater than zero
+	gracePeriod time.Duration
+	drainUntil  time.Time
+	routes      *routeCache
 
 	componentFactory componentFactory
 	exporters        map[string]*wrappedExporter
+	draining         map[string]*drainingExporter
+	telemetry        *metadata.TelemetryBuilder
 
 	stopped    bool
 	updateLock sync.RWMutex
@@ -62,9 +85,27 @@ func newLoadBalancer(logger *zap.Logger, cfg component.Config, factory component
 		return nil, errMultipleResolversProvided
 	}
 
+	capacityFactor, err := boundedLoadCapacityFactor(oCfg.BoundedLoad)
+	if err != nil {
+		return nil, err
+	}
+	affinityWindow, err := boundedLoadAffinityWindow(oCfg.BoundedLoad)
+	if err != nil {
+		return nil, err
+	}
+	gracePeriod, err := drainingGracePeriod(oCfg.Draining)
+	if err != nil {
+		return nil, err
+	}
+
 	var res resolver
+	var weights map[string]int
 	if oCfg.Resolver.Static != nil {
-		var err error
+		if err = validateWeights(oCfg.Resolver.Static.Hostnames, oCfg.Resolver.Static.Weights); err != nil {
+			return nil, err
+		}
+		weights = oCfg.Resolver.Static.Weights
+
 		res, err = newStaticResolver(
 			oCfg.Resolver.Static.Hostnames,
 			telemetry,
@@ -132,12 +173,78 @@ func newLoadBalancer(logger *zap.Logger, cfg component.Config, factory component
 		return nil, errNoResolver
 	}
 
-	return &loadBalancer{
+	lb := &loadBalancer{
 		logger:           logger,
 		res:              res,
+		weights:          weights,
+		capacityFactor:   capacityFactor,
+		gracePeriod:      gracePeriod,
 		componentFactory: factory,
 		exporters:        map[string]*wrappedExporter{},
-	}, nil
+		draining:         map[string]*drainingExporter{},
+		telemetry:        telemetry,
+	}
+	if capacityFactor > 0 {
+		lb.pins = newRouteCache(affinityWindow)
+	}
+	if gracePeriod > 0 {
+		lb.routes = newRouteCache(gracePeriod)
+	}
+	return lb, nil
+}
+
+func drainingGracePeriod(cfg *DrainingSettings) (time.Duration, error) {
+	if cfg == nil {
+		return 0, nil
+	}
+	if cfg.GracePeriod == 0 {
+		return defaultGracePeriod, nil
+	}
+	if cfg.GracePeriod < 0 {
+		return 0, errInvalidGracePeriod
+	}
+	return cfg.GracePeriod, nil
+}
+
+func boundedLoadCapacityFactor(cfg *BoundedLoadSettings) (float64, error) {
+	if cfg == nil {
+		return 0, nil
+	}
+	if cfg.CapacityFactor == 0 {
+		return defaultCapacityFactor, nil
+	}
+	if cfg.CapacityFactor < 1 {
+		return 0, errInvalidCapacityFactor
+	}
+	return cfg.CapacityFactor, nil
+}
+
+func boundedLoadAffinityWindow(cfg *BoundedLoadSettings) (time.Duration, error) {
+	if cfg == nil {
+		return 0, nil
+	}
+	if cfg.AffinityWindow == 0 {
+		return defaultAffinityWindow, nil
+	}
+	if cfg.AffinityWindow < 0 {
+		return 0, errInvalidAffinityWindow
+	}
+	return cfg.AffinityWindow, nil
+}
+
+func validateWeights(hostnames []string, weights map[string]int) error {
+	for hostname, weight := range weights {
+		if !endpointFound(hostname, hostnames) {
+			return fmt.Errorf("weight set for unknown hostname %q", hostname)
+		}
+		if weight <= 0 {
+			return fmt.Errorf("weight for hostname %q must be positive, got %d", hostname, weight)
+		}
+		if weight > maxWeight {
+			return fmt.Errorf("weight for hostname %q must be at most %d, got %d", hostname, maxWeight, weight)
+		}
+	}
+	return nil
 }
 
 func (lb *loadBalancer) Start(ctx context.Context, host component.Host) error {
@@ -147,13 +254,15 @@ func (lb *loadBalancer) Start(ctx context.Context, host component.Host) error {
 }
 
 func (lb *loadBalancer) onBackendChanges(resolved []string) {
-	newRing := newHashRing(resolved)
+	newRing := newWeightedHashRing(resolved, lb.weights)
 
 	if !newRing.equal(lb.ring) {
 		lb.updateLock.Lock()
 		defer lb.updateLock.Unlock()
 
 		lb.ring = newRing
+		lb.shares = newRing.shares()
+		lb.drainUntil = time.Now().Add(lb.gracePeriod)
 
 		// TODO: set a timeout?
 		ctx := context.Background()
@@ -169,6 +278,14 @@ func (lb *loadBalancer) addMissingExporters(ctx context.Context, endpoints []str
 		endpoint = endpointWithPort(endpoint)
 
 		if _, exists := lb.exporters[endpoint]; !exists {
+			if d, draining := lb.draining[endpoint]; draining {
+				// the backend came back before the end of its grace period
+				d.timer.Stop()
+				delete(lb.draining, endpoint)
+				lb.exporters[endpoint] = d.exporter
+				continue
+			}
+
 			exp, err := lb.componentFactory(ctx, endpoint)
 			if err != nil {
 				lb.logger.Error("failed to create new exporter for endpoint", zap.String("endpoint", endpoint), zap.Error(err))
@@ -199,15 +316,38 @@ func (lb *loadBalancer) removeExtraExporters(ctx context.Context, endpoints []st
 	for existing := range lb.exporters {
 		if !endpointFound(existing, endpointsWithPort) {
 			exp := lb.exporters[existing]
+			delete(lb.exporters, existing)
+			if lb.gracePeriod > 0 {
+				lb.drain(ctx, existing, exp)
+				continue
+			}
 			// Shutdown the exporter asynchronously to avoid blocking the resolver
 			go func() {
 				_ = exp.Shutdown(ctx)
 			}()
-			delete(lb.exporters, existing)
 		}
 	}
 }
 
+// drain keeps the exporter of a removed backend available for routing keys already seen, and shuts it down once the
+// grace period is over. The caller must hold the update lock.
+func (lb *loadBalancer) drain(ctx context.Context, endpoint string, exp *wrappedExporter) {
+	timer := time.AfterFunc(lb.gracePeriod, func() {
+		lb.updateLock.Lock()
+		defer lb.updateLock.Unlock()
+
+		d, found := lb.draining[endpoint]
+		if !found || d.exporter != exp {
+			return
+		}
+		delete(lb.draining, endpoint)
+		go func() {
+			_ = exp.Shutdown(ctx)
+		}()
+	})
+	lb.draining[endpoint] = &drainingExporter{exporter: exp, timer: timer}
+}
+
 func endpointFound(endpoint string, endpoints []string) bool {
 	for _, candidate := range endpoints {
 		if candidate == endpoint {
@@ -222,9 +362,16 @@ func (lb *loadBalancer) Shutdown(ctx context.Context) error {
 	err := lb.res.shutdown(ctx)
 	lb.stopped = true
 
+	lb.updateLock.Lock()
+	defer lb.updateLock.Unlock()
 	for _, e := range lb.exporters {
 		err = errors.Join(err, e.Shutdown(ctx))
 	}
+	for endpoint, d := range lb.draining {
+		d.timer.Stop()
+		err = errors.Join(err, d.exporter.Shutdown(ctx))
+		delete(lb.draining, endpoint)
+	}
 	return err
 }
 
@@ -235,12 +382,80 @@ func (lb *loadBalancer) exporterAndEndpoint(identifier []byte) (*wrappedExporter
 	// for details: https://github.com/open-telemetry/opentelemetry-collector-contrib/issues/1690
 	lb.updateLock.RLock()
 	defer lb.updateLock.RUnlock()
-	endpoint := lb.ring.endpointFor(identifier)
+	var endpoint string
+	if lb.capacityFactor > 0 {
+		endpoint = lb.boundedEndpoint(identifier)
+	} else {
+		endpoint = lb.ring.endpointFor(identifier)
+	}
+	if lb.routes != nil {
+		endpoint = lb.drainingEndpoint(identifier, endpoint)
+	}
 	exp, found := lb.exporters[endpointWithPort(endpoint)]
 	if !found {
+		if d, draining := lb.draining[endpointWithPort(endpoint)]; draining {
+			return d.exporter, endpoint, nil
+		}
 		// something is really wrong... how come we couldn't find the exporter??
 		return nil, "", fmt.Errorf("couldn't find the exporter for the endpoint %q", endpoint)
 	}
 
 	return exp, endpoint, nil
 }
+
+// boundedEndpoint returns the backend for a routing key with bounded loads. The load of the backends is only checked
+// for keys not seen within the affinity window, keys already seen keep their backend while it is in the list of
+// backends, so that changes in the load do not move the keys of a trace to another backend from one batch to the
+// next. The caller must hold the update lock.
+func (lb *loadBalancer) boundedEndpoint(identifier []byte) string {
+	key := string(identifier)
+	now := time.Now()
+
+	endpoint, seen := lb.pins.get(key, now)
+	if _, active := lb.exporters[endpointWithPort(endpoint)]; !seen || !active {
+		endpoint = lb.ring.boundedEndpointFor(identifier, lb.withinCapacity())
+	}
+	lb.pins.set(key, endpoint, now)
+	return endpoint
+}
+
+// withinCapacity returns a function telling whether an endpoint can take one more in-flight request without exceeding
+// its bounded load. The caller must hold the update lock.
+func (lb *loadBalancer) withinCapacity() func(endpoint string) bool {
+	var totalLoad int64
+	for _, exp := range lb.exporters {
+		totalLoad += exp.inflight.Load()
+	}
+	return func(endpoint string) bool {
+		exp, found := lb.exporters[endpointWithPort(endpoint)]
+		if !found {
+			return false
+		}
+		return exp.inflight.Load()+1 <= boundedCapacity(lb.capacityFactor, lb.shares[endpoint], totalLoad)
+	}
+}
+
+// drainingEndpoint returns the backend for a routing key, given the backend chosen by the ring. During the grace period
+// after a change in the list of backends, keys already seen keep being sent to their previous backend while it is
+// available, including backends being drained. The caller must hold the update lock.
+func (lb *loadBalancer) drainingEndpoint(identifier []byte, endpoint string) string {
+	key := string(identifier)
+	now := time.Now()
+
+	previous, seen := lb.routes.get(key, now)
+	if seen && previous != endpoint {
+		_, active := lb.exporters[endpointWithPort(previous)]
+		_, draining := lb.draining[endpointWithPort(previous)]
+		if now.Before(lb.drainUntil) && (active || draining) {
+			if draining {
+				lb.telemetry.LoadbalancerKeysDrained.Add(context.Background(), 1)
+			}
+			endpoint = previous
+		} else {
+			lb.telemetry.LoadbalancerKeysMigrated.Add(context.Background(), 1)
+		}
+	}
+
+	lb.routes.set(key, endpoint, now)
+	return endpoint
+}
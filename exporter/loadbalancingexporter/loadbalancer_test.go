@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -15,9 +16,13 @@ import (
 	"go.opentelemetry.io/collector/exporter"
 	"go.opentelemetry.io/collector/exporter/exportertest"
 	"go.opentelemetry.io/collector/exporter/otlpexporter"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata/metricdatatest"
+	"go.uber.org/zap"
 	"k8s.io/client-go/tools/clientcmd"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter/internal/metadatatest"
 )
 
 func TestNewLoadBalancerNoResolver(t *testing.T) {
@@ -451,6 +456,386 @@ func TestNewLoadBalancerInvalidServiceAwsResolver(t *testing.T) {
 	assert.True(t, clientcmd.IsConfigurationInvalid(err) || errors.Is(err, errNoServiceName))
 }
 
+func TestNewLoadBalancerInvalidCapacityFactor(t *testing.T) {
+	// prepare
+	ts, tb := getTelemetryAssets(t)
+	cfg := simpleConfig()
+	cfg.BoundedLoad = &BoundedLoadSettings{CapacityFactor: 0.5}
+
+	// test
+	p, err := newLoadBalancer(ts.Logger, cfg, nil, tb)
+
+	// verify
+	require.Nil(t, p)
+	require.Equal(t, errInvalidCapacityFactor, err)
+}
+
+func TestNewLoadBalancerDefaultCapacityFactor(t *testing.T) {
+	// prepare
+	ts, tb := getTelemetryAssets(t)
+	cfg := simpleConfig()
+	cfg.BoundedLoad = &BoundedLoadSettings{}
+
+	// test
+	p, err := newLoadBalancer(ts.Logger, cfg, nil, tb)
+
+	// verify
+	require.NoError(t, err)
+	assert.Equal(t, defaultCapacityFactor, p.capacityFactor)
+	assert.Equal(t, defaultAffinityWindow, p.pins.ttl)
+}
+
+func TestNewLoadBalancerInvalidAffinityWindow(t *testing.T) {
+	// prepare
+	ts, tb := getTelemetryAssets(t)
+	cfg := simpleConfig()
+	cfg.BoundedLoad = &BoundedLoadSettings{AffinityWindow: -time.Second}
+
+	// test
+	p, err := newLoadBalancer(ts.Logger, cfg, nil, tb)
+
+	// verify
+	require.Nil(t, p)
+	require.Equal(t, errInvalidAffinityWindow, err)
+}
+
+func TestNewLoadBalancerInvalidWeights(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		weights  map[string]int
+		expected string
+	}{
+		{
+			name:     "unknown hostname",
+			weights:  map[string]int{"endpoint-2": 200},
+			expected: `weight set for unknown hostname "endpoint-2"`,
+		},
+		{
+			name:     "zero weight",
+			weights:  map[string]int{"endpoint-1": 0},
+			expected: `weight for hostname "endpoint-1" must be positive, got 0`,
+		},
+		{
+			name:     "weight too large",
+			weights:  map[string]int{"endpoint-1": 1_000_000_000},
+			expected: `weight for hostname "endpoint-1" must be at most 1000, got 1000000000`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// prepare
+			ts, tb := getTelemetryAssets(t)
+			cfg := simpleConfig()
+			cfg.Resolver.Static.Weights = tt.weights
+
+			// test
+			p, err := newLoadBalancer(ts.Logger, cfg, nil, tb)
+
+			// verify
+			require.Nil(t, p)
+			require.EqualError(t, err, tt.expected)
+		})
+	}
+}
+
+func TestWeightedBackends(t *testing.T) {
+	// prepare
+	ts, tb := getTelemetryAssets(t)
+	cfg := &Config{
+		Resolver: ResolverSettings{
+			Static: &StaticResolver{
+				Hostnames: []string{"endpoint-1", "endpoint-2"},
+				Weights:   map[string]int{"endpoint-2": 300},
+			},
+		},
+	}
+	componentFactory := func(_ context.Context, _ string) (component.Component, error) {
+		return newNopMockExporter(), nil
+	}
+	p, err := newLoadBalancer(ts.Logger, cfg, componentFactory, tb)
+	require.NotNil(t, p)
+	require.NoError(t, err)
+
+	// test
+	err = p.Start(context.Background(), componenttest.NewNopHost())
+	require.NoError(t, err)
+
+	// verify
+	assert.True(t, p.ring.equal(newWeightedHashRing([]string{"endpoint-1", "endpoint-2"}, map[string]int{"endpoint-2": 300})))
+	assert.InDelta(t, 0.75, p.shares["endpoint-2"], 0.01)
+}
+
+func TestBoundedLoadBackends(t *testing.T) {
+	// prepare
+	ts, tb := getTelemetryAssets(t)
+	cfg := &Config{
+		Resolver: ResolverSettings{
+			Static: &StaticResolver{Hostnames: []string{"endpoint-1", "endpoint-2"}},
+		},
+		BoundedLoad: &BoundedLoadSettings{CapacityFactor: 1},
+	}
+	componentFactory := func(_ context.Context, _ string) (component.Component, error) {
+		return mockComponent{}, nil
+	}
+	p, err := newLoadBalancer(ts.Logger, cfg, componentFactory, tb)
+	require.NotNil(t, p)
+	require.NoError(t, err)
+
+	err = p.Start(context.Background(), componenttest.NewNopHost())
+	require.NoError(t, err)
+
+	// this trace ID will reach the endpoint-1 -- see the consistent hashing tests for more info
+	identifier := []byte{1, 2, 0, 0}
+	exp, endpoint, err := p.exporterAndEndpoint(identifier)
+	require.NoError(t, err)
+	require.Equal(t, "endpoint-1", endpoint)
+
+	// another key reaching the endpoint-1 in the plain ring
+	var other []byte
+	for i := byte(0); other == nil; i++ {
+		candidate := []byte{i, 0, 0, 1}
+		if p.ring.endpointFor(candidate) == "endpoint-1" {
+			other = candidate
+		}
+	}
+
+	// test
+	// with a capacity factor of 1, endpoint-1 can't take more than half of the in-flight requests
+	exp.acquire()
+	_, endpoint, err = p.exporterAndEndpoint(identifier)
+
+	// verify
+	// the key already seen keeps its backend
+	require.NoError(t, err)
+	assert.Equal(t, "endpoint-1", endpoint)
+
+	// test
+	_, endpoint, err = p.exporterAndEndpoint(other)
+
+	// verify
+	// the new key is routed to the next backend with spare capacity
+	require.NoError(t, err)
+	assert.Equal(t, "endpoint-2", endpoint)
+
+	// test
+	exp.release()
+	_, endpoint, err = p.exporterAndEndpoint(other)
+
+	// verify
+	// the new key keeps its backend as well, even though endpoint-1 is within capacity again
+	require.NoError(t, err)
+	assert.Equal(t, "endpoint-2", endpoint)
+}
+
+func TestBoundedLoadAffinityWindow(t *testing.T) {
+	// prepare
+	ts, tb := getTelemetryAssets(t)
+	cfg := &Config{
+		Resolver: ResolverSettings{
+			Static: &StaticResolver{Hostnames: []string{"endpoint-1", "endpoint-2"}},
+		},
+		BoundedLoad: &BoundedLoadSettings{CapacityFactor: 1, AffinityWindow: time.Millisecond},
+	}
+	componentFactory := func(_ context.Context, _ string) (component.Component, error) {
+		return mockComponent{}, nil
+	}
+	p, err := newLoadBalancer(ts.Logger, cfg, componentFactory, tb)
+	require.NotNil(t, p)
+	require.NoError(t, err)
+
+	err = p.Start(context.Background(), componenttest.NewNopHost())
+	require.NoError(t, err)
+
+	identifier := []byte{1, 2, 0, 0}
+	exp, endpoint, err := p.exporterAndEndpoint(identifier)
+	require.NoError(t, err)
+	require.Equal(t, "endpoint-1", endpoint)
+
+	// test
+	// once the affinity window is over, the key is routed based on the load again
+	exp.acquire()
+	defer exp.release()
+	time.Sleep(5 * time.Millisecond)
+	_, endpoint, err = p.exporterAndEndpoint(identifier)
+
+	// verify
+	require.NoError(t, err)
+	assert.Equal(t, "endpoint-2", endpoint)
+}
+
+func TestBoundedLoadRemovedBackend(t *testing.T) {
+	// prepare
+	ts, tb := getTelemetryAssets(t)
+	cfg := &Config{
+		Resolver: ResolverSettings{
+			Static: &StaticResolver{Hostnames: []string{"endpoint-1", "endpoint-2"}},
+		},
+		BoundedLoad: &BoundedLoadSettings{},
+	}
+	componentFactory := func(_ context.Context, _ string) (component.Component, error) {
+		return mockComponent{}, nil
+	}
+	p, err := newLoadBalancer(ts.Logger, cfg, componentFactory, tb)
+	require.NotNil(t, p)
+	require.NoError(t, err)
+
+	err = p.Start(context.Background(), componenttest.NewNopHost())
+	require.NoError(t, err)
+
+	identifier := []byte{1, 2, 0, 0}
+	_, endpoint, err := p.exporterAndEndpoint(identifier)
+	require.NoError(t, err)
+	require.Equal(t, "endpoint-1", endpoint)
+
+	// test
+	p.onBackendChanges([]string{"endpoint-2"})
+	_, endpoint, err = p.exporterAndEndpoint(identifier)
+
+	// verify
+	// the key doesn't stick to a backend removed from the list
+	require.NoError(t, err)
+	assert.Equal(t, "endpoint-2", endpoint)
+}
+
+func TestNewLoadBalancerInvalidGracePeriod(t *testing.T) {
+	// prepare
+	ts, tb := getTelemetryAssets(t)
+	cfg := simpleConfig()
+	cfg.Draining = &DrainingSettings{GracePeriod: -time.Second}
+
+	// test
+	p, err := newLoadBalancer(ts.Logger, cfg, nil, tb)
+
+	// verify
+	require.Nil(t, p)
+	require.Equal(t, errInvalidGracePeriod, err)
+}
+
+func TestNewLoadBalancerDefaultGracePeriod(t *testing.T) {
+	// prepare
+	ts, tb := getTelemetryAssets(t)
+	cfg := simpleConfig()
+	cfg.Draining = &DrainingSettings{}
+
+	// test
+	p, err := newLoadBalancer(ts.Logger, cfg, nil, tb)
+
+	// verify
+	require.NoError(t, err)
+	assert.Equal(t, defaultGracePeriod, p.gracePeriod)
+	assert.NotNil(t, p.routes)
+}
+
+func TestDrainingBackends(t *testing.T) {
+	// prepare
+	tt := componenttest.NewTelemetry()
+	defer func() { require.NoError(t, tt.Shutdown(context.Background())) }()
+	tb, err := metadata.NewTelemetryBuilder(tt.NewTelemetrySettings())
+	require.NoError(t, err)
+
+	cfg := simpleConfig()
+	cfg.Draining = &DrainingSettings{GracePeriod: time.Hour}
+	componentFactory := func(_ context.Context, _ string) (component.Component, error) {
+		return mockComponent{}, nil
+	}
+	p, err := newLoadBalancer(zap.NewNop(), cfg, componentFactory, tb)
+	require.NotNil(t, p)
+	require.NoError(t, err)
+
+	p.onBackendChanges([]string{"endpoint-1", "endpoint-2"})
+
+	// this trace ID will reach the endpoint-2 -- see the consistent hashing tests for more info
+	seen := []byte{128, 128, 0, 0}
+	exp, endpoint, err := p.exporterAndEndpoint(seen)
+	require.NoError(t, err)
+	require.Equal(t, "endpoint-2", endpoint)
+
+	// test
+	p.onBackendChanges([]string{"endpoint-1"})
+
+	// verify
+	// keys already seen keep being sent to the removed backend during the grace period
+	require.Contains(t, p.draining, endpointWithPort("endpoint-2"))
+	drainedExp, endpoint, err := p.exporterAndEndpoint(seen)
+	require.NoError(t, err)
+	assert.Equal(t, "endpoint-2", endpoint)
+	assert.Same(t, exp, drainedExp)
+
+	// new keys are routed using the new list of backends
+	_, endpoint, err = p.exporterAndEndpoint([]byte("get-recommendations-1"))
+	require.NoError(t, err)
+	assert.Equal(t, "endpoint-1", endpoint)
+
+	metadatatest.AssertEqualLoadbalancerKeysDrained(t, tt,
+		[]metricdata.DataPoint[int64]{{Value: 1}},
+		metricdatatest.IgnoreTimestamp())
+
+	// test
+	// once the grace period is over, keys already seen are migrated to the new backend
+	p.drainUntil = time.Now()
+	_, endpoint, err = p.exporterAndEndpoint(seen)
+
+	// verify
+	require.NoError(t, err)
+	assert.Equal(t, "endpoint-1", endpoint)
+	metadatatest.AssertEqualLoadbalancerKeysMigrated(t, tt,
+		[]metricdata.DataPoint[int64]{{Value: 1}},
+		metricdatatest.IgnoreTimestamp())
+
+	require.NoError(t, p.Shutdown(context.Background()))
+	assert.Empty(t, p.draining)
+}
+
+func TestDrainingBackendShutdownAfterGracePeriod(t *testing.T) {
+	// prepare
+	ts, tb := getTelemetryAssets(t)
+	cfg := simpleConfig()
+	cfg.Draining = &DrainingSettings{GracePeriod: 10 * time.Millisecond}
+	componentFactory := func(_ context.Context, _ string) (component.Component, error) {
+		return mockComponent{}, nil
+	}
+	p, err := newLoadBalancer(ts.Logger, cfg, componentFactory, tb)
+	require.NotNil(t, p)
+	require.NoError(t, err)
+
+	p.onBackendChanges([]string{"endpoint-1", "endpoint-2"})
+
+	// test
+	p.onBackendChanges([]string{"endpoint-1"})
+
+	// verify
+	assert.Eventually(t, func() bool {
+		p.updateLock.RLock()
+		defer p.updateLock.RUnlock()
+		return len(p.draining) == 0
+	}, time.Second, 5*time.Millisecond)
+	assert.Len(t, p.exporters, 1)
+}
+
+func TestDrainingBackendReturns(t *testing.T) {
+	// prepare
+	ts, tb := getTelemetryAssets(t)
+	cfg := simpleConfig()
+	cfg.Draining = &DrainingSettings{GracePeriod: time.Hour}
+	componentFactory := func(_ context.Context, _ string) (component.Component, error) {
+		return mockComponent{}, nil
+	}
+	p, err := newLoadBalancer(ts.Logger, cfg, componentFactory, tb)
+	require.NotNil(t, p)
+	require.NoError(t, err)
+
+	p.onBackendChanges([]string{"endpoint-1", "endpoint-2"})
+	exp := p.exporters[endpointWithPort("endpoint-2")]
+	p.onBackendChanges([]string{"endpoint-1"})
+	require.Contains(t, p.draining, endpointWithPort("endpoint-2"))
+
+	// test
+	p.onBackendChanges([]string{"endpoint-1", "endpoint-2"})
+
+	// verify
+	assert.Empty(t, p.draining)
+	assert.Same(t, exp, p.exporters[endpointWithPort("endpoint-2")])
+}
+
 func newNopMockExporter() *wrappedExporter {
 	return newWrappedExporter(mockComponent{}, "mock")
 }
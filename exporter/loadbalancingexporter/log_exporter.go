@@ -27,6 +27,7 @@ var _ exporter.Logs = (*logExporterImp)(nil)
 
 type logExporterImp struct {
 	loadBalancer *loadBalancer
+	router       *logsRouter
 
 	logger     *zap.Logger
 	started    bool
@@ -53,11 +54,20 @@ func newLogsExporter(params exporter.Settings, cfg component.Config) (*logExport
 		return nil, err
 	}
 
-	return &logExporterImp{
+	logExporter := logExporterImp{
 		loadBalancer: lb,
 		telemetry:    telemetry,
 		logger:       params.Logger,
-	}, nil
+	}
+
+	// logs are routed by trace ID, unless the routing key is computed by an OTTL expression
+	if cfg.(*Config).RoutingKey == ottlRoutingStr {
+		logExporter.router, err = newLogsRouter(cfg.(*Config), params.TelemetrySettings)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &logExporter, nil
 }
 
 func (e *logExporterImp) Capabilities() consumer.Capabilities {
@@ -81,6 +91,17 @@ func (e *logExporterImp) Shutdown(ctx context.Context) error {
 
 func (e *logExporterImp) ConsumeLogs(ctx context.Context, ld plog.Logs) error {
 	var errs error
+	if e.router != nil {
+		batches, err := e.router.split(ctx, ld)
+		if err != nil {
+			return err
+		}
+		for key, batch := range batches {
+			errs = multierr.Append(errs, e.consumeLogWithKey(ctx, []byte(key), batch))
+		}
+		return errs
+	}
+
 	batches := batchpersignal.SplitLogs(ld)
 	for _, batch := range batches {
 		errs = multierr.Append(errs, e.consumeLog(ctx, batch))
@@ -99,13 +120,17 @@ func (e *logExporterImp) consumeLog(ctx context.Context, ld plog.Logs) error {
 		balancingKey = random()
 	}
 
-	le, _, err := e.loadBalancer.exporterAndEndpoint(balancingKey[:])
+	return e.consumeLogWithKey(ctx, balancingKey[:], ld)
+}
+
+func (e *logExporterImp) consumeLogWithKey(ctx context.Context, balancingKey []byte, ld plog.Logs) error {
+	le, _, err := e.loadBalancer.exporterAndEndpoint(balancingKey)
 	if err != nil {
 		return err
 	}
 
-	le.consumeWG.Add(1)
-	defer le.consumeWG.Done()
+	le.acquire()
+	defer le.release()
 
 	start := time.Now()
 	err = le.ConsumeLogs(ctx, ld)
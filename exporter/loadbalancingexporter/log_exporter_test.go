@@ -233,6 +233,59 @@ func TestLogBatchWithTwoTraces(t *testing.T) {
 	assert.Len(t, sink.AllLogs(), 2)
 }
 
+func TestLogBatchWithOTTLRoutingKey(t *testing.T) {
+	ts, tb := getTelemetryAssets(t)
+	sink := new(consumertest.LogsSink)
+	componentFactory := func(_ context.Context, _ string) (component.Component, error) {
+		return newMockLogsExporter(sink.ConsumeLogs), nil
+	}
+
+	cfg := simpleConfig()
+	cfg.RoutingKey = ottlRoutingStr
+	cfg.RoutingExpression = `attributes["tenant"]`
+
+	lb, err := newLoadBalancer(ts.Logger, cfg, componentFactory, tb)
+	require.NotNil(t, lb)
+	require.NoError(t, err)
+
+	p, err := newLogsExporter(ts, cfg)
+	require.NotNil(t, p)
+	require.NoError(t, err)
+
+	// pre-load an exporter here, so that we don't use the actual OTLP exporter
+	lb.addMissingExporters(context.Background(), []string{"endpoint-1"})
+	p.loadBalancer = lb
+
+	err = p.Start(context.Background(), componenttest.NewNopHost())
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, p.Shutdown(context.Background()))
+	}()
+
+	batch := plog.NewLogs()
+	logs := batch.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	for _, tenant := range []string{"tenant-1", "tenant-2", "tenant-1"} {
+		logs.AppendEmpty().Attributes().PutStr("tenant", tenant)
+	}
+
+	// test
+	err = p.ConsumeLogs(context.Background(), batch)
+
+	// verify
+	assert.NoError(t, err)
+	assert.Len(t, sink.AllLogs(), 2)
+	assert.Equal(t, 3, sink.LogRecordCount())
+}
+
+func TestNewLogsExporterInvalidRoutingExpression(t *testing.T) {
+	cfg := simpleConfig()
+	cfg.RoutingKey = ottlRoutingStr
+	cfg.RoutingExpression = `attributes["tenant"`
+
+	_, err := newLogsExporter(exportertest.NewNopSettings(metadata.Type), cfg)
+	require.ErrorContains(t, err, "invalid routing_expression")
+}
+
 func TestNoLogsInBatch(t *testing.T) {
 	for _, tt := range []struct {
 		desc  string
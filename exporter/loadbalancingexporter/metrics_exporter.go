@@ -30,6 +30,7 @@ var _ exporter.Metrics = (*metricExporterImp)(nil)
 type metricExporterImp struct {
 	loadBalancer *loadBalancer
 	routingKey   routingKey
+	router       *metricsRouter
 
 	logger     *zap.Logger
 	stopped    bool
@@ -72,6 +73,12 @@ func newMetricsExporter(params exporter.Settings, cfg component.Config) (*metric
 		metricExporter.routingKey = metricNameRouting
 	case streamIDRoutingStr:
 		metricExporter.routingKey = streamIDRouting
+	case ottlRoutingStr:
+		metricExporter.routingKey = ottlRouting
+		metricExporter.router, err = newMetricsRouter(cfg.(*Config), params.TelemetrySettings)
+		if err != nil {
+			return nil, err
+		}
 	default:
 		return nil, fmt.Errorf("unsupported routing_key: %q", cfg.(*Config).RoutingKey)
 	}
@@ -109,6 +116,12 @@ func (e *metricExporterImp) ConsumeMetrics(ctx context.Context, md pmetric.Metri
 		batches = splitMetricsByMetricName(md)
 	case streamIDRouting:
 		batches = splitMetricsByStreamID(md)
+	case ottlRouting:
+		var err error
+		batches, err = e.router.split(ctx, md)
+		if err != nil {
+			return err
+		}
 	}
 
 	// Now assign each batch to an exporter, and merge as we go
@@ -123,7 +136,7 @@ func (e *metricExporterImp) ConsumeMetrics(ctx context.Context, md pmetric.Metri
 
 		expMetrics, ok := metricsByExporter[exp]
 		if !ok {
-			exp.consumeWG.Add(1)
+			exp.acquire()
 			expMetrics = pmetric.NewMetrics()
 			metricsByExporter[exp] = expMetrics
 			exporterEndpoints[exp] = endpoint
@@ -138,7 +151,7 @@ func (e *metricExporterImp) ConsumeMetrics(ctx context.Context, md pmetric.Metri
 		err := exp.ConsumeMetrics(ctx, mds)
 		duration := time.Since(start)
 
-		exp.consumeWG.Done()
+		exp.release()
 		errs = multierr.Append(errs, err)
 		e.telemetry.LoadbalancerBackendLatency.Record(ctx, duration.Milliseconds(), metric.WithAttributeSet(exp.endpointAttr))
 		if err == nil {
@@ -88,6 +88,15 @@ func TestNewMetricsExporter(t *testing.T) {
 			},
 			errNoResolver,
 		},
+		{
+			"ottl without expression",
+			func() *Config {
+				cfg := serviceBasedRoutingConfig()
+				cfg.RoutingKey = ottlRoutingStr
+				return cfg
+			}(),
+			errMissingRoutingExpression,
+		},
 	} {
 		t.Run(tt.desc, func(t *testing.T) {
 			// test
@@ -0,0 +1,417 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package loadbalancingexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/exp/metrics"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottldatapoint"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottllog"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlresource"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlspan"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+)
+
+const (
+	resourceContextStr  = "resource"
+	spanContextStr      = "span"
+	logContextStr       = "log"
+	datapointContextStr = "datapoint"
+)
+
+var errMissingRoutingExpression = errors.New("routing_expression must be set when routing_key is \"ottl\"")
+
+// tracesRouter splits traces by the routing key computed by an OTTL value expression, evaluated either for each
+// resource or for each span.
+type tracesRouter struct {
+	resourceExpr *ottl.ValueExpression[ottlresource.TransformContext]
+	spanExpr     *ottl.ValueExpression[ottlspan.TransformContext]
+}
+
+func newTracesRouter(cfg *Config, settings component.TelemetrySettings) (*tracesRouter, error) {
+	if cfg.RoutingExpression == "" {
+		return nil, errMissingRoutingExpression
+	}
+
+	switch cfg.RoutingContext {
+	case resourceContextStr:
+		expr, err := parseResourceExpression(cfg.RoutingExpression, settings)
+		if err != nil {
+			return nil, err
+		}
+		return &tracesRouter{resourceExpr: expr}, nil
+	case spanContextStr, "":
+		parser, err := ottlspan.NewParser(ottlfuncs.StandardConverters[ottlspan.TransformContext](), settings)
+		if err != nil {
+			return nil, err
+		}
+		expr, err := parser.ParseValueExpression(cfg.RoutingExpression)
+		if err != nil {
+			return nil, fmt.Errorf("invalid routing_expression: %w", err)
+		}
+		return &tracesRouter{spanExpr: expr}, nil
+	default:
+		return nil, fmt.Errorf("unsupported routing_context for traces: %q", cfg.RoutingContext)
+	}
+}
+
+// split groups the traces by routing key, keeping the resource and scope of each span.
+func (r *tracesRouter) split(ctx context.Context, td ptrace.Traces) (map[string]ptrace.Traces, error) {
+	results := map[string]ptrace.Traces{}
+
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		rs := td.ResourceSpans().At(i)
+
+		if r.resourceExpr != nil {
+			key, err := evalRoutingKey(ctx, r.resourceExpr, ottlresource.NewTransformContext(rs.Resource(), rs))
+			if err != nil {
+				return nil, err
+			}
+			existing, ok := results[key]
+			if !ok {
+				existing = ptrace.NewTraces()
+				results[key] = existing
+			}
+			rs.CopyTo(existing.ResourceSpans().AppendEmpty())
+			continue
+		}
+
+		// the spans of a resource and scope are grouped under a single copy of the resource and scope per key
+		rsByKey := map[string]ptrace.ResourceSpans{}
+		for j := 0; j < rs.ScopeSpans().Len(); j++ {
+			ss := rs.ScopeSpans().At(j)
+			ssByKey := map[string]ptrace.ScopeSpans{}
+
+			for k := 0; k < ss.Spans().Len(); k++ {
+				span := ss.Spans().At(k)
+
+				key, err := evalRoutingKey(ctx, r.spanExpr, ottlspan.NewTransformContext(span, ss.Scope(), rs.Resource(), ss, rs))
+				if err != nil {
+					return nil, err
+				}
+
+				ssClone, ok := ssByKey[key]
+				if !ok {
+					rsClone, ok := rsByKey[key]
+					if !ok {
+						existing, ok := results[key]
+						if !ok {
+							existing = ptrace.NewTraces()
+							results[key] = existing
+						}
+						rsClone = existing.ResourceSpans().AppendEmpty()
+						rs.Resource().CopyTo(rsClone.Resource())
+						rsClone.SetSchemaUrl(rs.SchemaUrl())
+						rsByKey[key] = rsClone
+					}
+					ssClone = rsClone.ScopeSpans().AppendEmpty()
+					ss.Scope().CopyTo(ssClone.Scope())
+					ssClone.SetSchemaUrl(ss.SchemaUrl())
+					ssByKey[key] = ssClone
+				}
+				span.CopyTo(ssClone.Spans().AppendEmpty())
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// logsRouter splits logs by the routing key computed by an OTTL value expression, evaluated either for each
+// resource or for each log record.
+type logsRouter struct {
+	resourceExpr *ottl.ValueExpression[ottlresource.TransformContext]
+	logExpr      *ottl.ValueExpression[ottllog.TransformContext]
+}
+
+func newLogsRouter(cfg *Config, settings component.TelemetrySettings) (*logsRouter, error) {
+	if cfg.RoutingExpression == "" {
+		return nil, errMissingRoutingExpression
+	}
+
+	switch cfg.RoutingContext {
+	case resourceContextStr:
+		expr, err := parseResourceExpression(cfg.RoutingExpression, settings)
+		if err != nil {
+			return nil, err
+		}
+		return &logsRouter{resourceExpr: expr}, nil
+	case logContextStr, "":
+		parser, err := ottllog.NewParser(ottlfuncs.StandardConverters[ottllog.TransformContext](), settings)
+		if err != nil {
+			return nil, err
+		}
+		expr, err := parser.ParseValueExpression(cfg.RoutingExpression)
+		if err != nil {
+			return nil, fmt.Errorf("invalid routing_expression: %w", err)
+		}
+		return &logsRouter{logExpr: expr}, nil
+	default:
+		return nil, fmt.Errorf("unsupported routing_context for logs: %q", cfg.RoutingContext)
+	}
+}
+
+// split groups the logs by routing key, keeping the resource and scope of each log record.
+func (r *logsRouter) split(ctx context.Context, ld plog.Logs) (map[string]plog.Logs, error) {
+	results := map[string]plog.Logs{}
+
+	for i := 0; i < ld.ResourceLogs().Len(); i++ {
+		rl := ld.ResourceLogs().At(i)
+
+		if r.resourceExpr != nil {
+			key, err := evalRoutingKey(ctx, r.resourceExpr, ottlresource.NewTransformContext(rl.Resource(), rl))
+			if err != nil {
+				return nil, err
+			}
+			existing, ok := results[key]
+			if !ok {
+				existing = plog.NewLogs()
+				results[key] = existing
+			}
+			rl.CopyTo(existing.ResourceLogs().AppendEmpty())
+			continue
+		}
+
+		// the log records of a resource and scope are grouped under a single copy of the resource and scope per key
+		rlByKey := map[string]plog.ResourceLogs{}
+		for j := 0; j < rl.ScopeLogs().Len(); j++ {
+			sl := rl.ScopeLogs().At(j)
+			slByKey := map[string]plog.ScopeLogs{}
+
+			for k := 0; k < sl.LogRecords().Len(); k++ {
+				lr := sl.LogRecords().At(k)
+
+				key, err := evalRoutingKey(ctx, r.logExpr, ottllog.NewTransformContext(lr, sl.Scope(), rl.Resource(), sl, rl))
+				if err != nil {
+					return nil, err
+				}
+
+				slClone, ok := slByKey[key]
+				if !ok {
+					rlClone, ok := rlByKey[key]
+					if !ok {
+						existing, ok := results[key]
+						if !ok {
+							existing = plog.NewLogs()
+							results[key] = existing
+						}
+						rlClone = existing.ResourceLogs().AppendEmpty()
+						rl.Resource().CopyTo(rlClone.Resource())
+						rlClone.SetSchemaUrl(rl.SchemaUrl())
+						rlByKey[key] = rlClone
+					}
+					slClone = rlClone.ScopeLogs().AppendEmpty()
+					sl.Scope().CopyTo(slClone.Scope())
+					slClone.SetSchemaUrl(sl.SchemaUrl())
+					slByKey[key] = slClone
+				}
+				lr.CopyTo(slClone.LogRecords().AppendEmpty())
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// metricsRouter splits metrics by the routing key computed by an OTTL value expression, evaluated either for each
+// resource or for each datapoint.
+type metricsRouter struct {
+	resourceExpr  *ottl.ValueExpression[ottlresource.TransformContext]
+	datapointExpr *ottl.ValueExpression[ottldatapoint.TransformContext]
+}
+
+func newMetricsRouter(cfg *Config, settings component.TelemetrySettings) (*metricsRouter, error) {
+	if cfg.RoutingExpression == "" {
+		return nil, errMissingRoutingExpression
+	}
+
+	switch cfg.RoutingContext {
+	case resourceContextStr:
+		expr, err := parseResourceExpression(cfg.RoutingExpression, settings)
+		if err != nil {
+			return nil, err
+		}
+		return &metricsRouter{resourceExpr: expr}, nil
+	case datapointContextStr, "":
+		parser, err := ottldatapoint.NewParser(ottlfuncs.StandardConverters[ottldatapoint.TransformContext](), settings)
+		if err != nil {
+			return nil, err
+		}
+		expr, err := parser.ParseValueExpression(cfg.RoutingExpression)
+		if err != nil {
+			return nil, fmt.Errorf("invalid routing_expression: %w", err)
+		}
+		return &metricsRouter{datapointExpr: expr}, nil
+	default:
+		return nil, fmt.Errorf("unsupported routing_context for metrics: %q", cfg.RoutingContext)
+	}
+}
+
+// split groups the metrics by routing key, keeping the resource, scope and metric of each datapoint.
+func (r *metricsRouter) split(ctx context.Context, md pmetric.Metrics) (map[string]pmetric.Metrics, error) {
+	results := map[string]pmetric.Metrics{}
+
+	for i := 0; i < md.ResourceMetrics().Len(); i++ {
+		rm := md.ResourceMetrics().At(i)
+
+		if r.resourceExpr != nil {
+			key, err := evalRoutingKey(ctx, r.resourceExpr, ottlresource.NewTransformContext(rm.Resource(), rm))
+			if err != nil {
+				return nil, err
+			}
+			existing, ok := results[key]
+			if !ok {
+				existing = pmetric.NewMetrics()
+				results[key] = existing
+			}
+			rm.CopyTo(existing.ResourceMetrics().AppendEmpty())
+			continue
+		}
+
+		for j := 0; j < rm.ScopeMetrics().Len(); j++ {
+			sm := rm.ScopeMetrics().At(j)
+
+			for k := 0; k < sm.Metrics().Len(); k++ {
+				m := sm.Metrics().At(k)
+
+				for l := 0; l < dataPointsLen(m); l++ {
+					tCtx := ottldatapoint.NewTransformContext(dataPointAt(m, l), m, sm.Metrics(), sm.Scope(), rm.Resource(), sm, rm)
+					key, err := evalRoutingKey(ctx, r.datapointExpr, tCtx)
+					if err != nil {
+						return nil, err
+					}
+
+					newMD := cloneMetricWithDataPoint(rm, sm, m, l)
+					if existing, ok := results[key]; ok {
+						metrics.Merge(existing, newMD)
+					} else {
+						results[key] = newMD
+					}
+				}
+			}
+		}
+	}
+
+	return results, nil
+}
+
+func parseResourceExpression(expression string, settings component.TelemetrySettings) (*ottl.ValueExpression[ottlresource.TransformContext], error) {
+	parser, err := ottlresource.NewParser(ottlfuncs.StandardConverters[ottlresource.TransformContext](), settings)
+	if err != nil {
+		return nil, err
+	}
+	expr, err := parser.ParseValueExpression(expression)
+	if err != nil {
+		return nil, fmt.Errorf("invalid routing_expression: %w", err)
+	}
+	return expr, nil
+}
+
+func evalRoutingKey[K any](ctx context.Context, expr *ottl.ValueExpression[K], tCtx K) (string, error) {
+	val, err := expr.Eval(ctx, tCtx)
+	if err != nil {
+		return "", fmt.Errorf("failed to evaluate routing_expression: %w", err)
+	}
+	return routingKeyFromValue(val), nil
+}
+
+// routingKeyFromValue converts the result of a routing expression to a routing key.
+func routingKeyFromValue(val any) string {
+	switch v := val.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case pcommon.Value:
+		return v.AsString()
+	case pcommon.Map:
+		m := pcommon.NewValueMap()
+		v.CopyTo(m.Map())
+		return m.AsString()
+	case pcommon.Slice:
+		s := pcommon.NewValueSlice()
+		v.CopyTo(s.Slice())
+		return s.AsString()
+	case fmt.Stringer:
+		return v.String()
+	}
+
+	raw := pcommon.NewValueEmpty()
+	if err := raw.FromRaw(val); err != nil {
+		return fmt.Sprint(val)
+	}
+	return raw.AsString()
+}
+
+func dataPointsLen(m pmetric.Metric) int {
+	switch m.Type() {
+	case pmetric.MetricTypeGauge:
+		return m.Gauge().DataPoints().Len()
+	case pmetric.MetricTypeSum:
+		return m.Sum().DataPoints().Len()
+	case pmetric.MetricTypeHistogram:
+		return m.Histogram().DataPoints().Len()
+	case pmetric.MetricTypeExponentialHistogram:
+		return m.ExponentialHistogram().DataPoints().Len()
+	case pmetric.MetricTypeSummary:
+		return m.Summary().DataPoints().Len()
+	}
+	return 0
+}
+
+func dataPointAt(m pmetric.Metric, i int) any {
+	switch m.Type() {
+	case pmetric.MetricTypeGauge:
+		return m.Gauge().DataPoints().At(i)
+	case pmetric.MetricTypeSum:
+		return m.Sum().DataPoints().At(i)
+	case pmetric.MetricTypeHistogram:
+		return m.Histogram().DataPoints().At(i)
+	case pmetric.MetricTypeExponentialHistogram:
+		return m.ExponentialHistogram().DataPoints().At(i)
+	case pmetric.MetricTypeSummary:
+		return m.Summary().DataPoints().At(i)
+	}
+	return nil
+}
+
+// cloneMetricWithDataPoint copies the resource, scope and metric of the i-th datapoint of the metric, with that
+// datapoint only.
+func cloneMetricWithDataPoint(rm pmetric.ResourceMetrics, sm pmetric.ScopeMetrics, m pmetric.Metric, i int) pmetric.Metrics {
+	md, mClone := cloneMetricWithoutType(rm, sm, m)
+
+	switch m.Type() {
+	case pmetric.MetricTypeGauge:
+		m.Gauge().DataPoints().At(i).CopyTo(mClone.SetEmptyGauge().DataPoints().AppendEmpty())
+	case pmetric.MetricTypeSum:
+		sumClone := mClone.SetEmptySum()
+		sumClone.SetIsMonotonic(m.Sum().IsMonotonic())
+		sumClone.SetAggregationTemporality(m.Sum().AggregationTemporality())
+		m.Sum().DataPoints().At(i).CopyTo(sumClone.DataPoints().AppendEmpty())
+	case pmetric.MetricTypeHistogram:
+		histogramClone := mClone.SetEmptyHistogram()
+		histogramClone.SetAggregationTemporality(m.Histogram().AggregationTemporality())
+		m.Histogram().DataPoints().At(i).CopyTo(histogramClone.DataPoints().AppendEmpty())
+	case pmetric.MetricTypeExponentialHistogram:
+		expHistogramClone := mClone.SetEmptyExponentialHistogram()
+		expHistogramClone.SetAggregationTemporality(m.ExponentialHistogram().AggregationTemporality())
+		m.ExponentialHistogram().DataPoints().At(i).CopyTo(expHistogramClone.DataPoints().AppendEmpty())
+	case pmetric.MetricTypeSummary:
+		m.Summary().DataPoints().At(i).CopyTo(mClone.SetEmptySummary().DataPoints().AppendEmpty())
+	}
+
+	return md
+}
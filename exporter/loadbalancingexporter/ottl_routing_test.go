@@ -0,0 +1,303 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package loadbalancingexporter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+func TestNewRoutersErrors(t *testing.T) {
+	settings := componenttest.NewNopTelemetrySettings()
+	for _, tt := range []struct {
+		desc string
+		cfg  *Config
+		err  string
+	}{
+		{
+			desc: "missing expression",
+			cfg:  &Config{RoutingKey: ottlRoutingStr},
+			err:  `routing_expression must be set when routing_key is "ottl"`,
+		},
+		{
+			desc: "invalid expression",
+			cfg:  &Config{RoutingKey: ottlRoutingStr, RoutingExpression: `attributes["tenant"`},
+			err:  "invalid routing_expression",
+		},
+		{
+			desc: "unsupported context",
+			cfg:  &Config{RoutingKey: ottlRoutingStr, RoutingExpression: `attributes["tenant"]`, RoutingContext: "scope"},
+			err:  "unsupported routing_context",
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			_, err := newTracesRouter(tt.cfg, settings)
+			assert.ErrorContains(t, err, tt.err)
+			_, err = newLogsRouter(tt.cfg, settings)
+			assert.ErrorContains(t, err, tt.err)
+			_, err = newMetricsRouter(tt.cfg, settings)
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestRoutersRejectOtherSignalContexts(t *testing.T) {
+	settings := componenttest.NewNopTelemetrySettings()
+	cfg := func(routingContext string) *Config {
+		return &Config{RoutingKey: ottlRoutingStr, RoutingExpression: `attributes["tenant"]`, RoutingContext: routingContext}
+	}
+
+	_, err := newTracesRouter(cfg(logContextStr), settings)
+	assert.EqualError(t, err, `unsupported routing_context for traces: "log"`)
+	_, err = newLogsRouter(cfg(datapointContextStr), settings)
+	assert.EqualError(t, err, `unsupported routing_context for logs: "datapoint"`)
+	_, err = newMetricsRouter(cfg(spanContextStr), settings)
+	assert.EqualError(t, err, `unsupported routing_context for metrics: "span"`)
+}
+
+func TestTracesRouterSplitBySpan(t *testing.T) {
+	router, err := newTracesRouter(&Config{RoutingExpression: `attributes["tenant"]`}, componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+
+	td := ptrace.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("service.name", "svc")
+	ss := rs.ScopeSpans().AppendEmpty()
+	ss.Scope().SetName("scope")
+	for _, tenant := range []string{"a", "b", "a"} {
+		span := ss.Spans().AppendEmpty()
+		span.Attributes().PutStr("tenant", tenant)
+	}
+	ss.Spans().AppendEmpty()
+
+	batches, err := router.split(context.Background(), td)
+	require.NoError(t, err)
+	require.Len(t, batches, 3)
+	assert.Equal(t, 2, batches["a"].SpanCount())
+	assert.Equal(t, 1, batches["b"].SpanCount())
+	assert.Equal(t, 1, batches[""].SpanCount())
+
+	for _, batch := range batches {
+		// the spans of a key share a single copy of their resource and scope
+		require.Equal(t, 1, batch.ResourceSpans().Len())
+		require.Equal(t, 1, batch.ResourceSpans().At(0).ScopeSpans().Len())
+		rsClone := batch.ResourceSpans().At(0)
+		svc, ok := rsClone.Resource().Attributes().Get("service.name")
+		require.True(t, ok)
+		assert.Equal(t, "svc", svc.Str())
+		assert.Equal(t, "scope", rsClone.ScopeSpans().At(0).Scope().Name())
+	}
+}
+
+func TestTracesRouterSplitByResource(t *testing.T) {
+	router, err := newTracesRouter(&Config{
+		RoutingExpression: `attributes["tenant"]`,
+		RoutingContext:    resourceContextStr,
+	}, componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+
+	td := ptrace.NewTraces()
+	for _, tenant := range []string{"a", "b", "a"} {
+		rs := td.ResourceSpans().AppendEmpty()
+		rs.Resource().Attributes().PutStr("tenant", tenant)
+		rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	}
+
+	batches, err := router.split(context.Background(), td)
+	require.NoError(t, err)
+	require.Len(t, batches, 2)
+	assert.Equal(t, 2, batches["a"].ResourceSpans().Len())
+	assert.Equal(t, 1, batches["b"].ResourceSpans().Len())
+}
+
+func TestLogsRouterSplitByLog(t *testing.T) {
+	router, err := newLogsRouter(&Config{
+		RoutingExpression: `Concat([resource.attributes["service.name"], attributes["tenant"]], "/")`,
+	}, componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+
+	ld := plog.NewLogs()
+	for _, svc := range []string{"svc-1", "svc-2"} {
+		rl := ld.ResourceLogs().AppendEmpty()
+		rl.Resource().Attributes().PutStr("service.name", svc)
+		sl := rl.ScopeLogs().AppendEmpty()
+		for _, tenant := range []string{"a", "b", "a"} {
+			sl.LogRecords().AppendEmpty().Attributes().PutStr("tenant", tenant)
+		}
+	}
+
+	batches, err := router.split(context.Background(), ld)
+	require.NoError(t, err)
+	require.Len(t, batches, 4)
+	assert.Equal(t, 2, batches["svc-1/a"].LogRecordCount())
+	assert.Equal(t, 1, batches["svc-1/b"].LogRecordCount())
+	assert.Equal(t, 2, batches["svc-2/a"].LogRecordCount())
+	assert.Equal(t, 1, batches["svc-2/b"].LogRecordCount())
+
+	// the log records of a key share a single copy of their resource and scope
+	for _, batch := range batches {
+		require.Equal(t, 1, batch.ResourceLogs().Len())
+		require.Equal(t, 1, batch.ResourceLogs().At(0).ScopeLogs().Len())
+	}
+}
+
+func TestLogsRouterSplitByLogGroupsScopes(t *testing.T) {
+	router, err := newLogsRouter(&Config{RoutingExpression: `attributes["tenant"]`}, componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+
+	ld := plog.NewLogs()
+	for _, svc := range []string{"svc-1", "svc-2"} {
+		rl := ld.ResourceLogs().AppendEmpty()
+		rl.Resource().Attributes().PutStr("service.name", svc)
+		for _, scope := range []string{"scope-1", "scope-2"} {
+			sl := rl.ScopeLogs().AppendEmpty()
+			sl.Scope().SetName(scope)
+			for _, tenant := range []string{"a", "b", "a"} {
+				sl.LogRecords().AppendEmpty().Attributes().PutStr("tenant", tenant)
+			}
+		}
+	}
+
+	batches, err := router.split(context.Background(), ld)
+	require.NoError(t, err)
+	require.Len(t, batches, 2)
+
+	batch := batches["a"]
+	assert.Equal(t, 8, batch.LogRecordCount())
+	require.Equal(t, 2, batch.ResourceLogs().Len())
+	for i, svc := range []string{"svc-1", "svc-2"} {
+		rl := batch.ResourceLogs().At(i)
+		name, _ := rl.Resource().Attributes().Get("service.name")
+		assert.Equal(t, svc, name.Str())
+		require.Equal(t, 2, rl.ScopeLogs().Len())
+		for j, scope := range []string{"scope-1", "scope-2"} {
+			assert.Equal(t, scope, rl.ScopeLogs().At(j).Scope().Name())
+			assert.Equal(t, 2, rl.ScopeLogs().At(j).LogRecords().Len())
+		}
+	}
+	assert.Equal(t, 4, batches["b"].LogRecordCount())
+}
+
+func TestLogsRouterSplitByResource(t *testing.T) {
+	router, err := newLogsRouter(&Config{
+		RoutingExpression: `attributes["tenant"]`,
+		RoutingContext:    resourceContextStr,
+	}, componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+
+	ld := plog.NewLogs()
+	for _, tenant := range []string{"a", "b"} {
+		rl := ld.ResourceLogs().AppendEmpty()
+		rl.Resource().Attributes().PutStr("tenant", tenant)
+		rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	}
+
+	batches, err := router.split(context.Background(), ld)
+	require.NoError(t, err)
+	require.Len(t, batches, 2)
+	assert.Equal(t, 1, batches["a"].LogRecordCount())
+	assert.Equal(t, 1, batches["b"].LogRecordCount())
+}
+
+func TestMetricsRouterSplitByDataPoint(t *testing.T) {
+	router, err := newMetricsRouter(&Config{
+		RoutingExpression: `attributes["tenant"]`,
+	}, componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+
+	md := pmetric.NewMetrics()
+	sm := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty()
+
+	gauge := sm.Metrics().AppendEmpty()
+	gauge.SetName("gauge")
+	gauge.SetEmptyGauge()
+	for _, tenant := range []string{"a", "b"} {
+		dp := gauge.Gauge().DataPoints().AppendEmpty()
+		dp.Attributes().PutStr("tenant", tenant)
+	}
+
+	sum := sm.Metrics().AppendEmpty()
+	sum.SetName("sum")
+	sum.SetEmptySum().SetIsMonotonic(true)
+	sum.Sum().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	for _, tenant := range []string{"a", "a"} {
+		dp := sum.Sum().DataPoints().AppendEmpty()
+		dp.Attributes().PutStr("tenant", tenant)
+	}
+
+	histogram := sm.Metrics().AppendEmpty()
+	histogram.SetName("histogram")
+	histogram.SetEmptyHistogram().DataPoints().AppendEmpty().Attributes().PutStr("tenant", "b")
+
+	batches, err := router.split(context.Background(), md)
+	require.NoError(t, err)
+	require.Len(t, batches, 2)
+	assert.Equal(t, 3, batches["a"].DataPointCount())
+	assert.Equal(t, 2, batches["b"].DataPointCount())
+
+	metricsA := batches["a"].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	require.Equal(t, 2, metricsA.Len())
+	assert.Equal(t, "gauge", metricsA.At(0).Name())
+	assert.Equal(t, "sum", metricsA.At(1).Name())
+	assert.True(t, metricsA.At(1).Sum().IsMonotonic())
+	assert.Equal(t, pmetric.AggregationTemporalityCumulative, metricsA.At(1).Sum().AggregationTemporality())
+	assert.Equal(t, 2, metricsA.At(1).Sum().DataPoints().Len())
+}
+
+func TestMetricsRouterSplitByResource(t *testing.T) {
+	router, err := newMetricsRouter(&Config{
+		RoutingExpression: `attributes["tenant"]`,
+		RoutingContext:    resourceContextStr,
+	}, componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+
+	md := pmetric.NewMetrics()
+	for _, tenant := range []string{"a", "a", "b"} {
+		rm := md.ResourceMetrics().AppendEmpty()
+		rm.Resource().Attributes().PutStr("tenant", tenant)
+		rm.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty().SetEmptyGauge().DataPoints().AppendEmpty()
+	}
+
+	batches, err := router.split(context.Background(), md)
+	require.NoError(t, err)
+	require.Len(t, batches, 2)
+	assert.Equal(t, 2, batches["a"].ResourceMetrics().Len())
+	assert.Equal(t, 1, batches["b"].ResourceMetrics().Len())
+}
+
+func TestRoutingKeyFromValue(t *testing.T) {
+	m := pcommon.NewMap()
+	m.PutStr("k", "v")
+	s := pcommon.NewSlice()
+	s.AppendEmpty().SetInt(1)
+
+	for _, tt := range []struct {
+		desc     string
+		val      any
+		expected string
+	}{
+		{"nil", nil, ""},
+		{"string", "tenant-1", "tenant-1"},
+		{"bytes", []byte("tenant-1"), "tenant-1"},
+		{"int", int64(42), "42"},
+		{"float", 1.5, "1.5"},
+		{"bool", true, "true"},
+		{"value", pcommon.NewValueStr("tenant-1"), "tenant-1"},
+		{"map", m, `{"k":"v"}`},
+		{"slice", s, "[1]"},
+		{"trace id", pcommon.TraceID([16]byte{1}), "01000000000000000000000000000000"},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			assert.Equal(t, tt.expected, routingKeyFromValue(tt.val))
+		})
+	}
+}
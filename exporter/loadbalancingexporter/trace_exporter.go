@@ -37,6 +37,7 @@ type traceExporterImp struct {
 	loadBalancer *loadBalancer
 	routingKey   routingKey
 	routingAttrs []string
+	router       *tracesRouter
 
 	logger     *zap.Logger
 	stopped    bool
@@ -77,6 +78,12 @@ func newTracesExporter(params exporter.Settings, cfg component.Config) (*traceEx
 	case attrRoutingStr:
 		traceExporter.routingKey = attrRouting
 		traceExporter.routingAttrs = cfg.(*Config).RoutingAttributes
+	case ottlRoutingStr:
+		traceExporter.routingKey = ottlRouting
+		traceExporter.router, err = newTracesRouter(cfg.(*Config), params.TelemetrySettings)
+		if err != nil {
+			return nil, err
+		}
 	case traceIDRoutingStr, "":
 	default:
 		return nil, fmt.Errorf("unsupported routing_key: %s", cfg.(*Config).RoutingKey)
@@ -100,10 +107,31 @@ func (e *traceExporterImp) Shutdown(ctx context.Context) error {
 }
 
 func (e *traceExporterImp) ConsumeTraces(ctx context.Context, td ptrace.Traces) error {
-	batches := batchpersignal.SplitTraces(td)
-
 	exporterSegregatedTraces := make(exporterTraces)
+
+	if e.routingKey == ottlRouting {
+		batches, err := e.router.split(ctx, td)
+		if err != nil {
+			return err
+		}
+		for rid, batch := range batches {
+			exp, _, err := e.loadBalancer.exporterAndEndpoint([]byte(rid))
+			if err != nil {
+				return err
+			}
+
+			_, ok := exporterSegregatedTraces[exp]
+			if !ok {
+				exp.acquire()
+				exporterSegregatedTraces[exp] = ptrace.NewTraces()
+			}
+			exporterSegregatedTraces[exp] = mergeTraces(exporterSegregatedTraces[exp], batch)
+		}
+		return e.consumeTracesByExporter(ctx, exporterSegregatedTraces)
+	}
+
 	endpoints := make(map[*wrappedExporter]string)
+	batches := batchpersignal.SplitTraces(td)
 	for _, batch := range batches {
 		routingID, err := routingIdentifiersFromTraces(batch, e.routingKey, e.routingAttrs)
 		if err != nil {
@@ -118,7 +146,7 @@ func (e *traceExporterImp) ConsumeTraces(ctx context.Context, td ptrace.Traces)
 
 			_, ok := exporterSegregatedTraces[exp]
 			if !ok {
-				exp.consumeWG.Add(1)
+				exp.acquire()
 				exporterSegregatedTraces[exp] = ptrace.NewTraces()
 			}
 			exporterSegregatedTraces[exp] = mergeTraces(exporterSegregatedTraces[exp], batch)
@@ -127,12 +155,16 @@ func (e *traceExporterImp) ConsumeTraces(ctx context.Context, td ptrace.Traces)
 		}
 	}
 
+	return e.consumeTracesByExporter(ctx, exporterSegregatedTraces)
+}
+
+func (e *traceExporterImp) consumeTracesByExporter(ctx context.Context, exporterSegregatedTraces exporterTraces) error {
 	var errs error
 
 	for exp, td := range exporterSegregatedTraces {
 		start := time.Now()
 		err := exp.ConsumeTraces(ctx, td)
-		exp.consumeWG.Done()
+		exp.release()
 		errs = multierr.Append(errs, err)
 		duration := time.Since(start)
 		e.telemetry.LoadbalancerBackendLatency.Record(ctx, duration.Milliseconds(), metric.WithAttributeSet(exp.endpointAttr))
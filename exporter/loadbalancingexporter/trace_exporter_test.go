@@ -245,6 +245,50 @@ func TestConsumeTracesServiceBased(t *testing.T) {
 	assert.NoError(t, res)
 }
 
+func TestConsumeTracesOTTLBased(t *testing.T) {
+	ts, tb := getTelemetryAssets(t)
+	sink := new(consumertest.TracesSink)
+	componentFactory := func(_ context.Context, _ string) (component.Component, error) {
+		return newMockTracesExporter(sink.ConsumeTraces), nil
+	}
+
+	cfg := simpleConfig()
+	cfg.RoutingKey = ottlRoutingStr
+	cfg.RoutingExpression = `attributes["tenant"]`
+
+	lb, err := newLoadBalancer(ts.Logger, cfg, componentFactory, tb)
+	require.NotNil(t, lb)
+	require.NoError(t, err)
+
+	p, err := newTracesExporter(ts, cfg)
+	require.NotNil(t, p)
+	require.NoError(t, err)
+	assert.Equal(t, ottlRouting, p.routingKey)
+
+	// pre-load an exporter here, so that we don't use the actual OTLP exporter
+	lb.addMissingExporters(context.Background(), []string{"endpoint-1"})
+	p.loadBalancer = lb
+
+	err = p.Start(context.Background(), componenttest.NewNopHost())
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, p.Shutdown(context.Background()))
+	}()
+
+	td := ptrace.NewTraces()
+	spans := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+	for _, tenant := range []string{"tenant-1", "tenant-2", "tenant-1"} {
+		spans.AppendEmpty().Attributes().PutStr("tenant", tenant)
+	}
+
+	// test
+	res := p.ConsumeTraces(context.Background(), td)
+
+	// verify
+	assert.NoError(t, res)
+	assert.Equal(t, 3, sink.SpanCount())
+}
+
 func TestAttributeBasedRouting(t *testing.T) {
 	for _, tc := range []struct {
 		name       string
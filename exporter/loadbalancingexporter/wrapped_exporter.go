@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"sync/atomic"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/exporter"
@@ -17,11 +18,14 @@ import (
 )
 
 // wrappedExporter is an exporter that waits for the data processing to complete before shutting down.
-// consumeWG has to be incremented explicitly by the consumer of the wrapped exporter.
+// acquire has to be called explicitly by the consumer of the wrapped exporter before consuming, and release after.
 type wrappedExporter struct {
 	component.Component
 	consumeWG sync.WaitGroup
 
+	// inflight is the number of requests currently being consumed, used as the load for bounded load balancing
+	inflight atomic.Int64
+
 	// we store the attributes here for both cases, to avoid new allocations on the hot path
 	endpointAttr attribute.Set
 	successAttr  attribute.Set
@@ -38,6 +42,18 @@ func newWrappedExporter(exp component.Component, identifier string) *wrappedExpo
 	}
 }
 
+// acquire registers a request about to be consumed by the exporter.
+func (we *wrappedExporter) acquire() {
+	we.consumeWG.Add(1)
+	we.inflight.Add(1)
+}
+
+// release unregisters a request previously registered with acquire.
+func (we *wrappedExporter) release() {
+	we.inflight.Add(-1)
+	we.consumeWG.Done()
+}
+
 func (we *wrappedExporter) Shutdown(ctx context.Context) error {
 	we.consumeWG.Wait()
 	return we.Component.Shutdown(ctx)
@@ -0,0 +1,118 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package opensearchexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/opensearchexporter"
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/indexbootstrap"
+)
+
+const (
+	signalLogs   = "logs"
+	signalTraces = "traces"
+
+	defaultTimestampField = "@timestamp"
+)
+
+// bootstrapResources returns the templates and the ISM policy of the indices of the signal.
+// The index template matches the configured index, or the ss4o indices of all the datasets
+// and namespaces of the signal.
+func bootstrapResources(cfg *Config, signal string) (indexbootstrap.Resources, error) {
+	mode := MappingSS4O
+	pattern := "ss4o_" + signal + "-*-*"
+	if signal == signalLogs {
+		mode = mappingModes[cfg.Mode]
+		if cfg.LogsIndex != "" {
+			pattern = cfg.LogsIndex
+		}
+	}
+	name := "otel-" + signal + "-" + mode.String()
+
+	fields := bootstrapFields(cfg, signal, mode)
+	// Data streams only accept create operations, and require the @timestamp field.
+	_, hasTimestamp := fields[defaultTimestampField]
+	dataStream := cfg.BulkAction == "create" && hasTimestamp
+
+	mappings, err := indexbootstrap.Mappings(fields)
+	if err != nil {
+		return indexbootstrap.Resources{}, err
+	}
+	return indexbootstrap.Resources{
+		PolicyName: "otel-" + signal,
+		ComponentTemplates: []indexbootstrap.ComponentTemplate{{
+			Name:     name + "@mappings",
+			Mappings: mappings,
+		}},
+		IndexTemplates: []indexbootstrap.IndexTemplate{{
+			Name:          name,
+			IndexPatterns: []string{pattern},
+			ComposedOf:    []string{name + "@mappings"},
+			DataStream:    dataStream,
+		}},
+	}, nil
+}
+
+// bootstrapFields returns the mappings of the fields of the documents of the signal encoded
+// in the mapping mode, keyed by their dotted paths.
+func bootstrapFields(cfg *Config, signal string, mode MappingMode) map[string]any {
+	if mode != MappingSS4O {
+		timestampField := cfg.TimestampField
+		if timestampField == "" {
+			timestampField = defaultTimestampField
+		}
+		timestamp := indexbootstrap.Field("date")
+		if cfg.UnixTimestamp {
+			timestamp["format"] = "epoch_millis"
+		}
+		return map[string]any{timestampField: timestamp}
+	}
+
+	fields := map[string]any{
+		"@timestamp":                       indexbootstrap.Field("date"),
+		"traceId":                          indexbootstrap.Field("keyword"),
+		"spanId":                           indexbootstrap.Field("keyword"),
+		"schemaUrl":                        indexbootstrap.Field("keyword"),
+		"instrumentationScope.name":        indexbootstrap.Field("keyword"),
+		"attributes.data_stream.type":      indexbootstrap.Field("keyword"),
+		"attributes.data_stream.dataset":   indexbootstrap.Field("keyword"),
+		"attributes.data_stream.namespace": indexbootstrap.Field("keyword"),
+	}
+	switch signal {
+	case signalLogs:
+		fields["observedTimestamp"] = indexbootstrap.Field("date")
+		fields["severity.text"] = indexbootstrap.Field("keyword")
+		fields["severity.number"] = indexbootstrap.Field("long")
+		fields["body"] = indexbootstrap.Field("text")
+	case signalTraces:
+		fields["startTime"] = indexbootstrap.Field("date")
+		fields["endTime"] = indexbootstrap.Field("date")
+		fields["parentSpanId"] = indexbootstrap.Field("keyword")
+		fields["traceState"] = indexbootstrap.Field("keyword")
+		fields["name"] = indexbootstrap.Field("keyword")
+		fields["kind"] = indexbootstrap.Field("keyword")
+		fields["status.code"] = indexbootstrap.Field("keyword")
+		fields["status.message"] = indexbootstrap.Field("text")
+	}
+	return fields
+}
+
+// bootstrapIndices installs the templates and the ISM policy of the indices of the signal, if enabled.
+func bootstrapIndices(ctx context.Context, transport indexbootstrap.Transport, cfg *Config, signal string, logger *zap.Logger) error {
+	if !cfg.Bootstrap.Enabled {
+		return nil
+	}
+	resources, err := bootstrapResources(cfg, signal)
+	if err != nil {
+		return fmt.Errorf("failed to bootstrap index templates: %w", err)
+	}
+	bootstrapper := indexbootstrap.New(transport, indexbootstrap.FlavorOpenSearch, cfg.Bootstrap, logger)
+	if err := bootstrapper.Install(ctx, resources); err != nil {
+		return fmt.Errorf("failed to bootstrap index templates: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package opensearchexporter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/opensearchexporter/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/indexbootstrap"
+)
+
+func TestBootstrapResources(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		signal     string
+		configure  func(*Config)
+		template   string
+		pattern    string
+		dataStream bool
+	}{
+		{
+			name:       "ss4o logs",
+			signal:     signalLogs,
+			template:   "otel-logs-ss4o",
+			pattern:    "ss4o_logs-*-*",
+			dataStream: true,
+		},
+		{
+			name:       "ss4o traces",
+			signal:     signalTraces,
+			template:   "otel-traces-ss4o",
+			pattern:    "ss4o_traces-*-*",
+			dataStream: true,
+		},
+		{
+			name:   "logs index",
+			signal: signalLogs,
+			configure: func(cfg *Config) {
+				cfg.LogsIndex = "my-logs"
+			},
+			template:   "otel-logs-ss4o",
+			pattern:    "my-logs",
+			dataStream: true,
+		},
+		{
+			name:   "index bulk action",
+			signal: signalLogs,
+			configure: func(cfg *Config) {
+				cfg.BulkAction = "index"
+			},
+			template:   "otel-logs-ss4o",
+			pattern:    "ss4o_logs-*-*",
+			dataStream: false,
+		},
+		{
+			name:   "ecs timestamp field",
+			signal: signalLogs,
+			configure: func(cfg *Config) {
+				cfg.Mode = MappingECS.String()
+				cfg.TimestampField = "ts"
+			},
+			template:   "otel-logs-ecs",
+			pattern:    "ss4o_logs-*-*",
+			dataStream: false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := withDefaultConfig()
+			if tc.configure != nil {
+				tc.configure(cfg)
+			}
+			res, err := bootstrapResources(cfg, tc.signal)
+			require.NoError(t, err)
+
+			assert.Equal(t, "otel-"+tc.signal, res.PolicyName)
+			require.Len(t, res.ComponentTemplates, 1)
+			assert.Equal(t, tc.template+"@mappings", res.ComponentTemplates[0].Name)
+			assert.Equal(t, []indexbootstrap.IndexTemplate{{
+				Name:          tc.template,
+				IndexPatterns: []string{tc.pattern},
+				ComposedOf:    []string{tc.template + "@mappings"},
+				DataStream:    tc.dataStream,
+			}}, res.IndexTemplates)
+		})
+	}
+}
+
+func TestBootstrapFields(t *testing.T) {
+	cfg := withDefaultConfig()
+
+	logs := bootstrapFields(cfg, signalLogs, MappingSS4O)
+	assert.Equal(t, indexbootstrap.Field("date"), logs["@timestamp"])
+	assert.Equal(t, indexbootstrap.Field("keyword"), logs["attributes.data_stream.dataset"])
+	assert.Equal(t, indexbootstrap.Field("text"), logs["body"])
+	assert.NotContains(t, logs, "startTime")
+
+	traces := bootstrapFields(cfg, signalTraces, MappingSS4O)
+	assert.Equal(t, indexbootstrap.Field("date"), traces["startTime"])
+	assert.NotContains(t, traces, "body")
+
+	cfg.TimestampField = "ts"
+	cfg.UnixTimestamp = true
+	assert.Equal(t, map[string]any{
+		"ts": map[string]any{"type": "date", "format": "epoch_millis"},
+	}, bootstrapFields(cfg, signalLogs, MappingECS))
+}
+
+func TestExporterBootstrap(t *testing.T) {
+	var mu sync.Mutex
+	resources := map[string]json.RawMessage{}
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case http.MethodGet:
+			if _, ok := resources[r.URL.Path]; !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write(resources[r.URL.Path])
+		case http.MethodPut:
+			var body json.RawMessage
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			resources[r.URL.Path] = body
+			_, _ = w.Write([]byte(`{"acknowledged":true}`))
+		}
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/_plugins/_ism/policies/", handler)
+	mux.HandleFunc("/_component_template/", handler)
+	mux.HandleFunc("/_index_template/", handler)
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	cfg := withDefaultConfig(func(config *Config) {
+		config.Endpoint = ts.URL
+		config.Bootstrap.Enabled = true
+	})
+	exporter, err := NewFactory().CreateTraces(context.Background(), exportertest.NewNopSettings(metadata.Type), cfg)
+	require.NoError(t, err)
+	require.NoError(t, exporter.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, exporter.Shutdown(context.Background()))
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Len(t, resources, 3)
+	assert.Contains(t, resources, "/_plugins/_ism/policies/otel-traces")
+	assert.Contains(t, resources, "/_component_template/otel-traces-ss4o@mappings")
+
+	var indexTemplate map[string]any
+	require.NoError(t, json.Unmarshal(resources["/_index_template/otel-traces-ss4o"], &indexTemplate))
+	assert.Equal(t, []any{"ss4o_traces-*-*"}, indexTemplate["index_patterns"])
+	assert.Equal(t, map[string]any{}, indexTemplate["data_stream"])
+	assert.NotContains(t, indexTemplate, "template")
+}
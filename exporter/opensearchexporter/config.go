@@ -10,6 +10,8 @@ import (
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/config/configretry"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/indexbootstrap"
 )
 
 const (
@@ -48,6 +50,10 @@ type Config struct {
 	// BulkAction configures the action for ingesting data. Only `create` and `index` are allowed here.
 	// If not specified, the default value `create` will be used.
 	BulkAction string `mapstructure:"bulk_action"`
+
+	// Bootstrap configures the installation of the index templates and the
+	// ISM policy of the indices on start.
+	Bootstrap indexbootstrap.Config `mapstructure:"bootstrap"`
 }
 
 var (
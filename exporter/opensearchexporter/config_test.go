@@ -19,6 +19,7 @@ import (
 	"go.opentelemetry.io/collector/confmap/xconfmap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/opensearchexporter/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/indexbootstrap"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -79,6 +80,7 @@ func TestLoadConfig(t *testing.T) {
 				MappingsSettings: MappingsSettings{
 					Mode: "ss4o",
 				},
+				Bootstrap: indexbootstrap.NewDefaultConfig(),
 			},
 			configValidateAssert: assert.NoError,
 		},
@@ -114,6 +116,27 @@ func TestLoadConfig(t *testing.T) {
 				return assert.ErrorContains(t, err, errBulkActionInvalid.Error())
 			},
 		},
+		{
+			id: component.NewIDWithName(metadata.Type, "bootstrap"),
+			expected: withDefaultConfig(func(config *Config) {
+				config.Endpoint = sampleEndpoint
+				config.Bootstrap.Enabled = true
+				config.Bootstrap.Lifecycle.RolloverMaxAge = 24 * time.Hour
+				config.Bootstrap.Lifecycle.DeleteAfter = 30 * 24 * time.Hour
+			}),
+			configValidateAssert: assert.NoError,
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "invalid_bootstrap"),
+			expected: withDefaultConfig(func(config *Config) {
+				config.Endpoint = sampleEndpoint
+				config.Bootstrap.Enabled = true
+				config.Bootstrap.Lifecycle.RolloverMaxAge = -time.Hour
+			}),
+			configValidateAssert: func(t assert.TestingT, err error, _ ...any) bool {
+				return assert.ErrorContains(t, err, "lifecycle::rollover_max_age should be non-negative")
+			},
+		},
 	}
 
 	for _, tt := range tests {
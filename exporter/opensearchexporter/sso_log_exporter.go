@@ -21,6 +21,7 @@ type logExporter struct {
 	model        mappingModel
 	httpSettings confighttp.ClientConfig
 	telemetry    component.TelemetrySettings
+	config       *Config
 }
 
 func newLogExporter(cfg *Config, set exporter.Settings) *logExporter {
@@ -41,6 +42,7 @@ func newLogExporter(cfg *Config, set exporter.Settings) *logExporter {
 		bulkAction:   cfg.BulkAction,
 		httpSettings: cfg.ClientConfig,
 		model:        model,
+		config:       cfg,
 	}
 }
 
@@ -55,6 +57,10 @@ func (l *logExporter) Start(ctx context.Context, host component.Host) error {
 		return err
 	}
 
+	if err := bootstrapIndices(ctx, client, l.config, signalLogs, l.telemetry.Logger); err != nil {
+		return err
+	}
+
 	l.client = client
 	return nil
 }
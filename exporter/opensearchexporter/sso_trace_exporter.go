@@ -23,6 +23,7 @@ type ssoTracesExporter struct {
 	model        mappingModel
 	httpSettings confighttp.ClientConfig
 	telemetry    component.TelemetrySettings
+	config       *Config
 }
 
 func newSSOTracesExporter(cfg *Config, set exporter.Settings) *ssoTracesExporter {
@@ -38,6 +39,7 @@ func newSSOTracesExporter(cfg *Config, set exporter.Settings) *ssoTracesExporter
 		bulkAction:   cfg.BulkAction,
 		model:        model,
 		httpSettings: cfg.ClientConfig,
+		config:       cfg,
 	}
 }
 
@@ -52,6 +54,10 @@ func (s *ssoTracesExporter) Start(ctx context.Context, host component.Host) erro
 		return err
 	}
 
+	if err := bootstrapIndices(ctx, client, s.config, signalTraces, s.telemetry.Logger); err != nil {
+		return err
+	}
+
 	s.client = client
 	return nil
 }
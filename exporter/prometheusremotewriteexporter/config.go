@@ -106,6 +106,18 @@ func (cfg *Config) Validate() error {
 		return errors.New("remote write consumer number can't be negative")
 	}
 
+	if cfg.WAL != nil {
+		if cfg.WAL.MinShards < 0 || cfg.WAL.MaxShards < 0 {
+			return errors.New("wal min_shards and max_shards can't be negative")
+		}
+		if cfg.WAL.MaxShards > 0 && cfg.WAL.MinShards > cfg.WAL.MaxShards {
+			return errors.New("wal min_shards can't be greater than max_shards")
+		}
+		if cfg.WAL.ReshardFrequency < 0 {
+			return errors.New("wal reshard_frequency can't be negative")
+		}
+	}
+
 	if cfg.TargetInfo == nil {
 		cfg.TargetInfo = &TargetInfo{
 			Enabled: true,
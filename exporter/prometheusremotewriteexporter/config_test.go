@@ -101,6 +101,14 @@ func TestLoadConfig(t *testing.T) {
 			id:           component.NewIDWithName(metadata.Type, "non_snappy_compression_type"),
 			errorMessage: "compression type must be snappy",
 		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "negative_wal_max_shards"),
+			errorMessage: "wal min_shards and max_shards can't be negative",
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "wal_min_shards_greater_than_max_shards"),
+			errorMessage: "wal min_shards can't be greater than max_shards",
+		},
 	}
 
 	for _, tt := range tests {
@@ -148,6 +156,32 @@ func TestDisabledTargetInfo(t *testing.T) {
 	assert.False(t, cfg.(*Config).TargetInfo.Enabled)
 }
 
+func TestWALShardsConfig(t *testing.T) {
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+	require.NoError(t, err)
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+
+	sub, err := cm.Sub(component.NewIDWithName(metadata.Type, "wal_shards").String())
+	require.NoError(t, err)
+	require.NoError(t, sub.Unmarshal(cfg))
+	require.NoError(t, xconfmap.Validate(cfg))
+
+	walConfig := cfg.(*Config).WAL
+	require.NotNil(t, walConfig)
+	assert.Equal(t, 2, walConfig.minShards())
+	assert.Equal(t, 20, walConfig.maxShards())
+	assert.Equal(t, 30*time.Second, walConfig.reshardFrequency())
+
+	defaults := &WALConfig{}
+	assert.Equal(t, defaultWALMinShards, defaults.minShards())
+	assert.Equal(t, defaultWALMaxShards, defaults.maxShards())
+	assert.Equal(t, defaultWALReshardFrequency, defaults.reshardFrequency())
+
+	// The maximum number of shards is never lower than the minimum.
+	assert.Equal(t, 60, (&WALConfig{MinShards: 60}).maxShards())
+}
+
 func toPtr[T any](val T) *T {
 	return &val
 }
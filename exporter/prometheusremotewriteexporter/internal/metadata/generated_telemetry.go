@@ -30,6 +30,7 @@ type TelemetryBuilder struct {
 	ExporterPrometheusremotewriteFailedTranslations   metric.Int64Counter
 	ExporterPrometheusremotewriteSentBatches          metric.Int64Counter
 	ExporterPrometheusremotewriteTranslatedTimeSeries metric.Int64Counter
+	ExporterPrometheusremotewriteWalShards            metric.Int64UpDownCounter
 	ExporterPrometheusremotewriteWalWrites            metric.Int64Counter
 	ExporterPrometheusremotewriteWalWritesFailures    metric.Int64Counter
 }
@@ -87,6 +88,12 @@ func NewTelemetryBuilder(settings component.TelemetrySettings, options ...Teleme
 		metric.WithUnit("1"),
 	)
 	errs = errors.Join(errs, err)
+	builder.ExporterPrometheusremotewriteWalShards, err = builder.meter.Int64UpDownCounter(
+		"otelcol_exporter_prometheusremotewrite_wal_shards",
+		metric.WithDescription("Number of shards used to send the requests read from the WAL"),
+		metric.WithUnit("{shard}"),
+	)
+	errs = errors.Join(errs, err)
 	builder.ExporterPrometheusremotewriteWalWrites, err = builder.meter.Int64Counter(
 		"otelcol_exporter_prometheusremotewrite_wal_writes",
 		metric.WithDescription("Number of WAL writes"),
@@ -4,6 +4,7 @@
 package prometheusremotewriteexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/prometheusremotewriteexporter"
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -18,7 +19,6 @@ import (
 	"go.opentelemetry.io/collector/exporter"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
-	"go.uber.org/multierr"
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/prometheusremotewriteexporter/internal/metadata"
@@ -27,6 +27,7 @@ import (
 type prwWalTelemetry interface {
 	recordWALWrites(ctx context.Context)
 	recordWALWritesFailures(ctx context.Context)
+	recordWALShards(ctx context.Context, delta int64)
 }
 
 type prwWalTelemetryOTel struct {
@@ -42,6 +43,10 @@ func (p *prwWalTelemetryOTel) recordWALWritesFailures(ctx context.Context) {
 	p.telemetryBuilder.ExporterPrometheusremotewriteWalWritesFailures.Add(ctx, 1, metric.WithAttributes(p.otelAttrs...))
 }
 
+func (p *prwWalTelemetryOTel) recordWALShards(ctx context.Context, delta int64) {
+	p.telemetryBuilder.ExporterPrometheusremotewriteWalShards.Add(ctx, delta, metric.WithAttributes(p.otelAttrs...))
+}
+
 func newPRWWalTelemetry(set exporter.Settings) (prwWalTelemetry, error) {
 	telemetryBuilder, err := metadata.NewTelemetryBuilder(set.TelemetrySettings)
 	if err != nil {
@@ -60,7 +65,7 @@ type prweWAL struct {
 	walConfig *WALConfig
 	walPath   string
 
-	exportSink func(ctx context.Context, reqL []*prompb.WriteRequest) error
+	shards *walShards
 
 	stopOnce  sync.Once
 	stopChan  chan struct{}
@@ -80,6 +85,12 @@ type WALConfig struct {
 	Directory         string        `mapstructure:"directory"`
 	BufferSize        int           `mapstructure:"buffer_size"`
 	TruncateFrequency time.Duration `mapstructure:"truncate_frequency"`
+	// MinShards is the minimum number of shards used to send the requests read from the WAL.
+	MinShards int `mapstructure:"min_shards"`
+	// MaxShards is the maximum number of shards used to send the requests read from the WAL.
+	MaxShards int `mapstructure:"max_shards"`
+	// ReshardFrequency is how often the number of shards is recomputed from the observed throughput.
+	ReshardFrequency time.Duration `mapstructure:"reshard_frequency"`
 }
 
 func (wc *WALConfig) bufferSize() int {
@@ -96,6 +107,27 @@ func (wc *WALConfig) truncateFrequency() time.Duration {
 	return defaultWALTruncateFrequency
 }
 
+func (wc *WALConfig) minShards() int {
+	if wc.MinShards > 0 {
+		return wc.MinShards
+	}
+	return defaultWALMinShards
+}
+
+func (wc *WALConfig) maxShards() int {
+	if wc.MaxShards > 0 {
+		return max(wc.MaxShards, wc.minShards())
+	}
+	return max(defaultWALMaxShards, wc.minShards())
+}
+
+func (wc *WALConfig) reshardFrequency() time.Duration {
+	if wc.ReshardFrequency > 0 {
+		return wc.ReshardFrequency
+	}
+	return defaultWALReshardFrequency
+}
+
 func newWAL(walConfig *WALConfig, set exporter.Settings, exportSink func(context.Context, []*prompb.WriteRequest) error) (*prweWAL, error) {
 	if walConfig == nil {
 		// There are cases for which the WAL can be disabled.
@@ -108,15 +140,16 @@ func newWAL(walConfig *WALConfig, set exporter.Settings, exportSink func(context
 		return nil, err
 	}
 
-	return &prweWAL{
-		exportSink: exportSink,
-		walConfig:  walConfig,
-		stopChan:   make(chan struct{}),
-		rNotify:    make(chan struct{}),
-		rWALIndex:  &atomic.Uint64{},
-		wWALIndex:  &atomic.Uint64{},
-		telemetry:  telemetryPRWWal,
-	}, nil
+	prweWAL := &prweWAL{
+		walConfig: walConfig,
+		stopChan:  make(chan struct{}),
+		rNotify:   make(chan struct{}),
+		rWALIndex: &atomic.Uint64{},
+		wWALIndex: &atomic.Uint64{},
+		telemetry: telemetryPRWWal,
+	}
+	prweWAL.shards = newWALShards(walConfig, prweWAL, exportSink, telemetryPRWWal)
+	return prweWAL, nil
 }
 
 func (wc *WALConfig) createWAL() (*wal.Log, string, error) {
@@ -172,13 +205,15 @@ func (prweWAL *prweWAL) stop() error {
 	err := errAlreadyClosed
 	prweWAL.stopOnce.Do(func() {
 		close(prweWAL.stopChan)
+		// The shards are stopped first, as the resharding waits for them.
+		prweWAL.shards.stop()
 		prweWAL.wg.Wait()
 		err = prweWAL.closeWAL()
 	})
 	return err
 }
 
-// run begins reading from the WAL until prwe.stopChan is closed.
+// run starts the shards reading from the WAL until prwe.stopChan is closed.
 func (prweWAL *prweWAL) run(ctx context.Context) (err error) {
 	var logger *zap.Logger
 	logger, err = loggerFromContext(ctx)
@@ -191,108 +226,48 @@ func (prweWAL *prweWAL) run(ctx context.Context) (err error) {
 		return
 	}
 
-	runCtx, cancel := context.WithCancel(ctx)
+	// The entries before the first index of the WAL were sent by all the shards before it was truncated.
+	prweWAL.shards.start(ctx, logger, max(prweWAL.rWALIndex.Load(), 1))
 
-	// Start the process of exporting but wait until the exporting has started.
-	waitUntilStartedCh := make(chan bool)
 	prweWAL.wg.Add(1)
 	go func() {
 		defer prweWAL.wg.Done()
-		defer cancel()
-
-		signalStart := func() { close(waitUntilStartedCh) }
-		for {
-			select {
-			case <-runCtx.Done():
-				return
-			case <-prweWAL.stopChan:
-				return
-			default:
-				err := prweWAL.continuallyPopWALThenExport(runCtx, signalStart)
-				signalStart = func() {}
-				if err != nil {
-					// log err
-					logger.Error("error processing WAL entries", zap.Error(err))
-					// Restart WAL
-					if errS := prweWAL.retrieveWALIndices(); errS != nil {
-						logger.Error("unable to re-start write-ahead log after error", zap.Error(errS))
-						return
-					}
-				}
-			}
-		}
+		prweWAL.reshardPeriodically(ctx, logger)
 	}()
-	<-waitUntilStartedCh
 	return nil
 }
 
-// continuallyPopWALThenExport reads a prompb.WriteRequest proto encoded blob from the WAL, and moves
-// the WAL's front index forward until either the read buffer period expires or the maximum
-// buffer size is exceeded. When either of the two conditions are matched, it then exports
-// the requests to the Remote-Write endpoint, and then truncates the head of the WAL to where
-// it last read from.
-func (prweWAL *prweWAL) continuallyPopWALThenExport(ctx context.Context, signalStart func()) (err error) {
-	var reqL []*prompb.WriteRequest
-	defer func() {
-		// Keeping it within a closure to ensure that the later
-		// updated value of reqL is always flushed to disk.
-		if errL := prweWAL.exportSink(ctx, reqL); errL != nil {
-			err = multierr.Append(err, errL)
-		}
-	}()
-
-	freshTimer := func() *time.Timer {
-		return time.NewTimer(prweWAL.walConfig.truncateFrequency())
-	}
-
-	timer := freshTimer()
-	defer func() {
-		// Added in a closure to ensure we capture the later
-		// updated value of timer when changed in the loop below.
-		timer.Stop()
-	}()
-
-	signalStart()
-
-	maxCountPerUpload := prweWAL.walConfig.bufferSize()
+// reshardPeriodically recomputes the number of shards used to send the requests read from
+// the WAL until prwe.stopChan is closed.
+func (prweWAL *prweWAL) reshardPeriodically(ctx context.Context, logger *zap.Logger) {
+	ticker := time.NewTicker(prweWAL.walConfig.reshardFrequency())
+	defer ticker.Stop()
 	for {
 		select {
 		case <-ctx.Done():
-			return ctx.Err()
+			return
 		case <-prweWAL.stopChan:
-			return nil
-		default:
-		}
-
-		var req *prompb.WriteRequest
-		req, err = prweWAL.readPrompbFromWAL(ctx, prweWAL.rWALIndex.Load())
-		if err != nil {
-			return err
-		}
-		reqL = append(reqL, req)
-
-		var shouldExport bool
-		select {
-		case <-timer.C:
-			shouldExport = true
-		default:
-			shouldExport = len(reqL) >= maxCountPerUpload
-		}
-
-		if !shouldExport {
-			continue
+			return
+		case <-ticker.C:
+			if from, to := prweWAL.shards.updateShards(ctx, prweWAL.pendingEntries()); from != to {
+				logger.Debug("resharding WAL replay", zap.Int("from", from), zap.Int("to", to))
+				prweWAL.shards.reshard(ctx, logger)
+			}
 		}
+	}
+}
 
-		// Otherwise, it is time to export, flush and then truncate the WAL, but also to kill the timer!
-		timer.Stop()
-		timer = freshTimer()
-
-		if err = prweWAL.exportThenFrontTruncateWAL(ctx, reqL); err != nil {
-			return err
-		}
-		// Reset but reuse the write requests slice.
-		reqL = reqL[:0]
+// pendingEntries returns the number of entries written to the WAL that weren't sent by all the shards yet.
+func (prweWAL *prweWAL) pendingEntries() uint64 {
+	rIndex, wIndex := prweWAL.rWALIndex.Load(), prweWAL.wWALIndex.Load()
+	if rIndex == 0 {
+		// The reads start at the first index.
+		rIndex = 1
+	}
+	if wIndex < rIndex {
+		return 0
 	}
+	return wIndex - rIndex + 1
 }
 
 func (prweWAL *prweWAL) closeWAL() error {
@@ -304,7 +279,9 @@ func (prweWAL *prweWAL) closeWAL() error {
 	return nil
 }
 
-func (prweWAL *prweWAL) syncAndTruncateFront() error {
+// syncAndTruncateFront saves the entries written to the WAL, and truncates the WAL from the front
+// up to the given index, the entries before it having been sent by all the shards.
+func (prweWAL *prweWAL) syncAndTruncateFront(index uint64) error {
 	prweWAL.mu.Lock()
 	defer prweWAL.mu.Unlock()
 
@@ -316,30 +293,15 @@ func (prweWAL *prweWAL) syncAndTruncateFront() error {
 	if err := prweWAL.wal.Sync(); err != nil {
 		return err
 	}
-	// Truncate the WAL from the front for the entries that we already
-	// read from the WAL and had already exported.
-	if err := prweWAL.wal.TruncateFront(prweWAL.rWALIndex.Load()); err != nil && !errors.Is(err, wal.ErrOutOfRange) {
-		return err
-	}
-	return nil
-}
-
-func (prweWAL *prweWAL) exportThenFrontTruncateWAL(ctx context.Context, reqL []*prompb.WriteRequest) error {
-	if len(reqL) == 0 {
+	// The shards truncate the WAL concurrently, so the read index only moves forward.
+	if index <= prweWAL.rWALIndex.Load() {
 		return nil
 	}
-	if cErr := ctx.Err(); cErr != nil {
-		return nil
-	}
-
-	if errL := prweWAL.exportSink(ctx, reqL); errL != nil {
-		return errL
-	}
-	if err := prweWAL.syncAndTruncateFront(); err != nil {
+	if err := prweWAL.wal.TruncateFront(index); err != nil && !errors.Is(err, wal.ErrOutOfRange) {
 		return err
 	}
-	// Reset by retrieving the respective read and write WAL indices.
-	return prweWAL.retrieveWALIndices()
+	prweWAL.rWALIndex.Store(index)
+	return nil
 }
 
 // persistToWAL is the routine that'll be hooked into the exporter's receiving side and it'll
@@ -351,6 +313,7 @@ func (prweWAL *prweWAL) persistToWAL(requests []*prompb.WriteRequest) error {
 
 	// Write all the requests to the WAL in a batch.
 	batch := new(wal.Batch)
+	samples := 0
 	for _, req := range requests {
 		protoBlob, err := proto.Marshal(req)
 		if err != nil {
@@ -358,67 +321,48 @@ func (prweWAL *prweWAL) persistToWAL(requests []*prompb.WriteRequest) error {
 		}
 		wIndex := prweWAL.wWALIndex.Add(1)
 		batch.Write(wIndex, protoBlob)
+		samples += countSamples(req)
 	}
 
-	// Notify reader go routine that is possibly waiting for writes.
-	select {
-	case prweWAL.rNotify <- struct{}{}:
-	default:
+	if err := prweWAL.wal.WriteBatch(batch); err != nil {
+		return err
 	}
 
-	return prweWAL.wal.WriteBatch(batch)
+	// Notify the shards that are possibly waiting for writes.
+	close(prweWAL.rNotify)
+	prweWAL.rNotify = make(chan struct{})
+	prweWAL.shards.samplesIn.incr(int64(samples))
+	return nil
 }
 
-func (prweWAL *prweWAL) readPrompbFromWAL(ctx context.Context, index uint64) (wreq *prompb.WriteRequest, err error) {
-	var protoBlob []byte
-	for i := 0; i < 12; i++ {
-		// Firstly check if we've been terminated, then exit if so.
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		case <-prweWAL.stopChan:
-			return nil, errors.New("attempt to read from WAL after stopped")
-		default:
-		}
-
-		if index <= 0 {
-			index = 1
-		}
-
-		prweWAL.mu.Lock()
-		if prweWAL.wal == nil {
-			return nil, errors.New("attempt to read from closed WAL")
-		}
-		protoBlob, err = prweWAL.wal.Read(index)
-		if err == nil { // The read succeeded.
-			req := new(prompb.WriteRequest)
-			if err = proto.Unmarshal(protoBlob, req); err != nil {
-				return nil, err
-			}
-
-			// Now increment the WAL's read index.
-			prweWAL.rWALIndex.Add(1)
+// writeNotify returns a channel that is closed once new entries are written to the WAL.
+func (prweWAL *prweWAL) writeNotify() <-chan struct{} {
+	prweWAL.mu.Lock()
+	defer prweWAL.mu.Unlock()
+	return prweWAL.rNotify
+}
 
-			prweWAL.mu.Unlock()
-			return req, nil
-		}
+// readPrompbFromWAL reads the request of the entry at the given index, it returns wal.ErrNotFound
+// if the entry wasn't written yet.
+func (prweWAL *prweWAL) readPrompbFromWAL(index uint64) (*prompb.WriteRequest, error) {
+	prweWAL.mu.Lock()
+	if prweWAL.wal == nil {
 		prweWAL.mu.Unlock()
+		return nil, errors.New("attempt to read from closed WAL")
+	}
+	protoBlob, err := prweWAL.wal.Read(index)
+	if err != nil {
+		prweWAL.mu.Unlock()
+		return nil, err
+	}
+	// The WAL doesn't copy the entries, so the blob is copied to be decoded outside of the lock,
+	// as the shards read the same entries concurrently.
+	protoBlob = bytes.Clone(protoBlob)
+	prweWAL.mu.Unlock()
 
-		// If WAL was empty, let's wait for a notification from
-		// the writer go routine.
-		if errors.Is(err, wal.ErrNotFound) {
-			select {
-			case <-prweWAL.rNotify:
-			case <-ctx.Done():
-				return nil, ctx.Err()
-			case <-prweWAL.stopChan:
-				return nil, errors.New("attempt to read from WAL after stopped")
-			}
-		}
-
-		if !errors.Is(err, wal.ErrNotFound) {
-			return nil, err
-		}
+	req := new(prompb.WriteRequest)
+	if err := proto.Unmarshal(protoBlob, req); err != nil {
+		return nil, err
 	}
-	return nil, err
+	return req, nil
 }
@@ -0,0 +1,493 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewriteexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/prometheusremotewriteexporter"
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/tidwall/wal"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultWALMinShards        = 1
+	defaultWALMaxShards        = 50
+	defaultWALReshardFrequency = 10 * time.Second
+
+	// shardToleranceFraction is the fraction of the current number of shards within which a change of
+	// the desired number of shards is ignored, so that small variations of the throughput don't reshard.
+	shardToleranceFraction = 0.3
+	// ewmaWeight is the weight of the latest interval in the moving averages of the rates.
+	ewmaWeight = 0.2
+	// backlogCatchUpFraction is the fraction of the WAL backlog that the shards aim to send
+	// in addition to the incoming samples during each resharding interval.
+	backlogCatchUpFraction = 0.1
+)
+
+// ewmaRate tracks the exponentially weighted moving average of a per-second rate.
+// incr can be called concurrently, tick and rate are only called by the resharding loop.
+type ewmaRate struct {
+	newEvents atomic.Int64
+	interval  time.Duration
+	lastRate  float64
+	init      bool
+}
+
+func newEWMARate(interval time.Duration) *ewmaRate {
+	return &ewmaRate{interval: interval}
+}
+
+func (r *ewmaRate) incr(n int64) {
+	r.newEvents.Add(n)
+}
+
+// tick updates the moving average with the events counted since the previous tick.
+func (r *ewmaRate) tick() {
+	newEvents := r.newEvents.Swap(0)
+	instantRate := float64(newEvents) / r.interval.Seconds()
+	switch {
+	case r.init:
+		r.lastRate += ewmaWeight * (instantRate - r.lastRate)
+	case newEvents > 0:
+		r.init = true
+		r.lastRate = instantRate
+	}
+}
+
+func (r *ewmaRate) rate() float64 {
+	return r.lastRate
+}
+
+// walEntries is the write-ahead log read by the shards.
+type walEntries interface {
+	// readPrompbFromWAL returns the request of the entry at the given index, or wal.ErrNotFound if it wasn't written yet.
+	readPrompbFromWAL(index uint64) (*prompb.WriteRequest, error)
+	// writeNotify returns a channel that is closed once new entries are written.
+	writeNotify() <-chan struct{}
+	// syncAndTruncateFront removes the entries before the given index, which were sent by all the shards.
+	syncAndTruncateFront(index uint64) error
+}
+
+// walShards sends the requests written to the WAL to the remote write endpoint. Similarly to the
+// Prometheus remote write queue manager, the time series are spread over a number of shards by
+// hashing their labels, so that the samples of a series are always sent in order by the same shard,
+// and the number of shards is periodically recomputed from the rate at which samples are written
+// to the WAL, the latency of the endpoint and the backlog of the WAL.
+//
+// Each shard is a long-lived goroutine reading the WAL from its own index and sending the time series
+// assigned to it. The shards track their progress separately: a shard failing to send its requests only
+// retries them, without holding back the other shards, and the WAL is truncated up to the first entry
+// that wasn't sent by all the shards.
+type walShards struct {
+	entries    walEntries
+	exportSink func(ctx context.Context, reqL []*prompb.WriteRequest) error
+	telemetry  prwWalTelemetry
+
+	bufferSize       int
+	flushFrequency   time.Duration
+	minShards        int
+	maxShards        int
+	reshardFrequency time.Duration
+	numShards        atomic.Int64
+
+	// mu protects the running shards, which are replaced when the number of shards changes.
+	mu       sync.Mutex
+	shards   atomic.Pointer[[]*walShard]
+	wg       sync.WaitGroup
+	stopOnce sync.Once
+	stopCh   chan struct{}
+
+	samplesIn    *ewmaRate // Samples written to the WAL.
+	samplesOut   *ewmaRate // Samples successfully sent to the endpoint.
+	sendDuration *ewmaRate // Nanoseconds spent sending by all the shards.
+
+	// entriesSent and samplesSent estimate the number of samples of the WAL entries that weren't sent yet.
+	entriesSent       atomic.Int64
+	samplesSent       atomic.Int64
+	lastSendTimestamp atomic.Int64
+}
+
+// walShard reads the WAL entries and sends the time series assigned to it.
+type walShard struct {
+	id        int
+	numShards int
+	// next is the index of the next WAL entry read by the shard.
+	next atomic.Uint64
+	// sent is the index of the first WAL entry whose time series assigned to the shard weren't all sent.
+	sent atomic.Uint64
+	// When resharding, pause is closed to stop the shard from reading new entries, which closes paused.
+	// Once all the shards are paused, resume is closed for the shard to send the time series of the
+	// entries before drainTo, then done is closed.
+	pause   chan struct{}
+	paused  chan struct{}
+	resume  chan struct{}
+	drainTo uint64
+	done    chan struct{}
+}
+
+// shardBatch holds the requests of a shard built from the WAL entries read since its last send.
+type shardBatch struct {
+	reqL    []*prompb.WriteRequest
+	size    int // The size of the last request.
+	maxSize int // The size of the biggest WAL entry.
+	entries int
+	samples int // The samples of all the time series of the entries, not only of the shard.
+}
+
+func newWALShards(walConfig *WALConfig, entries walEntries, exportSink func(context.Context, []*prompb.WriteRequest) error, telemetry prwWalTelemetry) *walShards {
+	reshardFrequency := walConfig.reshardFrequency()
+	s := &walShards{
+		entries:          entries,
+		exportSink:       exportSink,
+		telemetry:        telemetry,
+		bufferSize:       walConfig.bufferSize(),
+		flushFrequency:   walConfig.truncateFrequency(),
+		minShards:        walConfig.minShards(),
+		maxShards:        walConfig.maxShards(),
+		reshardFrequency: reshardFrequency,
+		stopCh:           make(chan struct{}),
+		samplesIn:        newEWMARate(reshardFrequency),
+		samplesOut:       newEWMARate(reshardFrequency),
+		sendDuration:     newEWMARate(reshardFrequency),
+	}
+	s.numShards.Store(int64(s.minShards))
+	s.telemetry.recordWALShards(context.Background(), int64(s.minShards))
+	return s
+}
+
+// start starts the shards, reading the WAL from the given index.
+func (s *walShards) start(ctx context.Context, logger *zap.Logger, first uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.startShards(ctx, logger, first)
+}
+
+func (s *walShards) startShards(ctx context.Context, logger *zap.Logger, first uint64) {
+	select {
+	case <-s.stopCh:
+		return
+	default:
+	}
+
+	shards := make([]*walShard, s.numShards.Load())
+	for i := range shards {
+		shard := &walShard{
+			id:        i,
+			numShards: len(shards),
+			pause:     make(chan struct{}),
+			paused:    make(chan struct{}),
+			resume:    make(chan struct{}),
+			done:      make(chan struct{}),
+		}
+		shard.next.Store(first)
+		shard.sent.Store(first)
+		shards[i] = shard
+	}
+	s.shards.Store(&shards)
+	for _, shard := range shards {
+		s.wg.Add(1)
+		go s.runShard(ctx, logger, shard)
+	}
+}
+
+// reshard restarts the shards if the number of shards changed. The running shards are paused, and then
+// send the time series of all the entries read by any of them, so that the new shards all start from
+// the same entry and the samples of each series are still sent in order.
+func (s *walShards) reshard(ctx context.Context, logger *zap.Logger) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	select {
+	case <-s.stopCh:
+		return
+	default:
+	}
+	shards := s.runningShards()
+	if len(shards) == 0 || len(shards) == int(s.numShards.Load()) {
+		return
+	}
+	for _, shard := range shards {
+		close(shard.pause)
+	}
+	var drainTo uint64
+	for _, shard := range shards {
+		select {
+		case <-shard.paused:
+		case <-shard.done:
+		}
+		drainTo = max(drainTo, shard.next.Load())
+	}
+	for _, shard := range shards {
+		shard.drainTo = drainTo
+		close(shard.resume)
+	}
+	for _, shard := range shards {
+		<-shard.done
+	}
+	s.startShards(ctx, logger, drainTo)
+}
+
+func (s *walShards) runningShards() []*walShard {
+	if shards := s.shards.Load(); shards != nil {
+		return *shards
+	}
+	return nil
+}
+
+// runShard reads the WAL entries from the index of the shard, and sends the time series assigned to the shard
+// once bufferSize entries were read, or every flushFrequency. The requests that failed to be sent are retried
+// every flushFrequency, and the shard doesn't read new entries until they are sent.
+func (s *walShards) runShard(ctx context.Context, logger *zap.Logger, shard *walShard) {
+	defer s.wg.Done()
+	defer close(shard.done)
+
+	ticker := time.NewTicker(s.flushFrequency)
+	defer ticker.Stop()
+
+	var (
+		batch  shardBatch
+		failed bool
+		paused bool
+		resume <-chan struct{}
+		// The shard reads the entries before limit, which is only set when resharding.
+		limit uint64 = math.MaxUint64
+	)
+	pause := shard.pause
+	onPause := func() {
+		pause, resume, paused = nil, shard.resume, true
+		close(shard.paused)
+	}
+	index := shard.next.Load()
+	for {
+		drained := index >= limit
+		if !failed && (batch.entries >= s.bufferSize || (drained && batch.entries > 0)) {
+			failed = !s.flush(ctx, logger, shard, &batch, index)
+		}
+		if drained && !failed {
+			return
+		}
+
+		select {
+		case <-pause:
+			onPause()
+		default:
+		}
+
+		var written <-chan struct{}
+		if !failed && !drained && !paused {
+			// Wait for the entry to be written if it can't be read yet.
+			written = s.entries.writeNotify()
+			req, err := s.entries.readPrompbFromWAL(index)
+			if err == nil {
+				batch.add(req, shard)
+				index++
+				shard.next.Store(index)
+				continue
+			}
+			if !errors.Is(err, wal.ErrNotFound) {
+				logger.Error("error reading WAL entry", zap.Int("shard", shard.id), zap.Uint64("index", index), zap.Error(err))
+				written = nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.stopCh:
+			// Send what was read, the entries are read again on restart if it fails.
+			s.flush(ctx, logger, shard, &batch, index)
+			return
+		case <-pause:
+			onPause()
+		case <-resume:
+			resume, paused, limit = nil, false, shard.drainTo
+		case <-written:
+		case <-ticker.C:
+			failed = !s.flush(ctx, logger, shard, &batch, index)
+		}
+	}
+}
+
+// flush sends the requests of the batch one at a time, so that the samples of each series are sent in order.
+// The requests that were sent are removed from the batch, so that only the ones that failed are sent again.
+// Once all the requests are sent, the entries up to next are marked as sent by the shard.
+func (s *walShards) flush(ctx context.Context, logger *zap.Logger, shard *walShard, batch *shardBatch, next uint64) bool {
+	if batch.entries == 0 {
+		return true
+	}
+
+	start := time.Now()
+	sent := 0
+	var err error
+	for _, req := range batch.reqL {
+		if err = s.exportSink(ctx, []*prompb.WriteRequest{req}); err != nil {
+			break
+		}
+		sent++
+	}
+	s.recordShardSend(batch.reqL[:sent], time.Since(start))
+	batch.reqL = batch.reqL[sent:]
+	if err != nil {
+		logger.Error("error sending WAL entries", zap.Int("shard", shard.id), zap.Int("requests", len(batch.reqL)), zap.Error(err))
+		return false
+	}
+
+	if shard.id == 0 {
+		// All the shards read the same entries, so only the first one records them.
+		s.recordEntriesSent(batch.entries, batch.samples)
+	}
+	*batch = shardBatch{maxSize: batch.maxSize}
+	shard.sent.Store(next)
+	if err := s.truncate(); err != nil {
+		logger.Error("error truncating WAL", zap.Error(err))
+	}
+	return true
+}
+
+// truncate removes the WAL entries sent by all the shards.
+func (s *walShards) truncate() error {
+	var first uint64
+	for i, shard := range s.runningShards() {
+		if sent := shard.sent.Load(); i == 0 || sent < first {
+			first = sent
+		}
+	}
+	if first == 0 {
+		return nil
+	}
+	return s.entries.syncAndTruncateFront(first)
+}
+
+// stop stops the running shards once they sent the entries they read.
+func (s *walShards) stop() {
+	s.stopOnce.Do(func() {
+		close(s.stopCh)
+	})
+	// Wait for a resharding in progress, which doesn't start shards once stopped.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.wg.Wait()
+}
+
+// add adds the time series of the request assigned to the shard to the batch. The time series are merged into
+// requests no bigger than the biggest of the entries, which were already batched to respect the maximum batch size.
+func (b *shardBatch) add(req *prompb.WriteRequest, shard *walShard) {
+	b.entries++
+	b.samples += countSamples(req)
+	b.maxSize = max(b.maxSize, req.Size())
+	for i := range req.Timeseries {
+		ts := req.Timeseries[i]
+		if int(seriesHash(ts.Labels)%uint64(shard.numShards)) != shard.id {
+			continue
+		}
+		size := ts.Size()
+		if len(b.reqL) == 0 || b.size+size > b.maxSize {
+			b.reqL = append(b.reqL, &prompb.WriteRequest{})
+			b.size = 0
+		}
+		last := b.reqL[len(b.reqL)-1]
+		last.Timeseries = append(last.Timeseries, ts)
+		b.size += size
+	}
+	if len(req.Metadata) > 0 && shard.id == 0 {
+		// The metadata isn't tied to a series, so it is always sent by the first shard in its own request.
+		b.reqL = append(b.reqL, &prompb.WriteRequest{Metadata: req.Metadata})
+		b.size = b.maxSize
+	}
+}
+
+func (s *walShards) recordShardSend(reqL []*prompb.WriteRequest, duration time.Duration) {
+	s.sendDuration.incr(int64(duration))
+	if len(reqL) == 0 {
+		return
+	}
+	samples := 0
+	for _, req := range reqL {
+		samples += countSamples(req)
+	}
+	s.samplesOut.incr(int64(samples))
+	s.lastSendTimestamp.Store(time.Now().UnixNano())
+}
+
+func (s *walShards) recordEntriesSent(entries, samples int) {
+	s.entriesSent.Add(int64(entries))
+	s.samplesSent.Add(int64(samples))
+}
+
+// updateShards updates the moving averages of the rates and recomputes the number of shards.
+// It returns the previous and the new number of shards.
+func (s *walShards) updateShards(ctx context.Context, pendingEntries uint64) (int, int) {
+	s.samplesIn.tick()
+	s.samplesOut.tick()
+	s.sendDuration.tick()
+
+	current := int(s.numShards.Load())
+	// Don't reshard while the endpoint isn't accepting the requests, adding shards would only add load to it.
+	if time.Since(time.Unix(0, s.lastSendTimestamp.Load())) > 2*s.reshardFrequency {
+		return current, current
+	}
+
+	var pendingSamples float64
+	if entries := s.entriesSent.Load(); entries > 0 {
+		pendingSamples = float64(pendingEntries) * float64(s.samplesSent.Load()) / float64(entries)
+	}
+
+	desired := desiredShards(current, s.samplesIn.rate(), s.samplesOut.rate(), s.sendDuration.rate(),
+		pendingSamples, s.reshardFrequency, s.minShards, s.maxShards)
+	if desired != current {
+		s.numShards.Store(int64(desired))
+		s.telemetry.recordWALShards(ctx, int64(desired-current))
+	}
+	return current, desired
+}
+
+// desiredShards computes the number of shards needed to keep up with the samples written to the WAL
+// and to catch up with the backlog, given the time a shard spends sending a sample.
+//
+// The rates are per second, and sendDurationRate is the number of nanoseconds spent sending per second.
+func desiredShards(current int, samplesInRate, samplesOutRate, sendDurationRate, pendingSamples float64,
+	reshardFrequency time.Duration, minShards, maxShards int,
+) int {
+	if samplesOutRate <= 0 {
+		return current
+	}
+
+	timePerSample := sendDurationRate / float64(time.Second) / samplesOutRate
+	catchUpRate := backlogCatchUpFraction / reshardFrequency.Seconds() * pendingSamples
+	desired := math.Ceil(timePerSample * (samplesInRate + catchUpRate))
+
+	lowerBound := float64(current) * (1 - shardToleranceFraction)
+	upperBound := float64(current) * (1 + shardToleranceFraction)
+	if lowerBound <= desired && desired <= upperBound {
+		return current
+	}
+	return min(max(int(desired), minShards), maxShards)
+}
+
+// seriesHash hashes the labels of a time series to pick its shard.
+func seriesHash(labels []prompb.Label) uint64 {
+	h := fnv.New64a()
+	for _, l := range labels {
+		_, _ = h.Write([]byte(l.Name))
+		_, _ = h.Write([]byte{0xff})
+		_, _ = h.Write([]byte(l.Value))
+		_, _ = h.Write([]byte{0xff})
+	}
+	return h.Sum64()
+}
+
+func countSamples(req *prompb.WriteRequest) int {
+	samples := 0
+	for i := range req.Timeseries {
+		samples += len(req.Timeseries[i].Samples) + len(req.Timeseries[i].Histograms)
+	}
+	return samples
+}
@@ -0,0 +1,517 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewriteexporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/wal"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata/metricdatatest"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/prometheusremotewriteexporter/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/prometheusremotewriteexporter/internal/metadatatest"
+)
+
+// recordingExportSink records the samples of the exported time series, in the order they were exported.
+type recordingExportSink struct {
+	mu      sync.Mutex
+	calls   int
+	delay   time.Duration
+	samples map[string][]int64
+}
+
+func newRecordingExportSink(delay time.Duration) *recordingExportSink {
+	return &recordingExportSink{delay: delay, samples: map[string][]int64{}}
+}
+
+func (r *recordingExportSink) export(_ context.Context, reqL []*prompb.WriteRequest) error {
+	time.Sleep(r.delay)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls++
+	for _, req := range reqL {
+		for _, ts := range req.Timeseries {
+			key := ts.Labels[0].Value
+			for _, s := range ts.Samples {
+				r.samples[key] = append(r.samples[key], s.Timestamp)
+			}
+		}
+	}
+	return nil
+}
+
+func (r *recordingExportSink) numSamples() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for _, samples := range r.samples {
+		n += len(samples)
+	}
+	return n
+}
+
+// memWAL is an in-memory WAL read by the shards.
+type memWAL struct {
+	mu        sync.Mutex
+	entries   []*prompb.WriteRequest
+	notify    chan struct{}
+	truncated uint64
+}
+
+func newMemWAL() *memWAL {
+	return &memWAL{notify: make(chan struct{})}
+}
+
+func (m *memWAL) write(reqL []*prompb.WriteRequest) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries = append(m.entries, reqL...)
+	close(m.notify)
+	m.notify = make(chan struct{})
+}
+
+func (m *memWAL) readPrompbFromWAL(index uint64) (*prompb.WriteRequest, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if index == 0 || index > uint64(len(m.entries)) {
+		return nil, wal.ErrNotFound
+	}
+	return m.entries[index-1], nil
+}
+
+func (m *memWAL) writeNotify() <-chan struct{} {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.notify
+}
+
+func (m *memWAL) syncAndTruncateFront(index uint64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.truncated = max(m.truncated, index)
+	return nil
+}
+
+func (m *memWAL) truncatedIndex() uint64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.truncated
+}
+
+// startWALShards starts shards reading the in-memory WAL from its first entry.
+func startWALShards(t *testing.T, config *WALConfig, entries walEntries, exportSink func(context.Context, []*prompb.WriteRequest) error) *walShards {
+	telemetry, err := newPRWWalTelemetry(exportertest.NewNopSettings(metadata.Type))
+	require.NoError(t, err)
+	s := newWALShards(config, entries, exportSink, telemetry)
+	s.start(context.Background(), zap.NewNop(), 1)
+	t.Cleanup(s.stop)
+	return s
+}
+
+// writeRequests creates numRequests requests, each with a sample for each of the numSeries series.
+// The timestamp of the samples is the index of the request.
+func writeRequests(numRequests, numSeries int) []*prompb.WriteRequest {
+	reqL := make([]*prompb.WriteRequest, 0, numRequests)
+	for i := 0; i < numRequests; i++ {
+		req := &prompb.WriteRequest{}
+		for j := 0; j < numSeries; j++ {
+			req.Timeseries = append(req.Timeseries, prompb.TimeSeries{
+				Labels:  []prompb.Label{{Name: "__name__", Value: fmt.Sprintf("series_%d", j)}},
+				Samples: []prompb.Sample{{Value: float64(i), Timestamp: int64(i)}},
+			})
+		}
+		reqL = append(reqL, req)
+	}
+	return reqL
+}
+
+func assertSamplesInOrder(t *testing.T, sink *recordingExportSink, numRequests, numSeries int) {
+	t.Helper()
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	require.Len(t, sink.samples, numSeries)
+	for series, timestamps := range sink.samples {
+		require.Len(t, timestamps, numRequests, series)
+		for i, ts := range timestamps {
+			assert.Equal(t, int64(i), ts, series)
+		}
+	}
+}
+
+func TestDesiredShards(t *testing.T) {
+	for _, tc := range []struct {
+		name             string
+		current          int
+		samplesInRate    float64
+		samplesOutRate   float64
+		sendDurationRate float64
+		pendingSamples   float64
+		minShards        int
+		maxShards        int
+		expected         int
+	}{
+		{
+			name:          "nothing sent",
+			current:       3,
+			samplesInRate: 100,
+			minShards:     1,
+			maxShards:     10,
+			expected:      3,
+		},
+		{
+			name:             "within tolerance",
+			current:          10,
+			samplesInRate:    44,
+			samplesOutRate:   4,
+			sendDurationRate: float64(time.Second),
+			minShards:        1,
+			maxShards:        50,
+			expected:         10,
+		},
+		{
+			name:             "scale up",
+			current:          1,
+			samplesInRate:    20,
+			samplesOutRate:   4,
+			sendDurationRate: float64(time.Second),
+			minShards:        1,
+			maxShards:        50,
+			expected:         5,
+		},
+		{
+			name:             "scale down",
+			current:          10,
+			samplesInRate:    8,
+			samplesOutRate:   4,
+			sendDurationRate: float64(time.Second),
+			minShards:        1,
+			maxShards:        50,
+			expected:         2,
+		},
+		{
+			name:             "scale up to catch up with the backlog",
+			current:          1,
+			samplesInRate:    1,
+			samplesOutRate:   4,
+			sendDurationRate: float64(time.Second),
+			pendingSamples:   1798,
+			minShards:        1,
+			maxShards:        100,
+			expected:         46,
+		},
+		{
+			name:             "limited to min shards",
+			current:          10,
+			samplesOutRate:   4,
+			sendDurationRate: float64(time.Second),
+			minShards:        2,
+			maxShards:        50,
+			expected:         2,
+		},
+		{
+			name:             "limited to max shards",
+			current:          1,
+			samplesInRate:    400,
+			samplesOutRate:   4,
+			sendDurationRate: float64(time.Second),
+			minShards:        1,
+			maxShards:        50,
+			expected:         50,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := desiredShards(tc.current, tc.samplesInRate, tc.samplesOutRate, tc.sendDurationRate,
+				tc.pendingSamples, time.Second, tc.minShards, tc.maxShards)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func TestEWMARate(t *testing.T) {
+	r := newEWMARate(time.Second)
+	r.tick()
+	assert.Zero(t, r.rate())
+
+	// The first non-zero rate initializes the average.
+	r.incr(100)
+	r.tick()
+	assert.Equal(t, 100.0, r.rate())
+
+	r.tick()
+	assert.InDelta(t, 80.0, r.rate(), 1e-9)
+}
+
+func TestShardBatch(t *testing.T) {
+	reqL := writeRequests(3, 20)
+	reqL[1].Metadata = []prompb.MetricMetadata{{MetricFamilyName: "series_0", Type: prompb.MetricMetadata_GAUGE}}
+
+	maxSize := 0
+	for _, req := range reqL {
+		maxSize = max(maxSize, req.Size())
+	}
+
+	const numShards = 4
+	seriesShard := map[string]int{}
+	numSeries, numMetadata := 0, 0
+	for id := 0; id < numShards; id++ {
+		var batch shardBatch
+		for _, req := range reqL {
+			batch.add(req, &walShard{id: id, numShards: numShards})
+		}
+		assert.Equal(t, 3, batch.entries)
+		assert.Equal(t, 60, batch.samples)
+
+		for _, req := range batch.reqL {
+			numMetadata += len(req.Metadata)
+			if len(req.Metadata) > 0 {
+				assert.Equal(t, 0, id)
+				assert.Empty(t, req.Timeseries)
+			}
+			size := 0
+			for _, ts := range req.Timeseries {
+				numSeries++
+				size += ts.Size()
+				name := ts.Labels[0].Value
+				if s, ok := seriesShard[name]; ok {
+					assert.Equal(t, s, id, "all the samples of a series must be sent by the same shard")
+				}
+				seriesShard[name] = id
+			}
+			assert.LessOrEqual(t, size, maxSize)
+		}
+	}
+	assert.Equal(t, 60, numSeries)
+	assert.Equal(t, 1, numMetadata)
+	assert.Len(t, seriesShard, 20)
+}
+
+func TestWALShardsSend(t *testing.T) {
+	entries := newMemWAL()
+	entries.write(writeRequests(10, 50))
+
+	sink := newRecordingExportSink(0)
+	s := startWALShards(t, &WALConfig{MinShards: 4, BufferSize: 10}, entries, sink.export)
+
+	assert.Eventually(t, func() bool {
+		return entries.truncatedIndex() == 11
+	}, 10*time.Second, 10*time.Millisecond)
+	assertSamplesInOrder(t, sink, 10, 50)
+	// The requests are sent by each shard one at a time.
+	assert.GreaterOrEqual(t, sink.calls, 4)
+	assert.Equal(t, int64(10), s.entriesSent.Load())
+	assert.Equal(t, int64(500), s.samplesSent.Load())
+}
+
+func TestWALShardsSendSingleShard(t *testing.T) {
+	entries := newMemWAL()
+	entries.write(writeRequests(10, 5))
+
+	sink := newRecordingExportSink(0)
+	s := startWALShards(t, &WALConfig{BufferSize: 10}, entries, sink.export)
+
+	// A single shard sends the requests one at a time, like any other shard.
+	assert.Eventually(t, func() bool {
+		return entries.truncatedIndex() == 11
+	}, 10*time.Second, 10*time.Millisecond)
+	assertSamplesInOrder(t, sink, 10, 5)
+	assert.Equal(t, 10, sink.calls)
+	assert.Len(t, s.runningShards(), 1)
+}
+
+func TestWALShardsSendOnFlushFrequency(t *testing.T) {
+	entries := newMemWAL()
+	sink := newRecordingExportSink(0)
+	startWALShards(t, &WALConfig{MinShards: 2, TruncateFrequency: 10 * time.Millisecond}, entries, sink.export)
+
+	// Fewer entries than the buffer size are sent once the flush frequency elapses.
+	entries.write(writeRequests(5, 10))
+	assert.Eventually(t, func() bool {
+		return entries.truncatedIndex() == 6
+	}, 10*time.Second, 10*time.Millisecond)
+	assertSamplesInOrder(t, sink, 5, 10)
+}
+
+func TestWALShardsReshard(t *testing.T) {
+	entries := newMemWAL()
+	reqL := writeRequests(20, 50)
+	entries.write(reqL[:10])
+
+	sink := newRecordingExportSink(0)
+	s := startWALShards(t, &WALConfig{MinShards: 4, BufferSize: 5, TruncateFrequency: 10 * time.Millisecond}, entries, sink.export)
+	shards := s.runningShards()
+	require.Len(t, shards, 4)
+	assert.Eventually(t, func() bool {
+		return entries.truncatedIndex() == 11
+	}, 10*time.Second, 10*time.Millisecond)
+
+	// The shards are kept while the number of shards doesn't change.
+	s.reshard(context.Background(), zap.NewNop())
+	assert.Equal(t, shards, s.runningShards())
+
+	// The shards are restarted once the number of shards changes, after they sent the entries read by any of them.
+	entries.write(reqL[10:15])
+	s.numShards.Store(2)
+	s.reshard(context.Background(), zap.NewNop())
+	require.Len(t, s.runningShards(), 2)
+	for _, shard := range shards {
+		assert.Equal(t, shards[0].drainTo, shard.sent.Load())
+	}
+
+	// The samples are still sent in order, and exactly once.
+	entries.write(reqL[15:])
+	assert.Eventually(t, func() bool {
+		return entries.truncatedIndex() == 21
+	}, 10*time.Second, 10*time.Millisecond)
+	assertSamplesInOrder(t, sink, 20, 50)
+	assert.Equal(t, int64(20), s.entriesSent.Load())
+}
+
+func TestWALShardsSendFailure(t *testing.T) {
+	entries := newMemWAL()
+	entries.write(writeRequests(10, 20))
+
+	// The requests including the first series fail until the endpoint recovers.
+	var failing atomic.Bool
+	failing.Store(true)
+	var failures atomic.Int64
+	sink := newRecordingExportSink(0)
+	errExport := errors.New("export failed")
+	exportSink := func(ctx context.Context, reqL []*prompb.WriteRequest) error {
+		for _, ts := range reqL[0].Timeseries {
+			if ts.Labels[0].Value == "series_0" && failing.Load() {
+				failures.Add(1)
+				return errExport
+			}
+		}
+		return sink.export(ctx, reqL)
+	}
+
+	config := &WALConfig{MinShards: 4, BufferSize: 10, TruncateFrequency: 10 * time.Millisecond}
+	s := startWALShards(t, config, entries, exportSink)
+	failingShard := int(seriesHash([]prompb.Label{{Name: "__name__", Value: "series_0"}}) % 4)
+
+	// The other shards send their series, while the failing shard keeps retrying its requests.
+	var otherSeries int
+	for i := 0; i < 20; i++ {
+		if int(seriesHash([]prompb.Label{{Name: "__name__", Value: fmt.Sprintf("series_%d", i)}})%4) != failingShard {
+			otherSeries++
+		}
+	}
+	assert.Eventually(t, func() bool {
+		return sink.numSamples() == otherSeries*10 && failures.Load() > 2
+	}, 10*time.Second, 10*time.Millisecond)
+	for id, shard := range s.runningShards() {
+		if id == failingShard {
+			assert.Equal(t, uint64(1), shard.sent.Load())
+		} else {
+			assert.Equal(t, uint64(11), shard.sent.Load())
+		}
+	}
+	// The WAL is only truncated once the entries were sent by all the shards.
+	assert.Equal(t, uint64(1), entries.truncatedIndex())
+
+	// Only the requests of the failing shard are sent again, the samples are sent exactly once.
+	failing.Store(false)
+	assert.Eventually(t, func() bool {
+		return entries.truncatedIndex() == 11
+	}, 10*time.Second, 10*time.Millisecond)
+	assertSamplesInOrder(t, sink, 10, 20)
+}
+
+func TestWALShardsUpdate(t *testing.T) {
+	tel := componenttest.NewTelemetry()
+	t.Cleanup(func() {
+		require.NoError(t, tel.Shutdown(context.Background()))
+	})
+	telemetry, err := newPRWWalTelemetry(metadatatest.NewSettings(tel))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	s := newWALShards(&WALConfig{MaxShards: 10, ReshardFrequency: time.Second}, newMemWAL(), doNothingExportSink, telemetry)
+	metadatatest.AssertEqualExporterPrometheusremotewriteWalShards(t, tel,
+		[]metricdata.DataPoint[int64]{{Value: 1}},
+		metricdatatest.IgnoreTimestamp())
+
+	// Nothing was sent yet, so the number of shards is kept.
+	from, to := s.updateShards(ctx, 0)
+	assert.Equal(t, 1, from)
+	assert.Equal(t, 1, to)
+
+	// Sending a sample takes 250ms, and 20 samples are written per second.
+	s.samplesIn.incr(20)
+	s.samplesOut.incr(4)
+	s.sendDuration.incr(int64(time.Second))
+	s.lastSendTimestamp.Store(time.Now().UnixNano())
+	from, to = s.updateShards(ctx, 0)
+	assert.Equal(t, 1, from)
+	assert.Equal(t, 5, to)
+	metadatatest.AssertEqualExporterPrometheusremotewriteWalShards(t, tel,
+		[]metricdata.DataPoint[int64]{{Value: 5}},
+		metricdatatest.IgnoreTimestamp())
+
+	// A backlog of 1000 entries of a sample each requires more shards than allowed.
+	s.samplesIn.incr(20)
+	s.samplesOut.incr(4)
+	s.sendDuration.incr(int64(time.Second))
+	s.entriesSent.Store(10)
+	s.samplesSent.Store(10)
+	from, to = s.updateShards(ctx, 1000)
+	assert.Equal(t, 5, from)
+	assert.Equal(t, 10, to)
+
+	// The endpoint didn't accept requests recently, so the number of shards is kept.
+	s.lastSendTimestamp.Store(time.Now().Add(-time.Minute).UnixNano())
+	from, to = s.updateShards(ctx, 0)
+	assert.Equal(t, 10, from)
+	assert.Equal(t, 10, to)
+	metadatatest.AssertEqualExporterPrometheusremotewriteWalShards(t, tel,
+		[]metricdata.DataPoint[int64]{{Value: 10}},
+		metricdatatest.IgnoreTimestamp())
+}
+
+func TestWALReplayWithShards(t *testing.T) {
+	const numRequests, numSeries = 100, 20
+
+	sink := newRecordingExportSink(time.Millisecond)
+	config := &WALConfig{
+		Directory:         t.TempDir(),
+		BufferSize:        10,
+		TruncateFrequency: 10 * time.Millisecond,
+		MinShards:         4,
+	}
+	set := exportertest.NewNopSettings(metadata.Type)
+	pwal, err := newWAL(config, set, sink.export)
+	require.NoError(t, err)
+
+	// Fill the WAL before the replay starts, as after an outage of the endpoint.
+	require.NoError(t, pwal.retrieveWALIndices())
+	require.NoError(t, pwal.persistToWAL(writeRequests(numRequests, numSeries)))
+	assert.Equal(t, uint64(numRequests), pwal.pendingEntries())
+
+	require.NoError(t, pwal.run(contextWithLogger(context.Background(), zap.NewNop())))
+	t.Cleanup(func() {
+		assert.NoError(t, pwal.stop())
+	})
+
+	assert.Eventually(t, func() bool {
+		return sink.numSamples() == numRequests*numSeries
+	}, 10*time.Second, 10*time.Millisecond)
+	assertSamplesInOrder(t, sink, numRequests, numSeries)
+	assert.Eventually(t, func() bool {
+		return pwal.pendingEntries() == 0
+	}, 10*time.Second, 10*time.Millisecond)
+}
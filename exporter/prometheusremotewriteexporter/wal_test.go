@@ -143,7 +143,6 @@ func TestWAL_persist(t *testing.T) {
 		},
 	}
 
-	ctx := context.Background()
 	require.NoError(t, pwal.retrieveWALIndices())
 	t.Cleanup(func() {
 		assert.NoError(t, pwal.stop())
@@ -161,7 +160,7 @@ func TestWAL_persist(t *testing.T) {
 
 	var reqLFromWAL []*prompb.WriteRequest
 	for i := start; i <= end; i++ {
-		req, err := pwal.readPrompbFromWAL(ctx, i)
+		req, err := pwal.readPrompbFromWAL(i)
 		require.NoError(t, err)
 		reqLFromWAL = append(reqLFromWAL, req)
 	}
@@ -11,6 +11,7 @@ import (
 	"go.opentelemetry.io/collector/confmap"
 	"go.opentelemetry.io/collector/extension"
 	"go.opentelemetry.io/collector/extension/extensioncapabilities"
+	"go.opentelemetry.io/collector/pipeline"
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
 
@@ -145,6 +146,17 @@ func (hc *healthCheckExtension) ComponentStatusChanged(
 	hc.eventCh <- &eventSourcePair{source: source, event: event}
 }
 
+// PipelineStatus returns the status of the pipeline, aggregated from the status of its components,
+// e.g. for the failover connector to fail over from the pipelines reporting errors. The boolean return
+// value indicates whether or not the pipeline was found.
+func (hc *healthCheckExtension) PipelineStatus(id pipeline.ID) (componentstatus.Status, bool) {
+	st, ok := hc.aggregator.AggregateStatus(status.Scope(id.String()), status.Concise)
+	if !ok {
+		return componentstatus.StatusNone, false
+	}
+	return st.Status(), true
+}
+
 // NotifyConfig implements the extensioncapabilities.ConfigWatcher interface.
 func (hc *healthCheckExtension) NotifyConfig(ctx context.Context, conf *confmap.Conf) error {
 	var err error
@@ -92,6 +92,27 @@ func TestComponentStatus(t *testing.T) {
 	assert.Equal(t, componentstatus.StatusStopping, st.Status())
 }
 
+func TestPipelineStatus(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	ext := newExtension(context.Background(), *cfg, extensiontest.NewNopSettings(extensiontest.NopType))
+
+	traces := testhelpers.NewPipelineMetadata("traces")
+	_, ok := ext.PipelineStatus(traces.PipelineID)
+	assert.False(t, ok)
+
+	testhelpers.SeedAggregator(ext.aggregator, traces.InstanceIDs(), componentstatus.StatusOK)
+	st, ok := ext.PipelineStatus(traces.PipelineID)
+	require.True(t, ok)
+	assert.Equal(t, componentstatus.StatusOK, st)
+
+	ext.aggregator.RecordStatus(traces.ExporterID, componentstatus.NewRecoverableErrorEvent(assert.AnError))
+	st, ok = ext.PipelineStatus(traces.PipelineID)
+	require.True(t, ok)
+	assert.Equal(t, componentstatus.StatusRecoverableError, st)
+
+	require.NoError(t, ext.Shutdown(context.Background()))
+}
+
 func TestNotifyConfig(t *testing.T) {
 	confMap, err := confmaptest.LoadConf(
 		filepath.Join("internal", "http", "testdata", "config.yaml"),
@@ -0,0 +1,376 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package indexbootstrap installs the index templates and the lifecycle policies
+// of the indices written by the Elasticsearch and OpenSearch exporters, so that
+// a fresh cluster can be written to without manual setup.
+package indexbootstrap // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/indexbootstrap"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Flavor is the kind of cluster the templates and policies are installed in.
+type Flavor string
+
+const (
+	// FlavorElasticsearch installs the lifecycle policy with Elasticsearch ILM,
+	// and attaches it to the indices with the index.lifecycle.name setting.
+	FlavorElasticsearch Flavor = "elasticsearch"
+	// FlavorOpenSearch installs the lifecycle policy with OpenSearch ISM,
+	// and attaches it to the indices with an ISM template.
+	FlavorOpenSearch Flavor = "opensearch"
+)
+
+// The kinds of resources installed, used in the logs and errors.
+const (
+	kindLifecyclePolicy   = "lifecycle policy"
+	kindComponentTemplate = "component template"
+	kindIndexTemplate     = "index template"
+)
+
+// managedBy is recorded in the metadata of the installed templates and policies.
+const managedBy = "opentelemetry-collector"
+
+// maxErrorBodySize is the maximum number of bytes of an error response included in the errors.
+const maxErrorBodySize = 1024
+
+// Transport performs the requests to the cluster. It is implemented by the
+// Elasticsearch and OpenSearch clients, which set the address of the nodes.
+type Transport interface {
+	Perform(*http.Request) (*http.Response, error)
+}
+
+// ComponentTemplate is a building block of the index templates,
+// holding the settings and mappings of the indices.
+type ComponentTemplate struct {
+	Name     string
+	Settings map[string]any
+	Mappings map[string]any
+}
+
+// IndexTemplate is a composable index template applied to the indices
+// matching its patterns.
+type IndexTemplate struct {
+	Name          string
+	IndexPatterns []string
+	ComposedOf    []string
+	// DataStream creates data streams for the names matching the patterns, instead of indices.
+	DataStream bool
+	// PriorityOffset is added to the configured priority, so that a template can take
+	// precedence over the other installed templates whose patterns overlap with its own.
+	PriorityOffset int
+}
+
+// Resources are the templates of the indices written by an exporter.
+type Resources struct {
+	// PolicyName is the name of the lifecycle policy of the indices.
+	PolicyName         string
+	ComponentTemplates []ComponentTemplate
+	IndexTemplates     []IndexTemplate
+}
+
+// Bootstrapper installs the templates and policies in a cluster.
+type Bootstrapper struct {
+	transport Transport
+	flavor    Flavor
+	config    Config
+	logger    *zap.Logger
+}
+
+// New creates a Bootstrapper installing the templates and policies with the transport.
+func New(transport Transport, flavor Flavor, cfg Config, logger *zap.Logger) *Bootstrapper {
+	return &Bootstrapper{
+		transport: transport,
+		flavor:    flavor,
+		config:    cfg,
+		logger:    logger,
+	}
+}
+
+// Install installs the lifecycle policy, the component templates and then the index templates,
+// so that the templates and policies an index template refers to exist when it is installed.
+func (b *Bootstrapper) Install(ctx context.Context, res Resources) error {
+	policyName := ""
+	if policy := b.lifecyclePolicy(res); policy != nil {
+		policyName = res.PolicyName
+		if err := b.install(ctx, kindLifecyclePolicy, res.PolicyName, b.policyPath(res.PolicyName), policy); err != nil {
+			return err
+		}
+	}
+
+	for _, ct := range res.ComponentTemplates {
+		template := map[string]any{}
+		if len(ct.Settings) > 0 {
+			template["settings"] = ct.Settings
+		}
+		if len(ct.Mappings) > 0 {
+			template["mappings"] = ct.Mappings
+		}
+		body := map[string]any{
+			"template": template,
+			"_meta":    map[string]any{"managed_by": managedBy},
+		}
+		if err := b.install(ctx, kindComponentTemplate, ct.Name, "/_component_template/"+url.PathEscape(ct.Name), body); err != nil {
+			return err
+		}
+	}
+
+	for _, it := range res.IndexTemplates {
+		body := map[string]any{
+			"index_patterns": it.IndexPatterns,
+			"composed_of":    it.ComposedOf,
+			"priority":       b.config.Priority + it.PriorityOffset,
+			"_meta":          map[string]any{"managed_by": managedBy},
+		}
+		if it.DataStream {
+			body["data_stream"] = map[string]any{}
+		}
+		if policyName != "" && b.flavor == FlavorElasticsearch {
+			body["template"] = map[string]any{
+				"settings": map[string]any{"index.lifecycle.name": policyName},
+			}
+		}
+		if err := b.install(ctx, kindIndexTemplate, it.Name, "/_index_template/"+url.PathEscape(it.Name), body); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lifecyclePolicy returns the body of the lifecycle policy of the resources,
+// or nil if the indices don't need a policy.
+func (b *Bootstrapper) lifecyclePolicy(res Resources) map[string]any {
+	lc := b.config.Lifecycle
+	if !lc.Enabled || res.PolicyName == "" || len(res.IndexTemplates) == 0 {
+		return nil
+	}
+
+	// Only the write index of a data stream can be rolled over without an alias.
+	rollover := lc.RolloverMaxAge > 0 || lc.RolloverMaxPrimaryShardSize != ""
+	for _, it := range res.IndexTemplates {
+		rollover = rollover && it.DataStream
+	}
+	if !rollover && lc.DeleteAfter == 0 {
+		return nil
+	}
+
+	if b.flavor == FlavorOpenSearch {
+		return b.ismPolicy(res, rollover)
+	}
+	return b.ilmPolicy(rollover)
+}
+
+func (b *Bootstrapper) ilmPolicy(rollover bool) map[string]any {
+	lc := b.config.Lifecycle
+	hotActions := map[string]any{}
+	if rollover {
+		conditions := map[string]any{}
+		if lc.RolloverMaxAge > 0 {
+			conditions["max_age"] = formatDuration(lc.RolloverMaxAge)
+		}
+		if lc.RolloverMaxPrimaryShardSize != "" {
+			conditions["max_primary_shard_size"] = lc.RolloverMaxPrimaryShardSize
+		}
+		hotActions["rollover"] = conditions
+	}
+	phases := map[string]any{
+		"hot": map[string]any{"min_age": "0ms", "actions": hotActions},
+	}
+	if lc.DeleteAfter > 0 {
+		phases["delete"] = map[string]any{
+			"min_age": formatDuration(lc.DeleteAfter),
+			"actions": map[string]any{"delete": map[string]any{}},
+		}
+	}
+	return map[string]any{
+		"policy": map[string]any{
+			"phases": phases,
+			"_meta":  map[string]any{"managed_by": managedBy},
+		},
+	}
+}
+
+func (b *Bootstrapper) ismPolicy(res Resources, rollover bool) map[string]any {
+	lc := b.config.Lifecycle
+	hotActions := []any{}
+	if rollover {
+		conditions := map[string]any{}
+		if lc.RolloverMaxAge > 0 {
+			conditions["min_index_age"] = formatDuration(lc.RolloverMaxAge)
+		}
+		if lc.RolloverMaxPrimaryShardSize != "" {
+			conditions["min_primary_shard_size"] = lc.RolloverMaxPrimaryShardSize
+		}
+		hotActions = append(hotActions, map[string]any{"rollover": conditions})
+	}
+	hot := map[string]any{"name": "hot", "actions": hotActions, "transitions": []any{}}
+	states := []any{hot}
+	if lc.DeleteAfter > 0 {
+		// Similarly to ILM, the age of the rolled over indices is counted from their rollover.
+		ageCondition := "min_index_age"
+		if rollover {
+			ageCondition = "min_rollover_age"
+		}
+		hot["transitions"] = []any{map[string]any{
+			"state_name": "delete",
+			"conditions": map[string]any{ageCondition: formatDuration(lc.DeleteAfter)},
+		}}
+		states = append(states, map[string]any{
+			"name":        "delete",
+			"actions":     []any{map[string]any{"delete": map[string]any{}}},
+			"transitions": []any{},
+		})
+	}
+
+	var patterns []string
+	for _, it := range res.IndexTemplates {
+		patterns = append(patterns, it.IndexPatterns...)
+	}
+	return map[string]any{
+		"policy": map[string]any{
+			"description":   "Managed by " + managedBy,
+			"default_state": "hot",
+			"states":        states,
+			"ism_template": []any{map[string]any{
+				"index_patterns": patterns,
+				"priority":       b.config.Priority,
+			}},
+		},
+	}
+}
+
+func (b *Bootstrapper) policyPath(name string) string {
+	if b.flavor == FlavorOpenSearch {
+		return "/_plugins/_ism/policies/" + url.PathEscape(name)
+	}
+	return "/_ilm/policy/" + url.PathEscape(name)
+}
+
+// install creates the resource at the path, or updates it if it exists and overwrite is enabled.
+func (b *Bootstrapper) install(ctx context.Context, kind, name, path string, body map[string]any) error {
+	existing, found, err := b.get(ctx, path)
+	if err != nil {
+		return fmt.Errorf("failed to get %s %q: %w", kind, name, err)
+	}
+
+	query := url.Values{}
+	if found {
+		if !b.config.Overwrite {
+			b.logger.Debug("Skipping the installation of an existing "+kind, zap.String("name", name))
+			return nil
+		}
+		if b.flavor == FlavorOpenSearch && kind == kindLifecyclePolicy {
+			// ISM policies can only be updated with the sequence number and primary term of their current version.
+			var version struct {
+				SeqNo       int64 `json:"_seq_no"`
+				PrimaryTerm int64 `json:"_primary_term"`
+			}
+			if err := json.Unmarshal(existing, &version); err != nil {
+				return fmt.Errorf("failed to decode %s %q: %w", kind, name, err)
+			}
+			query.Set("if_seq_no", strconv.FormatInt(version.SeqNo, 10))
+			query.Set("if_primary_term", strconv.FormatInt(version.PrimaryTerm, 10))
+		}
+	}
+
+	if err := b.put(ctx, path, query, body); err != nil {
+		return fmt.Errorf("failed to install %s %q: %w", kind, name, err)
+	}
+	b.logger.Info("Installed "+kind, zap.String("name", name), zap.Bool("updated", found))
+	return nil
+}
+
+// get returns the body of the resource at the path, and whether it exists.
+func (b *Bootstrapper) get(ctx context.Context, path string) ([]byte, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, path, http.NoBody)
+	if err != nil {
+		return nil, false, err
+	}
+	resp, err := b.transport.Perform(req)
+	if err != nil {
+		return nil, false, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, false, err
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, false, nil
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return respBody, true, nil
+	default:
+		return nil, false, responseError(resp, respBody)
+	}
+}
+
+func (b *Bootstrapper) put(ctx context.Context, path string, query url.Values, body map[string]any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, path, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := b.transport.Perform(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return responseError(resp, respBody)
+	}
+	return nil
+}
+
+func responseError(resp *http.Response, body []byte) error {
+	if len(body) > maxErrorBodySize {
+		body = body[:maxErrorBodySize]
+	}
+	if len(body) == 0 {
+		return errors.New(resp.Status)
+	}
+	return fmt.Errorf("%s: %s", resp.Status, body)
+}
+
+// formatDuration formats the duration with the largest time unit of Elasticsearch
+// and OpenSearch that represents it exactly.
+func formatDuration(d time.Duration) string {
+	switch {
+	case d%(24*time.Hour) == 0:
+		return strconv.FormatInt(int64(d/(24*time.Hour)), 10) + "d"
+	case d%time.Hour == 0:
+		return strconv.FormatInt(int64(d/time.Hour), 10) + "h"
+	case d%time.Minute == 0:
+		return strconv.FormatInt(int64(d/time.Minute), 10) + "m"
+	case d%time.Second == 0:
+		return strconv.FormatInt(int64(d/time.Second), 10) + "s"
+	default:
+		return strconv.FormatInt(d.Milliseconds(), 10) + "ms"
+	}
+}
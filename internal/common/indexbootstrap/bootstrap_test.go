@@ -0,0 +1,271 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package indexbootstrap
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeCluster stores the resources put by the bootstrapper, keyed by path.
+type fakeCluster struct {
+	mu        sync.Mutex
+	resources map[string]map[string]any
+	requests  []string
+	failPath  string
+}
+
+func newFakeCluster(t *testing.T) (*fakeCluster, Transport) {
+	c := &fakeCluster{resources: map[string]map[string]any{}}
+	server := httptest.NewServer(http.HandlerFunc(c.serveHTTP))
+	t.Cleanup(server.Close)
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	return c, &testTransport{url: serverURL, client: server.Client()}
+}
+
+func (c *fakeCluster) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requests = append(c.requests, r.Method+" "+r.URL.RequestURI())
+
+	if r.URL.Path == c.failPath {
+		http.Error(w, `{"error":"failed"}`, http.StatusBadRequest)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		resource, ok := c.resources[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"_seq_no": 7, "_primary_term": 2, "resource": resource})
+	case http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		var resource map[string]any
+		if err := json.Unmarshal(body, &resource); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		c.resources[r.URL.Path] = resource
+		_, _ = w.Write([]byte(`{"acknowledged":true}`))
+	}
+}
+
+func (c *fakeCluster) resource(path string) map[string]any {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.resources[path]
+}
+
+// testTransport sends the requests to the test server, as the clients send them to the cluster nodes.
+type testTransport struct {
+	url    *url.URL
+	client *http.Client
+}
+
+func (tt *testTransport) Perform(req *http.Request) (*http.Response, error) {
+	req.URL.Scheme = tt.url.Scheme
+	req.URL.Host = tt.url.Host
+	return tt.client.Do(req)
+}
+
+func testResources() Resources {
+	return Resources{
+		PolicyName: "otel-logs",
+		ComponentTemplates: []ComponentTemplate{{
+			Name:     "otel-logs@mappings",
+			Mappings: map[string]any{"properties": map[string]any{"@timestamp": map[string]any{"type": "date_nanos"}}},
+		}},
+		IndexTemplates: []IndexTemplate{{
+			Name:          "otel-logs",
+			IndexPatterns: []string{"logs-*.otel-*"},
+			ComposedOf:    []string{"otel-logs@mappings"},
+			DataStream:    true,
+		}},
+	}
+}
+
+func toJSONMap(t *testing.T, s string) map[string]any {
+	var m map[string]any
+	require.NoError(t, json.Unmarshal([]byte(s), &m))
+	return m
+}
+
+func TestInstallElasticsearch(t *testing.T) {
+	cluster, transport := newFakeCluster(t)
+	cfg := NewDefaultConfig()
+	cfg.Lifecycle.DeleteAfter = 90 * 24 * time.Hour
+	b := New(transport, FlavorElasticsearch, cfg, zap.NewNop())
+
+	require.NoError(t, b.Install(context.Background(), testResources()))
+
+	assert.Equal(t, toJSONMap(t, `{
+		"policy": {
+			"phases": {
+				"hot": {"min_age": "0ms", "actions": {"rollover": {"max_age": "30d", "max_primary_shard_size": "50gb"}}},
+				"delete": {"min_age": "90d", "actions": {"delete": {}}}
+			},
+			"_meta": {"managed_by": "opentelemetry-collector"}
+		}
+	}`), cluster.resource("/_ilm/policy/otel-logs"))
+	assert.Equal(t, toJSONMap(t, `{
+		"template": {"mappings": {"properties": {"@timestamp": {"type": "date_nanos"}}}},
+		"_meta": {"managed_by": "opentelemetry-collector"}
+	}`), cluster.resource("/_component_template/otel-logs@mappings"))
+	assert.Equal(t, toJSONMap(t, `{
+		"index_patterns": ["logs-*.otel-*"],
+		"composed_of": ["otel-logs@mappings"],
+		"priority": 120,
+		"data_stream": {},
+		"template": {"settings": {"index.lifecycle.name": "otel-logs"}},
+		"_meta": {"managed_by": "opentelemetry-collector"}
+	}`), cluster.resource("/_index_template/otel-logs"))
+}
+
+func TestInstallOpenSearch(t *testing.T) {
+	cluster, transport := newFakeCluster(t)
+	cfg := NewDefaultConfig()
+	cfg.Lifecycle.RolloverMaxPrimaryShardSize = ""
+	cfg.Lifecycle.RolloverMaxAge = 12 * time.Hour
+	cfg.Lifecycle.DeleteAfter = 7 * 24 * time.Hour
+	b := New(transport, FlavorOpenSearch, cfg, zap.NewNop())
+
+	require.NoError(t, b.Install(context.Background(), testResources()))
+
+	assert.Equal(t, toJSONMap(t, `{
+		"policy": {
+			"description": "Managed by opentelemetry-collector",
+			"default_state": "hot",
+			"states": [
+				{
+					"name": "hot",
+					"actions": [{"rollover": {"min_index_age": "12h"}}],
+					"transitions": [{"state_name": "delete", "conditions": {"min_rollover_age": "7d"}}]
+				},
+				{"name": "delete", "actions": [{"delete": {}}], "transitions": []}
+			],
+			"ism_template": [{"index_patterns": ["logs-*.otel-*"], "priority": 120}]
+		}
+	}`), cluster.resource("/_plugins/_ism/policies/otel-logs"))
+	// The ISM policy is attached by its ISM template, not by the index settings.
+	assert.NotContains(t, cluster.resource("/_index_template/otel-logs"), "template")
+}
+
+func TestInstallPriorityOffset(t *testing.T) {
+	cluster, transport := newFakeCluster(t)
+	res := testResources()
+	res.IndexTemplates = append(res.IndexTemplates, IndexTemplate{
+		Name:           "otel-logs-otel",
+		IndexPatterns:  []string{"logs-*.otel-*"},
+		ComposedOf:     []string{"otel-logs@mappings"},
+		DataStream:     true,
+		PriorityOffset: 1,
+	})
+	res.IndexTemplates[0].IndexPatterns = []string{"logs-*-*"}
+	b := New(transport, FlavorElasticsearch, NewDefaultConfig(), zap.NewNop())
+
+	require.NoError(t, b.Install(context.Background(), res))
+	assert.Equal(t, 120.0, cluster.resource("/_index_template/otel-logs")["priority"])
+	assert.Equal(t, 121.0, cluster.resource("/_index_template/otel-logs-otel")["priority"])
+}
+
+func TestInstallWithoutRollover(t *testing.T) {
+	res := testResources()
+	res.IndexTemplates[0].DataStream = false
+
+	t.Run("no policy", func(t *testing.T) {
+		cluster, transport := newFakeCluster(t)
+		b := New(transport, FlavorElasticsearch, NewDefaultConfig(), zap.NewNop())
+		require.NoError(t, b.Install(context.Background(), res))
+
+		assert.Nil(t, cluster.resource("/_ilm/policy/otel-logs"))
+		assert.NotContains(t, cluster.resource("/_index_template/otel-logs"), "template")
+		assert.NotContains(t, cluster.resource("/_index_template/otel-logs"), "data_stream")
+	})
+
+	t.Run("delete only", func(t *testing.T) {
+		cluster, transport := newFakeCluster(t)
+		cfg := NewDefaultConfig()
+		cfg.Lifecycle.DeleteAfter = 36 * time.Hour
+		b := New(transport, FlavorElasticsearch, cfg, zap.NewNop())
+		require.NoError(t, b.Install(context.Background(), res))
+
+		assert.Equal(t, toJSONMap(t, `{
+			"policy": {
+				"phases": {
+					"hot": {"min_age": "0ms", "actions": {}},
+					"delete": {"min_age": "36h", "actions": {"delete": {}}}
+				},
+				"_meta": {"managed_by": "opentelemetry-collector"}
+			}
+		}`), cluster.resource("/_ilm/policy/otel-logs"))
+	})
+}
+
+func TestInstallExisting(t *testing.T) {
+	t.Run("skip", func(t *testing.T) {
+		cluster, transport := newFakeCluster(t)
+		cluster.resources["/_index_template/otel-logs"] = map[string]any{"custom": true}
+		b := New(transport, FlavorElasticsearch, NewDefaultConfig(), zap.NewNop())
+		require.NoError(t, b.Install(context.Background(), testResources()))
+
+		assert.Equal(t, map[string]any{"custom": true}, cluster.resource("/_index_template/otel-logs"))
+		assert.NotContains(t, cluster.requests, "PUT /_index_template/otel-logs")
+		assert.Contains(t, cluster.requests, "PUT /_component_template/otel-logs@mappings")
+	})
+
+	t.Run("overwrite", func(t *testing.T) {
+		cluster, transport := newFakeCluster(t)
+		cluster.resources["/_index_template/otel-logs"] = map[string]any{"custom": true}
+		cfg := NewDefaultConfig()
+		cfg.Overwrite = true
+		b := New(transport, FlavorElasticsearch, cfg, zap.NewNop())
+		require.NoError(t, b.Install(context.Background(), testResources()))
+
+		assert.NotContains(t, cluster.resource("/_index_template/otel-logs"), "custom")
+	})
+
+	t.Run("overwrite ISM policy", func(t *testing.T) {
+		cluster, transport := newFakeCluster(t)
+		cluster.resources["/_plugins/_ism/policies/otel-logs"] = map[string]any{"custom": true}
+		cfg := NewDefaultConfig()
+		cfg.Overwrite = true
+		b := New(transport, FlavorOpenSearch, cfg, zap.NewNop())
+		require.NoError(t, b.Install(context.Background(), testResources()))
+
+		assert.Contains(t, cluster.requests, "PUT /_plugins/_ism/policies/otel-logs?if_primary_term=2&if_seq_no=7")
+	})
+}
+
+func TestInstallError(t *testing.T) {
+	cluster, transport := newFakeCluster(t)
+	cluster.failPath = "/_component_template/otel-logs@mappings"
+	b := New(transport, FlavorElasticsearch, NewDefaultConfig(), zap.NewNop())
+
+	err := b.Install(context.Background(), testResources())
+	assert.EqualError(t, err, `failed to get component template "otel-logs@mappings": 400 Bad Request: {"error":"failed"}`+"\n")
+	// The index template isn't installed without the component templates it is composed of.
+	assert.Nil(t, cluster.resource("/_index_template/otel-logs"))
+}
+
+func TestFormatDuration(t *testing.T) {
+	assert.Equal(t, "30d", formatDuration(30*24*time.Hour))
+	assert.Equal(t, "36h", formatDuration(36*time.Hour))
+	assert.Equal(t, "90m", formatDuration(90*time.Minute))
+	assert.Equal(t, "45s", formatDuration(45*time.Second))
+	assert.Equal(t, "1500ms", formatDuration(1500*time.Millisecond))
+}
@@ -0,0 +1,95 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package indexbootstrap // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/indexbootstrap"
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+const (
+	defaultPriority                    = 120
+	defaultRolloverMaxAge              = 30 * 24 * time.Hour
+	defaultRolloverMaxPrimaryShardSize = "50gb"
+)
+
+var byteSizeRegexp = regexp.MustCompile(`^[0-9]+(b|kb|mb|gb|tb|pb)$`)
+
+// Config configures the installation of the index templates and the lifecycle
+// policies of the indices written by an exporter when it starts.
+type Config struct {
+	// Enabled installs the index templates and the lifecycle policies on start.
+	Enabled bool `mapstructure:"enabled"`
+
+	// Overwrite updates the templates and policies that already exist.
+	// By default, existing templates and policies are left as they are,
+	// so that they can be customized.
+	Overwrite bool `mapstructure:"overwrite"`
+
+	// Priority is the priority of the installed index templates.
+	// It must be higher than the priority of the templates of the cluster
+	// matching the same indices for the installed templates to apply. The default
+	// priority overrides the built-in Elasticsearch logs-*-*, metrics-*-* and
+	// traces-*-* templates (priority 100), but not the built-in OTel templates
+	// matching logs-*.otel-*, metrics-*.otel-* and traces-*.otel-* (priority 150).
+	Priority int `mapstructure:"priority"`
+
+	// Lifecycle configures the lifecycle policy of the indices.
+	Lifecycle LifecycleConfig `mapstructure:"lifecycle"`
+}
+
+// LifecycleConfig configures the ILM (Elasticsearch) or ISM (OpenSearch)
+// policy managing the indices.
+type LifecycleConfig struct {
+	// Enabled installs a lifecycle policy and attaches it to the indices.
+	Enabled bool `mapstructure:"enabled"`
+
+	// RolloverMaxAge rolls the write index over once it is older than the age.
+	RolloverMaxAge time.Duration `mapstructure:"rollover_max_age"`
+
+	// RolloverMaxPrimaryShardSize rolls the write index over once one of its primary
+	// shards is bigger than the size, e.g. "50gb".
+	RolloverMaxPrimaryShardSize string `mapstructure:"rollover_max_primary_shard_size"`
+
+	// DeleteAfter deletes the indices once they are older than the age, since their rollover.
+	// The indices are never deleted if it is 0.
+	DeleteAfter time.Duration `mapstructure:"delete_after"`
+}
+
+// NewDefaultConfig returns the default bootstrap configuration.
+func NewDefaultConfig() Config {
+	return Config{
+		Priority: defaultPriority,
+		Lifecycle: LifecycleConfig{
+			Enabled:                     true,
+			RolloverMaxAge:              defaultRolloverMaxAge,
+			RolloverMaxPrimaryShardSize: defaultRolloverMaxPrimaryShardSize,
+		},
+	}
+}
+
+// Validate checks the bootstrap configuration.
+func (cfg *Config) Validate() error {
+	if cfg.Priority < 0 {
+		return errors.New("priority should be non-negative")
+	}
+	if !cfg.Lifecycle.Enabled {
+		return nil
+	}
+	if cfg.Lifecycle.RolloverMaxAge < 0 {
+		return errors.New("lifecycle::rollover_max_age should be non-negative")
+	}
+	if cfg.Lifecycle.DeleteAfter < 0 {
+		return errors.New("lifecycle::delete_after should be non-negative")
+	}
+	if size := cfg.Lifecycle.RolloverMaxPrimaryShardSize; size != "" && !byteSizeRegexp.MatchString(size) {
+		return fmt.Errorf("lifecycle::rollover_max_primary_shard_size %q is not a valid byte size, e.g. 50gb", size)
+	}
+	if cfg.Lifecycle.RolloverMaxAge == 0 && cfg.Lifecycle.RolloverMaxPrimaryShardSize == "" && cfg.Lifecycle.DeleteAfter == 0 {
+		return errors.New("lifecycle must define a rollover condition or delete_after")
+	}
+	return nil
+}
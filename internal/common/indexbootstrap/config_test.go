@@ -0,0 +1,82 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package indexbootstrap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigValidate(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		modify func(*Config)
+		err    string
+	}{
+		{
+			name:   "default",
+			modify: func(*Config) {},
+		},
+		{
+			name: "lifecycle disabled",
+			modify: func(cfg *Config) {
+				cfg.Lifecycle = LifecycleConfig{}
+			},
+		},
+		{
+			name: "delete only",
+			modify: func(cfg *Config) {
+				cfg.Lifecycle = LifecycleConfig{Enabled: true, DeleteAfter: 24 * time.Hour}
+			},
+		},
+		{
+			name: "negative priority",
+			modify: func(cfg *Config) {
+				cfg.Priority = -1
+			},
+			err: "priority should be non-negative",
+		},
+		{
+			name: "negative rollover max age",
+			modify: func(cfg *Config) {
+				cfg.Lifecycle.RolloverMaxAge = -time.Hour
+			},
+			err: "lifecycle::rollover_max_age should be non-negative",
+		},
+		{
+			name: "negative delete after",
+			modify: func(cfg *Config) {
+				cfg.Lifecycle.DeleteAfter = -time.Hour
+			},
+			err: "lifecycle::delete_after should be non-negative",
+		},
+		{
+			name: "invalid shard size",
+			modify: func(cfg *Config) {
+				cfg.Lifecycle.RolloverMaxPrimaryShardSize = "50 GiB"
+			},
+			err: `lifecycle::rollover_max_primary_shard_size "50 GiB" is not a valid byte size, e.g. 50gb`,
+		},
+		{
+			name: "no lifecycle actions",
+			modify: func(cfg *Config) {
+				cfg.Lifecycle = LifecycleConfig{Enabled: true}
+			},
+			err: "lifecycle must define a rollover condition or delete_after",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := NewDefaultConfig()
+			tc.modify(&cfg)
+			err := cfg.Validate()
+			if tc.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package indexbootstrap // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/indexbootstrap"
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// keywordIgnoreAbove is the length above which the strings mapped as keywords aren't indexed.
+const keywordIgnoreAbove = 1024
+
+// Mappings returns the mappings of the fields, keyed by their dotted paths,
+// e.g. "status.code". The fields that aren't mapped explicitly are mapped
+// dynamically, the strings being mapped as keywords. An error is returned if
+// a field is also the parent object of other fields, e.g. "a" and "a.b".
+func Mappings(fields map[string]any) (map[string]any, error) {
+	props, err := properties(fields)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{
+		"dynamic_templates": []any{
+			map[string]any{
+				"strings_as_keyword": map[string]any{
+					"match_mapping_type": "string",
+					"mapping":            map[string]any{"type": "keyword", "ignore_above": keywordIgnoreAbove},
+				},
+			},
+		},
+		"properties": props,
+	}, nil
+}
+
+// Field returns the mapping of a field of the type.
+func Field(fieldType string) map[string]any {
+	return map[string]any{"type": fieldType}
+}
+
+// properties converts the mappings of the fields keyed by their dotted paths into nested properties.
+func properties(fields map[string]any) (map[string]any, error) {
+	props := map[string]any{}
+	// The paths are sorted, so that a field is added before the fields it would be the parent object of.
+	for _, path := range slices.Sorted(maps.Keys(fields)) {
+		parent := props
+		keys := strings.Split(path, ".")
+		for i, key := range keys[:len(keys)-1] {
+			if _, ok := parent[key]; !ok {
+				parent[key] = map[string]any{"properties": map[string]any{}}
+			}
+			object, ok := parent[key].(map[string]any)
+			var children map[string]any
+			if ok {
+				children, ok = object["properties"].(map[string]any)
+			}
+			if !ok {
+				return nil, fmt.Errorf("field %q conflicts with field %q, which isn't an object", path, strings.Join(keys[:i+1], "."))
+			}
+			parent = children
+		}
+		parent[keys[len(keys)-1]] = fields[path]
+	}
+	return props, nil
+}
@@ -0,0 +1,57 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package indexbootstrap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMappings(t *testing.T) {
+	mappings, err := Mappings(map[string]any{
+		"@timestamp":     Field("date"),
+		"status.code":    Field("keyword"),
+		"status.message": Field("text"),
+		"a.b.c":          Field("long"),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]any{
+		"@timestamp": map[string]any{"type": "date"},
+		"status": map[string]any{"properties": map[string]any{
+			"code":    map[string]any{"type": "keyword"},
+			"message": map[string]any{"type": "text"},
+		}},
+		"a": map[string]any{"properties": map[string]any{
+			"b": map[string]any{"properties": map[string]any{
+				"c": map[string]any{"type": "long"},
+			}},
+		}},
+	}, mappings["properties"])
+	assert.Len(t, mappings["dynamic_templates"], 1)
+}
+
+func TestMappingsConflict(t *testing.T) {
+	for _, fields := range []map[string]any{
+		{"status": Field("keyword"), "status.code": Field("keyword")},
+		{"status": "keyword", "status.code": Field("keyword")},
+	} {
+		_, err := Mappings(fields)
+		assert.EqualError(t, err, `field "status.code" conflicts with field "status", which isn't an object`)
+	}
+
+	// A field mapped as an object can have fields.
+	mappings, err := Mappings(map[string]any{
+		"status":      map[string]any{"type": "object", "properties": map[string]any{}},
+		"status.code": Field("keyword"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"status": map[string]any{"type": "object", "properties": map[string]any{
+			"code": map[string]any{"type": "keyword"},
+		}},
+	}, mappings["properties"])
+}
@@ -0,0 +1,161 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package parseutils // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/parseutils"
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	cefPrefix = "CEF:"
+
+	// CEFVersionKey and the keys below are the fields of the map returned by ParseCEF.
+	CEFVersionKey            = "version"
+	CEFDeviceVendorKey       = "device_vendor"
+	CEFDeviceProductKey      = "device_product"
+	CEFDeviceVersionKey      = "device_version"
+	CEFDeviceEventClassIDKey = "device_event_class_id"
+	CEFNameKey               = "name"
+	CEFSeverityKey           = "severity"
+	CEFExtensionsKey         = "extensions"
+)
+
+var cefHeaderKeys = []string{
+	CEFVersionKey,
+	CEFDeviceVendorKey,
+	CEFDeviceProductKey,
+	CEFDeviceVersionKey,
+	CEFDeviceEventClassIDKey,
+	CEFNameKey,
+	CEFSeverityKey,
+}
+
+// ParseCEF parses an ArcSight Common Event Format message into a map.
+// The seven header fields are returned as top level keys and the extension
+// key value pairs are returned as a nested map under CEFExtensionsKey.
+// Any content preceding the "CEF:" marker, such as a syslog header, is ignored.
+func ParseCEF(value string) (map[string]any, error) {
+	start := strings.Index(value, cefPrefix)
+	if start < 0 {
+		return nil, fmt.Errorf("value does not contain a %q header", cefPrefix)
+	}
+	value = value[start+len(cefPrefix):]
+
+	fields, rest := splitHeader(value, len(cefHeaderKeys))
+	if len(fields) != len(cefHeaderKeys) {
+		return nil, fmt.Errorf("expected %d header fields, got %d", len(cefHeaderKeys), len(fields))
+	}
+
+	m := make(map[string]any, len(cefHeaderKeys)+1)
+	for i, key := range cefHeaderKeys {
+		m[key] = unescapeHeader(fields[i])
+	}
+
+	extensions, err := parseCEFExtensions(rest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse extensions: %w", err)
+	}
+	m[CEFExtensionsKey] = extensions
+	return m, nil
+}
+
+// splitHeader splits up to n pipe-delimited header fields from value, respecting
+// backslash escapes. The remainder following the n-th pipe is returned separately.
+func splitHeader(value string, n int) ([]string, string) {
+	fields := make([]string, 0, n)
+	escaped := false
+	last := 0
+	for i := 0; i < len(value); i++ {
+		switch {
+		case escaped:
+			escaped = false
+		case value[i] == '\\':
+			escaped = true
+		case value[i] == '|':
+			fields = append(fields, value[last:i])
+			last = i + 1
+			if len(fields) == n {
+				return fields, value[last:]
+			}
+		}
+	}
+	return fields, ""
+}
+
+// unescapeHeader resolves the '\|' and '\\' escape sequences allowed in header fields.
+func unescapeHeader(value string) string {
+	if !strings.Contains(value, `\`) {
+		return value
+	}
+	return strings.NewReplacer(`\\`, `\`, `\|`, `|`).Replace(value)
+}
+
+// unescapeExtension resolves the escape sequences allowed in extension values.
+func unescapeExtension(value string) string {
+	if !strings.Contains(value, `\`) {
+		return value
+	}
+	return strings.NewReplacer(`\\`, `\`, `\=`, `=`, `\n`, "\n", `\r`, "\r").Replace(value)
+}
+
+// parseCEFExtensions parses space separated key=value pairs. Values may contain
+// spaces, so a value ends where the key of the next pair begins.
+func parseCEFExtensions(value string) (map[string]any, error) {
+	m := make(map[string]any)
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return m, nil
+	}
+
+	type pair struct {
+		keyStart int
+		sep      int
+	}
+	var pairs []pair
+	escaped := false
+	for i := 0; i < len(value); i++ {
+		switch {
+		case escaped:
+			escaped = false
+		case value[i] == '\\':
+			escaped = true
+		case value[i] == '=':
+			if len(pairs) == 0 {
+				pairs = append(pairs, pair{keyStart: 0, sep: i})
+				continue
+			}
+			// The key is the word immediately preceding the separator. An '=' that is
+			// not preceded by a space-delimited word belongs to the previous value.
+			prev := pairs[len(pairs)-1]
+			space := strings.LastIndexByte(value[prev.sep+1:i], ' ')
+			if space < 0 {
+				continue
+			}
+			keyStart := prev.sep + 1 + space + 1
+			if keyStart == i {
+				continue
+			}
+			pairs = append(pairs, pair{keyStart: keyStart, sep: i})
+		}
+	}
+
+	if len(pairs) == 0 {
+		return nil, errors.New("no key value pairs found")
+	}
+
+	for i, p := range pairs {
+		key := value[p.keyStart:p.sep]
+		if key == "" || strings.ContainsRune(key, ' ') {
+			return nil, fmt.Errorf("invalid extension key %q", key)
+		}
+		end := len(value)
+		if i+1 < len(pairs) {
+			end = pairs[i+1].keyStart
+		}
+		m[key] = unescapeExtension(strings.TrimRight(value[p.sep+1:end], " "))
+	}
+	return m, nil
+}
@@ -0,0 +1,167 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package parseutils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCEF(t *testing.T) {
+	cases := []struct {
+		name      string
+		input     string
+		expected  map[string]any
+		expectErr string
+	}{
+		{
+			name:  "header only",
+			input: "CEF:0|Security|threatmanager|1.0|100|worm successfully stopped|10|",
+			expected: map[string]any{
+				"version":               "0",
+				"device_vendor":         "Security",
+				"device_product":        "threatmanager",
+				"device_version":        "1.0",
+				"device_event_class_id": "100",
+				"name":                  "worm successfully stopped",
+				"severity":              "10",
+				"extensions":            map[string]any{},
+			},
+		},
+		{
+			name:  "extensions",
+			input: "CEF:0|Security|threatmanager|1.0|100|worm successfully stopped|10|src=10.0.0.1 dst=2.1.2.2 spt=1232",
+			expected: map[string]any{
+				"version":               "0",
+				"device_vendor":         "Security",
+				"device_product":        "threatmanager",
+				"device_version":        "1.0",
+				"device_event_class_id": "100",
+				"name":                  "worm successfully stopped",
+				"severity":              "10",
+				"extensions": map[string]any{
+					"src": "10.0.0.1",
+					"dst": "2.1.2.2",
+					"spt": "1232",
+				},
+			},
+		},
+		{
+			name:  "extension values with spaces",
+			input: "CEF:0|Vendor|Product|1.0|200|Login|Low|suser=bob msg=user logged in from the VPN act=allow",
+			expected: map[string]any{
+				"version":               "0",
+				"device_vendor":         "Vendor",
+				"device_product":        "Product",
+				"device_version":        "1.0",
+				"device_event_class_id": "200",
+				"name":                  "Login",
+				"severity":              "Low",
+				"extensions": map[string]any{
+					"suser": "bob",
+					"msg":   "user logged in from the VPN",
+					"act":   "allow",
+				},
+			},
+		},
+		{
+			name:  "escaped header",
+			input: `CEF:0|security|threat\|manager|1.0|100|detected a \\ in message|10|`,
+			expected: map[string]any{
+				"version":               "0",
+				"device_vendor":         "security",
+				"device_product":        "threat|manager",
+				"device_version":        "1.0",
+				"device_event_class_id": "100",
+				"name":                  `detected a \ in message`,
+				"severity":              "10",
+				"extensions":            map[string]any{},
+			},
+		},
+		{
+			name:  "escaped extensions",
+			input: `CEF:0|security|threatmanager|1.0|100|name|10|msg=a\=b detected\nnext line path=C:\\Windows spt=1`,
+			expected: map[string]any{
+				"version":               "0",
+				"device_vendor":         "security",
+				"device_product":        "threatmanager",
+				"device_version":        "1.0",
+				"device_event_class_id": "100",
+				"name":                  "name",
+				"severity":              "10",
+				"extensions": map[string]any{
+					"msg":  "a=b detected\nnext line",
+					"path": `C:\Windows`,
+					"spt":  "1",
+				},
+			},
+		},
+		{
+			name:  "pipe in extension",
+			input: "CEF:0|Vendor|Product|1.0|200|Name|5|request=/a|b?c=d",
+			expected: map[string]any{
+				"version":               "0",
+				"device_vendor":         "Vendor",
+				"device_product":        "Product",
+				"device_version":        "1.0",
+				"device_event_class_id": "200",
+				"name":                  "Name",
+				"severity":              "5",
+				"extensions": map[string]any{
+					"request": "/a|b?c=d",
+				},
+			},
+		},
+		{
+			name:  "syslog prefix",
+			input: "<134>Feb 14 19:04:54 host CEF:1|Vendor|Product|1.0|200|Name|5|cs1Label=tenant cs1=acme",
+			expected: map[string]any{
+				"version":               "1",
+				"device_vendor":         "Vendor",
+				"device_product":        "Product",
+				"device_version":        "1.0",
+				"device_event_class_id": "200",
+				"name":                  "Name",
+				"severity":              "5",
+				"extensions": map[string]any{
+					"cs1Label": "tenant",
+					"cs1":      "acme",
+				},
+			},
+		},
+		{
+			name:      "missing prefix",
+			input:     "0|Vendor|Product|1.0|200|Name|5|",
+			expectErr: `value does not contain a "CEF:" header`,
+		},
+		{
+			name:      "missing header fields",
+			input:     "CEF:0|Vendor|Product|1.0|200",
+			expectErr: "expected 7 header fields, got 4",
+		},
+		{
+			name:      "invalid extensions",
+			input:     "CEF:0|Vendor|Product|1.0|200|Name|5|no pairs here",
+			expectErr: "failed to parse extensions: no key value pairs found",
+		},
+		{
+			name:      "empty key",
+			input:     "CEF:0|Vendor|Product|1.0|200|Name|5|=value",
+			expectErr: `failed to parse extensions: invalid extension key ""`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := ParseCEF(tc.input)
+			if tc.expectErr != "" {
+				require.EqualError(t, err, tc.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+}
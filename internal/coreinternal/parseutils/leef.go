@@ -0,0 +1,111 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package parseutils // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/parseutils"
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	leefPrefix = "LEEF:"
+
+	// LEEFVersionKey and the keys below are the fields of the map returned by ParseLEEF.
+	LEEFVersionKey        = "version"
+	LEEFVendorKey         = "vendor"
+	LEEFProductNameKey    = "product_name"
+	LEEFProductVersionKey = "product_version"
+	LEEFEventIDKey        = "event_id"
+	LEEFExtensionsKey     = "extensions"
+)
+
+var leefHeaderKeys = []string{
+	LEEFVersionKey,
+	LEEFVendorKey,
+	LEEFProductNameKey,
+	LEEFProductVersionKey,
+	LEEFEventIDKey,
+}
+
+// ParseLEEF parses an IBM QRadar Log Event Extended Format message into a map.
+// Both LEEF 1.0 (tab delimited attributes) and LEEF 2.0 (with an optional custom
+// attribute delimiter) are supported. The header fields are returned as top level
+// keys and the event attributes are returned as a nested map under LEEFExtensionsKey.
+// Any content preceding the "LEEF:" marker, such as a syslog header, is ignored.
+func ParseLEEF(value string) (map[string]any, error) {
+	start := strings.Index(value, leefPrefix)
+	if start < 0 {
+		return nil, fmt.Errorf("value does not contain a %q header", leefPrefix)
+	}
+	value = value[start+len(leefPrefix):]
+
+	fields, rest := splitHeader(value, len(leefHeaderKeys))
+	if len(fields) != len(leefHeaderKeys) {
+		return nil, fmt.Errorf("expected %d header fields, got %d", len(leefHeaderKeys), len(fields))
+	}
+
+	m := make(map[string]any, len(leefHeaderKeys)+1)
+	for i, key := range leefHeaderKeys {
+		m[key] = unescapeHeader(fields[i])
+	}
+
+	delimiter := "\t"
+	if strings.HasPrefix(fields[0], "2") {
+		// LEEF 2.0 adds an optional header field declaring the attribute delimiter.
+		delimField, attrs := splitHeader(rest, 1)
+		if len(delimField) == 1 && !strings.Contains(delimField[0], "=") {
+			d, err := parseLEEFDelimiter(delimField[0])
+			if err != nil {
+				return nil, err
+			}
+			delimiter = d
+			rest = attrs
+		}
+	}
+
+	m[LEEFExtensionsKey] = parseLEEFAttributes(rest, delimiter)
+	return m, nil
+}
+
+// parseLEEFDelimiter parses the delimiter header field, which is either a single
+// character or its hex representation such as "x09" or "0x09". An empty field
+// means the default tab delimiter.
+func parseLEEFDelimiter(value string) (string, error) {
+	switch {
+	case value == "":
+		return "\t", nil
+	case len(value) == 1:
+		return value, nil
+	}
+
+	lower := strings.ToLower(value)
+	hex, found := strings.CutPrefix(lower, "0x")
+	if !found {
+		hex, found = strings.CutPrefix(lower, "x")
+	}
+	if !found {
+		return "", fmt.Errorf("invalid delimiter %q", value)
+	}
+	code, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return "", fmt.Errorf("invalid delimiter %q: %w", value, err)
+	}
+	return string(rune(code)), nil
+}
+
+// parseLEEFAttributes splits key=value pairs on the delimiter. Only the first '='
+// of each pair separates the key from the value.
+func parseLEEFAttributes(value, delimiter string) map[string]any {
+	m := make(map[string]any)
+	for _, pair := range strings.Split(value, delimiter) {
+		key, val, found := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			continue
+		}
+		m[key] = val
+	}
+	return m
+}
@@ -0,0 +1,139 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package parseutils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLEEF(t *testing.T) {
+	cases := []struct {
+		name      string
+		input     string
+		expected  map[string]any
+		expectErr string
+	}{
+		{
+			name:  "leef 1.0",
+			input: "LEEF:1.0|Microsoft|MSExchange|4.0 SP1|15345|src=192.0.2.0\tdst=172.50.123.1\tsev=5\tcat=anomaly",
+			expected: map[string]any{
+				"version":         "1.0",
+				"vendor":          "Microsoft",
+				"product_name":    "MSExchange",
+				"product_version": "4.0 SP1",
+				"event_id":        "15345",
+				"extensions": map[string]any{
+					"src": "192.0.2.0",
+					"dst": "172.50.123.1",
+					"sev": "5",
+					"cat": "anomaly",
+				},
+			},
+		},
+		{
+			name:  "leef 2.0 default delimiter",
+			input: "LEEF:2.0|Lancope|StealthWatch|1.0|41|src=10.0.1.8\tdst=10.0.0.5",
+			expected: map[string]any{
+				"version":         "2.0",
+				"vendor":          "Lancope",
+				"product_name":    "StealthWatch",
+				"product_version": "1.0",
+				"event_id":        "41",
+				"extensions": map[string]any{
+					"src": "10.0.1.8",
+					"dst": "10.0.0.5",
+				},
+			},
+		},
+		{
+			name:  "leef 2.0 character delimiter",
+			input: "LEEF:2.0|Lancope|StealthWatch|1.0|41|^|src=10.0.1.8^dst=10.0.0.5^msg=a=b",
+			expected: map[string]any{
+				"version":         "2.0",
+				"vendor":          "Lancope",
+				"product_name":    "StealthWatch",
+				"product_version": "1.0",
+				"event_id":        "41",
+				"extensions": map[string]any{
+					"src": "10.0.1.8",
+					"dst": "10.0.0.5",
+					"msg": "a=b",
+				},
+			},
+		},
+		{
+			name:  "leef 2.0 hex delimiter",
+			input: "LEEF:2.0|Lancope|StealthWatch|1.0|41|0x7c|src=10.0.1.8|dst=10.0.0.5",
+			expected: map[string]any{
+				"version":         "2.0",
+				"vendor":          "Lancope",
+				"product_name":    "StealthWatch",
+				"product_version": "1.0",
+				"event_id":        "41",
+				"extensions": map[string]any{
+					"src": "10.0.1.8",
+					"dst": "10.0.0.5",
+				},
+			},
+		},
+		{
+			name:  "leef 2.0 empty delimiter",
+			input: "LEEF:2.0|Lancope|StealthWatch|1.0|41||src=10.0.1.8\tdst=10.0.0.5",
+			expected: map[string]any{
+				"version":         "2.0",
+				"vendor":          "Lancope",
+				"product_name":    "StealthWatch",
+				"product_version": "1.0",
+				"event_id":        "41",
+				"extensions": map[string]any{
+					"src": "10.0.1.8",
+					"dst": "10.0.0.5",
+				},
+			},
+		},
+		{
+			name:  "syslog prefix",
+			input: "<13>Jan 18 11:07:53 192.168.1.1 LEEF:1.0|QRadar|QRM|1.0|NEW_PORT_DISCOVERED|src=172.5.6.67",
+			expected: map[string]any{
+				"version":         "1.0",
+				"vendor":          "QRadar",
+				"product_name":    "QRM",
+				"product_version": "1.0",
+				"event_id":        "NEW_PORT_DISCOVERED",
+				"extensions": map[string]any{
+					"src": "172.5.6.67",
+				},
+			},
+		},
+		{
+			name:      "missing prefix",
+			input:     "1.0|QRadar|QRM|1.0|NEW_PORT_DISCOVERED|",
+			expectErr: `value does not contain a "LEEF:" header`,
+		},
+		{
+			name:      "missing header fields",
+			input:     "LEEF:1.0|QRadar|QRM",
+			expectErr: "expected 5 header fields, got 2",
+		},
+		{
+			name:      "invalid delimiter",
+			input:     "LEEF:2.0|QRadar|QRM|1.0|1|tab|src=172.5.6.67",
+			expectErr: `invalid delimiter "tab"`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := ParseLEEF(tc.input)
+			if tc.expectErr != "" {
+				require.EqualError(t, err, tc.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+}
@@ -832,6 +832,32 @@ func Test_e2e_converters(t *testing.T) {
 				m.AppendEmpty().SetStr("value2")
 			},
 		},
+		{
+			statement: `set(attributes["test"], ParseCEF("CEF:0|Security|threatmanager|1.0|100|worm stopped|10|src=10.0.0.1"))`,
+			want: func(tCtx ottllog.TransformContext) {
+				m := tCtx.GetLogRecord().Attributes().PutEmptyMap("test")
+				m.PutStr("version", "0")
+				m.PutStr("device_vendor", "Security")
+				m.PutStr("device_product", "threatmanager")
+				m.PutStr("device_version", "1.0")
+				m.PutStr("device_event_class_id", "100")
+				m.PutStr("name", "worm stopped")
+				m.PutStr("severity", "10")
+				m.PutEmptyMap("extensions").PutStr("src", "10.0.0.1")
+			},
+		},
+		{
+			statement: `set(attributes["test"], ParseLEEF("LEEF:2.0|Lancope|StealthWatch|1.0|41|^|src=10.0.1.8"))`,
+			want: func(tCtx ottllog.TransformContext) {
+				m := tCtx.GetLogRecord().Attributes().PutEmptyMap("test")
+				m.PutStr("version", "2.0")
+				m.PutStr("vendor", "Lancope")
+				m.PutStr("product_name", "StealthWatch")
+				m.PutStr("product_version", "1.0")
+				m.PutStr("event_id", "41")
+				m.PutEmptyMap("extensions").PutStr("src", "10.0.1.8")
+			},
+		},
 		{
 			statement: `set(attributes["test"], ParseKeyValue("k1=v1 k2=v2"))`,
 			want: func(tCtx ottllog.TransformContext) {
@@ -0,0 +1,119 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_parseCEF(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   string
+		expected map[string]any
+	}{
+		{
+			name:   "header only",
+			target: "CEF:0|Security|threatmanager|1.0|100|worm successfully stopped|10|",
+			expected: map[string]any{
+				"version":               "0",
+				"device_vendor":         "Security",
+				"device_product":        "threatmanager",
+				"device_version":        "1.0",
+				"device_event_class_id": "100",
+				"name":                  "worm successfully stopped",
+				"severity":              "10",
+				"extensions":            map[string]any{},
+			},
+		},
+		{
+			name:   "extensions",
+			target: `<134>Feb 14 19:04:54 host CEF:0|Security|threat\|manager|1.0|100|stopped|10|src=10.0.0.1 msg=a\=b c spt=1232`,
+			expected: map[string]any{
+				"version":               "0",
+				"device_vendor":         "Security",
+				"device_product":        "threat|manager",
+				"device_version":        "1.0",
+				"device_event_class_id": "100",
+				"name":                  "stopped",
+				"severity":              "10",
+				"extensions": map[string]any{
+					"src": "10.0.0.1",
+					"msg": "a=b c",
+					"spt": "1232",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := ottl.StandardStringGetter[any]{
+				Getter: func(_ context.Context, _ any) (any, error) {
+					return tt.target, nil
+				},
+			}
+			exprFunc := parseCEF[any](target)
+			result, err := exprFunc(context.Background(), nil)
+			require.NoError(t, err)
+
+			expected := pcommon.NewMap()
+			require.NoError(t, expected.FromRaw(tt.expected))
+			assert.Equal(t, expected.AsRaw(), result.(pcommon.Map).AsRaw())
+		})
+	}
+}
+
+func Test_parseCEF_error(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   string
+		expected string
+	}{
+		{
+			name:     "empty target",
+			target:   "",
+			expected: "cannot parse from empty target",
+		},
+		{
+			name:     "not a CEF message",
+			target:   "hello world",
+			expected: `failed to parse CEF message: value does not contain a "CEF:" header`,
+		},
+		{
+			name:     "missing header fields",
+			target:   "CEF:0|Security|threatmanager",
+			expected: "failed to parse CEF message: expected 7 header fields, got 2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := ottl.StandardStringGetter[any]{
+				Getter: func(_ context.Context, _ any) (any, error) {
+					return tt.target, nil
+				},
+			}
+			exprFunc := parseCEF[any](target)
+			_, err := exprFunc(context.Background(), nil)
+			assert.EqualError(t, err, tt.expected)
+		})
+	}
+}
+
+func Test_parseCEF_bad_target(t *testing.T) {
+	target := ottl.StandardStringGetter[any]{
+		Getter: func(_ context.Context, _ any) (any, error) {
+			return 1, nil
+		},
+	}
+	exprFunc := parseCEF[any](target)
+	_, err := exprFunc(context.Background(), nil)
+	assert.Error(t, err)
+}
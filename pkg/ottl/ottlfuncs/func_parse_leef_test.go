@@ -0,0 +1,106 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_parseLEEF(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   string
+		expected map[string]any
+	}{
+		{
+			name:   "leef 1.0",
+			target: "LEEF:1.0|Microsoft|MSExchange|4.0 SP1|15345|src=192.0.2.0\tdst=172.50.123.1",
+			expected: map[string]any{
+				"version":         "1.0",
+				"vendor":          "Microsoft",
+				"product_name":    "MSExchange",
+				"product_version": "4.0 SP1",
+				"event_id":        "15345",
+				"extensions": map[string]any{
+					"src": "192.0.2.0",
+					"dst": "172.50.123.1",
+				},
+			},
+		},
+		{
+			name:   "leef 2.0 custom delimiter",
+			target: "<13>Jan 18 11:07:53 host LEEF:2.0|Lancope|StealthWatch|1.0|41|x5E|src=10.0.1.8^dst=10.0.0.5",
+			expected: map[string]any{
+				"version":         "2.0",
+				"vendor":          "Lancope",
+				"product_name":    "StealthWatch",
+				"product_version": "1.0",
+				"event_id":        "41",
+				"extensions": map[string]any{
+					"src": "10.0.1.8",
+					"dst": "10.0.0.5",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := ottl.StandardStringGetter[any]{
+				Getter: func(_ context.Context, _ any) (any, error) {
+					return tt.target, nil
+				},
+			}
+			exprFunc := parseLEEF[any](target)
+			result, err := exprFunc(context.Background(), nil)
+			require.NoError(t, err)
+
+			expected := pcommon.NewMap()
+			require.NoError(t, expected.FromRaw(tt.expected))
+			assert.Equal(t, expected.AsRaw(), result.(pcommon.Map).AsRaw())
+		})
+	}
+}
+
+func Test_parseLEEF_error(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   string
+		expected string
+	}{
+		{
+			name:     "empty target",
+			target:   "",
+			expected: "cannot parse from empty target",
+		},
+		{
+			name:     "not a LEEF message",
+			target:   "hello world",
+			expected: `failed to parse LEEF message: value does not contain a "LEEF:" header`,
+		},
+		{
+			name:     "invalid delimiter",
+			target:   "LEEF:2.0|Lancope|StealthWatch|1.0|41|tab|src=10.0.1.8",
+			expected: `failed to parse LEEF message: invalid delimiter "tab"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := ottl.StandardStringGetter[any]{
+				Getter: func(_ context.Context, _ any) (any, error) {
+					return tt.target, nil
+				},
+			}
+			exprFunc := parseLEEF[any](target)
+			_, err := exprFunc(context.Background(), nil)
+			assert.EqualError(t, err, tt.expected)
+		})
+	}
+}
@@ -0,0 +1,217 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package parquet // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/parquet"
+
+import (
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+// timestampType is the type of the timestamps, written with a microsecond precision in UTC.
+var timestampType = &arrow.TimestampType{Unit: arrow.Microsecond, TimeZone: "UTC"}
+
+func microseconds(ts pcommon.Timestamp) arrow.Timestamp {
+	return arrow.Timestamp(int64(ts) / int64(time.Microsecond))
+}
+
+// optionalColumn is a nullable column of the root of the schema.
+type optionalColumn struct {
+	array.Builder
+}
+
+func (c optionalColumn) null() {
+	c.AppendNull()
+}
+
+func (c optionalColumn) string(v string) {
+	c.Builder.(*array.StringBuilder).Append(v)
+}
+
+// nonEmptyString writes the string, or null if it is empty.
+func (c optionalColumn) nonEmptyString(v string) {
+	if v == "" {
+		c.null()
+		return
+	}
+	c.string(v)
+}
+
+func (c optionalColumn) int32(v int32) {
+	c.Builder.(*array.Int32Builder).Append(v)
+}
+
+func (c optionalColumn) int64(v int64) {
+	c.Builder.(*array.Int64Builder).Append(v)
+}
+
+func (c optionalColumn) double(v float64) {
+	c.Builder.(*array.Float64Builder).Append(v)
+}
+
+func (c optionalColumn) boolean(v bool) {
+	c.Builder.(*array.BooleanBuilder).Append(v)
+}
+
+// timestamp writes the timestamp with a microsecond precision, or null if it is unset.
+func (c optionalColumn) timestamp(ts pcommon.Timestamp) {
+	if ts == 0 {
+		c.null()
+		return
+	}
+	c.Builder.(*array.TimestampBuilder).Append(microseconds(ts))
+}
+
+// mapColumn is a nullable map of strings to nullable strings.
+type mapColumn struct {
+	*array.MapBuilder
+}
+
+// attributes writes the attributes, their values being converted to strings.
+func (c mapColumn) attributes(attrs pcommon.Map) {
+	c.Append(true)
+	keys := c.KeyBuilder().(*array.StringBuilder)
+	values := c.ItemBuilder().(*array.StringBuilder)
+	for k, v := range attrs.All() {
+		keys.Append(k)
+		if v.Type() == pcommon.ValueTypeEmpty {
+			values.AppendNull()
+		} else {
+			values.Append(v.AsString())
+		}
+	}
+}
+
+// listColumn is a nullable list of non-nullable elements.
+type listColumn struct {
+	*array.ListBuilder
+}
+
+func (c listColumn) strings(values []string) {
+	c.Append(true)
+	elements := c.ValueBuilder().(*array.StringBuilder)
+	for _, v := range values {
+		elements.Append(v)
+	}
+}
+
+func (c listColumn) timestamps(values []pcommon.Timestamp) {
+	c.Append(true)
+	elements := c.ValueBuilder().(*array.TimestampBuilder)
+	for _, v := range values {
+		elements.Append(microseconds(v))
+	}
+}
+
+func (c listColumn) uint64s(values pcommon.UInt64Slice) {
+	c.Append(true)
+	elements := c.ValueBuilder().(*array.Int64Builder)
+	for _, v := range values.All() {
+		elements.Append(int64(v))
+	}
+}
+
+func (c listColumn) doubles(values pcommon.Float64Slice) {
+	c.Append(true)
+	elements := c.ValueBuilder().(*array.Float64Builder)
+	for _, v := range values.All() {
+		elements.Append(v)
+	}
+}
+
+// table buffers the rows of a row group. Its columns are declared in the order of the schema.
+type table struct {
+	fields   []arrow.Field
+	builders []array.Builder
+	numRows  int64
+}
+
+// endRow ends the current row, writing null to the columns that weren't written to.
+func (t *table) endRow() {
+	t.numRows++
+	for _, b := range t.builders {
+		if int64(b.Len()) < t.numRows {
+			b.AppendNull()
+		}
+	}
+}
+
+// schema returns the Arrow schema of the rows, which the Parquet schema of the file is derived from.
+func (t *table) schema() *arrow.Schema {
+	return arrow.NewSchema(t.fields, nil)
+}
+
+// record returns the rows buffered by the table. It must be released by the caller.
+func (t *table) record() arrow.Record {
+	columns := make([]arrow.Array, len(t.builders))
+	for i, b := range t.builders {
+		columns[i] = b.NewArray()
+		b.Release()
+	}
+	rec := array.NewRecord(t.schema(), columns, t.numRows)
+	for _, c := range columns {
+		c.Release()
+	}
+	return rec
+}
+
+func (t *table) column(name string, dt arrow.DataType) array.Builder {
+	t.fields = append(t.fields, arrow.Field{Name: name, Type: dt, Nullable: true})
+	b := array.NewBuilder(memory.DefaultAllocator, dt)
+	t.builders = append(t.builders, b)
+	return b
+}
+
+func (t *table) string(name string) optionalColumn {
+	return optionalColumn{t.column(name, arrow.BinaryTypes.String)}
+}
+
+func (t *table) int32(name string) optionalColumn {
+	return optionalColumn{t.column(name, arrow.PrimitiveTypes.Int32)}
+}
+
+func (t *table) int64(name string) optionalColumn {
+	return optionalColumn{t.column(name, arrow.PrimitiveTypes.Int64)}
+}
+
+func (t *table) double(name string) optionalColumn {
+	return optionalColumn{t.column(name, arrow.PrimitiveTypes.Float64)}
+}
+
+func (t *table) boolean(name string) optionalColumn {
+	return optionalColumn{t.column(name, arrow.FixedWidthTypes.Boolean)}
+}
+
+func (t *table) timestamp(name string) optionalColumn {
+	return optionalColumn{t.column(name, timestampType)}
+}
+
+// stringMap declares a map column, written with the standard three-level structure of the Parquet maps.
+func (t *table) stringMap(name string) mapColumn {
+	return mapColumn{t.column(name, arrow.MapOf(arrow.BinaryTypes.String, arrow.BinaryTypes.String)).(*array.MapBuilder)}
+}
+
+// list declares a list column, written with the standard three-level structure of the Parquet lists.
+func (t *table) list(name string, element arrow.DataType) listColumn {
+	return listColumn{t.column(name, arrow.ListOfNonNullable(element)).(*array.ListBuilder)}
+}
+
+func (t *table) stringList(name string) listColumn {
+	return t.list(name, arrow.BinaryTypes.String)
+}
+
+func (t *table) int64List(name string) listColumn {
+	return t.list(name, arrow.PrimitiveTypes.Int64)
+}
+
+func (t *table) doubleList(name string) listColumn {
+	return t.list(name, arrow.PrimitiveTypes.Float64)
+}
+
+func (t *table) timestampList(name string) listColumn {
+	return t.list(name, timestampType)
+}
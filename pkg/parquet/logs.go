@@ -0,0 +1,89 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package parquet // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/parquet"
+
+import (
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+const (
+	signalLogs    = "logs"
+	signalTraces  = "traces"
+	signalMetrics = "metrics"
+
+	serviceNameAttribute = "service.name"
+)
+
+// resourceColumns are the columns of the resource and the instrumentation scope of the rows,
+// shared by the schemas of all the signals.
+type resourceColumns struct {
+	serviceName        optionalColumn
+	resourceAttributes mapColumn
+	scopeName          optionalColumn
+	scopeVersion       optionalColumn
+	scopeAttributes    mapColumn
+}
+
+func newResourceColumns(t *table) resourceColumns {
+	return resourceColumns{
+		serviceName:        t.string("service_name"),
+		resourceAttributes: t.stringMap("resource_attributes"),
+		scopeName:          t.string("scope_name"),
+		scopeVersion:       t.string("scope_version"),
+		scopeAttributes:    t.stringMap("scope_attributes"),
+	}
+}
+
+func (c resourceColumns) write(resource pcommon.Resource, scope pcommon.InstrumentationScope) {
+	if serviceName, ok := resource.Attributes().Get(serviceNameAttribute); ok {
+		c.serviceName.string(serviceName.AsString())
+	} else {
+		c.serviceName.null()
+	}
+	c.resourceAttributes.attributes(resource.Attributes())
+	c.scopeName.nonEmptyString(scope.Name())
+	c.scopeVersion.nonEmptyString(scope.Version())
+	c.scopeAttributes.attributes(scope.Attributes())
+}
+
+// logsTable returns the rows of the log records.
+func logsTable(ld plog.Logs) *table {
+	t := &table{}
+	timestamp := t.timestamp("timestamp")
+	observedTimestamp := t.timestamp("observed_timestamp")
+	traceID := t.string("trace_id")
+	spanID := t.string("span_id")
+	flags := t.int32("flags")
+	severityText := t.string("severity_text")
+	severityNumber := t.int32("severity_number")
+	eventName := t.string("event_name")
+	body := t.string("body")
+	attributes := t.stringMap("attributes")
+	resource := newResourceColumns(t)
+
+	for _, rl := range ld.ResourceLogs().All() {
+		for _, sl := range rl.ScopeLogs().All() {
+			for _, lr := range sl.LogRecords().All() {
+				timestamp.timestamp(lr.Timestamp())
+				observedTimestamp.timestamp(lr.ObservedTimestamp())
+				traceID.nonEmptyString(lr.TraceID().String())
+				spanID.nonEmptyString(lr.SpanID().String())
+				flags.int32(int32(lr.Flags()))
+				severityText.nonEmptyString(lr.SeverityText())
+				severityNumber.int32(int32(lr.SeverityNumber()))
+				eventName.nonEmptyString(lr.EventName())
+				if lr.Body().Type() == pcommon.ValueTypeEmpty {
+					body.null()
+				} else {
+					body.string(lr.Body().AsString())
+				}
+				attributes.attributes(lr.Attributes())
+				resource.write(rl.Resource(), sl.Scope())
+				t.endRow()
+			}
+		}
+	}
+	return t
+}
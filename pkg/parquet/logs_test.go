@@ -0,0 +1,108 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package parquet
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+var testTimestamp = time.Date(2025, 5, 1, 12, 30, 0, 123456789, time.UTC)
+
+func TestMarshalLogs(t *testing.T) {
+	ld := plog.NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().PutStr("service.name", "checkout")
+	rl.Resource().Attributes().PutInt("host.cpus", 4)
+	sl := rl.ScopeLogs().AppendEmpty()
+	sl.Scope().SetName("github.com/checkout")
+	sl.Scope().SetVersion("1.2.3")
+
+	lr := sl.LogRecords().AppendEmpty()
+	lr.SetTimestamp(pcommon.NewTimestampFromTime(testTimestamp))
+	lr.SetObservedTimestamp(pcommon.NewTimestampFromTime(testTimestamp.Add(time.Second)))
+	lr.SetTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+	lr.SetSpanID([8]byte{1, 2, 3, 4, 5, 6, 7, 8})
+	lr.SetFlags(plog.DefaultLogRecordFlags.WithIsSampled(true))
+	lr.SetSeverityText("INFO")
+	lr.SetSeverityNumber(plog.SeverityNumberInfo)
+	lr.SetEventName("order.placed")
+	lr.Body().SetStr("order placed")
+	lr.Attributes().PutStr("order.id", "42")
+	lr.Attributes().PutBool("paid", true)
+	lr.Attributes().PutEmpty("empty")
+
+	structured := sl.LogRecords().AppendEmpty()
+	structured.Body().SetEmptyMap().PutStr("message", "structured")
+
+	data, err := Marshaler{}.MarshalLogs(ld)
+	require.NoError(t, err)
+	f := readParquet(t, data)
+	assert.Equal(t, 1, f.numRowGroups)
+	require.Len(t, f.rows, 2)
+
+	resource := map[string]any{
+		"service_name":        "checkout",
+		"resource_attributes": map[string]any{"service.name": "checkout", "host.cpus": "4"},
+		"scope_name":          "github.com/checkout",
+		"scope_version":       "1.2.3",
+		"scope_attributes":    map[string]any{},
+	}
+	assert.Equal(t, withResource(map[string]any{
+		"timestamp":          testTimestamp.UnixMicro(),
+		"observed_timestamp": testTimestamp.Add(time.Second).UnixMicro(),
+		"trace_id":           "0102030405060708090a0b0c0d0e0f10",
+		"span_id":            "0102030405060708",
+		"flags":              int32(1),
+		"severity_text":      "INFO",
+		"severity_number":    int32(plog.SeverityNumberInfo),
+		"event_name":         "order.placed",
+		"body":               "order placed",
+		"attributes":         map[string]any{"order.id": "42", "paid": "true", "empty": nil},
+	}, resource), f.rows[0])
+	assert.Equal(t, withResource(map[string]any{
+		"timestamp":          nil,
+		"observed_timestamp": nil,
+		"trace_id":           nil,
+		"span_id":            nil,
+		"flags":              int32(0),
+		"severity_text":      nil,
+		"severity_number":    int32(0),
+		"event_name":         nil,
+		"body":               `{"message":"structured"}`,
+		"attributes":         map[string]any{},
+	}, resource), f.rows[1])
+}
+
+func TestMarshalLogsManyAttributes(t *testing.T) {
+	ld := plog.NewLogs()
+	lrs := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	attrs := map[string]any{}
+	for i := 0; i < 300; i++ {
+		key := "key" + strconv.Itoa(i)
+		lrs.AppendEmpty().Attributes().PutStr(key, strconv.Itoa(i))
+		lrs.At(0).Attributes().PutStr(key, strconv.Itoa(i))
+		attrs[key] = strconv.Itoa(i)
+	}
+
+	data, err := Marshaler{}.MarshalLogs(ld)
+	require.NoError(t, err)
+	f := readParquet(t, data)
+	require.Len(t, f.rows, 300)
+	assert.Equal(t, attrs, f.rows[0]["attributes"])
+	assert.Equal(t, map[string]any{"key299": "299"}, f.rows[299]["attributes"])
+}
+
+func withResource(row, resource map[string]any) map[string]any {
+	for k, v := range resource {
+		row[k] = v
+	}
+	return row
+}
@@ -0,0 +1,54 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package parquet encodes logs, traces and metrics as Parquet files, with a stable schema per signal
+// which flattens the resources, the instrumentation scopes and the records into a row per log record,
+// span or data point. The attributes are written as maps of strings.
+package parquet // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/parquet"
+
+import (
+	"bytes"
+
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// FileExtension is the extension of the Parquet files.
+const FileExtension = "parquet"
+
+// Marshaler marshals each batch of telemetry into a Parquet file.
+type Marshaler struct{}
+
+var (
+	_ plog.Marshaler    = Marshaler{}
+	_ ptrace.Marshaler  = Marshaler{}
+	_ pmetric.Marshaler = Marshaler{}
+)
+
+// MarshalLogs returns a Parquet file holding a row per log record.
+func (Marshaler) MarshalLogs(ld plog.Logs) ([]byte, error) {
+	return marshal(func(w *Writer) error { return w.WriteLogs(ld) })
+}
+
+// MarshalTraces returns a Parquet file holding a row per span.
+func (Marshaler) MarshalTraces(td ptrace.Traces) ([]byte, error) {
+	return marshal(func(w *Writer) error { return w.WriteTraces(td) })
+}
+
+// MarshalMetrics returns a Parquet file holding a row per data point.
+func (Marshaler) MarshalMetrics(md pmetric.Metrics) ([]byte, error) {
+	return marshal(func(w *Writer) error { return w.WriteMetrics(md) })
+}
+
+func marshal(write func(*Writer) error) ([]byte, error) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	if err := write(w); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,192 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package parquet // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/parquet"
+
+import (
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+// dataPoint is implemented by the data points of all the metric types.
+type dataPoint interface {
+	StartTimestamp() pcommon.Timestamp
+	Timestamp() pcommon.Timestamp
+	Flags() pmetric.DataPointFlags
+	Attributes() pcommon.Map
+}
+
+// metricsColumns are the columns of the data points. The columns that don't apply to the type of
+// a data point are null, e.g. the value columns of histogram data points.
+type metricsColumns struct {
+	metricName        optionalColumn
+	metricDescription optionalColumn
+	metricUnit        optionalColumn
+	metricType        optionalColumn
+	temporality       optionalColumn
+	isMonotonic       optionalColumn
+	startTimestamp    optionalColumn
+	timestamp         optionalColumn
+	flags             optionalColumn
+	attributes        mapColumn
+
+	valueInt    optionalColumn
+	valueDouble optionalColumn
+
+	count          optionalColumn
+	sum            optionalColumn
+	min            optionalColumn
+	max            optionalColumn
+	bucketCounts   listColumn
+	explicitBounds listColumn
+
+	scale                optionalColumn
+	zeroCount            optionalColumn
+	positiveOffset       optionalColumn
+	positiveBucketCounts listColumn
+	negativeOffset       optionalColumn
+	negativeBucketCounts listColumn
+
+	quantiles      listColumn
+	quantileValues listColumn
+
+	resource resourceColumns
+}
+
+// metricsTable returns the rows of the data points of the metrics.
+func metricsTable(md pmetric.Metrics) *table {
+	t := &table{}
+	c := metricsColumns{
+		metricName:           t.string("metric_name"),
+		metricDescription:    t.string("metric_description"),
+		metricUnit:           t.string("metric_unit"),
+		metricType:           t.string("metric_type"),
+		temporality:          t.string("aggregation_temporality"),
+		isMonotonic:          t.boolean("is_monotonic"),
+		startTimestamp:       t.timestamp("start_timestamp"),
+		timestamp:            t.timestamp("timestamp"),
+		flags:                t.int32("flags"),
+		attributes:           t.stringMap("attributes"),
+		valueInt:             t.int64("value_int"),
+		valueDouble:          t.double("value_double"),
+		count:                t.int64("count"),
+		sum:                  t.double("sum"),
+		min:                  t.double("min"),
+		max:                  t.double("max"),
+		bucketCounts:         t.int64List("bucket_counts"),
+		explicitBounds:       t.doubleList("explicit_bounds"),
+		scale:                t.int32("scale"),
+		zeroCount:            t.int64("zero_count"),
+		positiveOffset:       t.int32("positive_offset"),
+		positiveBucketCounts: t.int64List("positive_bucket_counts"),
+		negativeOffset:       t.int32("negative_offset"),
+		negativeBucketCounts: t.int64List("negative_bucket_counts"),
+		quantiles:            t.doubleList("quantiles"),
+		quantileValues:       t.doubleList("quantile_values"),
+		resource:             newResourceColumns(t),
+	}
+
+	for _, rm := range md.ResourceMetrics().All() {
+		for _, sm := range rm.ScopeMetrics().All() {
+			for _, m := range sm.Metrics().All() {
+				c.writeMetric(t, m, rm.Resource(), sm.Scope())
+			}
+		}
+	}
+	return t
+}
+
+// writeMetric writes a row per data point of the metric.
+func (c *metricsColumns) writeMetric(t *table, m pmetric.Metric, resource pcommon.Resource, scope pcommon.InstrumentationScope) {
+	writeRow := func(dp dataPoint) {
+		c.metricName.string(m.Name())
+		c.metricDescription.nonEmptyString(m.Description())
+		c.metricUnit.nonEmptyString(m.Unit())
+		c.metricType.string(m.Type().String())
+		c.startTimestamp.timestamp(dp.StartTimestamp())
+		c.timestamp.timestamp(dp.Timestamp())
+		c.flags.int32(int32(dp.Flags()))
+		c.attributes.attributes(dp.Attributes())
+		c.resource.write(resource, scope)
+		t.endRow()
+	}
+
+	switch m.Type() {
+	case pmetric.MetricTypeGauge:
+		for _, dp := range m.Gauge().DataPoints().All() {
+			c.writeNumberValue(dp)
+			writeRow(dp)
+		}
+	case pmetric.MetricTypeSum:
+		sum := m.Sum()
+		for _, dp := range sum.DataPoints().All() {
+			c.temporality.string(sum.AggregationTemporality().String())
+			c.isMonotonic.boolean(sum.IsMonotonic())
+			c.writeNumberValue(dp)
+			writeRow(dp)
+		}
+	case pmetric.MetricTypeHistogram:
+		histogram := m.Histogram()
+		for _, dp := range histogram.DataPoints().All() {
+			c.temporality.string(histogram.AggregationTemporality().String())
+			c.count.int64(int64(dp.Count()))
+			if dp.HasSum() {
+				c.sum.double(dp.Sum())
+			}
+			if dp.HasMin() {
+				c.min.double(dp.Min())
+			}
+			if dp.HasMax() {
+				c.max.double(dp.Max())
+			}
+			c.bucketCounts.uint64s(dp.BucketCounts())
+			c.explicitBounds.doubles(dp.ExplicitBounds())
+			writeRow(dp)
+		}
+	case pmetric.MetricTypeExponentialHistogram:
+		histogram := m.ExponentialHistogram()
+		for _, dp := range histogram.DataPoints().All() {
+			c.temporality.string(histogram.AggregationTemporality().String())
+			c.count.int64(int64(dp.Count()))
+			if dp.HasSum() {
+				c.sum.double(dp.Sum())
+			}
+			if dp.HasMin() {
+				c.min.double(dp.Min())
+			}
+			if dp.HasMax() {
+				c.max.double(dp.Max())
+			}
+			c.scale.int32(dp.Scale())
+			c.zeroCount.int64(int64(dp.ZeroCount()))
+			c.positiveOffset.int32(dp.Positive().Offset())
+			c.positiveBucketCounts.uint64s(dp.Positive().BucketCounts())
+			c.negativeOffset.int32(dp.Negative().Offset())
+			c.negativeBucketCounts.uint64s(dp.Negative().BucketCounts())
+			writeRow(dp)
+		}
+	case pmetric.MetricTypeSummary:
+		for _, dp := range m.Summary().DataPoints().All() {
+			c.count.int64(int64(dp.Count()))
+			c.sum.double(dp.Sum())
+			quantiles := pcommon.NewFloat64Slice()
+			values := pcommon.NewFloat64Slice()
+			for _, qv := range dp.QuantileValues().All() {
+				quantiles.Append(qv.Quantile())
+				values.Append(qv.Value())
+			}
+			c.quantiles.doubles(quantiles)
+			c.quantileValues.doubles(values)
+			writeRow(dp)
+		}
+	}
+}
+
+func (c *metricsColumns) writeNumberValue(dp pmetric.NumberDataPoint) {
+	switch dp.ValueType() {
+	case pmetric.NumberDataPointValueTypeInt:
+		c.valueInt.int64(dp.IntValue())
+	case pmetric.NumberDataPointValueTypeDouble:
+		c.valueDouble.double(dp.DoubleValue())
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package parquet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+func TestMarshalMetrics(t *testing.T) {
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("service.name", "checkout")
+	metrics := rm.ScopeMetrics().AppendEmpty().Metrics()
+	ts := pcommon.NewTimestampFromTime(testTimestamp)
+
+	gauge := metrics.AppendEmpty()
+	gauge.SetName("cpu.utilization")
+	gauge.SetUnit("1")
+	dp := gauge.SetEmptyGauge().DataPoints().AppendEmpty()
+	dp.SetTimestamp(ts)
+	dp.SetDoubleValue(0.5)
+	dp.Attributes().PutStr("cpu", "0")
+
+	sum := metrics.AppendEmpty()
+	sum.SetName("requests")
+	sum.SetDescription("The number of requests.")
+	sum.SetEmptySum().SetIsMonotonic(true)
+	sum.Sum().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	dp = sum.Sum().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(ts)
+	dp.SetTimestamp(ts)
+	dp.SetIntValue(42)
+
+	histogram := metrics.AppendEmpty()
+	histogram.SetName("latency")
+	histogram.SetEmptyHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+	hdp := histogram.Histogram().DataPoints().AppendEmpty()
+	hdp.SetCount(3)
+	hdp.SetSum(6)
+	hdp.SetMin(1)
+	hdp.BucketCounts().FromRaw([]uint64{1, 2, 0})
+	hdp.ExplicitBounds().FromRaw([]float64{1, 5})
+
+	exponential := metrics.AppendEmpty()
+	exponential.SetName("latency.exponential")
+	exponential.SetEmptyExponentialHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+	edp := exponential.ExponentialHistogram().DataPoints().AppendEmpty()
+	edp.SetCount(4)
+	edp.SetScale(2)
+	edp.SetZeroCount(1)
+	edp.Positive().SetOffset(-1)
+	edp.Positive().BucketCounts().FromRaw([]uint64{1, 2})
+
+	summary := metrics.AppendEmpty()
+	summary.SetName("latency.summary")
+	sdp := summary.SetEmptySummary().DataPoints().AppendEmpty()
+	sdp.SetCount(10)
+	sdp.SetSum(20)
+	qv := sdp.QuantileValues().AppendEmpty()
+	qv.SetQuantile(0.99)
+	qv.SetValue(5)
+
+	data, err := Marshaler{}.MarshalMetrics(md)
+	require.NoError(t, err)
+	f := readParquet(t, data)
+	require.Len(t, f.rows, 5)
+
+	row := func(values map[string]any) map[string]any {
+		r := map[string]any{
+			"metric_description":      nil,
+			"metric_unit":             nil,
+			"aggregation_temporality": nil,
+			"is_monotonic":            nil,
+			"start_timestamp":         nil,
+			"timestamp":               nil,
+			"flags":                   int32(0),
+			"attributes":              map[string]any{},
+			"value_int":               nil,
+			"value_double":            nil,
+			"count":                   nil,
+			"sum":                     nil,
+			"min":                     nil,
+			"max":                     nil,
+			"bucket_counts":           nil,
+			"explicit_bounds":         nil,
+			"scale":                   nil,
+			"zero_count":              nil,
+			"positive_offset":         nil,
+			"positive_bucket_counts":  nil,
+			"negative_offset":         nil,
+			"negative_bucket_counts":  nil,
+			"quantiles":               nil,
+			"quantile_values":         nil,
+			"service_name":            "checkout",
+			"resource_attributes":     map[string]any{"service.name": "checkout"},
+			"scope_name":              nil,
+			"scope_version":           nil,
+			"scope_attributes":        map[string]any{},
+		}
+		return withResource(r, values)
+	}
+	assert.Equal(t, row(map[string]any{
+		"metric_name":  "cpu.utilization",
+		"metric_unit":  "1",
+		"metric_type":  "Gauge",
+		"timestamp":    testTimestamp.UnixMicro(),
+		"attributes":   map[string]any{"cpu": "0"},
+		"value_double": 0.5,
+	}), f.rows[0])
+	assert.Equal(t, row(map[string]any{
+		"metric_name":             "requests",
+		"metric_description":      "The number of requests.",
+		"metric_type":             "Sum",
+		"aggregation_temporality": "Cumulative",
+		"is_monotonic":            true,
+		"start_timestamp":         testTimestamp.UnixMicro(),
+		"timestamp":               testTimestamp.UnixMicro(),
+		"value_int":               int64(42),
+	}), f.rows[1])
+	assert.Equal(t, row(map[string]any{
+		"metric_name":             "latency",
+		"metric_type":             "Histogram",
+		"aggregation_temporality": "Delta",
+		"count":                   int64(3),
+		"sum":                     float64(6),
+		"min":                     float64(1),
+		"bucket_counts":           []any{int64(1), int64(2), int64(0)},
+		"explicit_bounds":         []any{float64(1), float64(5)},
+	}), f.rows[2])
+	assert.Equal(t, row(map[string]any{
+		"metric_name":             "latency.exponential",
+		"metric_type":             "ExponentialHistogram",
+		"aggregation_temporality": "Delta",
+		"count":                   int64(4),
+		"scale":                   int32(2),
+		"zero_count":              int64(1),
+		"positive_offset":         int32(-1),
+		"positive_bucket_counts":  []any{int64(1), int64(2)},
+		"negative_offset":         int32(0),
+		"negative_bucket_counts":  []any{},
+	}), f.rows[3])
+	assert.Equal(t, row(map[string]any{
+		"metric_name":     "latency.summary",
+		"metric_type":     "Summary",
+		"count":           int64(10),
+		"sum":             float64(20),
+		"quantiles":       []any{0.99},
+		"quantile_values": []any{float64(5)},
+	}), f.rows[4])
+}
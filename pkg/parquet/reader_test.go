@@ -0,0 +1,99 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package parquet
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet/file"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	"github.com/apache/arrow-go/v18/parquet/schema"
+	"github.com/stretchr/testify/require"
+)
+
+// parquetFile is the content of a Parquet file read back by readParquet.
+type parquetFile struct {
+	schema       *schema.Schema
+	numRowGroups int
+	// rows are keyed by the names of the fields of the root of the schema. Lists are read as []any,
+	// maps as map[string]any, and timestamps as their number of microseconds.
+	rows []map[string]any
+}
+
+// readParquet reads a file written by Writer with the Parquet reader of Apache Arrow, failing the test
+// if it isn't a valid Parquet file.
+func readParquet(t *testing.T, data []byte) parquetFile {
+	r, err := file.NewParquetReader(bytes.NewReader(data))
+	require.NoError(t, err)
+	defer r.Close()
+
+	f := parquetFile{
+		schema:       r.MetaData().Schema,
+		numRowGroups: r.NumRowGroups(),
+	}
+	fr, err := pqarrow.NewFileReader(r, pqarrow.ArrowReadProperties{}, memory.DefaultAllocator)
+	require.NoError(t, err)
+	tbl, err := fr.ReadTable(context.Background())
+	require.NoError(t, err)
+	defer tbl.Release()
+
+	f.rows = make([]map[string]any, tbl.NumRows())
+	for i := range f.rows {
+		f.rows[i] = map[string]any{}
+	}
+	for i := 0; i < int(tbl.NumCols()); i++ {
+		col := tbl.Column(i)
+		var row int
+		for _, chunk := range col.Data().Chunks() {
+			for j := 0; j < chunk.Len(); j++ {
+				f.rows[row][col.Name()] = arrowValue(t, chunk, j)
+				row++
+			}
+		}
+		require.Len(t, f.rows, row)
+	}
+	return f
+}
+
+func arrowValue(t *testing.T, arr arrow.Array, i int) any {
+	if arr.IsNull(i) {
+		return nil
+	}
+	switch a := arr.(type) {
+	case *array.String:
+		return a.Value(i)
+	case *array.Int32:
+		return a.Value(i)
+	case *array.Int64:
+		return a.Value(i)
+	case *array.Float64:
+		return a.Value(i)
+	case *array.Boolean:
+		return a.Value(i)
+	case *array.Timestamp:
+		return int64(a.Value(i))
+	case *array.Map:
+		// Maps are lists of key-value pairs, so they must be matched first.
+		m := map[string]any{}
+		start, end := a.ValueOffsets(i)
+		for j := int(start); j < int(end); j++ {
+			m[arrowValue(t, a.Keys(), j).(string)] = arrowValue(t, a.Items(), j)
+		}
+		return m
+	case *array.List:
+		values := []any{}
+		start, end := a.ValueOffsets(i)
+		for j := int(start); j < int(end); j++ {
+			values = append(values, arrowValue(t, a.ListValues(), j))
+		}
+		return values
+	}
+	t.Fatalf("unexpected array type %s", arr.DataType())
+	return nil
+}
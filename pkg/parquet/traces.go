@@ -0,0 +1,91 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package parquet // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/parquet"
+
+import (
+	"encoding/json"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// tracesTable returns the rows of the spans. The events and links of the spans are flattened into
+// lists of the same length, e.g. the i-th elements of event_timestamps and event_names belong to
+// the i-th event of the span.
+func tracesTable(td ptrace.Traces) *table {
+	t := &table{}
+	startTimestamp := t.timestamp("start_timestamp")
+	endTimestamp := t.timestamp("end_timestamp")
+	duration := t.int64("duration")
+	traceID := t.string("trace_id")
+	spanID := t.string("span_id")
+	parentSpanID := t.string("parent_span_id")
+	traceState := t.string("trace_state")
+	flags := t.int32("flags")
+	name := t.string("name")
+	kind := t.string("kind")
+	statusCode := t.string("status_code")
+	statusMessage := t.string("status_message")
+	attributes := t.stringMap("attributes")
+	eventTimestamps := t.timestampList("event_timestamps")
+	eventNames := t.stringList("event_names")
+	eventAttributes := t.stringList("event_attributes")
+	linkTraceIDs := t.stringList("link_trace_ids")
+	linkSpanIDs := t.stringList("link_span_ids")
+	resource := newResourceColumns(t)
+
+	var timestamps []pcommon.Timestamp
+	var names, attrs, traceIDs, spanIDs []string
+	for _, rs := range td.ResourceSpans().All() {
+		for _, ss := range rs.ScopeSpans().All() {
+			for _, span := range ss.Spans().All() {
+				startTimestamp.timestamp(span.StartTimestamp())
+				endTimestamp.timestamp(span.EndTimestamp())
+				duration.int64(int64(span.EndTimestamp()) - int64(span.StartTimestamp()))
+				traceID.nonEmptyString(span.TraceID().String())
+				spanID.nonEmptyString(span.SpanID().String())
+				parentSpanID.nonEmptyString(span.ParentSpanID().String())
+				traceState.nonEmptyString(span.TraceState().AsRaw())
+				flags.int32(int32(span.Flags()))
+				name.string(span.Name())
+				kind.string(span.Kind().String())
+				statusCode.string(span.Status().Code().String())
+				statusMessage.nonEmptyString(span.Status().Message())
+				attributes.attributes(span.Attributes())
+
+				timestamps, names, attrs = timestamps[:0], names[:0], attrs[:0]
+				for _, event := range span.Events().All() {
+					timestamps = append(timestamps, event.Timestamp())
+					names = append(names, event.Name())
+					attrs = append(attrs, attributesJSON(event.Attributes()))
+				}
+				eventTimestamps.timestamps(timestamps)
+				eventNames.strings(names)
+				eventAttributes.strings(attrs)
+
+				traceIDs, spanIDs = traceIDs[:0], spanIDs[:0]
+				for _, link := range span.Links().All() {
+					traceIDs = append(traceIDs, link.TraceID().String())
+					spanIDs = append(spanIDs, link.SpanID().String())
+				}
+				linkTraceIDs.strings(traceIDs)
+				linkSpanIDs.strings(spanIDs)
+
+				resource.write(rs.Resource(), ss.Scope())
+				t.endRow()
+			}
+		}
+	}
+	return t
+}
+
+// attributesJSON returns the attributes as a JSON object, keeping the types of their values.
+func attributesJSON(attrs pcommon.Map) string {
+	b, err := json.Marshal(attrs.AsRaw())
+	if err != nil {
+		// The attributes hold values that can't be represented in JSON, e.g. NaN.
+		return "{}"
+	}
+	return string(b)
+}
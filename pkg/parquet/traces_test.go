@@ -0,0 +1,96 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package parquet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+func TestMarshalTraces(t *testing.T) {
+	td := ptrace.NewTraces()
+	ss := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty()
+
+	span := ss.Spans().AppendEmpty()
+	span.SetTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+	span.SetSpanID([8]byte{1, 2, 3, 4, 5, 6, 7, 8})
+	span.SetParentSpanID([8]byte{8, 7, 6, 5, 4, 3, 2, 1})
+	span.TraceState().FromRaw("vendor=value")
+	span.SetName("GET /orders")
+	span.SetKind(ptrace.SpanKindServer)
+	span.SetStartTimestamp(pcommon.NewTimestampFromTime(testTimestamp))
+	span.SetEndTimestamp(pcommon.NewTimestampFromTime(testTimestamp.Add(1500 * time.Nanosecond)))
+	span.Status().SetCode(ptrace.StatusCodeError)
+	span.Status().SetMessage("timeout")
+	span.Attributes().PutInt("http.response.status_code", 504)
+	event := span.Events().AppendEmpty()
+	event.SetName("exception")
+	event.SetTimestamp(pcommon.NewTimestampFromTime(testTimestamp.Add(time.Microsecond)))
+	event.Attributes().PutStr("exception.type", "Timeout")
+	event.Attributes().PutInt("retries", 3)
+	span.Events().AppendEmpty().SetName("retry")
+	link := span.Links().AppendEmpty()
+	link.SetTraceID([16]byte{16, 15, 14, 13, 12, 11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1})
+	link.SetSpanID([8]byte{1, 1, 1, 1, 1, 1, 1, 1})
+
+	ss.Spans().AppendEmpty().SetName("empty")
+
+	data, err := Marshaler{}.MarshalTraces(td)
+	require.NoError(t, err)
+	f := readParquet(t, data)
+	require.Len(t, f.rows, 2)
+
+	resource := map[string]any{
+		"service_name":        nil,
+		"resource_attributes": map[string]any{},
+		"scope_name":          nil,
+		"scope_version":       nil,
+		"scope_attributes":    map[string]any{},
+	}
+	assert.Equal(t, withResource(map[string]any{
+		"start_timestamp":  testTimestamp.UnixMicro(),
+		"end_timestamp":    testTimestamp.Add(1500 * time.Nanosecond).UnixMicro(),
+		"duration":         int64(1500),
+		"trace_id":         "0102030405060708090a0b0c0d0e0f10",
+		"span_id":          "0102030405060708",
+		"parent_span_id":   "0807060504030201",
+		"trace_state":      "vendor=value",
+		"flags":            int32(0),
+		"name":             "GET /orders",
+		"kind":             "Server",
+		"status_code":      "Error",
+		"status_message":   "timeout",
+		"attributes":       map[string]any{"http.response.status_code": "504"},
+		"event_timestamps": []any{testTimestamp.Add(time.Microsecond).UnixMicro(), int64(0)},
+		"event_names":      []any{"exception", "retry"},
+		"event_attributes": []any{`{"exception.type":"Timeout","retries":3}`, `{}`},
+		"link_trace_ids":   []any{"100f0e0d0c0b0a090807060504030201"},
+		"link_span_ids":    []any{"0101010101010101"},
+	}, resource), f.rows[0])
+	assert.Equal(t, withResource(map[string]any{
+		"start_timestamp":  nil,
+		"end_timestamp":    nil,
+		"duration":         int64(0),
+		"trace_id":         nil,
+		"span_id":          nil,
+		"parent_span_id":   nil,
+		"trace_state":      nil,
+		"flags":            int32(0),
+		"name":             "empty",
+		"kind":             "Unspecified",
+		"status_code":      "Unset",
+		"status_message":   nil,
+		"attributes":       map[string]any{},
+		"event_timestamps": []any{},
+		"event_names":      []any{},
+		"event_attributes": []any{},
+		"link_trace_ids":   []any{},
+		"link_span_ids":    []any{},
+	}, resource), f.rows[1])
+}
@@ -0,0 +1,118 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package parquet // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/parquet"
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/compress"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+const createdBy = "opentelemetry-collector"
+
+var errClosed = errors.New("parquet writer is closed")
+
+// countingWriter counts the bytes written to w. It hides the Close method of w, which the
+// Parquet file writer would call once the footer is written.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(b []byte) (int, error) {
+	n, err := c.w.Write(b)
+	c.n += int64(n)
+	return n, err
+}
+
+// Writer writes telemetry to a Parquet file, a row group per batch. The footer of the file,
+// without which it can't be read, is written when the writer is closed.
+//
+// The schema of the file is the schema of the signal of the first batch written to it:
+// a file holds the telemetry of a single signal.
+type Writer struct {
+	w      *countingWriter
+	signal string
+	file   *pqarrow.FileWriter
+	closed bool
+}
+
+// NewWriter returns a writer writing a Parquet file to w. It doesn't close w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: &countingWriter{w: w}}
+}
+
+// WriteLogs writes a row group holding a row per log record.
+func (w *Writer) WriteLogs(ld plog.Logs) error {
+	return w.writeRowGroup(signalLogs, logsTable(ld))
+}
+
+// WriteTraces writes a row group holding a row per span.
+func (w *Writer) WriteTraces(td ptrace.Traces) error {
+	return w.writeRowGroup(signalTraces, tracesTable(td))
+}
+
+// WriteMetrics writes a row group holding a row per data point.
+func (w *Writer) WriteMetrics(md pmetric.Metrics) error {
+	return w.writeRowGroup(signalMetrics, metricsTable(md))
+}
+
+// Size returns the number of bytes written so far, excluding the footer. The pages of the last
+// row group may be written to the file only when the next row group, or the footer, is.
+func (w *Writer) Size() int64 {
+	return w.w.n
+}
+
+// Close writes the footer of the file, if anything was written to it.
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	if w.file == nil {
+		return nil
+	}
+	return w.file.Close()
+}
+
+func (w *Writer) writeRowGroup(signal string, t *table) error {
+	rec := t.record()
+	defer rec.Release()
+	if w.closed {
+		return errClosed
+	}
+	if w.file == nil {
+		props := parquet.NewWriterProperties(
+			parquet.WithCompression(compress.Codecs.Gzip),
+			parquet.WithCreatedBy(createdBy),
+		)
+		file, err := pqarrow.NewFileWriter(rec.Schema(), w.w, props, pqarrow.DefaultWriterProps())
+		if err != nil {
+			return err
+		}
+		w.signal = signal
+		w.file = file
+	} else if w.signal != signal {
+		return fmt.Errorf("cannot write %s to a parquet file holding %s", signal, w.signal)
+	}
+	if rec.NumRows() == 0 {
+		return nil
+	}
+
+	// The previous row group is flushed once the next one is started.
+	w.file.NewRowGroup()
+	for _, c := range rec.Columns() {
+		if err := w.file.WriteColumnData(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
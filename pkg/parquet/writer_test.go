@@ -0,0 +1,100 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package parquet
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/compress"
+	"github.com/apache/arrow-go/v18/parquet/file"
+	"github.com/apache/arrow-go/v18/parquet/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+func testLogs(bodies ...string) plog.Logs {
+	ld := plog.NewLogs()
+	lrs := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	for _, body := range bodies {
+		lrs.AppendEmpty().Body().SetStr(body)
+	}
+	return ld
+}
+
+func TestWriterRowGroups(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	require.NoError(t, w.WriteLogs(testLogs("a", "b")))
+	require.NoError(t, w.WriteLogs(plog.NewLogs()))
+	require.NoError(t, w.WriteLogs(testLogs("c")))
+	assert.Equal(t, int64(buf.Len()), w.Size())
+	require.NoError(t, w.Close())
+
+	f := readParquet(t, buf.Bytes())
+	assert.Equal(t, 2, f.numRowGroups)
+	var bodies []any
+	for _, row := range f.rows {
+		bodies = append(bodies, row["body"])
+	}
+	assert.Equal(t, []any{"a", "b", "c"}, bodies)
+}
+
+func TestWriterSchema(t *testing.T) {
+	data, err := Marshaler{}.MarshalLogs(plog.NewLogs())
+	require.NoError(t, err)
+	f := readParquet(t, data)
+	assert.Equal(t, 0, f.numRowGroups)
+	assert.Empty(t, f.rows)
+
+	// Timestamps are INT64 TIMESTAMP(MICROS, UTC).
+	timestamp := f.schema.Column(f.schema.ColumnIndexByName("timestamp"))
+	assert.Equal(t, parquet.Types.Int64, timestamp.PhysicalType())
+	assert.True(t, timestamp.LogicalType().Equals(schema.NewTimestampLogicalType(true, schema.TimeUnitMicros)))
+
+	// Maps have the standard three-level structure, with required keys and optional values.
+	attributes := f.schema.Root().Field(f.schema.Root().FieldIndexByName("attributes"))
+	assert.Equal(t, parquet.Repetitions.Optional, attributes.RepetitionType())
+	assert.Equal(t, "Map", attributes.LogicalType().String())
+	key := f.schema.Column(f.schema.ColumnIndexByName("attributes.key_value.key"))
+	assert.Equal(t, parquet.Repetitions.Required, key.SchemaNode().RepetitionType())
+	value := f.schema.Column(f.schema.ColumnIndexByName("attributes.key_value.value"))
+	assert.Equal(t, parquet.Repetitions.Optional, value.SchemaNode().RepetitionType())
+}
+
+func TestWriterCompression(t *testing.T) {
+	data, err := Marshaler{}.MarshalLogs(testLogs("a"))
+	require.NoError(t, err)
+	r, err := file.NewParquetReader(bytes.NewReader(data))
+	require.NoError(t, err)
+	defer r.Close()
+	assert.Equal(t, createdBy, r.MetaData().GetCreatedBy())
+	rg := r.MetaData().RowGroup(0)
+	for i := 0; i < rg.NumColumns(); i++ {
+		chunk, err := rg.ColumnChunk(i)
+		require.NoError(t, err)
+		assert.Equal(t, compress.Codecs.Gzip, chunk.Compression())
+	}
+}
+
+func TestWriterSignalMismatch(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	require.NoError(t, w.WriteLogs(testLogs("a")))
+	assert.EqualError(t, w.WriteTraces(ptrace.NewTraces()), "cannot write traces to a parquet file holding logs")
+	require.NoError(t, w.Close())
+	assert.Len(t, readParquet(t, buf.Bytes()).rows, 1)
+}
+
+func TestWriterClose(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	require.NoError(t, w.Close())
+	assert.Zero(t, buf.Len(), "nothing is written to a file without batches")
+	require.NoError(t, w.Close())
+	assert.ErrorIs(t, w.WriteLogs(testLogs("a")), errClosed)
+}
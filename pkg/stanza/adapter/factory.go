@@ -26,6 +26,12 @@ var synchronousLogEmitterFeatureGate = featuregate.GlobalRegistry().MustRegister
 	featuregate.WithRegisterReferenceURL("https://github.com/open-telemetry/opentelemetry-collector-contrib/issues/35456"),
 )
 
+// atLeastOnceInput is implemented by input configs which can hold back their progress
+// until emitted entries have been accepted by the next consumer.
+type atLeastOnceInput interface {
+	AtLeastOnceDelivery() bool
+}
+
 // LogReceiverType is the interface used by stanza-based log receivers
 type LogReceiverType interface {
 	Type() component.Type
@@ -79,9 +85,15 @@ func createLogsReceiver(logReceiverType LogReceiverType) rcvr.CreateLogsFunc {
 		}
 
 		var emitter helper.LogEmitter
-		if synchronousLogEmitterFeatureGate.IsEnabled() {
+		input, isAtLeastOnceInput := inputCfg.Builder.(atLeastOnceInput)
+		switch {
+		case isAtLeastOnceInput && input.AtLeastOnceDelivery():
+			// Entries must be handed to the consumer synchronously so that its result
+			// can be reported back to the input before progress is committed.
+			emitter = helper.NewAckingLogEmitter(params.TelemetrySettings, rcv.consumeEntriesWithAck)
+		case synchronousLogEmitterFeatureGate.IsEnabled():
 			emitter = helper.NewSynchronousLogEmitter(params.TelemetrySettings, rcv.consumeEntries)
-		} else {
+		default:
 			emitter = helper.NewBatchingLogEmitter(params.TelemetrySettings, rcv.consumeEntries, emitterOpts...)
 		}
 
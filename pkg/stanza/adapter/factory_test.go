@@ -5,6 +5,7 @@ package adapter
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -12,7 +13,10 @@ import (
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/receiver/receivertest"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/helper"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/input/file"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/parser/json"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/parser/regex"
 )
@@ -43,4 +47,22 @@ func TestCreateReceiver(t *testing.T) {
 		require.Error(t, err, "receiver creation should fail if parser configs aren't valid")
 		require.Nil(t, receiver, "receiver creation should fail if parser configs aren't valid")
 	})
+
+	t.Run("AtLeastOnceInputUsesAckingEmitter", func(t *testing.T) {
+		factory := NewFactory(TestReceiverType{}, component.StabilityLevelDevelopment)
+		cfg := factory.CreateDefaultConfig().(*TestConfig)
+		fileCfg := file.NewConfig()
+		fileCfg.Include = []string{"/var/log/*.log"}
+		fileCfg.AtLeastOnce = true
+		cfg.Input = operator.NewConfig(fileCfg)
+
+		consumerErr := errors.New("queue is full")
+		rcv, err := factory.CreateLogs(context.Background(), receivertest.NewNopSettings(factory.Type()), cfg, consumertest.NewErr(consumerErr))
+		require.NoError(t, err, "receiver creation failed")
+
+		// Consumer errors must be reported back to the input.
+		err = rcv.(*receiver).emitter.ProcessBatch(context.Background(), []*entry.Entry{entry.New()})
+		require.ErrorIs(t, err, helper.ErrEntriesNotAccepted)
+		require.ErrorIs(t, err, consumerErr)
+	})
 }
@@ -52,6 +52,12 @@ func (r *receiver) Start(ctx context.Context, host component.Host) error {
 }
 
 func (r *receiver) consumeEntries(ctx context.Context, entries []*entry.Entry) {
+	_ = r.consumeEntriesWithAck(ctx, entries)
+}
+
+// consumeEntriesWithAck is like consumeEntries, but returns the error from the next
+// consumer so that inputs with at-least-once delivery can retry the entries.
+func (r *receiver) consumeEntriesWithAck(ctx context.Context, entries []*entry.Entry) error {
 	obsrecvCtx := r.obsrecv.StartLogsOp(ctx)
 	pLogs := ConvertEntries(entries)
 	logRecordCount := pLogs.LogRecordCount()
@@ -61,6 +67,7 @@ func (r *receiver) consumeEntries(ctx context.Context, entries []*entry.Entry) {
 		r.set.Logger.Error("ConsumeLogs() failed", zap.Error(cErr))
 	}
 	r.obsrecv.EndLogsOp(obsrecvCtx, "stanza", logRecordCount, cErr)
+	return cErr
 }
 
 // Shutdown is invoked during service shutdown
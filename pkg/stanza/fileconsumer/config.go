@@ -90,6 +90,7 @@ type Config struct {
 	Compression             string          `mapstructure:"compression,omitempty"`
 	PollsToArchive          int             `mapstructure:"-"` // TODO: activate this config once archiving is set up
 	AcquireFSLock           bool            `mapstructure:"acquire_fs_lock,omitempty"`
+	AtLeastOnce             bool            `mapstructure:"at_least_once,omitempty"`
 }
 
 type HeaderConfig struct {
@@ -169,6 +170,7 @@ func (c Config) Build(set component.TelemetrySettings, emit emit.Callback, opts
 		IncludeFileRecordNumber: c.IncludeFileRecordNumber,
 		Compression:             c.Compression,
 		AcquireFSLock:           c.AcquireFSLock,
+		AtLeastOnce:             c.AtLeastOnce,
 	}
 
 	telemetryBuilder, err := metadata.NewTelemetryBuilder(set)
@@ -193,6 +195,12 @@ func (c Config) Build(set component.TelemetrySettings, emit emit.Callback, opts
 	}, nil
 }
 
+// AtLeastOnceDelivery reports whether file offsets should only be advanced once
+// emitted entries have been accepted by the consumer.
+func (c Config) AtLeastOnceDelivery() bool {
+	return c.AtLeastOnce
+}
+
 func (c Config) validate() error {
 	if _, err := matcher.New(c.Criteria); err != nil {
 		return err
@@ -41,6 +41,7 @@ func TestNewConfig(t *testing.T) {
 	assert.False(t, cfg.IncludeFileOwnerGroupName)
 	assert.False(t, cfg.IncludeFileRecordNumber)
 	assert.False(t, cfg.AcquireFSLock)
+	assert.False(t, cfg.AtLeastOnce)
 }
 
 func TestUnmarshal(t *testing.T) {
@@ -17,6 +17,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/featuregate"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/attrs"
@@ -789,6 +790,45 @@ func TestRestartOffsets(t *testing.T) {
 	}
 }
 
+// TestAtLeastOnceRestartOffsets checks that offsets of logs which were not accepted by
+// the consumer are not persisted, so the logs are read again after a restart.
+func TestAtLeastOnceRestartOffsets(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().includeDir(tempDir)
+	cfg.StartAt = "beginning"
+	cfg.AtLeastOnce = true
+
+	persister := testutil.NewUnscopedMockPersister()
+
+	logFile := filetest.OpenTemp(t, tempDir)
+	token := filetest.TokenWithLength(20)
+	filetest.WriteString(t, logFile, string(token)+"\n")
+
+	rejected := make(chan struct{}, 1)
+	operatorOne, err := cfg.Build(componenttest.NewNopTelemetrySettings(), func(context.Context, [][]byte, map[string]any, int64) error {
+		select {
+		case rejected <- struct{}{}:
+		default:
+		}
+		return fmt.Errorf("consume entries: %w", helper.ErrEntriesNotAccepted)
+	})
+	require.NoError(t, err)
+	require.NoError(t, operatorOne.Start(persister))
+	select {
+	case <-rejected:
+	case <-time.After(3 * time.Second):
+		require.FailNow(t, "timed out waiting for emit")
+	}
+	require.NoError(t, operatorOne.Stop())
+
+	operatorTwo, sink := testManager(t, cfg)
+	require.NoError(t, operatorTwo.Start(persister))
+	sink.ExpectToken(t, token)
+	require.NoError(t, operatorTwo.Stop())
+}
+
 func TestManyLogsDelivered(t *testing.T) {
 	t.Parallel()
 
@@ -49,6 +49,7 @@ type Factory struct {
 	IncludeFileRecordNumber bool
 	Compression             string
 	AcquireFSLock           bool
+	AtLeastOnce             bool
 }
 
 func (f *Factory) NewFingerprint(file *os.File) (*fingerprint.Fingerprint, error) {
@@ -91,6 +92,7 @@ func (f *Factory) NewReaderFromMetadata(file *os.File, m *Metadata) (r *Reader,
 		deleteAtEOF:       f.DeleteAtEOF,
 		compression:       f.Compression,
 		acquireFSLock:     f.AcquireFSLock,
+		atLeastOnce:       f.AtLeastOnce,
 		maxBatchSize:      DefaultMaxBatchSize,
 		emitFunc:          f.EmitFunc,
 	}
@@ -22,6 +22,7 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/header"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/scanner"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/flush"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/helper"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/tokenlen"
 )
 
@@ -58,6 +59,7 @@ type Reader struct {
 	needsUpdateFingerprint bool
 	compression            string
 	acquireFSLock          bool
+	atLeastOnce            bool
 	maxBatchSize           int
 }
 
@@ -70,6 +72,9 @@ func (r *Reader) ReadToEnd(ctx context.Context) {
 		defer r.unlockFile()
 	}
 
+	// When a batch is not accepted by the consumer, the offset must not move past it.
+	accepted := true
+
 	switch r.compression {
 	case "gzip":
 		currentEOF, err := r.createGzipReader()
@@ -78,8 +83,9 @@ func (r *Reader) ReadToEnd(ctx context.Context) {
 		}
 		// Offset tracking in an uncompressed file is based on the length of emitted tokens, but in this case
 		// we need to set the offset to the end of the file.
+		startOffset, startRecordNum := r.Offset, r.RecordNum
 		defer func() {
-			r.Offset = currentEOF
+			r.setGzipOffset(accepted, currentEOF, startOffset, startRecordNum)
 		}()
 	case "auto":
 		// Identifying a filename by its extension may not always be correct. We could have a compressed file without the .gz extension
@@ -90,8 +96,9 @@ func (r *Reader) ReadToEnd(ctx context.Context) {
 			}
 			// Offset tracking in an uncompressed file is based on the length of emitted tokens, but in this case
 			// we need to set the offset to the end of the file.
+			startOffset, startRecordNum := r.Offset, r.RecordNum
 			defer func() {
-				r.Offset = currentEOF
+				r.setGzipOffset(accepted, currentEOF, startOffset, startRecordNum)
 			}()
 		} else {
 			r.reader = r.file
@@ -117,7 +124,7 @@ func (r *Reader) ReadToEnd(ctx context.Context) {
 		}
 	}
 
-	r.readContents(ctx)
+	accepted = r.readContents(ctx)
 }
 
 // createGzipReader creates gzip reader and returns the file offset
@@ -143,6 +150,19 @@ func (r *Reader) createGzipReader() (int64, error) {
 	return currentEOF, nil
 }
 
+// setGzipOffset sets the offset of a gzip compressed file once it was read. The positions of the emitted tokens
+// are positions in the decompressed data, which cannot be used as offsets in the compressed file. So when a batch
+// was not accepted, the offset and the record number are restored to where reading started, and the data is
+// decompressed and read again from there on the next poll, including the batches that were already accepted.
+func (r *Reader) setGzipOffset(accepted bool, currentEOF, startOffset, startRecordNum int64) {
+	if accepted {
+		r.Offset = currentEOF
+		return
+	}
+	r.Offset = startOffset
+	r.RecordNum = startRecordNum
+}
+
 func (r *Reader) readHeader(ctx context.Context) (doneReadingFile bool) {
 	bufPtr := r.getBufPtrFromPool()
 	defer r.bufPool.Put(bufPtr)
@@ -205,7 +225,10 @@ func (r *Reader) readHeader(ctx context.Context) (doneReadingFile bool) {
 	return false
 }
 
-func (r *Reader) readContents(ctx context.Context) {
+// readContents reads and emits tokens until the end of the file. It returns false if
+// a batch was not accepted by the consumer, in which case reading stops and the offset
+// is left at the start of that batch so that it is read again on the next poll.
+func (r *Reader) readContents(ctx context.Context) bool {
 	var buf []byte
 	if r.TokenLenState.MinimumLength <= r.initialBufferSize {
 		bufPtr := r.getBufPtrFromPool()
@@ -221,11 +244,12 @@ func (r *Reader) readContents(ctx context.Context) {
 
 	tokenBodies := make([][]byte, r.maxBatchSize)
 	numTokensBatched := 0
+	batchRecordNum := r.RecordNum
 	// Iterate over the contents of the file.
 	for {
 		select {
 		case <-ctx.Done():
-			return
+			return true
 		default:
 		}
 
@@ -233,40 +257,62 @@ func (r *Reader) readContents(ctx context.Context) {
 		if !ok {
 			if err := s.Error(); err != nil {
 				r.set.Logger.Error("failed during scan", zap.Error(err))
-			} else if r.deleteAtEOF {
-				r.delete()
 			}
 
 			if numTokensBatched > 0 {
-				err := r.emitFunc(ctx, tokenBodies[:numTokensBatched], r.FileAttributes, r.RecordNum)
-				if err != nil {
-					r.set.Logger.Error("failed to emit token", zap.Error(err))
+				if !r.emit(ctx, tokenBodies[:numTokensBatched]) {
+					r.RecordNum = batchRecordNum
+					return false
 				}
 				r.Offset = s.Pos()
 			}
-			return
+
+			if s.Error() == nil && r.deleteAtEOF {
+				r.delete()
+			}
+			return true
 		}
 
 		var err error
 		tokenBodies[numTokensBatched], err = r.decoder.Bytes(s.Bytes())
 		if err != nil {
 			r.set.Logger.Error("failed to decode token", zap.Error(err))
-			r.Offset = s.Pos() // move past the bad token or we may be stuck
+			if numTokensBatched == 0 {
+				r.Offset = s.Pos() // move past the bad token or we may be stuck
+			}
 			continue
 		}
 		numTokensBatched++
 
 		r.RecordNum++
 		if r.maxBatchSize > 0 && numTokensBatched >= r.maxBatchSize {
-			if err = r.emitFunc(ctx, tokenBodies[:numTokensBatched], r.FileAttributes, r.RecordNum); err != nil {
-				r.set.Logger.Error("failed to emit token", zap.Error(err))
+			if !r.emit(ctx, tokenBodies[:numTokensBatched]) {
+				r.RecordNum = batchRecordNum
+				return false
 			}
 			numTokensBatched = 0
+			batchRecordNum = r.RecordNum
 			r.Offset = s.Pos()
 		}
 	}
 }
 
+// emit passes a batch of tokens to the emit callback. It returns false only when
+// at-least-once delivery is enabled and the consumer did not accept the batch.
+// Any other error is logged and the batch is considered done.
+func (r *Reader) emit(ctx context.Context, tokens [][]byte) bool {
+	err := r.emitFunc(ctx, tokens, r.FileAttributes, r.RecordNum)
+	if err == nil {
+		return true
+	}
+	if r.atLeastOnce && errors.Is(err, helper.ErrEntriesNotAccepted) {
+		r.set.Logger.Warn("tokens were not accepted, will retry from last acknowledged offset", zap.Error(err))
+		return false
+	}
+	r.set.Logger.Error("failed to emit token", zap.Error(err))
+	return true
+}
+
 // Delete will close and delete the file
 func (r *Reader) delete() {
 	r.close()
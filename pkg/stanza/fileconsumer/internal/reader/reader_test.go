@@ -4,6 +4,7 @@
 package reader
 
 import (
+	"compress/gzip"
 	"context"
 	"fmt"
 	"os"
@@ -23,6 +24,7 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/scanner"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/internal/filetest"
 	internaltime "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/internal/time"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/helper"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/split"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/trim"
 )
@@ -332,6 +334,106 @@ func TestUntermintedLogEntryGrows(t *testing.T) {
 	sink.ExpectNoCalls(t)
 }
 
+func TestAtLeastOnceHoldsOffset(t *testing.T) {
+	t.Parallel()
+
+	for _, atLeastOnce := range []bool{false, true} {
+		t.Run(fmt.Sprintf("at_least_once=%t", atLeastOnce), func(t *testing.T) {
+			t.Parallel()
+
+			tempDir := t.TempDir()
+			temp := filetest.OpenTemp(t, tempDir)
+			filetest.WriteString(t, temp, "testlog1\ntestlog2\n")
+
+			var accept bool
+			var received [][]byte
+			var recordNums []int64
+			f := newTestFactory(t, func(_ context.Context, tokens [][]byte, _ map[string]any, lastRecordNumber int64) error {
+				if !accept {
+					return fmt.Errorf("consume entries: %w", helper.ErrEntriesNotAccepted)
+				}
+				for _, token := range tokens {
+					received = append(received, append([]byte(nil), token...))
+				}
+				recordNums = append(recordNums, lastRecordNumber)
+				return nil
+			})
+			f.AtLeastOnce = atLeastOnce
+
+			fp, err := f.NewFingerprint(temp)
+			require.NoError(t, err)
+			reader, err := f.NewReader(filetest.OpenFile(t, temp.Name()), fp)
+			require.NoError(t, err)
+			defer reader.Close()
+
+			reader.ReadToEnd(context.Background())
+			if !atLeastOnce {
+				// The rejected batch is dropped and the offset moves past it.
+				require.Equal(t, int64(18), reader.Offset)
+				require.Equal(t, int64(2), reader.RecordNum)
+				return
+			}
+			require.Zero(t, reader.Offset)
+			require.Zero(t, reader.RecordNum)
+
+			accept = true
+			reader.ReadToEnd(context.Background())
+			require.Equal(t, [][]byte{[]byte("testlog1"), []byte("testlog2")}, received)
+			require.Equal(t, []int64{2}, recordNums)
+			require.Equal(t, int64(18), reader.Offset)
+			require.Equal(t, int64(2), reader.RecordNum)
+		})
+	}
+}
+
+func TestAtLeastOnceGzipRejectedBatch(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	temp := filetest.OpenTemp(t, tempDir)
+	writer := gzip.NewWriter(temp)
+	_, err := writer.Write([]byte("testlog1\ntestlog2\ntestlog3\n"))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	info, err := temp.Stat()
+	require.NoError(t, err)
+
+	// The first batch is accepted and the second one is rejected.
+	var calls int
+	var received [][]byte
+	f := newTestFactory(t, func(_ context.Context, tokens [][]byte, _ map[string]any, _ int64) error {
+		calls++
+		if calls == 2 {
+			return fmt.Errorf("consume entries: %w", helper.ErrEntriesNotAccepted)
+		}
+		for _, token := range tokens {
+			received = append(received, append([]byte(nil), token...))
+		}
+		return nil
+	})
+	f.AtLeastOnce = true
+	f.Compression = "gzip"
+
+	fp, err := f.NewFingerprint(temp)
+	require.NoError(t, err)
+	reader, err := f.NewReader(filetest.OpenFile(t, temp.Name()), fp)
+	require.NoError(t, err)
+	defer reader.Close()
+	reader.maxBatchSize = 1
+
+	reader.ReadToEnd(context.Background())
+	require.Equal(t, [][]byte{[]byte("testlog1")}, received)
+	// The offset is not left at a position of the decompressed data.
+	require.Zero(t, reader.Offset)
+	require.Zero(t, reader.RecordNum)
+
+	// The file is read again from the start, so the accepted batch is delivered twice.
+	reader.ReadToEnd(context.Background())
+	require.Equal(t, [][]byte{[]byte("testlog1"), []byte("testlog1"), []byte("testlog2"), []byte("testlog3")}, received)
+	require.Equal(t, info.Size(), reader.Offset)
+	require.Equal(t, int64(3), reader.RecordNum)
+}
+
 func BenchmarkFileRead(b *testing.B) {
 	tempDir := b.TempDir()
 
@@ -5,6 +5,8 @@ package helper // import "github.com/open-telemetry/opentelemetry-collector-cont
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"sync"
 	"time"
 
@@ -14,6 +16,11 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
 )
 
+// ErrEntriesNotAccepted is returned by an acking log emitter when the consumer did not
+// accept the entries. Inputs which support at-least-once delivery use it to hold back
+// their progress until the entries are delivered.
+var ErrEntriesNotAccepted = errors.New("entries not accepted by consumer")
+
 type LogEmitter interface {
 	operator.Operator
 	Start(operator.Persister) error
@@ -201,10 +208,23 @@ func (e *BatchingLogEmitter) makeNewBatch() []*entry.Entry {
 // SynchronousLogEmitter is a stanza operator that emits log entries to the consumer callback function `consumerFunc` synchronously
 type SynchronousLogEmitter struct {
 	OutputOperator
-	consumerFunc func(context.Context, []*entry.Entry)
+	consumerFunc func(context.Context, []*entry.Entry) error
 }
 
 func NewSynchronousLogEmitter(set component.TelemetrySettings, consumerFunc func(context.Context, []*entry.Entry)) *SynchronousLogEmitter {
+	return newSynchronousLogEmitter(set, func(ctx context.Context, entries []*entry.Entry) error {
+		consumerFunc(ctx, entries)
+		return nil
+	})
+}
+
+// NewAckingLogEmitter creates a SynchronousLogEmitter which reports errors returned by
+// the consumer back to the operator that wrote the entries, wrapped in ErrEntriesNotAccepted.
+func NewAckingLogEmitter(set component.TelemetrySettings, consumerFunc func(context.Context, []*entry.Entry) error) *SynchronousLogEmitter {
+	return newSynchronousLogEmitter(set, consumerFunc)
+}
+
+func newSynchronousLogEmitter(set component.TelemetrySettings, consumerFunc func(context.Context, []*entry.Entry) error) *SynchronousLogEmitter {
 	op, _ := NewOutputConfig("synchronous_log_emitter", "synchronous_log_emitter").Build(set)
 	return &SynchronousLogEmitter{
 		OutputOperator: op,
@@ -221,11 +241,16 @@ func (e *SynchronousLogEmitter) Stop() error {
 }
 
 func (e *SynchronousLogEmitter) ProcessBatch(ctx context.Context, entries []*entry.Entry) error {
-	e.consumerFunc(ctx, entries)
-	return nil
+	return e.consume(ctx, entries)
 }
 
 func (e *SynchronousLogEmitter) Process(ctx context.Context, ent *entry.Entry) error {
-	e.consumerFunc(ctx, []*entry.Entry{ent})
+	return e.consume(ctx, []*entry.Entry{ent})
+}
+
+func (e *SynchronousLogEmitter) consume(ctx context.Context, entries []*entry.Entry) error {
+	if err := e.consumerFunc(ctx, entries); err != nil {
+		return fmt.Errorf("%w: %w", ErrEntriesNotAccepted, err)
+	}
 	return nil
 }
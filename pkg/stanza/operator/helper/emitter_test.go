@@ -5,6 +5,7 @@ package helper
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"testing"
@@ -149,6 +150,37 @@ func TestSynchronousLogEmitter(t *testing.T) {
 	require.Equal(t, in, receivedEntries[0])
 }
 
+func TestAckingLogEmitter(t *testing.T) {
+	consumerErr := errors.New("queue is full")
+	var accept bool
+	var received int
+	emitter := NewAckingLogEmitter(
+		componenttest.NewNopTelemetrySettings(),
+		func(_ context.Context, entries []*entry.Entry) error {
+			if !accept {
+				return consumerErr
+			}
+			received += len(entries)
+			return nil
+		},
+	)
+
+	require.NoError(t, emitter.Start(nil))
+	defer func() {
+		require.NoError(t, emitter.Stop())
+	}()
+
+	err := emitter.ProcessBatch(context.Background(), complexEntries(2))
+	require.ErrorIs(t, err, ErrEntriesNotAccepted)
+	require.ErrorIs(t, err, consumerErr)
+	require.Zero(t, received)
+
+	accept = true
+	require.NoError(t, emitter.ProcessBatch(context.Background(), complexEntries(2)))
+	require.NoError(t, emitter.Process(context.Background(), entry.New()))
+	require.Equal(t, 3, received)
+}
+
 func complexEntries(count int) []*entry.Entry {
 	return complexEntriesForNDifferentHosts(count, 1)
 }
@@ -16,6 +16,7 @@ import (
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
 	stanza_errors "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/errors"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
 )
 
 // NewTransformerConfig creates a new transformer config with default values
@@ -31,6 +32,10 @@ type TransformerConfig struct {
 	WriterConfig `mapstructure:",squash"`
 	OnError      string `mapstructure:"on_error"`
 	IfExpr       string `mapstructure:"if"`
+
+	// DeadLetterOutput is the ID of the operator that receives entries which
+	// failed processing, instead of applying the on_error strategy to them.
+	DeadLetterOutput string `mapstructure:"dead_letter_output"`
 }
 
 // Build will build a transformer operator.
@@ -51,8 +56,9 @@ func (c TransformerConfig) Build(set component.TelemetrySettings) (TransformerOp
 	}
 
 	transformerOperator := TransformerOperator{
-		WriterOperator: writerOperator,
-		OnError:        c.OnError,
+		WriterOperator:     writerOperator,
+		OnError:            c.OnError,
+		DeadLetterOutputID: c.DeadLetterOutput,
 	}
 
 	if c.IfExpr != "" {
@@ -71,6 +77,44 @@ type TransformerOperator struct {
 	WriterOperator
 	OnError string
 	IfExpr  *vm.Program
+
+	DeadLetterOutputID string
+	DeadLetterOperator operator.Operator
+}
+
+// DeadLetterOutput returns the ID of the dead letter output of the operator, if any.
+func (t *TransformerOperator) DeadLetterOutput() string {
+	return t.DeadLetterOutputID
+}
+
+// Outputs returns the outputs of the transformer operator, including the dead letter output.
+func (t *TransformerOperator) Outputs() []operator.Operator {
+	if t.DeadLetterOperator == nil {
+		return t.OutputOperators
+	}
+	outputs := make([]operator.Operator, 0, len(t.OutputOperators)+1)
+	outputs = append(outputs, t.OutputOperators...)
+	return append(outputs, t.DeadLetterOperator)
+}
+
+// SetOutputs will set the outputs of the operator, including the dead letter output.
+func (t *TransformerOperator) SetOutputs(operators []operator.Operator) error {
+	if err := t.WriterOperator.SetOutputs(operators); err != nil {
+		return err
+	}
+	if t.DeadLetterOutputID == "" {
+		return nil
+	}
+
+	deadLetter, ok := t.findOperator(operators, t.DeadLetterOutputID)
+	if !ok {
+		return fmt.Errorf("dead letter output '%s' does not exist", t.DeadLetterOutputID)
+	}
+	if !deadLetter.CanProcess() {
+		return fmt.Errorf("dead letter output '%s' can not process entries", t.DeadLetterOutputID)
+	}
+	t.DeadLetterOperator = deadLetter
+	return nil
 }
 
 // CanProcess will always return true for a transformer operator.
@@ -104,19 +148,34 @@ func (t *TransformerOperator) ProcessWith(ctx context.Context, entry *entry.Entr
 }
 
 // HandleEntryError will handle an entry error using the on_error strategy.
+// If a dead letter output is configured, the entry is annotated with the
+// operator ID and error message and sent to it instead.
 func (t *TransformerOperator) HandleEntryError(ctx context.Context, entry *entry.Entry, err error) error {
 	if entry == nil {
 		return errors.New("got a nil entry, this should not happen and is potentially a bug")
 	}
 
+	action := t.OnError
+	if t.DeadLetterOperator != nil {
+		action = deadLetterAction
+	}
+
 	if t.OnError == SendOnErrorQuiet || t.OnError == DropOnErrorQuiet {
 		// No need to construct the zap attributes if logging not enabled at debug level.
 		if t.Logger().Core().Enabled(zapcore.DebugLevel) {
-			t.Logger().Debug("Failed to process entry", zapAttributes(entry, t.OnError, err)...)
+			t.Logger().Debug("Failed to process entry", zapAttributes(entry, action, err)...)
 		}
 	} else {
-		t.Logger().Error("Failed to process entry", zapAttributes(entry, t.OnError, err)...)
+		t.Logger().Error("Failed to process entry", zapAttributes(entry, action, err)...)
+	}
+
+	if t.DeadLetterOperator != nil {
+		if writeErr := t.writeDeadLetter(ctx, entry, err); writeErr != nil {
+			err = fmt.Errorf("failed to send entry to dead letter output: %w", writeErr)
+		}
+		return err
 	}
+
 	if t.OnError == SendOnError || t.OnError == SendOnErrorQuiet {
 		if writeErr := t.Write(ctx, entry); writeErr != nil {
 			err = fmt.Errorf("failed to send entry after error: %w", writeErr)
@@ -126,6 +185,14 @@ func (t *TransformerOperator) HandleEntryError(ctx context.Context, entry *entry
 	return err
 }
 
+// writeDeadLetter annotates the entry with the failing operator and error, and
+// sends it to the dead letter output.
+func (t *TransformerOperator) writeDeadLetter(ctx context.Context, e *entry.Entry, err error) error {
+	e.AddAttribute(DeadLetterOperatorIDAttribute, t.ID())
+	e.AddAttribute(DeadLetterErrorAttribute, err.Error())
+	return t.DeadLetterOperator.Process(ctx, e)
+}
+
 func (t *TransformerOperator) Skip(_ context.Context, entry *entry.Entry) (bool, error) {
 	if t.IfExpr == nil {
 		return false, nil
@@ -170,3 +237,14 @@ const DropOnError = "drop"
 
 // DropOnErrorQuiet specifies an on_error mode for dropping entries after an error but without logging on error level
 const DropOnErrorQuiet = "drop_quiet"
+
+// deadLetterAction is the action logged when an entry is sent to the dead letter output.
+const deadLetterAction = "dead_letter"
+
+// DeadLetterOperatorIDAttribute is the attribute holding the ID of the operator
+// that failed to process an entry sent to a dead letter output.
+const DeadLetterOperatorIDAttribute = "stanza.error.operator_id"
+
+// DeadLetterErrorAttribute is the attribute holding the error message of an
+// entry sent to a dead letter output.
+const DeadLetterErrorAttribute = "stanza.error.message"
@@ -382,3 +382,140 @@ func TestTransformerIf(t *testing.T) {
 		require.Error(t, err)
 	})
 }
+
+func TestTransformerDeadLetterOnError(t *testing.T) {
+	for _, onError := range []string{SendOnError, SendOnErrorQuiet, DropOnError, DropOnErrorQuiet} {
+		t.Run(onError, func(t *testing.T) {
+			cfg := NewTransformerConfig("test-id", "test-type")
+			cfg.OutputIDs = []string{"fake"}
+			cfg.OnError = onError
+			cfg.DeadLetterOutput = "dead-letter"
+			set := componenttest.NewNopTelemetrySettings()
+			transformer, err := cfg.Build(set)
+			require.NoError(t, err)
+			require.Equal(t, "dead-letter", transformer.DeadLetterOutput())
+
+			output := testutil.NewFakeOutput(t)
+			deadLetter := &testutil.Operator{}
+			deadLetter.On("ID").Return("dead-letter")
+			deadLetter.On("CanProcess").Return(true)
+			deadLetter.On("Process", mock.Anything, mock.Anything).Return(nil)
+			require.NoError(t, transformer.SetOutputs([]operator.Operator{output, deadLetter}))
+			require.Equal(t, []operator.Operator{output, deadLetter}, transformer.Outputs())
+
+			testEntry := entry.New()
+			testEntry.Body = "test"
+			err = transformer.ProcessWith(context.Background(), testEntry, func(_ *entry.Entry) error {
+				return errors.New("failure")
+			})
+			require.EqualError(t, err, "failure")
+
+			output.ExpectNoEntry(t, 100*time.Millisecond)
+			deadLetter.AssertCalled(t, "Process", mock.Anything, mock.MatchedBy(func(e *entry.Entry) bool {
+				return e.Body == "test" &&
+					e.Attributes[DeadLetterOperatorIDAttribute] == "test-id" &&
+					e.Attributes[DeadLetterErrorAttribute] == "failure"
+			}))
+		})
+	}
+}
+
+func TestTransformerDeadLetterLogs(t *testing.T) {
+	deadLetter := &testutil.Operator{}
+	deadLetter.On("ID").Return("dead-letter")
+	deadLetter.On("Process", mock.Anything, mock.Anything).Return(nil)
+
+	obs, logs := observer.New(zap.WarnLevel)
+	set := componenttest.NewNopTelemetrySettings()
+	set.Logger = zap.New(obs)
+
+	transformer := TransformerOperator{
+		OnError: DropOnError,
+		WriterOperator: WriterOperator{
+			BasicOperator: BasicOperator{
+				OperatorID:   "test-id",
+				OperatorType: "test-type",
+				set:          set,
+			},
+		},
+		DeadLetterOutputID: "dead-letter",
+		DeadLetterOperator: deadLetter,
+	}
+	testEntry := entry.New()
+	now := time.Now()
+	testEntry.Timestamp = now
+
+	err := transformer.HandleEntryError(context.Background(), testEntry, errors.New("failure"))
+	require.Error(t, err)
+
+	expectedLogs := []observer.LoggedEntry{
+		{
+			Entry: zapcore.Entry{Level: zap.ErrorLevel, Message: "Failed to process entry"},
+			Context: []zapcore.Field{
+				zap.Error(errors.New("failure")),
+				zap.String("action", "dead_letter"),
+				zap.Time("entry.timestamp", now),
+			},
+		},
+	}
+	require.Equal(t, expectedLogs, logs.AllUntimed())
+}
+
+func TestTransformerDeadLetterProcessError(t *testing.T) {
+	deadLetter := &testutil.Operator{}
+	deadLetter.On("ID").Return("dead-letter")
+	deadLetter.On("Process", mock.Anything, mock.Anything).Return(errors.New("queue full"))
+
+	transformer := TransformerOperator{
+		OnError: SendOnErrorQuiet,
+		WriterOperator: WriterOperator{
+			BasicOperator: BasicOperator{
+				OperatorID:   "test-id",
+				OperatorType: "test-type",
+				set:          componenttest.NewNopTelemetrySettings(),
+			},
+		},
+		DeadLetterOutputID: "dead-letter",
+		DeadLetterOperator: deadLetter,
+	}
+
+	err := transformer.HandleEntryError(context.Background(), entry.New(), errors.New("failure"))
+	require.EqualError(t, err, "failed to send entry to dead letter output: queue full")
+}
+
+func TestTransformerDeadLetterSetOutputs(t *testing.T) {
+	t.Run("Missing", func(t *testing.T) {
+		cfg := NewTransformerConfig("test-id", "test-type")
+		cfg.DeadLetterOutput = "dead-letter"
+		transformer, err := cfg.Build(componenttest.NewNopTelemetrySettings())
+		require.NoError(t, err)
+
+		err = transformer.SetOutputs([]operator.Operator{testutil.NewFakeOutput(t)})
+		require.EqualError(t, err, "dead letter output 'dead-letter' does not exist")
+	})
+
+	t.Run("CanNotProcess", func(t *testing.T) {
+		cfg := NewTransformerConfig("test-id", "test-type")
+		cfg.DeadLetterOutput = "dead-letter"
+		transformer, err := cfg.Build(componenttest.NewNopTelemetrySettings())
+		require.NoError(t, err)
+
+		deadLetter := &testutil.Operator{}
+		deadLetter.On("ID").Return("dead-letter")
+		deadLetter.On("CanProcess").Return(false)
+		err = transformer.SetOutputs([]operator.Operator{deadLetter})
+		require.EqualError(t, err, "dead letter output 'dead-letter' can not process entries")
+	})
+
+	t.Run("NotConfigured", func(t *testing.T) {
+		cfg := NewTransformerConfig("test-id", "test-type")
+		cfg.OutputIDs = []string{"fake"}
+		transformer, err := cfg.Build(componenttest.NewNopTelemetrySettings())
+		require.NoError(t, err)
+
+		output := testutil.NewFakeOutput(t)
+		require.NoError(t, transformer.SetOutputs([]operator.Operator{output}))
+		require.Nil(t, transformer.DeadLetterOperator)
+		require.Equal(t, []operator.Operator{output}, transformer.Outputs())
+	})
+}
@@ -71,12 +71,25 @@ type BaseConfig struct {
 	Encoding         string                  `mapstructure:"encoding,omitempty"`
 	SplitConfig      split.Config            `mapstructure:"multiline,omitempty"`
 	TrimConfig       trim.Config             `mapstructure:",squash"`
+	OctetCounting    bool                    `mapstructure:"octet_counting,omitempty"`
+	ProxyProtocol    bool                    `mapstructure:"proxy_protocol,omitempty"`
+	Limits           LimitsConfig            `mapstructure:"limits,omitempty"`
 	SplitFuncBuilder SplitFuncBuilder        `mapstructure:"-"`
 }
 
+// LimitsConfig configures limits which are applied to each source, identified
+// by the IP address of the peer. A value of 0 means no limit.
+type LimitsConfig struct {
+	MaxConnectionsPerSource    int             `mapstructure:"max_connections_per_source,omitempty"`
+	MaxBytesPerSecondPerSource helper.ByteSize `mapstructure:"max_bytes_per_second_per_source,omitempty"`
+}
+
 type SplitFuncBuilder func(enc encoding.Encoding) (bufio.SplitFunc, error)
 
 func (c Config) defaultSplitFuncBuilder(enc encoding.Encoding) (bufio.SplitFunc, error) {
+	if c.OctetCounting {
+		return octetCountingSplitFunc(int(c.MaxLogSize)), nil
+	}
 	return c.SplitConfig.Func(enc, true, int(c.MaxLogSize))
 }
 
@@ -110,6 +123,23 @@ func (c Config) Build(set component.TelemetrySettings) (operator.Operator, error
 		return nil, err
 	}
 
+	if c.OctetCounting {
+		if c.OneLogPerPacket {
+			return nil, errors.New("'octet_counting' cannot be used with 'one_log_per_packet'")
+		}
+		if c.SplitConfig.LineStartPattern != "" || c.SplitConfig.LineEndPattern != "" {
+			return nil, errors.New("'octet_counting' cannot be used with 'multiline'")
+		}
+	}
+
+	if c.Limits.MaxConnectionsPerSource < 0 {
+		return nil, errors.New("'limits.max_connections_per_source' must not be negative")
+	}
+
+	if c.Limits.MaxBytesPerSecondPerSource < 0 {
+		return nil, errors.New("'limits.max_bytes_per_second_per_source' must not be negative")
+	}
+
 	if c.SplitFuncBuilder == nil {
 		c.SplitFuncBuilder = c.defaultSplitFuncBuilder
 	}
@@ -126,6 +156,11 @@ func (c Config) Build(set component.TelemetrySettings) (operator.Operator, error
 		resolver = helper.NewIPResolver()
 	}
 
+	var limiter *sourceLimiter
+	if c.Limits.MaxConnectionsPerSource > 0 || c.Limits.MaxBytesPerSecondPerSource > 0 {
+		limiter = newSourceLimiter(c.Limits.MaxConnectionsPerSource, int(c.Limits.MaxBytesPerSecondPerSource))
+	}
+
 	tcpInput := &Input{
 		InputOperator:   inputOperator,
 		address:         c.ListenAddress,
@@ -137,7 +172,9 @@ func (c Config) Build(set component.TelemetrySettings) (operator.Operator, error
 		backoff: backoff.Backoff{
 			Max: 3 * time.Second,
 		},
-		resolver: resolver,
+		resolver:      resolver,
+		proxyProtocol: c.ProxyProtocol,
+		limiter:       limiter,
 	}
 
 	if c.TLS != nil {
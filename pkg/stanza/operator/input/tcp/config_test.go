@@ -43,6 +43,28 @@ func TestUnmarshal(t *testing.T) {
 					return cfg
 				}(),
 			},
+			{
+				Name:      "octet_counting",
+				ExpectErr: false,
+				Expect: func() *Config {
+					cfg := NewConfig()
+					cfg.ListenAddress = "10.0.0.1:9000"
+					cfg.OctetCounting = true
+					return cfg
+				}(),
+			},
+			{
+				Name:      "proxy_protocol_with_limits",
+				ExpectErr: false,
+				Expect: func() *Config {
+					cfg := NewConfig()
+					cfg.ListenAddress = "10.0.0.1:9000"
+					cfg.ProxyProtocol = true
+					cfg.Limits.MaxConnectionsPerSource = 10
+					cfg.Limits.MaxBytesPerSecondPerSource = 1024 * 1024
+					return cfg
+				}(),
+			},
 		},
 	}.Run(t)
 }
@@ -39,11 +39,16 @@ type Input struct {
 	tls      *tls.Config
 	backoff  backoff.Backoff
 
-	encoding  encoding.Encoding
-	splitFunc bufio.SplitFunc
-	resolver  *helper.IPResolver
+	encoding      encoding.Encoding
+	splitFunc     bufio.SplitFunc
+	resolver      *helper.IPResolver
+	proxyProtocol bool
+	limiter       *sourceLimiter
 }
 
+// proxyHeaderTimeout is how long a client has to send the PROXY protocol header.
+const proxyHeaderTimeout = 10 * time.Second
+
 // Start will start listening for log entries over tcp.
 func (i *Input) Start(_ operator.Persister) error {
 	if err := i.configureListener(); err != nil {
@@ -57,24 +62,18 @@ func (i *Input) Start(_ operator.Persister) error {
 }
 
 func (i *Input) configureListener() error {
-	if i.tls == nil {
-		listener, err := net.Listen("tcp", i.address)
-		if err != nil {
-			return fmt.Errorf("failed to configure tcp listener: %w", err)
-		}
-		i.listener = listener
-		return nil
-	}
-
-	i.tls.Time = time.Now
-	i.tls.Rand = rand.Reader
-
-	listener, err := tls.Listen("tcp", i.address, i.tls)
+	// TLS connections are established by the connection handler, since the
+	// PROXY protocol header precedes the TLS handshake.
+	listener, err := net.Listen("tcp", i.address)
 	if err != nil {
-		return fmt.Errorf("failed to configure tls listener: %w", err)
+		return fmt.Errorf("failed to configure tcp listener: %w", err)
 	}
-
 	i.listener = listener
+
+	if i.tls != nil {
+		i.tls.Time = time.Now
+		i.tls.Rand = rand.Reader
+	}
 	return nil
 }
 
@@ -129,6 +128,45 @@ func (i *Input) goHandleMessages(ctx context.Context, conn net.Conn, cancel cont
 		defer i.wg.Done()
 		defer cancel()
 
+		peerAddr, hostAddr := conn.RemoteAddr(), conn.LocalAddr()
+		if i.proxyProtocol {
+			var err error
+			if conn, peerAddr, hostAddr, err = i.acceptProxyHeader(conn); err != nil {
+				i.Logger().Error("Failed to read PROXY protocol header", zap.String("address", conn.RemoteAddr().String()), zap.Error(err))
+				return
+			}
+		}
+
+		if i.limiter != nil {
+			key := sourceKey(peerAddr)
+			rate, ok := i.limiter.acquire(key)
+			if !ok {
+				i.Logger().Warn("Rejecting connection, too many connections from source", zap.String("source", key))
+				return
+			}
+			defer i.limiter.release(key)
+			if rate != nil {
+				conn = &bufferedConn{Conn: conn, reader: &rateLimitedReader{ctx: ctx, reader: conn, limit: rate}}
+			}
+		}
+
+		var tlsState *tls.ConnectionState
+		if i.tls != nil {
+			tlsConn := tls.Server(conn, i.tls)
+			if err := tlsConn.HandshakeContext(ctx); err != nil {
+				i.Logger().Error("TLS handshake failed", zap.String("address", peerAddr.String()), zap.Error(err))
+				return
+			}
+			state := tlsConn.ConnectionState()
+			tlsState = &state
+			conn = tlsConn
+		}
+
+		var attributes map[string]any
+		if i.addAttributes {
+			attributes = i.connectionAttributes(peerAddr, hostAddr, tlsState)
+		}
+
 		dec := i.encoding.NewDecoder()
 		if i.OneLogPerPacket {
 			var buf bytes.Buffer
@@ -137,7 +175,7 @@ func (i *Input) goHandleMessages(ctx context.Context, conn net.Conn, cancel cont
 				i.Logger().Error("IO copy net connection buffer error", zap.Error(err))
 			}
 			log := truncateMaxLog(buf.Bytes(), i.MaxLogSize)
-			i.handleMessage(ctx, conn, dec, log)
+			i.handleMessage(ctx, attributes, dec, log)
 			return
 		}
 
@@ -149,7 +187,7 @@ func (i *Input) goHandleMessages(ctx context.Context, conn net.Conn, cancel cont
 		scanner.Split(i.splitFunc)
 
 		for scanner.Scan() {
-			i.handleMessage(ctx, conn, dec, scanner.Bytes())
+			i.handleMessage(ctx, attributes, dec, scanner.Bytes())
 		}
 
 		if err := scanner.Err(); err != nil {
@@ -158,7 +196,89 @@ func (i *Input) goHandleMessages(ctx context.Context, conn net.Conn, cancel cont
 	}()
 }
 
-func (i *Input) handleMessage(ctx context.Context, conn net.Conn, dec *encoding.Decoder, log []byte) {
+// acceptProxyHeader reads the PROXY protocol header from the connection. It returns a
+// connection to read the remaining data from, along with the addresses of the
+// original client and the server it connected to.
+func (i *Input) acceptProxyHeader(conn net.Conn) (net.Conn, net.Addr, net.Addr, error) {
+	if err := conn.SetReadDeadline(time.Now().Add(proxyHeaderTimeout)); err != nil {
+		return conn, nil, nil, err
+	}
+	reader := bufio.NewReader(conn)
+	src, dst, err := readProxyHeader(reader)
+	if err != nil {
+		return conn, nil, nil, err
+	}
+	if err = conn.SetReadDeadline(time.Time{}); err != nil {
+		return conn, nil, nil, err
+	}
+
+	buffered := &bufferedConn{Conn: conn, reader: reader}
+	if src == nil {
+		return buffered, conn.RemoteAddr(), conn.LocalAddr(), nil
+	}
+	return buffered, src, dst, nil
+}
+
+// connectionAttributes returns the attributes describing the connection, which
+// are added to every entry read from it.
+func (i *Input) connectionAttributes(peerAddr, hostAddr net.Addr, tlsState *tls.ConnectionState) map[string]any {
+	attributes := map[string]any{
+		"net.transport": "IP.TCP",
+	}
+
+	if addr, ok := peerAddr.(*net.TCPAddr); ok {
+		ip := addr.IP.String()
+		attributes["net.peer.ip"] = ip
+		attributes["net.peer.port"] = strconv.FormatInt(int64(addr.Port), 10)
+		attributes["net.peer.name"] = i.resolver.GetHostFromIP(ip)
+	}
+
+	if addr, ok := hostAddr.(*net.TCPAddr); ok {
+		ip := addr.IP.String()
+		attributes["net.host.ip"] = ip
+		attributes["net.host.port"] = strconv.FormatInt(int64(addr.Port), 10)
+		attributes["net.host.name"] = i.resolver.GetHostFromIP(ip)
+	}
+
+	// Only expose the identity of a peer certificate that was verified against the client CAs.
+	if tlsState != nil && len(tlsState.VerifiedChains) > 0 && len(tlsState.VerifiedChains[0]) > 0 {
+		cert := tlsState.VerifiedChains[0][0]
+		attributes["tls.client.subject"] = cert.Subject.String()
+		attributes["tls.client.issuer"] = cert.Issuer.String()
+		if len(cert.DNSNames) > 0 {
+			attributes["tls.client.san.dns"] = toAnySlice(cert.DNSNames)
+		}
+		if len(cert.EmailAddresses) > 0 {
+			attributes["tls.client.san.email"] = toAnySlice(cert.EmailAddresses)
+		}
+		if len(cert.IPAddresses) > 0 {
+			ips := make([]any, 0, len(cert.IPAddresses))
+			for _, ip := range cert.IPAddresses {
+				ips = append(ips, ip.String())
+			}
+			attributes["tls.client.san.ip"] = ips
+		}
+		if len(cert.URIs) > 0 {
+			uris := make([]any, 0, len(cert.URIs))
+			for _, uri := range cert.URIs {
+				uris = append(uris, uri.String())
+			}
+			attributes["tls.client.san.uri"] = uris
+		}
+	}
+
+	return attributes
+}
+
+func toAnySlice(values []string) []any {
+	result := make([]any, 0, len(values))
+	for _, v := range values {
+		result = append(result, v)
+	}
+	return result
+}
+
+func (i *Input) handleMessage(ctx context.Context, attributes map[string]any, dec *encoding.Decoder, log []byte) {
 	decoded, err := textutils.DecodeAsString(dec, log)
 	if err != nil {
 		i.Logger().Error("Failed to decode data", zap.Error(err))
@@ -171,20 +291,12 @@ func (i *Input) handleMessage(ctx context.Context, conn net.Conn, dec *encoding.
 		return
 	}
 
-	if i.addAttributes {
-		entry.AddAttribute("net.transport", "IP.TCP")
-		if addr, ok := conn.RemoteAddr().(*net.TCPAddr); ok {
-			ip := addr.IP.String()
-			entry.AddAttribute("net.peer.ip", ip)
-			entry.AddAttribute("net.peer.port", strconv.FormatInt(int64(addr.Port), 10))
-			entry.AddAttribute("net.peer.name", i.resolver.GetHostFromIP(ip))
+	if len(attributes) > 0 {
+		if entry.Attributes == nil {
+			entry.Attributes = make(map[string]any, len(attributes))
 		}
-
-		if addr, ok := conn.LocalAddr().(*net.TCPAddr); ok {
-			ip := addr.IP.String()
-			entry.AddAttribute("net.host.ip", addr.IP.String())
-			entry.AddAttribute("net.host.port", strconv.FormatInt(int64(addr.Port), 10))
-			entry.AddAttribute("net.host.name", i.resolver.GetHostFromIP(ip))
+		for k, v := range attributes {
+			entry.Attributes[k] = copyValue(v)
 		}
 	}
 
@@ -194,6 +306,14 @@ func (i *Input) handleMessage(ctx context.Context, conn net.Conn, dec *encoding.
 	}
 }
 
+// copyValue copies slice values so that entries do not share them.
+func copyValue(v any) any {
+	if s, ok := v.([]any); ok {
+		return append([]any(nil), s...)
+	}
+	return v
+}
+
 func truncateMaxLog(data []byte, maxLogSize int) (token []byte) {
 	if len(data) >= maxLogSize {
 		return data[:maxLogSize]
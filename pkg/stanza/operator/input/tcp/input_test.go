@@ -4,11 +4,21 @@
 package tcp
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	cryptorand "crypto/rand"
 	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"fmt"
+	"io"
+	"math/big"
 	"math/rand/v2"
 	"net"
+	"net/url"
 	"os"
+	"path/filepath"
 	"strconv"
 	"testing"
 	"time"
@@ -21,6 +31,7 @@ import (
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/split"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/testutil"
 )
 
@@ -341,6 +352,77 @@ func TestBuild(t *testing.T) {
 			},
 			true,
 		},
+		{
+			"octet-counting",
+			Config{
+				BaseConfig: BaseConfig{
+					ListenAddress: "10.0.0.1:9000",
+					OctetCounting: true,
+				},
+			},
+			false,
+		},
+		{
+			"octet-counting-with-one-log-per-packet",
+			Config{
+				BaseConfig: BaseConfig{
+					ListenAddress:   "10.0.0.1:9000",
+					OctetCounting:   true,
+					OneLogPerPacket: true,
+				},
+			},
+			true,
+		},
+		{
+			"octet-counting-with-multiline",
+			Config{
+				BaseConfig: BaseConfig{
+					ListenAddress: "10.0.0.1:9000",
+					OctetCounting: true,
+					SplitConfig: split.Config{
+						LineStartPattern: "^<",
+					},
+				},
+			},
+			true,
+		},
+		{
+			"limits",
+			Config{
+				BaseConfig: BaseConfig{
+					ListenAddress: "10.0.0.1:9000",
+					Limits: LimitsConfig{
+						MaxConnectionsPerSource:    10,
+						MaxBytesPerSecondPerSource: 1024,
+					},
+				},
+			},
+			false,
+		},
+		{
+			"limits-negative-connections",
+			Config{
+				BaseConfig: BaseConfig{
+					ListenAddress: "10.0.0.1:9000",
+					Limits: LimitsConfig{
+						MaxConnectionsPerSource: -1,
+					},
+				},
+			},
+			true,
+		},
+		{
+			"limits-negative-rate",
+			Config{
+				BaseConfig: BaseConfig{
+					ListenAddress: "10.0.0.1:9000",
+					Limits: LimitsConfig{
+						MaxBytesPerSecondPerSource: -1,
+					},
+				},
+			},
+			true,
+		},
 	}
 
 	for _, tc := range cases {
@@ -349,6 +431,10 @@ func TestBuild(t *testing.T) {
 			cfg.ListenAddress = tc.inputBody.ListenAddress
 			cfg.MaxLogSize = tc.inputBody.MaxLogSize
 			cfg.TLS = tc.inputBody.TLS
+			cfg.OneLogPerPacket = tc.inputBody.OneLogPerPacket
+			cfg.SplitConfig = tc.inputBody.SplitConfig
+			cfg.OctetCounting = tc.inputBody.OctetCounting
+			cfg.Limits = tc.inputBody.Limits
 			set := componenttest.NewNopTelemetrySettings()
 			_, err := cfg.Build(set)
 			if tc.expectErr {
@@ -375,6 +461,209 @@ func TestTLSTCPInput(t *testing.T) {
 	t.Run("CarriageReturn", tlsInputTest([]byte("message\r\n"), []string{"message"}))
 }
 
+// startTestInput builds and starts a tcp input from cfg, returning a channel
+// receiving the entries it writes.
+func startTestInput(t *testing.T, cfg *Config) (*Input, chan *entry.Entry) {
+	set := componenttest.NewNopTelemetrySettings()
+	op, err := cfg.Build(set)
+	require.NoError(t, err)
+
+	mockOutput := testutil.Operator{}
+	tcpInput := op.(*Input)
+	tcpInput.OutputOperators = []operator.Operator{&mockOutput}
+
+	entryChan := make(chan *entry.Entry, 10)
+	mockOutput.On("Process", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		entryChan <- args.Get(1).(*entry.Entry)
+	}).Return(nil)
+
+	require.NoError(t, tcpInput.Start(testutil.NewUnscopedMockPersister()))
+	t.Cleanup(func() {
+		require.NoError(t, tcpInput.Stop(), "expected to stop tcp input operator without error")
+	})
+	return tcpInput, entryChan
+}
+
+func expectEntry(t *testing.T, entryChan chan *entry.Entry) *entry.Entry {
+	select {
+	case e := <-entryChan:
+		return e
+	case <-time.After(time.Second):
+		require.FailNow(t, "Timed out waiting for message to be written")
+	}
+	return nil
+}
+
+func TestOctetCountingInput(t *testing.T) {
+	cfg := NewConfigWithID("test_id")
+	cfg.ListenAddress = ":0"
+	cfg.OctetCounting = true
+	tcpInput, entryChan := startTestInput(t, cfg)
+
+	conn, err := net.Dial("tcp", tcpInput.listener.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.Write([]byte("12 first\nsecond5 third"))
+	require.NoError(t, err)
+
+	require.Equal(t, "first\nsecond", expectEntry(t, entryChan).Body)
+	require.Equal(t, "third", expectEntry(t, entryChan).Body)
+}
+
+func TestProxyProtocolInput(t *testing.T) {
+	cfg := NewConfigWithID("test_id")
+	cfg.ListenAddress = ":0"
+	cfg.ProxyProtocol = true
+	cfg.AddAttributes = true
+	tcpInput, entryChan := startTestInput(t, cfg)
+
+	t.Run("proxied", func(t *testing.T) {
+		conn, err := net.Dial("tcp", tcpInput.listener.Addr().String())
+		require.NoError(t, err)
+		defer conn.Close()
+
+		_, err = conn.Write([]byte("PROXY TCP4 192.0.2.1 198.51.100.1 56324 514\r\nmessage\n"))
+		require.NoError(t, err)
+
+		e := expectEntry(t, entryChan)
+		require.Equal(t, "message", e.Body)
+		require.Equal(t, "192.0.2.1", e.Attributes["net.peer.ip"])
+		require.Equal(t, "56324", e.Attributes["net.peer.port"])
+		require.Equal(t, "198.51.100.1", e.Attributes["net.host.ip"])
+		require.Equal(t, "514", e.Attributes["net.host.port"])
+	})
+
+	t.Run("local", func(t *testing.T) {
+		conn, err := net.Dial("tcp", tcpInput.listener.Addr().String())
+		require.NoError(t, err)
+		defer conn.Close()
+
+		_, err = conn.Write([]byte("PROXY UNKNOWN\r\nmessage\n"))
+		require.NoError(t, err)
+
+		e := expectEntry(t, entryChan)
+		require.Equal(t, "message", e.Body)
+		require.Equal(t, conn.LocalAddr().(*net.TCPAddr).IP.String(), e.Attributes["net.peer.ip"])
+	})
+
+	t.Run("missing header", func(t *testing.T) {
+		conn, err := net.Dial("tcp", tcpInput.listener.Addr().String())
+		require.NoError(t, err)
+		defer conn.Close()
+
+		_, err = conn.Write([]byte("message\n"))
+		require.NoError(t, err)
+
+		select {
+		case e := <-entryChan:
+			require.FailNow(t, fmt.Sprintf("Unexpected entry: %s", e))
+		case <-time.After(100 * time.Millisecond):
+		}
+	})
+}
+
+func TestMaxConnectionsPerSource(t *testing.T) {
+	cfg := NewConfigWithID("test_id")
+	cfg.ListenAddress = "127.0.0.1:0"
+	cfg.Limits.MaxConnectionsPerSource = 1
+	tcpInput, entryChan := startTestInput(t, cfg)
+
+	first, err := net.Dial("tcp", tcpInput.listener.Addr().String())
+	require.NoError(t, err)
+	defer first.Close()
+	_, err = first.Write([]byte("first\n"))
+	require.NoError(t, err)
+	require.Equal(t, "first", expectEntry(t, entryChan).Body)
+
+	// The second connection from the same source is closed without reading.
+	second, err := net.Dial("tcp", tcpInput.listener.Addr().String())
+	require.NoError(t, err)
+	defer second.Close()
+	require.NoError(t, second.SetReadDeadline(time.Now().Add(time.Second)))
+	_, err = second.Read(make([]byte, 1))
+	require.ErrorIs(t, err, io.EOF)
+
+	_, err = first.Write([]byte("second\n"))
+	require.NoError(t, err)
+	require.Equal(t, "second", expectEntry(t, entryChan).Body)
+}
+
+func TestTLSClientCertificateAttributes(t *testing.T) {
+	dir := t.TempDir()
+	serverCert := filepath.Join(dir, "server.crt")
+	serverKey := filepath.Join(dir, "server.key")
+	require.NoError(t, os.WriteFile(serverCert, []byte(testTLSCertificate+"\n"), 0o600))
+	require.NoError(t, os.WriteFile(serverKey, []byte(testTLSPrivateKey+"\n"), 0o600))
+
+	clientCert, clientCertPEM := newTestClientCertificate(t)
+	clientCA := filepath.Join(dir, "client-ca.crt")
+	require.NoError(t, os.WriteFile(clientCA, clientCertPEM, 0o600))
+
+	cfg := NewConfigWithID("test_id")
+	cfg.ListenAddress = ":0"
+	cfg.AddAttributes = true
+	cfg.TLS = &configtls.ServerConfig{
+		Config: configtls.Config{
+			CertFile: serverCert,
+			KeyFile:  serverKey,
+		},
+		ClientCAFile: clientCA,
+	}
+	tcpInput, entryChan := startTestInput(t, cfg)
+
+	conn, err := tls.Dial("tcp", tcpInput.listener.Addr().String(), &tls.Config{
+		InsecureSkipVerify: true,
+		Certificates:       []tls.Certificate{clientCert},
+	})
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.Write([]byte("message\n"))
+	require.NoError(t, err)
+
+	e := expectEntry(t, entryChan)
+	require.Equal(t, "message", e.Body)
+	require.Equal(t, "CN=sender,O=Example", e.Attributes["tls.client.subject"])
+	require.Equal(t, "CN=sender,O=Example", e.Attributes["tls.client.issuer"])
+	require.Equal(t, []any{"sender.example.com"}, e.Attributes["tls.client.san.dns"])
+	require.Equal(t, []any{"spiffe://example.com/sender"}, e.Attributes["tls.client.san.uri"])
+	require.NotContains(t, e.Attributes, "tls.client.san.email")
+}
+
+// newTestClientCertificate returns a self-signed client certificate, along with
+// its PEM encoding for use as the client CA.
+func newTestClientCertificate(t *testing.T) (tls.Certificate, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), cryptorand.Reader)
+	require.NoError(t, err)
+
+	uri, err := url.Parse("spiffe://example.com/sender")
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "sender", Organization: []string{"Example"}},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		DNSNames:              []string{"sender.example.com"},
+		URIs:                  []*url.URL{uri},
+	}
+	der, err := x509.CreateCertificate(cryptorand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	require.NoError(t, err)
+	return cert, certPEM
+}
+
 func TestFailToBind(t *testing.T) {
 	ip := "localhost"
 	port := 0
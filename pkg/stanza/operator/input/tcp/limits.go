@@ -0,0 +1,157 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package tcp // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/input/tcp"
+
+import (
+	"context"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+// sourceLimiter enforces the per-source connection and byte rate limits.
+// A source is identified by the IP address of the peer.
+type sourceLimiter struct {
+	maxConnections    int
+	maxBytesPerSecond int
+
+	mu      sync.Mutex
+	sources map[string]*source
+}
+
+type source struct {
+	connections int
+	rate        *rateLimiter
+}
+
+func newSourceLimiter(maxConnections, maxBytesPerSecond int) *sourceLimiter {
+	return &sourceLimiter{
+		maxConnections:    maxConnections,
+		maxBytesPerSecond: maxBytesPerSecond,
+		sources:           make(map[string]*source),
+	}
+}
+
+// acquire registers a new connection from the given source. It returns false
+// if the source already has the maximum number of connections open. Otherwise
+// the returned rate limiter, which is nil if no byte rate limit is configured,
+// is shared by all connections from the source.
+func (l *sourceLimiter) acquire(key string) (*rateLimiter, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	s, ok := l.sources[key]
+	if !ok {
+		s = &source{}
+		if l.maxBytesPerSecond > 0 {
+			s.rate = newRateLimiter(l.maxBytesPerSecond)
+		}
+		l.sources[key] = s
+	}
+	if l.maxConnections > 0 && s.connections >= l.maxConnections {
+		return nil, false
+	}
+	s.connections++
+	return s.rate, true
+}
+
+// release unregisters a connection previously registered with acquire.
+func (l *sourceLimiter) release(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	s, ok := l.sources[key]
+	if !ok {
+		return
+	}
+	s.connections--
+	if s.connections <= 0 {
+		delete(l.sources, key)
+	}
+}
+
+// sourceKey returns the key identifying the source of a connection.
+func sourceKey(addr net.Addr) string {
+	if tcpAddr, ok := addr.(*net.TCPAddr); ok {
+		return tcpAddr.IP.String()
+	}
+	return addr.String()
+}
+
+// rateLimiter is a token bucket allowing bytesPerSecond bytes per second, with a
+// burst of up to one second's worth of bytes.
+type rateLimiter struct {
+	bytesPerSecond float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(bytesPerSecond int) *rateLimiter {
+	return &rateLimiter{
+		bytesPerSecond: float64(bytesPerSecond),
+		tokens:         float64(bytesPerSecond),
+		last:           time.Now(),
+	}
+}
+
+// reserve takes n bytes from the bucket and returns how long the caller must
+// wait before the bytes are within the limit.
+func (l *rateLimiter) reserve(n int) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.bytesPerSecond
+	if l.tokens > l.bytesPerSecond {
+		l.tokens = l.bytesPerSecond
+	}
+	l.last = now
+
+	l.tokens -= float64(n)
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.bytesPerSecond * float64(time.Second))
+}
+
+// wait blocks until n bytes are within the limit or the context is done.
+func (l *rateLimiter) wait(ctx context.Context, n int) error {
+	delay := l.reserve(n)
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// rateLimitedReader slows down reads from the underlying reader so that the
+// byte rate stays within the limit. Since the data is not read from the
+// connection while waiting, the sender is slowed down by TCP flow control.
+type rateLimitedReader struct {
+	ctx    context.Context
+	reader io.Reader
+	limit  *rateLimiter
+}
+
+func (r *rateLimitedReader) Read(b []byte) (int, error) {
+	if maxRead := int(r.limit.bytesPerSecond); len(b) > maxRead {
+		b = b[:maxRead]
+	}
+	n, err := r.reader.Read(b)
+	if n > 0 {
+		if waitErr := r.limit.wait(r.ctx, n); waitErr != nil && err == nil {
+			err = waitErr
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,93 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package tcp
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSourceLimiterConnections(t *testing.T) {
+	limiter := newSourceLimiter(2, 0)
+
+	rate, ok := limiter.acquire("192.0.2.1")
+	require.True(t, ok)
+	require.Nil(t, rate)
+	_, ok = limiter.acquire("192.0.2.1")
+	require.True(t, ok)
+	_, ok = limiter.acquire("192.0.2.1")
+	require.False(t, ok, "third connection from the same source must be rejected")
+
+	_, ok = limiter.acquire("192.0.2.2")
+	require.True(t, ok, "other sources are not affected")
+
+	limiter.release("192.0.2.1")
+	_, ok = limiter.acquire("192.0.2.1")
+	require.True(t, ok)
+
+	limiter.release("192.0.2.1")
+	limiter.release("192.0.2.1")
+	limiter.release("192.0.2.2")
+	require.Empty(t, limiter.sources)
+}
+
+func TestSourceLimiterSharesRate(t *testing.T) {
+	limiter := newSourceLimiter(0, 1024)
+
+	first, ok := limiter.acquire("192.0.2.1")
+	require.True(t, ok)
+	second, ok := limiter.acquire("192.0.2.1")
+	require.True(t, ok)
+	require.NotNil(t, first)
+	require.Same(t, first, second)
+
+	other, ok := limiter.acquire("192.0.2.2")
+	require.True(t, ok)
+	require.NotSame(t, first, other)
+}
+
+func TestRateLimiterReserve(t *testing.T) {
+	limiter := newRateLimiter(100)
+	require.Zero(t, limiter.reserve(100), "a burst of one second's worth of bytes is allowed")
+
+	delay := limiter.reserve(50)
+	require.Greater(t, delay, 400*time.Millisecond)
+	require.LessOrEqual(t, delay, 500*time.Millisecond)
+}
+
+func TestRateLimitedReader(t *testing.T) {
+	data := bytes.Repeat([]byte("a"), 150)
+	reader := &rateLimitedReader{
+		ctx:    context.Background(),
+		reader: bytes.NewReader(data),
+		limit:  newRateLimiter(100),
+	}
+
+	start := time.Now()
+	read, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.Equal(t, data, read)
+	require.GreaterOrEqual(t, time.Since(start), 400*time.Millisecond)
+}
+
+func TestRateLimitedReaderContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	reader := &rateLimitedReader{
+		ctx:    ctx,
+		reader: bytes.NewReader(bytes.Repeat([]byte("a"), 200)),
+		limit:  newRateLimiter(100),
+	}
+
+	buf := make([]byte, 100)
+	_, err := reader.Read(buf)
+	require.NoError(t, err)
+	_, err = reader.Read(buf)
+	require.ErrorIs(t, err, context.Canceled)
+}
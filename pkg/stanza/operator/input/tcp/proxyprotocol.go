@@ -0,0 +1,183 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package tcp // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/input/tcp"
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+)
+
+var (
+	proxyV1Prefix    = []byte("PROXY ")
+	proxyV2Signature = []byte("\r\n\r\n\x00\r\nQUIT\n")
+)
+
+const (
+	// proxyV1MaxLength is the maximum length of a v1 header, including the trailing CRLF.
+	proxyV1MaxLength = 107
+
+	proxyV2HeaderLength = 16
+	proxyV2CmdLocal     = 0x0
+	proxyV2CmdProxy     = 0x1
+	proxyV2FamilyInet   = 0x1
+	proxyV2FamilyInet6  = 0x2
+	proxyV2AddrLenInet  = 12
+	proxyV2AddrLenInet6 = 36
+)
+
+// readProxyHeader reads a PROXY protocol v1 or v2 header from r and returns the
+// source and destination addresses of the proxied connection. Both addresses are
+// nil if the header does not describe a proxied TCP connection, for example a
+// health check sent with the LOCAL command or the UNKNOWN protocol.
+func readProxyHeader(r *bufio.Reader) (src, dst net.Addr, err error) {
+	peek, err := r.Peek(len(proxyV1Prefix))
+	if err != nil {
+		return nil, nil, fmt.Errorf("read header: %w", err)
+	}
+	if bytes.Equal(peek, proxyV1Prefix) {
+		return readProxyV1Header(r)
+	}
+
+	peek, err = r.Peek(len(proxyV2Signature))
+	if err != nil {
+		return nil, nil, fmt.Errorf("read header: %w", err)
+	}
+	if bytes.Equal(peek, proxyV2Signature) {
+		return readProxyV2Header(r)
+	}
+	return nil, nil, errors.New("connection does not start with a PROXY protocol header")
+}
+
+func readProxyV1Header(r *bufio.Reader) (net.Addr, net.Addr, error) {
+	var line []byte
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, nil, fmt.Errorf("read v1 header: %w", err)
+		}
+		line = append(line, b)
+		if b == '\n' {
+			break
+		}
+		if len(line) >= proxyV1MaxLength {
+			return nil, nil, errors.New("v1 header exceeds maximum length")
+		}
+	}
+
+	header, found := strings.CutSuffix(string(line), "\r\n")
+	if !found {
+		return nil, nil, errors.New("v1 header is not terminated by CRLF")
+	}
+	fields := strings.Split(header, " ")
+	if len(fields) < 2 {
+		return nil, nil, fmt.Errorf("invalid v1 header %q", header)
+	}
+
+	switch fields[1] {
+	case "UNKNOWN":
+		return nil, nil, nil
+	case "TCP4", "TCP6":
+	default:
+		return nil, nil, fmt.Errorf("unsupported v1 protocol %q", fields[1])
+	}
+	if len(fields) != 6 {
+		return nil, nil, fmt.Errorf("invalid v1 header %q", header)
+	}
+
+	src, err := parseProxyV1Addr(fields[2], fields[4])
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid v1 source address: %w", err)
+	}
+	dst, err := parseProxyV1Addr(fields[3], fields[5])
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid v1 destination address: %w", err)
+	}
+	return src, dst, nil
+}
+
+func parseProxyV1Addr(ip, port string) (*net.TCPAddr, error) {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return nil, fmt.Errorf("invalid ip %q", ip)
+	}
+	p, err := strconv.ParseUint(port, 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("invalid port %q", port)
+	}
+	return &net.TCPAddr{IP: addr, Port: int(p)}, nil
+}
+
+func readProxyV2Header(r *bufio.Reader) (net.Addr, net.Addr, error) {
+	header := make([]byte, proxyV2HeaderLength)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, nil, fmt.Errorf("read v2 header: %w", err)
+	}
+
+	if version := header[12] >> 4; version != 2 {
+		return nil, nil, fmt.Errorf("unsupported v2 header version %d", version)
+	}
+	command := header[12] & 0x0F
+	family := header[13] >> 4
+	length := int(binary.BigEndian.Uint16(header[14:16]))
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, nil, fmt.Errorf("read v2 addresses: %w", err)
+	}
+
+	switch command {
+	case proxyV2CmdLocal:
+		return nil, nil, nil
+	case proxyV2CmdProxy:
+	default:
+		return nil, nil, fmt.Errorf("unsupported v2 command %d", command)
+	}
+
+	var ipLen int
+	switch family {
+	case proxyV2FamilyInet:
+		if length < proxyV2AddrLenInet {
+			return nil, nil, fmt.Errorf("v2 address block too short: %d bytes", length)
+		}
+		ipLen = net.IPv4len
+	case proxyV2FamilyInet6:
+		if length < proxyV2AddrLenInet6 {
+			return nil, nil, fmt.Errorf("v2 address block too short: %d bytes", length)
+		}
+		ipLen = net.IPv6len
+	default:
+		// Unix sockets and unspecified families carry no usable network address.
+		return nil, nil, nil
+	}
+
+	// Any TLVs following the addresses are ignored.
+	ports := payload[2*ipLen:]
+	src := &net.TCPAddr{
+		IP:   net.IP(payload[:ipLen]),
+		Port: int(binary.BigEndian.Uint16(ports[0:2])),
+	}
+	dst := &net.TCPAddr{
+		IP:   net.IP(payload[ipLen : 2*ipLen]),
+		Port: int(binary.BigEndian.Uint16(ports[2:4])),
+	}
+	return src, dst, nil
+}
+
+// bufferedConn is a net.Conn whose reads are served from a reader wrapping the
+// connection, such as a buffer holding data that was already read from it.
+type bufferedConn struct {
+	net.Conn
+	reader io.Reader
+}
+
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
+}
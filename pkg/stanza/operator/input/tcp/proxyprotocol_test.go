@@ -0,0 +1,130 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package tcp
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadProxyHeader(t *testing.T) {
+	cases := []struct {
+		name        string
+		input       string
+		expectedSrc net.Addr
+		expectedDst net.Addr
+		expectedErr string
+	}{
+		{
+			name:        "v1 tcp4",
+			input:       "PROXY TCP4 192.0.2.1 198.51.100.1 56324 514\r\nmessage\n",
+			expectedSrc: &net.TCPAddr{IP: net.ParseIP("192.0.2.1"), Port: 56324},
+			expectedDst: &net.TCPAddr{IP: net.ParseIP("198.51.100.1"), Port: 514},
+		},
+		{
+			name:        "v1 tcp6",
+			input:       "PROXY TCP6 2001:db8::1 2001:db8::2 56324 514\r\nmessage\n",
+			expectedSrc: &net.TCPAddr{IP: net.ParseIP("2001:db8::1"), Port: 56324},
+			expectedDst: &net.TCPAddr{IP: net.ParseIP("2001:db8::2"), Port: 514},
+		},
+		{
+			name:  "v1 unknown",
+			input: "PROXY UNKNOWN\r\nmessage\n",
+		},
+		{
+			name: "v2 tcp4",
+			input: "\r\n\r\n\x00\r\nQUIT\n" + "\x21\x11\x00\x0c" +
+				"\xc0\x00\x02\x01" + "\xc6\x33\x64\x01" + "\xdc\x04" + "\x02\x02" +
+				"message\n",
+			expectedSrc: &net.TCPAddr{IP: net.IPv4(192, 0, 2, 1).To4(), Port: 56324},
+			expectedDst: &net.TCPAddr{IP: net.IPv4(198, 51, 100, 1).To4(), Port: 514},
+		},
+		{
+			name: "v2 tcp4 with tlv",
+			input: "\r\n\r\n\x00\r\nQUIT\n" + "\x21\x11\x00\x10" +
+				"\xc0\x00\x02\x01" + "\xc6\x33\x64\x01" + "\xdc\x04" + "\x02\x02" + "\x04\x00\x01\x00" +
+				"message\n",
+			expectedSrc: &net.TCPAddr{IP: net.IPv4(192, 0, 2, 1).To4(), Port: 56324},
+			expectedDst: &net.TCPAddr{IP: net.IPv4(198, 51, 100, 1).To4(), Port: 514},
+		},
+		{
+			name: "v2 tcp6",
+			input: "\r\n\r\n\x00\r\nQUIT\n" + "\x21\x21\x00\x24" +
+				"\x20\x01\x0d\xb8\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x01" +
+				"\x20\x01\x0d\xb8\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x02" +
+				"\xdc\x04" + "\x02\x02" +
+				"message\n",
+			expectedSrc: &net.TCPAddr{IP: net.ParseIP("2001:db8::1"), Port: 56324},
+			expectedDst: &net.TCPAddr{IP: net.ParseIP("2001:db8::2"), Port: 514},
+		},
+		{
+			name:  "v2 local",
+			input: "\r\n\r\n\x00\r\nQUIT\n" + "\x20\x00\x00\x00" + "message\n",
+		},
+		{
+			name:        "missing header",
+			input:       "message without a header\n",
+			expectedErr: "connection does not start with a PROXY protocol header",
+		},
+		{
+			name:        "v1 not terminated",
+			input:       "PROXY TCP4 192.0.2.1 198.51.100.1 56324 514\nmessage\n",
+			expectedErr: "v1 header is not terminated by CRLF",
+		},
+		{
+			name:        "v1 too long",
+			input:       "PROXY TCP4 " + strings.Repeat("1", 200) + "\r\n",
+			expectedErr: "v1 header exceeds maximum length",
+		},
+		{
+			name:        "v1 invalid address",
+			input:       "PROXY TCP4 192.0.2 198.51.100.1 56324 514\r\n",
+			expectedErr: `invalid v1 source address: invalid ip "192.0.2"`,
+		},
+		{
+			name:        "v1 invalid port",
+			input:       "PROXY TCP4 192.0.2.1 198.51.100.1 56324 70000\r\n",
+			expectedErr: `invalid v1 destination address: invalid port "70000"`,
+		},
+		{
+			name:        "v2 invalid version",
+			input:       "\r\n\r\n\x00\r\nQUIT\n" + "\x11\x11\x00\x00",
+			expectedErr: "unsupported v2 header version 1",
+		},
+		{
+			name:        "v2 short address block",
+			input:       "\r\n\r\n\x00\r\nQUIT\n" + "\x21\x11\x00\x04" + "\xc0\x00\x02\x01",
+			expectedErr: "v2 address block too short: 4 bytes",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := bufio.NewReader(strings.NewReader(tc.input))
+			src, dst, err := readProxyHeader(r)
+			if tc.expectedErr != "" {
+				require.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			if tc.expectedSrc == nil {
+				require.Nil(t, src)
+				require.Nil(t, dst)
+			} else {
+				require.Equal(t, tc.expectedSrc.String(), src.String())
+				require.Equal(t, tc.expectedDst.String(), dst.String())
+			}
+
+			// The data following the header must be left unread.
+			rest, err := io.ReadAll(r)
+			require.NoError(t, err)
+			require.Equal(t, "message\n", string(rest))
+		})
+	}
+}
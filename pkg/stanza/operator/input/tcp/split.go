@@ -0,0 +1,59 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package tcp // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/input/tcp"
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+)
+
+// maxOctetCountDigits bounds the length prefix of a frame, so that a stream
+// which is not octet counted fails fast instead of being buffered.
+const maxOctetCountDigits = 10
+
+// octetCountingSplitFunc splits frames using the octet counting method described
+// in RFC 6587, where each message is prefixed by its length in bytes and a space.
+// The length prefix is not included in the returned tokens, but counts towards
+// the maximum size of a frame.
+func octetCountingSplitFunc(maxLogSize int) bufio.SplitFunc {
+	return func(data []byte, atEOF bool) (int, []byte, error) {
+		if len(data) == 0 {
+			return 0, nil, nil
+		}
+
+		space := bytes.IndexByte(data, ' ')
+		if space < 0 {
+			if len(data) > maxOctetCountDigits {
+				return 0, nil, fmt.Errorf("invalid octet counting frame: missing length prefix in %q", data[:maxOctetCountDigits])
+			}
+			if atEOF {
+				return 0, nil, fmt.Errorf("invalid octet counting frame: truncated length prefix %q", data)
+			}
+			return 0, nil, nil
+		}
+
+		prefix := data[:space]
+		if len(prefix) == 0 || len(prefix) > maxOctetCountDigits || prefix[0] < '1' || prefix[0] > '9' {
+			return 0, nil, fmt.Errorf("invalid octet counting frame: invalid length prefix %q", prefix)
+		}
+		length, err := strconv.Atoi(string(prefix))
+		if err != nil {
+			return 0, nil, fmt.Errorf("invalid octet counting frame: invalid length prefix %q", prefix)
+		}
+
+		end := space + 1 + length
+		if end > maxLogSize {
+			return 0, nil, fmt.Errorf("octet counting frame of %d bytes exceeds max_log_size %d", end, maxLogSize)
+		}
+		if end > len(data) {
+			if atEOF {
+				return 0, nil, fmt.Errorf("invalid octet counting frame: expected %d bytes, got %d", length, len(data)-space-1)
+			}
+			return 0, nil, nil
+		}
+		return end, data[space+1 : end], nil
+	}
+}
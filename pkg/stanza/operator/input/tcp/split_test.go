@@ -0,0 +1,76 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package tcp
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOctetCountingSplitFunc(t *testing.T) {
+	cases := []struct {
+		name        string
+		input       string
+		expected    []string
+		expectedErr string
+	}{
+		{
+			name:     "single frame",
+			input:    "7 message",
+			expected: []string{"message"},
+		},
+		{
+			name:     "multiple frames",
+			input:    "5 first6 second",
+			expected: []string{"first", "second"},
+		},
+		{
+			name:     "frames containing newlines",
+			input:    "12 first\nsecond10 third\nline",
+			expected: []string{"first\nsecond", "third\nline"},
+		},
+		{
+			name:        "missing length prefix",
+			input:       "message without length\n",
+			expectedErr: `invalid octet counting frame: invalid length prefix "message"`,
+		},
+		{
+			name:        "leading zero",
+			input:       "07 message",
+			expectedErr: `invalid octet counting frame: invalid length prefix "07"`,
+		},
+		{
+			name:        "truncated frame",
+			input:       "5 first10 second",
+			expected:    []string{"first"},
+			expectedErr: "invalid octet counting frame: expected 10 bytes, got 6",
+		},
+		{
+			name:        "frame too large",
+			input:       "100 message",
+			expectedErr: "octet counting frame of 104 bytes exceeds max_log_size 64",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			scanner := bufio.NewScanner(strings.NewReader(tc.input))
+			scanner.Split(octetCountingSplitFunc(64))
+
+			var tokens []string
+			for scanner.Scan() {
+				tokens = append(tokens, scanner.Text())
+			}
+			require.Equal(t, tc.expected, tokens)
+			if tc.expectedErr != "" {
+				require.EqualError(t, scanner.Err(), tc.expectedErr)
+			} else {
+				require.NoError(t, scanner.Err())
+			}
+		})
+	}
+}
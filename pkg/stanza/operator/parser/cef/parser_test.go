@@ -0,0 +1,143 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package cef
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
+)
+
+func newTestParser(t *testing.T) *Parser {
+	cfg := NewConfigWithID("test")
+	set := componenttest.NewNopTelemetrySettings()
+	op, err := cfg.Build(set)
+	require.NoError(t, err)
+	return op.(*Parser)
+}
+
+func TestInit(t *testing.T) {
+	builder, ok := operator.DefaultRegistry.Lookup("cef_parser")
+	require.True(t, ok, "expected cef_parser to be registered")
+	require.Equal(t, "cef_parser", builder().Type())
+}
+
+func TestParserBuildFailure(t *testing.T) {
+	cfg := NewConfigWithID("test")
+	cfg.OnError = "invalid_on_error"
+	set := componenttest.NewNopTelemetrySettings()
+	_, err := cfg.Build(set)
+	require.ErrorContains(t, err, "invalid `on_error` field")
+}
+
+func TestParserStringFailure(t *testing.T) {
+	parser := newTestParser(t)
+	_, err := parser.parse("invalid")
+	require.ErrorContains(t, err, `value does not contain a "CEF:" header`)
+}
+
+func TestParserInvalidType(t *testing.T) {
+	parser := newTestParser(t)
+	_, err := parser.parse([]int{})
+	require.ErrorContains(t, err, "type '[]int' cannot be parsed as CEF")
+}
+
+func TestProcess(t *testing.T) {
+	cases := []struct {
+		name   string
+		op     func() (operator.Operator, error)
+		input  *entry.Entry
+		expect *entry.Entry
+	}{
+		{
+			"default",
+			func() (operator.Operator, error) {
+				cfg := NewConfigWithID("test_id")
+				set := componenttest.NewNopTelemetrySettings()
+				return cfg.Build(set)
+			},
+			&entry.Entry{
+				Body: "CEF:0|Security|threatmanager|1.0|100|worm successfully stopped|10|src=10.0.0.1 dst=2.1.2.2 spt=1232",
+			},
+			&entry.Entry{
+				Attributes: map[string]any{
+					"version":               "0",
+					"device_vendor":         "Security",
+					"device_product":        "threatmanager",
+					"device_version":        "1.0",
+					"device_event_class_id": "100",
+					"name":                  "worm successfully stopped",
+					"severity":              "10",
+					"extensions": map[string]any{
+						"src": "10.0.0.1",
+						"dst": "2.1.2.2",
+						"spt": "1232",
+					},
+				},
+				Body: "CEF:0|Security|threatmanager|1.0|100|worm successfully stopped|10|src=10.0.0.1 dst=2.1.2.2 spt=1232",
+			},
+		},
+		{
+			"parse-from-syslog-message",
+			func() (operator.Operator, error) {
+				cfg := NewConfigWithID("test_id")
+				cfg.ParseFrom = entry.NewAttributeField("message")
+				cfg.ParseTo = entry.RootableField{Field: entry.NewAttributeField("cef")}
+				set := componenttest.NewNopTelemetrySettings()
+				return cfg.Build(set)
+			},
+			&entry.Entry{
+				Attributes: map[string]any{
+					"hostname": "host",
+					"message":  "CEF:0|Vendor|Product|1.0|200|Login|Low|suser=bob msg=user logged in",
+				},
+			},
+			&entry.Entry{
+				Attributes: map[string]any{
+					"hostname": "host",
+					"message":  "CEF:0|Vendor|Product|1.0|200|Login|Low|suser=bob msg=user logged in",
+					"cef": map[string]any{
+						"version":               "0",
+						"device_vendor":         "Vendor",
+						"device_product":        "Product",
+						"device_version":        "1.0",
+						"device_event_class_id": "200",
+						"name":                  "Login",
+						"severity":              "Low",
+						"extensions": map[string]any{
+							"suser": "bob",
+							"msg":   "user logged in",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			op, err := tc.op()
+			require.NoError(t, err, "did not expect operator function to return an error, this is a bug with the test case")
+
+			err = op.Process(context.Background(), tc.input)
+			require.NoError(t, err)
+			require.Equal(t, tc.expect, tc.input)
+		})
+	}
+}
+
+func BenchmarkParserParse(b *testing.B) {
+	v := `CEF:0|Trend Micro|Deep Security Manager|10.0|600|User Signed In|3|src=10.52.116.160 suser=admin target=admin msg=User signed in from 2001:db8::5 TrendMicroDsTenant=Primary TrendMicroDsTenantId=0`
+	parser := Parser{}
+	for n := 0; n < b.N; n++ {
+		if _, err := parser.parse(v); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
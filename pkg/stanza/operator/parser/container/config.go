@@ -21,7 +21,8 @@ import (
 const (
 	operatorType              = "container"
 	recombineSourceIdentifier = attrs.LogFilePath
-	recombineIsLastEntry      = "attributes.logtag == 'F'"
+	// CRI log tags may carry additional colon separated tags after the partial/full marker
+	recombineIsLastEntry = "attributes.logtag == 'F' || attributes.logtag startsWith 'F:'"
 )
 
 func init() {
@@ -47,9 +48,11 @@ func NewConfigWithID(operatorID string) *Config {
 type Config struct {
 	helper.ParserConfig `mapstructure:",squash"`
 
-	Format                  string          `mapstructure:"format"`
-	AddMetadataFromFilePath bool            `mapstructure:"add_metadata_from_filepath"`
-	MaxLogSize              helper.ByteSize `mapstructure:"max_log_size,omitempty"`
+	Format                     string          `mapstructure:"format"`
+	AddMetadataFromFilePath    bool            `mapstructure:"add_metadata_from_filepath"`
+	FilePathTemplate           string          `mapstructure:"filepath_template"`
+	AddMetadataFromDockerAttrs bool            `mapstructure:"add_metadata_from_docker_attrs"`
+	MaxLogSize                 helper.ByteSize `mapstructure:"max_log_size,omitempty"`
 }
 
 // Build will build a Container parser operator.
@@ -61,34 +64,56 @@ func (c Config) Build(set component.TelemetrySettings) (operator.Operator, error
 
 	if c.Format != "" {
 		switch c.Format {
-		case dockerFormat, crioFormat, containerdFormat:
+		case dockerFormat, crioFormat, containerdFormat, journaldFormat:
 		default:
 			return &Parser{}, stanza_errors.NewError(
 				"operator config has an invalid `format` field.",
-				"ensure that the `format` field is set to one of `docker`, `crio`, `containerd`.",
+				"ensure that the `format` field is set to one of `docker`, `crio`, `containerd`, `journald`.",
 				"format", c.OnError,
 			)
 		}
 	}
 
+	filePathMatcher := pathMatcher
+	if c.FilePathTemplate != "" {
+		filePathMatcher, err = compileFilePathTemplate(c.FilePathTemplate)
+		if err != nil {
+			return &Parser{}, stanza_errors.NewError(
+				"operator config has an invalid `filepath_template` field.",
+				err.Error(),
+				"filepath_template", c.FilePathTemplate,
+			)
+		}
+	}
+
 	wg := sync.WaitGroup{}
 
 	p := &Parser{
-		ParserOperator:          parserOperator,
-		format:                  c.Format,
-		addMetadataFromFilepath: c.AddMetadataFromFilePath,
-		criConsumers:            &wg,
+		ParserOperator:             parserOperator,
+		format:                     c.Format,
+		addMetadataFromFilepath:    c.AddMetadataFromFilePath,
+		addMetadataFromDockerAttrs: c.AddMetadataFromDockerAttrs,
+		pathMatcher:                filePathMatcher,
+		criConsumers:               &wg,
 	}
 
 	cLogEmitter := helper.NewBatchingLogEmitter(set, p.consumeEntries)
 	p.criLogEmitter = cLogEmitter
-	recombineParser, err := createRecombine(set, c, cLogEmitter)
+	recombineParser, err := createRecombine(set, createRecombineConfig(c), cLogEmitter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create internal recombine config: %w", err)
 	}
-
 	p.recombineParser = recombineParser
 
+	// the journald recombine operator is only needed when the entries may come from journald
+	if c.Format == "" || c.Format == journaldFormat {
+		journaldRecombineParser, err := createRecombine(set, createJournaldRecombineConfig(c), cLogEmitter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create internal journald recombine config: %w", err)
+		}
+		p.journaldRecombineParser = journaldRecombineParser
+	}
+
 	return p, nil
 }
 
@@ -97,12 +122,11 @@ func (c Config) Build(set component.TelemetrySettings) (operator.Operator, error
 //
 //	combine_field: body
 //	combine_with: ""
-//	is_last_entry: attributes.logtag == 'F'
+//	is_last_entry: attributes.logtag == 'F' || attributes.logtag startsWith 'F:'
 //	max_log_size: 102400
 //	source_identifier: attributes["log.file.path"]
 //	type: recombine
-func createRecombine(set component.TelemetrySettings, c Config, cLogEmitter *helper.BatchingLogEmitter) (operator.Operator, error) {
-	recombineParserCfg := createRecombineConfig(c)
+func createRecombine(set component.TelemetrySettings, recombineParserCfg *recombine.Config, cLogEmitter *helper.BatchingLogEmitter) (operator.Operator, error) {
 	recombineParser, err := recombineParserCfg.Build(set)
 	if err != nil {
 		return nil, fmt.Errorf("failed to resolve internal recombine config: %w", err)
@@ -126,3 +150,16 @@ func createRecombineConfig(c Config) *recombine.Config {
 	recombineParserCfg.MaxLogSize = c.MaxLogSize
 	return recombineParserCfg
 }
+
+// createJournaldRecombineConfig creates the recombine config used for partial messages of the
+// docker journald log driver. Journald entries have no file path, so partial messages are grouped
+// by the container ID instead.
+func createJournaldRecombineConfig(c Config) *recombine.Config {
+	recombineParserCfg := recombine.NewConfigWithID(journaldRecombineInternalID)
+	recombineParserCfg.IsLastEntry = recombineIsLastEntry
+	recombineParserCfg.CombineField = entry.NewBodyField()
+	recombineParserCfg.CombineWith = ""
+	recombineParserCfg.SourceIdentifier = entry.NewResourceField(containerIDField)
+	recombineParserCfg.MaxLogSize = c.MaxLogSize
+	return recombineParserCfg
+}
@@ -78,6 +78,30 @@ func TestConfig(t *testing.T) {
 					return cfg
 				}(),
 			},
+			{
+				Name: "filepath_template",
+				Expect: func() *Config {
+					cfg := NewConfig()
+					cfg.FilePathTemplate = "/var/log/containers/{namespace}/{pod_name}/{container_name}.log"
+					return cfg
+				}(),
+			},
+			{
+				Name: "add_metadata_from_docker_attrs",
+				Expect: func() *Config {
+					cfg := NewConfig()
+					cfg.AddMetadataFromDockerAttrs = true
+					return cfg
+				}(),
+			},
+			{
+				Name: "format_journald",
+				Expect: func() *Config {
+					cfg := NewConfig()
+					cfg.Format = "journald"
+					return cfg
+				}(),
+			},
 			{
 				Name: "max_log_size",
 				Expect: func() *Config {
@@ -27,14 +27,31 @@ const (
 	dockerFormat        = "docker"
 	crioFormat          = "crio"
 	containerdFormat    = "containerd"
+	journaldFormat      = "journald"
 	recombineInternalID = "recombine_container_internal"
-	dockerPattern       = "^\\{"
-	crioPattern         = "^(?P<time>[^ Z]+) (?P<stream>stdout|stderr) (?P<logtag>[^ ]*) ?(?P<log>.*)$"
-	containerdPattern   = "^(?P<time>[^ ^Z]+Z) (?P<stream>stdout|stderr) (?P<logtag>[^ ]*) ?(?P<log>.*)$"
-	logpathPattern      = "^.*(\\/|\\\\)(?P<namespace>[^_]+)_(?P<pod_name>[^_]+)_(?P<uid>[a-f0-9\\-]+)(\\/|\\\\)(?P<container_name>[^\\._]+)(\\/|\\\\)(?P<restart_count>\\d+)\\.log(\\.\\d{8}-\\d{6})?$"
-	logPathField        = attrs.LogFilePath
-	crioTimeLayout      = "2006-01-02T15:04:05.999999999Z07:00"
-	goTimeLayout        = "2006-01-02T15:04:05.999Z"
+
+	journaldRecombineInternalID = "recombine_container_journald_internal"
+	dockerPattern               = "^\\{"
+	crioPattern                 = "^(?P<time>[^ Z]+) (?P<stream>stdout|stderr) (?P<logtag>[^ ]*) ?(?P<log>.*)$"
+	containerdPattern           = "^(?P<time>[^ ^Z]+Z) (?P<stream>stdout|stderr) (?P<logtag>[^ ]*) ?(?P<log>.*)$"
+	logpathPattern              = "^.*(\\/|\\\\)(?P<namespace>[^_]+)_(?P<pod_name>[^_]+)_(?P<uid>[a-f0-9\\-]+)(\\/|\\\\)(?P<container_name>[^\\._]+)(\\/|\\\\)(?P<restart_count>\\d+)\\.log(\\.\\d{8}-\\d{6})?$"
+	logPathField                = attrs.LogFilePath
+	crioTimeLayout              = "2006-01-02T15:04:05.999999999Z07:00"
+	goTimeLayout                = "2006-01-02T15:04:05.999Z"
+	containerIDField            = "container.id"
+	dockerAttrsField            = "attrs"
+	dockerLabelPrefix           = "container.label."
+)
+
+// Fields written by the docker journald log driver
+const (
+	journaldMessageField        = "MESSAGE"
+	journaldPriorityField       = "PRIORITY"
+	journaldContainerIDField    = "CONTAINER_ID"
+	journaldContainerIDFull     = "CONTAINER_ID_FULL"
+	journaldContainerNameField  = "CONTAINER_NAME"
+	journaldImageNameField      = "IMAGE_NAME"
+	journaldPartialMessageField = "CONTAINER_PARTIAL_MESSAGE"
 )
 
 var (
@@ -49,25 +66,43 @@ var (
 		"stream": "log.iostream",
 	}
 	k8sMetadataMapping = map[string]string{
+		"container_id":   containerIDField,
 		"container_name": "k8s.container.name",
 		"namespace":      "k8s.namespace.name",
 		"pod_name":       "k8s.pod.name",
 		"restart_count":  "k8s.container.restart_count",
 		"uid":            "k8s.pod.uid",
 	}
+	journaldMetadataMapping = map[string]string{
+		journaldContainerNameField: "container.name",
+		journaldImageNameField:     "container.image.name",
+	}
+	// filePathTemplateGroups are the patterns matched by each placeholder of a filepath_template
+	filePathTemplateGroups = map[string]string{
+		"container_id":   `[a-f0-9]+`,
+		"container_name": `[^\\/]+?`,
+		"namespace":      `[^\\/]+?`,
+		"pod_name":       `[^\\/]+?`,
+		"restart_count":  `\d+`,
+		"uid":            `[a-f0-9\-]+`,
+	}
+	filePathTemplatePlaceholder = regexp.MustCompile(`\{([a-z_]+)\}`)
 )
 
 // Parser is an operator that parses Container logs.
 type Parser struct {
 	helper.ParserOperator
-	recombineParser         operator.Operator
-	format                  string
-	addMetadataFromFilepath bool
-	criLogEmitter           *helper.BatchingLogEmitter
-	asyncConsumerStarted    bool
-	criConsumerStartOnce    sync.Once
-	criConsumers            *sync.WaitGroup
-	timeLayout              string
+	recombineParser            operator.Operator
+	journaldRecombineParser    operator.Operator
+	format                     string
+	addMetadataFromFilepath    bool
+	addMetadataFromDockerAttrs bool
+	pathMatcher                *regexp.Regexp
+	criLogEmitter              *helper.BatchingLogEmitter
+	asyncConsumerStarted       bool
+	criConsumerStartOnce       sync.Once
+	criConsumers               *sync.WaitGroup
+	timeLayout                 string
 }
 
 func (p *Parser) ProcessBatch(ctx context.Context, entries []*entry.Entry) error {
@@ -92,19 +127,7 @@ func (p *Parser) Process(ctx context.Context, entry *entry.Entry) (err error) {
 			return fmt.Errorf("failed to process the docker log: %w", err)
 		}
 	case containerdFormat, crioFormat:
-		p.criConsumerStartOnce.Do(func() {
-			err = p.criLogEmitter.Start(nil)
-			if err != nil {
-				p.Logger().Error("unable to start the internal LogEmitter", zap.Error(err))
-				return
-			}
-			err = p.recombineParser.Start(nil)
-			if err != nil {
-				p.Logger().Error("unable to start the internal recombine operator", zap.Error(err))
-				return
-			}
-			p.asyncConsumerStarted = true
-		})
+		p.startAsyncConsumer()
 
 		// Short circuit if the "if" condition does not match
 		skip, err := p.Skip(ctx, entry)
@@ -141,6 +164,33 @@ func (p *Parser) Process(ctx context.Context, entry *entry.Entry) (err error) {
 		if err != nil {
 			return fmt.Errorf("failed to recombine the crio log: %w", err)
 		}
+	case journaldFormat:
+		p.startAsyncConsumer()
+
+		// Short circuit if the "if" condition does not match
+		skip, err := p.Skip(ctx, entry)
+		if err != nil {
+			return p.HandleEntryError(ctx, entry, err)
+		}
+		if skip {
+			return p.Write(ctx, entry)
+		}
+
+		err = p.ParseWith(ctx, entry, p.parseJournald)
+		if err != nil {
+			return fmt.Errorf("failed to parse journald log: %w", err)
+		}
+
+		err = p.handleJournaldMappings(entry)
+		if err != nil {
+			return fmt.Errorf("failed to handle attribute mappings: %w", err)
+		}
+
+		// send it to the recombine operator
+		err = p.journaldRecombineParser.Process(ctx, entry)
+		if err != nil {
+			return fmt.Errorf("failed to recombine the journald log: %w", err)
+		}
 	default:
 		return errors.New("failed to detect a valid container log format")
 	}
@@ -148,6 +198,30 @@ func (p *Parser) Process(ctx context.Context, entry *entry.Entry) (err error) {
 	return nil
 }
 
+// startAsyncConsumer starts the internal recombine operators and the internal criLogEmitter once
+func (p *Parser) startAsyncConsumer() {
+	p.criConsumerStartOnce.Do(func() {
+		err := p.criLogEmitter.Start(nil)
+		if err != nil {
+			p.Logger().Error("unable to start the internal LogEmitter", zap.Error(err))
+			return
+		}
+		err = p.recombineParser.Start(nil)
+		if err != nil {
+			p.Logger().Error("unable to start the internal recombine operator", zap.Error(err))
+			return
+		}
+		if p.journaldRecombineParser != nil {
+			err = p.journaldRecombineParser.Start(nil)
+			if err != nil {
+				p.Logger().Error("unable to start the internal journald recombine operator", zap.Error(err))
+				return
+			}
+		}
+		p.asyncConsumerStarted = true
+	})
+}
+
 // Stop ensures that the internal recombineParser, the internal criLogEmitter and
 // the crioConsumer are stopped in the proper order without being affected by
 // any possible race conditions
@@ -160,6 +234,11 @@ func (p *Parser) Stop() error {
 	if err := p.recombineParser.Stop(); err != nil {
 		errs = multierr.Append(errs, fmt.Errorf("unable to stop the internal recombine operator: %w", err))
 	}
+	if p.journaldRecombineParser != nil {
+		if err := p.journaldRecombineParser.Stop(); err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("unable to stop the internal journald recombine operator: %w", err))
+		}
+	}
 	// the recombineParser will call the Process of the criLogEmitter synchronously so the entries will be first
 	// written to the channel before the Stop of the recombineParser returns. Then since the criLogEmitter handles
 	// the entries synchronously it is safe to call its Stop.
@@ -178,6 +257,15 @@ func (p *Parser) detectFormat(e *entry.Entry) (string, error) {
 		return "", errors.New("entry cannot be parsed as container logs")
 	}
 
+	if fields, ok := value.(map[string]any); ok {
+		_, hasMessage := fields[journaldMessageField]
+		_, hasContainerID := fields[journaldContainerIDField]
+		if hasMessage && hasContainerID {
+			return journaldFormat, nil
+		}
+		return "", errors.New("entry cannot be parsed as container logs: missing journald container fields")
+	}
+
 	raw, ok := value.(string)
 	if !ok {
 		return "", fmt.Errorf("type '%T' cannot be parsed as container logs", value)
@@ -229,6 +317,75 @@ func (p *Parser) parseDocker(value any) (any, error) {
 	return parsedValue, nil
 }
 
+// parseJournald will parse the fields of an entry written by the docker journald log driver
+func (p *Parser) parseJournald(value any) (any, error) {
+	fields, ok := value.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("type '%T' cannot be parsed as journald container logs", value)
+	}
+
+	message, ok := fields[journaldMessageField]
+	if !ok {
+		return nil, fmt.Errorf("journald entry is missing the %s field", journaldMessageField)
+	}
+
+	parsedValue := map[string]any{
+		"log":    message,
+		"logtag": "F",
+	}
+	if partial, ok := fields[journaldPartialMessageField]; ok && partial == "true" {
+		parsedValue["logtag"] = "P"
+	}
+	// the journald log driver logs stdout with priority 6 (info) and stderr with priority 3 (err)
+	switch fields[journaldPriorityField] {
+	case "6":
+		parsedValue["stream"] = "stdout"
+	case "3":
+		parsedValue["stream"] = "stderr"
+	}
+
+	containerID, ok := fields[journaldContainerIDFull]
+	if !ok {
+		containerID, ok = fields[journaldContainerIDField]
+	}
+	if !ok {
+		return nil, fmt.Errorf("journald entry is missing the %s field", journaldContainerIDField)
+	}
+	parsedValue[containerIDField] = containerID
+	for originalKey := range journaldMetadataMapping {
+		if v, ok := fields[originalKey]; ok {
+			parsedValue[originalKey] = v
+		}
+	}
+	return parsedValue, nil
+}
+
+// handleJournaldMappings moves fields to final attributes and the container metadata to the resource
+func (p *Parser) handleJournaldMappings(e *entry.Entry) error {
+	err := moveFieldToBody(e, "log", "body")
+	if err != nil {
+		return err
+	}
+	if _, ok := e.Attributes["stream"]; ok {
+		if err = moveField(e, "stream", logFieldsMapping["stream"]); err != nil {
+			return err
+		}
+	}
+
+	if err = moveFieldToResource(e, containerIDField, containerIDField); err != nil {
+		return err
+	}
+	for originalKey, mappedKey := range journaldMetadataMapping {
+		if _, ok := e.Attributes[originalKey]; !ok {
+			continue
+		}
+		if err = moveFieldToResource(e, originalKey, mappedKey); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // handleTimeAndAttributeMappings handles fields' mappings and k8s meta extraction
 func (p *Parser) handleTimeAndAttributeMappings(e *entry.Entry) error {
 	err := parseTime(e, p.timeLayout)
@@ -240,6 +397,10 @@ func (p *Parser) handleTimeAndAttributeMappings(e *entry.Entry) error {
 	if err != nil {
 		return err
 	}
+	err = p.extractDockerAttrs(e)
+	if err != nil {
+		return err
+	}
 	err = p.extractk8sMetaFromFilePath(e)
 	if err != nil {
 		return err
@@ -278,20 +439,103 @@ func (p *Parser) extractk8sMetaFromFilePath(e *entry.Entry) error {
 		return fmt.Errorf("type '%T' cannot be parsed as log path field", logPath)
 	}
 
-	parsedValues, err := helper.MatchValues(rawLogPath, pathMatcher)
+	parsedValues, err := helper.MatchValues(rawLogPath, p.pathMatcher)
 	if err != nil {
 		return errors.New("failed to detect a valid log path")
 	}
 
-	for originalKey, attributeKey := range k8sMetadataMapping {
+	for originalKey, value := range parsedValues {
+		attributeKey, ok := k8sMetadataMapping[originalKey]
+		if !ok {
+			continue
+		}
 		newField := entry.NewResourceField(attributeKey)
-		if err := newField.Set(e, parsedValues[originalKey]); err != nil {
+		if err := newField.Set(e, value); err != nil {
 			return fmt.Errorf("failed to set %v as metadata at %v", originalKey, attributeKey)
 		}
 	}
 	return nil
 }
 
+// extractDockerAttrs moves the `attrs` set by the docker `labels` and `env` log options to
+// resource attributes prefixed with `container.label.`
+func (p *Parser) extractDockerAttrs(e *entry.Entry) error {
+	if !p.addMetadataFromDockerAttrs {
+		return nil
+	}
+
+	val, exist := entry.NewAttributeField(dockerAttrsField).Delete(e)
+	if !exist {
+		return nil
+	}
+	dockerAttrs, ok := val.(map[string]any)
+	if !ok {
+		return fmt.Errorf("type '%T' cannot be parsed as docker attrs", val)
+	}
+
+	for key, value := range dockerAttrs {
+		newField := entry.NewResourceField(dockerLabelPrefix + key)
+		if err := newField.Set(e, value); err != nil {
+			return fmt.Errorf("failed to set docker attr %v as metadata", key)
+		}
+	}
+	return nil
+}
+
+// compileFilePathTemplate converts a filepath_template into a regexp. Placeholders such as
+// `{namespace}` become named groups, `*` matches any characters within a path segment and
+// `**` matches any characters across path segments. Both `/` and `\` are accepted as separators.
+func compileFilePathTemplate(template string) (*regexp.Regexp, error) {
+	var pattern strings.Builder
+	pattern.WriteString("^")
+
+	placeholders := make(map[string]bool)
+	for template != "" {
+		switch {
+		case strings.HasPrefix(template, "{"):
+			loc := filePathTemplatePlaceholder.FindStringSubmatchIndex(template)
+			if loc == nil || loc[0] != 0 {
+				return nil, fmt.Errorf("invalid placeholder in template at %q", template)
+			}
+			name := template[loc[2]:loc[3]]
+			group, ok := filePathTemplateGroups[name]
+			if !ok {
+				return nil, fmt.Errorf("unknown placeholder {%s}", name)
+			}
+			// regexp doesn't support back-references, so a placeholder can't match twice
+			if placeholders[name] {
+				return nil, fmt.Errorf("placeholder {%s} is repeated, each placeholder can only be used once", name)
+			}
+			fmt.Fprintf(&pattern, "(?P<%s>%s)", name, group)
+			placeholders[name] = true
+			template = template[loc[1]:]
+		case strings.HasPrefix(template, "**"):
+			pattern.WriteString(".*")
+			template = template[2:]
+		case strings.HasPrefix(template, "*"):
+			pattern.WriteString(`[^\\/]*`)
+			template = template[1:]
+		case template[0] == '/' || template[0] == '\\':
+			pattern.WriteString(`(\/|\\)`)
+			template = template[1:]
+		default:
+			end := strings.IndexAny(template, `{*/\`)
+			if end < 0 {
+				end = len(template)
+			}
+			pattern.WriteString(regexp.QuoteMeta(template[:end]))
+			template = template[end:]
+		}
+	}
+	if len(placeholders) == 0 {
+		return nil, errors.New("template must contain at least one placeholder")
+	}
+	// rotated log files have a timestamp suffix
+	pattern.WriteString(`(\.\d{8}-\d{6})?$`)
+
+	return regexp.Compile(pattern.String())
+}
+
 func (p *Parser) consumeEntries(ctx context.Context, entries []*entry.Entry) {
 	for _, e := range entries {
 		err := p.Write(ctx, e)
@@ -325,6 +569,17 @@ func moveFieldToBody(e *entry.Entry, originalKey, mappedKey string) error {
 	return nil
 }
 
+func moveFieldToResource(e *entry.Entry, originalKey, mappedKey string) error {
+	val, exist := entry.NewAttributeField(originalKey).Delete(e)
+	if !exist {
+		return fmt.Errorf("move: field %v does not exist", originalKey)
+	}
+	if err := entry.NewResourceField(mappedKey).Set(e, val); err != nil {
+		return fmt.Errorf("failed to move %v to %v", originalKey, mappedKey)
+	}
+	return nil
+}
+
 func parseTime(e *entry.Entry, layout string) error {
 	var location *time.Location
 	parseFrom := "time"
@@ -80,7 +80,7 @@ func TestFormatDetectionFailure(t *testing.T) {
 func TestInternalRecombineCfg(t *testing.T) {
 	cfg := createRecombineConfig(Config{MaxLogSize: 102400})
 	expected := recombine.NewConfigWithID(recombineInternalID)
-	expected.IsLastEntry = "attributes.logtag == 'F'"
+	expected.IsLastEntry = "attributes.logtag == 'F' || attributes.logtag startsWith 'F:'"
 	expected.CombineField = entry.NewBodyField()
 	expected.CombineWith = ""
 	expected.SourceIdentifier = entry.NewAttributeField(attrs.LogFilePath)
@@ -88,6 +88,91 @@ func TestInternalRecombineCfg(t *testing.T) {
 	require.Equal(t, expected, cfg)
 }
 
+func TestInternalJournaldRecombineCfg(t *testing.T) {
+	cfg := createJournaldRecombineConfig(Config{MaxLogSize: 102400})
+	expected := recombine.NewConfigWithID(journaldRecombineInternalID)
+	expected.IsLastEntry = "attributes.logtag == 'F' || attributes.logtag startsWith 'F:'"
+	expected.CombineField = entry.NewBodyField()
+	expected.CombineWith = ""
+	expected.SourceIdentifier = entry.NewResourceField("container.id")
+	expected.MaxLogSize = 102400
+	require.Equal(t, expected, cfg)
+}
+
+func TestConfigBuildJournaldRecombine(t *testing.T) {
+	for format, built := range map[string]bool{
+		"":               true,
+		journaldFormat:   true,
+		dockerFormat:     false,
+		crioFormat:       false,
+		containerdFormat: false,
+	} {
+		t.Run(format, func(t *testing.T) {
+			config := NewConfigWithID("test")
+			config.Format = format
+			op, err := config.Build(componenttest.NewNopTelemetrySettings())
+			require.NoError(t, err)
+			if built {
+				require.NotNil(t, op.(*Parser).journaldRecombineParser)
+			} else {
+				require.Nil(t, op.(*Parser).journaldRecombineParser)
+			}
+		})
+	}
+}
+
+func TestConfigBuildFilePathTemplateError(t *testing.T) {
+	cases := []struct {
+		name     string
+		template string
+		expected string
+	}{
+		{
+			"no_placeholder",
+			"/var/log/containers/app.log",
+			"template must contain at least one placeholder",
+		},
+		{
+			"unknown_placeholder",
+			"/var/log/{namespace}/{unknown}.log",
+			"unknown placeholder {unknown}",
+		},
+		{
+			"unterminated_placeholder",
+			"/var/log/{namespace",
+			"invalid placeholder in template",
+		},
+		{
+			"repeated_placeholder",
+			"/var/log/{namespace}/{pod_name}/{namespace}.log",
+			"placeholder {namespace} is repeated, each placeholder can only be used once",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := NewConfigWithID("test")
+			config.FilePathTemplate = tc.template
+			set := componenttest.NewNopTelemetrySettings()
+			_, err := config.Build(set)
+			require.ErrorContains(t, err, "invalid `filepath_template` field")
+			require.ErrorContains(t, err, tc.expected)
+		})
+	}
+}
+
+func TestJournaldParserInvalidType(t *testing.T) {
+	parser := newTestParser(t)
+	_, err := parser.parseJournald("invalid")
+	require.ErrorContains(t, err, "type 'string' cannot be parsed as journald container logs")
+}
+
+func TestJournaldParserMissingContainerID(t *testing.T) {
+	parser := newTestParser(t)
+	_, err := parser.parseJournald(map[string]any{"MESSAGE": "hello"})
+	require.ErrorContains(t, err, "journald entry is missing the CONTAINER_ID field")
+}
+
 func TestProcess(t *testing.T) {
 	cases := []struct {
 		name   string
@@ -164,6 +249,106 @@ func TestProcess(t *testing.T) {
 				Timestamp: time.Date(2029, time.March, 30, 8, 31, 20, 545192187, time.UTC),
 			},
 		},
+		{
+			"docker_with_attrs",
+			func() (operator.Operator, error) {
+				cfg := NewConfigWithID("test_id")
+				cfg.AddMetadataFromFilePath = false
+				cfg.AddMetadataFromDockerAttrs = true
+				set := componenttest.NewNopTelemetrySettings()
+				return cfg.Build(set)
+			},
+			&entry.Entry{
+				Body: `{"log":"INFO: log line here","stream":"stdout","attrs":{"com.example.team":"payments","tag":"web"},"time":"2029-03-30T08:31:20.545192187Z"}`,
+			},
+			&entry.Entry{
+				Attributes: map[string]any{
+					"log.iostream": "stdout",
+				},
+				Body: "INFO: log line here",
+				Resource: map[string]any{
+					"container.label.com.example.team": "payments",
+					"container.label.tag":              "web",
+				},
+				Timestamp: time.Date(2029, time.March, 30, 8, 31, 20, 545192187, time.UTC),
+			},
+		},
+		{
+			"docker_with_attrs_disabled",
+			func() (operator.Operator, error) {
+				cfg := NewConfigWithID("test_id")
+				cfg.AddMetadataFromFilePath = false
+				set := componenttest.NewNopTelemetrySettings()
+				return cfg.Build(set)
+			},
+			&entry.Entry{
+				Body: `{"log":"INFO: log line here","stream":"stdout","attrs":{"tag":"web"},"time":"2029-03-30T08:31:20.545192187Z"}`,
+			},
+			&entry.Entry{
+				Attributes: map[string]any{
+					"log.iostream": "stdout",
+					"attrs": map[string]any{
+						"tag": "web",
+					},
+				},
+				Body:      "INFO: log line here",
+				Timestamp: time.Date(2029, time.March, 30, 8, 31, 20, 545192187, time.UTC),
+			},
+		},
+		{
+			"docker_with_filepath_template",
+			func() (operator.Operator, error) {
+				cfg := NewConfigWithID("test_id")
+				cfg.FilePathTemplate = "**/containers/{container_id}/*-json.log"
+				set := componenttest.NewNopTelemetrySettings()
+				return cfg.Build(set)
+			},
+			&entry.Entry{
+				Body: `{"log":"INFO: log line here","stream":"stdout","time":"2029-03-30T08:31:20.545192187Z"}`,
+				Attributes: map[string]any{
+					attrs.LogFilePath: "/var/lib/docker/containers/0bf7a3b1c9e2/0bf7a3b1c9e2-json.log",
+				},
+			},
+			&entry.Entry{
+				Attributes: map[string]any{
+					"log.iostream":    "stdout",
+					attrs.LogFilePath: "/var/lib/docker/containers/0bf7a3b1c9e2/0bf7a3b1c9e2-json.log",
+				},
+				Body: "INFO: log line here",
+				Resource: map[string]any{
+					"container.id": "0bf7a3b1c9e2",
+				},
+				Timestamp: time.Date(2029, time.March, 30, 8, 31, 20, 545192187, time.UTC),
+			},
+		},
+		{
+			"docker_with_k8s_filepath_template",
+			func() (operator.Operator, error) {
+				cfg := NewConfigWithID("test_id")
+				cfg.FilePathTemplate = "/data/logs/{namespace}/{pod_name}/{container_name}.log"
+				set := componenttest.NewNopTelemetrySettings()
+				return cfg.Build(set)
+			},
+			&entry.Entry{
+				Body: `{"log":"INFO: log line here","stream":"stdout","time":"2029-03-30T08:31:20.545192187Z"}`,
+				Attributes: map[string]any{
+					attrs.LogFilePath: "/data/logs/some/kube-scheduler-kind-control-plane/kube-scheduler44.log",
+				},
+			},
+			&entry.Entry{
+				Attributes: map[string]any{
+					"log.iostream":    "stdout",
+					attrs.LogFilePath: "/data/logs/some/kube-scheduler-kind-control-plane/kube-scheduler44.log",
+				},
+				Body: "INFO: log line here",
+				Resource: map[string]any{
+					"k8s.pod.name":       "kube-scheduler-kind-control-plane",
+					"k8s.container.name": "kube-scheduler44",
+					"k8s.namespace.name": "some",
+				},
+				Timestamp: time.Date(2029, time.March, 30, 8, 31, 20, 545192187, time.UTC),
+			},
+		},
 	}
 
 	for _, tc := range cases {
@@ -450,6 +635,90 @@ func TestRecombineProcess(t *testing.T) {
 				},
 			},
 		},
+		{
+			"crio_multiple_with_additional_tags",
+			func() (operator.Operator, error) {
+				cfg := NewConfigWithID("test_id")
+				cfg.AddMetadataFromFilePath = false
+				set := componenttest.NewNopTelemetrySettings()
+				return cfg.Build(set)
+			},
+			[]*entry.Entry{
+				{
+					Body: `2024-04-13T07:59:37.505201169-10:00 stdout P:x standalone crio line which i`,
+					Attributes: map[string]any{
+						attrs.LogFilePath: "/var/log/pods/some_kube-scheduler-kind-control-plane_49cc7c1fd3702c40b2686ea7486091d3/kube-scheduler44/1.log",
+					},
+				},
+				{
+					Body: `2024-04-13T07:59:37.505201169-10:00 stdout F:x s awesome!`,
+					Attributes: map[string]any{
+						attrs.LogFilePath: "/var/log/pods/some_kube-scheduler-kind-control-plane_49cc7c1fd3702c40b2686ea7486091d3/kube-scheduler44/1.log",
+					},
+				},
+			},
+			[]*entry.Entry{
+				{
+					Attributes: map[string]any{
+						"log.iostream":    "stdout",
+						"logtag":          "P:x",
+						attrs.LogFilePath: "/var/log/pods/some_kube-scheduler-kind-control-plane_49cc7c1fd3702c40b2686ea7486091d3/kube-scheduler44/1.log",
+					},
+					Body:      "standalone crio line which is awesome!",
+					Timestamp: time.Date(2024, time.April, 13, 7, 59, 37, 505201169, time.FixedZone("", -10*60*60)),
+				},
+			},
+		},
+		{
+			"journald_multiple_with_auto_detection",
+			func() (operator.Operator, error) {
+				cfg := NewConfigWithID("test_id")
+				set := componenttest.NewNopTelemetrySettings()
+				return cfg.Build(set)
+			},
+			[]*entry.Entry{
+				{
+					Body: map[string]any{
+						"MESSAGE":                   "standalone journald line which i",
+						"PRIORITY":                  "3",
+						"CONTAINER_ID":              "0bf7a3b1c9e2",
+						"CONTAINER_ID_FULL":         "0bf7a3b1c9e2d4f6a8b0c2e4f6a8b0c2e4f6a8b0c2e4f6a8b0c2e4f6a8b0c2e4",
+						"CONTAINER_NAME":            "web",
+						"IMAGE_NAME":                "nginx:latest",
+						"CONTAINER_PARTIAL_MESSAGE": "true",
+						"SYSLOG_IDENTIFIER":         "0bf7a3b1c9e2",
+					},
+					Timestamp: time.Date(2024, time.April, 13, 7, 59, 37, 505201000, time.UTC),
+				},
+				{
+					Body: map[string]any{
+						"MESSAGE":           "s awesome!",
+						"PRIORITY":          "3",
+						"CONTAINER_ID":      "0bf7a3b1c9e2",
+						"CONTAINER_ID_FULL": "0bf7a3b1c9e2d4f6a8b0c2e4f6a8b0c2e4f6a8b0c2e4f6a8b0c2e4f6a8b0c2e4",
+						"CONTAINER_NAME":    "web",
+						"IMAGE_NAME":        "nginx:latest",
+						"SYSLOG_IDENTIFIER": "0bf7a3b1c9e2",
+					},
+					Timestamp: time.Date(2024, time.April, 13, 7, 59, 37, 505202000, time.UTC),
+				},
+			},
+			[]*entry.Entry{
+				{
+					Attributes: map[string]any{
+						"log.iostream": "stderr",
+						"logtag":       "P",
+					},
+					Body: "standalone journald line which is awesome!",
+					Resource: map[string]any{
+						"container.id":         "0bf7a3b1c9e2d4f6a8b0c2e4f6a8b0c2e4f6a8b0c2e4f6a8b0c2e4f6a8b0c2e4",
+						"container.name":       "web",
+						"container.image.name": "nginx:latest",
+					},
+					Timestamp: time.Date(2024, time.April, 13, 7, 59, 37, 505201000, time.UTC),
+				},
+			},
+		},
 	}
 
 	for _, tc := range cases {
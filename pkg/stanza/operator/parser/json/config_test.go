@@ -28,6 +28,14 @@ func TestConfig(t *testing.T) {
 					return cfg
 				}(),
 			},
+			{
+				Name: "dead_letter_output",
+				Expect: func() *Config {
+					cfg := NewConfig()
+					cfg.DeadLetterOutput = "failed_json"
+					return cfg
+				}(),
+			},
 			{
 				Name: "parse_from_simple",
 				Expect: func() *Config {
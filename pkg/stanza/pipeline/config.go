@@ -42,28 +42,58 @@ func (c Config) Build(set component.TelemetrySettings) (*DirectedPipeline, error
 		ops = append(ops, op)
 	}
 
-	for i, op := range ops {
+	// Dead letter outputs only receive entries that failed processing, so they
+	// are not chained to by default.
+	deadLetterIDs := make(map[string]bool)
+	for _, op := range ops {
+		if dl, ok := op.(deadLetterWriter); ok && dl.DeadLetterOutput() != "" {
+			deadLetterIDs[dl.DeadLetterOutput()] = true
+		}
+	}
+
+	numOps := len(ops)
+	defaultOutputAdded := false
+	for i := 0; i < numOps; i++ {
+		op := ops[i]
+
 		// Any operator that already has an output will not be changed
 		if len(op.GetOutputIDs()) > 0 {
 			continue
 		}
 
-		// Any operator (except the last) will just output to the next
-		if i+1 < len(ops) {
-			op.SetOutputIDs([]string{ops[i+1].ID()})
+		// Any operator will just output to the next one that is not a dead letter output
+		if next := nextOperator(ops[i+1:numOps], deadLetterIDs); next != nil {
+			op.SetOutputIDs([]string{next.ID()})
 			continue
 		}
 
 		// The last operator may output to the default output
 		if op.CanOutput() && c.DefaultOutput != nil {
-			ops = append(ops, c.DefaultOutput)
-			op.SetOutputIDs([]string{ops[i+1].ID()})
+			if !defaultOutputAdded {
+				ops = append(ops, c.DefaultOutput)
+				defaultOutputAdded = true
+			}
+			op.SetOutputIDs([]string{c.DefaultOutput.ID()})
 		}
 	}
 
 	return NewDirectedPipeline(ops)
 }
 
+// deadLetterWriter is implemented by operators that may send failed entries to a dead letter output.
+type deadLetterWriter interface {
+	DeadLetterOutput() string
+}
+
+func nextOperator(ops []operator.Operator, skip map[string]bool) operator.Operator {
+	for _, op := range ops {
+		if !skip[op.ID()] {
+			return op
+		}
+	}
+	return nil
+}
+
 func dedeplucateIDs(ops []operator.Config) {
 	typeMap := make(map[string]int)
 	for _, op := range ops {
@@ -248,6 +248,54 @@ func TestDeduplicateIDs(t *testing.T) {
 	}
 }
 
+func TestBuildAPipelineDeadLetterOutput(t *testing.T) {
+	parserCfg := json.NewConfigWithID("json_parser")
+	parserCfg.DeadLetterOutput = "dead_letter"
+
+	cfg := Config{
+		Operators: []operator.Config{
+			{Builder: parserCfg},
+			{Builder: noop.NewConfigWithID("dead_letter")},
+			{Builder: noop.NewConfigWithID("noop")},
+		},
+		DefaultOutput: testutil.NewFakeOutput(t),
+	}
+
+	set := componenttest.NewNopTelemetrySettings()
+	pipe, err := cfg.Build(set)
+	require.NoError(t, err)
+
+	ops := pipe.Operators()
+	require.Len(t, ops, 4)
+
+	outputs := make(map[string][]string)
+	for _, op := range ops {
+		outputs[op.ID()] = op.GetOutputIDs()
+	}
+	require.Equal(t, map[string][]string{
+		"json_parser": {"noop"},
+		"dead_letter": {"fake"},
+		"noop":        {"fake"},
+		"fake":        nil,
+	}, outputs)
+}
+
+func TestBuildAPipelineMissingDeadLetterOutput(t *testing.T) {
+	parserCfg := json.NewConfigWithID("json_parser")
+	parserCfg.DeadLetterOutput = "dead_letter"
+
+	cfg := Config{
+		Operators: []operator.Config{
+			{Builder: parserCfg},
+		},
+		DefaultOutput: testutil.NewFakeOutput(t),
+	}
+
+	set := componenttest.NewNopTelemetrySettings()
+	_, err := cfg.Build(set)
+	require.ErrorContains(t, err, "dead letter output 'dead_letter' does not exist")
+}
+
 func TestUpdateOutputIDs(t *testing.T) {
 	cases := []struct {
 		defaultOut operator.Operator
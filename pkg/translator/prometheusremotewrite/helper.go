@@ -492,10 +492,26 @@ func (c *prometheusConverter) getOrCreateTimeSeries(lbls []prompb.Label) (*promp
 
 // addResourceTargetInfo converts the resource to the target info metric.
 func addResourceTargetInfo(resource pcommon.Resource, settings Settings, timestamp pcommon.Timestamp, converter *prometheusConverter) {
-	if settings.DisableTargetInfo || timestamp == 0 {
+	labels := targetInfoLabels(resource, settings, timestamp)
+	if labels == nil {
 		return
 	}
 
+	sample := &prompb.Sample{
+		Value: float64(1),
+		// convert ns to ms
+		Timestamp: convertTimeStamp(timestamp),
+	}
+	converter.addSample(sample, labels)
+}
+
+// targetInfoLabels returns the labels of the target_info metric for the resource, or nil if the target_info metric
+// should not be generated.
+func targetInfoLabels(resource pcommon.Resource, settings Settings, timestamp pcommon.Timestamp) []prompb.Label {
+	if settings.DisableTargetInfo || timestamp == 0 {
+		return nil
+	}
+
 	attributes := resource.Attributes()
 	identifyingAttrs := []string{
 		string(conventions.ServiceNamespaceKey),
@@ -511,7 +527,7 @@ func addResourceTargetInfo(resource pcommon.Resource, settings Settings, timesta
 	}
 	if nonIdentifyingAttrsCount == 0 {
 		// If we only have job + instance, then target_info isn't useful, so don't add it.
-		return
+		return nil
 	}
 
 	name := prometheustranslator.TargetInfoMetricName
@@ -520,25 +536,14 @@ func addResourceTargetInfo(resource pcommon.Resource, settings Settings, timesta
 	}
 
 	labels := createAttributes(resource, attributes, settings.ExternalLabels, identifyingAttrs, false, model.MetricNameLabel, name)
-	haveIdentifier := false
 	for _, l := range labels {
 		if l.Name == model.JobLabel || l.Name == model.InstanceLabel {
-			haveIdentifier = true
-			break
+			return labels
 		}
 	}
 
-	if !haveIdentifier {
-		// We need at least one identifying label to generate target_info.
-		return
-	}
-
-	sample := &prompb.Sample{
-		Value: float64(1),
-		// convert ns to ms
-		Timestamp: convertTimeStamp(timestamp),
-	}
-	converter.addSample(sample, labels)
+	// We need at least one identifying label to generate target_info.
+	return nil
 }
 
 // convertTimeStamp converts OTLP timestamp in ns to timestamp in ms
@@ -0,0 +1,221 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewrite
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/value"
+	writev2 "github.com/prometheus/prometheus/prompb/io/prometheus/write/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	conventions "go.opentelemetry.io/otel/semconv/v1.25.0"
+
+	prometheustranslator "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheus"
+)
+
+// seriesByLabelsV2 returns the time series of the converter indexed by their labels, so that the tests
+// don't depend on the order in which the symbols were added to the symbols table.
+func seriesByLabelsV2(t *testing.T, c *prometheusConverterV2) map[string]writev2.TimeSeries {
+	t.Helper()
+	b := labels.NewScratchBuilder(0)
+	symbols := c.symbolTable.Symbols()
+	out := map[string]writev2.TimeSeries{}
+	for _, ts := range c.timeSeries() {
+		out[ts.ToLabels(&b, symbols).String()] = ts
+	}
+	require.Len(t, out, len(c.unique))
+	return out
+}
+
+// exemplarLabelsV2 resolves the labels of the exemplar from the symbols table.
+func exemplarLabelsV2(t *testing.T, symbols []string, e writev2.Exemplar) map[string]string {
+	t.Helper()
+	require.Zero(t, len(e.LabelsRefs)%2)
+	out := map[string]string{}
+	for i := 0; i < len(e.LabelsRefs); i += 2 {
+		out[symbols[e.LabelsRefs[i]]] = symbols[e.LabelsRefs[i+1]]
+	}
+	return out
+}
+
+func TestPrometheusConverterV2_addHistogramDataPoints(t *testing.T) {
+	ts := pcommon.Timestamp(time.Now().UnixNano())
+	startTs := pcommon.Timestamp(time.Now().Add(-time.Minute).UnixNano())
+
+	metric := getHistogramMetric("test_hist", pcommon.NewMap(), pmetric.AggregationTemporalityCumulative, uint64(ts), 10, 3, []float64{1, 2}, []uint64{1, 1, 1})
+	metric.SetDescription("test histogram")
+	metric.SetUnit("s")
+	dp := metric.Histogram().DataPoints().At(0)
+	dp.SetStartTimestamp(startTs)
+	exemplar := dp.Exemplars().AppendEmpty()
+	exemplar.SetDoubleValue(1.5)
+	exemplar.SetTimestamp(ts)
+	exemplar.SetTraceID(pcommon.TraceID([16]byte{1}))
+
+	converter := newPrometheusConverterV2()
+	metadata := converter.metadata(metric, writev2.Metadata_METRIC_TYPE_HISTOGRAM)
+	converter.addHistogramDataPoints(metric.Histogram().DataPoints(), pcommon.NewResource(), Settings{}, metric.Name(), metadata)
+
+	symbols := converter.symbolTable.Symbols()
+	assert.Equal(t, "test histogram", symbols[metadata.HelpRef])
+	assert.Equal(t, "s", symbols[metadata.UnitRef])
+
+	want := map[string]float64{
+		`{__name__="test_hist_sum"}`:               10,
+		`{__name__="test_hist_count"}`:             3,
+		`{__name__="test_hist_bucket", le="1"}`:    1,
+		`{__name__="test_hist_bucket", le="2"}`:    2,
+		`{__name__="test_hist_bucket", le="+Inf"}`: 3,
+	}
+	series := seriesByLabelsV2(t, converter)
+	require.Len(t, series, len(want))
+	for lbls, v := range want {
+		s, ok := series[lbls]
+		require.True(t, ok, lbls)
+		assert.Equal(t, metadata, s.Metadata)
+		assert.Equal(t, convertTimeStamp(startTs), s.CreatedTimestamp)
+		assert.Equal(t, []writev2.Sample{{Value: v, Timestamp: convertTimeStamp(ts)}}, s.Samples)
+		if lbls != `{__name__="test_hist_bucket", le="2"}` {
+			assert.Empty(t, s.Exemplars, lbls)
+		}
+	}
+
+	bucket := series[`{__name__="test_hist_bucket", le="2"}`]
+	require.Len(t, bucket.Exemplars, 1)
+	assert.Equal(t, 1.5, bucket.Exemplars[0].Value)
+	assert.Equal(t, convertTimeStamp(ts), bucket.Exemplars[0].Timestamp)
+	assert.Equal(t, map[string]string{
+		prometheustranslator.ExemplarTraceIDKey: "01000000000000000000000000000000",
+	}, exemplarLabelsV2(t, symbols, bucket.Exemplars[0]))
+}
+
+func TestPrometheusConverterV2_addHistogramDataPointsStaleNaN(t *testing.T) {
+	ts := uint64(time.Now().UnixNano())
+	metric := getHistogramMetric("staleNaN_hist", pcommon.NewMap(), pmetric.AggregationTemporalityCumulative, ts, 10, 3, []float64{1}, []uint64{1, 2})
+
+	converter := newPrometheusConverterV2()
+	converter.addHistogramDataPoints(metric.Histogram().DataPoints(), pcommon.NewResource(), Settings{}, metric.Name(), writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_HISTOGRAM})
+
+	series := seriesByLabelsV2(t, converter)
+	require.Len(t, series, 4)
+	for lbls, s := range series {
+		require.Len(t, s.Samples, 1, lbls)
+		assert.True(t, value.IsStaleNaN(s.Samples[0].Value), lbls)
+	}
+}
+
+func TestPrometheusConverterV2_addSummaryDataPoints(t *testing.T) {
+	ts := pcommon.Timestamp(time.Now().UnixNano())
+	startTs := pcommon.Timestamp(time.Now().Add(-time.Minute).UnixNano())
+
+	quantiles := pmetric.NewSummaryDataPointValueAtQuantileSlice()
+	q := quantiles.AppendEmpty()
+	q.SetQuantile(0.5)
+	q.SetValue(2)
+	q = quantiles.AppendEmpty()
+	q.SetQuantile(0.99)
+	q.SetValue(5)
+	metric := getSummaryMetric("test_summary", getAttributes("foo", "bar"), uint64(ts), 12, 4, quantiles)
+	metric.Summary().DataPoints().At(0).SetStartTimestamp(startTs)
+
+	converter := newPrometheusConverterV2()
+	metadata := writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_SUMMARY}
+	converter.addSummaryDataPoints(metric.Summary().DataPoints(), pcommon.NewResource(), Settings{}, metric.Name(), metadata)
+
+	want := map[string]float64{
+		`{__name__="test_summary_sum", foo="bar"}`:              12,
+		`{__name__="test_summary_count", foo="bar"}`:            4,
+		`{__name__="test_summary", foo="bar", quantile="0.5"}`:  2,
+		`{__name__="test_summary", foo="bar", quantile="0.99"}`: 5,
+	}
+	series := seriesByLabelsV2(t, converter)
+	require.Len(t, series, len(want))
+	for lbls, v := range want {
+		s, ok := series[lbls]
+		require.True(t, ok, lbls)
+		assert.Equal(t, metadata, s.Metadata)
+		assert.Equal(t, convertTimeStamp(startTs), s.CreatedTimestamp)
+		assert.Equal(t, []writev2.Sample{{Value: v, Timestamp: convertTimeStamp(ts)}}, s.Samples)
+	}
+}
+
+func TestAddResourceTargetInfoV2(t *testing.T) {
+	resource := pcommon.NewResource()
+	resource.Attributes().PutStr(string(conventions.ServiceNameKey), "service-name")
+	resource.Attributes().PutStr(string(conventions.ServiceInstanceIDKey), "service-instance-id")
+	resource.Attributes().PutStr("resource_attr", "resource-attr-val-1")
+	ts := pcommon.Timestamp(time.Now().UnixNano())
+
+	for _, tc := range []struct {
+		desc      string
+		resource  pcommon.Resource
+		settings  Settings
+		timestamp pcommon.Timestamp
+		want      string
+	}{
+		{
+			desc:      "empty resource",
+			resource:  pcommon.NewResource(),
+			timestamp: ts,
+		},
+		{
+			desc:      "disable target info metric",
+			resource:  resource,
+			settings:  Settings{DisableTargetInfo: true},
+			timestamp: ts,
+		},
+		{
+			desc:     "without timestamp",
+			resource: resource,
+		},
+		{
+			desc:      "with resource attributes",
+			resource:  resource,
+			timestamp: ts,
+			want:      `{__name__="target_info", instance="service-instance-id", job="service-name", resource_attr="resource-attr-val-1"}`,
+		},
+		{
+			desc:      "with namespace",
+			resource:  resource,
+			settings:  Settings{Namespace: "foo"},
+			timestamp: ts,
+			want:      `{__name__="foo_target_info", instance="service-instance-id", job="service-name", resource_attr="resource-attr-val-1"}`,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			converter := newPrometheusConverterV2()
+			addResourceTargetInfoV2(tc.resource, tc.settings, tc.timestamp, converter)
+
+			series := seriesByLabelsV2(t, converter)
+			if tc.want == "" {
+				assert.Empty(t, series)
+				return
+			}
+			require.Len(t, series, 1)
+			s, ok := series[tc.want]
+			require.True(t, ok)
+			assert.Equal(t, writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_GAUGE}, s.Metadata)
+			assert.Equal(t, []writev2.Sample{{Value: 1, Timestamp: convertTimeStamp(tc.timestamp)}}, s.Samples)
+		})
+	}
+}
+
+func TestPrometheusConverterV2_addTimeSeriesReplacesExisting(t *testing.T) {
+	converter := newPrometheusConverterV2()
+	lbls := createLabels("test", nil)
+	converter.addSample(&writev2.Sample{Value: 1, Timestamp: 1}, lbls, writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_GAUGE})
+	converter.addSample(&writev2.Sample{Value: math.Inf(1), Timestamp: 2}, lbls, writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_COUNTER})
+
+	series := seriesByLabelsV2(t, converter)
+	require.Len(t, series, 1)
+	s := series[`{__name__="test"}`]
+	assert.Equal(t, writev2.Metadata_METRIC_TYPE_COUNTER, s.Metadata.Type)
+	assert.Equal(t, []writev2.Sample{{Value: math.Inf(1), Timestamp: 2}}, s.Samples)
+}
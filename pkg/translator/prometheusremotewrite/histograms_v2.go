@@ -0,0 +1,85 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewrite // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite"
+
+import (
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/prompb"
+	writev2 "github.com/prometheus/prometheus/prompb/io/prometheus/write/v2"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+func (c *prometheusConverterV2) addExponentialHistogramDataPoints(dataPoints pmetric.ExponentialHistogramDataPointSlice,
+	resource pcommon.Resource, settings Settings, baseName string, metadata writev2.Metadata,
+) error {
+	for x := 0; x < dataPoints.Len(); x++ {
+		pt := dataPoints.At(x)
+		lbls := createAttributes(
+			resource,
+			pt.Attributes(),
+			settings.ExternalLabels,
+			nil,
+			true,
+			model.MetricNameLabel,
+			baseName,
+		)
+
+		histogram, err := exponentialToNativeHistogramV2(pt)
+		if err != nil {
+			return err
+		}
+
+		ts := c.addTimeSeries(lbls, metadata)
+		ts.Histograms = []writev2.Histogram{histogram}
+		ts.CreatedTimestamp = convertTimeStamp(pt.StartTimestamp())
+		ts.Exemplars = getPromExemplarsV2(pt, &c.symbolTable)
+	}
+
+	return nil
+}
+
+// exponentialToNativeHistogramV2 translates OTel Exponential Histogram data point
+// to Prometheus remote write 2.0 Native Histogram.
+func exponentialToNativeHistogramV2(p pmetric.ExponentialHistogramDataPoint) (writev2.Histogram, error) {
+	h, err := exponentialToNativeHistogram(p)
+	if err != nil {
+		return writev2.Histogram{}, err
+	}
+
+	zeroThreshold := h.ZeroThreshold
+	if p.ZeroThreshold() > 0 {
+		zeroThreshold = p.ZeroThreshold()
+	}
+
+	return writev2.Histogram{
+		// The counter reset hint is left unspecified, which is always safe,
+		// see exponentialToNativeHistogram for the details.
+		ResetHint: writev2.Histogram_RESET_HINT_UNSPECIFIED,
+		Schema:    h.Schema,
+
+		Count:         &writev2.Histogram_CountInt{CountInt: h.GetCountInt()},
+		Sum:           h.Sum,
+		ZeroCount:     &writev2.Histogram_ZeroCountInt{ZeroCountInt: h.GetZeroCountInt()},
+		ZeroThreshold: zeroThreshold,
+
+		PositiveSpans:  bucketSpansV2(h.PositiveSpans),
+		PositiveDeltas: h.PositiveDeltas,
+		NegativeSpans:  bucketSpansV2(h.NegativeSpans),
+		NegativeDeltas: h.NegativeDeltas,
+
+		Timestamp: h.Timestamp,
+	}, nil
+}
+
+func bucketSpansV2(spans []prompb.BucketSpan) []writev2.BucketSpan {
+	if len(spans) == 0 {
+		return nil
+	}
+	out := make([]writev2.BucketSpan, len(spans))
+	for i, span := range spans {
+		out[i] = writev2.BucketSpan{Offset: span.Offset, Length: span.Length}
+	}
+	return out
+}
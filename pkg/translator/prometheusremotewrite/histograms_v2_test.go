@@ -0,0 +1,174 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewrite
+
+import (
+	"testing"
+	"time"
+
+	writev2 "github.com/prometheus/prometheus/prompb/io/prometheus/write/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	prometheustranslator "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheus"
+)
+
+func TestExponentialToNativeHistogramV2(t *testing.T) {
+	tests := []struct {
+		name            string
+		exponentialHist func() pmetric.ExponentialHistogramDataPoint
+		wantNativeHist  writev2.Histogram
+		wantErrMessage  string
+	}{
+		{
+			name: "convert exp. to native histogram",
+			exponentialHist: func() pmetric.ExponentialHistogramDataPoint {
+				pt := pmetric.NewExponentialHistogramDataPoint()
+				pt.SetStartTimestamp(pcommon.NewTimestampFromTime(time.UnixMilli(100)))
+				pt.SetTimestamp(pcommon.NewTimestampFromTime(time.UnixMilli(500)))
+				pt.SetCount(4)
+				pt.SetSum(10.1)
+				pt.SetScale(1)
+				pt.SetZeroCount(1)
+
+				pt.Positive().BucketCounts().FromRaw([]uint64{1, 1})
+				pt.Positive().SetOffset(1)
+
+				pt.Negative().BucketCounts().FromRaw([]uint64{1, 1})
+				pt.Negative().SetOffset(1)
+
+				return pt
+			},
+			wantNativeHist: writev2.Histogram{
+				Count:          &writev2.Histogram_CountInt{CountInt: 4},
+				Sum:            10.1,
+				Schema:         1,
+				ZeroThreshold:  defaultZeroThreshold,
+				ZeroCount:      &writev2.Histogram_ZeroCountInt{ZeroCountInt: 1},
+				NegativeSpans:  []writev2.BucketSpan{{Offset: 2, Length: 2}},
+				NegativeDeltas: []int64{1, 0},
+				PositiveSpans:  []writev2.BucketSpan{{Offset: 2, Length: 2}},
+				PositiveDeltas: []int64{1, 0},
+				Timestamp:      500,
+			},
+		},
+		{
+			name: "convert exp. to native histogram with zero threshold",
+			exponentialHist: func() pmetric.ExponentialHistogramDataPoint {
+				pt := pmetric.NewExponentialHistogramDataPoint()
+				pt.SetTimestamp(pcommon.NewTimestampFromTime(time.UnixMilli(500)))
+				pt.SetCount(3)
+				pt.SetScale(0)
+				pt.SetZeroCount(2)
+				pt.SetZeroThreshold(0.001)
+
+				pt.Positive().BucketCounts().FromRaw([]uint64{1})
+
+				return pt
+			},
+			wantNativeHist: writev2.Histogram{
+				Count:          &writev2.Histogram_CountInt{CountInt: 3},
+				Schema:         0,
+				ZeroThreshold:  0.001,
+				ZeroCount:      &writev2.Histogram_ZeroCountInt{ZeroCountInt: 2},
+				PositiveSpans:  []writev2.BucketSpan{{Offset: 1, Length: 1}},
+				PositiveDeltas: []int64{1},
+				Timestamp:      500,
+			},
+		},
+		{
+			name: "invalid negative scale",
+			exponentialHist: func() pmetric.ExponentialHistogramDataPoint {
+				pt := pmetric.NewExponentialHistogramDataPoint()
+				pt.SetScale(-10)
+				return pt
+			},
+			wantErrMessage: "cannot convert exponential to native histogram." +
+				" Scale must be >= -4, was -10",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := exponentialToNativeHistogramV2(tt.exponentialHist())
+			if tt.wantErrMessage != "" {
+				assert.ErrorContains(t, err, tt.wantErrMessage)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantNativeHist, got)
+		})
+	}
+}
+
+func TestPrometheusConverterV2_addExponentialHistogramDataPoints(t *testing.T) {
+	metric := pmetric.NewMetric()
+	metric.SetName("test_hist")
+	metric.SetDescription("test native histogram")
+	metric.SetEmptyExponentialHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+
+	pt := metric.ExponentialHistogram().DataPoints().AppendEmpty()
+	pt.SetStartTimestamp(pcommon.NewTimestampFromTime(time.UnixMilli(100)))
+	pt.SetTimestamp(pcommon.NewTimestampFromTime(time.UnixMilli(500)))
+	pt.SetCount(7)
+	pt.SetScale(1)
+	pt.SetSum(4.5)
+	pt.SetZeroCount(1)
+	pt.Positive().BucketCounts().FromRaw([]uint64{4, 2})
+	pt.Positive().SetOffset(1)
+	pt.Attributes().PutStr("attr", "test_attr")
+	exemplar := pt.Exemplars().AppendEmpty()
+	exemplar.SetDoubleValue(1.5)
+	exemplar.SetTimestamp(pcommon.NewTimestampFromTime(time.UnixMilli(400)))
+	exemplar.SetSpanID(pcommon.SpanID([8]byte{1}))
+
+	converter := newPrometheusConverterV2()
+	metadata := converter.metadata(metric, writev2.Metadata_METRIC_TYPE_HISTOGRAM)
+	require.NoError(t, converter.addExponentialHistogramDataPoints(
+		metric.ExponentialHistogram().DataPoints(),
+		pcommon.NewResource(),
+		Settings{},
+		metric.Name(),
+		metadata,
+	))
+
+	series := seriesByLabelsV2(t, converter)
+	require.Len(t, series, 1)
+	ts, ok := series[`{__name__="test_hist", attr="test_attr"}`]
+	require.True(t, ok)
+	assert.Equal(t, metadata, ts.Metadata)
+	assert.Equal(t, int64(100), ts.CreatedTimestamp)
+	assert.Empty(t, ts.Samples)
+	assert.Equal(t, []writev2.Histogram{{
+		Count:          &writev2.Histogram_CountInt{CountInt: 7},
+		Sum:            4.5,
+		Schema:         1,
+		ZeroThreshold:  defaultZeroThreshold,
+		ZeroCount:      &writev2.Histogram_ZeroCountInt{ZeroCountInt: 1},
+		PositiveSpans:  []writev2.BucketSpan{{Offset: 2, Length: 2}},
+		PositiveDeltas: []int64{4, -2},
+		Timestamp:      500,
+	}}, ts.Histograms)
+
+	symbols := converter.symbolTable.Symbols()
+	assert.Equal(t, "test native histogram", symbols[ts.Metadata.HelpRef])
+	require.Len(t, ts.Exemplars, 1)
+	assert.Equal(t, 1.5, ts.Exemplars[0].Value)
+	assert.Equal(t, int64(400), ts.Exemplars[0].Timestamp)
+	assert.Equal(t, map[string]string{
+		prometheustranslator.ExemplarSpanIDKey: "0100000000000000",
+	}, exemplarLabelsV2(t, symbols, ts.Exemplars[0]))
+}
+
+func TestPrometheusConverterV2_addExponentialHistogramDataPointsInvalidScale(t *testing.T) {
+	dataPoints := pmetric.NewExponentialHistogramDataPointSlice()
+	dataPoints.AppendEmpty().SetScale(-10)
+
+	converter := newPrometheusConverterV2()
+	err := converter.addExponentialHistogramDataPoints(dataPoints, pcommon.NewResource(), Settings{}, "test_hist", writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_HISTOGRAM})
+	assert.ErrorContains(t, err, "Scale must be >= -4")
+	assert.Empty(t, converter.unique)
+}
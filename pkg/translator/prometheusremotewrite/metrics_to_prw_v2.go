@@ -78,30 +78,47 @@ func (c *prometheusConverterV2) fromMetrics(md pmetric.Metrics, settings Setting
 					if dataPoints.Len() == 0 {
 						break
 					}
-					c.addGaugeNumberDataPoints(dataPoints, resource, settings, promName)
+					c.addGaugeNumberDataPoints(dataPoints, resource, settings, promName, c.metadata(metric, writev2.Metadata_METRIC_TYPE_GAUGE))
 				case pmetric.MetricTypeSum:
 					dataPoints := metric.Sum().DataPoints()
 					if dataPoints.Len() == 0 {
 						break
 					}
 					if !metric.Sum().IsMonotonic() {
-						c.addGaugeNumberDataPoints(dataPoints, resource, settings, promName)
+						c.addGaugeNumberDataPoints(dataPoints, resource, settings, promName, c.metadata(metric, writev2.Metadata_METRIC_TYPE_GAUGE))
 					} else {
-						c.addSumNumberDataPoints(dataPoints, resource, metric, settings, promName)
+						c.addSumNumberDataPoints(dataPoints, resource, metric, settings, promName, c.metadata(metric, writev2.Metadata_METRIC_TYPE_COUNTER))
 					}
 				case pmetric.MetricTypeHistogram:
-					// TODO implement
+					dataPoints := metric.Histogram().DataPoints()
+					if dataPoints.Len() == 0 {
+						break
+					}
+					c.addHistogramDataPoints(dataPoints, resource, settings, promName, c.metadata(metric, writev2.Metadata_METRIC_TYPE_HISTOGRAM))
 				case pmetric.MetricTypeExponentialHistogram:
-					// TODO implement
+					dataPoints := metric.ExponentialHistogram().DataPoints()
+					if dataPoints.Len() == 0 {
+						break
+					}
+					errs = multierr.Append(errs, c.addExponentialHistogramDataPoints(
+						dataPoints,
+						resource,
+						settings,
+						promName,
+						c.metadata(metric, writev2.Metadata_METRIC_TYPE_HISTOGRAM),
+					))
 				case pmetric.MetricTypeSummary:
-					// TODO implement
+					dataPoints := metric.Summary().DataPoints()
+					if dataPoints.Len() == 0 {
+						break
+					}
+					c.addSummaryDataPoints(dataPoints, resource, settings, promName, c.metadata(metric, writev2.Metadata_METRIC_TYPE_SUMMARY))
 				default:
 					errs = multierr.Append(errs, errors.New("unsupported metric type"))
 				}
 			}
 		}
-		// TODO implement
-		// addResourceTargetInfov2(resource, settings, mostRecentTimestamp, c)
+		addResourceTargetInfoV2(resource, settings, mostRecentTimestamp, c)
 	}
 
 	return
@@ -116,7 +133,19 @@ func (c *prometheusConverterV2) timeSeries() []writev2.TimeSeries {
 	return allTS
 }
 
-func (c *prometheusConverterV2) addSample(sample *writev2.Sample, lbls []prompb.Label) {
+// metadata returns the metadata of the time series converted from the metric. The help text and unit of the
+// metric are added to the symbols table, so that they are kept as is when the metric is converted back to OTLP.
+func (c *prometheusConverterV2) metadata(metric pmetric.Metric, metricType writev2.Metadata_MetricType) writev2.Metadata {
+	return writev2.Metadata{
+		Type:    metricType,
+		HelpRef: c.symbolTable.Symbolize(metric.Description()),
+		UnitRef: c.symbolTable.Symbolize(metric.Unit()),
+	}
+}
+
+// addTimeSeries adds a time series with the given labels and metadata, and returns it.
+// A time series previously added with the same labels is replaced.
+func (c *prometheusConverterV2) addTimeSeries(lbls []prompb.Label, metadata writev2.Metadata) *writev2.TimeSeries {
 	buf := make([]uint32, 0, len(lbls)*2)
 
 	// TODO: Read the PRW spec to see if labels need to be sorted. If it is, then we need to sort in export code. If not, we can sort in the test. (@dashpole have more context on this)
@@ -131,9 +160,16 @@ func (c *prometheusConverterV2) addSample(sample *writev2.Sample, lbls []prompb.
 		off = c.symbolTable.Symbolize(l.Value)
 		buf = append(buf, off)
 	}
-	ts := writev2.TimeSeries{
+	ts := &writev2.TimeSeries{
 		LabelsRefs: buf,
-		Samples:    []writev2.Sample{*sample},
+		Metadata:   metadata,
 	}
-	c.unique[timeSeriesSignature(lbls)] = &ts
+	c.unique[timeSeriesSignature(lbls)] = ts
+	return ts
+}
+
+func (c *prometheusConverterV2) addSample(sample *writev2.Sample, lbls []prompb.Label, metadata writev2.Metadata) *writev2.TimeSeries {
+	ts := c.addTimeSeries(lbls, metadata)
+	ts.Samples = []writev2.Sample{*sample}
+	return ts
 }
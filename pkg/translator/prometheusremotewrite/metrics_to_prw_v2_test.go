@@ -28,12 +28,14 @@ func TestFromMetricsV2(t *testing.T) {
 	want := []*writev2.TimeSeries{
 		{
 			LabelsRefs: []uint32{1, 2, 3, 4, 5, 6, 7, 8},
+			Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_GAUGE},
 			Samples: []writev2.Sample{
 				{Timestamp: convertTimeStamp(pcommon.Timestamp(ts)), Value: 1.23},
 			},
 		},
 		{
 			LabelsRefs: []uint32{1, 9, 3, 4, 5, 6, 7, 8},
+			Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_GAUGE},
 			Samples: []writev2.Sample{
 				{Timestamp: convertTimeStamp(pcommon.Timestamp(ts)), Value: 1.23},
 			},
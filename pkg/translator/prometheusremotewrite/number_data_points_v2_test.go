@@ -44,6 +44,7 @@ func TestPrometheusConverterV2_addGaugeNumberDataPoints(t *testing.T) {
 				return map[uint64]*writev2.TimeSeries{
 					labels.Hash(): {
 						LabelsRefs: []uint32{1, 2},
+						Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_GAUGE},
 						Samples: []writev2.Sample{
 							{Timestamp: convertTimeStamp(pcommon.Timestamp(ts)), Value: 1},
 						},
@@ -70,6 +71,7 @@ func TestPrometheusConverterV2_addGaugeNumberDataPoints(t *testing.T) {
 				return map[uint64]*writev2.TimeSeries{
 					labels.Hash(): {
 						LabelsRefs: []uint32{1, 2},
+						Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_GAUGE},
 						Samples: []writev2.Sample{
 							{Timestamp: convertTimeStamp(pcommon.Timestamp(ts)), Value: 1.5},
 						},
@@ -96,6 +98,7 @@ func TestPrometheusConverterV2_addGaugeNumberDataPoints(t *testing.T) {
 				return map[uint64]*writev2.TimeSeries{
 					labels.Hash(): {
 						LabelsRefs: []uint32{1, 2},
+						Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_GAUGE},
 						Samples: []writev2.Sample{
 							{Timestamp: convertTimeStamp(pcommon.Timestamp(ts)), Value: math.Float64frombits(value.StaleNaN)},
 						},
@@ -115,7 +118,7 @@ func TestPrometheusConverterV2_addGaugeNumberDataPoints(t *testing.T) {
 				SendMetadata:      false,
 			}
 			converter := newPrometheusConverterV2()
-			converter.addGaugeNumberDataPoints(metric.Gauge().DataPoints(), pcommon.NewResource(), settings, metric.Name())
+			converter.addGaugeNumberDataPoints(metric.Gauge().DataPoints(), pcommon.NewResource(), settings, metric.Name(), writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_GAUGE})
 			w := tt.want()
 
 			diff := cmp.Diff(w, converter.unique, cmpopts.EquateNaNs())
@@ -148,6 +151,7 @@ func TestPrometheusConverterV2_addGaugeNumberDataPointsDuplicate(t *testing.T) {
 		return map[uint64]*writev2.TimeSeries{
 			labels.Hash(): {
 				LabelsRefs: []uint32{1, 2},
+				Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_GAUGE},
 				Samples: []writev2.Sample{
 					{Timestamp: convertTimeStamp(pcommon.Timestamp(ts)), Value: 2},
 				},
@@ -163,8 +167,47 @@ func TestPrometheusConverterV2_addGaugeNumberDataPointsDuplicate(t *testing.T) {
 	}
 
 	converter := newPrometheusConverterV2()
-	converter.addGaugeNumberDataPoints(metric1.Gauge().DataPoints(), pcommon.NewResource(), settings, metric1.Name())
-	converter.addGaugeNumberDataPoints(metric2.Gauge().DataPoints(), pcommon.NewResource(), settings, metric2.Name())
+	converter.addGaugeNumberDataPoints(metric1.Gauge().DataPoints(), pcommon.NewResource(), settings, metric1.Name(), writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_GAUGE})
+	converter.addGaugeNumberDataPoints(metric2.Gauge().DataPoints(), pcommon.NewResource(), settings, metric2.Name(), writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_GAUGE})
 
 	assert.Equal(t, want(), converter.unique)
 }
+
+func TestPrometheusConverterV2_addSumNumberDataPoints(t *testing.T) {
+	ts := pcommon.Timestamp(time.Now().UnixNano())
+	startTs := pcommon.Timestamp(time.Now().Add(-time.Minute).UnixNano())
+
+	metric := getIntSumMetric("test_sum", getAttributes("foo", "bar"), pmetric.AggregationTemporalityCumulative, 5, uint64(ts))
+	metric.Sum().SetIsMonotonic(true)
+	dp := metric.Sum().DataPoints().At(0)
+	dp.SetStartTimestamp(startTs)
+	exemplar := dp.Exemplars().AppendEmpty()
+	exemplar.SetIntValue(2)
+	exemplar.SetTimestamp(ts)
+	exemplar.SetTraceID(pcommon.TraceID([16]byte{1}))
+	exemplar.FilteredAttributes().PutStr("tenant", "a")
+
+	converter := newPrometheusConverterV2()
+	metadata := writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_COUNTER}
+	converter.addSumNumberDataPoints(metric.Sum().DataPoints(), pcommon.NewResource(), metric, Settings{}, metric.Name(), metadata)
+
+	want := map[uint64]*writev2.TimeSeries{
+		timeSeriesSignature(getPromLabels(labels.MetricName, "test_sum", "foo", "bar")): {
+			LabelsRefs: []uint32{1, 2, 3, 4},
+			Metadata:   metadata,
+			Samples: []writev2.Sample{
+				{Timestamp: convertTimeStamp(ts), Value: 5},
+			},
+			Exemplars: []writev2.Exemplar{
+				{
+					LabelsRefs: []uint32{5, 6, 7, 8},
+					Value:      2,
+					Timestamp:  convertTimeStamp(ts),
+				},
+			},
+			CreatedTimestamp: convertTimeStamp(startTs),
+		},
+	}
+	assert.Equal(t, want, converter.unique)
+	assert.Equal(t, []string{"", "__name__", "test_sum", "foo", "bar", "trace_id", "01000000000000000000000000000000", "tenant", "a"}, converter.symbolTable.Symbols())
+}
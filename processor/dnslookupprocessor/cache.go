@@ -0,0 +1,63 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package dnslookupprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/dnslookupprocessor"
+
+import (
+	"sync"
+	"time"
+
+	"github.com/hashicorp/golang-lru/v2/simplelru"
+)
+
+type cacheEntry struct {
+	value   string
+	expires time.Time
+}
+
+// ttlCache is a LRU cache whose entries expire after a TTL. The expired entries are removed when they
+// are read, or evicted by the newer entries.
+type ttlCache struct {
+	mu  sync.Mutex
+	lru *simplelru.LRU[string, cacheEntry]
+	ttl time.Duration
+	now func() time.Time
+}
+
+// newTTLCache returns a cache of size entries expiring after ttl, or nil if size is 0.
+func newTTLCache(size int, ttl time.Duration) (*ttlCache, error) {
+	if size == 0 {
+		return nil, nil
+	}
+	lru, err := simplelru.NewLRU[string, cacheEntry](size, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &ttlCache{lru: lru, ttl: ttl, now: time.Now}, nil
+}
+
+func (c *ttlCache) get(key string) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.lru.Get(key)
+	if !ok {
+		return "", false
+	}
+	if !c.now().Before(entry.expires) {
+		c.lru.Remove(key)
+		return "", false
+	}
+	return entry.value, true
+}
+
+func (c *ttlCache) add(key, value string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lru.Add(key, cacheEntry{value: value, expires: c.now().Add(c.ttl)})
+}
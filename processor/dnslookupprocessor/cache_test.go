@@ -0,0 +1,50 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package dnslookupprocessor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTTLCache(t *testing.T) {
+	c, err := newTTLCache(2, time.Minute)
+	require.NoError(t, err)
+	now := time.Date(2024, 0o1, 10, 10, 30, 40, 0, time.UTC)
+	c.now = func() time.Time { return now }
+
+	c.add("a", "1")
+	c.add("b", "2")
+	value, ok := c.get("a")
+	require.True(t, ok)
+	assert.Equal(t, "1", value)
+
+	// The least recently used entry is evicted.
+	c.add("c", "3")
+	_, ok = c.get("b")
+	assert.False(t, ok)
+
+	now = now.Add(30 * time.Second)
+	c.add("a", "4")
+	now = now.Add(45 * time.Second)
+	// "c" expired, "a" was refreshed.
+	_, ok = c.get("c")
+	assert.False(t, ok)
+	value, ok = c.get("a")
+	require.True(t, ok)
+	assert.Equal(t, "4", value)
+	assert.Equal(t, 1, c.lru.Len())
+}
+
+func TestTTLCacheDisabled(t *testing.T) {
+	c, err := newTTLCache(0, time.Minute)
+	require.NoError(t, err)
+	assert.Nil(t, c)
+	c.add("a", "1")
+	_, ok := c.get("a")
+	assert.False(t, ok)
+}
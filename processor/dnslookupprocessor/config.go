@@ -3,9 +3,131 @@
 
 package dnslookupprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/dnslookupprocessor"
 
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ContextID is the context of the attributes a lookup reads and writes.
+type ContextID string
+
+const (
+	// resource uses the attributes of the resources.
+	resource ContextID = "resource"
+	// record uses the attributes of the spans, log records and data points.
+	record ContextID = "record"
+)
+
 // Config holds the configuration for the DnsLookup processor.
-type Config struct{}
+type Config struct {
+	// Resolve configures the resolution of hostnames to IP addresses.
+	Resolve LookupConfig `mapstructure:"resolve"`
+
+	// Reverse configures the resolution of IP addresses to hostnames.
+	Reverse LookupConfig `mapstructure:"reverse"`
+
+	// HitCacheSize is the maximum number of successful lookups cached, 0 disables the cache.
+	HitCacheSize int `mapstructure:"hit_cache_size"`
+
+	// HitCacheTTL is the time successful lookups are cached for.
+	HitCacheTTL time.Duration `mapstructure:"hit_cache_ttl"`
+
+	// MissCacheSize is the maximum number of lookups without records cached, 0 disables the cache.
+	MissCacheSize int `mapstructure:"miss_cache_size"`
+
+	// MissCacheTTL is the time lookups without records are cached for.
+	MissCacheTTL time.Duration `mapstructure:"miss_cache_ttl"`
+
+	// Timeout is the maximum time of a lookup, across all resolvers.
+	Timeout time.Duration `mapstructure:"timeout"`
+
+	// MaxConcurrentLookups is the maximum number of the distinct values of a batch looked up concurrently.
+	MaxConcurrentLookups int `mapstructure:"max_concurrent_lookups"`
+
+	// Hostfiles is the list of files, in the format of /etc/hosts, looked up before the nameservers.
+	// They allow resolving hostnames and IP addresses without DNS.
+	Hostfiles []string `mapstructure:"hostfiles"`
+
+	// Nameservers is the list of nameservers, as "host" or "host:port", the lookups are sent to.
+	Nameservers []string `mapstructure:"nameservers"`
+
+	// EnableSystemResolver enables the resolver of the system, after the host files and the nameservers.
+	EnableSystemResolver bool `mapstructure:"enable_system_resolver"`
+}
+
+// LookupConfig configures the attributes of a lookup.
+type LookupConfig struct {
+	// Enabled enables the lookup.
+	Enabled bool `mapstructure:"enabled"`
+
+	// Context is the context of the attributes, resource or record.
+	Context ContextID `mapstructure:"context"`
+
+	// SourceAttributes is the list of attributes holding the hostname or IP address to look up.
+	// The first attribute present is looked up.
+	SourceAttributes []string `mapstructure:"source_attributes"`
+
+	// TargetAttribute is the attribute the result of the lookup is written to.
+	TargetAttribute string `mapstructure:"target_attribute"`
+}
+
+func (cfg *LookupConfig) validate(name string) error {
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.Context != resource && cfg.Context != record {
+		return fmt.Errorf("%s context must be %q or %q", name, resource, record)
+	}
+	if len(cfg.SourceAttributes) == 0 {
+		return fmt.Errorf("%s source_attributes must not be empty", name)
+	}
+	for _, attribute := range cfg.SourceAttributes {
+		if attribute == "" {
+			return fmt.Errorf("%s source_attributes must not contain empty attributes", name)
+		}
+	}
+	if cfg.TargetAttribute == "" {
+		return fmt.Errorf("%s target_attribute must not be empty", name)
+	}
+	return nil
+}
 
 func (cfg *Config) Validate() error {
+	if !cfg.Resolve.Enabled && !cfg.Reverse.Enabled {
+		return errors.New("at least one of resolve or reverse must be enabled")
+	}
+	if err := cfg.Resolve.validate("resolve"); err != nil {
+		return err
+	}
+	if err := cfg.Reverse.validate("reverse"); err != nil {
+		return err
+	}
+	if cfg.HitCacheSize < 0 {
+		return errors.New("hit_cache_size must not be negative")
+	}
+	if cfg.HitCacheSize > 0 && cfg.HitCacheTTL <= 0 {
+		return errors.New("hit_cache_ttl must be positive")
+	}
+	if cfg.MissCacheSize < 0 {
+		return errors.New("miss_cache_size must not be negative")
+	}
+	if cfg.MissCacheSize > 0 && cfg.MissCacheTTL <= 0 {
+		return errors.New("miss_cache_ttl must be positive")
+	}
+	if cfg.Timeout <= 0 {
+		return errors.New("timeout must be positive")
+	}
+	if cfg.MaxConcurrentLookups <= 0 {
+		return errors.New("max_concurrent_lookups must be positive")
+	}
+	if len(cfg.Hostfiles) == 0 && len(cfg.Nameservers) == 0 && !cfg.EnableSystemResolver {
+		return errors.New("at least one of hostfiles, nameservers or enable_system_resolver must be set")
+	}
+	for _, nameserver := range cfg.Nameservers {
+		if nameserver == "" {
+			return errors.New("nameservers must not contain empty nameservers")
+		}
+	}
 	return nil
 }
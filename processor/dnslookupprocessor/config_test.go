@@ -0,0 +1,132 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package dnslookupprocessor
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap/confmaptest"
+	"go.opentelemetry.io/collector/confmap/xconfmap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/dnslookupprocessor/internal/metadata"
+)
+
+func TestLoadConfig(t *testing.T) {
+	t.Parallel()
+
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		id           component.ID
+		expected     component.Config
+		errorMessage string
+	}{
+		{
+			id:       component.NewID(metadata.Type),
+			expected: createDefaultConfig(),
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "custom"),
+			expected: &Config{
+				Resolve: LookupConfig{
+					Enabled:          true,
+					Context:          record,
+					SourceAttributes: []string{"server.address", "client.address"},
+					TargetAttribute:  "server.ip",
+				},
+				Reverse: LookupConfig{
+					Enabled:          true,
+					Context:          record,
+					SourceAttributes: []string{"client.address"},
+					TargetAttribute:  "client.hostname",
+				},
+				HitCacheSize:         100,
+				HitCacheTTL:          5 * time.Minute,
+				MissCacheSize:        0,
+				MissCacheTTL:         defaultMissCacheTTL,
+				Timeout:              2 * time.Second,
+				MaxConcurrentLookups: 4,
+				Hostfiles:            []string{"./testdata/hosts"},
+				Nameservers:          []string{"10.0.0.53", "10.0.0.54:5353"},
+			},
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "nothing_enabled"),
+			errorMessage: "at least one of resolve or reverse must be enabled",
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "invalid_context"),
+			errorMessage: `resolve context must be "resource" or "record"`,
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "no_source_attributes"),
+			errorMessage: "reverse source_attributes must not be empty",
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "empty_source_attribute"),
+			errorMessage: "resolve source_attributes must not contain empty attributes",
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "no_target_attribute"),
+			errorMessage: "resolve target_attribute must not be empty",
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "negative_hit_cache_size"),
+			errorMessage: "hit_cache_size must not be negative",
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "invalid_hit_cache_ttl"),
+			errorMessage: "hit_cache_ttl must be positive",
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "negative_miss_cache_size"),
+			errorMessage: "miss_cache_size must not be negative",
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "invalid_miss_cache_ttl"),
+			errorMessage: "miss_cache_ttl must be positive",
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "invalid_timeout"),
+			errorMessage: "timeout must be positive",
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "invalid_max_concurrent_lookups"),
+			errorMessage: "max_concurrent_lookups must be positive",
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "no_resolver"),
+			errorMessage: "at least one of hostfiles, nameservers or enable_system_resolver must be set",
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "empty_nameserver"),
+			errorMessage: "nameservers must not contain empty nameservers",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id.String(), func(t *testing.T) {
+			factory := NewFactory()
+			cfg := factory.CreateDefaultConfig()
+
+			sub, err := cm.Sub(tt.id.String())
+			require.NoError(t, err)
+			require.NoError(t, sub.Unmarshal(cfg))
+
+			if tt.expected == nil {
+				assert.EqualError(t, xconfmap.Validate(cfg), tt.errorMessage)
+				return
+			}
+
+			assert.NoError(t, xconfmap.Validate(cfg))
+			assert.Equal(t, tt.expected, cfg)
+		})
+	}
+}
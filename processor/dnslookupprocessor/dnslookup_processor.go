@@ -5,26 +5,352 @@ package dnslookupprocessor // import "github.com/open-telemetry/opentelemetry-co
 
 import (
 	"context"
+	"errors"
+	"net/netip"
+	"sync"
 
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/processor"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/dnslookupprocessor/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/dnslookupprocessor/internal/resolver"
 )
 
-type dnsLookupProcessor struct{}
+// lookup is a resolution, forward or reverse, of the values of attributes.
+type lookup struct {
+	config *LookupConfig
+	// accept reports whether the value can be looked up, e.g. is a hostname for forward resolutions.
+	accept  func(value string) bool
+	resolve func(r resolver.Resolver, ctx context.Context, value string) (string, error)
+	// hits caches the results of the successful lookups, and misses the values without records.
+	hits   *ttlCache
+	misses *ttlCache
+	// inflight deduplicates the concurrent lookups of the same value.
+	inflight singleflight.Group
+	// attributes are the attributes of the telemetry of the lookup.
+	attributes metric.MeasurementOption
+}
+
+// lookupResult is the result of a lookup shared by the concurrent lookups of a value.
+type lookupResult struct {
+	value string
+	found bool
+}
+
+type dnsLookupProcessor struct {
+	config           *Config
+	logger           *zap.Logger
+	resolver         resolver.Resolver
+	telemetryBuilder *metadata.TelemetryBuilder
+	lookups          []*lookup
+}
+
+func newDNSLookupProcessor(config *Config, set processor.Settings) (*dnsLookupProcessor, error) {
+	r, err := newResolver(config)
+	if err != nil {
+		return nil, err
+	}
+	telemetryBuilder, err := metadata.NewTelemetryBuilder(set.TelemetrySettings)
+	if err != nil {
+		return nil, err
+	}
+	p := &dnsLookupProcessor{
+		config:           config,
+		logger:           set.Logger,
+		resolver:         r,
+		telemetryBuilder: telemetryBuilder,
+	}
+	if config.Resolve.Enabled {
+		l, err := newLookup(config, &config.Resolve, "resolve", isHostname, resolver.Resolver.Resolve)
+		if err != nil {
+			return nil, err
+		}
+		p.lookups = append(p.lookups, l)
+	}
+	if config.Reverse.Enabled {
+		l, err := newLookup(config, &config.Reverse, "reverse", isIP, resolver.Resolver.Reverse)
+		if err != nil {
+			return nil, err
+		}
+		p.lookups = append(p.lookups, l)
+	}
+	return p, nil
+}
+
+// newResolver returns the resolver trying the host files, then the nameservers, then the system resolver.
+func newResolver(config *Config) (resolver.Resolver, error) {
+	var resolvers []resolver.Resolver
+	if len(config.Hostfiles) > 0 {
+		r, err := resolver.NewHostFileResolver(config.Hostfiles)
+		if err != nil {
+			return nil, err
+		}
+		resolvers = append(resolvers, r)
+	}
+	if len(config.Nameservers) > 0 {
+		resolvers = append(resolvers, resolver.NewNameserverResolver(config.Nameservers))
+	}
+	if config.EnableSystemResolver {
+		resolvers = append(resolvers, resolver.NewSystemResolver())
+	}
+	return resolver.NewChainResolver(resolvers...), nil
+}
+
+func newLookup(
+	config *Config,
+	lookupConfig *LookupConfig,
+	name string,
+	accept func(string) bool,
+	resolve func(resolver.Resolver, context.Context, string) (string, error),
+) (*lookup, error) {
+	hits, err := newTTLCache(config.HitCacheSize, config.HitCacheTTL)
+	if err != nil {
+		return nil, err
+	}
+	misses, err := newTTLCache(config.MissCacheSize, config.MissCacheTTL)
+	if err != nil {
+		return nil, err
+	}
+	return &lookup{
+		config:     lookupConfig,
+		accept:     accept,
+		resolve:    resolve,
+		hits:       hits,
+		misses:     misses,
+		attributes: metric.WithAttributeSet(attribute.NewSet(attribute.String("lookup", name))),
+	}, nil
+}
+
+func isIP(value string) bool {
+	_, err := netip.ParseAddr(value)
+	return err == nil
+}
 
-func newDNSLookupProcessor() *dnsLookupProcessor {
-	return &dnsLookupProcessor{}
+func isHostname(value string) bool {
+	return !isIP(value)
 }
 
-func (g *dnsLookupProcessor) processMetrics(_ context.Context, ms pmetric.Metrics) (pmetric.Metrics, error) {
+func (p *dnsLookupProcessor) processMetrics(ctx context.Context, ms pmetric.Metrics) (pmetric.Metrics, error) {
+	for _, l := range p.lookups {
+		var maps []pcommon.Map
+		for i := 0; i < ms.ResourceMetrics().Len(); i++ {
+			rm := ms.ResourceMetrics().At(i)
+			if l.config.Context == resource {
+				maps = append(maps, rm.Resource().Attributes())
+				continue
+			}
+			for j := 0; j < rm.ScopeMetrics().Len(); j++ {
+				metrics := rm.ScopeMetrics().At(j).Metrics()
+				for k := 0; k < metrics.Len(); k++ {
+					maps = appendDataPointAttributes(maps, metrics.At(k))
+				}
+			}
+		}
+		p.apply(ctx, l, maps)
+	}
 	return ms, nil
 }
 
-func (g *dnsLookupProcessor) processTraces(_ context.Context, ts ptrace.Traces) (ptrace.Traces, error) {
+func appendDataPointAttributes(maps []pcommon.Map, m pmetric.Metric) []pcommon.Map {
+	switch m.Type() {
+	case pmetric.MetricTypeGauge:
+		dps := m.Gauge().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			maps = append(maps, dps.At(i).Attributes())
+		}
+	case pmetric.MetricTypeSum:
+		dps := m.Sum().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			maps = append(maps, dps.At(i).Attributes())
+		}
+	case pmetric.MetricTypeHistogram:
+		dps := m.Histogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			maps = append(maps, dps.At(i).Attributes())
+		}
+	case pmetric.MetricTypeExponentialHistogram:
+		dps := m.ExponentialHistogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			maps = append(maps, dps.At(i).Attributes())
+		}
+	case pmetric.MetricTypeSummary:
+		dps := m.Summary().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			maps = append(maps, dps.At(i).Attributes())
+		}
+	}
+	return maps
+}
+
+func (p *dnsLookupProcessor) processTraces(ctx context.Context, ts ptrace.Traces) (ptrace.Traces, error) {
+	for _, l := range p.lookups {
+		var maps []pcommon.Map
+		for i := 0; i < ts.ResourceSpans().Len(); i++ {
+			rs := ts.ResourceSpans().At(i)
+			if l.config.Context == resource {
+				maps = append(maps, rs.Resource().Attributes())
+				continue
+			}
+			for j := 0; j < rs.ScopeSpans().Len(); j++ {
+				spans := rs.ScopeSpans().At(j).Spans()
+				for k := 0; k < spans.Len(); k++ {
+					maps = append(maps, spans.At(k).Attributes())
+				}
+			}
+		}
+		p.apply(ctx, l, maps)
+	}
 	return ts, nil
 }
 
-func (g *dnsLookupProcessor) processLogs(_ context.Context, ls plog.Logs) (plog.Logs, error) {
+func (p *dnsLookupProcessor) processLogs(ctx context.Context, ls plog.Logs) (plog.Logs, error) {
+	for _, l := range p.lookups {
+		var maps []pcommon.Map
+		for i := 0; i < ls.ResourceLogs().Len(); i++ {
+			rl := ls.ResourceLogs().At(i)
+			if l.config.Context == resource {
+				maps = append(maps, rl.Resource().Attributes())
+				continue
+			}
+			for j := 0; j < rl.ScopeLogs().Len(); j++ {
+				lrs := rl.ScopeLogs().At(j).LogRecords()
+				for k := 0; k < lrs.Len(); k++ {
+					maps = append(maps, lrs.At(k).Attributes())
+				}
+			}
+		}
+		p.apply(ctx, l, maps)
+	}
 	return ls, nil
 }
+
+// apply looks up, for each of the attribute maps, the first source attribute holding a value the lookup accepts,
+// and writes the result to the target attribute. Each distinct value is looked up once.
+func (p *dnsLookupProcessor) apply(ctx context.Context, l *lookup, maps []pcommon.Map) {
+	values := make([]string, len(maps))
+	var distinct []string
+	seen := map[string]struct{}{}
+	for i, attrs := range maps {
+		value, ok := l.source(attrs)
+		if !ok {
+			continue
+		}
+		values[i] = value
+		if _, ok := seen[value]; !ok {
+			seen[value] = struct{}{}
+			distinct = append(distinct, value)
+		}
+	}
+	if len(distinct) == 0 {
+		return
+	}
+
+	results := p.lookupAll(ctx, l, distinct)
+	for i, attrs := range maps {
+		if result, found := results[values[i]]; found {
+			attrs.PutStr(l.config.TargetAttribute, result)
+		}
+	}
+}
+
+// source returns the value of the first source attribute holding a value the lookup accepts.
+func (l *lookup) source(attrs pcommon.Map) (string, bool) {
+	for _, name := range l.config.SourceAttributes {
+		value, ok := attrs.Get(name)
+		if ok && value.Type() == pcommon.ValueTypeStr && value.Str() != "" && l.accept(value.Str()) {
+			return value.Str(), true
+		}
+	}
+	return "", false
+}
+
+// lookupAll returns the results of the lookups of the values which found a record. The values which aren't
+// cached are looked up concurrently, at most max_concurrent_lookups at once.
+func (p *dnsLookupProcessor) lookupAll(ctx context.Context, l *lookup, values []string) map[string]string {
+	results := make(map[string]string, len(values))
+	var uncached []string
+	for _, value := range values {
+		result, found, ok := p.cached(ctx, l, value)
+		if !ok {
+			uncached = append(uncached, value)
+		} else if found {
+			results[value] = result
+		}
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, p.config.MaxConcurrentLookups)
+	for _, value := range uncached {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if result, found := p.resolve(ctx, l, value); found {
+				mu.Lock()
+				results[value] = result
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// cached returns the result of the lookup of value from the caches. ok is false if the value isn't cached.
+func (p *dnsLookupProcessor) cached(ctx context.Context, l *lookup, value string) (result string, found, ok bool) {
+	if result, ok := l.hits.get(value); ok {
+		p.telemetryBuilder.ProcessorDnslookupCacheHits.Add(ctx, 1, l.attributes)
+		return result, true, true
+	}
+	if _, ok := l.misses.get(value); ok {
+		p.telemetryBuilder.ProcessorDnslookupCacheHits.Add(ctx, 1, l.attributes)
+		return "", false, true
+	}
+	return "", false, false
+}
+
+// resolve looks value up with the resolver, and caches the result. The concurrent lookups of the same value,
+// e.g. from batches processed concurrently, share a single lookup.
+func (p *dnsLookupProcessor) resolve(ctx context.Context, l *lookup, value string) (string, bool) {
+	v, _, _ := l.inflight.Do(value, func() (any, error) {
+		result, found := p.resolveUncached(ctx, l, value)
+		return lookupResult{value: result, found: found}, nil
+	})
+	r := v.(lookupResult)
+	return r.value, r.found
+}
+
+func (p *dnsLookupProcessor) resolveUncached(ctx context.Context, l *lookup, value string) (string, bool) {
+	p.telemetryBuilder.ProcessorDnslookupLookups.Add(ctx, 1, l.attributes)
+	// The lookup is shared with the other callers waiting for it, so it isn't canceled with ctx.
+	lookupCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), p.config.Timeout)
+	defer cancel()
+	result, err := l.resolve(p.resolver, lookupCtx, value)
+	if err != nil {
+		p.telemetryBuilder.ProcessorDnslookupLookupFailures.Add(ctx, 1, l.attributes)
+		if errors.Is(err, resolver.ErrNotFound) {
+			l.misses.add(value, "")
+		} else {
+			p.logger.Debug("DNS lookup failed", zap.String("value", value), zap.Error(err))
+		}
+		return "", false
+	}
+	l.hits.add(value, result)
+	return result, true
+}
+
+func (p *dnsLookupProcessor) shutdown(context.Context) error {
+	p.telemetryBuilder.Shutdown()
+	return nil
+}
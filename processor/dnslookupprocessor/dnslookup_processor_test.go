@@ -0,0 +1,402 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package dnslookupprocessor
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/processor/processortest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/metric/metricdatatest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/dnslookupprocessor/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/dnslookupprocessor/internal/metadatatest"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/dnslookupprocessor/internal/resolver"
+)
+
+// countingResolver counts the lookups sent to the resolver it wraps.
+type countingResolver struct {
+	resolver.Resolver
+	calls atomic.Int64
+}
+
+func (r *countingResolver) Resolve(ctx context.Context, hostname string) (string, error) {
+	r.calls.Add(1)
+	return r.Resolver.Resolve(ctx, hostname)
+}
+
+func (r *countingResolver) Reverse(ctx context.Context, ip string) (string, error) {
+	r.calls.Add(1)
+	return r.Resolver.Reverse(ctx, ip)
+}
+
+// blockingResolver blocks the lookups until release is closed, sending the values looked up to started.
+type blockingResolver struct {
+	calls   atomic.Int64
+	started chan string
+	release chan struct{}
+}
+
+func newBlockingResolver() *blockingResolver {
+	return &blockingResolver{started: make(chan string, 10), release: make(chan struct{})}
+}
+
+func (r *blockingResolver) Resolve(ctx context.Context, hostname string) (string, error) {
+	r.calls.Add(1)
+	r.started <- hostname
+	select {
+	case <-r.release:
+		return "10.0.0.1", nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+func (*blockingResolver) Reverse(context.Context, string) (string, error) {
+	return "", resolver.ErrNotFound
+}
+
+type failingResolver struct{}
+
+func (failingResolver) Resolve(context.Context, string) (string, error) {
+	return "", errors.New("timeout")
+}
+
+func (failingResolver) Reverse(context.Context, string) (string, error) {
+	return "", errors.New("timeout")
+}
+
+func hostFileConfig() *Config {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Hostfiles = []string{filepath.Join("testdata", "hosts")}
+	cfg.EnableSystemResolver = false
+	return cfg
+}
+
+func newTestProcessor(t *testing.T, cfg *Config) (*dnsLookupProcessor, *countingResolver) {
+	p, err := newDNSLookupProcessor(cfg, processortest.NewNopSettings(metadata.Type))
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, p.shutdown(context.Background())) })
+	r := &countingResolver{Resolver: p.resolver}
+	p.resolver = r
+	return p, r
+}
+
+func TestProcessTracesResource(t *testing.T) {
+	cfg := hostFileConfig()
+	cfg.Reverse.Enabled = true
+	cfg.Reverse.SourceAttributes = []string{"host.ip"}
+	cfg.Reverse.TargetAttribute = "host.name"
+	p, _ := newTestProcessor(t, cfg)
+
+	td := ptrace.NewTraces()
+	attrs := td.ResourceSpans().AppendEmpty().Resource().Attributes()
+	attrs.PutStr("source.address", "db.example.com")
+	attrs.PutStr("host.ip", "10.0.0.2")
+	// Unknown hostnames and IP addresses are left as they are.
+	unknown := td.ResourceSpans().AppendEmpty().Resource().Attributes()
+	unknown.PutStr("source.address", "unknown.example.com")
+	unknown.PutStr("host.ip", "10.0.0.9")
+	// Values of the wrong kind aren't looked up.
+	wrongKind := td.ResourceSpans().AppendEmpty().Resource().Attributes()
+	wrongKind.PutStr("source.address", "10.0.0.1")
+	wrongKind.PutStr("host.ip", "db.example.com")
+
+	td, err := p.processTraces(context.Background(), td)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"source.address": "db.example.com",
+		"source.ip":      "10.0.0.1",
+		"host.ip":        "10.0.0.2",
+		"host.name":      "cache.example.com",
+	}, td.ResourceSpans().At(0).Resource().Attributes().AsRaw())
+	assert.Equal(t, map[string]any{
+		"source.address": "unknown.example.com",
+		"host.ip":        "10.0.0.9",
+	}, td.ResourceSpans().At(1).Resource().Attributes().AsRaw())
+	assert.Equal(t, map[string]any{
+		"source.address": "10.0.0.1",
+		"host.ip":        "db.example.com",
+	}, td.ResourceSpans().At(2).Resource().Attributes().AsRaw())
+}
+
+func TestProcessRecords(t *testing.T) {
+	cfg := hostFileConfig()
+	cfg.Resolve = LookupConfig{
+		Enabled:          true,
+		Context:          record,
+		SourceAttributes: []string{"server.address", "client.address"},
+		TargetAttribute:  "server.ip",
+	}
+	p, _ := newTestProcessor(t, cfg)
+
+	t.Run("traces", func(t *testing.T) {
+		td := ptrace.NewTraces()
+		rs := td.ResourceSpans().AppendEmpty()
+		rs.Resource().Attributes().PutStr("server.address", "cache.example.com")
+		span := rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+		span.Attributes().PutStr("server.address", "db.example.com")
+
+		td, err := p.processTraces(context.Background(), td)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"server.address": "db.example.com", "server.ip": "10.0.0.1"},
+			td.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Attributes().AsRaw())
+		// The resource isn't looked up in the record context.
+		assert.Equal(t, map[string]any{"server.address": "cache.example.com"}, td.ResourceSpans().At(0).Resource().Attributes().AsRaw())
+	})
+
+	t.Run("logs", func(t *testing.T) {
+		ld := plog.NewLogs()
+		lrs := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+		// The first source attribute holding a hostname is looked up.
+		lr := lrs.AppendEmpty()
+		lr.Attributes().PutStr("server.address", "10.0.0.7")
+		lr.Attributes().PutStr("client.address", "cache.example.com")
+		lrs.AppendEmpty().Attributes().PutInt("server.address", 1)
+
+		ld, err := p.processLogs(context.Background(), ld)
+		require.NoError(t, err)
+		lrs = ld.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+		assert.Equal(t, map[string]any{"server.address": "10.0.0.7", "client.address": "cache.example.com", "server.ip": "10.0.0.2"},
+			lrs.At(0).Attributes().AsRaw())
+		assert.Equal(t, map[string]any{"server.address": int64(1)}, lrs.At(1).Attributes().AsRaw())
+	})
+
+	t.Run("metrics", func(t *testing.T) {
+		md := pmetric.NewMetrics()
+		metrics := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics()
+		metrics.AppendEmpty().SetEmptyGauge().DataPoints().AppendEmpty().Attributes().PutStr("server.address", "db.example.com")
+		metrics.AppendEmpty().SetEmptySum().DataPoints().AppendEmpty().Attributes().PutStr("server.address", "db.example.com")
+		metrics.AppendEmpty().SetEmptyHistogram().DataPoints().AppendEmpty().Attributes().PutStr("server.address", "db.example.com")
+		metrics.AppendEmpty().SetEmptyExponentialHistogram().DataPoints().AppendEmpty().Attributes().PutStr("server.address", "db.example.com")
+		metrics.AppendEmpty().SetEmptySummary().DataPoints().AppendEmpty().Attributes().PutStr("server.address", "db.example.com")
+
+		md, err := p.processMetrics(context.Background(), md)
+		require.NoError(t, err)
+		metrics = md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+		expected := map[string]any{"server.address": "db.example.com", "server.ip": "10.0.0.1"}
+		assert.Equal(t, expected, metrics.At(0).Gauge().DataPoints().At(0).Attributes().AsRaw())
+		assert.Equal(t, expected, metrics.At(1).Sum().DataPoints().At(0).Attributes().AsRaw())
+		assert.Equal(t, expected, metrics.At(2).Histogram().DataPoints().At(0).Attributes().AsRaw())
+		assert.Equal(t, expected, metrics.At(3).ExponentialHistogram().DataPoints().At(0).Attributes().AsRaw())
+		assert.Equal(t, expected, metrics.At(4).Summary().DataPoints().At(0).Attributes().AsRaw())
+	})
+}
+
+func TestProcessCaches(t *testing.T) {
+	p, r := newTestProcessor(t, hostFileConfig())
+
+	ld := plog.NewLogs()
+	for _, address := range []string{"db.example.com", "db.example.com", "unknown.example.com", "unknown.example.com"} {
+		ld.ResourceLogs().AppendEmpty().Resource().Attributes().PutStr("source.address", address)
+	}
+	for i := 0; i < 2; i++ {
+		_, err := p.processLogs(context.Background(), ld)
+		require.NoError(t, err)
+	}
+	// The successful lookups and the lookups without records are cached.
+	assert.Equal(t, int64(2), r.calls.Load())
+	assert.Equal(t, "10.0.0.1", ld.ResourceLogs().At(1).Resource().Attributes().AsRaw()["source.ip"])
+
+	p, r = newTestProcessor(t, func() *Config {
+		cfg := hostFileConfig()
+		cfg.HitCacheSize = 0
+		cfg.MissCacheSize = 0
+		return cfg
+	}())
+	for i := 0; i < 2; i++ {
+		_, err := p.processLogs(context.Background(), ld)
+		require.NoError(t, err)
+	}
+	// The values are still looked up once per batch.
+	assert.Equal(t, int64(4), r.calls.Load())
+}
+
+func TestProcessFailuresNotCached(t *testing.T) {
+	p, _ := newTestProcessor(t, hostFileConfig())
+	r := &countingResolver{Resolver: failingResolver{}}
+	p.resolver = r
+
+	ld := plog.NewLogs()
+	ld.ResourceLogs().AppendEmpty().Resource().Attributes().PutStr("source.address", "db.example.com")
+	for i := 0; i < 2; i++ {
+		_, err := p.processLogs(context.Background(), ld)
+		require.NoError(t, err)
+	}
+	// The lookups which failed, e.g. timed out, are retried.
+	assert.Equal(t, int64(2), r.calls.Load())
+	assert.Equal(t, map[string]any{"source.address": "db.example.com"}, ld.ResourceLogs().At(0).Resource().Attributes().AsRaw())
+}
+
+func TestProcessConcurrentLookups(t *testing.T) {
+	newBatch := func(addresses ...string) plog.Logs {
+		ld := plog.NewLogs()
+		for _, address := range addresses {
+			ld.ResourceLogs().AppendEmpty().Resource().Attributes().PutStr("source.address", address)
+		}
+		return ld
+	}
+	waitStarted := func(t *testing.T, r *blockingResolver) string {
+		select {
+		case hostname := <-r.started:
+			return hostname
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "lookup not started")
+			return ""
+		}
+	}
+
+	t.Run("distinct values", func(t *testing.T) {
+		cfg := hostFileConfig()
+		cfg.Timeout = time.Minute
+		p, _ := newTestProcessor(t, cfg)
+		r := newBlockingResolver()
+		p.resolver = r
+
+		ld := newBatch("db.example.com", "cache.example.com", "db.example.com")
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			_, err := p.processLogs(context.Background(), ld)
+			assert.NoError(t, err)
+		}()
+		// Both values are looked up before any lookup returns.
+		assert.ElementsMatch(t, []string{"db.example.com", "cache.example.com"}, []string{waitStarted(t, r), waitStarted(t, r)})
+		close(r.release)
+		<-done
+
+		assert.Equal(t, int64(2), r.calls.Load())
+		for i := 0; i < ld.ResourceLogs().Len(); i++ {
+			assert.Equal(t, "10.0.0.1", ld.ResourceLogs().At(i).Resource().Attributes().AsRaw()["source.ip"])
+		}
+	})
+
+	t.Run("same value", func(t *testing.T) {
+		cfg := hostFileConfig()
+		cfg.Timeout = time.Minute
+		cfg.HitCacheSize = 0
+		p, _ := newTestProcessor(t, cfg)
+		r := newBlockingResolver()
+		p.resolver = r
+
+		batches := []plog.Logs{newBatch("db.example.com"), newBatch("db.example.com")}
+		var wg sync.WaitGroup
+		process := func(ld plog.Logs) {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := p.processLogs(context.Background(), ld)
+				assert.NoError(t, err)
+			}()
+		}
+		process(batches[0])
+		waitStarted(t, r)
+		// The lookup of the second batch waits for the lookup of the first one.
+		process(batches[1])
+		require.Never(t, func() bool { return len(r.started) > 0 }, 100*time.Millisecond, 10*time.Millisecond)
+		close(r.release)
+		wg.Wait()
+
+		assert.Equal(t, int64(1), r.calls.Load())
+		for _, ld := range batches {
+			assert.Equal(t, "10.0.0.1", ld.ResourceLogs().At(0).Resource().Attributes().AsRaw()["source.ip"])
+		}
+	})
+
+	t.Run("max concurrent lookups", func(t *testing.T) {
+		cfg := hostFileConfig()
+		cfg.Timeout = time.Minute
+		cfg.MaxConcurrentLookups = 1
+		p, _ := newTestProcessor(t, cfg)
+		r := newBlockingResolver()
+		p.resolver = r
+
+		ld := newBatch("db.example.com", "cache.example.com")
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			_, err := p.processLogs(context.Background(), ld)
+			assert.NoError(t, err)
+		}()
+		waitStarted(t, r)
+		require.Never(t, func() bool { return len(r.started) > 0 }, 100*time.Millisecond, 10*time.Millisecond)
+		close(r.release)
+		<-done
+
+		assert.Equal(t, int64(2), r.calls.Load())
+	})
+}
+
+func TestProcessTelemetry(t *testing.T) {
+	tel := componenttest.NewTelemetry()
+	t.Cleanup(func() { require.NoError(t, tel.Shutdown(context.Background())) })
+
+	cfg := hostFileConfig()
+	cfg.Reverse.Enabled = true
+	sink := new(consumertest.LogsSink)
+	lp, err := NewFactory().CreateLogs(context.Background(), metadatatest.NewSettings(tel), cfg, sink)
+	require.NoError(t, err)
+	require.NoError(t, lp.Start(context.Background(), componenttest.NewNopHost()))
+
+	ld := plog.NewLogs()
+	for _, address := range []string{"db.example.com", "unknown.example.com"} {
+		ld.ResourceLogs().AppendEmpty().Resource().Attributes().PutStr("source.address", address)
+	}
+	ld.ResourceLogs().AppendEmpty().Resource().Attributes().PutStr("source.ip", "10.0.0.2")
+	require.NoError(t, lp.ConsumeLogs(context.Background(), ld))
+	ld = plog.NewLogs()
+	ld.ResourceLogs().AppendEmpty().Resource().Attributes().PutStr("source.address", "db.example.com")
+	require.NoError(t, lp.ConsumeLogs(context.Background(), ld))
+	require.NoError(t, lp.Shutdown(context.Background()))
+
+	// The IP addresses resolved are looked up again by the reverse lookup.
+	resolve := attribute.NewSet(attribute.String("lookup", "resolve"))
+	reverse := attribute.NewSet(attribute.String("lookup", "reverse"))
+	metadatatest.AssertEqualProcessorDnslookupLookups(t, tel, []metricdata.DataPoint[int64]{
+		{Attributes: resolve, Value: 2},
+		{Attributes: reverse, Value: 2},
+	}, metricdatatest.IgnoreTimestamp())
+	metadatatest.AssertEqualProcessorDnslookupCacheHits(t, tel, []metricdata.DataPoint[int64]{
+		{Attributes: resolve, Value: 1},
+		{Attributes: reverse, Value: 1},
+	}, metricdatatest.IgnoreTimestamp())
+	metadatatest.AssertEqualProcessorDnslookupLookupFailures(t, tel, []metricdata.DataPoint[int64]{
+		{Attributes: resolve, Value: 1},
+	}, metricdatatest.IgnoreTimestamp())
+
+	require.Len(t, sink.AllLogs(), 2)
+	resources := sink.AllLogs()[0].ResourceLogs()
+	assert.Equal(t, pcommon.NewValueStr("10.0.0.1"), mustGet(t, resources.At(0).Resource().Attributes(), "source.ip"))
+	assert.Equal(t, pcommon.NewValueStr("cache.example.com"), mustGet(t, resources.At(2).Resource().Attributes(), "source.address"))
+	resources = sink.AllLogs()[1].ResourceLogs()
+	assert.Equal(t, pcommon.NewValueStr("10.0.0.1"), mustGet(t, resources.At(0).Resource().Attributes(), "source.ip"))
+}
+
+func mustGet(t *testing.T, attrs pcommon.Map, key string) pcommon.Value {
+	v, ok := attrs.Get(key)
+	require.True(t, ok, key)
+	return v
+}
+
+func TestNewProcessorHostFileError(t *testing.T) {
+	cfg := hostFileConfig()
+	cfg.Hostfiles = []string{filepath.Join("testdata", "missing")}
+	_, err := NewFactory().CreateTraces(context.Background(), processortest.NewNopSettings(metadata.Type), cfg, consumertest.NewNop())
+	assert.ErrorContains(t, err, "failed to load host file")
+}
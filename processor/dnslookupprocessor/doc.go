@@ -3,5 +3,5 @@
 
 //go:generate mdatagen metadata.yaml
 
-// Package dnslookupprocessor
+// Package dnslookupprocessor resolves the hostnames and IP addresses held by attributes.
 package dnslookupprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/dnslookupprocessor"
@@ -5,6 +5,7 @@ package dnslookupprocessor // import "github.com/open-telemetry/opentelemetry-co
 
 import (
 	"context"
+	"time"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
@@ -16,6 +17,16 @@ import (
 
 var processorCapabilities = consumer.Capabilities{MutatesData: true}
 
+const (
+	defaultHitCacheSize  = 1000
+	defaultHitCacheTTL   = 60 * time.Second
+	defaultMissCacheSize = 1000
+	defaultMissCacheTTL  = 5 * time.Second
+	defaultTimeout       = 500 * time.Millisecond
+
+	defaultMaxConcurrentLookups = 16
+)
+
 // NewFactory creates a new processor factory with default configuration,
 // and registers the processors for metrics, traces, and logs.
 func NewFactory() processor.Factory {
@@ -24,17 +35,49 @@ func NewFactory() processor.Factory {
 
 // createDefaultConfig returns a default configuration for the processor.
 func createDefaultConfig() component.Config {
-	return &Config{}
+	return &Config{
+		Resolve: LookupConfig{
+			Enabled:          true,
+			Context:          resource,
+			SourceAttributes: []string{"source.address"},
+			TargetAttribute:  "source.ip",
+		},
+		Reverse: LookupConfig{
+			Enabled:          false,
+			Context:          resource,
+			SourceAttributes: []string{"source.ip"},
+			TargetAttribute:  "source.address",
+		},
+		HitCacheSize:         defaultHitCacheSize,
+		HitCacheTTL:          defaultHitCacheTTL,
+		MissCacheSize:        defaultMissCacheSize,
+		MissCacheTTL:         defaultMissCacheTTL,
+		Timeout:              defaultTimeout,
+		MaxConcurrentLookups: defaultMaxConcurrentLookups,
+		EnableSystemResolver: true,
+	}
 }
 
 func createMetricsProcessor(ctx context.Context, set processor.Settings, cfg component.Config, nextConsumer consumer.Metrics) (processor.Metrics, error) {
-	return processorhelper.NewMetrics(ctx, set, cfg, nextConsumer, newDNSLookupProcessor().processMetrics, processorhelper.WithCapabilities(processorCapabilities))
+	dp, err := newDNSLookupProcessor(cfg.(*Config), set)
+	if err != nil {
+		return nil, err
+	}
+	return processorhelper.NewMetrics(ctx, set, cfg, nextConsumer, dp.processMetrics, processorhelper.WithCapabilities(processorCapabilities), processorhelper.WithShutdown(dp.shutdown))
 }
 
 func createTracesProcessor(ctx context.Context, set processor.Settings, cfg component.Config, nextConsumer consumer.Traces) (processor.Traces, error) {
-	return processorhelper.NewTraces(ctx, set, cfg, nextConsumer, newDNSLookupProcessor().processTraces, processorhelper.WithCapabilities(processorCapabilities))
+	dp, err := newDNSLookupProcessor(cfg.(*Config), set)
+	if err != nil {
+		return nil, err
+	}
+	return processorhelper.NewTraces(ctx, set, cfg, nextConsumer, dp.processTraces, processorhelper.WithCapabilities(processorCapabilities), processorhelper.WithShutdown(dp.shutdown))
 }
 
 func createLogsProcessor(ctx context.Context, set processor.Settings, cfg component.Config, nextConsumer consumer.Logs) (processor.Logs, error) {
-	return processorhelper.NewLogs(ctx, set, cfg, nextConsumer, newDNSLookupProcessor().processLogs, processorhelper.WithCapabilities(processorCapabilities))
+	dp, err := newDNSLookupProcessor(cfg.(*Config), set)
+	if err != nil {
+		return nil, err
+	}
+	return processorhelper.NewLogs(ctx, set, cfg, nextConsumer, dp.processLogs, processorhelper.WithCapabilities(processorCapabilities), processorhelper.WithShutdown(dp.shutdown))
 }
@@ -0,0 +1,91 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package resolver // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/dnslookupprocessor/internal/resolver"
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/netip"
+	"os"
+	"strings"
+)
+
+// hostFileResolver resolves the hostnames and IP addresses of host files, in the format of /etc/hosts.
+type hostFileResolver struct {
+	// addresses maps the lower case hostnames to their first IP address.
+	addresses map[string]string
+	// hostnames maps the IP addresses to their first hostname.
+	hostnames map[string]string
+}
+
+// NewHostFileResolver returns a resolver of the hostnames and IP addresses of the host files. When an entry
+// appears more than once, the first one wins.
+func NewHostFileResolver(paths []string) (Resolver, error) {
+	r := &hostFileResolver{
+		addresses: map[string]string{},
+		hostnames: map[string]string{},
+	}
+	for _, path := range paths {
+		if err := r.load(path); err != nil {
+			return nil, fmt.Errorf("failed to load host file %q: %w", path, err)
+		}
+	}
+	return r, nil
+}
+
+func (r *hostFileResolver) load(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		addr, err := netip.ParseAddr(fields[0])
+		if err != nil {
+			continue
+		}
+		ip := addr.String()
+		for _, hostname := range fields[1:] {
+			hostname = normalizeHostname(hostname)
+			if _, ok := r.addresses[hostname]; !ok {
+				r.addresses[hostname] = ip
+			}
+		}
+		if _, ok := r.hostnames[ip]; !ok {
+			r.hostnames[ip] = normalizeHostname(fields[1])
+		}
+	}
+	return scanner.Err()
+}
+
+func (r *hostFileResolver) Resolve(_ context.Context, hostname string) (string, error) {
+	if ip, ok := r.addresses[normalizeHostname(hostname)]; ok {
+		return ip, nil
+	}
+	return "", ErrNotFound
+}
+
+func (r *hostFileResolver) Reverse(_ context.Context, ip string) (string, error) {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return "", ErrNotFound
+	}
+	if hostname, ok := r.hostnames[addr.String()]; ok {
+		return hostname, nil
+	}
+	return "", ErrNotFound
+}
+
+// normalizeHostname returns the hostname in lower case, without the trailing dot of fully qualified names.
+func normalizeHostname(hostname string) string {
+	return strings.ToLower(strings.TrimSuffix(hostname, "."))
+}
@@ -0,0 +1,113 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package resolver // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/dnslookupprocessor/internal/resolver"
+
+import (
+	"context"
+	"errors"
+	"net"
+)
+
+// defaultDNSPort is the port of the nameservers configured without one.
+const defaultDNSPort = "53"
+
+// netResolver resolves the hostnames and IP addresses with DNS queries.
+type netResolver struct {
+	resolver *net.Resolver
+}
+
+// NewSystemResolver returns a resolver using the resolver of the system, as configured in /etc/resolv.conf
+// and /etc/hosts on Unix systems.
+func NewSystemResolver() Resolver {
+	return &netResolver{resolver: net.DefaultResolver}
+}
+
+// NewNameserverResolver returns a resolver sending the DNS queries to the nameservers, as "host" or
+// "host:port". The nameservers are tried in order when they can't be reached, but a nameserver answering
+// that there is no record for a hostname or IP address is final.
+func NewNameserverResolver(nameservers []string) Resolver {
+	resolvers := make([]*net.Resolver, 0, len(nameservers))
+	for _, nameserver := range nameservers {
+		address := nameserver
+		if _, _, err := net.SplitHostPort(nameserver); err != nil {
+			address = net.JoinHostPort(nameserver, defaultDNSPort)
+		}
+		resolvers = append(resolvers, &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, network, address)
+			},
+		})
+	}
+	return &nameserverResolver{resolvers: resolvers}
+}
+
+func (r *netResolver) Resolve(ctx context.Context, hostname string) (string, error) {
+	return resolve(ctx, r.resolver, hostname)
+}
+
+func (r *netResolver) Reverse(ctx context.Context, ip string) (string, error) {
+	return reverse(ctx, r.resolver, ip)
+}
+
+// nameserverResolver tries the resolvers of the nameservers in order, until one of them answers.
+type nameserverResolver struct {
+	resolvers []*net.Resolver
+}
+
+func (r *nameserverResolver) Resolve(ctx context.Context, hostname string) (string, error) {
+	return r.lookup(ctx, func(resolver *net.Resolver) (string, error) {
+		return resolve(ctx, resolver, hostname)
+	})
+}
+
+func (r *nameserverResolver) Reverse(ctx context.Context, ip string) (string, error) {
+	return r.lookup(ctx, func(resolver *net.Resolver) (string, error) {
+		return reverse(ctx, resolver, ip)
+	})
+}
+
+func (r *nameserverResolver) lookup(ctx context.Context, lookup func(*net.Resolver) (string, error)) (string, error) {
+	err := ErrNotFound
+	for _, resolver := range r.resolvers {
+		var result string
+		result, err = lookup(resolver)
+		if err == nil || errors.Is(err, ErrNotFound) || ctx.Err() != nil {
+			return result, err
+		}
+	}
+	return "", err
+}
+
+func resolve(ctx context.Context, resolver *net.Resolver, hostname string) (string, error) {
+	addrs, err := resolver.LookupNetIP(ctx, "ip", hostname)
+	if err != nil {
+		return "", lookupError(err)
+	}
+	if len(addrs) == 0 {
+		return "", ErrNotFound
+	}
+	return addrs[0].Unmap().String(), nil
+}
+
+func reverse(ctx context.Context, resolver *net.Resolver, ip string) (string, error) {
+	names, err := resolver.LookupAddr(ctx, ip)
+	if err != nil {
+		return "", lookupError(err)
+	}
+	if len(names) == 0 {
+		return "", ErrNotFound
+	}
+	return normalizeHostname(names[0]), nil
+}
+
+// lookupError returns ErrNotFound when the DNS error is that there is no record.
+func lookupError(err error) error {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+		return ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,112 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package resolver
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// startDNSServer starts a DNS server on UDP answering the A and PTR queries with the records.
+func startDNSServer(t *testing.T, a map[string]string, ptr map[string]string) string {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			var query dnsmessage.Message
+			if err = query.Unpack(buf[:n]); err != nil || len(query.Questions) == 0 {
+				continue
+			}
+			question := query.Questions[0]
+			response := dnsmessage.Message{
+				Header:    dnsmessage.Header{ID: query.ID, Response: true, Authoritative: true, RCode: dnsmessage.RCodeNameError},
+				Questions: query.Questions,
+			}
+			name := question.Name.String()
+			switch question.Type {
+			case dnsmessage.TypeA:
+				if ip, ok := a[name]; ok {
+					response.Header.RCode = dnsmessage.RCodeSuccess
+					response.Answers = append(response.Answers, dnsmessage.Resource{
+						Header: dnsmessage.ResourceHeader{Name: question.Name, Type: dnsmessage.TypeA, Class: dnsmessage.ClassINET, TTL: 60},
+						Body:   &dnsmessage.AResource{A: [4]byte(net.ParseIP(ip).To4())},
+					})
+				}
+			case dnsmessage.TypePTR:
+				if hostname, ok := ptr[name]; ok {
+					response.Header.RCode = dnsmessage.RCodeSuccess
+					response.Answers = append(response.Answers, dnsmessage.Resource{
+						Header: dnsmessage.ResourceHeader{Name: question.Name, Type: dnsmessage.TypePTR, Class: dnsmessage.ClassINET, TTL: 60},
+						Body:   &dnsmessage.PTRResource{PTR: dnsmessage.MustNewName(hostname)},
+					})
+				}
+			default:
+				if _, ok := a[name]; ok {
+					// The name exists, without records of this type.
+					response.Header.RCode = dnsmessage.RCodeSuccess
+				}
+			}
+			packed, err := response.Pack()
+			if err != nil {
+				continue
+			}
+			_, _ = conn.WriteTo(packed, addr)
+		}
+	}()
+	return conn.LocalAddr().String()
+}
+
+func TestNameserverResolver(t *testing.T) {
+	nameserver := startDNSServer(t,
+		map[string]string{"db.example.com.": "10.0.0.1"},
+		map[string]string{"1.0.0.10.in-addr.arpa.": "db.example.com."},
+	)
+	// The unreachable nameservers are skipped.
+	unreachable, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	require.NoError(t, unreachable.Close())
+	r := NewNameserverResolver([]string{unreachable.LocalAddr().String(), nameserver})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	ip, err := r.Resolve(ctx, "db.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.1", ip)
+
+	hostname, err := r.Reverse(ctx, "10.0.0.1")
+	require.NoError(t, err)
+	assert.Equal(t, "db.example.com", hostname)
+
+	_, err = r.Resolve(ctx, "missing.example.com")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = r.Reverse(ctx, "10.0.0.2")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestNameserverResolverUnreachable(t *testing.T) {
+	unreachable, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	require.NoError(t, unreachable.Close())
+	r := NewNameserverResolver([]string{unreachable.LocalAddr().String()})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err = r.Resolve(ctx, "db.example.com")
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrNotFound)
+}
@@ -0,0 +1,60 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package resolver // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/dnslookupprocessor/internal/resolver"
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotFound is returned when the resolver has no record for the hostname or IP address.
+var ErrNotFound = errors.New("no record found")
+
+// Resolver resolves hostnames to IP addresses, and IP addresses to hostnames.
+type Resolver interface {
+	// Resolve returns the IP address of the hostname.
+	Resolve(ctx context.Context, hostname string) (string, error)
+	// Reverse returns the hostname of the IP address.
+	Reverse(ctx context.Context, ip string) (string, error)
+}
+
+// chainResolver tries the resolvers in order, until one of them finds a record.
+type chainResolver struct {
+	resolvers []Resolver
+}
+
+// NewChainResolver returns a resolver trying the resolvers in order. It returns ErrNotFound if none of
+// them found a record, or the last error other than ErrNotFound if some of them failed.
+func NewChainResolver(resolvers ...Resolver) Resolver {
+	return &chainResolver{resolvers: resolvers}
+}
+
+func (c *chainResolver) Resolve(ctx context.Context, hostname string) (string, error) {
+	return c.lookup(ctx, func(r Resolver) (string, error) {
+		return r.Resolve(ctx, hostname)
+	})
+}
+
+func (c *chainResolver) Reverse(ctx context.Context, ip string) (string, error) {
+	return c.lookup(ctx, func(r Resolver) (string, error) {
+		return r.Reverse(ctx, ip)
+	})
+}
+
+func (c *chainResolver) lookup(ctx context.Context, lookup func(Resolver) (string, error)) (string, error) {
+	err := ErrNotFound
+	for _, r := range c.resolvers {
+		result, lookupErr := lookup(r)
+		if lookupErr == nil {
+			return result, nil
+		}
+		if !errors.Is(lookupErr, ErrNotFound) {
+			err = lookupErr
+		}
+		if ctx.Err() != nil {
+			return "", err
+		}
+	}
+	return "", err
+}
@@ -0,0 +1,124 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package resolver
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type staticResolver struct {
+	result string
+	err    error
+	calls  int
+}
+
+func (r *staticResolver) Resolve(context.Context, string) (string, error) {
+	r.calls++
+	return r.result, r.err
+}
+
+func (r *staticResolver) Reverse(context.Context, string) (string, error) {
+	r.calls++
+	return r.result, r.err
+}
+
+func TestChainResolver(t *testing.T) {
+	notFound := &staticResolver{err: ErrNotFound}
+	failed := &staticResolver{err: errors.New("timeout")}
+	found := &staticResolver{result: "10.0.0.1"}
+	unused := &staticResolver{result: "10.0.0.2"}
+
+	ip, err := NewChainResolver(notFound, failed, found, unused).Resolve(context.Background(), "example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.1", ip)
+	assert.Equal(t, 0, unused.calls)
+
+	_, err = NewChainResolver(notFound, notFound).Reverse(context.Background(), "10.0.0.1")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// The errors other than not found take precedence, as the record may exist.
+	_, err = NewChainResolver(failed, notFound).Reverse(context.Background(), "10.0.0.1")
+	assert.EqualError(t, err, "timeout")
+
+	_, err = NewChainResolver().Resolve(context.Background(), "example.com")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestChainResolverCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	failed := &staticResolver{err: context.Canceled}
+	unused := &staticResolver{result: "10.0.0.1"}
+	_, err := NewChainResolver(failed, unused).Resolve(ctx, "example.com")
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 0, unused.calls)
+}
+
+func writeHostFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "hosts")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestHostFileResolver(t *testing.T) {
+	first := writeHostFile(t, `# The hosts of the test
+127.0.0.1 localhost
+10.0.0.1  db.example.com db   # The database
+10.0.0.2  Cache.Example.Com.
+::1       localhost ip6-localhost
+2001:db8::1 web.example.com
+invalid   broken.example.com
+10.0.0.3
+`)
+	second := writeHostFile(t, `10.0.0.9 db.example.com other.example.com
+10.0.0.1 ignored.example.com
+`)
+	r, err := NewHostFileResolver([]string{first, second})
+	require.NoError(t, err)
+
+	for hostname, expected := range map[string]string{
+		"localhost":          "127.0.0.1",
+		"db.example.com":     "10.0.0.1",
+		"DB":                 "10.0.0.1",
+		"cache.example.com.": "10.0.0.2",
+		"web.example.com":    "2001:db8::1",
+		"other.example.com":  "10.0.0.9",
+	} {
+		ip, err := r.Resolve(context.Background(), hostname)
+		require.NoError(t, err, hostname)
+		assert.Equal(t, expected, ip, hostname)
+	}
+	_, err = r.Resolve(context.Background(), "broken.example.com")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	for ip, expected := range map[string]string{
+		"127.0.0.1":             "localhost",
+		"10.0.0.1":              "db.example.com",
+		"10.0.0.2":              "cache.example.com",
+		"::1":                   "localhost",
+		"2001:0db8:0:0:0:0:0:1": "web.example.com",
+		"10.0.0.9":              "db.example.com",
+	} {
+		hostname, err := r.Reverse(context.Background(), ip)
+		require.NoError(t, err, ip)
+		assert.Equal(t, expected, hostname, ip)
+	}
+	_, err = r.Reverse(context.Background(), "10.0.0.3")
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = r.Reverse(context.Background(), "not an ip")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestHostFileResolverMissingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing")
+	_, err := NewHostFileResolver([]string{path})
+	assert.ErrorContains(t, err, `failed to load host file "`+path+`"`)
+}
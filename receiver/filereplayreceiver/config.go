@@ -0,0 +1,107 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package filereplayreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/filereplayreceiver"
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"go.opentelemetry.io/collector/component"
+)
+
+const (
+	// the format of encoded telemetry data, as written by the file exporter
+	formatTypeJSON  = "json"
+	formatTypeProto = "proto"
+
+	// the type of compression codec
+	compressionZSTD = "zstd"
+
+	// pacingNone replays the telemetry as fast as possible.
+	pacingNone = "none"
+	// pacingTimestamps replays the telemetry at the pace of its original timestamps.
+	pacingTimestamps = "timestamps"
+)
+
+// Config defines configuration for the file replay receiver.
+type Config struct {
+	// Include is the list of glob patterns of the files to replay, supporting `**` to match
+	// the directories written by the file exporter when group_by is enabled.
+	Include []string `mapstructure:"include"`
+
+	// Exclude is the list of glob patterns of the files not to replay.
+	Exclude []string `mapstructure:"exclude"`
+
+	// FormatType is the format the files were written with by the file exporter.
+	// Options:
+	// - json[default]:  OTLP json bytes.
+	// - proto:  OTLP binary protobuf bytes.
+	FormatType string `mapstructure:"format"`
+
+	// Encoding is the encoding extension the files were written with by the file exporter.
+	// Overrides FormatType.
+	Encoding *component.ID `mapstructure:"encoding"`
+
+	// Compression is the compression the files were written with by the file exporter.
+	// Options:
+	// - zstd
+	Compression string `mapstructure:"compression"`
+
+	// Replay defines how the telemetry is replayed.
+	Replay ReplayConfig `mapstructure:"replay"`
+
+	// prevent unkeyed literal initialization
+	_ struct{}
+}
+
+// ReplayConfig defines how the telemetry is replayed.
+type ReplayConfig struct {
+	// Pacing defines the pace of the replay.
+	// Options:
+	// - none[default]: as fast as possible.
+	// - timestamps: at the pace of the original timestamps of the telemetry.
+	Pacing string `mapstructure:"pacing"`
+
+	// Speed is the factor applied to the pace of the original timestamps, e.g. 2 replays
+	// the telemetry twice as fast as it was recorded.
+	Speed float64 `mapstructure:"speed"`
+
+	// Loop replays the files again once they have all been replayed, until the receiver is shut down.
+	Loop bool `mapstructure:"loop"`
+
+	// MaxOpenFiles is the maximum number of files read together. The files are merged in groups of
+	// at most MaxOpenFiles files, in the order of their paths, the groups being replayed one after the other.
+	MaxOpenFiles int `mapstructure:"max_open_files"`
+}
+
+var _ component.Config = (*Config)(nil)
+
+// Validate checks if the receiver configuration is valid
+func (cfg *Config) Validate() error {
+	if len(cfg.Include) == 0 {
+		return errors.New("include must not be empty")
+	}
+	for _, pattern := range append(append([]string{}, cfg.Include...), cfg.Exclude...) {
+		if !doublestar.ValidatePattern(pattern) {
+			return fmt.Errorf("invalid glob pattern %q", pattern)
+		}
+	}
+	if cfg.FormatType != formatTypeJSON && cfg.FormatType != formatTypeProto {
+		return errors.New("format type is not supported")
+	}
+	if cfg.Compression != "" && cfg.Compression != compressionZSTD {
+		return errors.New("compression is not supported")
+	}
+	if cfg.Replay.Pacing != pacingNone && cfg.Replay.Pacing != pacingTimestamps {
+		return fmt.Errorf("pacing must be %q or %q", pacingNone, pacingTimestamps)
+	}
+	if cfg.Replay.Speed <= 0 {
+		return errors.New("speed must be larger than zero")
+	}
+	if cfg.Replay.MaxOpenFiles <= 0 {
+		return errors.New("max_open_files must be larger than zero")
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package filereplayreceiver
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap/confmaptest"
+	"go.opentelemetry.io/collector/confmap/xconfmap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/filereplayreceiver/internal/metadata"
+)
+
+func TestLoadConfig(t *testing.T) {
+	t.Parallel()
+
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		id           component.ID
+		expected     component.Config
+		errorMessage string
+	}{
+		{
+			id: component.NewID(metadata.Type),
+			expected: &Config{
+				Include:    []string{"./capture/*.json"},
+				FormatType: formatTypeJSON,
+				Replay: ReplayConfig{
+					Pacing:       pacingNone,
+					Speed:        1,
+					MaxOpenFiles: defaultMaxOpenFiles,
+				},
+			},
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "proto"),
+			expected: &Config{
+				Include:     []string{"./capture/**/*.proto"},
+				Exclude:     []string{"./capture/old/**"},
+				FormatType:  formatTypeProto,
+				Compression: compressionZSTD,
+				Replay: ReplayConfig{
+					Pacing:       pacingTimestamps,
+					Speed:        2,
+					Loop:         true,
+					MaxOpenFiles: 10,
+				},
+			},
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "no_include"),
+			errorMessage: "include must not be empty",
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "invalid_pattern"),
+			errorMessage: `invalid glob pattern "./capture/[*.json"`,
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "invalid_format"),
+			errorMessage: "format type is not supported",
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "invalid_compression"),
+			errorMessage: "compression is not supported",
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "invalid_pacing"),
+			errorMessage: `pacing must be "none" or "timestamps"`,
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "invalid_speed"),
+			errorMessage: "speed must be larger than zero",
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "invalid_max_open_files"),
+			errorMessage: "max_open_files must be larger than zero",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id.String(), func(t *testing.T) {
+			factory := NewFactory()
+			cfg := factory.CreateDefaultConfig()
+
+			sub, err := cm.Sub(tt.id.String())
+			require.NoError(t, err)
+			require.NoError(t, sub.Unmarshal(cfg))
+
+			if tt.expected == nil {
+				assert.EqualError(t, xconfmap.Validate(cfg), tt.errorMessage)
+				return
+			}
+
+			assert.NoError(t, xconfmap.Validate(cfg))
+			assert.Equal(t, tt.expected, cfg)
+		})
+	}
+}
@@ -0,0 +1,8 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate mdatagen metadata.yaml
+
+// Package filereplayreceiver implements a receiver that replays the logs, traces and metrics
+// written to files by the file exporter.
+package filereplayreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/filereplayreceiver"
@@ -0,0 +1,131 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package filereplayreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/filereplayreceiver"
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/receiver"
+	"go.opentelemetry.io/collector/receiver/receiverhelper"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/filereplayreceiver/internal/metadata"
+)
+
+const (
+	transport = "file"
+
+	defaultMaxOpenFiles = 100
+)
+
+// NewFactory creates a factory for the file replay receiver
+func NewFactory() receiver.Factory {
+	return receiver.NewFactory(
+		metadata.Type,
+		createDefaultConfig,
+		receiver.WithTraces(createTracesReceiver, metadata.TracesStability),
+		receiver.WithMetrics(createMetricsReceiver, metadata.MetricsStability),
+		receiver.WithLogs(createLogsReceiver, metadata.LogsStability))
+}
+
+func createDefaultConfig() component.Config {
+	return &Config{
+		FormatType: formatTypeJSON,
+		Replay: ReplayConfig{
+			Pacing:       pacingNone,
+			Speed:        1,
+			MaxOpenFiles: defaultMaxOpenFiles,
+		},
+	}
+}
+
+func newReceiver(settings receiver.Settings, cfg component.Config, s signal) (*fileReplayReceiver, error) {
+	obsrecv, err := receiverhelper.NewObsReport(receiverhelper.ObsReportSettings{
+		ReceiverID:             settings.ID,
+		Transport:              transport,
+		ReceiverCreateSettings: settings,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &fileReplayReceiver{
+		cfg:      cfg.(*Config),
+		settings: settings,
+		obsrecv:  obsrecv,
+		signal:   s,
+	}, nil
+}
+
+func createTracesReceiver(_ context.Context, settings receiver.Settings, cfg component.Config, traces consumer.Traces) (receiver.Traces, error) {
+	r, err := newReceiver(settings, cfg, signal{
+		decode: func(u *unmarshaller, buf []byte) (batch, error) {
+			td, err := u.unmarshalTraces(buf)
+			if err != nil {
+				return batch{}, err
+			}
+			return batch{
+				timestamp: tracesTimestamp(td),
+				count:     td.SpanCount(),
+				consume: func(ctx context.Context) error {
+					return traces.ConsumeTraces(ctx, td)
+				},
+			}, nil
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	r.signal.startOp = r.obsrecv.StartTracesOp
+	r.signal.endOp = r.obsrecv.EndTracesOp
+	return r, nil
+}
+
+func createMetricsReceiver(_ context.Context, settings receiver.Settings, cfg component.Config, metrics consumer.Metrics) (receiver.Metrics, error) {
+	r, err := newReceiver(settings, cfg, signal{
+		decode: func(u *unmarshaller, buf []byte) (batch, error) {
+			md, err := u.unmarshalMetrics(buf)
+			if err != nil {
+				return batch{}, err
+			}
+			return batch{
+				timestamp: metricsTimestamp(md),
+				count:     md.DataPointCount(),
+				consume: func(ctx context.Context) error {
+					return metrics.ConsumeMetrics(ctx, md)
+				},
+			}, nil
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	r.signal.startOp = r.obsrecv.StartMetricsOp
+	r.signal.endOp = r.obsrecv.EndMetricsOp
+	return r, nil
+}
+
+func createLogsReceiver(_ context.Context, settings receiver.Settings, cfg component.Config, logs consumer.Logs) (receiver.Logs, error) {
+	r, err := newReceiver(settings, cfg, signal{
+		decode: func(u *unmarshaller, buf []byte) (batch, error) {
+			ld, err := u.unmarshalLogs(buf)
+			if err != nil {
+				return batch{}, err
+			}
+			return batch{
+				timestamp: logsTimestamp(ld),
+				count:     ld.LogRecordCount(),
+				consume: func(ctx context.Context) error {
+					return logs.ConsumeLogs(ctx, ld)
+				},
+			}, nil
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	r.signal.startOp = r.obsrecv.StartLogsOp
+	r.signal.endOp = r.obsrecv.EndLogsOp
+	return r, nil
+}
@@ -0,0 +1,166 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package filereplayreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/filereplayreceiver"
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"go.uber.org/zap"
+)
+
+// fileCursor reads the batches of a file, one ahead, so that the files are merged by timestamp.
+type fileCursor struct {
+	path string
+	// index is the position of the file in the sorted list of files, which orders the batches with the same timestamp.
+	index  int
+	file   *os.File
+	reader frameReader
+	next   batch
+	// decodeErrors is the number of messages of the file that couldn't be decoded.
+	decodeErrors int
+}
+
+// cursorHeap orders the files by the timestamp of their next batch, the batches without timestamps first.
+type cursorHeap []*fileCursor
+
+func (h cursorHeap) Len() int { return len(h) }
+
+func (h cursorHeap) Less(i, j int) bool {
+	if h[i].next.timestamp != h[j].next.timestamp {
+		return h[i].next.timestamp < h[j].next.timestamp
+	}
+	return h[i].index < h[j].index
+}
+
+func (h cursorHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *cursorHeap) Push(x any) { *h = append(*h, x.(*fileCursor)) }
+
+func (h *cursorHeap) Pop() any {
+	old := *h
+	c := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return c
+}
+
+// replayFiles replays the batches of the files merged in timestamp order, so that the telemetry of files written
+// at the same time, e.g. the files of the group_by directories of the file exporter, is paced together.
+//
+// At most max_open_files files are open at once: the files are merged in groups of consecutive paths, which are
+// replayed one after the other, the pacing restarting with each group.
+func (r *fileReplayReceiver) replayFiles(ctx context.Context, u *unmarshaller, p *pacer, files []string) error {
+	for start := 0; start < len(files); start += r.cfg.Replay.MaxOpenFiles {
+		if start > 0 {
+			p.reset()
+		}
+		end := min(start+r.cfg.Replay.MaxOpenFiles, len(files))
+		if err := r.mergeFiles(ctx, u, p, files[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeFiles replays the batches of the files merged in timestamp order.
+func (r *fileReplayReceiver) mergeFiles(ctx context.Context, u *unmarshaller, p *pacer, files []string) error {
+	h := make(cursorHeap, 0, len(files))
+	defer func() {
+		for _, c := range h {
+			c.file.Close()
+		}
+	}()
+	for i, path := range files {
+		c, err := r.openCursor(ctx, u, path, i)
+		if err != nil {
+			r.settings.Logger.Error("Failed to replay file", zap.String("file", path), zap.Error(err))
+			continue
+		}
+		if c != nil {
+			h = append(h, c)
+		}
+	}
+	heap.Init(&h)
+
+	for h.Len() > 0 {
+		c := h[0]
+		if err := p.wait(ctx, c.next.timestamp); err != nil {
+			return err
+		}
+		opCtx := r.signal.startOp(ctx)
+		err := c.next.consume(opCtx)
+		r.signal.endOp(opCtx, r.format(), c.next.count, err)
+
+		ok, err := r.advance(ctx, u, c)
+		if err != nil {
+			r.settings.Logger.Error("Failed to replay file", zap.String("file", c.path), zap.Error(err))
+		}
+		if ok {
+			heap.Fix(&h, 0)
+			continue
+		}
+		heap.Pop(&h)
+		c.file.Close()
+	}
+	return nil
+}
+
+// openCursor opens the file and reads its first batch. It returns nil if the file holds no batches.
+func (r *fileReplayReceiver) openCursor(ctx context.Context, u *unmarshaller, path string, index int) (*fileCursor, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	c := &fileCursor{path: path, index: index, file: f, reader: newFrameReader(f, r.cfg)}
+	ok, err := r.advance(ctx, u, c)
+	if !ok {
+		f.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// advance reads the next batch of the file holding telemetry of the signal. It returns false at the end of the file,
+// or if the file can't be read anymore.
+func (r *fileReplayReceiver) advance(ctx context.Context, u *unmarshaller, c *fileCursor) (bool, error) {
+	for {
+		buf, err := c.reader.next()
+		if errors.Is(err, io.EOF) {
+			r.logDecodeErrors(c)
+			return false, nil
+		}
+		if err != nil {
+			r.logDecodeErrors(c)
+			return false, fmt.Errorf("failed to read message: %w", err)
+		}
+		b, err := r.signal.decode(u, buf)
+		if err != nil {
+			c.decodeErrors++
+			if c.decodeErrors == 1 {
+				r.settings.Logger.Warn("Failed to decode message", zap.String("file", c.path), zap.Error(err))
+			}
+			opCtx := r.signal.startOp(ctx)
+			r.signal.endOp(opCtx, r.format(), 0, err)
+			continue
+		}
+		if b.count == 0 {
+			// The file holds another type of telemetry.
+			continue
+		}
+		c.next = b
+		return true, nil
+	}
+}
+
+// logDecodeErrors logs the number of messages of the file that couldn't be decoded, the first error being logged
+// when it occurs.
+func (r *fileReplayReceiver) logDecodeErrors(c *fileCursor) {
+	if c.decodeErrors > 1 {
+		r.settings.Logger.Warn("Failed to decode messages", zap.String("file", c.path), zap.Int("messages", c.decodeErrors))
+	}
+}
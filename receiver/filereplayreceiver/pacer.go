@@ -0,0 +1,152 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package filereplayreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/filereplayreceiver"
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// pacer delays the batches so that they are replayed at the pace of their original timestamps.
+type pacer struct {
+	pacing string
+	speed  float64
+
+	// start is the time the first batch with a timestamp was replayed, and first its timestamp.
+	start time.Time
+	first pcommon.Timestamp
+}
+
+func newPacer(cfg ReplayConfig) *pacer {
+	return &pacer{pacing: cfg.Pacing, speed: cfg.Speed}
+}
+
+// wait blocks until the batch with the timestamp ts is due, or ctx is done. The batches without
+// timestamps, or with timestamps before the first one, are due immediately.
+func (p *pacer) wait(ctx context.Context, ts pcommon.Timestamp) error {
+	if p.pacing != pacingTimestamps || ts == 0 {
+		return nil
+	}
+	if p.first == 0 {
+		p.start = time.Now()
+		p.first = ts
+		return nil
+	}
+	if ts <= p.first {
+		return nil
+	}
+	d := time.Until(p.start.Add(time.Duration(float64(ts-p.first) / p.speed)))
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// reset restarts the pacing, e.g. when the files are replayed again.
+func (p *pacer) reset() {
+	p.first = 0
+}
+
+// earliest returns the earliest of the non-zero timestamps a and b.
+func earliest(a, b pcommon.Timestamp) pcommon.Timestamp {
+	if a == 0 || (b != 0 && b < a) {
+		return b
+	}
+	return a
+}
+
+// logsTimestamp returns the earliest timestamp of the log records, or their observed timestamp
+// when they don't have one.
+func logsTimestamp(ld plog.Logs) pcommon.Timestamp {
+	var ts pcommon.Timestamp
+	for i := 0; i < ld.ResourceLogs().Len(); i++ {
+		sls := ld.ResourceLogs().At(i).ScopeLogs()
+		for j := 0; j < sls.Len(); j++ {
+			lrs := sls.At(j).LogRecords()
+			for k := 0; k < lrs.Len(); k++ {
+				lr := lrs.At(k)
+				if lr.Timestamp() != 0 {
+					ts = earliest(ts, lr.Timestamp())
+				} else {
+					ts = earliest(ts, lr.ObservedTimestamp())
+				}
+			}
+		}
+	}
+	return ts
+}
+
+// tracesTimestamp returns the earliest start timestamp of the spans.
+func tracesTimestamp(td ptrace.Traces) pcommon.Timestamp {
+	var ts pcommon.Timestamp
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		sss := td.ResourceSpans().At(i).ScopeSpans()
+		for j := 0; j < sss.Len(); j++ {
+			spans := sss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				ts = earliest(ts, spans.At(k).StartTimestamp())
+			}
+		}
+	}
+	return ts
+}
+
+// metricsTimestamp returns the earliest timestamp of the data points.
+func metricsTimestamp(md pmetric.Metrics) pcommon.Timestamp {
+	var ts pcommon.Timestamp
+	for i := 0; i < md.ResourceMetrics().Len(); i++ {
+		sms := md.ResourceMetrics().At(i).ScopeMetrics()
+		for j := 0; j < sms.Len(); j++ {
+			metrics := sms.At(j).Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				ts = earliest(ts, metricTimestamp(metrics.At(k)))
+			}
+		}
+	}
+	return ts
+}
+
+func metricTimestamp(m pmetric.Metric) pcommon.Timestamp {
+	var ts pcommon.Timestamp
+	switch m.Type() {
+	case pmetric.MetricTypeGauge:
+		dps := m.Gauge().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			ts = earliest(ts, dps.At(i).Timestamp())
+		}
+	case pmetric.MetricTypeSum:
+		dps := m.Sum().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			ts = earliest(ts, dps.At(i).Timestamp())
+		}
+	case pmetric.MetricTypeHistogram:
+		dps := m.Histogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			ts = earliest(ts, dps.At(i).Timestamp())
+		}
+	case pmetric.MetricTypeExponentialHistogram:
+		dps := m.ExponentialHistogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			ts = earliest(ts, dps.At(i).Timestamp())
+		}
+	case pmetric.MetricTypeSummary:
+		dps := m.Summary().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			ts = earliest(ts, dps.At(i).Timestamp())
+		}
+	}
+	return ts
+}
@@ -0,0 +1,83 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package filereplayreceiver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+func TestPacer(t *testing.T) {
+	p := newPacer(ReplayConfig{Pacing: pacingTimestamps, Speed: 2})
+	first := pcommon.NewTimestampFromTime(time.Date(2024, 0o1, 10, 10, 30, 40, 0, time.UTC))
+
+	start := time.Now()
+	require.NoError(t, p.wait(context.Background(), first))
+	// The batches without timestamps, or earlier than the first one, aren't delayed.
+	require.NoError(t, p.wait(context.Background(), 0))
+	require.NoError(t, p.wait(context.Background(), first-pcommon.Timestamp(time.Hour)))
+	assert.Less(t, time.Since(start), 100*time.Millisecond)
+
+	// 200ms after the first batch, replayed twice as fast.
+	require.NoError(t, p.wait(context.Background(), first+pcommon.Timestamp(200*time.Millisecond)))
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+
+	// The pacing restarts from the next batch after a reset.
+	p.reset()
+	start = time.Now()
+	require.NoError(t, p.wait(context.Background(), first+pcommon.Timestamp(time.Hour)))
+	assert.Less(t, time.Since(start), 100*time.Millisecond)
+}
+
+func TestPacerCanceled(t *testing.T) {
+	p := newPacer(ReplayConfig{Pacing: pacingTimestamps, Speed: 1})
+	require.NoError(t, p.wait(context.Background(), 1))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, p.wait(ctx, pcommon.Timestamp(time.Hour)), context.Canceled)
+}
+
+func TestPacerNone(t *testing.T) {
+	p := newPacer(ReplayConfig{Pacing: pacingNone, Speed: 1})
+	require.NoError(t, p.wait(context.Background(), 1))
+	start := time.Now()
+	require.NoError(t, p.wait(context.Background(), pcommon.Timestamp(time.Hour)))
+	assert.Less(t, time.Since(start), 100*time.Millisecond)
+}
+
+func TestTimestamps(t *testing.T) {
+	ld := plog.NewLogs()
+	lrs := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	lrs.AppendEmpty().SetTimestamp(30)
+	lrs.AppendEmpty().SetObservedTimestamp(20)
+	lrs.AppendEmpty()
+	assert.Equal(t, pcommon.Timestamp(20), logsTimestamp(ld))
+
+	td := ptrace.NewTraces()
+	spans := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+	spans.AppendEmpty().SetStartTimestamp(40)
+	spans.AppendEmpty().SetStartTimestamp(30)
+	assert.Equal(t, pcommon.Timestamp(30), tracesTimestamp(td))
+
+	md := pmetric.NewMetrics()
+	metrics := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics()
+	metrics.AppendEmpty().SetEmptyGauge().DataPoints().AppendEmpty().SetTimestamp(50)
+	metrics.AppendEmpty().SetEmptySum().DataPoints().AppendEmpty().SetTimestamp(40)
+	metrics.AppendEmpty().SetEmptyHistogram().DataPoints().AppendEmpty().SetTimestamp(30)
+	metrics.AppendEmpty().SetEmptyExponentialHistogram().DataPoints().AppendEmpty().SetTimestamp(20)
+	metrics.AppendEmpty().SetEmptySummary().DataPoints().AppendEmpty().SetTimestamp(60)
+	metrics.AppendEmpty().SetEmptyGauge().DataPoints().AppendEmpty()
+	assert.Equal(t, pcommon.Timestamp(20), metricsTimestamp(md))
+
+	assert.Equal(t, pcommon.Timestamp(0), logsTimestamp(plog.NewLogs()))
+}
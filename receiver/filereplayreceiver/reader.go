@@ -0,0 +1,125 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package filereplayreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/filereplayreceiver"
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/klauspost/compress/zstd"
+)
+
+// maxLineSize is the maximum size of the lines of JSON files, the size of a batch of telemetry.
+const maxLineSize = 64 * 1024 * 1024
+
+// frameReader reads the messages written by the file exporter to a file.
+type frameReader interface {
+	// next returns the next message, or io.EOF at the end of the file.
+	next() ([]byte, error)
+}
+
+// lineReader reads the messages written as lines, when the format is json and the messages aren't compressed.
+type lineReader struct {
+	scanner *bufio.Scanner
+}
+
+func newLineReader(r io.Reader) *lineReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxLineSize)
+	return &lineReader{scanner: scanner}
+}
+
+func (r *lineReader) next() ([]byte, error) {
+	for r.scanner.Scan() {
+		if len(r.scanner.Bytes()) > 0 {
+			return r.scanner.Bytes(), nil
+		}
+	}
+	if err := r.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+// bufferReader reads the messages preceded by their size, as 4 bytes (an unsigned 32 bit integer),
+// decompressing them if needed.
+type bufferReader struct {
+	r          *bufio.Reader
+	decoder    *zstd.Decoder
+	decompress bool
+}
+
+var zstdDecoder, _ = zstd.NewReader(nil)
+
+func newBufferReader(r io.Reader, compression string) *bufferReader {
+	return &bufferReader{
+		r:          bufio.NewReader(r),
+		decoder:    zstdDecoder,
+		decompress: compression == compressionZSTD,
+	}
+}
+
+func (r *bufferReader) next() ([]byte, error) {
+	var size [4]byte
+	if _, err := io.ReadFull(r.r, size[:]); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			// The file is truncated, e.g. it is still being written.
+			return nil, io.EOF
+		}
+		return nil, err
+	}
+	n := binary.BigEndian.Uint32(size[:])
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r.r, buf); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+		return nil, err
+	}
+	if !r.decompress {
+		return buf, nil
+	}
+	data, err := r.decoder.DecodeAll(buf, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress message: %w", err)
+	}
+	return data, nil
+}
+
+// newFrameReader returns the reader of the messages written by the file exporter with cfg.
+func newFrameReader(r io.Reader, cfg *Config) frameReader {
+	// The file exporter writes lines only when the format is json and the messages aren't compressed.
+	if cfg.FormatType == formatTypeJSON && cfg.Compression == "" {
+		return newLineReader(r)
+	}
+	return newBufferReader(r, cfg.Compression)
+}
+
+// listFiles returns the files matching the include patterns and none of the exclude patterns, sorted
+// by path, so that the backups rotated by the file exporter are replayed before the current file.
+func listFiles(include, exclude []string) ([]string, error) {
+	var files []string
+	for _, pattern := range include {
+		matches, err := doublestar.FilepathGlob(pattern, doublestar.WithFilesOnly())
+		if err != nil {
+			return nil, err
+		}
+	match:
+		for _, match := range matches {
+			for _, excluded := range exclude {
+				if ok, _ := doublestar.PathMatch(excluded, match); ok {
+					continue match
+				}
+			}
+			files = append(files, match)
+		}
+	}
+	slices.Sort(files)
+	return slices.Compact(files), nil
+}
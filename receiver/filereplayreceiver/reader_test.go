@@ -0,0 +1,138 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package filereplayreceiver
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeLines writes the messages as the file exporter does for the json format without compression.
+func writeLines(messages ...[]byte) []byte {
+	var buf bytes.Buffer
+	for _, m := range messages {
+		buf.Write(m)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
+// writeBuffers writes the messages preceded by their size as the file exporter does for the proto format,
+// or when the messages are compressed.
+func writeBuffers(compress bool, messages ...[]byte) []byte {
+	var buf bytes.Buffer
+	encoder, _ := zstd.NewWriter(nil)
+	for _, m := range messages {
+		if compress {
+			m = encoder.EncodeAll(m, nil)
+		}
+		var size [4]byte
+		binary.BigEndian.PutUint32(size[:], uint32(len(m)))
+		buf.Write(size[:])
+		buf.Write(m)
+	}
+	return buf.Bytes()
+}
+
+func readAll(t *testing.T, r frameReader) []string {
+	var messages []string
+	for {
+		m, err := r.next()
+		if err == io.EOF {
+			return messages
+		}
+		require.NoError(t, err)
+		messages = append(messages, string(m))
+	}
+}
+
+func TestFrameReader(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		cfg  *Config
+		data []byte
+	}{
+		{
+			name: "json",
+			cfg:  &Config{FormatType: formatTypeJSON},
+			data: writeLines([]byte("first"), []byte("second")),
+		},
+		{
+			name: "json with empty lines",
+			cfg:  &Config{FormatType: formatTypeJSON},
+			data: []byte("first\n\nsecond"),
+		},
+		{
+			name: "json compressed",
+			cfg:  &Config{FormatType: formatTypeJSON, Compression: compressionZSTD},
+			data: writeBuffers(true, []byte("first"), []byte("second")),
+		},
+		{
+			name: "proto",
+			cfg:  &Config{FormatType: formatTypeProto},
+			data: writeBuffers(false, []byte("first"), []byte("second")),
+		},
+		{
+			name: "proto compressed",
+			cfg:  &Config{FormatType: formatTypeProto, Compression: compressionZSTD},
+			data: writeBuffers(true, []byte("first"), []byte("second")),
+		},
+		{
+			name: "proto truncated",
+			cfg:  &Config{FormatType: formatTypeProto},
+			data: writeBuffers(false, []byte("first"), []byte("second"), []byte("third"))[:25],
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newFrameReader(bytes.NewReader(tt.data), tt.cfg)
+			assert.Equal(t, []string{"first", "second"}, readAll(t, r))
+		})
+	}
+}
+
+func TestFrameReaderInvalidCompression(t *testing.T) {
+	r := newFrameReader(bytes.NewReader(writeBuffers(false, []byte("first"))), &Config{FormatType: formatTypeProto, Compression: compressionZSTD})
+	_, err := r.next()
+	assert.ErrorContains(t, err, "failed to decompress message")
+}
+
+func TestListFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{
+		"traces.json",
+		"traces-2024-01-10T10-30-40.000.json",
+		"traces-2024-01-10T09-30-40.000.json",
+		filepath.Join("group", "service-a.json"),
+		filepath.Join("group", "nested", "service-b.json"),
+		filepath.Join("old", "traces.json"),
+		"metrics.proto",
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o700))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), nil, 0o600))
+	}
+
+	files, err := listFiles([]string{filepath.Join(dir, "*.json")}, nil)
+	require.NoError(t, err)
+	// The backups are replayed before the current file, in the order they were rotated.
+	assert.Equal(t, []string{
+		filepath.Join(dir, "traces-2024-01-10T09-30-40.000.json"),
+		filepath.Join(dir, "traces-2024-01-10T10-30-40.000.json"),
+		filepath.Join(dir, "traces.json"),
+	}, files)
+
+	files, err = listFiles([]string{filepath.Join(dir, "**", "*.json"), filepath.Join(dir, "group", "*.json")}, []string{filepath.Join(dir, "old", "**"), filepath.Join(dir, "traces*")})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "group", "nested", "service-b.json"),
+		filepath.Join(dir, "group", "service-a.json"),
+	}, files)
+}
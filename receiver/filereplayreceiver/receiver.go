@@ -0,0 +1,103 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package filereplayreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/filereplayreceiver"
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/receiver"
+	"go.opentelemetry.io/collector/receiver/receiverhelper"
+	"go.uber.org/zap"
+)
+
+// noFilesInterval is the interval between the attempts to find files to replay in a loop, when none
+// match the include patterns.
+const noFilesInterval = time.Second
+
+// batch is a batch of telemetry read from a file.
+type batch struct {
+	// timestamp is the earliest timestamp of the telemetry, used for pacing.
+	timestamp pcommon.Timestamp
+	count     int
+	consume   func(context.Context) error
+}
+
+// signal adapts the replay to a type of telemetry.
+type signal struct {
+	decode  func(u *unmarshaller, buf []byte) (batch, error)
+	startOp func(ctx context.Context) context.Context
+	endOp   func(ctx context.Context, format string, count int, err error)
+}
+
+type fileReplayReceiver struct {
+	cfg      *Config
+	settings receiver.Settings
+	obsrecv  *receiverhelper.ObsReport
+	signal   signal
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func (r *fileReplayReceiver) Start(_ context.Context, host component.Host) error {
+	u, err := newUnmarshaller(r.cfg, host)
+	if err != nil {
+		return err
+	}
+	var ctx context.Context
+	ctx, r.cancel = context.WithCancel(context.Background())
+	r.done = make(chan struct{})
+	go r.replay(ctx, u)
+	return nil
+}
+
+func (r *fileReplayReceiver) Shutdown(context.Context) error {
+	if r.cancel == nil {
+		return nil
+	}
+	r.cancel()
+	<-r.done
+	return nil
+}
+
+// replay replays the files, again and again if the replay loops, until ctx is done.
+func (r *fileReplayReceiver) replay(ctx context.Context, u *unmarshaller) {
+	defer close(r.done)
+	p := newPacer(r.cfg.Replay)
+	for {
+		files, err := listFiles(r.cfg.Include, r.cfg.Exclude)
+		if err != nil {
+			r.settings.Logger.Error("Failed to list the files to replay", zap.Error(err))
+			return
+		}
+		if len(files) == 0 {
+			r.settings.Logger.Warn("No files to replay", zap.Strings("include", r.cfg.Include))
+		}
+		if err = r.replayFiles(ctx, u, p, files); err != nil {
+			return
+		}
+		if !r.cfg.Replay.Loop {
+			r.settings.Logger.Info("Replay complete", zap.Int("files", len(files)))
+			return
+		}
+		p.reset()
+		if len(files) == 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(noFilesInterval):
+			}
+		}
+	}
+}
+
+func (r *fileReplayReceiver) format() string {
+	if r.cfg.Encoding != nil {
+		return r.cfg.Encoding.String()
+	}
+	return r.cfg.FormatType
+}
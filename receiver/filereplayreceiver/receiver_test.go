@@ -0,0 +1,367 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package filereplayreceiver
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/filereplayreceiver/internal/metadata"
+)
+
+var baseTime = time.Date(2024, 0o1, 10, 10, 30, 40, 0, time.UTC)
+
+func generateLogs(service string, offset time.Duration) plog.Logs {
+	ld := plog.NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().PutStr("service.name", service)
+	lr := rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	lr.SetTimestamp(pcommon.NewTimestampFromTime(baseTime.Add(offset)))
+	lr.Body().SetStr("hello")
+	return ld
+}
+
+func generateTraces(offset time.Duration) ptrace.Traces {
+	td := ptrace.NewTraces()
+	span := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	span.SetName("operation")
+	span.SetStartTimestamp(pcommon.NewTimestampFromTime(baseTime.Add(offset)))
+	span.SetEndTimestamp(pcommon.NewTimestampFromTime(baseTime.Add(offset + time.Second)))
+	return td
+}
+
+func generateMetrics(offset time.Duration) pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	m := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	m.SetName("requests")
+	dp := m.SetEmptySum().DataPoints().AppendEmpty()
+	dp.SetTimestamp(pcommon.NewTimestampFromTime(baseTime.Add(offset)))
+	dp.SetIntValue(1)
+	return md
+}
+
+func writeFile(t *testing.T, path string, data []byte) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+}
+
+// waitReplayed waits for the receiver to replay all the files.
+func waitReplayed(t *testing.T, r component.Component) {
+	select {
+	case <-r.(*fileReplayReceiver).done:
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "the files were not replayed")
+	}
+}
+
+func newConfig(include string) *Config {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Include = []string{include}
+	return cfg
+}
+
+func TestReplayLogsJSON(t *testing.T) {
+	dir := t.TempDir()
+	marshaler := &plog.JSONMarshaler{}
+	first, err := marshaler.MarshalLogs(generateLogs("a", 0))
+	require.NoError(t, err)
+	second, err := marshaler.MarshalLogs(generateLogs("b", time.Second))
+	require.NoError(t, err)
+	// The group_by directories of the file exporter.
+	writeFile(t, filepath.Join(dir, "a", "logs.json"), writeLines(first))
+	writeFile(t, filepath.Join(dir, "b", "logs.json"), writeLines(second, []byte("invalid")))
+
+	sink := new(consumertest.LogsSink)
+	r, err := NewFactory().CreateLogs(context.Background(), receivertest.NewNopSettings(metadata.Type), newConfig(filepath.Join(dir, "**", "*.json")), sink)
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, r.Shutdown(context.Background())) })
+
+	require.Eventually(t, func() bool {
+		return sink.LogRecordCount() == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	logs := sink.AllLogs()
+	require.Len(t, logs, 2)
+	assert.Equal(t, generateLogs("a", 0), logs[0])
+	assert.Equal(t, generateLogs("b", time.Second), logs[1])
+}
+
+func TestReplayTracesProtoCompressed(t *testing.T) {
+	dir := t.TempDir()
+	marshaler := &ptrace.ProtoMarshaler{}
+	var messages [][]byte
+	for i := 0; i < 3; i++ {
+		buf, err := marshaler.MarshalTraces(generateTraces(time.Duration(i) * time.Second))
+		require.NoError(t, err)
+		messages = append(messages, buf)
+	}
+	writeFile(t, filepath.Join(dir, "traces.proto"), writeBuffers(true, messages...))
+
+	cfg := newConfig(filepath.Join(dir, "*.proto"))
+	cfg.FormatType = formatTypeProto
+	cfg.Compression = compressionZSTD
+	sink := new(consumertest.TracesSink)
+	r, err := NewFactory().CreateTraces(context.Background(), receivertest.NewNopSettings(metadata.Type), cfg, sink)
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, r.Shutdown(context.Background())) })
+
+	require.Eventually(t, func() bool {
+		return sink.SpanCount() == 3
+	}, 5*time.Second, 10*time.Millisecond)
+	for i, td := range sink.AllTraces() {
+		assert.Equal(t, generateTraces(time.Duration(i)*time.Second), td)
+	}
+}
+
+func TestReplayMetricsProto(t *testing.T) {
+	dir := t.TempDir()
+	marshaler := &pmetric.ProtoMarshaler{}
+	buf, err := marshaler.MarshalMetrics(generateMetrics(0))
+	require.NoError(t, err)
+	writeFile(t, filepath.Join(dir, "metrics.proto"), writeBuffers(false, buf))
+
+	cfg := newConfig(filepath.Join(dir, "*.proto"))
+	cfg.FormatType = formatTypeProto
+	sink := new(consumertest.MetricsSink)
+	r, err := NewFactory().CreateMetrics(context.Background(), receivertest.NewNopSettings(metadata.Type), cfg, sink)
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, r.Shutdown(context.Background())) })
+
+	require.Eventually(t, func() bool {
+		return sink.DataPointCount() == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, generateMetrics(0), sink.AllMetrics()[0])
+}
+
+func TestReplayPacing(t *testing.T) {
+	dir := t.TempDir()
+	marshaler := &plog.JSONMarshaler{}
+	first, err := marshaler.MarshalLogs(generateLogs("a", 0))
+	require.NoError(t, err)
+	second, err := marshaler.MarshalLogs(generateLogs("a", 400*time.Millisecond))
+	require.NoError(t, err)
+	writeFile(t, filepath.Join(dir, "logs.json"), writeLines(first, second))
+
+	cfg := newConfig(filepath.Join(dir, "*.json"))
+	cfg.Replay.Pacing = pacingTimestamps
+	cfg.Replay.Speed = 2
+	sink := new(consumertest.LogsSink)
+	r, err := NewFactory().CreateLogs(context.Background(), receivertest.NewNopSettings(metadata.Type), cfg, sink)
+	require.NoError(t, err)
+	start := time.Now()
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, r.Shutdown(context.Background())) })
+
+	require.Eventually(t, func() bool {
+		return sink.LogRecordCount() == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	// The second batch is replayed 400ms after the first one, twice as fast.
+	assert.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+}
+
+func TestReplayPacingFiles(t *testing.T) {
+	dir := t.TempDir()
+	marshaler := &plog.JSONMarshaler{}
+	marshal := func(service string, offset time.Duration) []byte {
+		buf, err := marshaler.MarshalLogs(generateLogs(service, offset))
+		require.NoError(t, err)
+		return buf
+	}
+	// The group_by directories of the file exporter, written at the same time.
+	writeFile(t, filepath.Join(dir, "a", "logs.json"), writeLines(marshal("a", 0), marshal("a", 400*time.Millisecond)))
+	writeFile(t, filepath.Join(dir, "b", "logs.json"), writeLines(marshal("b", 200*time.Millisecond), marshal("b", 600*time.Millisecond)))
+
+	cfg := newConfig(filepath.Join(dir, "**", "*.json"))
+	cfg.Replay.Pacing = pacingTimestamps
+	cfg.Replay.Speed = 2
+	sink := new(consumertest.LogsSink)
+	r, err := NewFactory().CreateLogs(context.Background(), receivertest.NewNopSettings(metadata.Type), cfg, sink)
+	require.NoError(t, err)
+	start := time.Now()
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, r.Shutdown(context.Background())) })
+
+	waitReplayed(t, r)
+	// The files are replayed together, in timestamp order, over 600ms twice as fast.
+	assert.GreaterOrEqual(t, time.Since(start), 300*time.Millisecond)
+	logs := sink.AllLogs()
+	require.Len(t, logs, 4)
+	for i, expected := range []plog.Logs{
+		generateLogs("a", 0),
+		generateLogs("b", 200*time.Millisecond),
+		generateLogs("a", 400*time.Millisecond),
+		generateLogs("b", 600*time.Millisecond),
+	} {
+		assert.Equal(t, expected, logs[i])
+	}
+}
+
+func TestReplayMaxOpenFiles(t *testing.T) {
+	dir := t.TempDir()
+	marshaler := &plog.JSONMarshaler{}
+	marshal := func(service string, offset time.Duration) []byte {
+		buf, err := marshaler.MarshalLogs(generateLogs(service, offset))
+		require.NoError(t, err)
+		return buf
+	}
+	for i, service := range []string{"a", "b", "c"} {
+		offset := time.Duration(i) * time.Second
+		writeFile(t, filepath.Join(dir, service, "logs.json"), writeLines(marshal(service, offset), marshal(service, offset+3*time.Second)))
+	}
+
+	cfg := newConfig(filepath.Join(dir, "**", "*.json"))
+	cfg.Replay.MaxOpenFiles = 2
+	sink := new(consumertest.LogsSink)
+	r, err := NewFactory().CreateLogs(context.Background(), receivertest.NewNopSettings(metadata.Type), cfg, sink)
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, r.Shutdown(context.Background())) })
+
+	waitReplayed(t, r)
+	logs := sink.AllLogs()
+	require.Len(t, logs, 6)
+	// The first two files are merged, then the third one is replayed.
+	for i, expected := range []plog.Logs{
+		generateLogs("a", 0),
+		generateLogs("b", time.Second),
+		generateLogs("a", 3*time.Second),
+		generateLogs("b", 4*time.Second),
+		generateLogs("c", 2*time.Second),
+		generateLogs("c", 5*time.Second),
+	} {
+		assert.Equal(t, expected, logs[i])
+	}
+}
+
+func TestReplayDecodeErrors(t *testing.T) {
+	dir := t.TempDir()
+	buf, err := (&plog.JSONMarshaler{}).MarshalLogs(generateLogs("a", 0))
+	require.NoError(t, err)
+	writeFile(t, filepath.Join(dir, "logs.json"), writeLines([]byte("invalid"), buf, []byte("invalid")))
+
+	core, observed := observer.New(zap.WarnLevel)
+	settings := receivertest.NewNopSettings(metadata.Type)
+	settings.Logger = zap.New(core)
+	sink := new(consumertest.LogsSink)
+	r, err := NewFactory().CreateLogs(context.Background(), settings, newConfig(filepath.Join(dir, "*.json")), sink)
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	waitReplayed(t, r)
+	require.NoError(t, r.Shutdown(context.Background()))
+	assert.Equal(t, 1, sink.LogRecordCount())
+
+	// The first error of the file is logged, and the number of messages that couldn't be decoded.
+	entries := observed.All()
+	require.Len(t, entries, 2)
+	assert.Equal(t, "Failed to decode message", entries[0].Message)
+	assert.Equal(t, filepath.Join(dir, "logs.json"), entries[0].ContextMap()["file"])
+	assert.Equal(t, "Failed to decode messages", entries[1].Message)
+	assert.Equal(t, int64(2), entries[1].ContextMap()["messages"])
+}
+
+func TestReplayLoop(t *testing.T) {
+	dir := t.TempDir()
+	buf, err := (&plog.JSONMarshaler{}).MarshalLogs(generateLogs("a", 0))
+	require.NoError(t, err)
+	writeFile(t, filepath.Join(dir, "logs.json"), writeLines(buf))
+
+	cfg := newConfig(filepath.Join(dir, "*.json"))
+	cfg.Replay.Loop = true
+	sink := new(consumertest.LogsSink)
+	r, err := NewFactory().CreateLogs(context.Background(), receivertest.NewNopSettings(metadata.Type), cfg, sink)
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+
+	require.Eventually(t, func() bool {
+		return sink.LogRecordCount() >= 3
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, r.Shutdown(context.Background()))
+}
+
+func TestReplayOtherSignal(t *testing.T) {
+	dir := t.TempDir()
+	buf, err := (&plog.JSONMarshaler{}).MarshalLogs(generateLogs("a", 0))
+	require.NoError(t, err)
+	writeFile(t, filepath.Join(dir, "logs.json"), writeLines(buf))
+
+	sink := new(consumertest.TracesSink)
+	r, err := NewFactory().CreateTraces(context.Background(), receivertest.NewNopSettings(metadata.Type), newConfig(filepath.Join(dir, "*.json")), sink)
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	waitReplayed(t, r)
+	require.NoError(t, r.Shutdown(context.Background()))
+	assert.Zero(t, sink.SpanCount())
+}
+
+type logsEncoding struct {
+	component.StartFunc
+	component.ShutdownFunc
+	plog.JSONUnmarshaler
+}
+
+type hostWithEncoding struct {
+	encodings map[component.ID]component.Component
+}
+
+func (h hostWithEncoding) GetExtensions() map[component.ID]component.Component {
+	return h.encodings
+}
+
+func TestReplayEncoding(t *testing.T) {
+	dir := t.TempDir()
+	buf, err := (&plog.JSONMarshaler{}).MarshalLogs(generateLogs("a", 0))
+	require.NoError(t, err)
+	writeFile(t, filepath.Join(dir, "logs.json"), writeLines(buf))
+
+	id := component.MustNewID("otlpjson")
+	host := hostWithEncoding{map[component.ID]component.Component{id: &logsEncoding{}}}
+	cfg := newConfig(filepath.Join(dir, "*.json"))
+	cfg.Encoding = &id
+
+	sink := new(consumertest.LogsSink)
+	r, err := NewFactory().CreateLogs(context.Background(), receivertest.NewNopSettings(metadata.Type), cfg, sink)
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), host))
+	t.Cleanup(func() { require.NoError(t, r.Shutdown(context.Background())) })
+	require.Eventually(t, func() bool {
+		return sink.LogRecordCount() == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// The encoding doesn't support traces, nothing is replayed.
+	tracesSink := new(consumertest.TracesSink)
+	tr, err := NewFactory().CreateTraces(context.Background(), receivertest.NewNopSettings(metadata.Type), cfg, tracesSink)
+	require.NoError(t, err)
+	require.NoError(t, tr.Start(context.Background(), host))
+	waitReplayed(t, tr)
+	require.NoError(t, tr.Shutdown(context.Background()))
+	assert.Zero(t, tracesSink.SpanCount())
+}
+
+func TestReplayUnknownEncoding(t *testing.T) {
+	cfg := newConfig("*.json")
+	id := component.MustNewID("unknown")
+	cfg.Encoding = &id
+	r, err := NewFactory().CreateLogs(context.Background(), receivertest.NewNopSettings(metadata.Type), cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	assert.EqualError(t, r.Start(context.Background(), componenttest.NewNopHost()), `unknown encoding "unknown"`)
+	require.NoError(t, r.Shutdown(context.Background()))
+}
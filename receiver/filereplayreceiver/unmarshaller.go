@@ -0,0 +1,80 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package filereplayreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/filereplayreceiver"
+
+import (
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// Unmarshaler configuration used for unmarshaling the telemetry written by the file exporter
+var tracesUnmarshalers = map[string]ptrace.Unmarshaler{
+	formatTypeJSON:  &ptrace.JSONUnmarshaler{},
+	formatTypeProto: &ptrace.ProtoUnmarshaler{},
+}
+
+var metricsUnmarshalers = map[string]pmetric.Unmarshaler{
+	formatTypeJSON:  &pmetric.JSONUnmarshaler{},
+	formatTypeProto: &pmetric.ProtoUnmarshaler{},
+}
+
+var logsUnmarshalers = map[string]plog.Unmarshaler{
+	formatTypeJSON:  &plog.JSONUnmarshaler{},
+	formatTypeProto: &plog.ProtoUnmarshaler{},
+}
+
+type unmarshaller struct {
+	tracesUnmarshaler  ptrace.Unmarshaler
+	metricsUnmarshaler pmetric.Unmarshaler
+	logsUnmarshaler    plog.Unmarshaler
+}
+
+func newUnmarshaller(conf *Config, host component.Host) (*unmarshaller, error) {
+	if conf.Encoding != nil {
+		encoding := host.GetExtensions()[*conf.Encoding]
+		if encoding == nil {
+			return nil, fmt.Errorf("unknown encoding %q", conf.Encoding)
+		}
+		// cast with ok to avoid panics.
+		tu, _ := encoding.(ptrace.Unmarshaler)
+		mu, _ := encoding.(pmetric.Unmarshaler)
+		lu, _ := encoding.(plog.Unmarshaler)
+		return &unmarshaller{
+			tracesUnmarshaler:  tu,
+			metricsUnmarshaler: mu,
+			logsUnmarshaler:    lu,
+		}, nil
+	}
+	return &unmarshaller{
+		tracesUnmarshaler:  tracesUnmarshalers[conf.FormatType],
+		metricsUnmarshaler: metricsUnmarshalers[conf.FormatType],
+		logsUnmarshaler:    logsUnmarshalers[conf.FormatType],
+	}, nil
+}
+
+func (u *unmarshaller) unmarshalTraces(buf []byte) (ptrace.Traces, error) {
+	if u.tracesUnmarshaler == nil {
+		return ptrace.Traces{}, errors.New("traces are not supported by encoding")
+	}
+	return u.tracesUnmarshaler.UnmarshalTraces(buf)
+}
+
+func (u *unmarshaller) unmarshalMetrics(buf []byte) (pmetric.Metrics, error) {
+	if u.metricsUnmarshaler == nil {
+		return pmetric.Metrics{}, errors.New("metrics are not supported by encoding")
+	}
+	return u.metricsUnmarshaler.UnmarshalMetrics(buf)
+}
+
+func (u *unmarshaller) unmarshalLogs(buf []byte) (plog.Logs, error) {
+	if u.logsUnmarshaler == nil {
+		return plog.Logs{}, errors.New("logs are not supported by encoding")
+	}
+	return u.logsUnmarshaler.UnmarshalLogs(buf)
+}
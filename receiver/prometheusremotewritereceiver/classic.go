@@ -0,0 +1,233 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewritereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusremotewritereceiver"
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/value"
+	writev2 "github.com/prometheus/prometheus/prompb/io/prometheus/write/v2"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+const (
+	bucketSuffix = "_bucket"
+	sumSuffix    = "_sum"
+	countSuffix  = "_count"
+
+	leLabel       = "le"
+	quantileLabel = "quantile"
+)
+
+// classicBaseName returns the name of the metric a series of a classic histogram or summary belongs to. The buckets
+// of a histogram are sent as <name>_bucket series with a le label, the quantiles of a summary as <name> series with a
+// quantile label, and both send their sum and count as <name>_sum and <name>_count series.
+func classicBaseName(name string, ls labels.Labels, metricType writev2.Metadata_MetricType) string {
+	switch {
+	case metricType == writev2.Metadata_METRIC_TYPE_HISTOGRAM && ls.Has(leLabel):
+		return strings.TrimSuffix(name, bucketSuffix)
+	case metricType == writev2.Metadata_METRIC_TYPE_SUMMARY && ls.Has(quantileLabel):
+		return name
+	case strings.HasSuffix(name, sumSuffix):
+		return strings.TrimSuffix(name, sumSuffix)
+	default:
+		return strings.TrimSuffix(name, countSuffix)
+	}
+}
+
+// classicKey identifies a datapoint of a classic histogram or summary: the series of the metric with the same labels,
+// apart from the metric name and the le or quantile label, and samples with the same timestamp.
+type classicKey struct {
+	metric    uint64
+	labels    uint64
+	timestamp int64
+}
+
+type classicBucket struct {
+	bound float64
+	count float64
+}
+
+// classicHistogram is a histogram datapoint being assembled from the series of a request.
+type classicHistogram struct {
+	name      string
+	dp        pmetric.HistogramDataPoint
+	buckets   []classicBucket
+	hasInf    bool
+	inf       float64
+	exemplars []writev2.Exemplar
+}
+
+// classicDatapoints assembles the datapoints of the classic histograms and summaries of a request. The buckets of a
+// histogram are cumulative and their series may be sent in any order, so the datapoints of the histograms are only
+// complete once all the series of the request were added.
+type classicDatapoints struct {
+	symbols    []string
+	histograms map[classicKey]*classicHistogram
+	// histogramKeys keeps the order in which the histogram datapoints were created, for the errors to be deterministic.
+	histogramKeys []classicKey
+	summaries     map[classicKey]pmetric.SummaryDataPoint
+}
+
+func newClassicDatapoints(symbols []string) *classicDatapoints {
+	return &classicDatapoints{
+		symbols:    symbols,
+		histograms: make(map[classicKey]*classicHistogram),
+		summaries:  make(map[classicKey]pmetric.SummaryDataPoint),
+	}
+}
+
+// addHistogramSeries adds the samples of a _bucket, _sum or _count series to the datapoints of the histogram.
+func (c *classicDatapoints) addHistogramSeries(datapoints pmetric.HistogramDataPointSlice, metricKey uint64, ls labels.Labels, ts writev2.TimeSeries) error {
+	name := ls.Get(labels.MetricName)
+	var bound float64
+	isBucket := ls.Has(leLabel)
+	if isBucket {
+		var err error
+		if bound, err = strconv.ParseFloat(ls.Get(leLabel), 64); err != nil {
+			return fmt.Errorf("invalid le label %q in series %q", ls.Get(leLabel), name)
+		}
+	}
+
+	labelsHash, _ := ls.HashWithoutLabels(nil, leLabel)
+	var last *classicHistogram
+	for _, sample := range ts.Samples {
+		key := classicKey{metric: metricKey, labels: labelsHash, timestamp: sample.Timestamp}
+		h, ok := c.histograms[key]
+		if !ok {
+			h = &classicHistogram{name: classicBaseName(name, ls, writev2.Metadata_METRIC_TYPE_HISTOGRAM), dp: datapoints.AppendEmpty()}
+			h.dp.SetStartTimestamp(pcommon.Timestamp(ts.CreatedTimestamp * int64(time.Millisecond)))
+			h.dp.SetTimestamp(pcommon.Timestamp(sample.Timestamp * int64(time.Millisecond)))
+			addDatapointAttributes(h.dp.Attributes(), labels.NewBuilder(ls).Del(leLabel).Labels())
+			c.histograms[key] = h
+			c.histogramKeys = append(c.histogramKeys, key)
+		}
+		last = h
+
+		if value.IsStaleNaN(sample.Value) {
+			h.dp.SetFlags(pmetric.DefaultDataPointFlags.WithNoRecordedValue(true))
+			continue
+		}
+		switch {
+		case isBucket && math.IsInf(bound, 1):
+			h.hasInf = true
+			h.inf = sample.Value
+		case isBucket:
+			h.buckets = append(h.buckets, classicBucket{bound: bound, count: sample.Value})
+		case strings.HasSuffix(name, sumSuffix):
+			h.dp.SetSum(sample.Value)
+		default:
+			h.dp.SetCount(uint64(math.Round(sample.Value)))
+		}
+	}
+
+	// The exemplars of a time series are not linked to a specific sample, so they are attached to the datapoint
+	// of the last sample of the time series.
+	if last != nil {
+		last.exemplars = append(last.exemplars, ts.Exemplars...)
+	}
+	return nil
+}
+
+// addSummarySeries adds the samples of a quantile, _sum or _count series to the datapoints of the summary.
+func (c *classicDatapoints) addSummarySeries(datapoints pmetric.SummaryDataPointSlice, metricKey uint64, ls labels.Labels, ts writev2.TimeSeries) error {
+	name := ls.Get(labels.MetricName)
+	var quantile float64
+	isQuantile := ls.Has(quantileLabel)
+	if isQuantile {
+		var err error
+		if quantile, err = strconv.ParseFloat(ls.Get(quantileLabel), 64); err != nil {
+			return fmt.Errorf("invalid quantile label %q in series %q", ls.Get(quantileLabel), name)
+		}
+	}
+
+	labelsHash, _ := ls.HashWithoutLabels(nil, quantileLabel)
+	for _, sample := range ts.Samples {
+		key := classicKey{metric: metricKey, labels: labelsHash, timestamp: sample.Timestamp}
+		dp, ok := c.summaries[key]
+		if !ok {
+			dp = datapoints.AppendEmpty()
+			dp.SetStartTimestamp(pcommon.Timestamp(ts.CreatedTimestamp * int64(time.Millisecond)))
+			dp.SetTimestamp(pcommon.Timestamp(sample.Timestamp * int64(time.Millisecond)))
+			addDatapointAttributes(dp.Attributes(), labels.NewBuilder(ls).Del(quantileLabel).Labels())
+			c.summaries[key] = dp
+		}
+
+		if value.IsStaleNaN(sample.Value) {
+			dp.SetFlags(pmetric.DefaultDataPointFlags.WithNoRecordedValue(true))
+			continue
+		}
+		switch {
+		case isQuantile:
+			qv := dp.QuantileValues().AppendEmpty()
+			qv.SetQuantile(quantile)
+			qv.SetValue(sample.Value)
+		case strings.HasSuffix(name, sumSuffix):
+			dp.SetSum(sample.Value)
+		default:
+			dp.SetCount(uint64(math.Round(sample.Value)))
+		}
+	}
+	return nil
+}
+
+// finish completes the datapoints once all the series of the request were added.
+func (c *classicDatapoints) finish() error {
+	var errs error
+	for _, key := range c.histogramKeys {
+		errs = errors.Join(errs, c.histograms[key].finish(c.symbols))
+	}
+	for _, dp := range c.summaries {
+		dp.QuantileValues().Sort(func(a, b pmetric.SummaryDataPointValueAtQuantile) bool {
+			return a.Quantile() < b.Quantile()
+		})
+	}
+	return errs
+}
+
+// finish sets the explicit bounds and the bucket counts of the datapoint from the cumulative buckets, and adds the
+// exemplars of its bucket series in timestamp order.
+func (h *classicHistogram) finish(symbols []string) error {
+	sort.SliceStable(h.exemplars, func(i, j int) bool {
+		return h.exemplars[i].Timestamp < h.exemplars[j].Timestamp
+	})
+	if err := addExemplars(h.dp.Exemplars(), h.exemplars, symbols); err != nil {
+		return err
+	}
+	if h.dp.Flags().NoRecordedValue() {
+		return nil
+	}
+	sort.Slice(h.buckets, func(i, j int) bool {
+		return h.buckets[i].bound < h.buckets[j].bound
+	})
+	// The +Inf bucket holds all the observations, which is the count of the datapoint when the bucket is missing.
+	if !h.hasInf {
+		h.inf = float64(h.dp.Count())
+	}
+
+	bounds := make([]float64, 0, len(h.buckets))
+	counts := make([]uint64, 0, len(h.buckets)+1)
+	var previous float64
+	for _, b := range append(h.buckets, classicBucket{bound: math.Inf(1), count: h.inf}) {
+		if b.count < previous {
+			return fmt.Errorf("bucket counts of histogram %q are not cumulative", h.name)
+		}
+		if !math.IsInf(b.bound, 1) {
+			bounds = append(bounds, b.bound)
+		}
+		counts = append(counts, uint64(math.Round(b.count-previous)))
+		previous = b.count
+	}
+	h.dp.ExplicitBounds().FromRaw(bounds)
+	h.dp.BucketCounts().FromRaw(counts)
+	return nil
+}
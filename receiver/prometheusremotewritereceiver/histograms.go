@@ -0,0 +1,195 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewritereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusremotewritereceiver"
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/value"
+	writev2 "github.com/prometheus/prometheus/prompb/io/prometheus/write/v2"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+const (
+	// Exponential native histograms use schemas between -4 and 8, the other schemas,
+	// such as the custom buckets schema (-53), can't be represented as exponential histograms.
+	minExponentialSchema = -4
+	maxExponentialSchema = 8
+
+	// maxNativeHistogramBuckets limits the number of dense buckets created from the sparse
+	// buckets of a native histogram, so that a single span can't allocate unbounded memory.
+	maxNativeHistogramBuckets = 1 << 16
+
+	exemplarTraceIDKey = "trace_id"
+	exemplarSpanIDKey  = "span_id"
+)
+
+// addExponentialHistogramDatapoints translates the native histograms of the time series to exponential histogram datapoints.
+func addExponentialHistogramDatapoints(datapoints pmetric.ExponentialHistogramDataPointSlice, ls labels.Labels, ts writev2.TimeSeries, symbols []string) error {
+	var errs error
+	added := 0
+	for i := range ts.Histograms {
+		h := &ts.Histograms[i]
+		if h.Schema < minExponentialSchema || h.Schema > maxExponentialSchema {
+			errs = errors.Join(errs, fmt.Errorf("unsupported native histogram schema %d", h.Schema))
+			continue
+		}
+
+		_, isFloat := h.Count.(*writev2.Histogram_CountFloat)
+		positiveOffset, positive, err := convertNativeBuckets(h.PositiveSpans, h.PositiveDeltas, h.PositiveCounts, isFloat)
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("invalid positive buckets: %w", err))
+			continue
+		}
+		negativeOffset, negative, err := convertNativeBuckets(h.NegativeSpans, h.NegativeDeltas, h.NegativeCounts, isFloat)
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("invalid negative buckets: %w", err))
+			continue
+		}
+
+		dp := datapoints.AppendEmpty()
+		added++
+		dp.SetStartTimestamp(pcommon.Timestamp(ts.CreatedTimestamp * int64(time.Millisecond)))
+		dp.SetTimestamp(pcommon.Timestamp(h.Timestamp * int64(time.Millisecond)))
+		dp.SetScale(h.Schema)
+		dp.SetZeroThreshold(h.ZeroThreshold)
+		addDatapointAttributes(dp.Attributes(), ls)
+
+		// Stale markers are sent as histograms whose sum is the stale NaN.
+		if value.IsStaleNaN(h.Sum) {
+			dp.SetFlags(pmetric.DefaultDataPointFlags.WithNoRecordedValue(true))
+			continue
+		}
+
+		dp.SetSum(h.Sum)
+		switch count := h.Count.(type) {
+		case *writev2.Histogram_CountInt:
+			dp.SetCount(count.CountInt)
+		case *writev2.Histogram_CountFloat:
+			dp.SetCount(uint64(math.Round(count.CountFloat)))
+		}
+		switch zeroCount := h.ZeroCount.(type) {
+		case *writev2.Histogram_ZeroCountInt:
+			dp.SetZeroCount(zeroCount.ZeroCountInt)
+		case *writev2.Histogram_ZeroCountFloat:
+			dp.SetZeroCount(uint64(math.Round(zeroCount.ZeroCountFloat)))
+		}
+		dp.Positive().SetOffset(positiveOffset)
+		dp.Positive().BucketCounts().FromRaw(positive)
+		dp.Negative().SetOffset(negativeOffset)
+		dp.Negative().BucketCounts().FromRaw(negative)
+	}
+
+	// As for the samples, the exemplars are attached to the last datapoint created from the time series.
+	if len(ts.Exemplars) == 0 || added == 0 {
+		return errs
+	}
+	return errors.Join(errs, addExemplars(datapoints.At(datapoints.Len()-1).Exemplars(), ts.Exemplars, symbols))
+}
+
+// convertNativeBuckets translates the sparse buckets of a native histogram to the dense buckets
+// of an exponential histogram, and returns the offset of the first bucket with the bucket counts.
+//
+// Prometheus bucket index i covers the range (base^(i-1), base^i], while the OTel exponential
+// histogram bucket index i covers the range (base^i, base^(i+1)], so the offset is shifted by one.
+func convertNativeBuckets(spans []writev2.BucketSpan, deltas []int64, counts []float64, isFloat bool) (int32, []uint64, error) {
+	if len(spans) == 0 {
+		return 0, nil, nil
+	}
+
+	var numBuckets, numDense int
+	for i, span := range spans {
+		if i > 0 && span.Offset < 0 {
+			return 0, nil, fmt.Errorf("span %d has a negative offset %d", i, span.Offset)
+		}
+		numBuckets += int(span.Length)
+		numDense += int(span.Length)
+		if i > 0 {
+			numDense += int(span.Offset)
+		}
+	}
+	if numDense > maxNativeHistogramBuckets {
+		return 0, nil, fmt.Errorf("too many buckets: %d, the limit is %d", numDense, maxNativeHistogramBuckets)
+	}
+	if isFloat && numBuckets != len(counts) {
+		return 0, nil, fmt.Errorf("spans define %d buckets, but got %d bucket counts", numBuckets, len(counts))
+	}
+	if !isFloat && numBuckets != len(deltas) {
+		return 0, nil, fmt.Errorf("spans define %d buckets, but got %d bucket deltas", numBuckets, len(deltas))
+	}
+
+	var (
+		bucketCounts = make([]uint64, 0, numDense)
+		idx          int
+		count        int64
+	)
+	for i, span := range spans {
+		if i > 0 {
+			// The offset of the following spans is the gap to the end of the previous span.
+			for j := int32(0); j < span.Offset; j++ {
+				bucketCounts = append(bucketCounts, 0)
+			}
+		}
+		for j := uint32(0); j < span.Length; j++ {
+			if isFloat {
+				bucketCounts = append(bucketCounts, uint64(math.Round(counts[idx])))
+			} else {
+				count += deltas[idx]
+				if count < 0 {
+					return 0, nil, fmt.Errorf("bucket %d has a negative count %d", idx, count)
+				}
+				bucketCounts = append(bucketCounts, uint64(count))
+			}
+			idx++
+		}
+	}
+	return spans[0].Offset - 1, bucketCounts, nil
+}
+
+// addExemplars translates the exemplars of a time series to OTel exemplars. The trace_id and span_id labels
+// are used as the trace and span IDs of the exemplar, the other labels become filtered attributes.
+func addExemplars(dest pmetric.ExemplarSlice, exemplars []writev2.Exemplar, symbols []string) error {
+	for _, e := range exemplars {
+		if len(e.LabelsRefs)%2 != 0 {
+			return fmt.Errorf("exemplar has an odd number of label refs: %d", len(e.LabelsRefs))
+		}
+		for _, ref := range e.LabelsRefs {
+			if ref >= uint32(len(symbols)) {
+				return fmt.Errorf("exemplar label ref %d is out of bounds of symbolsTable", ref)
+			}
+		}
+
+		exemplar := dest.AppendEmpty()
+		exemplar.SetTimestamp(pcommon.Timestamp(e.Timestamp * int64(time.Millisecond)))
+		exemplar.SetDoubleValue(e.Value)
+		for i := 0; i < len(e.LabelsRefs); i += 2 {
+			name, val := symbols[e.LabelsRefs[i]], symbols[e.LabelsRefs[i+1]]
+			switch name {
+			case exemplarTraceIDKey:
+				var traceID pcommon.TraceID
+				if b, err := hex.DecodeString(val); err == nil && len(b) == len(traceID) {
+					copy(traceID[:], b)
+					exemplar.SetTraceID(traceID)
+					continue
+				}
+			case exemplarSpanIDKey:
+				var spanID pcommon.SpanID
+				if b, err := hex.DecodeString(val); err == nil && len(b) == len(spanID) {
+					copy(spanID[:], b)
+					exemplar.SetSpanID(spanID)
+					continue
+				}
+			}
+			// Labels that can't be translated to IDs are kept as attributes.
+			exemplar.FilteredAttributes().PutStr(name, val)
+		}
+	}
+	return nil
+}
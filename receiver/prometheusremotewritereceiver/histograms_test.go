@@ -0,0 +1,200 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewritereceiver
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/value"
+	writev2 "github.com/prometheus/prometheus/prompb/io/prometheus/write/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+func TestConvertNativeBuckets(t *testing.T) {
+	for _, tc := range []struct {
+		name           string
+		spans          []writev2.BucketSpan
+		deltas         []int64
+		counts         []float64
+		isFloat        bool
+		expectedOffset int32
+		expectedCounts []uint64
+		expectError    string
+	}{
+		{
+			name: "no buckets",
+		},
+		{
+			name:           "single span",
+			spans:          []writev2.BucketSpan{{Offset: 2, Length: 3}},
+			deltas:         []int64{4, -2, 1},
+			expectedOffset: 1,
+			expectedCounts: []uint64{4, 2, 3},
+		},
+		{
+			name:           "gap between spans",
+			spans:          []writev2.BucketSpan{{Offset: -1, Length: 1}, {Offset: 2, Length: 1}},
+			deltas:         []int64{1, 2},
+			expectedOffset: -2,
+			expectedCounts: []uint64{1, 0, 0, 3},
+		},
+		{
+			name:           "float buckets",
+			spans:          []writev2.BucketSpan{{Offset: 0, Length: 2}},
+			counts:         []float64{1, 2.4},
+			isFloat:        true,
+			expectedOffset: -1,
+			expectedCounts: []uint64{1, 2},
+		},
+		{
+			name:        "less deltas than buckets",
+			spans:       []writev2.BucketSpan{{Offset: 0, Length: 2}},
+			deltas:      []int64{1},
+			expectError: "spans define 2 buckets, but got 1 bucket deltas",
+		},
+		{
+			name:        "less counts than buckets",
+			spans:       []writev2.BucketSpan{{Offset: 0, Length: 2}},
+			counts:      []float64{1},
+			isFloat:     true,
+			expectError: "spans define 2 buckets, but got 1 bucket counts",
+		},
+		{
+			name:        "negative offset",
+			spans:       []writev2.BucketSpan{{Offset: 0, Length: 1}, {Offset: -1, Length: 1}},
+			deltas:      []int64{1, 1},
+			expectError: "span 1 has a negative offset -1",
+		},
+		{
+			name:        "negative count",
+			spans:       []writev2.BucketSpan{{Offset: 0, Length: 2}},
+			deltas:      []int64{1, -2},
+			expectError: "bucket 1 has a negative count -1",
+		},
+		{
+			name:        "too many buckets",
+			spans:       []writev2.BucketSpan{{Offset: 0, Length: 1}, {Offset: math.MaxInt32, Length: 1}},
+			deltas:      []int64{1, 1},
+			expectError: "too many buckets",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			offset, counts, err := convertNativeBuckets(tc.spans, tc.deltas, tc.counts, tc.isFloat)
+			if tc.expectError != "" {
+				assert.ErrorContains(t, err, tc.expectError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedOffset, offset)
+			assert.Equal(t, tc.expectedCounts, counts)
+		})
+	}
+}
+
+func TestAddExponentialHistogramDatapoints(t *testing.T) {
+	ls := labels.FromStrings(labels.MetricName, "test_hist", "job", "test", "method", "GET")
+
+	t.Run("float histogram", func(t *testing.T) {
+		datapoints := pmetric.NewExponentialHistogramDataPointSlice()
+		ts := writev2.TimeSeries{
+			CreatedTimestamp: 1,
+			Histograms: []writev2.Histogram{
+				{
+					Count:          &writev2.Histogram_CountFloat{CountFloat: 4},
+					Sum:            3,
+					Schema:         -1,
+					ZeroCount:      &writev2.Histogram_ZeroCountFloat{ZeroCountFloat: 1},
+					NegativeSpans:  []writev2.BucketSpan{{Offset: 1, Length: 1}},
+					NegativeCounts: []float64{3},
+					Timestamp:      2,
+				},
+			},
+		}
+		require.NoError(t, addExponentialHistogramDatapoints(datapoints, ls, ts, []string{""}))
+		require.Equal(t, 1, datapoints.Len())
+
+		dp := datapoints.At(0)
+		assert.Equal(t, pcommon.Timestamp(1*int64(time.Millisecond)), dp.StartTimestamp())
+		assert.Equal(t, pcommon.Timestamp(2*int64(time.Millisecond)), dp.Timestamp())
+		assert.Equal(t, int32(-1), dp.Scale())
+		assert.Equal(t, uint64(4), dp.Count())
+		assert.Equal(t, uint64(1), dp.ZeroCount())
+		assert.Equal(t, 3.0, dp.Sum())
+		assert.Equal(t, int32(0), dp.Negative().Offset())
+		assert.Equal(t, []uint64{3}, dp.Negative().BucketCounts().AsRaw())
+		assert.Equal(t, 0, dp.Positive().BucketCounts().Len())
+		assert.Equal(t, map[string]any{"method": "GET"}, dp.Attributes().AsRaw())
+	})
+
+	t.Run("stale marker", func(t *testing.T) {
+		datapoints := pmetric.NewExponentialHistogramDataPointSlice()
+		ts := writev2.TimeSeries{
+			Histograms: []writev2.Histogram{
+				{
+					Count:     &writev2.Histogram_CountInt{CountInt: value.StaleNaN},
+					Sum:       math.Float64frombits(value.StaleNaN),
+					Timestamp: 2,
+				},
+			},
+		}
+		require.NoError(t, addExponentialHistogramDatapoints(datapoints, ls, ts, []string{""}))
+		require.Equal(t, 1, datapoints.Len())
+		assert.True(t, datapoints.At(0).Flags().NoRecordedValue())
+		assert.Equal(t, uint64(0), datapoints.At(0).Count())
+	})
+
+	t.Run("invalid histogram is skipped", func(t *testing.T) {
+		datapoints := pmetric.NewExponentialHistogramDataPointSlice()
+		ts := writev2.TimeSeries{
+			Histograms: []writev2.Histogram{
+				{
+					Count:          &writev2.Histogram_CountInt{CountInt: 1},
+					PositiveSpans:  []writev2.BucketSpan{{Offset: 0, Length: 2}},
+					PositiveDeltas: []int64{1},
+					Timestamp:      1,
+				},
+				{
+					Count:     &writev2.Histogram_CountInt{CountInt: 1},
+					ZeroCount: &writev2.Histogram_ZeroCountInt{ZeroCountInt: 1},
+					Timestamp: 2,
+				},
+			},
+		}
+		err := addExponentialHistogramDatapoints(datapoints, ls, ts, []string{""})
+		assert.ErrorContains(t, err, "invalid positive buckets: spans define 2 buckets, but got 1 bucket deltas")
+		require.Equal(t, 1, datapoints.Len())
+		assert.Equal(t, pcommon.Timestamp(2*int64(time.Millisecond)), datapoints.At(0).Timestamp())
+	})
+}
+
+func TestAddExemplars(t *testing.T) {
+	symbols := []string{"", "trace_id", "0102030405060708090a0b0c0d0e0f10", "span_id", "not-a-span-id", "user", "alice"}
+
+	exemplars := pmetric.NewExemplarSlice()
+	require.NoError(t, addExemplars(exemplars, []writev2.Exemplar{
+		{LabelsRefs: []uint32{1, 2, 3, 4, 5, 6}, Value: 1.5, Timestamp: 10},
+		{Value: 2},
+	}, symbols))
+	require.Equal(t, 2, exemplars.Len())
+
+	e := exemplars.At(0)
+	assert.Equal(t, 1.5, e.DoubleValue())
+	assert.Equal(t, pcommon.Timestamp(10*int64(time.Millisecond)), e.Timestamp())
+	assert.Equal(t, pcommon.TraceID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}, e.TraceID())
+	assert.True(t, e.SpanID().IsEmpty())
+	// The span ID can't be decoded, so it is kept as an attribute.
+	assert.Equal(t, map[string]any{"span_id": "not-a-span-id", "user": "alice"}, e.FilteredAttributes().AsRaw())
+
+	assert.Equal(t, 2.0, exemplars.At(1).DoubleValue())
+	assert.True(t, exemplars.At(1).TraceID().IsEmpty())
+
+	err := addExemplars(pmetric.NewExemplarSlice(), []writev2.Exemplar{{LabelsRefs: []uint32{1}}}, symbols)
+	assert.EqualError(t, err, "exemplar has an odd number of label refs: 1")
+}
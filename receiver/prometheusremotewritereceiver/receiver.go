@@ -219,6 +219,10 @@ func (prw *prometheusRemoteWriteReceiver) translateV2(_ context.Context, req *wr
 		stats            = promremote.WriteResponseStats{}
 		// The key is composed by: resource_hash:scope_name:scope_version:metric_name:unit:type
 		metricCache = make(map[uint64]pmetric.Metric)
+		// The resource identity is computed once per job and instance, as a target_info series later in the request
+		// changes the attributes of the resource, which would split the series of a metric across several metrics.
+		resourceIDs = make(map[uint64]string)
+		classic     = newClassicDatapoints(req.Symbols)
 	)
 
 	for _, ts := range req.Timeseries {
@@ -270,6 +274,11 @@ func (prw *prometheusRemoteWriteReceiver) translateV2(_ context.Context, req *wr
 
 		scopeName, scopeVersion := prw.extractScopeInfo(ls)
 		metricName := ls.Get(labels.MetricName)
+		// The series of a classic histogram or summary are assembled into a single metric.
+		if (ts.Metadata.Type == writev2.Metadata_METRIC_TYPE_HISTOGRAM && len(ts.Histograms) == 0) ||
+			ts.Metadata.Type == writev2.Metadata_METRIC_TYPE_SUMMARY {
+			metricName = classicBaseName(metricName, ls, ts.Metadata.Type)
+		}
 		if ts.Metadata.UnitRef >= uint32(len(req.Symbols)) {
 			badRequestErrors = errors.Join(badRequestErrors, fmt.Errorf("unit ref %d is out of bounds of symbolsTable", ts.Metadata.UnitRef))
 			continue
@@ -283,15 +292,19 @@ func (prw *prometheusRemoteWriteReceiver) translateV2(_ context.Context, req *wr
 		unit := req.Symbols[ts.Metadata.UnitRef]
 		description := req.Symbols[ts.Metadata.HelpRef]
 
-		resourceID := identity.OfResource(rm.Resource())
+		resourceID, ok := resourceIDs[hashedLabels]
+		if !ok {
+			resourceID = identity.OfResource(rm.Resource()).String()
+			resourceIDs[hashedLabels] = resourceID
+		}
 
 		metricIdentity := createMetricIdentity(
-			resourceID.String(), // Resource identity
-			scopeName,           // Scope name
-			scopeVersion,        // Scope version
-			metricName,          // Metric name
-			unit,                // Unit
-			ts.Metadata.Type,    // Metric type
+			resourceID,       // Resource identity
+			scopeName,        // Scope name
+			scopeVersion,     // Scope version
+			metricName,       // Metric name
+			unit,             // Unit
+			ts.Metadata.Type, // Metric type
 		)
 
 		metricKey := metricIdentity.Hash()
@@ -328,7 +341,13 @@ func (prw *prometheusRemoteWriteReceiver) translateV2(_ context.Context, req *wr
 				sum.SetIsMonotonic(true)
 				sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
 			case writev2.Metadata_METRIC_TYPE_HISTOGRAM:
-				metric.SetEmptyHistogram()
+				// Native histograms are translated to exponential histograms, classic histograms
+				// are sent as individual _bucket, _sum and _count series.
+				if len(ts.Histograms) > 0 {
+					metric.SetEmptyExponentialHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+				} else {
+					metric.SetEmptyHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+				}
 			case writev2.Metadata_METRIC_TYPE_SUMMARY:
 				metric.SetEmptySummary()
 			}
@@ -345,17 +364,25 @@ func (prw *prometheusRemoteWriteReceiver) translateV2(_ context.Context, req *wr
 		// Otherwise, we append the samples to the existing metric.
 		switch ts.Metadata.Type {
 		case writev2.Metadata_METRIC_TYPE_GAUGE:
-			addNumberDatapoints(metric.Gauge().DataPoints(), ls, ts)
+			badRequestErrors = errors.Join(badRequestErrors, addNumberDatapoints(metric.Gauge().DataPoints(), ls, ts, req.Symbols))
 		case writev2.Metadata_METRIC_TYPE_COUNTER:
-			addNumberDatapoints(metric.Sum().DataPoints(), ls, ts)
+			badRequestErrors = errors.Join(badRequestErrors, addNumberDatapoints(metric.Sum().DataPoints(), ls, ts, req.Symbols))
 		case writev2.Metadata_METRIC_TYPE_HISTOGRAM:
-			addHistogramDatapoints(metric.Histogram().DataPoints(), ls, ts)
+			switch {
+			case len(ts.Histograms) > 0 && metric.Type() == pmetric.MetricTypeExponentialHistogram:
+				badRequestErrors = errors.Join(badRequestErrors, addExponentialHistogramDatapoints(metric.ExponentialHistogram().DataPoints(), ls, ts, req.Symbols))
+			case len(ts.Histograms) == 0 && metric.Type() == pmetric.MetricTypeHistogram:
+				badRequestErrors = errors.Join(badRequestErrors, classic.addHistogramSeries(metric.Histogram().DataPoints(), metricKey, ls, ts))
+			default:
+				badRequestErrors = errors.Join(badRequestErrors, fmt.Errorf("metric %q mixes native and classic histogram samples", metricName))
+			}
 		case writev2.Metadata_METRIC_TYPE_SUMMARY:
-			addSummaryDatapoints(metric.Summary().DataPoints(), ls, ts)
+			badRequestErrors = errors.Join(badRequestErrors, classic.addSummarySeries(metric.Summary().DataPoints(), metricKey, ls, ts))
 		default:
 			badRequestErrors = errors.Join(badRequestErrors, fmt.Errorf("unsupported metric type %q for metric %q", ts.Metadata.Type, metricName))
 		}
 	}
+	badRequestErrors = errors.Join(badRequestErrors, classic.finish())
 
 	return otelMetrics, stats, badRequestErrors
 }
@@ -378,7 +405,7 @@ func parseJobAndInstance(dest pcommon.Map, job, instance string) {
 }
 
 // addNumberDatapoints adds the labels to the datapoints attributes.
-func addNumberDatapoints(datapoints pmetric.NumberDataPointSlice, ls labels.Labels, ts writev2.TimeSeries) {
+func addNumberDatapoints(datapoints pmetric.NumberDataPointSlice, ls labels.Labels, ts writev2.TimeSeries, symbols []string) error {
 	// Add samples from the timeseries
 	for _, sample := range ts.Samples {
 		dp := datapoints.AppendEmpty()
@@ -386,25 +413,27 @@ func addNumberDatapoints(datapoints pmetric.NumberDataPointSlice, ls labels.Labe
 		// Set timestamp in nanoseconds (Prometheus uses milliseconds)
 		dp.SetTimestamp(pcommon.Timestamp(sample.Timestamp * int64(time.Millisecond)))
 		dp.SetDoubleValue(sample.Value)
-
-		attributes := dp.Attributes()
-		for _, l := range ls {
-			if l.Name == "instance" || l.Name == "job" || // Become resource attributes
-				l.Name == labels.MetricName || // Becomes metric name
-				l.Name == "otel_scope_name" || l.Name == "otel_scope_version" { // Becomes scope name and version
-				continue
-			}
-			attributes.PutStr(l.Name, l.Value)
-		}
+		addDatapointAttributes(dp.Attributes(), ls)
 	}
-}
 
-func addSummaryDatapoints(_ pmetric.SummaryDataPointSlice, _ labels.Labels, _ writev2.TimeSeries) {
-	// TODO: Implement this function
+	// The exemplars of a time series are not linked to a specific sample, so they are attached to the last
+	// datapoint created from the time series.
+	if len(ts.Exemplars) == 0 || len(ts.Samples) == 0 {
+		return nil
+	}
+	return addExemplars(datapoints.At(datapoints.Len()-1).Exemplars(), ts.Exemplars, symbols)
 }
 
-func addHistogramDatapoints(_ pmetric.HistogramDataPointSlice, _ labels.Labels, _ writev2.TimeSeries) {
-	// TODO: Implement this function
+// addDatapointAttributes adds the labels that don't describe the resource, scope or metric name to the datapoint attributes.
+func addDatapointAttributes(attributes pcommon.Map, ls labels.Labels) {
+	for _, l := range ls {
+		if l.Name == "instance" || l.Name == "job" || // Become resource attributes
+			l.Name == labels.MetricName || // Becomes metric name
+			l.Name == "otel_scope_name" || l.Name == "otel_scope_version" { // Becomes scope name and version
+			continue
+		}
+		attributes.PutStr(l.Name, l.Value)
+	}
 }
 
 // extractScopeInfo extracts the scope name and version from the labels. If the labels do not contain the scope name/version,
@@ -10,6 +10,8 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"sync"
 	"testing"
 	"time"
@@ -20,6 +22,7 @@ import (
 	writev2 "github.com/prometheus/prometheus/prompb/io/prometheus/write/v2"
 	"github.com/prometheus/prometheus/storage/remote"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/pdata/pcommon"
@@ -27,6 +30,7 @@ import (
 	"go.opentelemetry.io/collector/receiver/receivertest"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/pdatatest/pmetrictest"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusremotewritereceiver/internal/metadata"
 )
 
@@ -468,6 +472,282 @@ func TestTranslateV2(t *testing.T) {
 				dp.SetTimestamp(pcommon.Timestamp(1 * int64(time.Millisecond)))
 				dp.Attributes().PutStr("d", "e")
 
+				return metrics
+			}(),
+		},
+		{
+			name: "native histogram with exemplars",
+			request: &writev2.Request{
+				Symbols: []string{
+					"",
+					"__name__", "test_hist", // 1, 2
+					"job", "service-x", // 3, 4
+					"instance", "107cn001", // 5, 6
+					"trace_id", "0102030405060708090a0b0c0d0e0f10", // 7, 8
+					"span_id", "0102030405060708", // 9, 10
+					"user", "alice", // 11, 12
+				},
+				Timeseries: []writev2.TimeSeries{
+					{
+						Metadata:         writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_HISTOGRAM},
+						LabelsRefs:       []uint32{1, 2, 3, 4, 5, 6},
+						CreatedTimestamp: 1,
+						Histograms: []writev2.Histogram{
+							{
+								Count:          &writev2.Histogram_CountInt{CountInt: 5},
+								Sum:            10,
+								Schema:         0,
+								ZeroThreshold:  0.001,
+								ZeroCount:      &writev2.Histogram_ZeroCountInt{ZeroCountInt: 1},
+								PositiveSpans:  []writev2.BucketSpan{{Offset: 1, Length: 2}, {Offset: 1, Length: 1}},
+								PositiveDeltas: []int64{1, 1, -1},
+								Timestamp:      2,
+							},
+						},
+						Exemplars: []writev2.Exemplar{
+							{LabelsRefs: []uint32{7, 8, 9, 10, 11, 12}, Value: 2, Timestamp: 2},
+						},
+					},
+				},
+			},
+			expectedMetrics: func() pmetric.Metrics {
+				metrics := pmetric.NewMetrics()
+				rm := metrics.ResourceMetrics().AppendEmpty()
+				attrs := rm.Resource().Attributes()
+				attrs.PutStr("service.name", "service-x")
+				attrs.PutStr("service.instance.id", "107cn001")
+				sm := rm.ScopeMetrics().AppendEmpty()
+				sm.Scope().SetName("OpenTelemetry Collector")
+				sm.Scope().SetVersion("latest")
+
+				m := sm.Metrics().AppendEmpty()
+				m.SetName("test_hist")
+				hist := m.SetEmptyExponentialHistogram()
+				hist.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+				dp := hist.DataPoints().AppendEmpty()
+				dp.SetStartTimestamp(pcommon.Timestamp(1 * int64(time.Millisecond)))
+				dp.SetTimestamp(pcommon.Timestamp(2 * int64(time.Millisecond)))
+				dp.SetCount(5)
+				dp.SetSum(10)
+				dp.SetScale(0)
+				dp.SetZeroThreshold(0.001)
+				dp.SetZeroCount(1)
+				dp.Positive().SetOffset(0)
+				dp.Positive().BucketCounts().FromRaw([]uint64{1, 2, 0, 1})
+
+				exemplar := dp.Exemplars().AppendEmpty()
+				exemplar.SetTimestamp(pcommon.Timestamp(2 * int64(time.Millisecond)))
+				exemplar.SetDoubleValue(2)
+				exemplar.SetTraceID(pcommon.TraceID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+				exemplar.SetSpanID(pcommon.SpanID{1, 2, 3, 4, 5, 6, 7, 8})
+				exemplar.FilteredAttributes().PutStr("user", "alice")
+
+				return metrics
+			}(),
+		},
+		{
+			name: "native histogram with custom buckets",
+			request: &writev2.Request{
+				Symbols: []string{"", "__name__", "test_hist"},
+				Timeseries: []writev2.TimeSeries{
+					{
+						Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_HISTOGRAM},
+						LabelsRefs: []uint32{1, 2},
+						Histograms: []writev2.Histogram{{Schema: -53, CustomValues: []float64{1, 2}}},
+					},
+				},
+			},
+			expectError: "unsupported native histogram schema -53",
+		},
+		{
+			name: "exemplar label ref bigger than symbols length",
+			request: &writev2.Request{
+				Symbols: []string{"", "__name__", "test_counter"},
+				Timeseries: []writev2.TimeSeries{
+					{
+						Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_COUNTER},
+						LabelsRefs: []uint32{1, 2},
+						Samples:    []writev2.Sample{{Value: 1, Timestamp: 1}},
+						Exemplars:  []writev2.Exemplar{{LabelsRefs: []uint32{1, 3}, Value: 1, Timestamp: 1}},
+					},
+				},
+			},
+			expectError: "exemplar label ref 3 is out of bounds of symbolsTable",
+		},
+		{
+			name: "classic histogram",
+			request: &writev2.Request{
+				Symbols: []string{
+					"",
+					"__name__", "test_hist_bucket", // 1, 2
+					"job", "service-x", // 3, 4
+					"le", "1", // 5, 6
+					"+Inf",            // 7
+					"10",              // 8
+					"test_hist_sum",   // 9
+					"test_hist_count", // 10
+					"method", "GET",   // 11, 12
+					"trace_id", "0102030405060708090a0b0c0d0e0f10", // 13, 14
+				},
+				// The series are not sent in the order of the buckets.
+				Timeseries: []writev2.TimeSeries{
+					{
+						Metadata:         writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_HISTOGRAM},
+						LabelsRefs:       []uint32{1, 2, 3, 4, 11, 12, 5, 7},
+						CreatedTimestamp: 1,
+						Samples:          []writev2.Sample{{Value: 6, Timestamp: 2}},
+					},
+					{
+						Metadata:         writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_HISTOGRAM},
+						LabelsRefs:       []uint32{1, 9, 3, 4, 11, 12},
+						CreatedTimestamp: 1,
+						Samples:          []writev2.Sample{{Value: 25, Timestamp: 2}},
+					},
+					{
+						Metadata:         writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_HISTOGRAM},
+						LabelsRefs:       []uint32{1, 2, 3, 4, 11, 12, 5, 8},
+						CreatedTimestamp: 1,
+						Samples:          []writev2.Sample{{Value: 3, Timestamp: 2}},
+						Exemplars:        []writev2.Exemplar{{LabelsRefs: []uint32{13, 14}, Value: 5, Timestamp: 2}},
+					},
+					{
+						Metadata:         writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_HISTOGRAM},
+						LabelsRefs:       []uint32{1, 10, 3, 4, 11, 12},
+						CreatedTimestamp: 1,
+						Samples:          []writev2.Sample{{Value: 6, Timestamp: 2}},
+					},
+					{
+						Metadata:         writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_HISTOGRAM},
+						LabelsRefs:       []uint32{1, 2, 3, 4, 11, 12, 5, 6},
+						CreatedTimestamp: 1,
+						Samples:          []writev2.Sample{{Value: 1, Timestamp: 2}},
+					},
+				},
+			},
+			expectedMetrics: func() pmetric.Metrics {
+				metrics := pmetric.NewMetrics()
+				rm := metrics.ResourceMetrics().AppendEmpty()
+				rm.Resource().Attributes().PutStr("service.name", "service-x")
+				sm := rm.ScopeMetrics().AppendEmpty()
+				sm.Scope().SetName("OpenTelemetry Collector")
+				sm.Scope().SetVersion("latest")
+
+				m := sm.Metrics().AppendEmpty()
+				m.SetName("test_hist")
+				hist := m.SetEmptyHistogram()
+				hist.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+				dp := hist.DataPoints().AppendEmpty()
+				dp.SetStartTimestamp(pcommon.Timestamp(1 * int64(time.Millisecond)))
+				dp.SetTimestamp(pcommon.Timestamp(2 * int64(time.Millisecond)))
+				dp.Attributes().PutStr("method", "GET")
+				dp.SetCount(6)
+				dp.SetSum(25)
+				dp.ExplicitBounds().FromRaw([]float64{1, 10})
+				dp.BucketCounts().FromRaw([]uint64{1, 2, 3})
+
+				exemplar := dp.Exemplars().AppendEmpty()
+				exemplar.SetTimestamp(pcommon.Timestamp(2 * int64(time.Millisecond)))
+				exemplar.SetDoubleValue(5)
+				exemplar.SetTraceID(pcommon.TraceID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+
+				return metrics
+			}(),
+		},
+		{
+			name: "classic histogram with non cumulative buckets",
+			request: &writev2.Request{
+				Symbols: []string{"", "__name__", "test_hist_bucket", "le", "1", "10"},
+				Timeseries: []writev2.TimeSeries{
+					{
+						Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_HISTOGRAM},
+						LabelsRefs: []uint32{1, 2, 3, 4},
+						Samples:    []writev2.Sample{{Value: 3, Timestamp: 2}},
+					},
+					{
+						Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_HISTOGRAM},
+						LabelsRefs: []uint32{1, 2, 3, 5},
+						Samples:    []writev2.Sample{{Value: 1, Timestamp: 2}},
+					},
+				},
+			},
+			expectError: `bucket counts of histogram "test_hist" are not cumulative`,
+		},
+		{
+			name: "classic histogram with invalid le label",
+			request: &writev2.Request{
+				Symbols: []string{"", "__name__", "test_hist_bucket", "le", "one"},
+				Timeseries: []writev2.TimeSeries{
+					{
+						Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_HISTOGRAM},
+						LabelsRefs: []uint32{1, 2, 3, 4},
+						Samples:    []writev2.Sample{{Value: 3, Timestamp: 2}},
+					},
+				},
+			},
+			expectError: `invalid le label "one" in series "test_hist_bucket"`,
+		},
+		{
+			name: "summary",
+			request: &writev2.Request{
+				Symbols: []string{
+					"",
+					"__name__", "test_summary", // 1, 2
+					"job", "service-x", // 3, 4
+					"quantile", "0.99", // 5, 6
+					"0.5",                // 7
+					"test_summary_sum",   // 8
+					"test_summary_count", // 9
+				},
+				Timeseries: []writev2.TimeSeries{
+					{
+						Metadata:         writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_SUMMARY},
+						LabelsRefs:       []uint32{1, 2, 3, 4, 5, 6},
+						CreatedTimestamp: 1,
+						Samples:          []writev2.Sample{{Value: 0.9, Timestamp: 2}},
+					},
+					{
+						Metadata:         writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_SUMMARY},
+						LabelsRefs:       []uint32{1, 8, 3, 4},
+						CreatedTimestamp: 1,
+						Samples:          []writev2.Sample{{Value: 5, Timestamp: 2}},
+					},
+					{
+						Metadata:         writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_SUMMARY},
+						LabelsRefs:       []uint32{1, 2, 3, 4, 5, 7},
+						CreatedTimestamp: 1,
+						Samples:          []writev2.Sample{{Value: 0.4, Timestamp: 2}},
+					},
+					{
+						Metadata:         writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_SUMMARY},
+						LabelsRefs:       []uint32{1, 9, 3, 4},
+						CreatedTimestamp: 1,
+						Samples:          []writev2.Sample{{Value: 10, Timestamp: 2}},
+					},
+				},
+			},
+			expectedMetrics: func() pmetric.Metrics {
+				metrics := pmetric.NewMetrics()
+				rm := metrics.ResourceMetrics().AppendEmpty()
+				rm.Resource().Attributes().PutStr("service.name", "service-x")
+				sm := rm.ScopeMetrics().AppendEmpty()
+				sm.Scope().SetName("OpenTelemetry Collector")
+				sm.Scope().SetVersion("latest")
+
+				m := sm.Metrics().AppendEmpty()
+				m.SetName("test_summary")
+				dp := m.SetEmptySummary().DataPoints().AppendEmpty()
+				dp.SetStartTimestamp(pcommon.Timestamp(1 * int64(time.Millisecond)))
+				dp.SetTimestamp(pcommon.Timestamp(2 * int64(time.Millisecond)))
+				dp.SetCount(10)
+				dp.SetSum(5)
+				// The quantiles are sorted.
+				qv := dp.QuantileValues().AppendEmpty()
+				qv.SetQuantile(0.5)
+				qv.SetValue(0.4)
+				qv = dp.QuantileValues().AppendEmpty()
+				qv.SetQuantile(0.99)
+				qv.SetValue(0.9)
+
 				return metrics
 			}(),
 		},
@@ -510,6 +790,34 @@ func (m *MockConsumer) ConsumeMetrics(_ context.Context, md pmetric.Metrics) err
 	return nil
 }
 
+// TestTranslateV2RoundTrip checks that metrics exported with the Prometheus remote write 2.0 translator
+// are received unchanged.
+func TestTranslateV2RoundTrip(t *testing.T) {
+	raw, err := os.ReadFile(filepath.Join("testdata", "roundtrip.json"))
+	require.NoError(t, err)
+	expected, err := (&pmetric.JSONUnmarshaler{}).UnmarshalMetrics(raw)
+	require.NoError(t, err)
+
+	tsMap, symbolsTable, err := prometheusremotewrite.FromMetricsV2(expected, prometheusremotewrite.Settings{})
+	require.NoError(t, err)
+	request := &writev2.Request{Symbols: symbolsTable.Symbols()}
+	for _, ts := range tsMap {
+		request.Timeseries = append(request.Timeseries, *ts)
+	}
+
+	// Go through the wire format, as the receiver would.
+	body, err := proto.Marshal(request)
+	require.NoError(t, err)
+	var decoded writev2.Request
+	require.NoError(t, proto.Unmarshal(body, &decoded))
+
+	prwReceiver := setupMetricsReceiver(t)
+	metrics, stats, err := prwReceiver.translateV2(context.Background(), &decoded)
+	require.NoError(t, err)
+	assert.Equal(t, remote.WriteResponseStats{}, stats)
+	assert.NoError(t, pmetrictest.CompareMetrics(expected, metrics, pmetrictest.IgnoreMetricsOrder()))
+}
+
 func TestTargetInfoWithMultipleRequests(t *testing.T) {
 	tests := []struct {
 		name     string